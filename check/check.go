@@ -3,6 +3,10 @@ package check
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strconv"
 	"time"
 
 	"github.com/emancu/pgdoctor/db"
@@ -16,8 +20,13 @@ type DBTX = db.DBTX
 type Severity int
 
 const (
-	SeveritySkip Severity = iota - 1 // Check could not run (timeout, permission error, etc.)
-	SeverityOK   Severity = iota
+	// SeverityNotApplicable marks a finding, or a whole check, that does not
+	// apply to this server: the feature it inspects is missing (a setting
+	// added in a later PostgreSQL version, an extension that is not
+	// installed). It is neither a pass nor a failure.
+	SeverityNotApplicable Severity = iota - 2
+	SeveritySkip                   // Check could not run (timeout, permission error, etc.)
+	SeverityOK
 	SeverityWarn
 	SeverityFail
 )
@@ -32,6 +41,8 @@ func (s Severity) String() string {
 		return "fail"
 	case SeveritySkip:
 		return "skip"
+	case SeverityNotApplicable:
+		return "n/a"
 	default:
 		return "unknown"
 	}
@@ -47,6 +58,9 @@ const (
 	CategoryPerformance Category = "performance"
 )
 
+// Checker runs one check. Check must build a new Report on every call and
+// must not keep a reference to it after returning: the runner sets its
+// Duration and hands it to callers, who may read it from other goroutines.
 type Checker interface {
 	Metadata() Metadata
 	Check(context.Context) (*Report, error)
@@ -56,6 +70,39 @@ type Checker interface {
 // Each check defines its own supported keys.
 type Config map[string]map[string]string
 
+// Merge returns a new Config with override's keys layered over c's.
+// Keys are merged per check, so an override only replaces the keys it sets.
+// Neither input is modified.
+func (c Config) Merge(override Config) Config {
+	merged := make(Config, len(c)+len(override))
+	for _, layer := range []Config{c, override} {
+		for checkID, keys := range layer {
+			if merged[checkID] == nil {
+				merged[checkID] = make(map[string]string, len(keys))
+			}
+			for key, value := range keys {
+				merged[checkID][key] = value
+			}
+		}
+	}
+	return merged
+}
+
+// EnabledKey is the config key that turns a check off ("enabled": "false").
+// Unlike other keys it applies to every check and is read by the runner.
+const EnabledKey = "enabled"
+
+// Enabled reports whether checkID should run. Checks are enabled unless their
+// EnabledKey parses as false; unparseable values leave the check enabled.
+func (c Config) Enabled(checkID string) bool {
+	value, ok := c[checkID][EnabledKey]
+	if !ok {
+		return true
+	}
+	enabled, err := strconv.ParseBool(value)
+	return err != nil || enabled
+}
+
 // Package holds references to a check's exported functions.
 // This allows the generator to create a simple list that consumers
 // can use to either get metadata or instantiate checkers.
@@ -71,15 +118,35 @@ type Metadata struct {
 	Description string
 	Readme      string
 	SQL         string // SQL query used by this check
+	// ClusterWide marks checks whose results are the same from every database
+	// of a cluster (server settings, replication, connections). Multi-database
+	// runs execute them once; see pgdoctor.SplitClusterWide.
+	ClusterWide bool
+	// Heavy marks checks that read whole relations rather than catalogs and
+	// statistics views, like pgstattuple scans. When the runner is given a
+	// limit (pgdoctor.Options.HeavyLimit), concurrent runs start only so many
+	// of them at once.
+	Heavy bool
 }
 
 // Report holds check-level metadata and all subcheck findings for a single check.
 // The check's overall severity is the maximum severity across all findings.
+//
+// A Report is not safe for concurrent mutation; it is owned by the Check call
+// that builds it. Findings must not share slices (Headers, Cells, ...) with
+// other reports, so never put package-level slices in a Table.
 type Report struct {
 	Metadata // Embedded, promotes CheckID, Name, Category, Description, SQL
 	Severity Severity
 	Duration time.Duration
 	Results  []Finding
+	// Database is the database the check ran in when the runner was given one
+	// (pgdoctor.Options.Database), as in multi-database runs. Empty otherwise.
+	Database string
+	// Errored is set by the runner when the check returned an error instead
+	// of findings. Such a report is SeveritySkip, like a check that could not
+	// run, and this tells the two apart; see pgdoctor.Errored.
+	Errored bool
 }
 
 func NewReport(metadata Metadata) *Report {
@@ -90,7 +157,13 @@ func NewReport(metadata Metadata) *Report {
 	}
 }
 
+// AddFinding appends a finding and raises the report's severity to match. A
+// report whose findings are all SeverityNotApplicable is not applicable
+// itself.
 func (r *Report) AddFinding(res Finding) {
+	if len(r.Results) == 0 && res.Severity == SeverityNotApplicable {
+		r.Severity = SeverityNotApplicable
+	}
 	r.Results = append(r.Results, res)
 
 	if res.Severity > r.Severity {
@@ -109,22 +182,139 @@ type Finding struct {
 	Name     string
 	Severity Severity
 	Details  string
+	// Reason is a one-line rationale of the rule that set Severity, such as
+	// "statement_timeout 0ms < min 500ms → FAIL", shown with --reasons. It
+	// is optional, and only meaningful for WARN and FAIL findings.
+	Reason string
 	// Table contains optional structured tabular data.
 	// If set, the CLI will render this as a formatted table.
 	Table *Table
 	// Debug contains debug information like SQL queries, timing info, etc.
 	// Only shown when --debug flag is used.
 	Debug string
+	// DetailsSensitivity marks Details that embed object names or SQL text
+	// not also shown in Table. Report.Redact replaces such Details whole.
+	DetailsSensitivity Sensitivity
+	// Priority ranks findings of the same severity across checks; higher is
+	// more urgent. Most findings leave it at zero. See CompareFindings.
+	Priority int
+	// Tags label the nature of a finding across checks and categories, such
+	// as TagWraparound, so output can be filtered and routed by it. Most
+	// findings have none.
+	Tags []string
+	// SeeAlso lists the findings of other checks that share a tag with this
+	// one and also report a problem, as "check-id/finding-id", so output can
+	// point to them instead of raising the same cause as separate alerts. The
+	// runner never sets it; see pgdoctor.LinkRelated.
+	SeeAlso []string
+	// FirstSeen is when the finding was first reported, carried over from a
+	// saved run the CLI compares against (--baseline). It is zero when the
+	// run had no baseline.
+	FirstSeen time.Time
+}
+
+// TagWraparound tags findings about transaction ID wraparound, which the
+// freeze-age and partition-freeze-skew checks both report on.
+const TagWraparound = "wraparound"
+
+// TagXminHorizon tags findings about what holds back the xmin horizon, such
+// as a long transaction or an abandoned slot, which the xmin-horizon,
+// connection-health, and inactive-slots checks each report from their side.
+const TagXminHorizon = "xmin-horizon"
+
+// TagTempFiles tags findings about temp files filling disk, which the
+// temp-usage check rates and temp-tablespace places.
+const TagTempFiles = "temp-files"
+
+// HasTag reports whether the finding is tagged with tag.
+func (f Finding) HasTag(tag string) bool {
+	return slices.Contains(f.Tags, tag)
+}
+
+// PriorityUrgent is the Priority of findings that lead to an outage if left
+// alone, such as transaction ID wraparound or a full disk.
+const PriorityUrgent = 10
+
+// CompareFindings orders findings by how urgently they need attention: higher
+// severity first (FAIL, WARN, OK, SKIP, then N/A), then higher Priority. It returns
+// a negative number when a comes first, a positive one when b does, and zero
+// when they rank equally, as slices.SortStableFunc expects.
+func CompareFindings(a, b Finding) int {
+	if a.Severity != b.Severity {
+		return int(b.Severity) - int(a.Severity)
+	}
+	return b.Priority - a.Priority
 }
 
 type Table struct {
 	Headers []string
-	Rows    []TableRow
+	// Align optionally sets each column's alignment, by header index. Columns
+	// without an entry are left-aligned. Set AlignRight on numeric columns
+	// (sizes, counts, percentages) so their digits line up.
+	Align []ColumnAlign
+	// Sensitive optionally marks columns holding SQL text or object names,
+	// by header index, so Report.Redact can strip them.
+	Sensitive []Sensitivity
+	// Key optionally names the columns, by header index, that identify what a
+	// row is about, such as its schema, table, and index, as opposed to sizes
+	// and counts that change from run to run. Without it the first column
+	// does. See RowFingerprint.
+	Key  []int
+	Rows []TableRow
+	// KeepOrder makes text output print Rows in the order the check added
+	// them. Otherwise the worst rows are printed first, keeping the check's
+	// order among rows of the same severity. Set it when the order carries
+	// meaning, such as settings listed from the limit each one draws from.
+	KeepOrder bool
+}
+
+// ColumnAlign is a presentation hint for a table column.
+type ColumnAlign int
+
+const (
+	AlignLeft ColumnAlign = iota
+	AlignRight
+)
+
+// ColumnAlign returns the alignment of column i.
+func (t *Table) ColumnAlign(i int) ColumnAlign {
+	if i < len(t.Align) {
+		return t.Align[i]
+	}
+	return AlignLeft
+}
+
+// RowFingerprint returns a stable identity of row from its Key cells, so the
+// same object matches across runs (--baseline) while its other cells change.
+func (t *Table) RowFingerprint(row TableRow) string {
+	key := t.Key
+	if len(key) == 0 {
+		key = []int{0}
+	}
+	h := sha256.New()
+	for _, i := range key {
+		if i < len(row.Cells) {
+			h.Write([]byte(row.Cells[i]))
+		}
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// ColumnSensitivity returns the sensitivity of column i.
+func (t *Table) ColumnSensitivity(i int) Sensitivity {
+	if i < len(t.Sensitive) {
+		return t.Sensitive[i]
+	}
+	return NotSensitive
 }
 
 type TableRow struct {
 	Cells    []string
 	Severity Severity
+	// FirstSeen is when the object the row names was first listed, carried
+	// over from the --baseline run by RowFingerprint, like Finding.FirstSeen.
+	FirstSeen time.Time
 }
 
 // InstanceMetadata contains database instance specifications and configuration.
@@ -0,0 +1,118 @@
+package check_test
+
+import (
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigMerge(t *testing.T) {
+	t.Parallel()
+
+	base := check.Config{
+		"session-settings": {"timeout_warn": "5000", "roles": "app"},
+		"table-bloat":      {"threshold": "20"},
+	}
+	override := check.Config{
+		"session-settings": {"timeout_warn": "2000"},
+		"pk-types":         {"strict": "true"},
+	}
+
+	merged := base.Merge(override)
+
+	assert.Equal(t, check.Config{
+		"session-settings": {"timeout_warn": "2000", "roles": "app"},
+		"table-bloat":      {"threshold": "20"},
+		"pk-types":         {"strict": "true"},
+	}, merged)
+
+	// Inputs are left untouched.
+	assert.Equal(t, "5000", base["session-settings"]["timeout_warn"])
+	assert.NotContains(t, base, "pk-types")
+}
+
+func TestConfigEnabled(t *testing.T) {
+	t.Parallel()
+
+	cfg := check.Config{
+		"table-bloat":      {"enabled": "false"},
+		"index-bloat":      {"enabled": "0"},
+		"pk-types":         {"enabled": "true"},
+		"toast-storage":    {"enabled": "maybe"},
+		"session-settings": {"timeout_warn": "2000"},
+	}
+
+	assert.False(t, cfg.Enabled("table-bloat"))
+	assert.False(t, cfg.Enabled("index-bloat"))
+	assert.True(t, cfg.Enabled("pk-types"))
+	assert.True(t, cfg.Enabled("toast-storage"), "unparseable values leave the check enabled")
+	assert.True(t, cfg.Enabled("session-settings"), "checks without the key are enabled")
+	assert.True(t, cfg.Enabled("freeze-age"))
+
+	var empty check.Config
+	assert.True(t, empty.Enabled("table-bloat"))
+}
+
+func TestConfigMerge_Nil(t *testing.T) {
+	t.Parallel()
+
+	var base check.Config
+	merged := base.Merge(nil)
+	assert.NotNil(t, merged)
+	assert.Empty(t, merged)
+}
+
+func TestCompareFindings(t *testing.T) {
+	t.Parallel()
+
+	fail := check.Finding{Severity: check.SeverityFail}
+	urgentFail := check.Finding{Severity: check.SeverityFail, Priority: check.PriorityUrgent}
+	urgentWarn := check.Finding{Severity: check.SeverityWarn, Priority: check.PriorityUrgent}
+	ok := check.Finding{Severity: check.SeverityOK}
+	skip := check.Finding{Severity: check.SeveritySkip}
+	notApplicable := check.Finding{Severity: check.SeverityNotApplicable}
+
+	assert.Negative(t, check.CompareFindings(fail, urgentWarn), "severity outranks priority")
+	assert.Negative(t, check.CompareFindings(urgentFail, fail), "priority breaks severity ties")
+	assert.Positive(t, check.CompareFindings(ok, urgentWarn))
+	assert.Negative(t, check.CompareFindings(ok, skip), "skipped findings rank after passing ones")
+	assert.Negative(t, check.CompareFindings(skip, notApplicable), "not applicable findings rank last")
+	assert.Zero(t, check.CompareFindings(fail, fail))
+}
+
+func TestReportAddFinding_NotApplicable(t *testing.T) {
+	t.Parallel()
+
+	report := check.NewReport(check.Metadata{CheckID: "demo"})
+	report.AddFinding(check.Finding{ID: "feature", Severity: check.SeverityNotApplicable})
+	assert.Equal(t, check.SeverityNotApplicable, report.Severity, "a report of only N/A findings is N/A")
+
+	report.AddFinding(check.Finding{ID: "other", Severity: check.SeverityOK})
+	assert.Equal(t, check.SeverityOK, report.Severity)
+
+	report = check.NewReport(check.Metadata{CheckID: "demo"})
+	report.AddFinding(check.Finding{ID: "other", Severity: check.SeverityWarn})
+	report.AddFinding(check.Finding{ID: "feature", Severity: check.SeverityNotApplicable})
+	assert.Equal(t, check.SeverityWarn, report.Severity, "N/A findings never lower a report")
+	assert.Equal(t, "n/a", check.SeverityNotApplicable.String())
+}
+
+func TestTableRowFingerprint(t *testing.T) {
+	t.Parallel()
+
+	row := func(cells ...string) check.TableRow { return check.TableRow{Cells: cells} }
+
+	byName := &check.Table{Headers: []string{"Table", "Bloat"}}
+	assert.Equal(t, byName.RowFingerprint(row("public.events", "61%")), byName.RowFingerprint(row("public.events", "72%")),
+		"without Key the first column identifies the row")
+	assert.NotEqual(t, byName.RowFingerprint(row("public.events", "61%")), byName.RowFingerprint(row("public.users", "61%")))
+	assert.Len(t, byName.RowFingerprint(row("public.events")), 16)
+
+	byIndex := &check.Table{Headers: []string{"Table", "Size", "Index"}, Key: []int{0, 2}}
+	assert.Equal(t, byIndex.RowFingerprint(row("public.events", "1GiB", "events_pkey")), byIndex.RowFingerprint(row("public.events", "2GiB", "events_pkey")))
+	assert.NotEqual(t, byIndex.RowFingerprint(row("public.events", "1GiB", "events_pkey")), byIndex.RowFingerprint(row("public.events", "1GiB", "events_at_idx")),
+		"every Key column is part of the identity")
+	assert.NotEqual(t, byIndex.RowFingerprint(row("ab", "", "c")), byIndex.RowFingerprint(row("a", "", "bc")), "cells are not simply concatenated")
+	assert.NotPanics(t, func() { byIndex.RowFingerprint(row("public.events")) }, "short rows have empty key cells")
+}
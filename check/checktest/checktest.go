@@ -0,0 +1,155 @@
+// Package checktest provides fake query results and report assertions for
+// testing checks.
+//
+// A check's fake implements its query interface with one line per method,
+// returning a canned Result:
+//
+//	type fakeQueries struct {
+//		indexes checktest.Result[[]db.BrokenIndexesRow]
+//	}
+//
+//	func (f fakeQueries) BrokenIndexes(context.Context) ([]db.BrokenIndexesRow, error) {
+//		return f.indexes.Get()
+//	}
+//
+// Tests then run the checker and assert on the report:
+//
+//	report := checktest.Run(t, invalidindexes.New(fakeQueries{indexes: checktest.Returns(rows)}))
+//	finding := checktest.OnlyFinding(t, report)
+package checktest
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/emancu/pgdoctor/check"
+)
+
+// Result is the canned outcome of one query method: the rows it returns, or
+// the error it fails with.
+type Result[T any] struct {
+	Value T
+	Err   error
+}
+
+// Returns is a Result that succeeds with v.
+func Returns[T any](v T) Result[T] {
+	return Result[T]{Value: v}
+}
+
+// Fails is a Result that fails with err.
+func Fails[T any](err error) Result[T] {
+	return Result[T]{Err: err}
+}
+
+// Get returns the canned rows, or the zero value and the error.
+func (r Result[T]) Get() (T, error) {
+	if r.Err != nil {
+		var zero T
+		return zero, r.Err
+	}
+	return r.Value, nil
+}
+
+// Run runs the checker and fails the test if Check returns an error.
+func Run(t testing.TB, c check.Checker) *check.Report {
+	t.Helper()
+	report, err := c.Check(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	return report
+}
+
+// RequireError runs the checker and fails the test unless Check returns an
+// error naming the check, as checks wrap their query errors.
+func RequireError(t testing.TB, c check.Checker) error {
+	t.Helper()
+	_, err := c.Check(context.Background())
+	require.ErrorContains(t, err, c.Metadata().CheckID)
+	return err
+}
+
+// OnlyFinding returns the report's single finding, failing the test if it
+// has none or several.
+func OnlyFinding(t testing.TB, report *check.Report) check.Finding {
+	t.Helper()
+	require.Len(t, report.Results, 1, "%s should emit exactly one finding, got %v", report.CheckID, FindingIDs(report))
+	return report.Results[0]
+}
+
+// Finding returns the finding with the given ID, failing the test if the
+// report has none.
+func Finding(t testing.TB, report *check.Report, id string) check.Finding {
+	t.Helper()
+	for _, f := range report.Results {
+		if f.ID == id {
+			return f
+		}
+	}
+	require.FailNow(t, "finding not found", "%s has no finding %q, got %v", report.CheckID, id, FindingIDs(report))
+	return check.Finding{}
+}
+
+// FindingIDs lists the IDs of the report's findings in order.
+func FindingIDs(report *check.Report) []string {
+	ids := make([]string, 0, len(report.Results))
+	for _, f := range report.Results {
+		ids = append(ids, f.ID)
+	}
+	return ids
+}
+
+// RequireSeverities fails the test unless the report has exactly the given
+// findings, by ID, with the given severities.
+func RequireSeverities(t testing.TB, report *check.Report, want map[string]check.Severity) {
+	t.Helper()
+	got := make(map[string]check.Severity, len(report.Results))
+	for _, f := range report.Results {
+		got[f.ID] = f.Severity
+	}
+	require.Equal(t, want, got, "%s finding severities", report.CheckID)
+}
+
+// Rows returns the cells of each row in the finding's table, or nil when it
+// has no table.
+func Rows(f check.Finding) [][]string {
+	if f.Table == nil {
+		return nil
+	}
+	rows := make([][]string, 0, len(f.Table.Rows))
+	for _, row := range f.Table.Rows {
+		rows = append(rows, row.Cells)
+	}
+	return rows
+}
+
+// RowWhere returns the first row of the finding's table whose cell under the
+// header column equals value, failing the test if there is none.
+func RowWhere(t testing.TB, f check.Finding, column, value string) check.TableRow {
+	t.Helper()
+	require.NotNil(t, f.Table, "finding %q has no table", f.ID)
+	col := slices.Index(f.Table.Headers, column)
+	require.GreaterOrEqual(t, col, 0, "finding %q has no column %q, got %v", f.ID, column, f.Table.Headers)
+	for _, row := range f.Table.Rows {
+		if col < len(row.Cells) && row.Cells[col] == value {
+			return row
+		}
+	}
+	require.FailNow(t, "row not found", "finding %q has no row with %s = %q", f.ID, column, value)
+	return check.TableRow{}
+}
+
+// RequireMetadata fails the test unless the metadata has the given ID and
+// category and every field shown to users is filled in.
+func RequireMetadata(t testing.TB, m check.Metadata, id string, category check.Category) {
+	t.Helper()
+	require.Equal(t, id, m.CheckID)
+	require.Equal(t, category, m.Category)
+	require.NotEmpty(t, m.Name)
+	require.NotEmpty(t, m.Description)
+	require.NotEmpty(t, m.Readme)
+	require.NotEmpty(t, m.SQL)
+}
@@ -0,0 +1,111 @@
+package checktest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+)
+
+type fakeQueries struct {
+	sizes checktest.Result[[]int64]
+}
+
+func (f fakeQueries) Sizes(context.Context) ([]int64, error) {
+	return f.sizes.Get()
+}
+
+// sizeChecker is a minimal check: one finding per size, WARN above 10.
+type sizeChecker struct {
+	queries fakeQueries
+}
+
+func (c sizeChecker) Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryConfigs,
+		CheckID:     "sizes",
+		Name:        "Sizes",
+		Description: "Flags large sizes",
+		Readme:      "# Sizes",
+		SQL:         "SELECT 1",
+	}
+}
+
+func (c sizeChecker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(c.Metadata())
+	sizes, err := c.queries.Sizes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+	for i, size := range sizes {
+		severity := check.SeverityOK
+		if size > 10 {
+			severity = check.SeverityWarn
+		}
+		report.AddFinding(check.Finding{
+			ID:       fmt.Sprintf("size-%d", i),
+			Severity: severity,
+			Table: &check.Table{
+				Headers: []string{"Index", "Size"},
+				Rows:    []check.TableRow{{Cells: []string{fmt.Sprint(i), fmt.Sprint(size)}, Severity: severity}},
+			},
+		})
+	}
+	return report, nil
+}
+
+func TestResult(t *testing.T) {
+	t.Parallel()
+
+	rows, err := checktest.Returns([]int64{1, 2}).Get()
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, rows)
+
+	boom := errors.New("boom")
+	rows, err = checktest.Fails[[]int64](boom).Get()
+	require.ErrorIs(t, err, boom)
+	assert.Nil(t, rows)
+}
+
+func TestReportAssertions(t *testing.T) {
+	t.Parallel()
+
+	report := checktest.Run(t, sizeChecker{fakeQueries{sizes: checktest.Returns([]int64{5, 20})}})
+
+	assert.Equal(t, []string{"size-0", "size-1"}, checktest.FindingIDs(report))
+	checktest.RequireSeverities(t, report, map[string]check.Severity{
+		"size-0": check.SeverityOK,
+		"size-1": check.SeverityWarn,
+	})
+
+	finding := checktest.Finding(t, report, "size-1")
+	assert.Equal(t, [][]string{{"1", "20"}}, checktest.Rows(finding))
+	assert.Equal(t, check.SeverityWarn, checktest.RowWhere(t, finding, "Size", "20").Severity)
+	assert.Nil(t, checktest.Rows(check.Finding{}))
+}
+
+func TestOnlyFinding(t *testing.T) {
+	t.Parallel()
+
+	report := checktest.Run(t, sizeChecker{fakeQueries{sizes: checktest.Returns([]int64{5})}})
+	assert.Equal(t, "size-0", checktest.OnlyFinding(t, report).ID)
+}
+
+func TestRequireError(t *testing.T) {
+	t.Parallel()
+
+	err := checktest.RequireError(t, sizeChecker{fakeQueries{sizes: checktest.Fails[[]int64](errors.New("connection refused"))}})
+	assert.ErrorContains(t, err, "connection refused")
+}
+
+func TestRequireMetadata(t *testing.T) {
+	t.Parallel()
+
+	checktest.RequireMetadata(t, sizeChecker{}.Metadata(), "sizes", check.CategoryConfigs)
+}
@@ -5,6 +5,7 @@ import (
 	"math"
 	"strconv"
 	"strings"
+	"sync/atomic"
 
 	"github.com/jackc/pgx/v5/pgtype"
 )
@@ -16,9 +17,35 @@ const (
 	GiB = MiB * 1024
 )
 
-// FormatBytes formats a byte count as a human-readable string (e.g., "1.5GiB").
+// NumberFormat sets the separators FormatBytes and FormatNumber use. The zero
+// value is the default: "." decimals and no thousands grouping.
+type NumberFormat struct {
+	// Decimal is the decimal separator; empty means ".".
+	Decimal string
+	// Grouping separates thousands in the raw values FormatNumber prints
+	// without a suffix, such as negative counts; empty means no grouping.
+	Grouping string
+}
+
+var numberFormat atomic.Pointer[NumberFormat]
+
+// SetNumberFormat sets the NumberFormat of FormatBytes and FormatNumber for
+// the whole process, e.g. once at startup from a --locale flag. Checks format
+// their cells while they run, so it must be set before the run.
+func SetNumberFormat(f NumberFormat) {
+	numberFormat.Store(&f)
+}
+
+func currentNumberFormat() NumberFormat {
+	if f := numberFormat.Load(); f != nil {
+		return *f
+	}
+	return NumberFormat{}
+}
+
+// Bytes formats a byte count as a human-readable string (e.g., "1.5GiB").
 // Supports from bytes up to exbibytes (EiB) for large database objects.
-func FormatBytes(bytes int64) string {
+func (f NumberFormat) Bytes(bytes int64) string {
 	const unit = 1024
 	if bytes < unit {
 		return fmt.Sprintf("%dB", bytes)
@@ -28,21 +55,62 @@ func FormatBytes(bytes int64) string {
 		div *= unit
 		exp++
 	}
-	return fmt.Sprintf("%.1f%ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+	return fmt.Sprintf("%s%ciB", f.decimal(float64(bytes)/float64(div)), "KMGTPE"[exp])
 }
 
-// FormatNumber formats a large number as a human-readable string (e.g., "1.5M").
-func FormatNumber(n int64) string {
+// Number formats a large number as a human-readable string (e.g., "1.5M").
+func (f NumberFormat) Number(n int64) string {
 	if n >= 1_000_000_000 {
-		return fmt.Sprintf("%.1fB", float64(n)/1_000_000_000)
+		return f.decimal(float64(n)/1_000_000_000) + "B"
 	}
 	if n >= 1_000_000 {
-		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+		return f.decimal(float64(n)/1_000_000) + "M"
 	}
 	if n >= 1_000 {
-		return fmt.Sprintf("%.1fK", float64(n)/1_000)
+		return f.decimal(float64(n)/1_000) + "K"
+	}
+	return f.group(n)
+}
+
+// decimal formats v with one decimal place.
+func (f NumberFormat) decimal(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 1, 64)
+	if f.Decimal != "" {
+		s = strings.Replace(s, ".", f.Decimal, 1)
 	}
-	return fmt.Sprintf("%d", n)
+	return s
+}
+
+// group formats n with Grouping between each three digits.
+func (f NumberFormat) group(n int64) string {
+	s := strconv.FormatInt(n, 10)
+	if f.Grouping == "" {
+		return s
+	}
+	sign := ""
+	if n < 0 {
+		sign, s = "-", s[1:]
+	}
+	var b strings.Builder
+	for i, d := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteString(f.Grouping)
+		}
+		b.WriteRune(d)
+	}
+	return sign + b.String()
+}
+
+// FormatBytes formats a byte count as a human-readable string (e.g., "1.5GiB")
+// in the process NumberFormat.
+func FormatBytes(bytes int64) string {
+	return currentNumberFormat().Bytes(bytes)
+}
+
+// FormatNumber formats a large number as a human-readable string (e.g., "1.5M")
+// in the process NumberFormat.
+func FormatNumber(n int64) string {
+	return currentNumberFormat().Number(n)
 }
 
 // FormatDurationMs formats milliseconds as a human-readable duration (e.g., "1.5h").
@@ -47,3 +47,40 @@ func TestParseDurationMs_Invalid(t *testing.T) {
 		require.Error(t, err, "expected error for %q", value)
 	}
 }
+
+func TestNumberFormat(t *testing.T) {
+	t.Parallel()
+
+	var en check.NumberFormat
+	de := check.NumberFormat{Decimal: ",", Grouping: "."}
+
+	tests := []struct {
+		format check.NumberFormat
+		got    string
+		want   string
+	}{
+		{en, en.Bytes(512), "512B"},
+		{en, en.Bytes(1536), "1.5KiB"},
+		{en, en.Number(999), "999"},
+		{en, en.Number(1_500_000), "1.5M"},
+		{en, en.Number(-1_234_567), "-1234567"},
+		{de, de.Bytes(1536), "1,5KiB"},
+		{de, de.Bytes(3 * check.GiB), "3,0GiB"},
+		{de, de.Number(2_500_000_000), "2,5B"},
+		{de, de.Number(999), "999"},
+		{de, de.Number(-1_234_567), "-1.234.567"},
+		{de, de.Number(-100), "-100"},
+		{check.NumberFormat{Grouping: " "}, check.NumberFormat{Grouping: " "}.Number(-12_345), "-12 345"},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, tt.got, "%+v", tt.format)
+	}
+}
+
+func TestFormatNumber_DefaultFormat(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "1.0MiB", check.FormatBytes(check.MiB))
+	require.Equal(t, "200.0K", check.FormatNumber(200_000))
+	require.Equal(t, "-5000", check.FormatNumber(-5000))
+}
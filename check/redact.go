@@ -0,0 +1,154 @@
+package check
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Sensitivity marks what a table column or a finding's Details can reveal,
+// so a redacted run knows what to strip.
+type Sensitivity int
+
+const (
+	NotSensitive Sensitivity = iota
+	// SensitiveQuery is SQL text, which can contain literal values.
+	SensitiveQuery
+	// SensitiveIdentifier is a schema, table, index, role, or other object name.
+	SensitiveIdentifier
+)
+
+// Redaction is how much of a report to strip before it leaves pgdoctor.
+type Redaction int
+
+const (
+	RedactNone Redaction = iota
+	// RedactQueries replaces SQL text.
+	RedactQueries
+	// RedactIdentifiers replaces SQL text and object names.
+	RedactIdentifiers
+)
+
+// covers reports whether data of sensitivity s is stripped at this level.
+func (r Redaction) covers(s Sensitivity) bool {
+	switch s {
+	case SensitiveQuery:
+		return r >= RedactQueries
+	case SensitiveIdentifier:
+		return r >= RedactIdentifiers
+	default:
+		return false
+	}
+}
+
+// redactedDetails replaces Details marked sensitive, since free text can't be
+// redacted piecemeal.
+const redactedDetails = "[redacted]"
+
+// Redact returns a copy of the report with sensitive data replaced.
+//
+// Cells in columns marked with Table.Sensitive become stable placeholders
+// ("<query:1a2b3c4d>", "<id:1a2b3c4d>") derived from a hash of the value, so
+// the same object still correlates across rows and runs. Those values are
+// also replaced wherever they appear in the finding's Details and Reason.
+// Details marked with Finding.DetailsSensitivity are replaced as a whole, and
+// Debug, which carries SQL, is always dropped. At RedactIdentifiers, Database
+// gets a placeholder too.
+//
+// Placeholders are pseudonyms, not encryption: a common name like
+// "public.users" can be recovered by hashing guesses.
+func (r *Report) Redact(level Redaction) *Report {
+	if level == RedactNone {
+		return r
+	}
+
+	redacted := *r
+	if level.covers(SensitiveIdentifier) && r.Database != "" {
+		redacted.Database = placeholder(SensitiveIdentifier, r.Database)
+	}
+	redacted.Results = make([]Finding, len(r.Results))
+	for i, finding := range r.Results {
+		redacted.Results[i] = finding.redact(level)
+	}
+	return &redacted
+}
+
+func (f Finding) redact(level Redaction) Finding {
+	f.Debug = ""
+
+	replacements := map[string]string{}
+	if f.Table != nil {
+		table := *f.Table
+		table.Rows = make([]TableRow, len(f.Table.Rows))
+		for i, row := range f.Table.Rows {
+			cells := make([]string, len(row.Cells))
+			for j, cell := range row.Cells {
+				cells[j] = cell
+				sensitivity := f.Table.ColumnSensitivity(j)
+				if !level.covers(sensitivity) || cell == "" || cell == "-" {
+					continue
+				}
+				cells[j] = placeholder(sensitivity, cell)
+				replacements[cell] = cells[j]
+			}
+			table.Rows[i] = TableRow{Cells: cells, Severity: row.Severity}
+		}
+		f.Table = &table
+	}
+
+	f.Reason = replaceValues(f.Reason, replacements)
+	if level.covers(f.DetailsSensitivity) {
+		f.Details = redactedDetails
+		return f
+	}
+
+	f.Details = replaceValues(f.Details, replacements)
+	return f
+}
+
+// replaceValues replaces whole-word occurrences of each key in s, in one pass
+// and longest keys first, so "public.orders_archive" isn't left half-redacted
+// by "public.orders" and a table named "a" doesn't rewrite every "a".
+func replaceValues(s string, replacements map[string]string) string {
+	if len(replacements) == 0 {
+		return s
+	}
+
+	values := make([]string, 0, len(replacements))
+	for value := range replacements {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+	for i, value := range values {
+		values[i] = regexp.QuoteMeta(value)
+	}
+	re := regexp.MustCompile(strings.Join(values, "|"))
+
+	var b strings.Builder
+	last := 0
+	for _, m := range re.FindAllStringIndex(s, -1) {
+		if (m[0] > 0 && isWordByte(s[m[0]-1])) || (m[1] < len(s) && isWordByte(s[m[1]])) {
+			continue
+		}
+		b.WriteString(s[last:m[0]])
+		b.WriteString(replacements[s[m[0]:m[1]]])
+		last = m[1]
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func placeholder(sensitivity Sensitivity, value string) string {
+	sum := sha256.Sum256([]byte(value))
+	prefix := "id"
+	if sensitivity == SensitiveQuery {
+		prefix = "query"
+	}
+	return "<" + prefix + ":" + hex.EncodeToString(sum[:4]) + ">"
+}
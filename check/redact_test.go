@@ -0,0 +1,110 @@
+package check_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sensitiveReport() *check.Report {
+	report := check.NewReport(check.Metadata{CheckID: "demo", Name: "Demo"})
+	report.AddFinding(check.Finding{
+		ID:       "stuck",
+		Name:     "Stuck",
+		Severity: check.SeverityWarn,
+		Details:  "public.orders_archive and public.orders are locked by app",
+		Reason:   "public.orders locked for 1h > max 10m → WARN",
+		Debug:    "SELECT * FROM public.orders WHERE email = 'a@example.com'",
+		Table: &check.Table{
+			Headers:   []string{"Table", "Query", "Size"},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.SensitiveQuery},
+			Rows: []check.TableRow{
+				{Cells: []string{"public.orders", "UPDATE orders SET email = 'a@example.com'", "1 GB"}, Severity: check.SeverityWarn},
+				{Cells: []string{"public.orders_archive", "-", "2 GB"}, Severity: check.SeverityWarn},
+				{Cells: []string{"app", "", "3 GB"}},
+			},
+		},
+	})
+	report.AddFinding(check.Finding{
+		ID:                 "disabled",
+		Name:               "Disabled",
+		Severity:           check.SeverityWarn,
+		Details:            "autovacuum disabled on: public.orders",
+		DetailsSensitivity: check.SensitiveIdentifier,
+	})
+	return report
+}
+
+func TestReport_RedactNoneReturnsReport(t *testing.T) {
+	t.Parallel()
+
+	report := sensitiveReport()
+	assert.Same(t, report, report.Redact(check.RedactNone))
+}
+
+func TestReport_RedactQueries(t *testing.T) {
+	t.Parallel()
+
+	report := sensitiveReport()
+	redacted := report.Redact(check.RedactQueries)
+
+	stuck := redacted.Results[0]
+	assert.Empty(t, stuck.Debug, "debug output carries SQL")
+	assert.Equal(t, "public.orders", stuck.Table.Rows[0].Cells[0], "identifiers are kept")
+	assert.True(t, strings.HasPrefix(stuck.Table.Rows[0].Cells[1], "<query:"))
+	assert.Equal(t, "-", stuck.Table.Rows[1].Cells[1], "placeholders for missing values are kept")
+	assert.Equal(t, "1 GB", stuck.Table.Rows[0].Cells[2])
+	assert.Equal(t, "autovacuum disabled on: public.orders", redacted.Results[1].Details)
+
+	assert.Contains(t, report.Results[0].Table.Rows[0].Cells[1], "a@example.com", "the original report is not modified")
+	assert.NotEmpty(t, report.Results[0].Debug)
+}
+
+func TestReport_RedactIdentifiers(t *testing.T) {
+	t.Parallel()
+
+	redacted := sensitiveReport().Redact(check.RedactIdentifiers)
+
+	stuck := redacted.Results[0]
+	orders := stuck.Table.Rows[0].Cells[0]
+	archive := stuck.Table.Rows[1].Cells[0]
+	app := stuck.Table.Rows[2].Cells[0]
+	assert.Regexp(t, `^<id:[0-9a-f]{8}>$`, orders)
+	assert.NotEqual(t, orders, archive)
+	assert.Equal(t, "", stuck.Table.Rows[2].Cells[1])
+	assert.Equal(t, archive+" and "+orders+" are locked by "+app, stuck.Details,
+		"table values are replaced in Details, longest first and as whole words")
+	assert.Equal(t, orders+" locked for 1h > max 10m → WARN", stuck.Reason, "and in Reason")
+	assert.Equal(t, "[redacted]", redacted.Results[1].Details)
+	assert.Equal(t, check.SeverityWarn, stuck.Table.Rows[0].Severity)
+}
+
+func TestReport_RedactIsStable(t *testing.T) {
+	t.Parallel()
+
+	a := sensitiveReport().Redact(check.RedactIdentifiers)
+	b := sensitiveReport().Redact(check.RedactIdentifiers)
+	require.Equal(t, a.Results[0].Table.Rows, b.Results[0].Table.Rows, "the same value always maps to the same placeholder")
+}
+
+func TestReport_RedactWholeWordsOnly(t *testing.T) {
+	t.Parallel()
+
+	report := check.NewReport(check.Metadata{CheckID: "demo"})
+	report.AddFinding(check.Finding{
+		ID:      "demo",
+		Details: "a table named a has a_b",
+		Table: &check.Table{
+			Headers:   []string{"Table"},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier},
+			Rows:      []check.TableRow{{Cells: []string{"a"}}},
+		},
+	})
+
+	redacted := report.Redact(check.RedactIdentifiers)
+	placeholder := redacted.Results[0].Table.Rows[0].Cells[0]
+	assert.Equal(t, placeholder+" table named "+placeholder+" has a_b", redacted.Results[0].Details)
+}
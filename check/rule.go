@@ -0,0 +1,464 @@
+package check
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Rule is a compiled boolean expression evaluated against one result row,
+// e.g. "bloat_pct > 40 and table_bytes >= 1073741824".
+//
+// Grammar:
+//
+//	expr    = and { ("or" | "||") and }
+//	and     = unary { ("and" | "&&") unary }
+//	unary   = ("not" | "!") unary | compare
+//	compare = operand [ ("==" | "!=" | "<" | "<=" | ">" | ">=") operand ]
+//	operand = number | 'string' | "string" | true | false | null | column | "(" expr ")"
+//
+// Columns are looked up by name in the row. Numbers and numeric strings
+// compare numerically; other strings compare lexically. Ordering comparisons
+// against null are false.
+type Rule struct {
+	source string
+	root   ruleNode
+}
+
+// ParseRule compiles an expression. It never executes anything beyond
+// comparisons and boolean logic over the row's values.
+func ParseRule(source string) (*Rule, error) {
+	tokens, err := lexRule(source)
+	if err != nil {
+		return nil, fmt.Errorf("parsing rule %q: %w", source, err)
+	}
+
+	p := &ruleParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err == nil && p.peek().kind != tokEOF {
+		err = fmt.Errorf("unexpected %q", p.peek().text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing rule %q: %w", source, err)
+	}
+
+	return &Rule{source: source, root: root}, nil
+}
+
+// String returns the rule's source expression.
+func (r *Rule) String() string {
+	return r.source
+}
+
+// Eval reports whether the row satisfies the rule. It fails if the rule
+// references a column the row doesn't have.
+func (r *Rule) Eval(row map[string]any) (bool, error) {
+	v, err := r.root.eval(row)
+	if err != nil {
+		return false, fmt.Errorf("evaluating rule %q: %w", r.source, err)
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("evaluating rule %q: result is %s, not a boolean", r.source, describe(v))
+	}
+	return b, nil
+}
+
+// SeverityRules assigns a severity to a row: FAIL if Fail matches, else WARN
+// if Warn matches, else OK. Either rule may be nil.
+type SeverityRules struct {
+	Fail *Rule
+	Warn *Rule
+}
+
+// Severity evaluates the rules against a row.
+func (s SeverityRules) Severity(row map[string]any) (Severity, error) {
+	for _, r := range []struct {
+		rule     *Rule
+		severity Severity
+	}{{s.Fail, SeverityFail}, {s.Warn, SeverityWarn}} {
+		if r.rule == nil {
+			continue
+		}
+		matched, err := r.rule.Eval(row)
+		if err != nil {
+			return SeverityOK, err
+		}
+		if matched {
+			return r.severity, nil
+		}
+	}
+	return SeverityOK, nil
+}
+
+// Lexer.
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func lexRule(src string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(src); {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(':
+			tokens = append(tokens, token{tokLParen, "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{tokRParen, ")"})
+			i++
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(src[i+1:], src[i])
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string at offset %d", i)
+			}
+			tokens = append(tokens, token{tokString, src[i+1 : i+1+end]})
+			i += end + 2
+		case strings.ContainsRune("<>=!&|", c):
+			op := src[i : i+1]
+			if i+1 < len(src) {
+				if two := src[i : i+2]; two == "<=" || two == ">=" || two == "==" || two == "!=" || two == "&&" || two == "||" {
+					op = two
+				}
+			}
+			if op == "=" || op == "&" || op == "|" {
+				return nil, fmt.Errorf("unknown operator %q at offset %d", op, i)
+			}
+			tokens = append(tokens, token{tokOp, op})
+			i += len(op)
+		case c == '-' || c == '.' || unicode.IsDigit(c):
+			j := i + 1
+			for j < len(src) && (unicode.IsDigit(rune(src[j])) || src[j] == '.' || src[j] == 'e' || src[j] == 'E') {
+				// An exponent can be signed, as in 1e-5.
+				if (src[j] == 'e' || src[j] == 'E') && j+1 < len(src) && (src[j+1] == '+' || src[j+1] == '-') {
+					j++
+				}
+				j++
+			}
+			tokens = append(tokens, token{tokNumber, src[i:j]})
+			i = j
+		case c == '_' || unicode.IsLetter(c):
+			j := i + 1
+			for j < len(src) && (src[j] == '_' || unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j]))) {
+				j++
+			}
+			tokens = append(tokens, token{tokIdent, src[i:j]})
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected character %q at offset %d", c, i)
+		}
+	}
+	return append(tokens, token{kind: tokEOF}), nil
+}
+
+// Parser.
+
+type ruleParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *ruleParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *ruleParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it is one of the given operators or
+// keywords (case-insensitive).
+func (p *ruleParser) accept(words ...string) bool {
+	t := p.peek()
+	if t.kind != tokOp && t.kind != tokIdent {
+		return false
+	}
+	for _, w := range words {
+		if strings.EqualFold(t.text, w) {
+			p.pos++
+			return true
+		}
+	}
+	return false
+}
+
+func (p *ruleParser) parseOr() (ruleNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("or", "||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalNode{or: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *ruleParser) parseAnd() (ruleNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("and", "&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *ruleParser) parseUnary() (ruleNode, error) {
+	if p.accept("not", "!") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand: operand}, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *ruleParser) parseCompare() (ruleNode, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	if t.kind != tokOp || t.text == "!" || t.text == "&&" || t.text == "||" {
+		return left, nil
+	}
+	p.next()
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return compareNode{op: t.text, left: left, right: right}, nil
+}
+
+func (p *ruleParser) parseOperand() (ruleNode, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", t.text)
+		}
+		return literalNode{value: f}, nil
+	case tokString:
+		return literalNode{value: t.text}, nil
+	case tokLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next().kind != tokRParen {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		return inner, nil
+	case tokIdent:
+		switch strings.ToLower(t.text) {
+		case "true":
+			return literalNode{value: true}, nil
+		case "false":
+			return literalNode{value: false}, nil
+		case "null":
+			return literalNode{value: nil}, nil
+		case "and", "or", "not":
+			return nil, fmt.Errorf("unexpected %q", t.text)
+		}
+		return columnNode{name: t.text}, nil
+	case tokEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	default:
+		return nil, fmt.Errorf("unexpected %q", t.text)
+	}
+}
+
+// Evaluation.
+
+type ruleNode interface {
+	eval(row map[string]any) (any, error)
+}
+
+type literalNode struct{ value any }
+
+func (n literalNode) eval(map[string]any) (any, error) { return n.value, nil }
+
+type columnNode struct{ name string }
+
+func (n columnNode) eval(row map[string]any) (any, error) {
+	v, ok := row[n.name]
+	if !ok {
+		return nil, fmt.Errorf("unknown column %q", n.name)
+	}
+	return v, nil
+}
+
+type notNode struct{ operand ruleNode }
+
+func (n notNode) eval(row map[string]any) (any, error) {
+	b, err := evalBool(n.operand, row)
+	if err != nil {
+		return nil, err
+	}
+	return !b, nil
+}
+
+type logicalNode struct {
+	or          bool
+	left, right ruleNode
+}
+
+func (n logicalNode) eval(row map[string]any) (any, error) {
+	left, err := evalBool(n.left, row)
+	if err != nil {
+		return nil, err
+	}
+	if left == n.or {
+		return left, nil
+	}
+	return evalBool(n.right, row)
+}
+
+type compareNode struct {
+	op          string
+	left, right ruleNode
+}
+
+func (n compareNode) eval(row map[string]any) (any, error) {
+	left, err := n.left.eval(row)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(row)
+	if err != nil {
+		return nil, err
+	}
+
+	if left == nil || right == nil {
+		switch n.op {
+		case "==":
+			return left == nil && right == nil, nil
+		case "!=":
+			return (left == nil) != (right == nil), nil
+		default:
+			return false, nil
+		}
+	}
+
+	var cmp int
+	lf, lNum := toFloat(left)
+	rf, rNum := toFloat(right)
+	lb, lBool := left.(bool)
+	rb, rBool := right.(bool)
+	ls, lStr := left.(string)
+	rs, rStr := right.(string)
+	switch {
+	case lNum && rNum:
+		cmp = compareFloats(lf, rf)
+	case lBool && rBool && (n.op == "==" || n.op == "!="):
+		if lb != rb {
+			cmp = 1
+		}
+	case lStr && rStr:
+		cmp = strings.Compare(ls, rs)
+	default:
+		return nil, fmt.Errorf("cannot compare %s %s %s", describe(left), n.op, describe(right))
+	}
+
+	switch n.op {
+	case "==":
+		return cmp == 0, nil
+	case "!=":
+		return cmp != 0, nil
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	case ">=":
+		return cmp >= 0, nil
+	default:
+		return nil, fmt.Errorf("unknown operator %q", n.op)
+	}
+}
+
+func evalBool(n ruleNode, row map[string]any) (bool, error) {
+	v, err := n.eval(row)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("%s is not a boolean", describe(v))
+	}
+	return b, nil
+}
+
+// toFloat converts numbers and numeric strings to float64.
+func toFloat(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func describe(v any) string {
+	if v == nil {
+		return "null"
+	}
+	return fmt.Sprintf("%T %v", v, v)
+}
@@ -0,0 +1,169 @@
+package check_test
+
+import (
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRule_Eval(t *testing.T) {
+	t.Parallel()
+
+	row := map[string]any{
+		"bloat_pct":  float64(45.5),
+		"rows":       int64(2_000_000),
+		"size_text":  "1024",
+		"schema":     "public",
+		"enabled":    true,
+		"last_check": nil,
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"bloat_pct > 40", true},
+		{"bloat_pct > 60", false},
+		{"bloat_pct >= 45.5", true},
+		{"bloat_pct < 45.5", false},
+		{"bloat_pct <= 45.5", true},
+		{"rows == 2000000", true},
+		{"rows != 2000000", false},
+		{"rows > 1e6", true},
+		{"bloat_pct > 1e-5", true},
+		{"rows < 2.5E+3", false},
+		{"bloat_pct < 4.6e+1", true},
+		{"size_text > 999", true},
+		{"schema == 'public'", true},
+		{`schema != "public"`, false},
+		{"schema < 'q'", true},
+		{"enabled == true", true},
+		{"enabled", true},
+		{"not enabled", false},
+		{"!enabled", false},
+		{"last_check == null", true},
+		{"last_check != null", false},
+		{"last_check > 5", false},
+		{"bloat_pct > 40 and rows > 1000000", true},
+		{"bloat_pct > 40 && rows > 5000000", false},
+		{"bloat_pct > 60 or rows > 1000000", true},
+		{"bloat_pct > 60 || rows > 5000000", false},
+		{"bloat_pct > 60 and rows > 0 or schema == 'public'", true},
+		{"bloat_pct > 60 and (rows > 0 or schema == 'public')", false},
+		{"NOT (bloat_pct > 60) AND enabled", true},
+		{"-1 < bloat_pct", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			t.Parallel()
+
+			rule, err := check.ParseRule(tt.expr)
+			require.NoError(t, err)
+
+			got, err := rule.Eval(row)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseRule_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []string{
+		"",
+		"bloat_pct >",
+		"bloat_pct = 40",
+		"(bloat_pct > 40",
+		"bloat_pct > 40)",
+		"bloat_pct > 'unterminated",
+		"bloat_pct > 40 and",
+		"bloat_pct ~ 40",
+		"and bloat_pct > 40",
+		"bloat_pct > 40 rows",
+	}
+
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := check.ParseRule(expr)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestRule_EvalErrors(t *testing.T) {
+	t.Parallel()
+
+	row := map[string]any{"bloat_pct": float64(50), "schema": "public", "enabled": true}
+
+	tests := []struct {
+		expr    string
+		message string
+	}{
+		{"missing > 1", `unknown column "missing"`},
+		{"schema > 1", "cannot compare"},
+		{"enabled > false", "cannot compare"},
+		{"bloat_pct", "not a boolean"},
+		{"bloat_pct and enabled", "not a boolean"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			t.Parallel()
+
+			rule, err := check.ParseRule(tt.expr)
+			require.NoError(t, err)
+
+			_, err = rule.Eval(row)
+			require.ErrorContains(t, err, tt.message)
+		})
+	}
+}
+
+func TestRule_ShortCircuits(t *testing.T) {
+	t.Parallel()
+
+	rule, err := check.ParseRule("enabled or missing > 1")
+	require.NoError(t, err)
+
+	got, err := rule.Eval(map[string]any{"enabled": true})
+	require.NoError(t, err, "the right-hand side is never evaluated")
+	assert.True(t, got)
+}
+
+func TestSeverityRules_Severity(t *testing.T) {
+	t.Parallel()
+
+	fail, err := check.ParseRule("bloat_pct > 60")
+	require.NoError(t, err)
+	warn, err := check.ParseRule("bloat_pct > 40")
+	require.NoError(t, err)
+
+	rules := check.SeverityRules{Fail: fail, Warn: warn}
+
+	tests := []struct {
+		bloat float64
+		want  check.Severity
+	}{
+		{70, check.SeverityFail},
+		{50, check.SeverityWarn},
+		{10, check.SeverityOK},
+	}
+	for _, tt := range tests {
+		got, err := rules.Severity(map[string]any{"bloat_pct": tt.bloat})
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, got, "bloat_pct=%v", tt.bloat)
+	}
+
+	got, err := check.SeverityRules{Warn: warn}.Severity(map[string]any{"bloat_pct": float64(70)})
+	require.NoError(t, err)
+	assert.Equal(t, check.SeverityWarn, got, "a missing fail rule never fails")
+
+	_, err = rules.Severity(map[string]any{})
+	require.ErrorContains(t, err, "bloat_pct")
+}
@@ -0,0 +1,95 @@
+package check
+
+import "fmt"
+
+// Config keys that bound the severity of every finding in a category. Like
+// EnabledKey they are read by the runner, from the entry keyed by the
+// category name (Config["indexes"]["max_severity"]), and take a severity
+// name: "warn" or "fail".
+const (
+	MinSeverityKey = "min_severity"
+	MaxSeverityKey = "max_severity"
+)
+
+// ParseSeverity parses a severity name as printed by Severity.String. Only
+// "pass", "warn", and "fail" are accepted; "skip" and "n/a" are not results a
+// check can be raised or lowered to.
+func ParseSeverity(s string) (Severity, error) {
+	for _, severity := range []Severity{SeverityOK, SeverityWarn, SeverityFail} {
+		if s == severity.String() {
+			return severity, nil
+		}
+	}
+	return SeverityOK, fmt.Errorf("unknown severity %q (valid: %s, %s, %s)", s, SeverityOK, SeverityWarn, SeverityFail)
+}
+
+// SeverityLimit bounds the severity of findings that report a problem. A zero
+// bound (SeverityOK) leaves that side open.
+type SeverityLimit struct {
+	Min Severity
+	Max Severity
+}
+
+// clamp applies the limit to one severity. Passing, skipped, and not
+// applicable results are facts, not judgements, so they are never changed.
+func (l SeverityLimit) clamp(s Severity) Severity {
+	if s <= SeverityOK {
+		return s
+	}
+	if l.Min > SeverityOK && s < l.Min {
+		s = l.Min
+	}
+	if l.Max > SeverityOK && s > l.Max {
+		s = l.Max
+	}
+	return s
+}
+
+// SeverityLimit returns the bounds configured for a category with
+// MinSeverityKey and MaxSeverityKey. Unparseable values are ignored.
+func (c Config) SeverityLimit(category Category) SeverityLimit {
+	var limit SeverityLimit
+	keys := c[string(category)]
+	if v, ok := keys[MinSeverityKey]; ok {
+		if s, err := ParseSeverity(v); err == nil {
+			limit.Min = s
+		}
+	}
+	if v, ok := keys[MaxSeverityKey]; ok {
+		if s, err := ParseSeverity(v); err == nil {
+			limit.Max = s
+		}
+	}
+	return limit
+}
+
+// ClampSeverity returns a copy of the report with every warning and failure,
+// in findings and their table rows, moved within the limit, and the report's
+// severity recomputed. Skipped and not applicable reports are returned
+// unchanged.
+func (r *Report) ClampSeverity(limit SeverityLimit) *Report {
+	if limit == (SeverityLimit{}) || r.Severity < SeverityOK {
+		return r
+	}
+
+	clamped := *r
+	clamped.Severity = SeverityOK
+	clamped.Results = make([]Finding, len(r.Results))
+	for i, finding := range r.Results {
+		finding.Severity = limit.clamp(finding.Severity)
+		if finding.Table != nil {
+			table := *finding.Table
+			table.Rows = make([]TableRow, len(finding.Table.Rows))
+			for j, row := range finding.Table.Rows {
+				row.Severity = limit.clamp(row.Severity)
+				table.Rows[j] = row
+			}
+			finding.Table = &table
+		}
+		clamped.Results[i] = finding
+		if finding.Severity > clamped.Severity {
+			clamped.Severity = finding.Severity
+		}
+	}
+	return &clamped
+}
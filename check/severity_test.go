@@ -0,0 +1,97 @@
+package check_test
+
+import (
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSeverity(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []check.Severity{check.SeverityOK, check.SeverityWarn, check.SeverityFail} {
+		got, err := check.ParseSeverity(s.String())
+		require.NoError(t, err)
+		assert.Equal(t, s, got)
+	}
+
+	_, err := check.ParseSeverity("skip")
+	require.ErrorContains(t, err, `unknown severity "skip"`)
+	_, err = check.ParseSeverity("critical")
+	require.Error(t, err)
+}
+
+func TestConfig_SeverityLimit(t *testing.T) {
+	t.Parallel()
+
+	cfg := check.Config{
+		"indexes": {check.MaxSeverityKey: "warn"},
+		"configs": {check.MinSeverityKey: "fail", check.MaxSeverityKey: "bogus"},
+	}
+	assert.Equal(t, check.SeverityLimit{Max: check.SeverityWarn}, cfg.SeverityLimit(check.CategoryIndexes))
+	assert.Equal(t, check.SeverityLimit{Min: check.SeverityFail}, cfg.SeverityLimit(check.CategoryConfigs), "unparseable values are ignored")
+	assert.Equal(t, check.SeverityLimit{}, cfg.SeverityLimit(check.CategoryVacuum))
+}
+
+func clampReport() *check.Report {
+	report := check.NewReport(check.Metadata{CheckID: "demo", Category: check.CategoryIndexes})
+	report.AddFinding(check.Finding{ID: "ok", Severity: check.SeverityOK})
+	report.AddFinding(check.Finding{ID: "warn", Severity: check.SeverityWarn})
+	report.AddFinding(check.Finding{ID: "fail", Severity: check.SeverityFail, Table: &check.Table{
+		Headers: []string{"Index"},
+		Rows: []check.TableRow{
+			{Cells: []string{"a"}, Severity: check.SeverityFail},
+			{Cells: []string{"b"}, Severity: check.SeverityOK},
+		},
+	}})
+	return report
+}
+
+func severities(r *check.Report) []check.Severity {
+	var out []check.Severity
+	for _, f := range r.Results {
+		out = append(out, f.Severity)
+	}
+	return out
+}
+
+func TestReport_ClampSeverity_Max(t *testing.T) {
+	t.Parallel()
+
+	report := clampReport()
+	clamped := report.ClampSeverity(check.SeverityLimit{Max: check.SeverityWarn})
+
+	assert.Equal(t, check.SeverityWarn, clamped.Severity)
+	assert.Equal(t, []check.Severity{check.SeverityOK, check.SeverityWarn, check.SeverityWarn}, severities(clamped))
+	assert.Equal(t, check.SeverityWarn, clamped.Results[2].Table.Rows[0].Severity)
+	assert.Equal(t, check.SeverityOK, clamped.Results[2].Table.Rows[1].Severity, "passing rows are never raised or lowered")
+
+	assert.Equal(t, check.SeverityFail, report.Severity, "the original report is not modified")
+	assert.Equal(t, check.SeverityFail, report.Results[2].Table.Rows[0].Severity)
+}
+
+func TestReport_ClampSeverity_Min(t *testing.T) {
+	t.Parallel()
+
+	clamped := clampReport().ClampSeverity(check.SeverityLimit{Min: check.SeverityFail})
+	assert.Equal(t, []check.Severity{check.SeverityOK, check.SeverityFail, check.SeverityFail}, severities(clamped))
+	assert.Equal(t, check.SeverityFail, clamped.Severity)
+}
+
+func TestReport_ClampSeverity_Unchanged(t *testing.T) {
+	t.Parallel()
+
+	report := clampReport()
+	assert.Same(t, report, report.ClampSeverity(check.SeverityLimit{}))
+
+	skipped := check.NewReport(check.Metadata{CheckID: "demo"})
+	skipped.Severity = check.SeveritySkip
+	skipped.AddFinding(check.Finding{ID: "error", Severity: check.SeveritySkip})
+	assert.Same(t, skipped, skipped.ClampSeverity(check.SeverityLimit{Min: check.SeverityFail}))
+
+	notApplicable := check.NewReport(check.Metadata{CheckID: "demo"})
+	notApplicable.AddFinding(check.Finding{ID: "demo", Severity: check.SeverityNotApplicable})
+	assert.Same(t, notApplicable, notApplicable.ClampSeverity(check.SeverityLimit{Min: check.SeverityFail}))
+}
@@ -0,0 +1,46 @@
+package check
+
+import (
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// MinStatsWindowSeconds is the shortest statistics window counters are rated
+// over; right after a reset they reflect minutes, not the workload.
+const MinStatsWindowSeconds = 3600
+
+// StatsWindow returns the seconds to rate cumulative statistics counters
+// over, for checks that divide them into rates. Queries select
+//
+//	EXTRACT(EPOCH FROM now() - stats_reset)::bigint AS seconds_since_reset
+//	EXTRACT(EPOCH FROM now() - pg_postmaster_start_time())::bigint AS uptime_seconds
+//
+// The window is the time since the last reset. stats_reset is NULL until the
+// first one, and the server's uptime stands in for it then, although the
+// counters may be older: they survive clean restarts since PostgreSQL 15.
+// The window is never shorter than MinStatsWindowSeconds, so counters that
+// just started do not turn into large rates. StatsWindowNote says when the
+// window is not the measured one.
+func StatsWindow(secondsSinceReset pgtype.Int8, uptimeSeconds int64) float64 {
+	seconds := uptimeSeconds
+	if secondsSinceReset.Valid {
+		seconds = secondsSinceReset.Int64
+	}
+	return float64(max(seconds, MinStatsWindowSeconds))
+}
+
+// StatsWindowNote explains, for the details of a finding, why the rates
+// StatsWindow gives are estimates, or returns "" when they cover a known
+// window of at least MinStatsWindowSeconds.
+func StatsWindowNote(secondsSinceReset pgtype.Int8) string {
+	switch {
+	case !secondsSinceReset.Valid:
+		return "statistics were never reset, so the counters are rated over the server's uptime and may overstate the rates; " +
+			"pg_stat_reset() starts a window to rate them over"
+	case secondsSinceReset.Int64 < MinStatsWindowSeconds:
+		return fmt.Sprintf("statistics were reset %s ago, so the counters are rated over %s and may understate the rates",
+			FormatDurationSec(secondsSinceReset.Int64), FormatDurationSec(MinStatsWindowSeconds))
+	}
+	return ""
+}
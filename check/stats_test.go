@@ -0,0 +1,36 @@
+package check_test
+
+import (
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsWindow(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		reset   pgtype.Int8
+		uptime  int64
+		seconds float64
+		note    string
+	}{
+		{"never reset", pgtype.Int8{}, 7200, 7200, "statistics were never reset, so the counters are rated over the server's uptime and may overstate the rates; pg_stat_reset() starts a window to rate them over"},
+		{"never reset, started recently", pgtype.Int8{}, 60, 3600, "statistics were never reset, so the counters are rated over the server's uptime and may overstate the rates; pg_stat_reset() starts a window to rate them over"},
+		{"reset recently", pgtype.Int8{Int64: 600, Valid: true}, 86400, 3600, "statistics were reset 10m ago, so the counters are rated over 1h and may understate the rates"},
+		{"an hour", pgtype.Int8{Int64: 3600, Valid: true}, 86400, 3600, ""},
+		{"a day", pgtype.Int8{Int64: 86400, Valid: true}, 600, 86400, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.seconds, check.StatsWindow(tt.reset, tt.uptime))
+			assert.Equal(t, tt.note, check.StatsWindowNote(tt.reset))
+		})
+	}
+}
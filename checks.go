@@ -5,32 +5,79 @@ package pgdoctor
 
 import (
 	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/checks/advisorylocks"
+	"github.com/emancu/pgdoctor/checks/autovacuumworkers"
+	"github.com/emancu/pgdoctor/checks/brinopportunity"
 	"github.com/emancu/pgdoctor/checks/cacheefficiency"
+	"github.com/emancu/pgdoctor/checks/collationmismatch"
+	"github.com/emancu/pgdoctor/checks/committimestamp"
+	"github.com/emancu/pgdoctor/checks/connectionchurn"
 	"github.com/emancu/pgdoctor/checks/connectionefficiency"
 	"github.com/emancu/pgdoctor/checks/connectionhealth"
+	"github.com/emancu/pgdoctor/checks/costparams"
+	"github.com/emancu/pgdoctor/checks/cronjobhealth"
+	"github.com/emancu/pgdoctor/checks/databasecollation"
+	"github.com/emancu/pgdoctor/checks/databasesettings"
+	"github.com/emancu/pgdoctor/checks/ddlchurn"
 	"github.com/emancu/pgdoctor/checks/duplicateindexes"
+	"github.com/emancu/pgdoctor/checks/extensionsecurity"
+	"github.com/emancu/pgdoctor/checks/fdwhealth"
 	"github.com/emancu/pgdoctor/checks/freezeage"
+	"github.com/emancu/pgdoctor/checks/grantaudit"
+	"github.com/emancu/pgdoctor/checks/hotchainbloat"
+	"github.com/emancu/pgdoctor/checks/hugepages"
+	"github.com/emancu/pgdoctor/checks/inactiveslots"
 	"github.com/emancu/pgdoctor/checks/indexbloat"
+	"github.com/emancu/pgdoctor/checks/indexcolumnorder"
+	"github.com/emancu/pgdoctor/checks/indexsizeratio"
 	"github.com/emancu/pgdoctor/checks/indexusage"
+	"github.com/emancu/pgdoctor/checks/insertvacuum"
 	"github.com/emancu/pgdoctor/checks/invalidindexes"
+	"github.com/emancu/pgdoctor/checks/iotiming"
+	"github.com/emancu/pgdoctor/checks/legacyinheritance"
+	"github.com/emancu/pgdoctor/checks/lockwaitrisk"
+	"github.com/emancu/pgdoctor/checks/namingissues"
+	"github.com/emancu/pgdoctor/checks/nevervacuumed"
+	"github.com/emancu/pgdoctor/checks/parallelquery"
+	"github.com/emancu/pgdoctor/checks/partitionfreezeskew"
 	"github.com/emancu/pgdoctor/checks/partitioning"
+	"github.com/emancu/pgdoctor/checks/partitionpruning"
 	"github.com/emancu/pgdoctor/checks/partitionusage"
+	"github.com/emancu/pgdoctor/checks/pendingrestart"
 	"github.com/emancu/pgdoctor/checks/pgversion"
+	"github.com/emancu/pgdoctor/checks/pkredundantindex"
 	"github.com/emancu/pgdoctor/checks/pktypes"
+	"github.com/emancu/pgdoctor/checks/preloadlibraries"
+	"github.com/emancu/pgdoctor/checks/publicationaudit"
+	"github.com/emancu/pgdoctor/checks/relationsizelimit"
+	"github.com/emancu/pgdoctor/checks/replicationcapacity"
+	"github.com/emancu/pgdoctor/checks/replicationfilters"
 	"github.com/emancu/pgdoctor/checks/replicationlag"
 	"github.com/emancu/pgdoctor/checks/replicationslots"
+	"github.com/emancu/pgdoctor/checks/rolememberships"
 	"github.com/emancu/pgdoctor/checks/sequencehealth"
 	"github.com/emancu/pgdoctor/checks/sessionsettings"
+	"github.com/emancu/pgdoctor/checks/sparsecolumns"
+	"github.com/emancu/pgdoctor/checks/statementsreset"
 	"github.com/emancu/pgdoctor/checks/statisticsfreshness"
 	"github.com/emancu/pgdoctor/checks/tableactivity"
 	"github.com/emancu/pgdoctor/checks/tablebloat"
 	"github.com/emancu/pgdoctor/checks/tableseqscans"
+	"github.com/emancu/pgdoctor/checks/tablespaceplacement"
 	"github.com/emancu/pgdoctor/checks/tablevacuumhealth"
+	"github.com/emancu/pgdoctor/checks/temptablespace"
 	"github.com/emancu/pgdoctor/checks/tempusage"
+	"github.com/emancu/pgdoctor/checks/toastconfig"
 	"github.com/emancu/pgdoctor/checks/toaststorage"
+	"github.com/emancu/pgdoctor/checks/uptime"
 	"github.com/emancu/pgdoctor/checks/uuiddefaults"
 	"github.com/emancu/pgdoctor/checks/uuidtypes"
+	"github.com/emancu/pgdoctor/checks/vacuumcostbudget"
+	"github.com/emancu/pgdoctor/checks/vacuumscalefactors"
 	"github.com/emancu/pgdoctor/checks/vacuumsettings"
+	"github.com/emancu/pgdoctor/checks/vacuumsla"
+	"github.com/emancu/pgdoctor/checks/walsettings"
+	"github.com/emancu/pgdoctor/checks/xminhorizon"
 	"github.com/emancu/pgdoctor/db"
 )
 
@@ -38,12 +85,48 @@ import (
 // Consumers call .Metadata() for check information or .New(conn, cfg) to instantiate checkers.
 func AllChecks() []check.Package {
 	return []check.Package{
+		{
+			Metadata: advisorylocks.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return advisorylocks.New(db.New(conn), cfg)
+			},
+		},
+		{
+			Metadata: autovacuumworkers.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return autovacuumworkers.New(db.New(conn), cfg)
+			},
+		},
+		{
+			Metadata: brinopportunity.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return brinopportunity.New(db.New(conn), cfg)
+			},
+		},
 		{
 			Metadata: cacheefficiency.Metadata,
 			New: func(conn db.DBTX, cfg check.Config) check.Checker {
 				return cacheefficiency.New(db.New(conn), cfg)
 			},
 		},
+		{
+			Metadata: collationmismatch.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return collationmismatch.New(db.New(conn), cfg)
+			},
+		},
+		{
+			Metadata: committimestamp.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return committimestamp.New(db.New(conn), cfg)
+			},
+		},
+		{
+			Metadata: connectionchurn.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return connectionchurn.New(db.New(conn), cfg)
+			},
+		},
 		{
 			Metadata: connectionefficiency.Metadata,
 			New: func(conn db.DBTX, cfg check.Config) check.Checker {
@@ -56,60 +139,234 @@ func AllChecks() []check.Package {
 				return connectionhealth.New(db.New(conn), cfg)
 			},
 		},
+		{
+			Metadata: costparams.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return costparams.New(db.New(conn), cfg)
+			},
+		},
+		{
+			Metadata: cronjobhealth.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return cronjobhealth.New(db.New(conn), cfg)
+			},
+		},
+		{
+			Metadata: databasecollation.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return databasecollation.New(db.New(conn), cfg)
+			},
+		},
+		{
+			Metadata: databasesettings.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return databasesettings.New(db.New(conn), cfg)
+			},
+		},
+		{
+			Metadata: ddlchurn.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return ddlchurn.New(db.New(conn), cfg)
+			},
+		},
 		{
 			Metadata: duplicateindexes.Metadata,
 			New: func(conn db.DBTX, cfg check.Config) check.Checker {
 				return duplicateindexes.New(db.New(conn), cfg)
 			},
 		},
+		{
+			Metadata: extensionsecurity.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return extensionsecurity.New(db.New(conn), cfg)
+			},
+		},
+		{
+			Metadata: fdwhealth.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return fdwhealth.New(db.New(conn), cfg)
+			},
+		},
 		{
 			Metadata: freezeage.Metadata,
 			New: func(conn db.DBTX, cfg check.Config) check.Checker {
 				return freezeage.New(db.New(conn), cfg)
 			},
 		},
+		{
+			Metadata: grantaudit.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return grantaudit.New(db.New(conn), cfg)
+			},
+		},
+		{
+			Metadata: hotchainbloat.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return hotchainbloat.New(db.New(conn), cfg)
+			},
+		},
+		{
+			Metadata: hugepages.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return hugepages.New(db.New(conn), cfg)
+			},
+		},
+		{
+			Metadata: inactiveslots.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return inactiveslots.New(db.New(conn), cfg)
+			},
+		},
 		{
 			Metadata: indexbloat.Metadata,
 			New: func(conn db.DBTX, cfg check.Config) check.Checker {
 				return indexbloat.New(db.New(conn), cfg)
 			},
 		},
+		{
+			Metadata: indexcolumnorder.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return indexcolumnorder.New(db.New(conn), cfg)
+			},
+		},
+		{
+			Metadata: indexsizeratio.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return indexsizeratio.New(db.New(conn), cfg)
+			},
+		},
 		{
 			Metadata: indexusage.Metadata,
 			New: func(conn db.DBTX, cfg check.Config) check.Checker {
 				return indexusage.New(db.New(conn), cfg)
 			},
 		},
+		{
+			Metadata: insertvacuum.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return insertvacuum.New(db.New(conn), cfg)
+			},
+		},
 		{
 			Metadata: invalidindexes.Metadata,
 			New: func(conn db.DBTX, cfg check.Config) check.Checker {
 				return invalidindexes.New(db.New(conn), cfg)
 			},
 		},
+		{
+			Metadata: iotiming.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return iotiming.New(db.New(conn), cfg)
+			},
+		},
+		{
+			Metadata: legacyinheritance.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return legacyinheritance.New(db.New(conn), cfg)
+			},
+		},
+		{
+			Metadata: lockwaitrisk.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return lockwaitrisk.New(db.New(conn), cfg)
+			},
+		},
+		{
+			Metadata: namingissues.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return namingissues.New(db.New(conn), cfg)
+			},
+		},
+		{
+			Metadata: nevervacuumed.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return nevervacuumed.New(db.New(conn), cfg)
+			},
+		},
+		{
+			Metadata: parallelquery.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return parallelquery.New(db.New(conn), cfg)
+			},
+		},
+		{
+			Metadata: partitionfreezeskew.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return partitionfreezeskew.New(db.New(conn), cfg)
+			},
+		},
 		{
 			Metadata: partitioning.Metadata,
 			New: func(conn db.DBTX, cfg check.Config) check.Checker {
 				return partitioning.New(db.New(conn), cfg)
 			},
 		},
+		{
+			Metadata: partitionpruning.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return partitionpruning.New(db.New(conn), cfg)
+			},
+		},
 		{
 			Metadata: partitionusage.Metadata,
 			New: func(conn db.DBTX, cfg check.Config) check.Checker {
 				return partitionusage.New(db.New(conn), cfg)
 			},
 		},
+		{
+			Metadata: pendingrestart.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return pendingrestart.New(db.New(conn), cfg)
+			},
+		},
 		{
 			Metadata: pgversion.Metadata,
 			New: func(conn db.DBTX, cfg check.Config) check.Checker {
 				return pgversion.New(db.New(conn), cfg)
 			},
 		},
+		{
+			Metadata: pkredundantindex.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return pkredundantindex.New(db.New(conn), cfg)
+			},
+		},
 		{
 			Metadata: pktypes.Metadata,
 			New: func(conn db.DBTX, cfg check.Config) check.Checker {
 				return pktypes.New(db.New(conn), cfg)
 			},
 		},
+		{
+			Metadata: preloadlibraries.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return preloadlibraries.New(db.New(conn), cfg)
+			},
+		},
+		{
+			Metadata: publicationaudit.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return publicationaudit.New(db.New(conn), cfg)
+			},
+		},
+		{
+			Metadata: relationsizelimit.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return relationsizelimit.New(db.New(conn), cfg)
+			},
+		},
+		{
+			Metadata: replicationcapacity.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return replicationcapacity.New(db.New(conn), cfg)
+			},
+		},
+		{
+			Metadata: replicationfilters.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return replicationfilters.New(db.New(conn), cfg)
+			},
+		},
 		{
 			Metadata: replicationlag.Metadata,
 			New: func(conn db.DBTX, cfg check.Config) check.Checker {
@@ -122,6 +379,12 @@ func AllChecks() []check.Package {
 				return replicationslots.New(db.New(conn), cfg)
 			},
 		},
+		{
+			Metadata: rolememberships.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return rolememberships.New(db.New(conn), cfg)
+			},
+		},
 		{
 			Metadata: sequencehealth.Metadata,
 			New: func(conn db.DBTX, cfg check.Config) check.Checker {
@@ -134,6 +397,18 @@ func AllChecks() []check.Package {
 				return sessionsettings.New(db.New(conn), cfg)
 			},
 		},
+		{
+			Metadata: sparsecolumns.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return sparsecolumns.New(db.New(conn), cfg)
+			},
+		},
+		{
+			Metadata: statementsreset.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return statementsreset.New(db.New(conn), cfg)
+			},
+		},
 		{
 			Metadata: statisticsfreshness.Metadata,
 			New: func(conn db.DBTX, cfg check.Config) check.Checker {
@@ -158,24 +433,48 @@ func AllChecks() []check.Package {
 				return tableseqscans.New(db.New(conn), cfg)
 			},
 		},
+		{
+			Metadata: tablespaceplacement.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return tablespaceplacement.New(db.New(conn), cfg)
+			},
+		},
 		{
 			Metadata: tablevacuumhealth.Metadata,
 			New: func(conn db.DBTX, cfg check.Config) check.Checker {
 				return tablevacuumhealth.New(db.New(conn), cfg)
 			},
 		},
+		{
+			Metadata: temptablespace.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return temptablespace.New(db.New(conn), cfg)
+			},
+		},
 		{
 			Metadata: tempusage.Metadata,
 			New: func(conn db.DBTX, cfg check.Config) check.Checker {
 				return tempusage.New(db.New(conn), cfg)
 			},
 		},
+		{
+			Metadata: toastconfig.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return toastconfig.New(db.New(conn), cfg)
+			},
+		},
 		{
 			Metadata: toaststorage.Metadata,
 			New: func(conn db.DBTX, cfg check.Config) check.Checker {
 				return toaststorage.New(db.New(conn), cfg)
 			},
 		},
+		{
+			Metadata: uptime.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return uptime.New(db.New(conn), cfg)
+			},
+		},
 		{
 			Metadata: uuiddefaults.Metadata,
 			New: func(conn db.DBTX, cfg check.Config) check.Checker {
@@ -188,11 +487,41 @@ func AllChecks() []check.Package {
 				return uuidtypes.New(db.New(conn), cfg)
 			},
 		},
+		{
+			Metadata: vacuumcostbudget.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return vacuumcostbudget.New(db.New(conn), cfg)
+			},
+		},
+		{
+			Metadata: vacuumscalefactors.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return vacuumscalefactors.New(db.New(conn), cfg)
+			},
+		},
 		{
 			Metadata: vacuumsettings.Metadata,
 			New: func(conn db.DBTX, cfg check.Config) check.Checker {
 				return vacuumsettings.New(db.New(conn), cfg)
 			},
 		},
+		{
+			Metadata: vacuumsla.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return vacuumsla.New(db.New(conn), cfg)
+			},
+		},
+		{
+			Metadata: walsettings.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return walsettings.New(db.New(conn), cfg)
+			},
+		},
+		{
+			Metadata: xminhorizon.Metadata,
+			New: func(conn db.DBTX, cfg check.Config) check.Checker {
+				return xminhorizon.New(db.New(conn), cfg)
+			},
+		},
 	}
 }
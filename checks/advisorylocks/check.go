@@ -0,0 +1,137 @@
+// Package advisorylocks implements a check for advisory locks held by long-lived sessions.
+package advisorylocks
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strconv"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type AdvisoryLocksQueries interface {
+	AdvisoryLocks(context.Context) ([]db.AdvisoryLocksRow, error)
+}
+
+type checker struct {
+	queries       AdvisoryLocksQueries
+	maxAgeSeconds int64 // default: 3600
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryPerformance,
+		CheckID:     "advisory-locks",
+		Name:        "Advisory Locks",
+		Description: "Identifies advisory locks held by long-lived sessions that were likely never released",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries AdvisoryLocksQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries:       queries,
+		maxAgeSeconds: 3600,
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			if v, ok := myCfg["max_age_seconds"]; ok {
+				if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+					c.maxAgeSeconds = n
+				}
+			}
+		}
+	}
+	return c
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	rows, err := c.queries.AdvisoryLocks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	var tableRows []check.TableRow
+	for _, row := range rows {
+		if check.Int8ToInt64(row.SessionAgeSeconds) < c.maxAgeSeconds {
+			continue
+		}
+		tableRows = append(tableRows, check.TableRow{
+			Cells: []string{
+				row.LockKey,
+				row.LockMode,
+				fmt.Sprintf("%d", row.Pid.Int32),
+				row.Username,
+				valueOrDash(row.ApplicationName),
+				formatState(row),
+				check.FormatDurationSec(check.Int8ToInt64(row.SessionAgeSeconds)),
+			},
+			Severity: check.SeverityWarn,
+		})
+	}
+
+	if len(tableRows) == 0 {
+		details := "No advisory locks held"
+		if len(rows) > 0 {
+			details = fmt.Sprintf("%d advisory lock(s) held, none by a session older than %s",
+				len(rows), check.FormatDurationSec(c.maxAgeSeconds))
+		}
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  details,
+		})
+		return report, nil
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: check.SeverityWarn,
+		Details: fmt.Sprintf("Found %d advisory lock(s) held by sessions older than %s",
+			len(tableRows), check.FormatDurationSec(c.maxAgeSeconds)),
+		Table: &check.Table{
+			Headers:   []string{"Key", "Mode", "PID", "User", "Application", "State", "Session Age"},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignLeft, check.AlignRight, check.AlignLeft, check.AlignLeft, check.AlignLeft, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.NotSensitive, check.NotSensitive, check.NotSensitive, check.SensitiveIdentifier, check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	})
+
+	return report, nil
+}
+
+// formatState shows how long an idle session has been sitting on its locks,
+// which is the strongest hint that a lock was forgotten.
+func formatState(row db.AdvisoryLocksRow) string {
+	if row.State == "" {
+		return "-"
+	}
+	if row.State == "idle" && row.StateAgeSeconds.Valid {
+		return fmt.Sprintf("idle %s", check.FormatDurationSec(row.StateAgeSeconds.Int64))
+	}
+	return row.State
+}
+
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,129 @@
+package advisorylocks_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/checks/advisorylocks"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockQueryer struct {
+	rows []db.AdvisoryLocksRow
+	err  error
+}
+
+func (m *mockQueryer) AdvisoryLocks(context.Context) ([]db.AdvisoryLocksRow, error) {
+	return m.rows, m.err
+}
+
+func heldLock(pid int32, key, state string, sessionAge, stateAge int64) db.AdvisoryLocksRow {
+	return db.AdvisoryLocksRow{
+		Pid:               pgtype.Int4{Int32: pid, Valid: true},
+		LockKey:           key,
+		LockMode:          "ExclusiveLock",
+		Username:          "app",
+		ApplicationName:   "worker",
+		State:             state,
+		SessionAgeSeconds: pgtype.Int8{Int64: sessionAge, Valid: true},
+		StateAgeSeconds:   pgtype.Int8{Int64: stateAge, Valid: true},
+	}
+}
+
+func TestAdvisoryLocks_Severity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		rows     []db.AdvisoryLocksRow
+		severity check.Severity
+		details  string
+	}{
+		{
+			name:     "no advisory locks",
+			rows:     nil,
+			severity: check.SeverityOK,
+			details:  "No advisory locks held",
+		},
+		{
+			name:     "lock held by young session",
+			rows:     []db.AdvisoryLocksRow{heldLock(101, "42", "active", 120, 1)},
+			severity: check.SeverityOK,
+			details:  "1 advisory lock(s) held, none by a session older than 1h",
+		},
+		{
+			name:     "lock held by old session",
+			rows:     []db.AdvisoryLocksRow{heldLock(101, "42", "idle", 7200, 7000)},
+			severity: check.SeverityWarn,
+			details:  "Found 1 advisory lock(s) held by sessions older than 1h",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			report, err := advisorylocks.New(&mockQueryer{rows: tt.rows}).Check(context.Background())
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.severity, report.Severity)
+			require.Len(t, report.Results, 1)
+			assert.Equal(t, "advisory-locks", report.Results[0].ID)
+			assert.Equal(t, tt.details, report.Results[0].Details)
+		})
+	}
+}
+
+func TestAdvisoryLocks_TableContents(t *testing.T) {
+	t.Parallel()
+
+	report, err := advisorylocks.New(&mockQueryer{rows: []db.AdvisoryLocksRow{
+		heldLock(101, "42", "idle", 2*86400, 3*3600),
+		heldLock(202, "7,9", "active", 5400, 2),
+		heldLock(303, "8", "idle", 60, 30),
+	}}).Check(context.Background())
+	require.NoError(t, err)
+
+	table := report.Results[0].Table
+	require.NotNil(t, table)
+	assert.Equal(t, []string{"Key", "Mode", "PID", "User", "Application", "State", "Session Age"}, table.Headers)
+	require.Len(t, table.Rows, 2, "the young session is not reported")
+	assert.Equal(t, []string{"42", "ExclusiveLock", "101", "app", "worker", "idle 3h", "2d"}, table.Rows[0].Cells)
+	assert.Equal(t, "active", table.Rows[1].Cells[5])
+	assert.Equal(t, check.SeverityWarn, table.Rows[0].Severity)
+}
+
+func TestAdvisoryLocks_ConfigMaxAge(t *testing.T) {
+	t.Parallel()
+
+	cfg := check.Config{"advisory-locks": {"max_age_seconds": "60"}}
+	report, err := advisorylocks.New(&mockQueryer{rows: []db.AdvisoryLocksRow{
+		heldLock(101, "42", "idle", 120, 100),
+	}}, cfg).Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, check.SeverityWarn, report.Severity)
+}
+
+func TestAdvisoryLocks_QueryError(t *testing.T) {
+	t.Parallel()
+
+	_, err := advisorylocks.New(&mockQueryer{err: fmt.Errorf("connection refused")}).Check(context.Background())
+	require.ErrorContains(t, err, "advisory-locks")
+}
+
+func TestAdvisoryLocks_Metadata(t *testing.T) {
+	t.Parallel()
+
+	m := advisorylocks.Metadata()
+	assert.Equal(t, "advisory-locks", m.CheckID)
+	assert.Equal(t, "Advisory Locks", m.Name)
+	assert.Equal(t, check.CategoryPerformance, m.Category)
+	assert.NotEmpty(t, m.Description)
+	assert.NotEmpty(t, m.SQL)
+	assert.NotEmpty(t, m.Readme)
+}
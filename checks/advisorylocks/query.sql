@@ -0,0 +1,25 @@
+-- name: AdvisoryLocks :many
+-- Granted advisory locks with the holding session's age and state.
+-- PostgreSQL does not record when a lock was acquired, so the session age is
+-- the upper bound on how long the lock may have been held. Keys taken as a
+-- single bigint (objsubid = 1) are reassembled from classid/objid; two-int4
+-- keys (objsubid = 2) are shown as "classid,objid".
+SELECT
+  l.pid
+  , CASE
+    WHEN l.objsubid = 1 THEN ((l.classid::bigint << 32) | l.objid::bigint)::text
+    ELSE l.classid::text || ',' || l.objid::text
+  END::text AS lock_key
+  , l.mode::text AS lock_mode
+  , coalesce(a.usename::text, '')::text AS username
+  , coalesce(a.application_name, '')::text AS application_name
+  , coalesce(a.state, '')::text AS state
+  , extract(EPOCH FROM (now() - a.backend_start))::bigint AS session_age_seconds
+  , extract(EPOCH FROM (now() - a.state_change))::bigint AS state_age_seconds
+FROM pg_locks AS l
+INNER JOIN pg_stat_activity AS a ON l.pid = a.pid
+WHERE
+  l.locktype = 'advisory'
+  AND l.granted
+  AND l.database = (SELECT oid FROM pg_database WHERE datname = current_database())
+ORDER BY session_age_seconds DESC, l.pid;
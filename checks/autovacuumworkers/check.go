@@ -0,0 +1,126 @@
+// Package autovacuumworkers implements a check for autovacuum worker saturation.
+package autovacuumworkers
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type AutovacuumWorkersQueries interface {
+	AutovacuumMaxWorkers(context.Context) (int32, error)
+	AutovacuumWorkers(context.Context) ([]db.AutovacuumWorkersRow, error)
+}
+
+type checker struct {
+	queries AutovacuumWorkersQueries
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryVacuum,
+		CheckID:     "autovacuum-workers",
+		Name:        "Autovacuum Worker Saturation",
+		Description: "Compares running autovacuum workers against autovacuum_max_workers; when every worker is busy, other tables wait for vacuum",
+		Readme:      readme,
+		SQL:         querySQL,
+		ClusterWide: true,
+	}
+}
+
+func New(queries AutovacuumWorkersQueries, _ ...check.Config) check.Checker {
+	return &checker{
+		queries: queries,
+	}
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	maxWorkers, err := c.queries.AutovacuumMaxWorkers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+	workers, err := c.queries.AutovacuumWorkers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	if len(workers) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  fmt.Sprintf("No autovacuum workers running (autovacuum_max_workers = %d)", maxWorkers),
+		})
+		return report, nil
+	}
+
+	severity := check.SeverityOK
+	if len(workers) >= int(maxWorkers) {
+		severity = check.SeverityWarn
+	}
+
+	wraparound := 0
+	tableRows := make([]check.TableRow, 0, len(workers))
+	for _, w := range workers {
+		if w.Wraparound {
+			wraparound++
+		}
+		phase := "-"
+		if w.Phase.Valid {
+			phase = w.Phase.String
+		}
+		scanned := "-"
+		if w.ScannedPercent.Valid {
+			scanned = fmt.Sprintf("%.0f%%", w.ScannedPercent.Float64)
+		}
+		tableRows = append(tableRows, check.TableRow{
+			Cells: []string{
+				w.DatabaseName,
+				w.Activity,
+				phase,
+				scanned,
+				check.FormatDurationSec(w.RunningSeconds),
+			},
+			Severity: severity,
+		})
+	}
+
+	details := fmt.Sprintf("%d of %d autovacuum workers busy", len(workers), maxWorkers)
+	if wraparound > 0 {
+		details += fmt.Sprintf(" (%d preventing wraparound)", wraparound)
+	}
+	if severity == check.SeverityWarn {
+		details += "; other tables that need vacuum wait until a worker frees up. " +
+			"If this persists, raise autovacuum_max_workers or the cost limit so each run finishes sooner"
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: severity,
+		Details:  details,
+		Table: &check.Table{
+			Headers:   []string{"Database", "Activity", "Phase", "Scanned", "Running For"},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignLeft, check.AlignLeft, check.AlignRight, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	})
+
+	return report, nil
+}
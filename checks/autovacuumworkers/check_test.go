@@ -0,0 +1,131 @@
+package autovacuumworkers_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/autovacuumworkers"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeQueries struct {
+	maxWorkers checktest.Result[int32]
+	workers    checktest.Result[[]db.AutovacuumWorkersRow]
+}
+
+func (f fakeQueries) AutovacuumMaxWorkers(context.Context) (int32, error) {
+	return f.maxWorkers.Get()
+}
+
+func (f fakeQueries) AutovacuumWorkers(context.Context) ([]db.AutovacuumWorkersRow, error) {
+	return f.workers.Get()
+}
+
+func worker(activity string, runningSeconds int64) db.AutovacuumWorkersRow {
+	return db.AutovacuumWorkersRow{
+		DatabaseName:   "app",
+		Activity:       activity,
+		RunningSeconds: runningSeconds,
+		Phase:          pgtype.Text{String: "scanning heap", Valid: true},
+		ScannedPercent: pgtype.Float8{Float64: 42.4, Valid: true},
+	}
+}
+
+func newChecker(maxWorkers int32, workers ...db.AutovacuumWorkersRow) check.Checker {
+	return autovacuumworkers.New(fakeQueries{
+		maxWorkers: checktest.Returns(maxWorkers),
+		workers:    checktest.Returns(workers),
+	})
+}
+
+func TestAutovacuumWorkers_Idle(t *testing.T) {
+	t.Parallel()
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(3)))
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "No autovacuum workers running (autovacuum_max_workers = 3)", finding.Details)
+	assert.Nil(t, finding.Table)
+}
+
+func TestAutovacuumWorkers_Thresholds(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		running  int
+		expected check.Severity
+	}{
+		{"one of three", 1, check.SeverityOK},
+		{"two of three", 2, check.SeverityOK},
+		{"saturated", 3, check.SeverityWarn},
+		{"above max after a reload", 4, check.SeverityWarn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			workers := make([]db.AutovacuumWorkersRow, tt.running)
+			for i := range workers {
+				workers[i] = worker(fmt.Sprintf("VACUUM public.t%d", i), 60)
+			}
+			finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(3, workers...)))
+			assert.Equal(t, tt.expected, finding.Severity)
+			assert.Len(t, checktest.Rows(finding), tt.running, "every worker is listed")
+		})
+	}
+}
+
+func TestAutovacuumWorkers_Table(t *testing.T) {
+	t.Parallel()
+
+	wraparound := worker("VACUUM public.events (to prevent wraparound)", 7200)
+	wraparound.Wraparound = true
+	analyze := worker("ANALYZE public.users", 5)
+	analyze.Phase = pgtype.Text{}
+	analyze.ScannedPercent = pgtype.Float8{}
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(2, wraparound, analyze)))
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Contains(t, finding.Details, "2 of 2 autovacuum workers busy (1 preventing wraparound)")
+	assert.Contains(t, finding.Details, "autovacuum_max_workers")
+	assert.Equal(t, [][]string{
+		{"app", "VACUUM public.events (to prevent wraparound)", "scanning heap", "42%", "2h"},
+		{"app", "ANALYZE public.users", "-", "-", "5s"},
+	}, checktest.Rows(finding))
+}
+
+func TestAutovacuumWorkers_WraparoundFlag(t *testing.T) {
+	t.Parallel()
+
+	wraparound := worker("VACUUM public.events (to prevent wraparound)", 7200)
+	wraparound.Wraparound = true
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(3, wraparound)))
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "1 of 3 autovacuum workers busy (1 preventing wraparound)", finding.Details)
+}
+
+func TestAutovacuumWorkers_QueryError(t *testing.T) {
+	t.Parallel()
+
+	queryErr := fmt.Errorf("connection refused")
+	checktest.RequireError(t, autovacuumworkers.New(fakeQueries{maxWorkers: checktest.Fails[int32](queryErr)}))
+	checktest.RequireError(t, autovacuumworkers.New(fakeQueries{
+		maxWorkers: checktest.Returns(int32(3)),
+		workers:    checktest.Fails[[]db.AutovacuumWorkersRow](queryErr),
+	}))
+}
+
+func TestAutovacuumWorkers_Metadata(t *testing.T) {
+	t.Parallel()
+
+	m := autovacuumworkers.Metadata()
+	checktest.RequireMetadata(t, m, "autovacuum-workers", check.CategoryVacuum)
+	assert.True(t, m.ClusterWide, "workers are counted across all databases")
+}
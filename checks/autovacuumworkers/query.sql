@@ -0,0 +1,23 @@
+-- name: AutovacuumMaxWorkers :one
+-- The number of autovacuum workers the launcher may run at once.
+SELECT current_setting('autovacuum_max_workers')::int AS max_workers;
+
+-- name: AutovacuumWorkers :many
+-- Autovacuum workers running now, in every database. The query text names
+-- the table and whether the run prevents wraparound; for backends of other
+-- roles it is only visible with pg_read_all_stats.
+SELECT
+  a.pid
+  , coalesce(a.datname, '')::text AS database_name
+  , regexp_replace(coalesce(a.query, ''), '^autovacuum: ', '')::text AS activity
+  , coalesce(a.query LIKE '%(to prevent wraparound)', false)::bool AS wraparound
+  , coalesce(EXTRACT(EPOCH FROM now() - a.xact_start), 0)::bigint AS running_seconds
+  , p.phase::text AS phase
+  , CASE
+    WHEN p.heap_blks_total > 0
+      THEN (p.heap_blks_scanned * 100.0 / p.heap_blks_total)::float8
+  END AS scanned_percent
+FROM pg_stat_activity AS a
+LEFT JOIN pg_stat_progress_vacuum AS p ON p.pid = a.pid
+WHERE a.backend_type = 'autovacuum worker'
+ORDER BY running_seconds DESC;
@@ -0,0 +1,149 @@
+// Package brinopportunity implements a check for large btree indexes on append-mostly tables that BRIN could replace.
+package brinopportunity
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type BrinOpportunityQueries interface {
+	BrinCandidates(ctx context.Context, minIndexBytes int64) ([]db.BrinCandidatesRow, error)
+}
+
+type checker struct {
+	queries        BrinOpportunityQueries
+	minIndexSizeMB int64   // default: 100
+	maxChangeRatio float64 // updates and deletes per insert; default: 0.01
+	minCorrelation float64 // absolute; default: 0.9
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryIndexes,
+		CheckID:     "brin-opportunity",
+		Name:        "BRIN Opportunity",
+		Description: "Finds large btree indexes on naturally ordered columns of append-mostly tables that a much smaller BRIN index could replace",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries BrinOpportunityQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries:        queries,
+		minIndexSizeMB: 100,
+		maxChangeRatio: 0.01,
+		minCorrelation: 0.9,
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			if v, ok := myCfg["min_index_size_mb"]; ok {
+				if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+					c.minIndexSizeMB = n
+				}
+			}
+			if v, ok := myCfg["max_change_ratio"]; ok {
+				if n, err := strconv.ParseFloat(v, 64); err == nil && n >= 0 {
+					c.maxChangeRatio = n
+				}
+			}
+			if v, ok := myCfg["min_correlation"]; ok {
+				if n, err := strconv.ParseFloat(v, 64); err == nil && n > 0 && n <= 1 {
+					c.minCorrelation = n
+				}
+			}
+		}
+	}
+	return c
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	rows, err := c.queries.BrinCandidates(ctx, c.minIndexSizeMB*1024*1024)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	var tableRows []check.TableRow
+	var savings int64
+	for _, row := range rows {
+		if row.Inserts == 0 || float64(row.Updates+row.Deletes) > c.maxChangeRatio*float64(row.Inserts) {
+			continue
+		}
+		if math.Abs(row.Correlation) < c.minCorrelation {
+			continue
+		}
+		estimate := brinSizeEstimate(row.TablePages)
+		if estimate >= row.IndexSizeBytes {
+			continue
+		}
+		savings += row.IndexSizeBytes - estimate
+		tableRows = append(tableRows, check.TableRow{
+			Cells: []string{
+				row.SchemaName + "." + row.TableName,
+				row.ColumnName,
+				row.IndexName,
+				check.FormatBytes(row.IndexSizeBytes),
+				check.FormatBytes(estimate),
+				check.FormatBytes(row.IndexSizeBytes - estimate),
+			},
+			Severity: check.SeverityWarn,
+		})
+	}
+
+	if len(tableRows) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  fmt.Sprintf("No btree index of %dMB or more on a naturally ordered column of an append-mostly table", c.minIndexSizeMB),
+		})
+		return report, nil
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: check.SeverityWarn,
+		Details: fmt.Sprintf("Found %d btree index(es) on append-mostly tables whose column follows the physical row order; "+
+			"BRIN indexes could save about %s, if the column is only queried by range",
+			len(tableRows), check.FormatBytes(savings)),
+		Table: &check.Table{
+			Headers:   []string{"Table", "Column", "Index", "Index Size", "Est. BRIN Size", "Savings"},
+			Key:       []int{0, 2},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignLeft, check.AlignLeft, check.AlignRight, check.AlignRight, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.SensitiveIdentifier, check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	})
+
+	return report, nil
+}
+
+// brinSizeEstimate estimates a single-column BRIN index with the default
+// pages_per_range of 128: a metapage, range map pages of about 1360 entries,
+// and summary pages holding about 200 min/max tuples, with at least one of
+// each.
+func brinSizeEstimate(tablePages int64) int64 {
+	const pageSize = 8192
+	ranges := max(1, (tablePages+127)/128)
+	pages := 1 + (ranges+1359)/1360 + (ranges+199)/200
+	return pages * pageSize
+}
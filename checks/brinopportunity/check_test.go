@@ -0,0 +1,128 @@
+package brinopportunity_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/brinopportunity"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeQueries struct {
+	candidates    checktest.Result[[]db.BrinCandidatesRow]
+	minIndexBytes *int64
+}
+
+func (f fakeQueries) BrinCandidates(_ context.Context, minIndexBytes int64) ([]db.BrinCandidatesRow, error) {
+	if f.minIndexBytes != nil {
+		*f.minIndexBytes = minIndexBytes
+	}
+	return f.candidates.Get()
+}
+
+// candidate is a 10GB append-only table with a 2GB index on created_at.
+func candidate() db.BrinCandidatesRow {
+	return db.BrinCandidatesRow{
+		SchemaName:     "public",
+		TableName:      "events",
+		IndexName:      "events_created_at_idx",
+		ColumnName:     "created_at",
+		IndexSizeBytes: 2 << 30,
+		TablePages:     1_310_720,
+		Inserts:        50_000_000,
+		Updates:        1_000,
+		Deletes:        0,
+		Correlation:    0.99,
+	}
+}
+
+func run(t *testing.T, rows []db.BrinCandidatesRow, cfg ...check.Config) check.Finding {
+	t.Helper()
+	return checktest.OnlyFinding(t, checktest.Run(t, brinopportunity.New(fakeQueries{candidates: checktest.Returns(rows)}, cfg...)))
+}
+
+func TestBrinOpportunity_Metadata(t *testing.T) {
+	t.Parallel()
+	checktest.RequireMetadata(t, brinopportunity.Metadata(), "brin-opportunity", check.CategoryIndexes)
+}
+
+func TestBrinOpportunity_QueryError(t *testing.T) {
+	t.Parallel()
+	checktest.RequireError(t, brinopportunity.New(fakeQueries{candidates: checktest.Fails[[]db.BrinCandidatesRow](errors.New("boom"))}))
+}
+
+func TestBrinOpportunity_NoCandidates(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, nil)
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "No btree index of 100MB or more on a naturally ordered column of an append-mostly table", finding.Details)
+}
+
+func TestBrinOpportunity_Candidate(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, []db.BrinCandidatesRow{candidate()})
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Contains(t, finding.Details, "Found 1 btree index(es)")
+	assert.Equal(t, [][]string{
+		{"public.events", "created_at", "events_created_at_idx", "2.0GiB", "488.0KiB", "2.0GiB"},
+	}, checktest.Rows(finding))
+	require.NotNil(t, finding.Table)
+	assert.Equal(t, check.SensitiveIdentifier, finding.Table.ColumnSensitivity(2))
+	assert.Equal(t, check.NotSensitive, finding.Table.ColumnSensitivity(3))
+}
+
+func TestBrinOpportunity_Filtered(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		modify func(*db.BrinCandidatesRow)
+	}{
+		{name: "updated too often", modify: func(r *db.BrinCandidatesRow) { r.Updates = 1_000_000 }},
+		{name: "deleted too often", modify: func(r *db.BrinCandidatesRow) { r.Deletes = 1_000_000 }},
+		{name: "no inserts", modify: func(r *db.BrinCandidatesRow) { r.Inserts, r.Updates = 0, 0 }},
+		{name: "column not in row order", modify: func(r *db.BrinCandidatesRow) { r.Correlation = 0.3 }},
+		{name: "no statistics", modify: func(r *db.BrinCandidatesRow) { r.Correlation = 0 }},
+		{name: "btree already small", modify: func(r *db.BrinCandidatesRow) { r.IndexSizeBytes = 64 << 10 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			row := candidate()
+			tt.modify(&row)
+			assert.Equal(t, check.SeverityOK, run(t, []db.BrinCandidatesRow{row}).Severity)
+		})
+	}
+
+	// Descending order works as well for BRIN.
+	row := candidate()
+	row.Correlation = -0.95
+	assert.Equal(t, check.SeverityWarn, run(t, []db.BrinCandidatesRow{row}).Severity)
+}
+
+func TestBrinOpportunity_Config(t *testing.T) {
+	t.Parallel()
+
+	var minIndexBytes int64
+	row := candidate()
+	row.Updates, row.Correlation = 2_000_000, 0.8
+	cfg := check.Config{"brin-opportunity": {"min_index_size_mb": "500", "max_change_ratio": "0.05", "min_correlation": "0.75"}}
+	report := checktest.Run(t, brinopportunity.New(fakeQueries{
+		candidates:    checktest.Returns([]db.BrinCandidatesRow{row}),
+		minIndexBytes: &minIndexBytes,
+	}, cfg))
+	assert.Equal(t, int64(500<<20), minIndexBytes)
+	assert.Equal(t, check.SeverityWarn, checktest.OnlyFinding(t, report).Severity)
+
+	// Invalid values keep the defaults.
+	finding := run(t, []db.BrinCandidatesRow{row}, check.Config{"brin-opportunity": {"max_change_ratio": "-1", "min_correlation": "2"}})
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+}
@@ -0,0 +1,44 @@
+-- name: BrinCandidates :many
+-- Single-column btree indexes of at least min_index_bytes on timestamp, date,
+-- and integer columns, with the table's size in pages, its insert, update,
+-- and delete counts since the statistics were reset, and the column's
+-- physical order correlation (0 without statistics). Unique and partial
+-- indexes are left out: BRIN can replace neither.
+SELECT
+  n.nspname::text AS schema_name
+  , t.relname::text AS table_name
+  , i.relname::text AS index_name
+  , a.attname::text AS column_name
+  , pg_catalog.pg_relation_size(i.oid)::bigint AS index_size_bytes
+  , t.relpages::bigint AS table_pages
+  , coalesce(s.n_tup_ins, 0)::bigint AS inserts
+  , coalesce(s.n_tup_upd, 0)::bigint AS updates
+  , coalesce(s.n_tup_del, 0)::bigint AS deletes
+  , coalesce(st.correlation, 0)::double precision AS correlation
+FROM pg_catalog.pg_index AS idx
+INNER JOIN pg_catalog.pg_class AS i ON idx.indexrelid = i.oid
+INNER JOIN pg_catalog.pg_class AS t ON idx.indrelid = t.oid
+INNER JOIN pg_catalog.pg_namespace AS n ON t.relnamespace = n.oid
+INNER JOIN pg_catalog.pg_am AS am ON i.relam = am.oid
+INNER JOIN pg_catalog.pg_attribute AS a ON a.attrelid = t.oid AND a.attnum = idx.indkey[0]
+INNER JOIN pg_catalog.pg_stat_user_tables AS s ON s.relid = t.oid
+LEFT JOIN LATERAL (
+  -- Partitioned tables only have inherited statistics.
+  SELECT ps.correlation
+  FROM pg_catalog.pg_stats AS ps
+  WHERE ps.schemaname = n.nspname AND ps.tablename = t.relname AND ps.attname = a.attname
+  ORDER BY ps.inherited
+  LIMIT 1
+) AS st ON TRUE
+WHERE
+  am.amname = 'btree'
+  AND t.relkind = 'r'
+  AND idx.indnatts = 1
+  AND NOT idx.indisunique
+  AND idx.indisvalid
+  AND idx.indexprs IS NULL
+  AND idx.indpred IS NULL
+  AND a.atttypid = ANY('{timestamp,timestamptz,date,int2,int4,int8}'::regtype [])
+  AND pg_catalog.pg_relation_size(i.oid) >= sqlc.arg(min_index_bytes)::bigint
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
+ORDER BY pg_catalog.pg_relation_size(i.oid) DESC;
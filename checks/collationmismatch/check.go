@@ -0,0 +1,115 @@
+// Package collationmismatch implements checks for collation versions that changed under existing indexes.
+package collationmismatch
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+// PostgreSQL 13 started recording libc collation versions; earlier releases
+// only record ICU ones.
+const libcVersionsMinMajor = 13
+
+type CollationMismatchQueries interface {
+	PGVersion(context.Context) (db.PGVersionRow, error)
+	CollationVersionMismatches(context.Context) ([]db.CollationVersionMismatchesRow, error)
+}
+
+type checker struct {
+	queries CollationMismatchQueries
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryIndexes,
+		CheckID:     "collation-mismatch",
+		Name:        "Collation Version Mismatch",
+		Description: "Detects collations whose recorded version no longer matches the OS or ICU library, a sign of silently corrupt indexes",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries CollationMismatchQueries, _ ...check.Config) check.Checker {
+	return &checker{
+		queries: queries,
+	}
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	version, err := c.queries.PGVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	collations, err := c.queries.CollationVersionMismatches(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+	checkCollationVersions(collations, version.Major, report)
+
+	return report, nil
+}
+
+func checkCollationVersions(rows []db.CollationVersionMismatchesRow, major int32, report *check.Report) {
+	if len(rows) == 0 {
+		details := "All recorded collation versions match the provider"
+		if major < libcVersionsMinMajor {
+			details += fmt.Sprintf(" (only ICU collations are versioned before PostgreSQL %d)", libcVersionsMinMajor)
+		}
+		report.AddFinding(check.Finding{
+			ID:       "collation-versions",
+			Name:     "Collation Versions",
+			Severity: check.SeverityOK,
+			Details:  details,
+		})
+		return
+	}
+
+	indexes := int64(0)
+	tableRows := make([]check.TableRow, 0, len(rows))
+	for _, row := range rows {
+		indexes += row.IndexCount
+		tableRows = append(tableRows, check.TableRow{
+			Cells: []string{
+				row.CollationName,
+				row.Provider,
+				row.RecordedVersion,
+				row.ActualVersion,
+				check.FormatNumber(row.IndexCount),
+			},
+			Severity: check.SeverityFail,
+		})
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       "collation-versions",
+		Name:     "Collation Versions",
+		Severity: check.SeverityFail,
+		Details: fmt.Sprintf("Found %d collation(s) whose version changed since they were recorded, used by %s index(es); "+
+			"REINDEX those indexes, then ALTER COLLATION ... REFRESH VERSION",
+			len(rows), check.FormatNumber(indexes)),
+		Table: &check.Table{
+			Headers:   []string{"Collation", "Provider", "Recorded", "Actual", "Indexes"},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignLeft, check.AlignLeft, check.AlignLeft, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	})
+}
@@ -0,0 +1,124 @@
+package collationmismatch_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/checks/collationmismatch"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockQueryer struct {
+	major        int32
+	collations   []db.CollationVersionMismatchesRow
+	versionErr   error
+	collationErr error
+}
+
+func (m *mockQueryer) PGVersion(context.Context) (db.PGVersionRow, error) {
+	return db.PGVersionRow{Major: m.major}, m.versionErr
+}
+
+func (m *mockQueryer) CollationVersionMismatches(context.Context) ([]db.CollationVersionMismatchesRow, error) {
+	return m.collations, m.collationErr
+}
+
+func findingByID(t *testing.T, report *check.Report, id string) check.Finding {
+	t.Helper()
+	for _, f := range report.Results {
+		if f.ID == id {
+			return f
+		}
+	}
+	t.Fatalf("finding %q not found", id)
+	return check.Finding{}
+}
+
+func TestCollationMismatch_NoMismatches(t *testing.T) {
+	t.Parallel()
+
+	report, err := collationmismatch.New(&mockQueryer{major: 16}).Check(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, check.SeverityOK, report.Severity)
+	require.Len(t, report.Results, 1)
+	assert.Equal(t, check.SeverityOK, findingByID(t, report, "collation-versions").Severity)
+}
+
+func TestCollationMismatch_CollationVersions(t *testing.T) {
+	t.Parallel()
+
+	report, err := collationmismatch.New(&mockQueryer{
+		major: 16,
+		collations: []db.CollationVersionMismatchesRow{
+			{CollationName: "pg_catalog.en_US", Provider: "libc", RecordedVersion: "2.31", ActualVersion: "2.36", IndexCount: 12},
+			{CollationName: "public.german", Provider: "icu", RecordedVersion: "153.14", ActualVersion: "153.120", IndexCount: 0},
+		},
+	}).Check(context.Background())
+	require.NoError(t, err)
+
+	finding := findingByID(t, report, "collation-versions")
+	assert.Equal(t, check.SeverityFail, finding.Severity)
+	assert.Contains(t, finding.Details, "2 collation(s)")
+	assert.Contains(t, finding.Details, "used by 12 index(es)")
+	assert.Contains(t, finding.Details, "REFRESH VERSION")
+	require.NotNil(t, finding.Table)
+	assert.Equal(t, []string{"pg_catalog.en_US", "libc", "2.31", "2.36", "12"}, finding.Table.Rows[0].Cells)
+}
+
+func TestCollationMismatch_OlderVersions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		major    int32
+		libcNote bool
+	}{
+		{"PG12 versions only ICU", 12, true},
+		{"PG13 versions libc", 13, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			report, err := collationmismatch.New(&mockQueryer{major: tt.major}).Check(context.Background())
+			require.NoError(t, err)
+
+			assert.Equal(t, check.SeverityOK, report.Severity)
+			if tt.libcNote {
+				assert.Contains(t, findingByID(t, report, "collation-versions").Details, "only ICU")
+			} else {
+				assert.NotContains(t, findingByID(t, report, "collation-versions").Details, "only ICU")
+			}
+		})
+	}
+}
+
+func TestCollationMismatch_QueryError(t *testing.T) {
+	t.Parallel()
+
+	for _, queryer := range []*mockQueryer{
+		{versionErr: fmt.Errorf("connection refused")},
+		{major: 16, collationErr: fmt.Errorf("connection refused")},
+	} {
+		_, err := collationmismatch.New(queryer).Check(context.Background())
+		require.ErrorContains(t, err, "collation-mismatch")
+	}
+}
+
+func TestCollationMismatch_Metadata(t *testing.T) {
+	t.Parallel()
+
+	m := collationmismatch.Metadata()
+	assert.Equal(t, "collation-mismatch", m.CheckID)
+	assert.Equal(t, check.CategoryIndexes, m.Category)
+	assert.False(t, m.ClusterWide, "pg_collation is per database, so multi-database runs check each one")
+	assert.NotEmpty(t, m.Name)
+	assert.NotEmpty(t, m.Readme)
+	assert.NotEmpty(t, m.SQL)
+}
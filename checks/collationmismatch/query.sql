@@ -0,0 +1,27 @@
+-- name: CollationVersionMismatches :many
+-- Collations whose recorded version differs from the version the provider
+-- (glibc or ICU) reports now, with the number of indexes that sort by them.
+-- Collations never used by an index are included: new indexes would be built
+-- with the new ordering while queries compare against old data.
+SELECT
+  (n.nspname || '.' || c.collname)::text AS collation_name
+  , CASE c.collprovider
+    WHEN 'c' THEN 'libc'
+    WHEN 'i' THEN 'icu'
+    WHEN 'b' THEN 'builtin'
+    ELSE 'default'
+  END::text AS provider
+  , c.collversion::text AS recorded_version
+  , pg_collation_actual_version(c.oid)::text AS actual_version
+  , (
+    SELECT count(*)
+    FROM pg_index AS i
+    WHERE c.oid = ANY(i.indcollation::oid [])
+  ) AS index_count
+FROM pg_collation AS c
+INNER JOIN pg_namespace AS n ON c.collnamespace = n.oid
+WHERE
+  c.collversion IS NOT NULL
+  AND pg_collation_actual_version(c.oid) IS NOT NULL
+  AND c.collversion <> pg_collation_actual_version(c.oid)
+ORDER BY index_count DESC, collation_name;
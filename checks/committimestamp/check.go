@@ -0,0 +1,95 @@
+// Package committimestamp implements a check for track_commit_timestamp on clusters with logical replication subscriptions.
+package committimestamp
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type CommitTimestampQueries interface {
+	CommitTimestamp(context.Context) (db.CommitTimestampRow, error)
+}
+
+type checker struct {
+	queries CommitTimestampQueries
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryConfigs,
+		CheckID:     "commit-timestamp",
+		Name:        "Commit Timestamp",
+		Description: "Checks that track_commit_timestamp is on when the cluster has logical replication subscriptions, so conflicts can be traced to the commits behind them",
+		Readme:      readme,
+		SQL:         querySQL,
+		ClusterWide: true,
+	}
+}
+
+func New(queries CommitTimestampQueries, _ ...check.Config) check.Checker {
+	return &checker{queries: queries}
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	settings, err := c.queries.CommitTimestamp(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	on := settings.TrackCommitTimestamp == "on"
+	expected, status := "-", "OK"
+	severity := check.SeverityOK
+	var details, reason string
+	switch {
+	case settings.Subscriptions > 0 && !on:
+		expected = "on"
+		status = fmt.Sprintf("Off with %d subscription(s): conflicts cannot be traced to a commit", settings.Subscriptions)
+		severity = check.SeverityWarn
+		reason = fmt.Sprintf("track_commit_timestamp off with %d subscription(s) → WARN", settings.Subscriptions)
+		details = fmt.Sprintf("track_commit_timestamp is off while the cluster has %d logical replication subscription(s) (%d enabled): "+
+			"conflict logs cannot tell which side wrote a row last, and pg_xact_commit_timestamp() has nothing to return. "+
+			"Turning it on takes a restart, and only commits after it are timestamped",
+			settings.Subscriptions, settings.EnabledSubscriptions)
+	case settings.Subscriptions > 0:
+		expected = "on"
+		details = fmt.Sprintf("Commit timestamps are tracked for conflict diagnosis on the cluster's %d logical replication subscription(s)", settings.Subscriptions)
+	case on:
+		details = "Commit timestamps are tracked; the cluster has no logical replication subscriptions"
+	default:
+		status = "Off: no subscriptions need it"
+		details = "Commit timestamps are not tracked; the cluster has no logical replication subscriptions that would need them"
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: severity,
+		Details:  details,
+		Reason:   reason,
+		Table: &check.Table{
+			Headers: []string{"Parameter", "Current", "Expected", "Status"},
+			Rows: []check.TableRow{{
+				Cells:    []string{"track_commit_timestamp", settings.TrackCommitTimestamp, expected, status},
+				Severity: severity,
+			}},
+		},
+	})
+
+	return report, nil
+}
@@ -0,0 +1,76 @@
+package committimestamp_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/emancu/pgdoctor"
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/committimestamp"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeQueries struct {
+	settings checktest.Result[db.CommitTimestampRow]
+}
+
+func (f fakeQueries) CommitTimestamp(context.Context) (db.CommitTimestampRow, error) {
+	return f.settings.Get()
+}
+
+func run(t *testing.T, row db.CommitTimestampRow) check.Finding {
+	t.Helper()
+	return checktest.OnlyFinding(t, checktest.Run(t, committimestamp.New(fakeQueries{settings: checktest.Returns(row)})))
+}
+
+func TestCommitTimestamp_Metadata(t *testing.T) {
+	t.Parallel()
+	checktest.RequireMetadata(t, committimestamp.Metadata(), "commit-timestamp", check.CategoryConfigs)
+
+	// The setting and pg_subscription are shared, so --all-databases reads
+	// them once.
+	clusterWide, perDatabase := pgdoctor.SplitClusterWide([]check.Package{{Metadata: committimestamp.Metadata}})
+	assert.Len(t, clusterWide, 1)
+	assert.Empty(t, perDatabase)
+}
+
+func TestCommitTimestamp_QueryError(t *testing.T) {
+	t.Parallel()
+	checktest.RequireError(t, committimestamp.New(fakeQueries{settings: checktest.Fails[db.CommitTimestampRow](errors.New("boom"))}))
+}
+
+func TestCommitTimestamp_OffWithSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, db.CommitTimestampRow{TrackCommitTimestamp: "off", Subscriptions: 2, EnabledSubscriptions: 1})
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Contains(t, finding.Details, "2 logical replication subscription(s) (1 enabled)")
+	assert.Equal(t, "track_commit_timestamp off with 2 subscription(s) → WARN", finding.Reason)
+	assert.Equal(t, [][]string{
+		{"track_commit_timestamp", "off", "on", "Off with 2 subscription(s): conflicts cannot be traced to a commit"},
+	}, checktest.Rows(finding))
+}
+
+func TestCommitTimestamp_OnWithSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, db.CommitTimestampRow{TrackCommitTimestamp: "on", Subscriptions: 1, EnabledSubscriptions: 1})
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Empty(t, finding.Reason)
+	assert.Equal(t, [][]string{{"track_commit_timestamp", "on", "on", "OK"}}, checktest.Rows(finding))
+}
+
+func TestCommitTimestamp_NoSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, db.CommitTimestampRow{TrackCommitTimestamp: "off"})
+	assert.Equal(t, check.SeverityOK, finding.Severity, "without subscriptions the setting is informational")
+	assert.Equal(t, [][]string{{"track_commit_timestamp", "off", "-", "Off: no subscriptions need it"}}, checktest.Rows(finding))
+
+	finding = run(t, db.CommitTimestampRow{TrackCommitTimestamp: "on"})
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "Commit timestamps are tracked; the cluster has no logical replication subscriptions", finding.Details)
+}
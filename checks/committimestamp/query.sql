@@ -0,0 +1,8 @@
+-- name: CommitTimestamp :one
+-- track_commit_timestamp and the logical replication subscriptions on the
+-- cluster. The setting is cluster-wide and pg_subscription is shared, so
+-- subscriptions in every database count.
+SELECT
+  current_setting('track_commit_timestamp')::text AS track_commit_timestamp
+  , (SELECT count(*)::int FROM pg_catalog.pg_subscription) AS subscriptions
+  , (SELECT count(*)::int FROM pg_catalog.pg_subscription WHERE subenabled) AS enabled_subscriptions;
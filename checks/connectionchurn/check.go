@@ -0,0 +1,203 @@
+// Package connectionchurn implements a check for clients opening a new connection per query instead of pooling.
+package connectionchurn
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type ConnectionChurnQueries interface {
+	SessionChurn(context.Context) ([]db.SessionChurnRow, error)
+	RecentBackends(context.Context) (db.RecentBackendsRow, error)
+}
+
+type checker struct {
+	queries      ConnectionChurnQueries
+	maxPerSecond float64 // new connections per second; default: 10
+}
+
+// sampleSeconds is the window of the RecentBackends sample.
+const sampleSeconds = 60
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryConfigs,
+		CheckID:     "connection-churn",
+		Name:        "Connection Churn",
+		Description: "Estimates new connections per second to detect clients opening a connection per query instead of pooling",
+		Readme:      readme,
+		SQL:         querySQL,
+		ClusterWide: true,
+	}
+}
+
+func New(queries ConnectionChurnQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries:      queries,
+		maxPerSecond: 10,
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			if v, ok := myCfg["max_connections_per_second"]; ok {
+				if n, err := strconv.ParseFloat(v, 64); err == nil && n > 0 {
+					c.maxPerSecond = n
+				}
+			}
+		}
+	}
+	return c
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	// Session statistics count every connection since the reset; older
+	// servers only show the connections open now.
+	meta := check.InstanceMetadataFromContext(ctx)
+	if meta == nil || meta.EngineVersionMajor < 14 {
+		return c.checkSample(ctx, report, "PostgreSQL 13 and older have no session statistics")
+	}
+
+	rows, err := c.queries.SessionChurn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	var rate float64
+	var sessions, transactions int64
+	var sessionTimeMs float64
+	var tableRows []check.TableRow
+	var reason string
+	var notes []string
+	for _, row := range rows {
+		if row.Sessions == 0 {
+			continue
+		}
+		if note := check.StatsWindowNote(row.SecondsSinceReset); note != "" {
+			notes = append(notes, row.DatabaseName+": "+note)
+		}
+		dbRate := float64(row.Sessions) / check.StatsWindow(row.SecondsSinceReset, row.UptimeSeconds)
+		rate += dbRate
+		sessions += row.Sessions
+		transactions += row.Transactions
+		sessionTimeMs += row.SessionTimeMs
+		if dbRate < c.maxPerSecond {
+			continue
+		}
+		if reason == "" {
+			reason = fmt.Sprintf("%s: %.1f connections/s ≥ max_connections_per_second %s → WARN", row.DatabaseName, dbRate, formatRate(c.maxPerSecond))
+		}
+		tableRows = append(tableRows, check.TableRow{
+			Cells: []string{
+				row.DatabaseName,
+				check.FormatNumber(row.Sessions),
+				fmt.Sprintf("%.1f", dbRate),
+				fmt.Sprintf("%.1f", float64(row.Transactions)/float64(row.Sessions)),
+				check.FormatDurationMs(row.SessionTimeMs / float64(row.Sessions)),
+			},
+			Severity: check.SeverityWarn,
+		})
+	}
+
+	if sessions == 0 {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  "No sessions counted since the statistics were reset",
+		})
+		return report, nil
+	}
+
+	perConnection := fmt.Sprintf("averaging %.1f transaction(s) and %s per connection",
+		float64(transactions)/float64(sessions), check.FormatDurationMs(sessionTimeMs/float64(sessions)))
+	var estimated string
+	if len(notes) > 0 {
+		estimated = ". Rates are estimates for " + strings.Join(notes, "; ")
+	}
+	if len(tableRows) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details: fmt.Sprintf("About %.1f new connection(s) per second across the cluster, %s (from pg_stat_database.sessions since the statistics were reset)%s",
+				rate, perConnection, estimated),
+		})
+		return report, nil
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: check.SeverityWarn,
+		Details: fmt.Sprintf("About %.1f new connection(s) per second across the cluster, %s: clients likely open a connection per query instead of pooling. "+
+			"Each connection forks a backend and authenticates; route them through a pooler such as PgBouncer or the driver's pool. "+
+			"Estimated from pg_stat_database.sessions over the time since each database's statistics were reset%s",
+			rate, perConnection, estimated),
+		Reason: reason,
+		Table: &check.Table{
+			Headers:   []string{"Database", "Connections", "Per Second", "Xacts/Conn", "Avg Session"},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignRight, check.AlignRight, check.AlignRight, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	})
+
+	return report, nil
+}
+
+// checkSample estimates the rate from the client backends that connected in
+// the last sampleSeconds. Connections that already closed are not counted, so
+// it is a lower bound: a client connecting per query is caught only by the
+// connections open at that moment. why says why session statistics were not
+// used.
+func (c *checker) checkSample(ctx context.Context, report *check.Report, why string) (*check.Report, error) {
+	sample, err := c.queries.RecentBackends(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	rate := float64(sample.RecentBackends) / sampleSeconds
+	if rate < c.maxPerSecond {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details: fmt.Sprintf("%d of %d client connection(s) opened in the last minute (%s, so only open connections are sampled)",
+				sample.RecentBackends, sample.ClientBackends, why),
+		})
+		return report, nil
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: check.SeverityWarn,
+		Details: fmt.Sprintf("At least %.1f new connection(s) per second: %d of %d client connection(s) opened in the last minute, "+
+			"so clients likely open a connection per query instead of pooling; route them through a pooler such as PgBouncer or the driver's pool. "+
+			"Estimated from pg_stat_activity.backend_start, which misses connections that already closed",
+			rate, sample.RecentBackends, sample.ClientBackends),
+		Reason: fmt.Sprintf("%.1f connections/s ≥ max_connections_per_second %s → WARN", rate, formatRate(c.maxPerSecond)),
+	})
+	return report, nil
+}
+
+func formatRate(rate float64) string {
+	return strconv.FormatFloat(rate, 'f', -1, 64)
+}
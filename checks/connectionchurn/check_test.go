@@ -0,0 +1,148 @@
+package connectionchurn_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/connectionchurn"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeQueries struct {
+	churn  checktest.Result[[]db.SessionChurnRow]
+	sample checktest.Result[db.RecentBackendsRow]
+}
+
+func (f fakeQueries) SessionChurn(context.Context) ([]db.SessionChurnRow, error) {
+	return f.churn.Get()
+}
+
+func (f fakeQueries) RecentBackends(context.Context) (db.RecentBackendsRow, error) {
+	return f.sample.Get()
+}
+
+func pg(major int) context.Context {
+	return check.ContextWithInstanceMetadata(context.Background(), &check.InstanceMetadata{EngineVersionMajor: major})
+}
+
+func run(t *testing.T, queries fakeQueries, cfg ...check.Config) check.Finding {
+	t.Helper()
+	report, err := connectionchurn.New(queries, cfg...).Check(pg(16))
+	require.NoError(t, err)
+	return checktest.OnlyFinding(t, report)
+}
+
+func churn(rows ...db.SessionChurnRow) fakeQueries {
+	return fakeQueries{churn: checktest.Returns(rows)}
+}
+
+var hour = pgtype.Int8{Int64: 3600, Valid: true}
+
+// An hour of statistics: 72,000 sessions are 20 per second.
+func perQuery() db.SessionChurnRow {
+	return db.SessionChurnRow{DatabaseName: "app", Sessions: 72_000, Transactions: 79_200, SessionTimeMs: 288_000, SecondsSinceReset: hour}
+}
+
+func pooled() db.SessionChurnRow {
+	return db.SessionChurnRow{DatabaseName: "reports", Sessions: 360, Transactions: 360_000, SessionTimeMs: 3_600_000, SecondsSinceReset: hour}
+}
+
+func TestConnectionChurn_Metadata(t *testing.T) {
+	t.Parallel()
+	checktest.RequireMetadata(t, connectionchurn.Metadata(), "connection-churn", check.CategoryConfigs)
+}
+
+func TestConnectionChurn_QueryErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := connectionchurn.New(fakeQueries{churn: checktest.Fails[[]db.SessionChurnRow](errors.New("boom"))}).Check(pg(16))
+	require.ErrorContains(t, err, "configs/connection-churn")
+
+	checktest.RequireError(t, connectionchurn.New(fakeQueries{sample: checktest.Fails[db.RecentBackendsRow](errors.New("boom"))}))
+}
+
+func TestConnectionChurn_ConnectionPerQuery(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, churn(perQuery(), pooled()))
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Contains(t, finding.Details, "About 20.1 new connection(s) per second across the cluster, averaging 6.1 transaction(s) and 54ms per connection: "+
+		"clients likely open a connection per query instead of pooling")
+	assert.Equal(t, "app: 20.0 connections/s ≥ max_connections_per_second 10 → WARN", finding.Reason)
+	assert.Equal(t, [][]string{{"app", "72.0K", "20.0", "1.1", "4ms"}}, checktest.Rows(finding))
+}
+
+func TestConnectionChurn_Pooled(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, churn(pooled()))
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "About 0.1 new connection(s) per second across the cluster, averaging 1000.0 transaction(s) and 10.0s per connection "+
+		"(from pg_stat_database.sessions since the statistics were reset)", finding.Details)
+	assert.Nil(t, finding.Table)
+}
+
+func TestConnectionChurn_NoSessions(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, churn(db.SessionChurnRow{DatabaseName: "app", SecondsSinceReset: pgtype.Int8{Int64: 10, Valid: true}}))
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "No sessions counted since the statistics were reset", finding.Details)
+}
+
+func TestConnectionChurn_StatsNeverReset(t *testing.T) {
+	t.Parallel()
+
+	// Sessions counted since an unknown time are rated over the server's
+	// uptime, with a note: 72,000 sessions in 4 hours are 5 per second.
+	neverReset := perQuery()
+	neverReset.SecondsSinceReset = pgtype.Int8{}
+	neverReset.UptimeSeconds = 4 * 3600
+
+	finding := run(t, churn(neverReset, pooled()))
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "About 5.1 new connection(s) per second across the cluster, averaging 6.1 transaction(s) and 54ms per connection "+
+		"(from pg_stat_database.sessions since the statistics were reset). "+
+		"Rates are estimates for app: statistics were never reset, so the counters are rated over the server's uptime and may overstate the rates; "+
+		"pg_stat_reset() starts a window to rate them over", finding.Details)
+
+	// An uptime that short still puts the database over the limit.
+	neverReset.UptimeSeconds = 3600
+	finding = run(t, churn(neverReset))
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Contains(t, finding.Details, "Rates are estimates for app: statistics were never reset")
+}
+
+func TestConnectionChurn_MaxConnectionsPerSecond(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, churn(perQuery()), check.Config{"connection-churn": {"max_connections_per_second": "50"}})
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+
+	// Invalid values keep the default.
+	finding = run(t, churn(perQuery()), check.Config{"connection-churn": {"max_connections_per_second": "-1"}})
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+}
+
+func TestConnectionChurn_SampleBeforePG14(t *testing.T) {
+	t.Parallel()
+
+	queries := fakeQueries{sample: checktest.Returns(db.RecentBackendsRow{ClientBackends: 900, RecentBackends: 720})}
+	report, err := connectionchurn.New(queries).Check(pg(13))
+	require.NoError(t, err)
+	finding := checktest.OnlyFinding(t, report)
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Contains(t, finding.Details, "At least 12.0 new connection(s) per second: 720 of 900 client connection(s) opened in the last minute")
+	assert.Equal(t, "12.0 connections/s ≥ max_connections_per_second 10 → WARN", finding.Reason)
+
+	queries = fakeQueries{sample: checktest.Returns(db.RecentBackendsRow{ClientBackends: 50, RecentBackends: 3})}
+	finding = checktest.OnlyFinding(t, checktest.Run(t, connectionchurn.New(queries)))
+	assert.Equal(t, check.SeverityOK, finding.Severity, "without version metadata the sample is used")
+	assert.Contains(t, finding.Details, "3 of 50 client connection(s) opened in the last minute")
+}
@@ -0,0 +1,26 @@
+-- name: SessionChurn :many
+-- Sessions opened in each database since its statistics were reset
+-- (PostgreSQL 14+), with the transactions and session time they ran, the
+-- seconds since the reset, NULL when the statistics were never reset, and the
+-- server's uptime.
+SELECT
+  d.datname::text AS database_name
+  , d.sessions::bigint AS sessions
+  , (d.xact_commit + d.xact_rollback)::bigint AS transactions
+  , coalesce(d.session_time, 0)::double precision AS session_time_ms
+  , extract(EPOCH FROM now() - d.stats_reset)::bigint AS seconds_since_reset
+  , extract(EPOCH FROM now() - pg_postmaster_start_time())::bigint AS uptime_seconds
+FROM pg_catalog.pg_stat_database AS d
+WHERE
+  d.datname IS NOT NULL
+  AND d.datname NOT IN ('template0', 'template1')
+ORDER BY d.sessions DESC, d.datname;
+
+-- name: RecentBackends :one
+-- Client backends now connected, and how many of them connected within the
+-- last minute: the fallback sample for servers without session statistics.
+SELECT
+  count(*)::bigint AS client_backends
+  , count(*) FILTER (WHERE a.backend_start > now() - interval '60 seconds')::bigint AS recent_backends
+FROM pg_catalog.pg_stat_activity AS a
+WHERE a.backend_type = 'client backend';
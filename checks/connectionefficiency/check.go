@@ -44,6 +44,7 @@ func Metadata() check.Metadata {
 		Description: "Analyzes PostgreSQL 14+ session statistics for connection pool efficiency",
 		Readme:      readme,
 		SQL:         querySQL,
+		ClusterWide: true,
 	}
 }
 
@@ -60,6 +60,7 @@ func Metadata() check.Metadata {
 		Description: "Monitors connection pool saturation, idle ratios, and stuck transactions",
 		Readme:      readme,
 		SQL:         querySQL,
+		ClusterWide: true,
 	}
 }
 
@@ -245,6 +246,7 @@ func checkIdleInTransaction(rows []db.IdleInTransactionRow, report *check.Report
 	if len(rows) == 0 {
 		report.AddFinding(check.Finding{
 			ID:       "idle-in-transaction",
+			Tags:     []string{check.TagXminHorizon},
 			Name:     "Idle In Transaction",
 			Severity: check.SeverityOK,
 			Details:  "No connections stuck in 'idle in transaction' state",
@@ -271,6 +273,7 @@ func checkIdleInTransaction(rows []db.IdleInTransactionRow, report *check.Report
 	if len(problematic) == 0 {
 		report.AddFinding(check.Finding{
 			ID:       "idle-in-transaction",
+			Tags:     []string{check.TagXminHorizon},
 			Name:     "Idle In Transaction",
 			Severity: check.SeverityOK,
 			Details:  "No connections stuck in 'idle in transaction' state",
@@ -303,12 +306,15 @@ func checkIdleInTransaction(rows []db.IdleInTransactionRow, report *check.Report
 
 	report.AddFinding(check.Finding{
 		ID:       "idle-in-transaction",
+		Tags:     []string{check.TagXminHorizon},
 		Name:     "Idle In Transaction",
 		Severity: severity,
 		Details:  fmt.Sprintf("Found %d connection(s) stuck in 'idle in transaction' state", len(problematic)),
 		Table: &check.Table{
-			Headers: []string{"PID", "User", "Database", "Duration", "Query"},
-			Rows:    tableRows,
+			Headers:   []string{"PID", "User", "Database", "Duration", "Query"},
+			Align:     []check.ColumnAlign{check.AlignRight, check.AlignLeft, check.AlignLeft, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.NotSensitive, check.SensitiveIdentifier, check.SensitiveIdentifier, check.NotSensitive, check.SensitiveQuery},
+			Rows:      tableRows,
 		},
 	})
 }
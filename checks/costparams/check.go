@@ -0,0 +1,184 @@
+// Package costparams implements a check for planner I/O cost settings that do not match the storage.
+package costparams
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+// storage holds the recommended cost settings for one kind of storage, and
+// the bounds past which the current values are flagged.
+type storage struct {
+	randomPageCost    float64 // recommended
+	maxRandomPageCost float64 // warn above, unless 0
+	minRandomPageCost float64 // warn below
+	ioConcurrency     int32   // recommended
+	minIOConcurrency  int32   // warn below
+}
+
+// storages are the values of the storage config key. SQL cannot tell what
+// the data directory sits on, so the user says.
+var storages = map[string]storage{
+	// SSDs, NVMe, and cloud block storage: random reads cost about the
+	// same as sequential ones, and many can be in flight.
+	"ssd": {randomPageCost: 1.1, maxRandomPageCost: 2, ioConcurrency: 200, minIOConcurrency: 100},
+	// Spinning disks, which the PostgreSQL defaults assume.
+	"hdd": {randomPageCost: 4, minRandomPageCost: 2, ioConcurrency: 2},
+}
+
+type CostParamsQueries interface {
+	CostParams(context.Context) (db.CostParamsRow, error)
+}
+
+type checker struct {
+	queries CostParamsQueries
+	storage string // "" when not configured
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryConfigs,
+		CheckID:     "cost-params",
+		Name:        "Planner Cost Parameters",
+		Description: "Compares random_page_cost, seq_page_cost, and effective_io_concurrency with the values recommended for the configured storage type",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries CostParamsQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries: queries,
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			if v, ok := myCfg["storage"]; ok {
+				v = strings.ToLower(strings.TrimSpace(v))
+				if _, known := storages[v]; known {
+					c.storage = v
+				}
+			}
+		}
+	}
+	return c
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	params, err := c.queries.CostParams(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	randomPage := row("random_page_cost", formatCost(params.RandomPageCost))
+	seqPage := row("seq_page_cost", formatCost(params.SeqPageCost))
+	ioConcurrency := row("effective_io_concurrency", strconv.Itoa(int(params.EffectiveIoConcurrency)))
+
+	s, configured := storages[c.storage]
+	if configured {
+		label := strings.ToUpper(c.storage)
+		randomPage.expected = formatCost(s.randomPageCost)
+		switch {
+		case s.maxRandomPageCost > 0 && params.RandomPageCost > s.maxRandomPageCost:
+			randomPage.warn(fmt.Sprintf("Above %s for %s", formatCost(s.maxRandomPageCost), label))
+		case params.RandomPageCost < s.minRandomPageCost:
+			randomPage.warn(fmt.Sprintf("Below %s for %s", formatCost(s.minRandomPageCost), label))
+		}
+		seqPage.expected = "1"
+		ioConcurrency.expected = strconv.Itoa(int(s.ioConcurrency))
+		if params.EffectiveIoConcurrency < s.minIOConcurrency {
+			ioConcurrency.warn(fmt.Sprintf("Below %d for %s", s.minIOConcurrency, label))
+		}
+	}
+
+	// Whatever the storage, random reads are never cheaper than sequential
+	// ones; below seq_page_cost the planner prefers index scans everywhere.
+	if params.RandomPageCost < params.SeqPageCost {
+		if !configured {
+			randomPage.expected = "≥ seq_page_cost"
+		}
+		randomPage.warn("Below seq_page_cost")
+	}
+
+	rows := []*settingRow{randomPage, seqPage, ioConcurrency}
+	severity := check.SeverityOK
+	var issues int
+	tableRows := make([]check.TableRow, 0, len(rows))
+	for _, r := range rows {
+		if r.severity > severity {
+			severity = r.severity
+		}
+		if r.severity != check.SeverityOK {
+			issues++
+		}
+		tableRows = append(tableRows, check.TableRow{
+			Cells:    []string{r.parameter, r.current, r.expected, r.status},
+			Severity: r.severity,
+		})
+	}
+
+	var details string
+	switch {
+	case !configured:
+		details = "Storage type not configured; set storage (ssd or hdd) in the cost-params config to compare these with recommended values"
+	case issues == 0:
+		details = fmt.Sprintf("Cost settings suit %s storage", strings.ToUpper(c.storage))
+	default:
+		details = fmt.Sprintf("%d cost setting(s) do not suit %s storage; the planner misjudges index scans against sequential scans",
+			issues, strings.ToUpper(c.storage))
+	}
+	if !configured && issues > 0 {
+		details = "random_page_cost is below seq_page_cost. " + details
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: severity,
+		Details:  details,
+		Table: &check.Table{
+			Headers: []string{"Parameter", "Current", "Expected", "Status"},
+			Rows:    tableRows,
+		},
+	})
+
+	return report, nil
+}
+
+// settingRow is one row of the report table.
+type settingRow struct {
+	parameter string
+	current   string
+	expected  string
+	status    string
+	severity  check.Severity
+}
+
+func row(parameter, current string) *settingRow {
+	return &settingRow{parameter: parameter, current: current, expected: "-", status: "OK", severity: check.SeverityOK}
+}
+
+func (r *settingRow) warn(status string) {
+	r.status, r.severity = status, check.SeverityWarn
+}
+
+func formatCost(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,115 @@
+package costparams_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/costparams"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeQueries struct {
+	params checktest.Result[db.CostParamsRow]
+}
+
+func (f fakeQueries) CostParams(context.Context) (db.CostParamsRow, error) {
+	return f.params.Get()
+}
+
+// defaults are the PostgreSQL 17 defaults.
+var defaults = db.CostParamsRow{RandomPageCost: 4, SeqPageCost: 1, EffectiveIoConcurrency: 1}
+
+func newChecker(params db.CostParamsRow, storage string) check.Checker {
+	var cfg check.Config
+	if storage != "" {
+		cfg = check.Config{"cost-params": {"storage": storage}}
+	}
+	return costparams.New(fakeQueries{params: checktest.Returns(params)}, cfg)
+}
+
+func TestCostParams_Metadata(t *testing.T) {
+	t.Parallel()
+	checktest.RequireMetadata(t, costparams.Metadata(), "cost-params", check.CategoryConfigs)
+}
+
+func TestCostParams_QueryError(t *testing.T) {
+	t.Parallel()
+	checktest.RequireError(t, costparams.New(fakeQueries{params: checktest.Fails[db.CostParamsRow](errors.New("boom"))}))
+}
+
+func TestCostParams_DefaultsOnSSD(t *testing.T) {
+	t.Parallel()
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(defaults, "ssd")))
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Equal(t, "2 cost setting(s) do not suit SSD storage; the planner misjudges index scans against sequential scans", finding.Details)
+	assert.Equal(t, [][]string{
+		{"random_page_cost", "4", "1.1", "Above 2 for SSD"},
+		{"seq_page_cost", "1", "1", "OK"},
+		{"effective_io_concurrency", "1", "200", "Below 100 for SSD"},
+	}, checktest.Rows(finding))
+}
+
+func TestCostParams_Thresholds(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		storage       string
+		randomPage    float64
+		ioConcurrency int32
+		expected      check.Severity
+	}{
+		{"tuned SSD", "ssd", 1.1, 200, check.SeverityOK},
+		{"SSD at the bound", "ssd", 2, 100, check.SeverityOK},
+		{"SSD with default concurrency", "ssd", 1.1, 16, check.SeverityWarn},
+		{"HDD defaults", "hdd", 4, 1, check.SeverityOK},
+		{"HDD tuned for SSD", "hdd", 1.1, 200, check.SeverityWarn},
+		{"storage is case-insensitive", " SSD ", 1.1, 200, check.SeverityOK},
+		{"not configured", "", 4, 1, check.SeverityOK},
+		{"unknown storage is ignored", "tape", 4, 1, check.SeverityOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			params := db.CostParamsRow{RandomPageCost: tt.randomPage, SeqPageCost: 1, EffectiveIoConcurrency: tt.ioConcurrency}
+			finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(params, tt.storage)))
+			assert.Equal(t, tt.expected, finding.Severity)
+		})
+	}
+}
+
+func TestCostParams_NotConfigured(t *testing.T) {
+	t.Parallel()
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(defaults, "")))
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Contains(t, finding.Details, "Storage type not configured")
+	assert.Equal(t, [][]string{
+		{"random_page_cost", "4", "-", "OK"},
+		{"seq_page_cost", "1", "-", "OK"},
+		{"effective_io_concurrency", "1", "-", "OK"},
+	}, checktest.Rows(finding), "current values are listed without recommendations")
+}
+
+func TestCostParams_RandomBelowSequential(t *testing.T) {
+	t.Parallel()
+
+	params := db.CostParamsRow{RandomPageCost: 0.5, SeqPageCost: 1, EffectiveIoConcurrency: 200}
+
+	for _, storage := range []string{"", "ssd"} {
+		finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(params, storage)))
+		assert.Equal(t, check.SeverityWarn, finding.Severity, "storage %q", storage)
+		assert.Equal(t, "Below seq_page_cost", checktest.Rows(finding)[0][3], "storage %q", storage)
+	}
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(params, "")))
+	assert.Equal(t, "≥ seq_page_cost", checktest.Rows(finding)[0][2])
+	assert.Contains(t, finding.Details, "random_page_cost is below seq_page_cost")
+}
@@ -0,0 +1,7 @@
+-- name: CostParams :one
+-- The planner's I/O cost settings as the current session sees them, so
+-- ALTER DATABASE and ALTER ROLE overrides are included.
+SELECT
+  current_setting('random_page_cost')::float8 AS random_page_cost
+  , current_setting('seq_page_cost')::float8 AS seq_page_cost
+  , current_setting('effective_io_concurrency')::int AS effective_io_concurrency;
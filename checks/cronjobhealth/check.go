@@ -0,0 +1,326 @@
+// Package cronjobhealth implements a check for failed and missed pg_cron jobs.
+package cronjobhealth
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type CronJobHealthQueries interface {
+	HasPgCron(context.Context) (bool, error)
+	CronClock(context.Context) (db.CronClockRow, error)
+	CronJobs(context.Context, int32) ([]db.CronJobsRow, error)
+}
+
+type checker struct {
+	queries     CronJobHealthQueries
+	windowHours int32         // default: 24
+	grace       time.Duration // default: 10m
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryConfigs,
+		CheckID:     "cron-job-health",
+		Name:        "pg_cron Job Health",
+		Description: "Reports pg_cron jobs that failed recently or did not start when their schedule said they should",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries CronJobHealthQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries:     queries,
+		windowHours: 24,
+		grace:       10 * time.Minute,
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			if v, ok := myCfg["window_hours"]; ok {
+				if n, err := strconv.ParseInt(v, 10, 32); err == nil && n > 0 {
+					c.windowHours = int32(n)
+				}
+			}
+			if v, ok := myCfg["grace_minutes"]; ok {
+				if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+					c.grace = time.Duration(n) * time.Minute
+				}
+			}
+		}
+	}
+	return c
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	installed, err := c.queries.HasPgCron(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+	if !installed {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityNotApplicable,
+			Details:  "pg_cron is not installed in this database; its jobs live in the database named by cron.database_name",
+		})
+		return report, nil
+	}
+
+	clock, err := c.queries.CronClock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+	jobs, err := c.queries.CronJobs(ctx, c.windowHours)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	// pg_cron evaluates schedules in cron.timezone, GMT before pg_cron 1.5.
+	loc := time.UTC
+	if clock.Timezone.Valid && clock.Timezone.String != "" {
+		if l, err := time.LoadLocation(clock.Timezone.String); err == nil {
+			loc = l
+		}
+	}
+	now := clock.Now.Time.In(loc)
+
+	active, failed, missed := 0, 0, 0
+	var tableRows []check.TableRow
+	for _, job := range jobs {
+		if !job.Active {
+			continue
+		}
+		active++
+
+		var issues []string
+		severity := check.SeverityOK
+		if job.RecentFailures > 0 {
+			failed++
+			severity = check.SeverityFail
+			issues = append(issues, fmt.Sprintf("%s failure(s) in %dh", check.FormatNumber(job.RecentFailures), c.windowHours))
+		}
+		// Jobs with no recorded run are not judged: they may be new, or
+		// cron.log_run may be off.
+		if job.LastStartTime.Valid {
+			if due, ok := dueRun(job.Schedule, now.Add(-c.grace)); ok && job.LastStartTime.Time.Before(due) {
+				missed++
+				if severity < check.SeverityWarn {
+					severity = check.SeverityWarn
+				}
+				issues = append(issues, fmt.Sprintf("run due %s ago did not start", check.FormatDurationSec(int64(now.Sub(due).Seconds()))))
+			}
+		}
+		if severity == check.SeverityOK {
+			continue
+		}
+
+		lastRun := "never"
+		if job.LastStartTime.Valid {
+			lastRun = check.FormatDurationSec(int64(now.Sub(job.LastStartTime.Time).Seconds())) + " ago"
+		}
+		lastStatus := "-"
+		if job.LastStatus.Valid {
+			lastStatus = job.LastStatus.String
+		}
+		lastError := "-"
+		if job.LastError.Valid && job.LastError.String != "" {
+			lastError, _, _ = strings.Cut(job.LastError.String, "\n")
+		}
+		tableRows = append(tableRows, check.TableRow{
+			Cells: []string{
+				job.JobName,
+				job.DatabaseName,
+				job.Schedule,
+				lastStatus,
+				lastRun,
+				strings.Join(issues, "; "),
+				lastError,
+			},
+			Severity: severity,
+		})
+	}
+
+	if len(tableRows) == 0 {
+		details := fmt.Sprintf("%d active job(s); none failed in the last %dh or missed a scheduled run", active, c.windowHours)
+		if active == 0 {
+			details = "pg_cron is installed but no active jobs are scheduled"
+		}
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  details,
+		})
+		return report, nil
+	}
+
+	severity := check.SeverityWarn
+	var summary []string
+	if failed > 0 {
+		severity = check.SeverityFail
+		summary = append(summary, fmt.Sprintf("%d job(s) failed in the last %dh", failed, c.windowHours))
+	}
+	if missed > 0 {
+		summary = append(summary, fmt.Sprintf("%d job(s) did not start when scheduled", missed))
+	}
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: severity,
+		Details:  strings.Join(summary, "; ") + "; see cron.job_run_details for each run",
+		Table: &check.Table{
+			Headers:   []string{"Job", "Database", "Schedule", "Last Status", "Last Run", "Issue", "Last Error"},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.SensitiveIdentifier, check.NotSensitive, check.NotSensitive, check.NotSensitive, check.NotSensitive, check.SensitiveQuery},
+			Rows:      tableRows,
+		},
+	})
+
+	return report, nil
+}
+
+// intervalSchedule matches pg_cron's "N seconds" schedules.
+var intervalSchedule = regexp.MustCompile(`^([1-9][0-9]?) seconds?$`)
+
+var scheduleMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var (
+	monthNames = map[string]int{"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6, "jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12}
+	dayNames   = map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}
+)
+
+// dueRun returns the latest time at or before t the schedule should have
+// started a run. ok is false for schedules it cannot evaluate (@reboot, the
+// last-day-of-month "$", ...) or that did not fire in the past year.
+func dueRun(spec string, t time.Time) (due time.Time, ok bool) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	if m := intervalSchedule.FindStringSubmatch(spec); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return t.Add(-time.Duration(n) * time.Second), true
+	}
+	if macro, ok := scheduleMacros[spec]; ok {
+		spec = macro
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return time.Time{}, false
+	}
+	minute, _, ok1 := parseField(fields[0], 0, 59, nil)
+	hour, _, ok2 := parseField(fields[1], 0, 23, nil)
+	dom, domStar, ok3 := parseField(fields[2], 1, 31, nil)
+	month, _, ok4 := parseField(fields[3], 1, 12, monthNames)
+	dow, dowStar, ok5 := parseField(fields[4], 0, 7, dayNames)
+	if !ok1 || !ok2 || !ok3 || !ok4 || !ok5 {
+		return time.Time{}, false
+	}
+	if dow&(1<<7) != 0 {
+		dow |= 1 // 7 is Sunday too
+	}
+
+	// As in cron, a restricted day of month and day of week match either.
+	dayMatches := func(t time.Time) bool {
+		d, w := dom&(1<<t.Day()) != 0, dow&(1<<int(t.Weekday())) != 0
+		if domStar || dowStar {
+			return d && w
+		}
+		return d || w
+	}
+
+	loc := t.Location()
+	limit := t.AddDate(-1, 0, -1)
+	t = t.Truncate(time.Minute)
+	for t.After(limit) {
+		switch {
+		case month&(1<<int(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc).Add(-time.Minute)
+		case !dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc).Add(-time.Minute)
+		case hour&(1<<t.Hour()) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc).Add(-time.Minute)
+		case minute&(1<<t.Minute()) == 0:
+			t = t.Add(-time.Minute)
+		default:
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseField parses one cron field into a bit set of allowed values. star
+// reports whether the field starts with "*", which changes how the two day
+// fields combine.
+func parseField(field string, lo, hi int, names map[string]int) (set uint64, star bool, ok bool) {
+	value := func(s string) (int, bool) {
+		if n, ok := names[s]; ok {
+			return n, true
+		}
+		n, err := strconv.Atoi(s)
+		return n, err == nil && n >= lo && n <= hi
+	}
+
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, false, false
+			}
+			step = n
+		}
+
+		from, to := lo, hi
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			a, b, _ := strings.Cut(rangePart, "-")
+			var okA, okB bool
+			from, okA = value(a)
+			to, okB = value(b)
+			if !okA || !okB || from > to {
+				return 0, false, false
+			}
+		default:
+			var ok bool
+			if from, ok = value(rangePart); !ok {
+				return 0, false, false
+			}
+			if !hasStep {
+				to = from
+			}
+		}
+		for v := from; v <= to; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, strings.HasPrefix(field, "*"), set != 0
+}
@@ -0,0 +1,228 @@
+package cronjobhealth_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/cronjobhealth"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeQueries struct {
+	installed   checktest.Result[bool]
+	clock       checktest.Result[db.CronClockRow]
+	jobs        checktest.Result[[]db.CronJobsRow]
+	windowHours *int32
+}
+
+func (f fakeQueries) HasPgCron(context.Context) (bool, error) {
+	return f.installed.Get()
+}
+
+func (f fakeQueries) CronClock(context.Context) (db.CronClockRow, error) {
+	return f.clock.Get()
+}
+
+func (f fakeQueries) CronJobs(_ context.Context, windowHours int32) ([]db.CronJobsRow, error) {
+	if f.windowHours != nil {
+		*f.windowHours = windowHours
+	}
+	return f.jobs.Get()
+}
+
+// now is a Wednesday.
+var now = time.Date(2026, 10, 14, 9, 30, 0, 0, time.UTC)
+
+func clock(timezone string) db.CronClockRow {
+	return db.CronClockRow{
+		Now:      pgtype.Timestamptz{Time: now, Valid: true},
+		Timezone: pgtype.Text{String: timezone, Valid: timezone != ""},
+	}
+}
+
+// job builds an active job whose latest run started ago before now and
+// succeeded.
+func job(name, schedule string, ago time.Duration) db.CronJobsRow {
+	return db.CronJobsRow{
+		JobName:       name,
+		Schedule:      schedule,
+		DatabaseName:  "app",
+		Active:        true,
+		LastStatus:    pgtype.Text{String: "succeeded", Valid: true},
+		LastStartTime: pgtype.Timestamptz{Time: now.Add(-ago), Valid: true},
+	}
+}
+
+func newChecker(timezone string, jobs []db.CronJobsRow, cfg ...check.Config) check.Checker {
+	return cronjobhealth.New(fakeQueries{
+		installed: checktest.Returns(true),
+		clock:     checktest.Returns(clock(timezone)),
+		jobs:      checktest.Returns(jobs),
+	}, cfg...)
+}
+
+func TestCronJobHealth_NotInstalled(t *testing.T) {
+	t.Parallel()
+
+	report := checktest.Run(t, cronjobhealth.New(fakeQueries{installed: checktest.Returns(false)}))
+	finding := checktest.OnlyFinding(t, report)
+	assert.Equal(t, check.SeverityNotApplicable, finding.Severity)
+	assert.Equal(t, check.SeverityNotApplicable, report.Severity)
+	assert.Contains(t, finding.Details, "cron.database_name")
+}
+
+func TestCronJobHealth_NoJobs(t *testing.T) {
+	t.Parallel()
+
+	report := checktest.Run(t, newChecker("", nil))
+	finding := checktest.OnlyFinding(t, report)
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Contains(t, finding.Details, "no active jobs")
+}
+
+func TestCronJobHealth_Failures(t *testing.T) {
+	t.Parallel()
+
+	failing := job("nightly-rollup", "0 3 * * *", 6*time.Hour+30*time.Minute)
+	failing.LastStatus = pgtype.Text{String: "failed", Valid: true}
+	failing.RecentFailures = 2
+	failing.LastError = pgtype.Text{String: "ERROR:  relation \"rollups\" does not exist\nLINE 1: ...", Valid: true}
+
+	report := checktest.Run(t, newChecker("", []db.CronJobsRow{failing, job("vacuum-queue", "*/5 * * * *", 2*time.Minute)}))
+	finding := checktest.OnlyFinding(t, report)
+	assert.Equal(t, check.SeverityFail, finding.Severity)
+	assert.Equal(t, "1 job(s) failed in the last 24h; see cron.job_run_details for each run", finding.Details)
+	assert.Equal(t, [][]string{
+		{"nightly-rollup", "app", "0 3 * * *", "failed", "6h ago", "2 failure(s) in 24h", `ERROR:  relation "rollups" does not exist`},
+	}, checktest.Rows(finding))
+}
+
+func TestCronJobHealth_MissedRuns(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		schedule string
+		ago      time.Duration
+		missed   bool
+	}{
+		{"every five minutes, on time", "*/5 * * * *", 2 * time.Minute, false},
+		{"every five minutes, stalled", "*/5 * * * *", 30 * time.Minute, true},
+		{"next run within grace", "*/5 * * * *", 8 * time.Minute, false},
+		{"daily, ran today", "0 3 * * *", 6*time.Hour + 29*time.Minute, false},
+		{"daily, last ran yesterday", "0 3 * * *", 30*time.Hour + 30*time.Minute, true},
+		{"macro", "@daily", 9*time.Hour + 29*time.Minute, false},
+		{"macro, missed", "@hourly", 2 * time.Hour, true},
+		{"weekday names", "0 9 * * mon-fri", 24*time.Hour + 30*time.Minute, true},
+		{"weekly on Monday", "0 0 * * 1", 2*24*time.Hour + 9*time.Hour + 30*time.Minute, false},
+		{"Sunday as 7", "0 0 * * 7", 3*24*time.Hour + 9*time.Hour + 30*time.Minute, false},
+		{"day of month or weekday", "0 0 13 * 3", 24*time.Hour + 9*time.Hour + 30*time.Minute, true},
+		{"monthly", "0 0 1 * *", 13*24*time.Hour + 9*time.Hour + 30*time.Minute, false},
+		{"interval", "30 seconds", 20 * time.Second, false},
+		{"interval, stalled", "30 seconds", 20 * time.Minute, true},
+		{"reboot is not judged", "@reboot", 90 * 24 * time.Hour, false},
+		{"last day of month is not judged", "0 0 $ * *", 90 * 24 * time.Hour, false},
+		{"invalid schedule is not judged", "61 * * * *", 90 * 24 * time.Hour, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			report := checktest.Run(t, newChecker("", []db.CronJobsRow{job("demo", tt.schedule, tt.ago)}))
+			finding := checktest.OnlyFinding(t, report)
+			if !tt.missed {
+				assert.Equal(t, check.SeverityOK, finding.Severity)
+				return
+			}
+			assert.Equal(t, check.SeverityWarn, finding.Severity)
+			assert.Contains(t, checktest.RowWhere(t, finding, "Job", "demo").Cells[5], "did not start")
+		})
+	}
+}
+
+func TestCronJobHealth_Timezone(t *testing.T) {
+	t.Parallel()
+
+	// 09:30 UTC is 05:30 in New York, so a 09:00 job last ran yesterday.
+	jobs := []db.CronJobsRow{job("morning-report", "0 9 * * *", 20*time.Hour+30*time.Minute)}
+
+	report := checktest.Run(t, newChecker("America/New_York", jobs))
+	assert.Equal(t, check.SeverityOK, checktest.OnlyFinding(t, report).Severity)
+
+	report = checktest.Run(t, newChecker("", jobs))
+	assert.Equal(t, check.SeverityWarn, checktest.OnlyFinding(t, report).Severity, "schedules default to GMT")
+}
+
+func TestCronJobHealth_SkipsInactiveAndNeverRun(t *testing.T) {
+	t.Parallel()
+
+	inactive := job("disabled", "*/5 * * * *", 30*24*time.Hour)
+	inactive.Active = false
+	inactive.RecentFailures = 3
+	neverRun := job("new-job", "*/5 * * * *", 0)
+	neverRun.LastStatus = pgtype.Text{}
+	neverRun.LastStartTime = pgtype.Timestamptz{}
+
+	report := checktest.Run(t, newChecker("", []db.CronJobsRow{inactive, neverRun}))
+	finding := checktest.OnlyFinding(t, report)
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "1 active job(s); none failed in the last 24h or missed a scheduled run", finding.Details)
+}
+
+func TestCronJobHealth_Config(t *testing.T) {
+	t.Parallel()
+
+	var windowHours int32
+	queries := fakeQueries{
+		installed:   checktest.Returns(true),
+		clock:       checktest.Returns(clock("")),
+		jobs:        checktest.Returns([]db.CronJobsRow{job("demo", "*/5 * * * *", 30*time.Minute)}),
+		windowHours: &windowHours,
+	}
+
+	report := checktest.Run(t, cronjobhealth.New(queries))
+	assert.Equal(t, int32(24), windowHours)
+	assert.Equal(t, check.SeverityWarn, report.Severity)
+
+	cfg := check.Config{"cron-job-health": {"window_hours": "72", "grace_minutes": "60"}}
+	report = checktest.Run(t, cronjobhealth.New(queries, cfg))
+	assert.Equal(t, int32(72), windowHours)
+	assert.Equal(t, check.SeverityOK, report.Severity, "a 60 minute grace covers a 30 minute stall")
+
+	cfg = check.Config{"cron-job-health": {"window_hours": "0", "grace_minutes": "soon"}}
+	report = checktest.Run(t, cronjobhealth.New(queries, cfg))
+	assert.Equal(t, int32(24), windowHours, "invalid values keep the defaults")
+	assert.Equal(t, check.SeverityWarn, report.Severity)
+}
+
+func TestCronJobHealth_QueryError(t *testing.T) {
+	t.Parallel()
+
+	queryErr := fmt.Errorf("permission denied for schema cron")
+	checktest.RequireError(t, cronjobhealth.New(fakeQueries{installed: checktest.Fails[bool](queryErr)}))
+	checktest.RequireError(t, cronjobhealth.New(fakeQueries{
+		installed: checktest.Returns(true),
+		clock:     checktest.Fails[db.CronClockRow](queryErr),
+	}))
+	err := checktest.RequireError(t, cronjobhealth.New(fakeQueries{
+		installed: checktest.Returns(true),
+		clock:     checktest.Returns(clock("")),
+		jobs:      checktest.Fails[[]db.CronJobsRow](queryErr),
+	}))
+	require.ErrorIs(t, err, queryErr)
+}
+
+func TestCronJobHealth_Metadata(t *testing.T) {
+	t.Parallel()
+
+	checktest.RequireMetadata(t, cronjobhealth.Metadata(), "cron-job-health", check.CategoryConfigs)
+}
@@ -0,0 +1,48 @@
+-- name: HasPgCron :one
+-- Checks if the pg_cron extension is installed in this database.
+SELECT EXISTS(
+  SELECT 1 FROM pg_extension
+  WHERE extname = 'pg_cron'
+);
+
+-- name: CronClock :one
+-- The server clock and the time zone pg_cron evaluates schedules in
+-- (cron.timezone, pg_cron 1.5+; GMT when unset).
+SELECT
+  now() AS now
+  , current_setting('cron.timezone', true) AS timezone;
+
+-- name: CronJobs :many
+-- Every scheduled job with its most recent run and the failed runs in the
+-- last window_hours. Runs are only recorded while cron.log_run is on.
+SELECT
+  j.jobid
+  , coalesce(j.jobname, 'job ' || j.jobid)::text AS job_name
+  , j.schedule::text AS schedule
+  , j.database::text AS database_name
+  , j.active
+  , last_run.status AS last_status
+  , last_run.start_time AS last_start_time
+  , coalesce(failures.failed_runs, 0)::bigint AS recent_failures
+  , failures.last_error
+FROM cron.job AS j
+LEFT JOIN LATERAL (
+  SELECT
+    d.status
+    , d.start_time
+  FROM cron.job_run_details AS d
+  WHERE d.jobid = j.jobid
+  ORDER BY d.runid DESC
+  LIMIT 1
+) AS last_run ON true
+LEFT JOIN LATERAL (
+  SELECT
+    count(*) AS failed_runs
+    , (array_agg(d.return_message ORDER BY d.runid DESC))[1] AS last_error
+  FROM cron.job_run_details AS d
+  WHERE
+    d.jobid = j.jobid
+    AND d.status = 'failed'
+    AND d.start_time >= now() - make_interval(hours => sqlc.arg(window_hours)::int)
+) AS failures ON true
+ORDER BY job_name;
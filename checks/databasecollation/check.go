@@ -0,0 +1,109 @@
+// Package databasecollation implements a check for database default collation versions that changed under existing indexes.
+package databasecollation
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+// pg_database.datcollversion was added in PostgreSQL 15.
+const databaseVersionsMinMajor = 15
+
+type DatabaseCollationQueries interface {
+	PGVersion(context.Context) (db.PGVersionRow, error)
+	DatabaseCollationMismatches(context.Context) ([]db.DatabaseCollationMismatchesRow, error)
+}
+
+type checker struct {
+	queries DatabaseCollationQueries
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryIndexes,
+		CheckID:     "database-collation",
+		Name:        "Database Collation Version",
+		Description: "Detects databases whose default collation version no longer matches the OS or ICU library, a sign of silently corrupt indexes",
+		Readme:      readme,
+		SQL:         querySQL,
+		// Database collations are read for every database at once.
+		ClusterWide: true,
+	}
+}
+
+func New(queries DatabaseCollationQueries, _ ...check.Config) check.Checker {
+	return &checker{
+		queries: queries,
+	}
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	version, err := c.queries.PGVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	if version.Major < databaseVersionsMinMajor {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityNotApplicable,
+			Details:  fmt.Sprintf("Database collation versions are not recorded before PostgreSQL %d", databaseVersionsMinMajor),
+		})
+		return report, nil
+	}
+
+	rows, err := c.queries.DatabaseCollationMismatches(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	if len(rows) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  "All database collation versions match the provider",
+		})
+		return report, nil
+	}
+
+	tableRows := make([]check.TableRow, 0, len(rows))
+	for _, row := range rows {
+		tableRows = append(tableRows, check.TableRow{
+			Cells:    []string{row.DatabaseName, row.RecordedVersion, row.ActualVersion},
+			Severity: check.SeverityFail,
+		})
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: check.SeverityFail,
+		Details: fmt.Sprintf("Found %d database(s) whose default collation version changed; "+
+			"REINDEX their text indexes, then ALTER DATABASE ... REFRESH COLLATION VERSION", len(rows)),
+		Table: &check.Table{
+			Headers:   []string{"Database", "Recorded", "Actual"},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	})
+
+	return report, nil
+}
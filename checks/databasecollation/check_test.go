@@ -0,0 +1,97 @@
+package databasecollation_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/checks/databasecollation"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockQueryer struct {
+	major       int32
+	databases   []db.DatabaseCollationMismatchesRow
+	versionErr  error
+	databaseErr error
+
+	databasesQueried bool
+}
+
+func (m *mockQueryer) PGVersion(context.Context) (db.PGVersionRow, error) {
+	return db.PGVersionRow{Major: m.major}, m.versionErr
+}
+
+func (m *mockQueryer) DatabaseCollationMismatches(context.Context) ([]db.DatabaseCollationMismatchesRow, error) {
+	m.databasesQueried = true
+	return m.databases, m.databaseErr
+}
+
+func TestDatabaseCollation_NoMismatches(t *testing.T) {
+	t.Parallel()
+
+	report, err := databasecollation.New(&mockQueryer{major: 16}).Check(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, check.SeverityOK, report.Severity)
+	require.Len(t, report.Results, 1)
+	assert.Equal(t, "database-collation", report.Results[0].ID)
+}
+
+func TestDatabaseCollation_Mismatch(t *testing.T) {
+	t.Parallel()
+
+	report, err := databasecollation.New(&mockQueryer{
+		major: 15,
+		databases: []db.DatabaseCollationMismatchesRow{
+			{DatabaseName: "app", RecordedVersion: "2.31", ActualVersion: "2.36"},
+		},
+	}).Check(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, report.Results, 1)
+	finding := report.Results[0]
+	assert.Equal(t, check.SeverityFail, finding.Severity)
+	assert.Contains(t, finding.Details, "1 database(s)")
+	assert.Contains(t, finding.Details, "REFRESH COLLATION VERSION")
+	require.NotNil(t, finding.Table)
+	assert.Equal(t, []string{"app", "2.31", "2.36"}, finding.Table.Rows[0].Cells)
+}
+
+func TestDatabaseCollation_BeforePG15(t *testing.T) {
+	t.Parallel()
+
+	queryer := &mockQueryer{major: 14}
+	report, err := databasecollation.New(queryer).Check(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, check.SeverityNotApplicable, report.Severity)
+	assert.False(t, queryer.databasesQueried, "pg_database.datcollversion only exists on PG15+")
+}
+
+func TestDatabaseCollation_QueryError(t *testing.T) {
+	t.Parallel()
+
+	for _, queryer := range []*mockQueryer{
+		{versionErr: fmt.Errorf("connection refused")},
+		{major: 16, databaseErr: fmt.Errorf("connection refused")},
+	} {
+		_, err := databasecollation.New(queryer).Check(context.Background())
+		require.ErrorContains(t, err, "database-collation")
+	}
+}
+
+func TestDatabaseCollation_Metadata(t *testing.T) {
+	t.Parallel()
+
+	m := databasecollation.Metadata()
+	assert.Equal(t, "database-collation", m.CheckID)
+	assert.Equal(t, check.CategoryIndexes, m.Category)
+	assert.True(t, m.ClusterWide, "database collations are read for every database, so multi-database runs check them once")
+	assert.NotEmpty(t, m.Name)
+	assert.NotEmpty(t, m.Readme)
+	assert.NotEmpty(t, m.SQL)
+}
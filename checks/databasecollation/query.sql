@@ -0,0 +1,15 @@
+-- name: DatabaseCollationMismatches :many
+-- Databases whose default collation version (PG 15+) differs from the version
+-- the provider reports now. Every text index in them that uses the default
+-- collation is affected.
+SELECT
+  d.datname::text AS database_name
+  , d.datcollversion::text AS recorded_version
+  , pg_database_collation_actual_version(d.oid)::text AS actual_version
+FROM pg_database AS d
+WHERE
+  d.datallowconn
+  AND d.datcollversion IS NOT NULL
+  AND pg_database_collation_actual_version(d.oid) IS NOT NULL
+  AND d.datcollversion <> pg_database_collation_actual_version(d.oid)
+ORDER BY d.datname;
@@ -0,0 +1,221 @@
+// Package databasesettings implements a check for validating database-level timeout defaults.
+package databasesettings
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strconv"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type DatabaseSettingsQueries interface {
+	DatabaseSettings(context.Context) ([]db.DatabaseSettingsRow, error)
+}
+
+type settingCheck struct {
+	Database  string
+	Parameter string
+	Current   string
+	Source    string
+	Expected  string
+	Status    string
+	Severity  check.Severity
+}
+
+type checker struct {
+	queryer     DatabaseSettingsQueries
+	timeoutWarn int64 // default: 5000
+	timeoutFail int64 // default: 10000
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryConfigs,
+		CheckID:     "database-settings",
+		Name:        "PostgreSQL Database Configs",
+		Description: "Validates the statement, lock, and idle-in-transaction timeouts each database sets for sessions without role-level overrides",
+		Readme:      readme,
+		SQL:         querySQL,
+		ClusterWide: true,
+	}
+}
+
+func New(queryer DatabaseSettingsQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queryer:     queryer,
+		timeoutWarn: 5000,
+		timeoutFail: 10000,
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			if v, ok := myCfg["timeout_warn"]; ok {
+				if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+					c.timeoutWarn = n
+				}
+			}
+			if v, ok := myCfg["timeout_fail"]; ok {
+				if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+					c.timeoutFail = n
+				}
+			}
+		}
+	}
+	return c
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	settings, err := c.queryer.DatabaseSettings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	databases := map[string]bool{}
+	var checks []settingCheck
+	for _, row := range settings {
+		databases[row.DatabaseName] = true
+
+		ms, err := check.ParseDurationMs(row.SettingValue, row.Unit)
+		if err != nil {
+			return nil, fmt.Errorf("setting %s for database %s has invalid value %q: %w", row.SettingName, row.DatabaseName, row.SettingValue, err)
+		}
+
+		var sc settingCheck
+		switch row.SettingName {
+		case "statement_timeout":
+			sc = c.checkStatementTimeout(ms)
+		case "lock_timeout":
+			sc = c.checkLockTimeout(ms)
+		case "idle_in_transaction_session_timeout":
+			sc = checkIdleTimeout(ms)
+		default:
+			continue
+		}
+		sc.Database = row.DatabaseName
+		sc.Source = row.Source
+		checks = append(checks, sc)
+	}
+
+	if len(databases) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  "No databases accept connections",
+		})
+		return report, nil
+	}
+
+	overallSeverity := check.SeverityOK
+	for _, sc := range checks {
+		if sc.Severity > overallSeverity {
+			overallSeverity = sc.Severity
+		}
+	}
+
+	result := check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: overallSeverity,
+		Details:  fmt.Sprintf("Timeout defaults of %d database(s) are within limits", len(databases)),
+	}
+
+	if overallSeverity != check.SeverityOK {
+		// Create table with only non-OK entries
+		tableRows := []check.TableRow{}
+		for _, sc := range checks {
+			if sc.Severity != check.SeverityOK {
+				tableRows = append(tableRows, check.TableRow{
+					Cells: []string{
+						sc.Database,
+						sc.Parameter,
+						sc.Current,
+						sc.Source,
+						sc.Expected,
+						sc.Status,
+					},
+					Severity: sc.Severity,
+				})
+			}
+		}
+
+		result.Details = fmt.Sprintf("Found %d database default issue(s). Sessions of roles without their own settings get these values", len(tableRows))
+		result.Table = &check.Table{
+			Headers:   []string{"Database", "Parameter", "Current", "Source", "Expected", "Status"},
+			Key:       []int{0, 1},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier},
+			Rows:      tableRows,
+		}
+	}
+
+	report.AddFinding(result)
+
+	return report, nil
+}
+
+// checkStatementTimeout applies the session-settings thresholds.
+func (c *checker) checkStatementTimeout(ms int64) settingCheck {
+	sc := settingCheck{
+		Parameter: "statement_timeout",
+		Current:   fmt.Sprintf("%dms", ms),
+		Expected:  fmt.Sprintf("≤ %dms", c.timeoutWarn),
+		Status:    "OK",
+		Severity:  check.SeverityOK,
+	}
+	switch {
+	case ms == 0:
+		sc.Current, sc.Status, sc.Severity = "0ms (disabled)", "MUST be set", check.SeverityFail
+	case ms > c.timeoutFail:
+		sc.Status, sc.Severity = "Too high", check.SeverityFail
+	case ms > c.timeoutWarn:
+		sc.Status, sc.Severity = "High", check.SeverityWarn
+	}
+	return sc
+}
+
+// checkLockTimeout warns when waiting for a lock is unbounded or longer than
+// timeout_warn.
+func (c *checker) checkLockTimeout(ms int64) settingCheck {
+	sc := settingCheck{
+		Parameter: "lock_timeout",
+		Current:   fmt.Sprintf("%dms", ms),
+		Expected:  fmt.Sprintf("≤ %dms", c.timeoutWarn),
+		Status:    "OK",
+		Severity:  check.SeverityOK,
+	}
+	switch {
+	case ms == 0:
+		sc.Current, sc.Status, sc.Severity = "0ms (disabled)", "Disabled", check.SeverityWarn
+	case ms > c.timeoutWarn:
+		sc.Status, sc.Severity = "High", check.SeverityWarn
+	}
+	return sc
+}
+
+func checkIdleTimeout(ms int64) settingCheck {
+	sc := settingCheck{
+		Parameter: "idle_in_txn_timeout",
+		Current:   fmt.Sprintf("%dms", ms),
+		Expected:  "60000ms",
+		Status:    "OK",
+		Severity:  check.SeverityOK,
+	}
+	if ms == 0 {
+		sc.Current, sc.Status, sc.Severity = "0ms (disabled)", "Disabled", check.SeverityWarn
+	}
+	return sc
+}
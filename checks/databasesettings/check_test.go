@@ -0,0 +1,139 @@
+package databasesettings_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/databasesettings"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeQueries struct {
+	settings checktest.Result[[]db.DatabaseSettingsRow]
+}
+
+func (f fakeQueries) DatabaseSettings(context.Context) ([]db.DatabaseSettingsRow, error) {
+	return f.settings.Get()
+}
+
+func newChecker(rows []db.DatabaseSettingsRow, cfg ...check.Config) check.Checker {
+	return databasesettings.New(fakeQueries{settings: checktest.Returns(rows)}, cfg...)
+}
+
+// database returns the three timeout rows of one database, with the given
+// statement_timeout and well-behaved lock and idle timeouts.
+func database(name, statementTimeout, source string) []db.DatabaseSettingsRow {
+	return []db.DatabaseSettingsRow{
+		{DatabaseName: name, SettingName: "statement_timeout", SettingValue: statementTimeout, Unit: "ms", Source: source},
+		{DatabaseName: name, SettingName: "lock_timeout", SettingValue: "3000", Unit: "ms", Source: "database"},
+		{DatabaseName: name, SettingName: "idle_in_transaction_session_timeout", SettingValue: "60000", Unit: "ms", Source: "database"},
+	}
+}
+
+func TestDatabaseSettings_Metadata(t *testing.T) {
+	t.Parallel()
+	checktest.RequireMetadata(t, databasesettings.Metadata(), "database-settings", check.CategoryConfigs)
+	assert.True(t, databasesettings.Metadata().ClusterWide)
+}
+
+func TestDatabaseSettings_QueryError(t *testing.T) {
+	t.Parallel()
+	checktest.RequireError(t, databasesettings.New(fakeQueries{settings: checktest.Fails[[]db.DatabaseSettingsRow](errors.New("boom"))}))
+}
+
+func TestDatabaseSettings_AllWithinLimits(t *testing.T) {
+	t.Parallel()
+
+	rows := append(database("app", "5000", "database"), database("reporting", "3000", "all roles")...)
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(rows)))
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "Timeout defaults of 2 database(s) are within limits", finding.Details)
+	assert.Nil(t, finding.Table)
+}
+
+func TestDatabaseSettings_DisabledTimeouts(t *testing.T) {
+	t.Parallel()
+
+	rows := append(database("app", "5000", "database"),
+		db.DatabaseSettingsRow{DatabaseName: "legacy", SettingName: "statement_timeout", SettingValue: "0", Unit: "ms", Source: "cluster"},
+		db.DatabaseSettingsRow{DatabaseName: "legacy", SettingName: "lock_timeout", SettingValue: "0", Unit: "ms", Source: "cluster"},
+		db.DatabaseSettingsRow{DatabaseName: "legacy", SettingName: "idle_in_transaction_session_timeout", SettingValue: "0", Unit: "ms", Source: "cluster"},
+	)
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(rows)))
+	assert.Equal(t, check.SeverityFail, finding.Severity)
+	assert.Equal(t, "Found 3 database default issue(s). Sessions of roles without their own settings get these values", finding.Details)
+	assert.Equal(t, [][]string{
+		{"legacy", "statement_timeout", "0ms (disabled)", "cluster", "≤ 5000ms", "MUST be set"},
+		{"legacy", "lock_timeout", "0ms (disabled)", "cluster", "≤ 5000ms", "Disabled"},
+		{"legacy", "idle_in_txn_timeout", "0ms (disabled)", "cluster", "60000ms", "Disabled"},
+	}, checktest.Rows(finding))
+	assert.Equal(t, []check.Sensitivity{check.SensitiveIdentifier}, finding.Table.Sensitive)
+}
+
+func TestDatabaseSettings_Thresholds(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		value    string
+		unit     string
+		cfg      check.Config
+		expected check.Severity
+	}{
+		{"at warn threshold", "5000", "ms", nil, check.SeverityOK},
+		{"above warn threshold", "6000", "ms", nil, check.SeverityWarn},
+		{"above fail threshold", "30", "s", nil, check.SeverityFail},
+		{"custom warn threshold", "3000", "ms", check.Config{"database-settings": {"timeout_warn": "2000"}}, check.SeverityWarn},
+		{"custom fail threshold", "6000", "ms", check.Config{"database-settings": {"timeout_fail": "5500"}}, check.SeverityFail},
+		{"invalid threshold keeps default", "6000", "ms", check.Config{"database-settings": {"timeout_warn": "soon"}}, check.SeverityWarn},
+		{"olap profile", "600000", "ms", check.Config{"database-settings": {"timeout_warn": "300000", "timeout_fail": "1800000"}}, check.SeverityWarn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rows := database("app", tt.value, "database")
+			rows[0].Unit = tt.unit
+			var cfg []check.Config
+			if tt.cfg != nil {
+				cfg = append(cfg, tt.cfg)
+			}
+			finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(rows, cfg...)))
+			assert.Equal(t, tt.expected, finding.Severity)
+		})
+	}
+}
+
+func TestDatabaseSettings_HighLockTimeout(t *testing.T) {
+	t.Parallel()
+
+	rows := database("app", "5000", "database")
+	rows[1].SettingValue = "60000"
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(rows)))
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Equal(t, [][]string{
+		{"app", "lock_timeout", "60000ms", "database", "≤ 5000ms", "High"},
+	}, checktest.Rows(finding))
+}
+
+func TestDatabaseSettings_InvalidValue(t *testing.T) {
+	t.Parallel()
+
+	rows := database("app", "forever", "database")
+	_, err := newChecker(rows).Check(context.Background())
+	require.ErrorContains(t, err, `setting statement_timeout for database app has invalid value "forever"`)
+}
+
+func TestDatabaseSettings_NoDatabases(t *testing.T) {
+	t.Parallel()
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(nil)))
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "No databases accept connections", finding.Details)
+}
@@ -0,0 +1,54 @@
+-- name: DatabaseSettings :many
+-- The timeout defaults each connectable database gives sessions that have no
+-- role-level override: ALTER DATABASE d SET (setrole = 0, setdatabase = d),
+-- then ALTER ROLE ALL SET (setrole = 0, setdatabase = 0), then the cluster
+-- default. source says which level the value came from.
+--
+-- Caveat: the cluster default is pg_settings.reset_val, which includes the
+-- database and role overrides of pgdoctor's own session. Run pgdoctor from a
+-- database and role without overrides for these settings, or the fallback
+-- for other databases can be wrong (as in session-settings).
+WITH settings AS (
+  SELECT s.name, s.reset_val, s.unit
+  FROM pg_settings AS s
+  WHERE s.name IN (
+    'statement_timeout'
+    , 'lock_timeout'
+    , 'idle_in_transaction_session_timeout'
+  )
+)
+
+, overrides AS (
+  SELECT
+    drs.setdatabase AS db_oid
+    , split_part(cfg, '=', 1) AS param_name
+    , substring(cfg FROM position('=' IN cfg) + 1) AS param_value
+  FROM pg_db_role_setting AS drs
+  CROSS JOIN LATERAL unnest(coalesce(drs.setconfig, ARRAY[]::text [])) AS cfg
+  WHERE
+    drs.setrole = 0
+    AND split_part(cfg, '=', 1) IN (
+      'statement_timeout'
+      , 'lock_timeout'
+      , 'idle_in_transaction_session_timeout'
+    )
+)
+
+SELECT
+  d.datname::text AS database_name
+  , s.name::text AS setting_name
+  , coalesce(od.param_value, oa.param_value, s.reset_val)::text AS setting_value
+  , coalesce(s.unit, '')::text AS unit
+  , CASE
+    WHEN od.param_value IS NOT NULL THEN 'database'
+    WHEN oa.param_value IS NOT NULL THEN 'all roles'
+    ELSE 'cluster'
+  END::text AS source
+FROM pg_database AS d
+CROSS JOIN settings AS s
+LEFT JOIN overrides AS od ON od.db_oid = d.oid AND od.param_name = s.name
+LEFT JOIN overrides AS oa ON oa.db_oid = 0 AND oa.param_name = s.name
+WHERE
+  d.datallowconn
+  AND NOT d.datistemplate
+ORDER BY d.datname, s.name;
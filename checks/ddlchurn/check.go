@@ -0,0 +1,174 @@
+// Package ddlchurn implements a check estimating temporary table and DDL churn from proxy metrics.
+package ddlchurn
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strconv"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type DDLChurnQueries interface {
+	TempRelations(context.Context) (db.TempRelationsRow, error)
+	CatalogChurn(context.Context) ([]db.CatalogChurnRow, error)
+}
+
+type checker struct {
+	queries       DDLChurnQueries
+	maxTempTables int64   // default: 1000
+	churnRatio    float64 // default: 10
+	catalogBytes  int64   // default: 1GiB
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryPerformance,
+		CheckID:     "ddl-churn",
+		Name:        "Temp Table and DDL Churn",
+		Description: "Estimates temporary table and DDL churn, a driver of catalog bloat, from temp schemas and catalog turnover",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries DDLChurnQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries:       queries,
+		maxTempTables: 1000,
+		churnRatio:    10,
+		catalogBytes:  check.GiB,
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			if v, ok := myCfg["temp_tables"]; ok {
+				if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+					c.maxTempTables = n
+				}
+			}
+			if v, ok := myCfg["churn_ratio"]; ok {
+				if n, err := strconv.ParseFloat(v, 64); err == nil && n > 0 {
+					c.churnRatio = n
+				}
+			}
+			if v, ok := myCfg["catalog_size_mb"]; ok {
+				if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+					c.catalogBytes = n * check.MiB
+				}
+			}
+		}
+	}
+	return c
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	temp, err := c.queries.TempRelations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s (temp relations): %w", report.Category, report.CheckID, err)
+	}
+
+	catalogs, err := c.queries.CatalogChurn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s (catalogs): %w", report.Category, report.CheckID, err)
+	}
+
+	c.checkTempTables(temp, report)
+	c.checkCatalogChurn(catalogs, report)
+
+	return report, nil
+}
+
+func (c *checker) checkTempTables(row db.TempRelationsRow, report *check.Report) {
+	details := fmt.Sprintf("%s temporary table(s) exist across %s temporary schema(s)",
+		check.FormatNumber(row.TempTables), check.FormatNumber(row.TempSchemas))
+
+	if row.TempTables < c.maxTempTables {
+		report.AddFinding(check.Finding{
+			ID:       "temp-tables",
+			Name:     "Temporary Tables",
+			Severity: check.SeverityOK,
+			Details:  details,
+		})
+		return
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       "temp-tables",
+		Name:     "Temporary Tables",
+		Severity: check.SeverityWarn,
+		Details: details + fmt.Sprintf(" (threshold: %s). Every CREATE TEMP TABLE writes catalog rows that are "+
+			"deleted again when the table is dropped; reuse sessions and tables instead of recreating them", check.FormatNumber(c.maxTempTables)),
+	})
+}
+
+func (c *checker) checkCatalogChurn(rows []db.CatalogChurnRow, report *check.Report) {
+	var tableRows []check.TableRow
+	flagged := 0
+	for _, row := range rows {
+		turnover := "-"
+		severity := check.SeverityOK
+		if row.LiveRows > 0 {
+			ratio := float64(row.ChangedRows) / float64(row.LiveRows)
+			turnover = fmt.Sprintf("%.1fx", ratio)
+			if ratio >= c.churnRatio {
+				severity = check.SeverityWarn
+			}
+		}
+		if row.TotalBytes >= c.catalogBytes {
+			severity = check.SeverityWarn
+		}
+		if severity == check.SeverityWarn {
+			flagged++
+		}
+
+		tableRows = append(tableRows, check.TableRow{
+			Cells: []string{
+				row.CatalogName,
+				check.FormatBytes(row.TotalBytes),
+				check.FormatNumber(row.LiveRows),
+				check.FormatNumber(row.DeadRows),
+				check.FormatNumber(row.ChangedRows),
+				turnover,
+			},
+			Severity: severity,
+		})
+	}
+
+	if flagged == 0 {
+		report.AddFinding(check.Finding{
+			ID:       "catalog-churn",
+			Name:     "Catalog Churn",
+			Severity: check.SeverityOK,
+			Details:  fmt.Sprintf("No system catalog has turned over %.0fx or grown past %s", c.churnRatio, check.FormatBytes(c.catalogBytes)),
+		})
+		return
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       "catalog-churn",
+		Name:     "Catalog Churn",
+		Severity: check.SeverityWarn,
+		Details: fmt.Sprintf("%d system catalog(s) turned over %.0fx or more since the last stats reset, or grew past %s; "+
+			"frequent CREATE/DROP (often temporary tables) bloats the catalogs and slows planning. "+
+			"Use connection pooling with session reuse, or keep tables instead of recreating them", flagged, c.churnRatio, check.FormatBytes(c.catalogBytes)),
+		Table: &check.Table{
+			Headers: []string{"Catalog", "Size", "Live Rows", "Dead Rows", "Changed", "Turnover"},
+			Align:   []check.ColumnAlign{check.AlignLeft, check.AlignRight, check.AlignRight, check.AlignRight, check.AlignRight, check.AlignRight},
+			Rows:    tableRows,
+		},
+	})
+}
@@ -0,0 +1,117 @@
+package ddlchurn_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/ddlchurn"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeQueries struct {
+	temp     checktest.Result[db.TempRelationsRow]
+	catalogs checktest.Result[[]db.CatalogChurnRow]
+}
+
+func (f fakeQueries) TempRelations(context.Context) (db.TempRelationsRow, error) {
+	return f.temp.Get()
+}
+
+func (f fakeQueries) CatalogChurn(context.Context) ([]db.CatalogChurnRow, error) {
+	return f.catalogs.Get()
+}
+
+func catalog(name string, totalBytes, live, changed int64) db.CatalogChurnRow {
+	return db.CatalogChurnRow{CatalogName: name, TotalBytes: totalBytes, LiveRows: live, DeadRows: changed / 10, ChangedRows: changed}
+}
+
+func quietCatalogs() []db.CatalogChurnRow {
+	return []db.CatalogChurnRow{
+		catalog("pg_attribute", 20*check.MiB, 100_000, 50_000),
+		catalog("pg_class", 4*check.MiB, 10_000, 5_000),
+	}
+}
+
+func newChecker(temp db.TempRelationsRow, catalogs []db.CatalogChurnRow, cfg ...check.Config) check.Checker {
+	return ddlchurn.New(fakeQueries{temp: checktest.Returns(temp), catalogs: checktest.Returns(catalogs)}, cfg...)
+}
+
+func TestDDLChurn_Quiet(t *testing.T) {
+	t.Parallel()
+
+	report := checktest.Run(t, newChecker(db.TempRelationsRow{TempSchemas: 3, TempTables: 12}, quietCatalogs()))
+	checktest.RequireSeverities(t, report, map[string]check.Severity{
+		"temp-tables":   check.SeverityOK,
+		"catalog-churn": check.SeverityOK,
+	})
+	assert.Equal(t, "12 temporary table(s) exist across 3 temporary schema(s)", checktest.Finding(t, report, "temp-tables").Details)
+}
+
+func TestDDLChurn_TempTables(t *testing.T) {
+	t.Parallel()
+
+	report := checktest.Run(t, newChecker(db.TempRelationsRow{TempSchemas: 80, TempTables: 2500}, quietCatalogs()))
+	finding := checktest.Finding(t, report, "temp-tables")
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Contains(t, finding.Details, "2.5K temporary table(s)")
+
+	cfg := check.Config{"ddl-churn": {"temp_tables": "5000"}}
+	report = checktest.Run(t, newChecker(db.TempRelationsRow{TempSchemas: 80, TempTables: 2500}, quietCatalogs(), cfg))
+	assert.Equal(t, check.SeverityOK, checktest.Finding(t, report, "temp-tables").Severity)
+}
+
+func TestDDLChurn_CatalogTurnover(t *testing.T) {
+	t.Parallel()
+
+	catalogs := []db.CatalogChurnRow{
+		catalog("pg_attribute", 300*check.MiB, 100_000, 4_000_000),
+		catalog("pg_class", 40*check.MiB, 10_000, 50_000),
+		catalog("pg_depend", 0, 0, 0),
+	}
+	report := checktest.Run(t, newChecker(db.TempRelationsRow{}, catalogs))
+
+	finding := checktest.Finding(t, report, "catalog-churn")
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Contains(t, finding.Details, "1 system catalog(s)")
+	assert.Equal(t, [][]string{
+		{"pg_attribute", "300.0MiB", "100.0K", "400.0K", "4.0M", "40.0x"},
+		{"pg_class", "40.0MiB", "10.0K", "5.0K", "50.0K", "5.0x"},
+		{"pg_depend", "0B", "0", "0", "0", "-"},
+	}, checktest.Rows(finding))
+	assert.Equal(t, check.SeverityWarn, checktest.RowWhere(t, finding, "Catalog", "pg_attribute").Severity)
+	assert.Equal(t, check.SeverityOK, checktest.RowWhere(t, finding, "Catalog", "pg_class").Severity)
+}
+
+func TestDDLChurn_CatalogSize(t *testing.T) {
+	t.Parallel()
+
+	catalogs := []db.CatalogChurnRow{catalog("pg_attribute", 2*check.GiB, 10_000_000, 1_000)}
+	report := checktest.Run(t, newChecker(db.TempRelationsRow{}, catalogs))
+	assert.Equal(t, check.SeverityWarn, checktest.Finding(t, report, "catalog-churn").Severity)
+
+	cfg := check.Config{"ddl-churn": {"catalog_size_mb": "4096", "churn_ratio": "0.5"}}
+	report = checktest.Run(t, newChecker(db.TempRelationsRow{}, catalogs, cfg))
+	assert.Equal(t, check.SeverityOK, checktest.Finding(t, report, "catalog-churn").Severity)
+}
+
+func TestDDLChurn_QueryError(t *testing.T) {
+	t.Parallel()
+
+	checktest.RequireError(t, ddlchurn.New(fakeQueries{temp: checktest.Fails[db.TempRelationsRow](fmt.Errorf("connection refused"))}))
+
+	err := checktest.RequireError(t, ddlchurn.New(fakeQueries{
+		catalogs: checktest.Fails[[]db.CatalogChurnRow](fmt.Errorf("permission denied")),
+	}))
+	require.ErrorContains(t, err, "catalogs")
+}
+
+func TestDDLChurn_Metadata(t *testing.T) {
+	t.Parallel()
+
+	checktest.RequireMetadata(t, ddlchurn.Metadata(), "ddl-churn", check.CategoryPerformance)
+}
@@ -0,0 +1,29 @@
+-- name: TempRelations :one
+-- Temporary schemas and the temporary tables in them right now. A backend
+-- gets a pg_temp_N schema the first time it creates a temporary table, and
+-- the schema outlives the session, so the count is how many backend slots
+-- have ever used temporary tables.
+SELECT
+  (
+    SELECT count(*) FROM pg_namespace
+    WHERE nspname LIKE 'pg\_temp\_%'
+  ) AS temp_schemas
+  , (
+    SELECT count(*) FROM pg_class
+    WHERE relpersistence = 't' AND relkind IN ('r', 'p')
+  ) AS temp_tables;
+
+-- name: CatalogChurn :many
+-- Size and row turnover of the catalogs every CREATE and DROP writes to.
+-- Inserted and deleted rows are counted since the last statistics reset.
+SELECT
+  s.relname::text AS catalog_name
+  , pg_total_relation_size(s.relid) AS total_bytes
+  , COALESCE(s.n_live_tup, 0)::bigint AS live_rows
+  , COALESCE(s.n_dead_tup, 0)::bigint AS dead_rows
+  , (COALESCE(s.n_tup_ins, 0) + COALESCE(s.n_tup_del, 0))::bigint AS changed_rows
+FROM pg_stat_sys_tables AS s
+WHERE
+  s.schemaname = 'pg_catalog'
+  AND s.relname IN ('pg_class', 'pg_attribute', 'pg_type', 'pg_depend')
+ORDER BY pg_total_relation_size(s.relid) DESC;
@@ -0,0 +1,168 @@
+// Package extensionsecurity implements a check for installed extensions that widen what non-superusers can do.
+package extensionsecurity
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type ExtensionSecurityQueries interface {
+	ExtensionSecurity(context.Context) ([]db.ExtensionSecurityRow, error)
+}
+
+type checker struct {
+	queries ExtensionSecurityQueries
+	allowed map[string]struct{}
+}
+
+// defaultAllowed are untrusted extensions that are not reported unless
+// allow is set: pg_stat_statements only reads statistics, and other checks
+// rely on it.
+var defaultAllowed = []string{"pg_stat_statements"}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategorySchema,
+		CheckID:     "extension-security",
+		Name:        "Extension Security",
+		Description: "Flags untrusted extensions, extension C functions owned by non-superusers, and extensions in schemas non-superusers can create objects in, where their functions can be shadowed",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries ExtensionSecurityQueries, cfg ...check.Config) check.Checker {
+	c := &checker{queries: queries, allowed: toSet(defaultAllowed)}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			if v, ok := myCfg["allow"]; ok {
+				c.allowed = toSet(splitList(v))
+			}
+		}
+	}
+	return c
+}
+
+// splitList splits a comma-separated config value, dropping blanks.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func toSet(items []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		set[item] = struct{}{}
+	}
+	return set
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	extensions, err := c.queries.ExtensionSecurity(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	var tableRows []check.TableRow
+	var reason string
+	flagged, allowed := 0, 0
+	for _, ext := range extensions {
+		if _, ok := c.allowed[ext.ExtensionName]; ok {
+			allowed++
+			continue
+		}
+
+		var risks [][2]string // issue, risk
+		if !ext.Trusted {
+			issue, risk := "Untrusted", "Only a superuser can install it; its functions can reach files, the network, or server memory, so review who may call them"
+			if !ext.OwnerSuperuser {
+				issue = "Untrusted, owned by a non-superuser"
+				risk = "Its owner can grant, alter, and replace the bodies of functions only a superuser should control"
+			}
+			risks = append(risks, [2]string{issue, risk})
+		}
+		if ext.NonSuperuserCFunctions > 0 {
+			risks = append(risks, [2]string{
+				fmt.Sprintf("%d C function(s) owned by non-superusers", ext.NonSuperuserCFunctions),
+				"Their owners can grant them or make them SECURITY DEFINER, and C code runs unchecked in the server process",
+			})
+		}
+		if ext.SchemaWriters != "" {
+			risks = append(risks, [2]string{
+				"Schema writable by " + ext.SchemaWriters,
+				"They can create functions and operators that shadow the extension's, run as whoever calls them with this schema on the search_path (CVE-2018-1058)",
+			})
+		}
+		if len(risks) == 0 {
+			continue
+		}
+
+		flagged++
+		if reason == "" {
+			reason = fmt.Sprintf("%s: %s → WARN", ext.ExtensionName, risks[0][0])
+		}
+		for _, r := range risks {
+			tableRows = append(tableRows, check.TableRow{
+				Cells:    []string{ext.ExtensionName, ext.ExtensionVersion, ext.SchemaName, ext.OwnerName, r[0], r[1]},
+				Severity: check.SeverityWarn,
+			})
+		}
+	}
+
+	if flagged == 0 {
+		details := fmt.Sprintf("None of the %d installed extension(s) are untrusted or in a schema non-superusers can create objects in", len(extensions))
+		if allowed > 0 {
+			details += fmt.Sprintf(" (%d on the allow list)", allowed)
+		}
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  details,
+		})
+		return report, nil
+	}
+
+	details := fmt.Sprintf("Found %d extension(s) with %d privilege risk(s): untrusted code, C functions in non-superuser hands, "+
+		"or functions that roles able to create objects in their schema can shadow. "+
+		"Add extensions installed on purpose to the allow list", flagged, len(tableRows))
+	if allowed > 0 {
+		details += fmt.Sprintf("; %d on the allow list", allowed)
+	}
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: check.SeverityWarn,
+		Details:  details,
+		Reason:   reason,
+		Table: &check.Table{
+			Headers:   []string{"Extension", "Version", "Schema", "Owner", "Issue", "Risk"},
+			Sensitive: []check.Sensitivity{check.NotSensitive, check.NotSensitive, check.SensitiveIdentifier, check.SensitiveIdentifier, check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	})
+
+	return report, nil
+}
@@ -0,0 +1,99 @@
+package extensionsecurity_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/extensionsecurity"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeQueries struct {
+	extensions checktest.Result[[]db.ExtensionSecurityRow]
+}
+
+func (f fakeQueries) ExtensionSecurity(context.Context) ([]db.ExtensionSecurityRow, error) {
+	return f.extensions.Get()
+}
+
+func run(t *testing.T, cfg check.Config, extensions ...db.ExtensionSecurityRow) check.Finding {
+	t.Helper()
+	checker := extensionsecurity.New(fakeQueries{extensions: checktest.Returns(extensions)}, cfg)
+	return checktest.OnlyFinding(t, checktest.Run(t, checker))
+}
+
+// extension is a trusted extension owned by a superuser in a schema only
+// superusers can write to.
+func extension(name string) db.ExtensionSecurityRow {
+	return db.ExtensionSecurityRow{ExtensionName: name, ExtensionVersion: "1.0", SchemaName: "extensions", OwnerName: "postgres", OwnerSuperuser: true, Trusted: true}
+}
+
+func TestExtensionSecurity_Metadata(t *testing.T) {
+	t.Parallel()
+	checktest.RequireMetadata(t, extensionsecurity.Metadata(), "extension-security", check.CategorySchema)
+}
+
+func TestExtensionSecurity_QueryError(t *testing.T) {
+	t.Parallel()
+	checktest.RequireError(t, extensionsecurity.New(fakeQueries{extensions: checktest.Fails[[]db.ExtensionSecurityRow](errors.New("boom"))}))
+}
+
+func TestExtensionSecurity_Clean(t *testing.T) {
+	t.Parallel()
+
+	statements := extension("pg_stat_statements")
+	statements.Trusted = false
+
+	finding := run(t, nil, extension("pgcrypto"), statements)
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "None of the 2 installed extension(s) are untrusted or in a schema non-superusers can create objects in (1 on the allow list)",
+		finding.Details, "pg_stat_statements is allowed by default")
+}
+
+func TestExtensionSecurity_Risks(t *testing.T) {
+	t.Parallel()
+
+	dblink := extension("dblink")
+	dblink.Trusted = false
+	dblink.OwnerSuperuser = false
+	dblink.OwnerName = "app_admin"
+	dblink.NonSuperuserCFunctions = 3
+
+	pgcrypto := extension("pgcrypto")
+	pgcrypto.SchemaName = "public"
+	pgcrypto.SchemaWriters = "PUBLIC"
+
+	finding := run(t, nil, dblink, pgcrypto, extension("citext"))
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Contains(t, finding.Details, "Found 2 extension(s) with 3 privilege risk(s)")
+	assert.Equal(t, "dblink: Untrusted, owned by a non-superuser → WARN", finding.Reason)
+
+	rows := checktest.Rows(finding)
+	require.Len(t, rows, 3)
+	assert.Equal(t, []string{"dblink", "1.0", "extensions", "app_admin", "Untrusted, owned by a non-superuser"}, rows[0][:5])
+	assert.Equal(t, "3 C function(s) owned by non-superusers", rows[1][4])
+	assert.Equal(t, []string{"pgcrypto", "1.0", "public", "postgres", "Schema writable by PUBLIC"}, rows[2][:5])
+	assert.Contains(t, rows[2][5], "CVE-2018-1058")
+}
+
+func TestExtensionSecurity_Allow(t *testing.T) {
+	t.Parallel()
+
+	dblink := extension("dblink")
+	dblink.Trusted = false
+	statements := extension("pg_stat_statements")
+	statements.Trusted = false
+
+	finding := run(t, check.Config{"extension-security": {"allow": "dblink, "}}, dblink, statements)
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Equal(t, [][]string{
+		{"pg_stat_statements", "1.0", "extensions", "postgres", "Untrusted",
+			"Only a superuser can install it; its functions can reach files, the network, or server memory, so review who may call them"},
+	}, checktest.Rows(finding), "allow replaces the default list")
+	assert.Contains(t, finding.Details, "; 1 on the allow list")
+}
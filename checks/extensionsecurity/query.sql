@@ -0,0 +1,50 @@
+-- name: ExtensionSecurity :many
+-- Installed extensions outside pg_catalog with what makes them a privilege
+-- risk: whether the installed version is trusted (installable without
+-- superuser; the trusted column is PostgreSQL 13+, and every extension needed
+-- a superuser before), whether its owner is a superuser, its C functions owned
+-- by non-superusers, and the non-superuser roles that can create objects in
+-- its schema, PUBLIC counting as one.
+SELECT
+  e.extname::text AS extension_name
+  , e.extversion::text AS extension_version
+  , n.nspname::text AS schema_name
+  , o.rolname::text AS owner_name
+  , o.rolsuper AS owner_superuser
+  , coalesce((to_jsonb(v) ->> 'trusted')::boolean, false) AS trusted
+  , (
+    SELECT count(*)::int
+    FROM pg_catalog.pg_depend AS d
+    JOIN pg_catalog.pg_proc AS p ON p.oid = d.objid
+    JOIN pg_catalog.pg_language AS l ON l.oid = p.prolang
+    JOIN pg_catalog.pg_roles AS po ON po.oid = p.proowner
+    WHERE
+      d.classid = 'pg_catalog.pg_proc'::regclass
+      AND d.refclassid = 'pg_catalog.pg_extension'::regclass
+      AND d.refobjid = e.oid
+      AND d.deptype = 'e'
+      AND l.lanname = 'c'
+      AND NOT po.rolsuper
+  ) AS non_superuser_c_functions
+  , coalesce((
+    SELECT string_agg(w.name, ', ' ORDER BY w.name <> 'PUBLIC', w.name)
+    FROM (
+      SELECT 'PUBLIC'::text AS name
+      WHERE has_schema_privilege('public', n.oid, 'CREATE')
+      UNION ALL
+      SELECT r.rolname::text
+      FROM pg_catalog.pg_roles AS r
+      WHERE
+        NOT r.rolsuper
+        AND r.rolname NOT LIKE 'pg\_%'
+        AND NOT has_schema_privilege('public', n.oid, 'CREATE')
+        AND has_schema_privilege(r.oid, n.oid, 'CREATE')
+    ) AS w
+  ), '')::text AS schema_writers
+FROM pg_catalog.pg_extension AS e
+JOIN pg_catalog.pg_namespace AS n ON n.oid = e.extnamespace
+JOIN pg_catalog.pg_roles AS o ON o.oid = e.extowner
+LEFT JOIN pg_catalog.pg_available_extension_versions AS v
+  ON v.name = e.extname AND v.version = e.extversion
+WHERE n.nspname <> 'pg_catalog'
+ORDER BY e.extname;
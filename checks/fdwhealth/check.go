@@ -0,0 +1,205 @@
+// Package fdwhealth implements checks for foreign servers, their user mappings, and optionally their reachability.
+package fdwhealth
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type FDWHealthQueries interface {
+	ForeignServers(context.Context) ([]db.ForeignServersRow, error)
+	PlaintextPasswordMappings(context.Context) ([]db.PlaintextPasswordMappingsRow, error)
+	ProbeForeignTable(context.Context, string) error
+}
+
+type checker struct {
+	queries FDWHealthQueries
+	// probe reads one row through a foreign table on each server to test
+	// connectivity. Off by default: it connects to and queries remote servers.
+	probe bool
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryConfigs,
+		CheckID:     "fdw-health",
+		Name:        "Foreign Data Wrappers",
+		Description: "Lists foreign servers, flags user mappings with plaintext passwords, and optionally probes remote connectivity",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries FDWHealthQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries: queries,
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			c.probe = myCfg["probe"] == "true"
+		}
+	}
+	return c
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	servers, err := c.queries.ForeignServers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	if len(servers) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       "foreign-servers",
+			Name:     "Foreign Servers",
+			Severity: check.SeverityOK,
+			Details:  "No foreign servers defined",
+		})
+		return report, nil
+	}
+
+	mappings, err := c.queries.PlaintextPasswordMappings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	checkForeignServers(servers, report)
+	checkPlaintextPasswords(mappings, report)
+	if c.probe {
+		c.checkConnectivity(ctx, servers, report)
+	}
+
+	return report, nil
+}
+
+func checkForeignServers(rows []db.ForeignServersRow, report *check.Report) {
+	tableRows := make([]check.TableRow, 0, len(rows))
+	for _, row := range rows {
+		options := row.Options
+		if options == "" {
+			options = "-"
+		}
+		tableRows = append(tableRows, check.TableRow{
+			Cells: []string{
+				row.ServerName,
+				row.WrapperName,
+				options,
+				check.FormatNumber(row.UserMappings),
+				check.FormatNumber(row.ForeignTables),
+			},
+			Severity: check.SeverityOK,
+		})
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       "foreign-servers",
+		Name:     "Foreign Servers",
+		Severity: check.SeverityOK,
+		Details:  fmt.Sprintf("%d foreign server(s) defined", len(rows)),
+		Table: &check.Table{
+			Headers:   []string{"Server", "Wrapper", "Options", "User Mappings", "Foreign Tables"},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignLeft, check.AlignLeft, check.AlignRight, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.NotSensitive, check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	})
+}
+
+func checkPlaintextPasswords(rows []db.PlaintextPasswordMappingsRow, report *check.Report) {
+	if len(rows) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       "plaintext-passwords",
+			Name:     "User Mapping Passwords",
+			Severity: check.SeverityOK,
+			Details:  "No visible user mapping stores a password in its options",
+		})
+		return
+	}
+
+	tableRows := make([]check.TableRow, 0, len(rows))
+	for _, row := range rows {
+		tableRows = append(tableRows, check.TableRow{
+			Cells:    []string{row.ServerName, row.UserName},
+			Severity: check.SeverityWarn,
+		})
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       "plaintext-passwords",
+		Name:     "User Mapping Passwords",
+		Severity: check.SeverityWarn,
+		Details: fmt.Sprintf("Found %d user mapping(s) storing a password in plain text in the catalog, "+
+			"readable by superusers and the mapped user and included in pg_dump output", len(rows)),
+		Table: &check.Table{
+			Headers:   []string{"Server", "User"},
+			Key:       []int{0, 1},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	})
+}
+
+// checkConnectivity probes each server through its first foreign table. Servers
+// without foreign tables cannot be probed without creating one, so they are
+// only counted.
+func (c *checker) checkConnectivity(ctx context.Context, servers []db.ForeignServersRow, report *check.Report) {
+	var tableRows []check.TableRow
+	probed, skipped := 0, 0
+	for _, server := range servers {
+		if server.ProbeTable == "" {
+			skipped++
+			continue
+		}
+		probed++
+		if err := c.queries.ProbeForeignTable(ctx, server.ProbeTable); err != nil {
+			tableRows = append(tableRows, check.TableRow{
+				Cells:    []string{server.ServerName, server.ProbeTable, err.Error()},
+				Severity: check.SeverityFail,
+			})
+		}
+	}
+
+	notProbed := ""
+	if skipped > 0 {
+		notProbed = fmt.Sprintf("; %d server(s) without foreign tables not probed", skipped)
+	}
+
+	if len(tableRows) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       "connectivity",
+			Name:     "Remote Connectivity",
+			Severity: check.SeverityOK,
+			Details:  fmt.Sprintf("%d foreign server(s) reachable%s", probed, notProbed),
+		})
+		return
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       "connectivity",
+		Name:     "Remote Connectivity",
+		Severity: check.SeverityFail,
+		Details: fmt.Sprintf("%d of %d probed foreign server(s) failed to answer a one-row read%s",
+			len(tableRows), probed, notProbed),
+		Table: &check.Table{
+			Headers:   []string{"Server", "Probe Table", "Error"},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.SensitiveIdentifier, check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	})
+}
@@ -0,0 +1,176 @@
+package fdwhealth_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/checks/fdwhealth"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockQueryer struct {
+	servers     []db.ForeignServersRow
+	mappings    []db.PlaintextPasswordMappingsRow
+	probeErrs   map[string]error
+	probed      []string
+	serversErr  error
+	mappingsErr error
+}
+
+func (m *mockQueryer) ForeignServers(context.Context) ([]db.ForeignServersRow, error) {
+	return m.servers, m.serversErr
+}
+
+func (m *mockQueryer) PlaintextPasswordMappings(context.Context) ([]db.PlaintextPasswordMappingsRow, error) {
+	return m.mappings, m.mappingsErr
+}
+
+func (m *mockQueryer) ProbeForeignTable(_ context.Context, table string) error {
+	m.probed = append(m.probed, table)
+	return m.probeErrs[table]
+}
+
+func server(name, probeTable string) db.ForeignServersRow {
+	return db.ForeignServersRow{
+		ServerName:    name,
+		WrapperName:   "postgres_fdw",
+		Options:       "host=" + name + ".internal, dbname=app",
+		UserMappings:  1,
+		ForeignTables: 3,
+		ProbeTable:    probeTable,
+	}
+}
+
+var probeConfig = check.Config{"fdw-health": {"probe": "true"}}
+
+func findingByID(t *testing.T, report *check.Report, id string) check.Finding {
+	t.Helper()
+	for _, f := range report.Results {
+		if f.ID == id {
+			return f
+		}
+	}
+	t.Fatalf("finding %q not found", id)
+	return check.Finding{}
+}
+
+func TestFDWHealth_NoServers(t *testing.T) {
+	t.Parallel()
+
+	report, err := fdwhealth.New(&mockQueryer{}, probeConfig).Check(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, report.Results, 1)
+	assert.Equal(t, check.SeverityOK, findingByID(t, report, "foreign-servers").Severity)
+}
+
+func TestFDWHealth_ListsServers(t *testing.T) {
+	t.Parallel()
+
+	mock := &mockQueryer{servers: []db.ForeignServersRow{server("shard1", "public.orders"), server("legacy", "")}}
+	mock.servers[1].Options = ""
+	report, err := fdwhealth.New(mock).Check(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, report.Results, 2, "connectivity is not probed by default")
+	assert.Empty(t, mock.probed)
+
+	finding := findingByID(t, report, "foreign-servers")
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	require.NotNil(t, finding.Table)
+	require.Len(t, finding.Table.Rows, 2)
+	assert.Equal(t, []string{"shard1", "postgres_fdw", "host=shard1.internal, dbname=app", "1", "3"}, finding.Table.Rows[0].Cells)
+	assert.Equal(t, "-", finding.Table.Rows[1].Cells[2])
+}
+
+func TestFDWHealth_PlaintextPasswords(t *testing.T) {
+	t.Parallel()
+
+	report, err := fdwhealth.New(&mockQueryer{
+		servers:  []db.ForeignServersRow{server("shard1", "public.orders")},
+		mappings: []db.PlaintextPasswordMappingsRow{{ServerName: "shard1", UserName: "app"}},
+	}).Check(context.Background())
+	require.NoError(t, err)
+
+	finding := findingByID(t, report, "plaintext-passwords")
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	require.NotNil(t, finding.Table)
+	assert.Equal(t, []string{"shard1", "app"}, finding.Table.Rows[0].Cells)
+}
+
+func TestFDWHealth_NoPlaintextPasswords(t *testing.T) {
+	t.Parallel()
+
+	report, err := fdwhealth.New(&mockQueryer{
+		servers: []db.ForeignServersRow{server("shard1", "public.orders")},
+	}).Check(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, check.SeverityOK, findingByID(t, report, "plaintext-passwords").Severity)
+}
+
+func TestFDWHealth_Connectivity(t *testing.T) {
+	t.Parallel()
+
+	mock := &mockQueryer{
+		servers: []db.ForeignServersRow{
+			server("shard1", "public.orders"),
+			server("shard2", `"Shard2".orders`),
+			server("empty", ""),
+		},
+		probeErrs: map[string]error{`"Shard2".orders`: fmt.Errorf("could not connect to server \"shard2\"")},
+	}
+	report, err := fdwhealth.New(mock, probeConfig).Check(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"public.orders", `"Shard2".orders`}, mock.probed, "servers without foreign tables are skipped")
+
+	finding := findingByID(t, report, "connectivity")
+	assert.Equal(t, check.SeverityFail, finding.Severity)
+	assert.Contains(t, finding.Details, "1 of 2 probed")
+	assert.Contains(t, finding.Details, "1 server(s) without foreign tables not probed")
+	require.NotNil(t, finding.Table)
+	require.Len(t, finding.Table.Rows, 1)
+	assert.Equal(t, []string{"shard2", `"Shard2".orders`, "could not connect to server \"shard2\""}, finding.Table.Rows[0].Cells)
+}
+
+func TestFDWHealth_ConnectivityOK(t *testing.T) {
+	t.Parallel()
+
+	report, err := fdwhealth.New(&mockQueryer{
+		servers: []db.ForeignServersRow{server("shard1", "public.orders")},
+	}, probeConfig).Check(context.Background())
+	require.NoError(t, err)
+
+	finding := findingByID(t, report, "connectivity")
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "1 foreign server(s) reachable", finding.Details)
+}
+
+func TestFDWHealth_QueryError(t *testing.T) {
+	t.Parallel()
+
+	_, err := fdwhealth.New(&mockQueryer{serversErr: fmt.Errorf("connection refused")}).Check(context.Background())
+	require.ErrorContains(t, err, "fdw-health")
+
+	_, err = fdwhealth.New(&mockQueryer{
+		servers:     []db.ForeignServersRow{server("shard1", "public.orders")},
+		mappingsErr: fmt.Errorf("connection refused"),
+	}).Check(context.Background())
+	require.ErrorContains(t, err, "fdw-health")
+}
+
+func TestFDWHealth_Metadata(t *testing.T) {
+	t.Parallel()
+
+	m := fdwhealth.Metadata()
+	assert.Equal(t, "fdw-health", m.CheckID)
+	assert.Equal(t, check.CategoryConfigs, m.Category)
+	assert.NotEmpty(t, m.Name)
+	assert.NotEmpty(t, m.Readme)
+	assert.NotEmpty(t, m.SQL)
+}
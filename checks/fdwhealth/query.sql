@@ -0,0 +1,37 @@
+-- name: ForeignServers :many
+-- Foreign servers with their wrapper, options (never passwords), how many user
+-- mappings and foreign tables use them, and one foreign table to probe
+-- connectivity through (empty when the server has none).
+SELECT
+  s.srvname::text AS server_name
+  , w.fdwname::text AS wrapper_name
+  , coalesce(array_to_string(ARRAY(
+    SELECT o
+    FROM unnest(s.srvoptions) AS o
+    WHERE o NOT LIKE 'password=%'
+  ), ', '), '')::text AS options
+  , (SELECT count(*) FROM pg_catalog.pg_user_mappings AS um WHERE um.srvid = s.oid) AS user_mappings
+  , (SELECT count(*) FROM pg_catalog.pg_foreign_table AS ft WHERE ft.ftserver = s.oid) AS foreign_tables
+  , coalesce((
+    SELECT format('%I.%I', n.nspname, c.relname)
+    FROM pg_catalog.pg_foreign_table AS ft
+    INNER JOIN pg_catalog.pg_class AS c ON ft.ftrelid = c.oid
+    INNER JOIN pg_catalog.pg_namespace AS n ON c.relnamespace = n.oid
+    WHERE ft.ftserver = s.oid
+    ORDER BY c.oid
+    LIMIT 1
+  ), '')::text AS probe_table
+FROM pg_catalog.pg_foreign_server AS s
+INNER JOIN pg_catalog.pg_foreign_data_wrapper AS w ON s.srvfdw = w.oid
+ORDER BY s.srvname;
+
+-- name: PlaintextPasswordMappings :many
+-- User mappings that store a password in their options. pg_user_mappings only
+-- shows options to superusers and to the mapped user, so other mappings are
+-- not visible to an unprivileged role.
+SELECT
+  srvname::text AS server_name
+  , usename::text AS user_name
+FROM pg_catalog.pg_user_mappings
+WHERE EXISTS (SELECT 1 FROM unnest(umoptions) AS o WHERE o LIKE 'password=%')
+ORDER BY srvname, usename;
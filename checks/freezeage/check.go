@@ -103,10 +103,12 @@ func checkDatabaseFreezeAge(rows []db.DatabaseFreezeAgeRow, report *check.Report
 		}
 
 		report.AddFinding(check.Finding{
-			ID:       "database-freeze-age",
-			Name:     "Database Freeze Age",
-			Severity: check.SeverityOK,
-			Details:  fmt.Sprintf("All databases within safe range. Oldest: %s at %s transactions", oldestDB, formatAge(oldestAge)),
+			ID:                 "database-freeze-age",
+			Tags:               []string{check.TagWraparound},
+			Name:               "Database Freeze Age",
+			Severity:           check.SeverityOK,
+			Details:            fmt.Sprintf("All databases within safe range. Oldest: %s at %s transactions", oldestDB, formatAge(oldestAge)),
+			DetailsSensitivity: check.SensitiveIdentifier,
 		})
 		return
 	}
@@ -148,12 +150,17 @@ func checkDatabaseFreezeAge(rows []db.DatabaseFreezeAgeRow, report *check.Report
 
 	report.AddFinding(check.Finding{
 		ID:       "database-freeze-age",
+		Tags:     []string{check.TagWraparound},
 		Name:     "Database Freeze Age",
 		Severity: severity,
 		Details:  fmt.Sprintf("Found %d database(s) with high transaction ID age", len(critical)+len(warning)),
+		// Wraparound stops the database from accepting writes.
+		Priority: check.PriorityUrgent,
 		Table: &check.Table{
-			Headers: []string{"Database", "Age", "% to Limit", "Freeze Max Age"},
-			Rows:    tableRows,
+			Headers:   []string{"Database", "Age", "% to Limit", "Freeze Max Age"},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignRight, check.AlignRight, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier},
+			Rows:      tableRows,
 		},
 	})
 }
@@ -174,6 +181,7 @@ func checkTableFreezeAge(rows []db.TableFreezeAgeRow, report *check.Report) {
 	if len(critical) == 0 && len(warning) == 0 {
 		report.AddFinding(check.Finding{
 			ID:       "table-freeze-age",
+			Tags:     []string{check.TagWraparound},
 			Name:     "Table Freeze Age",
 			Severity: check.SeverityOK,
 			Details:  "All tables within safe transaction ID age range",
@@ -216,12 +224,15 @@ func checkTableFreezeAge(rows []db.TableFreezeAgeRow, report *check.Report) {
 
 	report.AddFinding(check.Finding{
 		ID:       "table-freeze-age",
+		Tags:     []string{check.TagWraparound},
 		Name:     "Table Freeze Age",
 		Severity: severity,
 		Details:  fmt.Sprintf("Found %d table(s) with high transaction ID age", len(critical)+len(warning)),
 		Table: &check.Table{
-			Headers: []string{"Table", "Age", "Size", "Last Vacuum", "Vacuum Count"},
-			Rows:    tableRows,
+			Headers:   []string{"Table", "Age", "Size", "Last Vacuum", "Vacuum Count"},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignRight, check.AlignRight, check.AlignLeft, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier},
+			Rows:      tableRows,
 		},
 	})
 }
@@ -100,6 +100,7 @@ func TestFreezeAge_AllHealthy(t *testing.T) {
 
 	for _, finding := range report.Results {
 		require.Equal(t, check.SeverityOK, finding.Severity)
+		require.True(t, finding.HasTag(check.TagWraparound), "%s is tagged even when passing", finding.ID)
 	}
 }
 
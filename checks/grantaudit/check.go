@@ -0,0 +1,227 @@
+// Package grantaudit implements a check for write privileges granted too broadly on tables.
+package grantaudit
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+// publicGrantee is how the query names grants to every role.
+const publicGrantee = "PUBLIC"
+
+type GrantAuditQueries interface {
+	TableGrants(ctx context.Context, grantees []string) ([]db.TableGrantsRow, error)
+}
+
+type checker struct {
+	queries    GrantAuditQueries
+	grantees   []string            // default: PUBLIC
+	privileges map[string]struct{} // default: INSERT, UPDATE, DELETE, TRUNCATE
+	allowed    []allowRule
+}
+
+// allowRule exempts a grant from the audit: "[grantee:]schema.table", where
+// table may be "*" for the whole schema and an empty grantee matches any.
+type allowRule struct {
+	grantee string
+	schema  string
+	table   string
+}
+
+func (r allowRule) matches(row db.TableGrantsRow) bool {
+	return (r.grantee == "" || r.grantee == row.Grantee) &&
+		r.schema == row.SchemaName &&
+		(r.table == "*" || r.table == row.TableName)
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategorySchema,
+		CheckID:     "grant-audit",
+		Name:        "Table Grant Audit",
+		Description: "Finds tables granting write privileges to PUBLIC or other roles a policy says should not have them",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries GrantAuditQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries:    queries,
+		grantees:   []string{publicGrantee},
+		privileges: map[string]struct{}{"INSERT": {}, "UPDATE": {}, "DELETE": {}, "TRUNCATE": {}},
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			if v, ok := myCfg["grantees"]; ok {
+				if grantees := parseGrantees(v); len(grantees) > 0 {
+					c.grantees = grantees
+				}
+			}
+			if v, ok := myCfg["privileges"]; ok {
+				if privileges := parsePrivileges(v); len(privileges) > 0 {
+					c.privileges = privileges
+				}
+			}
+			if v, ok := myCfg["allow"]; ok {
+				c.allowed = parseAllowRules(v)
+			}
+		}
+	}
+	return c
+}
+
+// splitList splits a comma-separated config value, dropping blanks.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseGrantees reads the roles to audit. No role can be named "public", so
+// any spelling of it means PUBLIC.
+func parseGrantees(value string) []string {
+	grantees := splitList(value)
+	for i, grantee := range grantees {
+		if strings.EqualFold(grantee, publicGrantee) {
+			grantees[i] = publicGrantee
+		}
+	}
+	return grantees
+}
+
+func parsePrivileges(value string) map[string]struct{} {
+	privileges := map[string]struct{}{}
+	for _, privilege := range splitList(value) {
+		privileges[strings.ToUpper(privilege)] = struct{}{}
+	}
+	return privileges
+}
+
+// parseAllowRules reads "[grantee:]schema.table" entries. Unqualified tables
+// are in the public schema.
+func parseAllowRules(value string) []allowRule {
+	var rules []allowRule
+	for _, entry := range splitList(value) {
+		var rule allowRule
+		if grantee, table, ok := strings.Cut(entry, ":"); ok {
+			if grantees := parseGrantees(grantee); len(grantees) > 0 {
+				rule.grantee = grantees[0]
+			}
+			entry = table
+		}
+		rule.schema, rule.table = "public", entry
+		if schema, table, ok := strings.Cut(entry, "."); ok {
+			rule.schema, rule.table = schema, table
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	grants, err := c.queries.TableGrants(ctx, c.grantees)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	var tableRows []check.TableRow
+	for _, grant := range grants {
+		privileges := c.flagged(grant.Privileges)
+		if len(privileges) == 0 || c.allows(grant) {
+			continue
+		}
+		grantOption := "-"
+		if grant.Grantable {
+			grantOption = "yes"
+		}
+		tableRows = append(tableRows, check.TableRow{
+			Cells: []string{
+				grant.SchemaName + "." + grant.TableName,
+				grant.Grantee,
+				strings.Join(privileges, ", "),
+				grantOption,
+			},
+			Severity: check.SeverityWarn,
+		})
+	}
+
+	grantees := c.granteesLabel()
+	if len(tableRows) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  fmt.Sprintf("No tables grant write privileges to %s", grantees),
+		})
+		return report, nil
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: check.SeverityWarn,
+		Details: fmt.Sprintf("%d table grant(s) give write privileges to %s; every role holding them can change the data. "+
+			"Revoke them, or list intended grants under allow",
+			len(tableRows), grantees),
+		Table: &check.Table{
+			Headers:   []string{"Table", "Grantee", "Privileges", "Grant Option"},
+			Key:       []int{0, 1},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	})
+
+	return report, nil
+}
+
+// granteesLabel names the audited roles in Details. Role names other than
+// PUBLIC are identifiers that --redact must be able to strip, so they are only
+// shown in the table.
+func (c *checker) granteesLabel() string {
+	if len(c.grantees) == 1 && c.grantees[0] == publicGrantee {
+		return publicGrantee
+	}
+	return fmt.Sprintf("the %d audited role(s)", len(c.grantees))
+}
+
+// flagged returns the audited privileges among privileges, in their order.
+func (c *checker) flagged(privileges []string) []string {
+	var out []string
+	for _, privilege := range privileges {
+		if _, ok := c.privileges[privilege]; ok {
+			out = append(out, privilege)
+		}
+	}
+	return out
+}
+
+func (c *checker) allows(grant db.TableGrantsRow) bool {
+	for _, rule := range c.allowed {
+		if rule.matches(grant) {
+			return true
+		}
+	}
+	return false
+}
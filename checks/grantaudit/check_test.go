@@ -0,0 +1,115 @@
+package grantaudit_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/grantaudit"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeQueries struct {
+	grants   checktest.Result[[]db.TableGrantsRow]
+	grantees *[]string // records the roles the check asked for
+}
+
+func (f fakeQueries) TableGrants(_ context.Context, grantees []string) ([]db.TableGrantsRow, error) {
+	if f.grantees != nil {
+		*f.grantees = grantees
+	}
+	return f.grants.Get()
+}
+
+func grant(table, grantee string, privileges ...string) db.TableGrantsRow {
+	return db.TableGrantsRow{SchemaName: "public", TableName: table, Grantee: grantee, Privileges: privileges}
+}
+
+func newChecker(cfg check.Config, grants ...db.TableGrantsRow) check.Checker {
+	return grantaudit.New(fakeQueries{grants: checktest.Returns(grants)}, cfg)
+}
+
+func TestGrantAudit_NoBroadGrants(t *testing.T) {
+	t.Parallel()
+
+	var grantees []string
+	checker := grantaudit.New(fakeQueries{grants: checktest.Returns[[]db.TableGrantsRow](nil), grantees: &grantees})
+	finding := checktest.OnlyFinding(t, checktest.Run(t, checker))
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "No tables grant write privileges to PUBLIC", finding.Details)
+	assert.Equal(t, []string{"PUBLIC"}, grantees, "PUBLIC is audited by default")
+}
+
+func TestGrantAudit_PublicWriteGrants(t *testing.T) {
+	t.Parallel()
+
+	withOption := grant("orders", "PUBLIC", "DELETE", "INSERT", "SELECT", "UPDATE")
+	withOption.Grantable = true
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(nil,
+		withOption,
+		grant("countries", "PUBLIC", "SELECT"),
+		grant("events", "PUBLIC", "TRUNCATE"),
+	)))
+
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Contains(t, finding.Details, "2 table grant(s) give write privileges to PUBLIC")
+	assert.Equal(t, [][]string{
+		{"public.orders", "PUBLIC", "DELETE, INSERT, UPDATE", "yes"},
+		{"public.events", "PUBLIC", "TRUNCATE", "-"},
+	}, checktest.Rows(finding), "SELECT alone is not audited")
+}
+
+func TestGrantAudit_Config(t *testing.T) {
+	t.Parallel()
+
+	cfg := check.Config{"grant-audit": {
+		"grantees":   "public, reporting",
+		"privileges": "insert, select",
+		"allow":      "reporting:reporting.*, audit_log",
+	}}
+
+	var grantees []string
+	checker := grantaudit.New(fakeQueries{
+		grantees: &grantees,
+		grants: checktest.Returns([]db.TableGrantsRow{
+			grant("orders", "reporting", "SELECT"),
+			grant("audit_log", "PUBLIC", "INSERT"),
+			{SchemaName: "reporting", TableName: "daily", Grantee: "reporting", Privileges: []string{"INSERT"}},
+			{SchemaName: "reporting", TableName: "daily", Grantee: "PUBLIC", Privileges: []string{"DELETE", "INSERT"}},
+		}),
+	}, cfg)
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, checker))
+	assert.Equal(t, []string{"PUBLIC", "reporting"}, grantees, "any spelling of public means PUBLIC")
+	assert.Equal(t, [][]string{
+		{"public.orders", "reporting", "SELECT", "-"},
+		{"reporting.daily", "PUBLIC", "INSERT", "-"},
+	}, checktest.Rows(finding), "allow entries match their grantee, schema, and table")
+	assert.Contains(t, finding.Details, "to the 2 audited role(s)", "role names stay out of Details")
+}
+
+func TestGrantAudit_EmptyConfigKeepsDefaults(t *testing.T) {
+	t.Parallel()
+
+	cfg := check.Config{"grant-audit": {"grantees": " , ", "privileges": ""}}
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(cfg, grant("orders", "PUBLIC", "UPDATE"))))
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Contains(t, finding.Details, "to PUBLIC")
+}
+
+func TestGrantAudit_QueryError(t *testing.T) {
+	t.Parallel()
+
+	checktest.RequireError(t, grantaudit.New(fakeQueries{
+		grants: checktest.Fails[[]db.TableGrantsRow](fmt.Errorf("permission denied")),
+	}))
+}
+
+func TestGrantAudit_Metadata(t *testing.T) {
+	t.Parallel()
+
+	checktest.RequireMetadata(t, grantaudit.Metadata(), "grant-audit", check.CategorySchema)
+}
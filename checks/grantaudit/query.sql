@@ -0,0 +1,26 @@
+-- name: TableGrants :many
+-- Privileges on tables, views, and foreign tables held by the given roles, one
+-- row per table and grantee. 'PUBLIC' matches grants to every role. Owners'
+-- own privileges are implicit and not listed; tables still on the default
+-- ACL (relacl IS NULL) grant nothing to anyone else.
+SELECT
+  n.nspname::text AS schema_name
+  , c.relname::text AS table_name
+  , g.grantee
+  , array_agg(a.privilege_type ORDER BY a.privilege_type)::text[] AS privileges
+  , coalesce(bool_or(a.is_grantable), false) AS grantable
+FROM pg_class AS c
+JOIN pg_namespace AS n ON n.oid = c.relnamespace
+CROSS JOIN LATERAL aclexplode(c.relacl) AS a
+CROSS JOIN LATERAL (
+  SELECT
+    CASE WHEN a.grantee = 0 THEN 'PUBLIC' ELSE pg_get_userbyid(a.grantee) END::text AS grantee
+) AS g
+WHERE
+  c.relkind IN ('r', 'p', 'v', 'f')
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
+  AND n.nspname NOT LIKE 'pg_temp_%'
+  AND a.grantee <> c.relowner
+  AND g.grantee = ANY(sqlc.arg(grantees)::text[])
+GROUP BY n.nspname, c.relname, g.grantee
+ORDER BY n.nspname, c.relname, g.grantee;
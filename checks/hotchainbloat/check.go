@@ -0,0 +1,180 @@
+// Package hotchainbloat implements an opt-in check measuring exact table bloat with pgstattuple.
+package hotchainbloat
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strconv"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+const (
+	// Same dead tuple thresholds as table-bloat, applied to the exact share of
+	// the table taken by dead tuples and free space.
+	bloatWarnPercent = 20
+	bloatFailPercent = 40
+)
+
+type HotChainBloatQueries interface {
+	HasPgstattuple(context.Context) (bool, error)
+	PreciseTableBloat(context.Context, int32) ([]db.PreciseTableBloatRow, error)
+}
+
+type checker struct {
+	queries HotChainBloatQueries
+	// scan runs pgstattuple, which reads every page of each table. Off by
+	// default because it is slow and I/O heavy on large tables.
+	scan      bool
+	maxTables int32 // default: 5
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryVacuum,
+		CheckID:     "hot-chain-bloat",
+		Name:        "Precise Table Bloat (slow, opt-in)",
+		Description: "Measures exact dead tuple and free space bloat with pgstattuple; accurate but scans whole tables, so it only runs when enabled",
+		Readme:      readme,
+		SQL:         querySQL,
+		Heavy:       true,
+	}
+}
+
+func New(queries HotChainBloatQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries:   queries,
+		maxTables: 5,
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			c.scan = myCfg["scan"] == "true"
+			if v, ok := myCfg["max_tables"]; ok {
+				if n, err := strconv.ParseInt(v, 10, 32); err == nil && n > 0 {
+					c.maxTables = int32(n)
+				}
+			}
+		}
+	}
+	return c
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	if !c.scan {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  "Not enabled: pgstattuple reads every page of the scanned tables; set scan: true for hot-chain-bloat in --config to run it",
+		})
+		return report, nil
+	}
+
+	installed, err := c.queries.HasPgstattuple(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+	if !installed {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  "pgstattuple is not installed in this database (CREATE EXTENSION pgstattuple); see table-bloat for the estimate",
+		})
+		return report, nil
+	}
+
+	rows, err := c.queries.PreciseTableBloat(ctx, c.maxTables)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	if len(rows) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  "No tables of 8MB or more have dead tuples to measure",
+		})
+		return report, nil
+	}
+
+	severity := check.SeverityOK
+	flagged := 0
+	tableRows := make([]check.TableRow, 0, len(rows))
+	for _, row := range rows {
+		size := check.Int8ToInt64(row.TableLen)
+		bloat := check.Int8ToInt64(row.DeadTupleLen) + check.Int8ToInt64(row.FreeSpace)
+		bloatPercent := 0.0
+		if size > 0 {
+			bloatPercent = float64(bloat) / float64(size) * 100
+		}
+
+		rowSeverity := check.SeverityOK
+		switch {
+		case bloatPercent >= bloatFailPercent:
+			rowSeverity = check.SeverityFail
+		case bloatPercent >= bloatWarnPercent:
+			rowSeverity = check.SeverityWarn
+		}
+		if rowSeverity > check.SeverityOK {
+			flagged++
+		}
+		if rowSeverity > severity {
+			severity = rowSeverity
+		}
+
+		hot := "-"
+		if row.HotUpdatePercent.Valid {
+			hot = fmt.Sprintf("%.1f%%", row.HotUpdatePercent.Float64)
+		}
+		tableRows = append(tableRows, check.TableRow{
+			Cells: []string{
+				row.TableName,
+				check.FormatBytes(size),
+				fmt.Sprintf("%.1f%%", row.DeadTuplePercent.Float64),
+				fmt.Sprintf("%.1f%%", row.FreePercent.Float64),
+				check.FormatBytes(bloat),
+				fmt.Sprintf("%.1f%%", bloatPercent),
+				hot,
+			},
+			Severity: rowSeverity,
+		})
+	}
+
+	details := fmt.Sprintf("Measured %d table(s) with pgstattuple; none has %d%% or more of its size in dead tuples and free space",
+		len(rows), bloatWarnPercent)
+	if flagged > 0 {
+		details = fmt.Sprintf("Measured %d table(s) with pgstattuple; %d have %d%% or more of their size in dead tuples and free space",
+			len(rows), flagged, bloatWarnPercent)
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: severity,
+		Details:  details,
+		Table: &check.Table{
+			Headers:   []string{"Table", "Size", "Dead %", "Free %", "Bloat", "Bloat %", "HOT Updates"},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignRight, check.AlignRight, check.AlignRight, check.AlignRight, check.AlignRight, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	})
+
+	return report, nil
+}
@@ -0,0 +1,170 @@
+package hotchainbloat_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/checks/hotchainbloat"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockQueryer struct {
+	installed    bool
+	rows         []db.PreciseTableBloatRow
+	maxTables    int32
+	installedErr error
+	rowsErr      error
+}
+
+func (m *mockQueryer) HasPgstattuple(context.Context) (bool, error) {
+	return m.installed, m.installedErr
+}
+
+func (m *mockQueryer) PreciseTableBloat(_ context.Context, maxTables int32) ([]db.PreciseTableBloatRow, error) {
+	m.maxTables = maxTables
+	return m.rows, m.rowsErr
+}
+
+var scanConfig = check.Config{"hot-chain-bloat": {"scan": "true"}}
+
+// bloatRow builds a 100MiB table with the given dead and free percentages.
+func bloatRow(table string, deadPercent, freePercent float64) db.PreciseTableBloatRow {
+	size := int64(100 * check.MiB)
+	return db.PreciseTableBloatRow{
+		TableName:        table,
+		TableLen:         pgtype.Int8{Int64: size, Valid: true},
+		DeadTupleLen:     pgtype.Int8{Int64: int64(float64(size) * deadPercent / 100), Valid: true},
+		DeadTuplePercent: pgtype.Float8{Float64: deadPercent, Valid: true},
+		FreeSpace:        pgtype.Int8{Int64: int64(float64(size) * freePercent / 100), Valid: true},
+		FreePercent:      pgtype.Float8{Float64: freePercent, Valid: true},
+		HotUpdatePercent: pgtype.Float8{Float64: 12.5, Valid: true},
+	}
+}
+
+func TestHotChainBloat_DisabledByDefault(t *testing.T) {
+	t.Parallel()
+
+	mock := &mockQueryer{installed: true, rows: []db.PreciseTableBloatRow{bloatRow("public.events", 60, 10)}}
+	report, err := hotchainbloat.New(mock).Check(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, report.Results, 1)
+	assert.Equal(t, check.SeverityOK, report.Results[0].Severity)
+	assert.Contains(t, report.Results[0].Details, "Not enabled")
+	assert.Zero(t, mock.maxTables, "pgstattuple must not run unless enabled")
+}
+
+func TestHotChainBloat_ExtensionMissing(t *testing.T) {
+	t.Parallel()
+
+	report, err := hotchainbloat.New(&mockQueryer{}, scanConfig).Check(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, report.Results, 1)
+	assert.Equal(t, check.SeverityOK, report.Results[0].Severity)
+	assert.Contains(t, report.Results[0].Details, "pgstattuple is not installed")
+}
+
+func TestHotChainBloat_NoTables(t *testing.T) {
+	t.Parallel()
+
+	report, err := hotchainbloat.New(&mockQueryer{installed: true}, scanConfig).Check(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, report.Results, 1)
+	assert.Equal(t, check.SeverityOK, report.Results[0].Severity)
+}
+
+func TestHotChainBloat_Thresholds(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		row      db.PreciseTableBloatRow
+		expected check.Severity
+	}{
+		{"healthy", bloatRow("public.events", 5, 5), check.SeverityOK},
+		{"dead and free add up", bloatRow("public.events", 12, 10), check.SeverityWarn},
+		{"mostly dead", bloatRow("public.events", 45, 0), check.SeverityFail},
+		{"empty table", db.PreciseTableBloatRow{TableName: "public.empty"}, check.SeverityOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			report, err := hotchainbloat.New(&mockQueryer{
+				installed: true,
+				rows:      []db.PreciseTableBloatRow{tt.row},
+			}, scanConfig).Check(context.Background())
+			require.NoError(t, err)
+
+			require.Len(t, report.Results, 1)
+			assert.Equal(t, tt.expected, report.Results[0].Severity)
+		})
+	}
+}
+
+func TestHotChainBloat_Table(t *testing.T) {
+	t.Parallel()
+
+	noUpdates := bloatRow("public.logs", 5, 5)
+	noUpdates.HotUpdatePercent = pgtype.Float8{}
+	report, err := hotchainbloat.New(&mockQueryer{
+		installed: true,
+		rows:      []db.PreciseTableBloatRow{bloatRow("public.events", 30, 20), noUpdates},
+	}, scanConfig).Check(context.Background())
+	require.NoError(t, err)
+
+	finding := report.Results[0]
+	assert.Equal(t, check.SeverityFail, finding.Severity)
+	assert.Contains(t, finding.Details, "Measured 2 table(s)")
+	assert.Contains(t, finding.Details, "1 have 20%")
+	require.NotNil(t, finding.Table)
+	require.Len(t, finding.Table.Rows, 2, "healthy measured tables are listed too")
+	assert.Equal(t, []string{"public.events", "100.0MiB", "30.0%", "20.0%", "50.0MiB", "50.0%", "12.5%"}, finding.Table.Rows[0].Cells)
+	assert.Equal(t, check.SeverityOK, finding.Table.Rows[1].Severity)
+	assert.Equal(t, "-", finding.Table.Rows[1].Cells[6])
+}
+
+func TestHotChainBloat_MaxTables(t *testing.T) {
+	t.Parallel()
+
+	mock := &mockQueryer{installed: true}
+	_, err := hotchainbloat.New(mock, scanConfig).Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(5), mock.maxTables)
+
+	mock = &mockQueryer{installed: true}
+	cfg := check.Config{"hot-chain-bloat": {"scan": "true", "max_tables": "20"}}
+	_, err = hotchainbloat.New(mock, cfg).Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(20), mock.maxTables)
+}
+
+func TestHotChainBloat_QueryError(t *testing.T) {
+	t.Parallel()
+
+	_, err := hotchainbloat.New(&mockQueryer{installedErr: fmt.Errorf("connection refused")}, scanConfig).Check(context.Background())
+	require.ErrorContains(t, err, "hot-chain-bloat")
+
+	_, err = hotchainbloat.New(&mockQueryer{installed: true, rowsErr: fmt.Errorf("canceling statement due to statement timeout")}, scanConfig).Check(context.Background())
+	require.ErrorContains(t, err, "hot-chain-bloat")
+}
+
+func TestHotChainBloat_Metadata(t *testing.T) {
+	t.Parallel()
+
+	m := hotchainbloat.Metadata()
+	assert.Equal(t, "hot-chain-bloat", m.CheckID)
+	assert.Equal(t, check.CategoryVacuum, m.Category)
+	assert.NotEmpty(t, m.Name)
+	assert.NotEmpty(t, m.Readme)
+	assert.NotEmpty(t, m.SQL)
+	assert.True(t, m.Heavy, "pgstattuple reads every page of each table")
+}
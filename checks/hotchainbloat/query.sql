@@ -0,0 +1,37 @@
+-- name: HasPgstattuple :one
+-- Checks if the pgstattuple extension is installed.
+SELECT EXISTS(
+  SELECT 1 FROM pg_extension
+  WHERE extname = 'pgstattuple'
+);
+
+-- name: PreciseTableBloat :many
+-- Exact dead tuple and free space figures from pgstattuple() for the tables
+-- (8MB or larger) with the most dead tuples. pgstattuple reads every page of
+-- each table, so the number of tables scanned is capped by max_tables.
+WITH candidates AS (
+  SELECT
+    s.relid
+    , (s.schemaname || '.' || s.relname)::text AS table_name
+    , CASE
+      WHEN s.n_tup_upd > 0
+        THEN (s.n_tup_hot_upd::float8 / s.n_tup_upd * 100)
+    END AS hot_update_percent
+  FROM pg_stat_user_tables AS s
+  WHERE
+    s.n_dead_tup > 0
+    AND pg_relation_size(s.relid) >= 8 * 1024 * 1024
+  ORDER BY s.n_dead_tup DESC
+  LIMIT sqlc.arg(max_tables)::int
+)
+SELECT
+  c.table_name
+  , st.table_len
+  , st.dead_tuple_len
+  , st.dead_tuple_percent
+  , st.free_space
+  , st.free_percent
+  , c.hot_update_percent
+FROM candidates AS c
+CROSS JOIN LATERAL pgstattuple(c.relid) AS st
+ORDER BY (st.dead_tuple_len + st.free_space) DESC;
@@ -0,0 +1,157 @@
+// Package hugepages implements a check for huge pages requested, in use, and sized for the server's shared memory.
+package hugepages
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strconv"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type HugePagesQueries interface {
+	HugePages(context.Context) (db.HugePagesRow, error)
+}
+
+type checker struct {
+	queries  HugePagesQueries
+	minBytes int64 // shared memory from which huge_pages = off warns; default: 8GiB
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryConfigs,
+		CheckID:     "hugepages",
+		Name:        "Huge Pages",
+		Description: "Checks that huge pages are requested for large shared memory, and in use rather than silently fallen back to regular pages",
+		Readme:      readme,
+		SQL:         querySQL,
+		ClusterWide: true,
+	}
+}
+
+func New(queries HugePagesQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries:  queries,
+		minBytes: 8 * check.GiB,
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			if v, ok := myCfg["min_shared_memory_gb"]; ok {
+				if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+					c.minBytes = n * check.GiB
+				}
+			}
+		}
+	}
+	return c
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	settings, err := c.queries.HugePages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	switch settings.HugePagesNeeded {
+	case 0:
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityNotApplicable,
+			Details: fmt.Sprintf("huge_pages is %s; the size of shared memory and the huge pages it needs are reported from PostgreSQL 15, "+
+				"so compare HugePages_Total and HugePages_Rsvd in /proc/meminfo instead", settings.HugePages),
+		})
+		return report, nil
+	case -1:
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityNotApplicable,
+			Details:  fmt.Sprintf("Huge pages are not supported on this platform; huge_pages is %s", settings.HugePages),
+		})
+		return report, nil
+	}
+
+	size := check.FormatBytes(settings.SharedMemorySizeBytes)
+	pages := fmt.Sprintf("%d huge pages", settings.HugePagesNeeded) // exact, for vm.nr_hugepages
+	if settings.HugePageSizeBytes > 0 {
+		pages += " of " + check.FormatBytes(settings.HugePageSizeBytes)
+	}
+	large := settings.SharedMemorySizeBytes >= c.minBytes
+
+	// With huge_pages = on the server does not start without them, so they
+	// are in use even before huge_pages_status tells.
+	status := settings.HugePagesStatus
+	if status == "" && settings.HugePages == "on" {
+		status = "on"
+	}
+
+	setting := check.TableRow{Cells: []string{"huge_pages", settings.HugePages, "-", "OK"}, Severity: check.SeverityOK}
+	inUse := check.TableRow{Cells: []string{"huge_pages_status", settings.HugePagesStatus, "-", "OK"}, Severity: check.SeverityOK}
+	var details, reason string
+	switch {
+	case settings.HugePages == "off" && large:
+		setting.Cells[2] = "try"
+		setting.Cells[3] = fmt.Sprintf("Off with %s of shared memory", size)
+		setting.Severity = check.SeverityWarn
+		reason = fmt.Sprintf("huge_pages off with %s of shared memory → WARN", size)
+		details = fmt.Sprintf("huge_pages is off with %s of shared memory: every backend maps it in regular pages, "+
+			"costing TLB misses and page table memory that grows with the number of connections. "+
+			"Reserve %s with vm.nr_hugepages, set huge_pages = try, and restart", size, pages)
+	case settings.HugePages == "off":
+		setting.Cells[3] = fmt.Sprintf("Off: shared memory is below %s", check.FormatBytes(c.minBytes))
+		details = fmt.Sprintf("huge_pages is off; shared memory (%s) is below %s, from which huge pages matter", size, check.FormatBytes(c.minBytes))
+	case status == "off":
+		inUse.Cells[2] = "on"
+		inUse.Cells[3] = "Fell back to regular pages"
+		inUse.Severity = check.SeverityWarn
+		reason = "huge_pages try fell back to regular pages → WARN"
+		details = fmt.Sprintf("huge_pages is try, but the server started on regular pages: the kernel had fewer than %s free "+
+			"for its %s of shared memory. Reserve at least that many with vm.nr_hugepages and restart", pages, size)
+	case status == "on":
+		details = fmt.Sprintf("Shared memory (%s) is on huge pages", size)
+	default:
+		setting.Cells[3] = "Requested; whether in use is reported from PostgreSQL 17"
+		details = fmt.Sprintf("huge_pages is try, but the server only reports whether it got them from PostgreSQL 17: "+
+			"check that HugePages_Rsvd in /proc/meminfo covers the %s its %s of shared memory needs", pages, size)
+	}
+
+	rows := []check.TableRow{setting}
+	if settings.HugePagesStatus != "" {
+		rows = append(rows, inUse)
+	}
+	rows = append(rows, check.TableRow{
+		Cells:    []string{"shared_memory_size", size, "-", "Needs " + pages},
+		Severity: check.SeverityOK,
+	})
+
+	severity := max(setting.Severity, inUse.Severity)
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: severity,
+		Details:  details,
+		Reason:   reason,
+		Table: &check.Table{
+			Headers: []string{"Parameter", "Current", "Expected", "Status"},
+			Rows:    rows,
+		},
+	})
+
+	return report, nil
+}
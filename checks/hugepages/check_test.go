@@ -0,0 +1,159 @@
+package hugepages_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/hugepages"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeQueries struct {
+	settings checktest.Result[db.HugePagesRow]
+}
+
+func (f fakeQueries) HugePages(context.Context) (db.HugePagesRow, error) {
+	return f.settings.Get()
+}
+
+func run(t *testing.T, cfg map[string]string, row db.HugePagesRow) check.Finding {
+	t.Helper()
+	checker := hugepages.New(fakeQueries{settings: checktest.Returns(row)}, check.Config{"hugepages": cfg})
+	return checktest.OnlyFinding(t, checktest.Run(t, checker))
+}
+
+// settings are those of a server with 16GiB of shared memory on PostgreSQL
+// 17, using the default huge page size.
+func settings(hugePages, status string) db.HugePagesRow {
+	return db.HugePagesRow{
+		HugePages:             hugePages,
+		HugePagesStatus:       status,
+		SharedMemorySizeBytes: 16 * check.GiB,
+		HugePagesNeeded:       8192,
+	}
+}
+
+func TestHugePages_Metadata(t *testing.T) {
+	t.Parallel()
+	checktest.RequireMetadata(t, hugepages.Metadata(), "hugepages", check.CategoryConfigs)
+}
+
+func TestHugePages_QueryError(t *testing.T) {
+	t.Parallel()
+	checktest.RequireError(t, hugepages.New(fakeQueries{settings: checktest.Fails[db.HugePagesRow](errors.New("boom"))}))
+}
+
+func TestHugePages_Severity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		row      db.HugePagesRow
+		severity check.Severity
+		rows     [][]string
+	}{
+		{
+			name:     "in use",
+			row:      settings("try", "on"),
+			severity: check.SeverityOK,
+			rows: [][]string{
+				{"huge_pages", "try", "-", "OK"},
+				{"huge_pages_status", "on", "-", "OK"},
+				{"shared_memory_size", "16.0GiB", "-", "Needs 8192 huge pages"},
+			},
+		},
+		{
+			name:     "fell back",
+			row:      settings("try", "off"),
+			severity: check.SeverityWarn,
+			rows: [][]string{
+				{"huge_pages", "try", "-", "OK"},
+				{"huge_pages_status", "off", "on", "Fell back to regular pages"},
+				{"shared_memory_size", "16.0GiB", "-", "Needs 8192 huge pages"},
+			},
+		},
+		{
+			name:     "off with large shared memory",
+			row:      settings("off", "off"),
+			severity: check.SeverityWarn,
+			rows: [][]string{
+				{"huge_pages", "off", "try", "Off with 16.0GiB of shared memory"},
+				{"huge_pages_status", "off", "-", "OK"},
+				{"shared_memory_size", "16.0GiB", "-", "Needs 8192 huge pages"},
+			},
+		},
+		{
+			name:     "off with small shared memory",
+			row:      db.HugePagesRow{HugePages: "off", HugePagesStatus: "off", SharedMemorySizeBytes: 2 * check.GiB, HugePagesNeeded: 1024},
+			severity: check.SeverityOK,
+		},
+		{
+			name:     "on before PostgreSQL 17",
+			row:      settings("on", ""),
+			severity: check.SeverityOK,
+			rows: [][]string{
+				{"huge_pages", "on", "-", "OK"},
+				{"shared_memory_size", "16.0GiB", "-", "Needs 8192 huge pages"},
+			},
+		},
+		{
+			name:     "try before PostgreSQL 17",
+			row:      settings("try", ""),
+			severity: check.SeverityOK,
+			rows: [][]string{
+				{"huge_pages", "try", "-", "Requested; whether in use is reported from PostgreSQL 17"},
+				{"shared_memory_size", "16.0GiB", "-", "Needs 8192 huge pages"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			finding := run(t, nil, tt.row)
+			assert.Equal(t, tt.severity, finding.Severity)
+			if tt.rows != nil {
+				assert.Equal(t, tt.rows, checktest.Rows(finding))
+			}
+		})
+	}
+}
+
+func TestHugePages_FellBack(t *testing.T) {
+	t.Parallel()
+
+	row := settings("try", "off")
+	row.HugePageSizeBytes = check.GiB
+	row.HugePagesNeeded = 17
+
+	finding := run(t, nil, row)
+	assert.Equal(t, "huge_pages try fell back to regular pages → WARN", finding.Reason)
+	assert.Contains(t, finding.Details, "fewer than 17 huge pages of 1.0GiB free")
+}
+
+func TestHugePages_MinSharedMemory(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, map[string]string{"min_shared_memory_gb": "32"}, settings("off", "off"))
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Contains(t, finding.Details, "below 32.0GiB")
+}
+
+func TestHugePages_NotApplicable(t *testing.T) {
+	t.Parallel()
+
+	for name, row := range map[string]db.HugePagesRow{
+		"before PostgreSQL 15": {HugePages: "try"},
+		"unsupported platform": {HugePages: "try", SharedMemorySizeBytes: 16 * check.GiB, HugePagesNeeded: -1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, check.SeverityNotApplicable, run(t, nil, row).Severity)
+		})
+	}
+}
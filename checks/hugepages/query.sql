@@ -0,0 +1,12 @@
+-- name: HugePages :one
+-- huge_pages, with what the server reports about the shared memory it
+-- allocated at start: shared_memory_size and the number of huge pages it
+-- needs (PostgreSQL 15+, -1 when the platform has no huge pages, 0 before
+-- 15), huge_page_size (14+, 0 for the system default), and whether huge
+-- pages are in use (huge_pages_status, 17+, empty before).
+SELECT
+  current_setting('huge_pages')::text AS huge_pages
+  , coalesce(current_setting('huge_pages_status', true), '')::text AS huge_pages_status
+  , coalesce(pg_catalog.pg_size_bytes(current_setting('shared_memory_size', true)), 0)::bigint AS shared_memory_size_bytes
+  , coalesce(current_setting('shared_memory_size_in_huge_pages', true)::bigint, 0)::bigint AS huge_pages_needed
+  , coalesce(pg_catalog.pg_size_bytes(current_setting('huge_page_size', true)), 0)::bigint AS huge_page_size_bytes;
@@ -0,0 +1,168 @@
+// Package inactiveslots implements a check for logical replication slots whose consumer has stopped reading.
+package inactiveslots
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strconv"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+// inactiveSinceMinMajor is the first release with
+// pg_replication_slots.inactive_since.
+const inactiveSinceMinMajor = 17
+
+type InactiveSlotsQueries interface {
+	PGVersion(context.Context) (db.PGVersionRow, error)
+	InactiveLogicalSlots(context.Context) ([]db.InactiveLogicalSlotsRow, error)
+	InactiveLogicalSlotsPG15(context.Context) ([]db.InactiveLogicalSlotsPG15Row, error)
+}
+
+type checker struct {
+	queries   InactiveSlotsQueries
+	failBytes int64 // default: 5GiB
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryConfigs,
+		CheckID:     "inactive-slots",
+		Name:        "Inactive Logical Slots",
+		Description: "Finds logical replication slots with no consumer connected that keep retaining WAL",
+		Readme:      readme,
+		SQL:         querySQL,
+		ClusterWide: true,
+	}
+}
+
+func New(queries InactiveSlotsQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries:   queries,
+		failBytes: 5 * check.GiB,
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			if v, ok := myCfg["fail_retained_gb"]; ok {
+				if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+					c.failBytes = n * check.GiB
+				}
+			}
+		}
+	}
+	return c
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	slots, err := c.fetchSlots(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	if len(slots) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Tags:     []string{check.TagXminHorizon},
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  "All logical replication slots have a consumer connected",
+		})
+		return report, nil
+	}
+
+	severity := check.SeverityWarn
+	var retained int64
+	tableRows := make([]check.TableRow, 0, len(slots))
+	for _, slot := range slots {
+		bytes := check.Int8ToInt64(slot.RetainedBytes)
+		retained += bytes
+
+		rowSeverity := check.SeverityWarn
+		if bytes >= c.failBytes {
+			rowSeverity = check.SeverityFail
+			severity = check.SeverityFail
+		}
+
+		inactiveFor := "-"
+		if slot.InactiveSeconds.Valid {
+			inactiveFor = check.FormatDurationSec(slot.InactiveSeconds.Int64)
+		}
+		tableRows = append(tableRows, check.TableRow{
+			Cells: []string{
+				slot.SlotName,
+				slot.Plugin,
+				slot.Database,
+				check.FormatBytes(bytes),
+				check.FormatBytes(check.Int8ToInt64(slot.UnconfirmedBytes)),
+				inactiveFor,
+			},
+			Severity: rowSeverity,
+		})
+	}
+
+	details := fmt.Sprintf("Found %d logical slot(s) with no consumer connected, retaining %s of WAL; the disk keeps filling until the consumer resumes or the slot is dropped",
+		len(slots), check.FormatBytes(retained))
+	if severity == check.SeverityFail {
+		details = fmt.Sprintf("Found %d logical slot(s) with no consumer connected, retaining %s of WAL (at least one over %s); resume the consumer or drop the slot before the disk fills",
+			len(slots), check.FormatBytes(retained), check.FormatBytes(c.failBytes))
+	}
+
+	priority := 0
+	if severity == check.SeverityFail {
+		// The retained WAL is on its way to filling the disk.
+		priority = check.PriorityUrgent
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Tags:     []string{check.TagXminHorizon},
+		Name:     report.Name,
+		Severity: severity,
+		Details:  details,
+		Priority: priority,
+		Table: &check.Table{
+			Headers:   []string{"Slot", "Plugin", "Database", "Retained WAL", "Unconfirmed", "Inactive For"},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignLeft, check.AlignLeft, check.AlignRight, check.AlignRight, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.NotSensitive, check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	})
+
+	return report, nil
+}
+
+// fetchSlots picks the query for the server version: inactive_since only
+// exists on PostgreSQL 17+.
+func (c *checker) fetchSlots(ctx context.Context) ([]db.InactiveLogicalSlotsRow, error) {
+	version, err := c.queries.PGVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if version.Major >= inactiveSinceMinMajor {
+		return c.queries.InactiveLogicalSlots(ctx)
+	}
+
+	pg15Slots, err := c.queries.InactiveLogicalSlotsPG15(ctx)
+	if err != nil {
+		return nil, err
+	}
+	slots := make([]db.InactiveLogicalSlotsRow, len(pg15Slots))
+	for i, s := range pg15Slots {
+		slots[i] = db.InactiveLogicalSlotsRow(s)
+	}
+	return slots, nil
+}
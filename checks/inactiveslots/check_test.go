@@ -0,0 +1,150 @@
+package inactiveslots_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/checks/inactiveslots"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockQueryer struct {
+	major      int32
+	slots      []db.InactiveLogicalSlotsRow
+	pg15Slots  []db.InactiveLogicalSlotsPG15Row
+	versionErr error
+	slotsErr   error
+}
+
+func (m *mockQueryer) PGVersion(context.Context) (db.PGVersionRow, error) {
+	return db.PGVersionRow{Major: m.major}, m.versionErr
+}
+
+func (m *mockQueryer) InactiveLogicalSlots(context.Context) ([]db.InactiveLogicalSlotsRow, error) {
+	return m.slots, m.slotsErr
+}
+
+func (m *mockQueryer) InactiveLogicalSlotsPG15(context.Context) ([]db.InactiveLogicalSlotsPG15Row, error) {
+	return m.pg15Slots, m.slotsErr
+}
+
+func int8(v int64) pgtype.Int8 {
+	return pgtype.Int8{Int64: v, Valid: true}
+}
+
+func slot(name string, retained int64) db.InactiveLogicalSlotsRow {
+	return db.InactiveLogicalSlotsRow{
+		SlotName:         name,
+		Plugin:           "pgoutput",
+		Database:         "app",
+		RetainedBytes:    int8(retained),
+		UnconfirmedBytes: int8(retained / 2),
+		InactiveSeconds:  int8(7200),
+	}
+}
+
+func TestInactiveSlots_NoInactiveSlots(t *testing.T) {
+	t.Parallel()
+
+	report, err := inactiveslots.New(&mockQueryer{major: 17}).Check(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, report.Results, 1)
+	assert.Equal(t, check.SeverityOK, report.Severity)
+}
+
+func TestInactiveSlots_Severity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		retained int64
+		cfg      check.Config
+		expected check.Severity
+	}{
+		{"small retention warns", 100 * check.MiB, nil, check.SeverityWarn},
+		{"below default fail", 4 * check.GiB, nil, check.SeverityWarn},
+		{"at default fail", 5 * check.GiB, nil, check.SeverityFail},
+		{"configured higher", 10 * check.GiB, check.Config{"inactive-slots": {"fail_retained_gb": "20"}}, check.SeverityWarn},
+		{"configured lower", 2 * check.GiB, check.Config{"inactive-slots": {"fail_retained_gb": "1"}}, check.SeverityFail},
+		{"invalid config keeps default", 6 * check.GiB, check.Config{"inactive-slots": {"fail_retained_gb": "lots"}}, check.SeverityFail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			queryer := &mockQueryer{major: 17, slots: []db.InactiveLogicalSlotsRow{slot("debezium", tt.retained)}}
+			report, err := inactiveslots.New(queryer, tt.cfg).Check(context.Background())
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, report.Severity)
+		})
+	}
+}
+
+func TestInactiveSlots_TableContents(t *testing.T) {
+	t.Parallel()
+
+	queryer := &mockQueryer{major: 17, slots: []db.InactiveLogicalSlotsRow{
+		slot("debezium", 8*check.GiB),
+		slot("fivetran", 1*check.GiB),
+	}}
+	report, err := inactiveslots.New(queryer).Check(context.Background())
+	require.NoError(t, err)
+
+	finding := report.Results[0]
+	assert.Contains(t, finding.Details, "2 logical slot(s)")
+	assert.Contains(t, finding.Details, "9.0GiB")
+	require.NotNil(t, finding.Table)
+	require.Len(t, finding.Table.Rows, 2)
+	assert.Equal(t, []string{"debezium", "pgoutput", "app", "8.0GiB", "4.0GiB", "2h"}, finding.Table.Rows[0].Cells)
+	assert.Equal(t, check.SeverityFail, finding.Table.Rows[0].Severity)
+	assert.Equal(t, check.SeverityWarn, finding.Table.Rows[1].Severity)
+}
+
+func TestInactiveSlots_PG15HasNoInactiveDuration(t *testing.T) {
+	t.Parallel()
+
+	queryer := &mockQueryer{major: 16, pg15Slots: []db.InactiveLogicalSlotsPG15Row{{
+		SlotName:         "debezium",
+		Plugin:           "pgoutput",
+		Database:         "app",
+		RetainedBytes:    int8(check.GiB),
+		UnconfirmedBytes: int8(check.GiB),
+	}}}
+	report, err := inactiveslots.New(queryer).Check(context.Background())
+	require.NoError(t, err)
+
+	require.NotNil(t, report.Results[0].Table)
+	assert.Equal(t, "-", report.Results[0].Table.Rows[0].Cells[5])
+}
+
+func TestInactiveSlots_QueryError(t *testing.T) {
+	t.Parallel()
+
+	for _, queryer := range []*mockQueryer{
+		{versionErr: fmt.Errorf("connection refused")},
+		{major: 17, slotsErr: fmt.Errorf("connection refused")},
+		{major: 15, slotsErr: fmt.Errorf("connection refused")},
+	} {
+		_, err := inactiveslots.New(queryer).Check(context.Background())
+		require.ErrorContains(t, err, "inactive-slots")
+	}
+}
+
+func TestInactiveSlots_Metadata(t *testing.T) {
+	t.Parallel()
+
+	m := inactiveslots.Metadata()
+	assert.Equal(t, "inactive-slots", m.CheckID)
+	assert.Equal(t, check.CategoryConfigs, m.Category)
+	assert.NotEmpty(t, m.Name)
+	assert.NotEmpty(t, m.Readme)
+	assert.NotEmpty(t, m.SQL)
+}
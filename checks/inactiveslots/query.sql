@@ -0,0 +1,54 @@
+-- name: InactiveLogicalSlots :many
+-- For PostgreSQL 17+: inactive, non-temporary logical slots with the WAL they
+-- retain, how far their consumer is behind, and how long they have been
+-- inactive. Slots whose WAL is already lost retain nothing and are excluded.
+WITH current_lsn AS (
+  SELECT
+    CASE
+      WHEN pg_is_in_recovery() THEN pg_last_wal_receive_lsn()
+      ELSE pg_current_wal_lsn()
+    END AS lsn
+)
+
+SELECT
+  s.slot_name::text AS slot_name
+  , s.plugin::text AS plugin
+  , s.database::text AS database
+  , pg_wal_lsn_diff(l.lsn, s.restart_lsn)::bigint AS retained_bytes
+  , pg_wal_lsn_diff(l.lsn, s.confirmed_flush_lsn)::bigint AS unconfirmed_bytes
+  , extract(EPOCH FROM (now() - s.inactive_since))::bigint AS inactive_seconds
+FROM pg_replication_slots AS s
+CROSS JOIN current_lsn AS l
+WHERE
+  s.slot_type = 'logical'
+  AND NOT s.active
+  AND NOT s.temporary
+  AND s.wal_status IS DISTINCT FROM 'lost'
+ORDER BY retained_bytes DESC NULLS LAST;
+
+-- name: InactiveLogicalSlotsPG15 :many
+-- For PostgreSQL 15/16: inactive_since doesn't exist, so the inactive
+-- duration is unknown.
+WITH current_lsn AS (
+  SELECT
+    CASE
+      WHEN pg_is_in_recovery() THEN pg_last_wal_receive_lsn()
+      ELSE pg_current_wal_lsn()
+    END AS lsn
+)
+
+SELECT
+  s.slot_name::text AS slot_name
+  , s.plugin::text AS plugin
+  , s.database::text AS database
+  , pg_wal_lsn_diff(l.lsn, s.restart_lsn)::bigint AS retained_bytes
+  , pg_wal_lsn_diff(l.lsn, s.confirmed_flush_lsn)::bigint AS unconfirmed_bytes
+  , NULL::bigint AS inactive_seconds
+FROM pg_replication_slots AS s
+CROSS JOIN current_lsn AS l
+WHERE
+  s.slot_type = 'logical'
+  AND NOT s.active
+  AND NOT s.temporary
+  AND s.wal_status IS DISTINCT FROM 'lost'
+ORDER BY retained_bytes DESC NULLS LAST;
@@ -95,6 +95,8 @@ func checkHighBloatIndexes(rows []db.IndexBloatRow, report *check.Report) {
 	}
 
 	headers := []string{"Table", "Index", "Bloat %", "Bloat Size", "Actual Size"}
+	sensitive := []check.Sensitivity{check.SensitiveIdentifier, check.SensitiveIdentifier}
+	align := []check.ColumnAlign{check.AlignLeft, check.AlignLeft, check.AlignRight, check.AlignRight, check.AlignRight}
 	var tableRows []check.TableRow
 
 	for _, row := range critical {
@@ -129,8 +131,11 @@ func checkHighBloatIndexes(rows []db.IndexBloatRow, report *check.Report) {
 		Severity: check.SeverityWarn,
 		Details:  fmt.Sprintf("Found %d index(es) with high bloat (>50%%)", len(critical)+len(warning)),
 		Table: &check.Table{
-			Headers: headers,
-			Rows:    tableRows,
+			Headers:   headers,
+			Key:       []int{0, 1},
+			Align:     align,
+			Sensitive: sensitive,
+			Rows:      tableRows,
 		},
 	})
 }
@@ -170,6 +175,8 @@ func checkLargeBloatedIndexes(rows []db.IndexBloatRow, report *check.Report) {
 	}
 
 	headers := []string{"Table", "Index", "Bloat Size", "Bloat %", "Actual Size"}
+	sensitive := []check.Sensitivity{check.SensitiveIdentifier, check.SensitiveIdentifier}
+	align := []check.ColumnAlign{check.AlignLeft, check.AlignLeft, check.AlignRight, check.AlignRight, check.AlignRight}
 	var tableRows []check.TableRow
 
 	for _, row := range critical {
@@ -209,8 +216,11 @@ func checkLargeBloatedIndexes(rows []db.IndexBloatRow, report *check.Report) {
 		Severity: check.SeverityWarn,
 		Details:  fmt.Sprintf("Found %d index(es) wasting significant disk space (total: %s)", len(critical)+len(warning), check.FormatBytes(totalWasted)),
 		Table: &check.Table{
-			Headers: headers,
-			Rows:    tableRows,
+			Headers:   headers,
+			Key:       []int{0, 1},
+			Align:     align,
+			Sensitive: sensitive,
+			Rows:      tableRows,
 		},
 	})
 }
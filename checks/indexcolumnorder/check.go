@@ -0,0 +1,413 @@
+// Package indexcolumnorder implements a check for multi-column indexes whose column order does not suit their queries.
+package indexcolumnorder
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type IndexColumnOrderQueries interface {
+	HasPgStatStatements(context.Context) (bool, error)
+	IndexColumnOrderIndexes(context.Context) ([]db.IndexColumnOrderIndexesRow, error)
+	IndexColumnOrderStatements(context.Context, int64) ([]db.IndexColumnOrderStatementsRow, error)
+}
+
+type checker struct {
+	queries        IndexColumnOrderQueries
+	minCalls       int64 // default: 100
+	lowCardinality int64 // distinct values; default: 10
+}
+
+// cardinalityRatio is how many times more distinct values a later column
+// needs than a low-cardinality leading one before the order is questioned.
+const cardinalityRatio = 100
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryIndexes,
+		CheckID:     "index-column-order",
+		Name:        "Index Column Order",
+		Description: "Suggests reordering multi-column indexes whose column order does not match how frequent statements filter them",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries IndexColumnOrderQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries:        queries,
+		minCalls:       100,
+		lowCardinality: 10,
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			parsePositive(myCfg, "min_calls", &c.minCalls)
+			parsePositive(myCfg, "low_cardinality", &c.lowCardinality)
+		}
+	}
+	return c
+}
+
+// parsePositive sets *dst to the value of key, when it is a positive integer.
+func parsePositive(cfg map[string]string, key string, dst *int64) {
+	if v, ok := cfg[key]; ok {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+			*dst = n
+		}
+	}
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	installed, err := c.queries.HasPgStatStatements(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+	if !installed {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityNotApplicable,
+			Details:  "pg_stat_statements is not installed in this database; column order is judged against the statements it records",
+		})
+		return report, nil
+	}
+
+	indexes, err := c.queries.IndexColumnOrderIndexes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+	byTable := map[string][]db.IndexColumnOrderIndexesRow{}
+	multiColumn := 0
+	for _, idx := range indexes {
+		key := idx.SchemaName + "." + idx.TableName
+		byTable[key] = append(byTable[key], idx)
+		if len(idx.Columns) > 1 {
+			multiColumn++
+		}
+	}
+	if multiColumn == 0 {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  "No multi-column btree indexes found",
+		})
+		return report, nil
+	}
+
+	rows, err := c.queries.IndexColumnOrderStatements(ctx, c.minCalls)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+	if len(rows) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  fmt.Sprintf("pg_stat_statements has no statements with a WHERE clause and at least %d calls to compare %d multi-column index(es) against", c.minCalls, multiColumn),
+		})
+		return report, nil
+	}
+	statements := make([]statement, 0, len(rows))
+	for _, row := range rows {
+		statements = append(statements, newStatement(row))
+	}
+
+	var tableRows []check.TableRow
+	for _, idx := range indexes {
+		if len(idx.Columns) < 2 {
+			continue
+		}
+		others := byTable[idx.SchemaName+"."+idx.TableName]
+		patterns := newIndexPatterns(idx)
+		for _, s := range []suggestion{
+			rangeBeforeEquality(idx, patterns, others, statements),
+			c.lowCardinalityLeading(idx, patterns, others, statements),
+		} {
+			if s.statements == 0 {
+				continue
+			}
+			tableRows = append(tableRows, check.TableRow{
+				Cells: []string{
+					idx.SchemaName + "." + idx.IndexName,
+					"(" + strings.Join(idx.Columns, ", ") + ")",
+					"(" + strings.Join(s.order, ", ") + ")",
+					s.reason,
+					check.FormatNumber(int64(s.statements)),
+					check.FormatNumber(s.calls),
+				},
+				Severity: check.SeverityWarn,
+			})
+		}
+	}
+
+	if len(tableRows) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  fmt.Sprintf("The column order of %d multi-column index(es) matches the %d statement(s) analyzed", multiColumn, len(statements)),
+		})
+		return report, nil
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: check.SeverityWarn,
+		Details: fmt.Sprintf("%d index column order(s) do not match how frequent statements filter the table. "+
+			"These are suggestions read from pg_stat_statements text: confirm with EXPLAIN on the statements before "+
+			"building the reordered index and dropping the old one",
+			len(tableRows)),
+		Table: &check.Table{
+			Headers: []string{"Index", "Columns", "Suggested Order", "Reason", "Statements", "Calls"},
+			Align:   []check.ColumnAlign{check.AlignLeft, check.AlignLeft, check.AlignLeft, check.AlignLeft, check.AlignRight, check.AlignRight},
+			Sensitive: []check.Sensitivity{
+				check.SensitiveIdentifier, check.SensitiveIdentifier, check.SensitiveIdentifier, check.SensitiveIdentifier,
+			},
+			Rows: tableRows,
+		},
+	})
+
+	return report, nil
+}
+
+// suggestion is a better column order for an index, with the statements
+// that would benefit from it.
+type suggestion struct {
+	order      []string
+	reason     string
+	statements int
+	calls      int64
+	top        int64 // calls of the statement the order and reason come from
+}
+
+// add counts a statement for s, keeping the order and reason of the most
+// called pattern.
+func (s *suggestion) add(st statement, order []string, reason string) {
+	if s.statements == 0 || st.calls > s.top {
+		s.order, s.reason, s.top = order, reason, st.calls
+	}
+	s.statements++
+	s.calls += st.calls
+}
+
+// rangeBeforeEquality looks for statements that filter a column with a range
+// and a later column of the index with equality. The scan then covers the
+// whole range and checks the later column row by row; with the equality
+// column first it would read only the matching rows.
+func rangeBeforeEquality(
+	idx db.IndexColumnOrderIndexesRow,
+	patterns indexPatterns,
+	others []db.IndexColumnOrderIndexesRow,
+	statements []statement,
+) suggestion {
+	var s suggestion
+	for _, st := range statements {
+		if !patterns.table.MatchString(st.text) {
+			continue
+		}
+		kinds := patterns.predicates(st)
+		// The leading columns up to the range must be equalities, or the
+		// index would not be used for the range at all.
+		r := 0
+		for r < len(kinds) && kinds[r] == predicateEquality {
+			r++
+		}
+		if r == len(kinds) || kinds[r] != predicateRange {
+			continue
+		}
+		var moved []string
+		for j := r + 1; j < len(kinds); j++ {
+			if kinds[j] == predicateEquality {
+				moved = append(moved, idx.Columns[j])
+			}
+		}
+		if len(moved) == 0 {
+			continue
+		}
+		leading := append(append([]string{}, idx.Columns[:r]...), moved...)
+		if ledBy(others, idx.IndexName, leading) {
+			continue
+		}
+		order := append(append([]string{}, leading...), without(idx.Columns[r:], moved)...)
+		reason := fmt.Sprintf("range on %s before equality on %s", idx.Columns[r], strings.Join(moved, ", "))
+		s.add(st, order, reason)
+	}
+	return s
+}
+
+// lowCardinalityLeading looks for an index led by a column with few distinct
+// values, and statements that filter a much more selective later column
+// without it. The leading column cannot narrow those scans, so each one
+// reads the index once per leading value at best (skip scan, PostgreSQL 18)
+// and in full at worst.
+func (c *checker) lowCardinalityLeading(
+	idx db.IndexColumnOrderIndexesRow,
+	patterns indexPatterns,
+	others []db.IndexColumnOrderIndexesRow,
+	statements []statement,
+) suggestion {
+	var s suggestion
+	if len(idx.ColumnDistinct) != len(idx.Columns) {
+		return s
+	}
+	lead := idx.ColumnDistinct[0]
+	if lead < 0 || lead > float64(c.lowCardinality) {
+		return s
+	}
+	for _, st := range statements {
+		if !patterns.table.MatchString(st.text) || patterns.lead.MatchString(st.where) {
+			continue
+		}
+		kinds := patterns.predicates(st)
+		for j := 1; j < len(kinds); j++ {
+			if kinds[j] != predicateEquality || idx.ColumnDistinct[j] < cardinalityRatio*max(lead, 1) {
+				continue
+			}
+			column := idx.Columns[j]
+			if ledBy(others, idx.IndexName, []string{column}) {
+				break
+			}
+			order := append([]string{column}, without(idx.Columns, []string{column})...)
+			reason := fmt.Sprintf("leads with %s (~%s values), filtered on %s without it",
+				idx.Columns[0], check.FormatNumber(int64(lead)), column)
+			s.add(st, order, reason)
+			break
+		}
+	}
+	return s
+}
+
+// ledBy reports whether an index other than name starts with columns, in
+// any order, so statements are already served by it.
+func ledBy(indexes []db.IndexColumnOrderIndexesRow, name string, columns []string) bool {
+	for _, idx := range indexes {
+		if idx.IndexName == name || len(idx.Columns) < len(columns) {
+			continue
+		}
+		if len(without(idx.Columns[:len(columns)], columns)) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// without returns columns, in order, minus the ones in drop.
+func without(columns, drop []string) []string {
+	var out []string
+	for _, c := range columns {
+		keep := true
+		for _, d := range drop {
+			if c == d {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+type predicateKind int
+
+const (
+	predicateNone predicateKind = iota
+	predicateRange
+	predicateEquality
+)
+
+// statement is a normalized statement from pg_stat_statements, lowercased
+// with its whitespace collapsed.
+type statement struct {
+	text  string
+	where string
+	calls int64
+}
+
+func newStatement(row db.IndexColumnOrderStatementsRow) statement {
+	text := strings.Join(strings.Fields(strings.ToLower(row.Query)), " ")
+	return statement{text: text, where: whereClause(text), calls: row.Calls}
+}
+
+// whereClause returns the text after the first WHERE, up to the clauses that
+// can follow it.
+func whereClause(text string) string {
+	_, clause, ok := strings.Cut(text, " where ")
+	if !ok {
+		return ""
+	}
+	for _, marker := range []string{" order by ", " group by ", " having ", " limit ", " offset ", " returning ", " for update", " for share", ";"} {
+		if i := strings.Index(clause, marker); i != -1 {
+			clause = clause[:i]
+		}
+	}
+	return clause
+}
+
+// indexPatterns match an index's table and columns in statement text.
+type indexPatterns struct {
+	table   *regexp.Regexp   // the table name, anywhere in the statement
+	lead    *regexp.Regexp   // the leading column, anywhere in the WHERE clause
+	columns []*regexp.Regexp // a comparison on each column, in index order
+}
+
+func newIndexPatterns(idx db.IndexColumnOrderIndexesRow) indexPatterns {
+	p := indexPatterns{table: wordPattern(idx.TableName), lead: wordPattern(idx.Columns[0])}
+	for _, column := range idx.Columns {
+		p.columns = append(p.columns, predicatePattern(column))
+	}
+	return p
+}
+
+// predicates returns how the WHERE clause of st filters each column:
+// equality (=, IN, = ANY, IS NULL) beats a range (<, <=, >, >=, BETWEEN),
+// and anything else, such as <> or LIKE, does not count.
+func (p indexPatterns) predicates(st statement) []predicateKind {
+	kinds := make([]predicateKind, len(p.columns))
+	for i, pattern := range p.columns {
+		for _, m := range pattern.FindAllStringSubmatch(st.where, -1) {
+			switch op := m[1]; {
+			case op == "=" || op == "in" || strings.HasPrefix(op, "is"):
+				kinds[i] = predicateEquality
+			case op == "<" || op == ">" || op == "<=" || op == ">=" || op == "between":
+				kinds[i] = max(kinds[i], predicateRange)
+			}
+		}
+	}
+	return kinds
+}
+
+// wordPattern matches name as a whole identifier, optionally quoted.
+func wordPattern(name string) *regexp.Regexp {
+	return regexp.MustCompile(`(^|[^\w$])"?` + regexp.QuoteMeta(strings.ToLower(name)) + `"?($|[^\w$])`)
+}
+
+// predicatePattern matches a comparison on column, optionally qualified by
+// a table alias, capturing the operator. "<>" and "!=" are matched so their
+// "=" and ">" are not mistaken for the operators they contain.
+func predicatePattern(column string) *regexp.Regexp {
+	return regexp.MustCompile(`(?:^|[^\w$"])(?:\w+\.)?"?` + regexp.QuoteMeta(strings.ToLower(column)) +
+		`"?\s*(<>|!=|<=|>=|=|<|>|in\b|is\s+null\b|between\b)`)
+}
@@ -0,0 +1,214 @@
+package indexcolumnorder_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/indexcolumnorder"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeQueries struct {
+	installed  checktest.Result[bool]
+	indexes    checktest.Result[[]db.IndexColumnOrderIndexesRow]
+	statements checktest.Result[[]db.IndexColumnOrderStatementsRow]
+	minCalls   *int64
+}
+
+func (f fakeQueries) HasPgStatStatements(context.Context) (bool, error) {
+	return f.installed.Get()
+}
+
+func (f fakeQueries) IndexColumnOrderIndexes(context.Context) ([]db.IndexColumnOrderIndexesRow, error) {
+	return f.indexes.Get()
+}
+
+func (f fakeQueries) IndexColumnOrderStatements(_ context.Context, minCalls int64) ([]db.IndexColumnOrderStatementsRow, error) {
+	if f.minCalls != nil {
+		*f.minCalls = minCalls
+	}
+	return f.statements.Get()
+}
+
+func index(table, name string, columns []string, distinct ...float64) db.IndexColumnOrderIndexesRow {
+	if distinct == nil {
+		for range columns {
+			distinct = append(distinct, -1)
+		}
+	}
+	return db.IndexColumnOrderIndexesRow{SchemaName: "public", TableName: table, IndexName: name, Columns: columns, ColumnDistinct: distinct}
+}
+
+func stmt(query string, calls int64) db.IndexColumnOrderStatementsRow {
+	return db.IndexColumnOrderStatementsRow{Query: query, Calls: calls}
+}
+
+func run(t *testing.T, indexes []db.IndexColumnOrderIndexesRow, statements ...db.IndexColumnOrderStatementsRow) check.Finding {
+	t.Helper()
+	return checktest.OnlyFinding(t, checktest.Run(t, indexcolumnorder.New(fakeQueries{
+		installed:  checktest.Returns(true),
+		indexes:    checktest.Returns(indexes),
+		statements: checktest.Returns(statements),
+	})))
+}
+
+func TestIndexColumnOrder_Metadata(t *testing.T) {
+	t.Parallel()
+	checktest.RequireMetadata(t, indexcolumnorder.Metadata(), "index-column-order", check.CategoryIndexes)
+}
+
+func TestIndexColumnOrder_QueryErrors(t *testing.T) {
+	t.Parallel()
+
+	multi := []db.IndexColumnOrderIndexesRow{index("orders", "orders_idx", []string{"a", "b"})}
+	checktest.RequireError(t, indexcolumnorder.New(fakeQueries{installed: checktest.Fails[bool](errors.New("boom"))}))
+	checktest.RequireError(t, indexcolumnorder.New(fakeQueries{
+		installed: checktest.Returns(true),
+		indexes:   checktest.Fails[[]db.IndexColumnOrderIndexesRow](errors.New("boom")),
+	}))
+	checktest.RequireError(t, indexcolumnorder.New(fakeQueries{
+		installed:  checktest.Returns(true),
+		indexes:    checktest.Returns(multi),
+		statements: checktest.Fails[[]db.IndexColumnOrderStatementsRow](errors.New("boom")),
+	}))
+}
+
+func TestIndexColumnOrder_NoPgStatStatements(t *testing.T) {
+	t.Parallel()
+
+	report := checktest.Run(t, indexcolumnorder.New(fakeQueries{installed: checktest.Returns(false)}))
+	assert.Equal(t, check.SeverityNotApplicable, report.Severity)
+}
+
+func TestIndexColumnOrder_NothingToCompare(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, []db.IndexColumnOrderIndexesRow{index("orders", "orders_pkey", []string{"id"})})
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "No multi-column btree indexes found", finding.Details)
+
+	finding = run(t, []db.IndexColumnOrderIndexesRow{index("orders", "orders_idx", []string{"a", "b"})})
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Contains(t, finding.Details, "no statements")
+}
+
+func TestIndexColumnOrder_RangeBeforeEquality(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t,
+		[]db.IndexColumnOrderIndexesRow{
+			index("orders", "orders_tenant_created_status_idx", []string{"tenant_id", "created_at", "status"}),
+		},
+		stmt("SELECT * FROM orders\nWHERE tenant_id = $1 AND created_at > $2 AND status = $3 ORDER BY id LIMIT $4", 5000),
+		stmt(`UPDATE orders SET seen = true WHERE "tenant_id" = $1 AND created_at BETWEEN $2 AND $3 AND status IN ($4, $5)`, 200),
+		// Not usable for the range: tenant_id is only compared with <>.
+		stmt("SELECT * FROM orders WHERE tenant_id <> $1 AND created_at > $2 AND status = $3", 9000),
+		// Another table.
+		stmt("SELECT * FROM invoices WHERE tenant_id = $1 AND created_at > $2 AND status = $3", 9000),
+	)
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Equal(t, [][]string{{
+		"public.orders_tenant_created_status_idx",
+		"(tenant_id, created_at, status)",
+		"(tenant_id, status, created_at)",
+		"range on created_at before equality on status",
+		"2",
+		"5.2K",
+	}}, checktest.Rows(finding))
+	require.NotNil(t, finding.Table)
+	assert.Equal(t, check.SensitiveIdentifier, finding.Table.ColumnSensitivity(3))
+	assert.Equal(t, check.NotSensitive, finding.Table.ColumnSensitivity(4))
+}
+
+func TestIndexColumnOrder_LowCardinalityLeading(t *testing.T) {
+	t.Parallel()
+
+	idx := index("events", "events_kind_user_idx", []string{"kind", "user_id"}, 4, 250_000)
+	finding := run(t,
+		[]db.IndexColumnOrderIndexesRow{idx},
+		stmt("SELECT * FROM events WHERE user_id = $1", 800),
+		// Uses the leading column: fine.
+		stmt("SELECT * FROM events WHERE kind = $1 AND user_id = $2", 9000),
+	)
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Equal(t, [][]string{{
+		"public.events_kind_user_idx",
+		"(kind, user_id)",
+		"(user_id, kind)",
+		"leads with kind (~4 values), filtered on user_id without it",
+		"1",
+		"800",
+	}}, checktest.Rows(finding))
+}
+
+func TestIndexColumnOrder_FalsePositiveControl(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		indexes []db.IndexColumnOrderIndexesRow
+		query   string
+	}{
+		{
+			name: "another index leads with the suggested columns",
+			indexes: []db.IndexColumnOrderIndexesRow{
+				index("orders", "orders_created_status_idx", []string{"created_at", "status"}),
+				index("orders", "orders_status_idx", []string{"status"}),
+			},
+			query: "SELECT * FROM orders WHERE created_at > $1 AND status = $2",
+		},
+		{
+			name:    "statement on another table",
+			indexes: []db.IndexColumnOrderIndexesRow{index("orders", "orders_created_status_idx", []string{"created_at", "status"})},
+			query:   "SELECT * FROM customers WHERE created_at > $1 AND status = $2",
+		},
+		{
+			name:    "range on the last column is the right order",
+			indexes: []db.IndexColumnOrderIndexesRow{index("orders", "orders_status_created_idx", []string{"status", "created_at"})},
+			query:   "SELECT * FROM orders WHERE status = $1 AND created_at >= $2",
+		},
+		{
+			name:    "leading column without statistics",
+			indexes: []db.IndexColumnOrderIndexesRow{index("events", "events_kind_user_idx", []string{"kind", "user_id"})},
+			query:   "SELECT * FROM events WHERE user_id = $1",
+		},
+		{
+			name:    "later column not selective enough",
+			indexes: []db.IndexColumnOrderIndexesRow{index("events", "events_kind_user_idx", []string{"kind", "user_id"}, 4, 50)},
+			query:   "SELECT * FROM events WHERE user_id = $1",
+		},
+		{
+			name:    "column name inside another identifier",
+			indexes: []db.IndexColumnOrderIndexesRow{index("orders", "orders_created_status_idx", []string{"created_at", "status"})},
+			query:   "SELECT * FROM orders WHERE created_at > $1 AND order_status = $2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			finding := run(t, tt.indexes, stmt(tt.query, 10_000))
+			assert.Equal(t, check.SeverityOK, finding.Severity)
+		})
+	}
+}
+
+func TestIndexColumnOrder_Config(t *testing.T) {
+	t.Parallel()
+
+	var minCalls int64
+	idx := index("events", "events_kind_user_idx", []string{"kind", "user_id"}, 40, 250_000)
+	report := checktest.Run(t, indexcolumnorder.New(fakeQueries{
+		installed:  checktest.Returns(true),
+		indexes:    checktest.Returns([]db.IndexColumnOrderIndexesRow{idx}),
+		statements: checktest.Returns([]db.IndexColumnOrderStatementsRow{stmt("SELECT * FROM events WHERE user_id = $1", 800)}),
+		minCalls:   &minCalls,
+	}, check.Config{"index-column-order": {"min_calls": "500", "low_cardinality": "50"}}))
+	assert.Equal(t, int64(500), minCalls)
+	assert.Equal(t, check.SeverityWarn, checktest.OnlyFinding(t, report).Severity)
+}
@@ -0,0 +1,58 @@
+-- name: IndexColumnOrderIndexes :many
+-- Btree indexes on plain columns, with their key columns in index order and
+-- each column's estimated number of distinct values (-1 without statistics).
+-- Single-column indexes are included so the check can tell when another
+-- index already leads with a column.
+SELECT
+  n.nspname::text AS schema_name
+  , t.relname::text AS table_name
+  , i.relname::text AS index_name
+  , array_agg(a.attname::text ORDER BY k.n)::text [] AS columns
+  -- pg_stats stores n_distinct as a negative fraction of the rows when it
+  -- scales with the table
+  , array_agg(
+    coalesce(
+      CASE WHEN st.n_distinct < 0 THEN -st.n_distinct * greatest(t.reltuples, 0) ELSE st.n_distinct END
+      , -1
+    ) ORDER BY k.n
+  )::double precision [] AS column_distinct
+FROM pg_index AS idx
+INNER JOIN pg_class AS i ON idx.indexrelid = i.oid
+INNER JOIN pg_class AS t ON idx.indrelid = t.oid
+INNER JOIN pg_namespace AS n ON t.relnamespace = n.oid
+INNER JOIN pg_am AS am ON i.relam = am.oid
+CROSS JOIN LATERAL unnest(idx.indkey::int []) WITH ORDINALITY AS k (attnum, n)
+INNER JOIN pg_attribute AS a ON a.attrelid = t.oid AND k.attnum = a.attnum
+LEFT JOIN LATERAL (
+  -- Partitioned tables only have inherited statistics.
+  SELECT s.n_distinct
+  FROM pg_stats AS s
+  WHERE s.schemaname = n.nspname AND s.tablename = t.relname AND s.attname = a.attname
+  ORDER BY s.inherited
+  LIMIT 1
+) AS st ON TRUE
+WHERE
+  am.amname = 'btree'
+  AND idx.indisvalid
+  AND idx.indexprs IS NULL
+  AND k.n <= idx.indnkeyatts
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast', 'cron', 'pgpartman', 'debezium')
+GROUP BY n.nspname, t.relname, i.relname
+ORDER BY n.nspname, t.relname, i.relname;
+
+-- name: IndexColumnOrderStatements :many
+-- The most called statements of this database with a WHERE clause, whose
+-- predicates the check matches against index columns. Statements with joins
+-- are left out: their column names cannot be told apart by table.
+SELECT
+  query::text AS query
+  , calls::bigint AS calls
+FROM pg_stat_statements
+WHERE
+  dbid = (SELECT oid FROM pg_database WHERE datname = current_database())
+  AND calls >= sqlc.arg(min_calls)::bigint
+  AND query ~* '\mwhere\M'
+  AND query !~* '\mjoin\M'
+  AND query ~* '^\s*(select|update|delete)\M'
+ORDER BY calls DESC
+LIMIT 1000;
@@ -0,0 +1,119 @@
+// Package indexsizeratio implements a check for tables whose indexes are much larger than the table itself.
+package indexsizeratio
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strconv"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type IndexSizeRatioQueries interface {
+	IndexSizeRatios(context.Context) ([]db.IndexSizeRatiosRow, error)
+}
+
+type checker struct {
+	queries  IndexSizeRatioQueries
+	maxRatio float64 // default: 3
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryIndexes,
+		CheckID:     "index-size-ratio",
+		Name:        "Index to Table Size Ratio",
+		Description: "Flags tables whose indexes take several times the space of the table, a sign of over-indexing or index bloat",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries IndexSizeRatioQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries:  queries,
+		maxRatio: 3,
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			if v, ok := myCfg["max_ratio"]; ok {
+				if n, err := strconv.ParseFloat(v, 64); err == nil && n > 0 {
+					c.maxRatio = n
+				}
+			}
+		}
+	}
+	return c
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	rows, err := c.queries.IndexSizeRatios(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	var tableRows []check.TableRow
+	for _, row := range rows {
+		tableSize := check.Int8ToInt64(row.TableSizeBytes)
+		indexSize := check.Int8ToInt64(row.IndexSizeBytes)
+		// An empty heap with 100MB+ of indexes is flagged without a ratio.
+		ratioCell := "-"
+		if tableSize > 0 {
+			ratio := float64(indexSize) / float64(tableSize)
+			if ratio < c.maxRatio {
+				continue
+			}
+			ratioCell = fmt.Sprintf("%.1fx", ratio)
+		}
+		tableRows = append(tableRows, check.TableRow{
+			Cells: []string{
+				row.TableName,
+				check.FormatBytes(tableSize),
+				check.FormatBytes(indexSize),
+				check.FormatNumber(row.IndexCount),
+				ratioCell,
+			},
+			Severity: check.SeverityWarn,
+		})
+	}
+
+	if len(tableRows) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  fmt.Sprintf("No table's indexes exceed %.1fx its size", c.maxRatio),
+		})
+		return report, nil
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: check.SeverityWarn,
+		Details: fmt.Sprintf("Found %d table(s) whose indexes take %.1fx or more of the table's size; "+
+			"look for unused, duplicate, or bloated indexes", len(tableRows), c.maxRatio),
+		Table: &check.Table{
+			Headers:   []string{"Table", "Table Size", "Index Size", "Indexes", "Ratio"},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignRight, check.AlignRight, check.AlignRight, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	})
+
+	return report, nil
+}
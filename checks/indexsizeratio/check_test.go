@@ -0,0 +1,131 @@
+package indexsizeratio_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/checks/indexsizeratio"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockQueryer struct {
+	rows    []db.IndexSizeRatiosRow
+	rowsErr error
+}
+
+func (m *mockQueryer) IndexSizeRatios(context.Context) ([]db.IndexSizeRatiosRow, error) {
+	return m.rows, m.rowsErr
+}
+
+func sizes(table string, tableBytes, indexBytes int64) db.IndexSizeRatiosRow {
+	return db.IndexSizeRatiosRow{
+		TableName:      table,
+		TableSizeBytes: pgtype.Int8{Int64: tableBytes, Valid: true},
+		IndexSizeBytes: pgtype.Int8{Int64: indexBytes, Valid: true},
+		IndexCount:     7,
+	}
+}
+
+func TestIndexSizeRatio_NoTables(t *testing.T) {
+	t.Parallel()
+
+	report, err := indexsizeratio.New(&mockQueryer{}).Check(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, report.Results, 1)
+	assert.Equal(t, check.SeverityOK, report.Results[0].Severity)
+}
+
+func TestIndexSizeRatio_Thresholds(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		row      db.IndexSizeRatiosRow
+		expected check.Severity
+	}{
+		{"indexes smaller than table", sizes("public.events", 10*check.GiB, 4*check.GiB), check.SeverityOK},
+		{"below ratio", sizes("public.events", check.GiB, 2*check.GiB), check.SeverityOK},
+		{"at ratio", sizes("public.events", check.GiB, 3*check.GiB), check.SeverityWarn},
+		{"far above ratio", sizes("public.events", 100*check.MiB, 2*check.GiB), check.SeverityWarn},
+		{"empty heap", sizes("public.events", 0, 200*check.MiB), check.SeverityWarn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			report, err := indexsizeratio.New(&mockQueryer{
+				rows: []db.IndexSizeRatiosRow{tt.row},
+			}).Check(context.Background())
+			require.NoError(t, err)
+
+			require.Len(t, report.Results, 1)
+			assert.Equal(t, tt.expected, report.Results[0].Severity)
+		})
+	}
+}
+
+func TestIndexSizeRatio_Table(t *testing.T) {
+	t.Parallel()
+
+	report, err := indexsizeratio.New(&mockQueryer{
+		rows: []db.IndexSizeRatiosRow{
+			sizes("public.events", 100*check.MiB, 2*check.GiB),
+			sizes("public.users", 10*check.GiB, 4*check.GiB),
+			sizes("public.staging", 0, 200*check.MiB),
+		},
+	}).Check(context.Background())
+	require.NoError(t, err)
+
+	finding := report.Results[0]
+	assert.Contains(t, finding.Details, "Found 2 table(s)")
+	require.NotNil(t, finding.Table)
+	require.Len(t, finding.Table.Rows, 2)
+	assert.Equal(t, []string{"public.events", "100.0MiB", "2.0GiB", "7", "20.5x"}, finding.Table.Rows[0].Cells)
+	assert.Equal(t, "-", finding.Table.Rows[1].Cells[4])
+}
+
+func TestIndexSizeRatio_Config(t *testing.T) {
+	t.Parallel()
+
+	rows := []db.IndexSizeRatiosRow{sizes("public.events", check.GiB, 4*check.GiB)}
+
+	cfg := check.Config{"index-size-ratio": {"max_ratio": "5"}}
+	report, err := indexsizeratio.New(&mockQueryer{rows: rows}, cfg).Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, check.SeverityOK, report.Results[0].Severity)
+
+	cfg = check.Config{"index-size-ratio": {"max_ratio": "1.5"}}
+	report, err = indexsizeratio.New(&mockQueryer{rows: rows}, cfg).Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, check.SeverityWarn, report.Results[0].Severity)
+
+	cfg = check.Config{"index-size-ratio": {"max_ratio": "-1"}}
+	report, err = indexsizeratio.New(&mockQueryer{rows: rows}, cfg).Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, check.SeverityWarn, report.Results[0].Severity, "invalid values keep the default of 3")
+}
+
+func TestIndexSizeRatio_QueryError(t *testing.T) {
+	t.Parallel()
+
+	_, err := indexsizeratio.New(&mockQueryer{rowsErr: fmt.Errorf("connection refused")}).Check(context.Background())
+	require.ErrorContains(t, err, "index-size-ratio")
+}
+
+func TestIndexSizeRatio_Metadata(t *testing.T) {
+	t.Parallel()
+
+	m := indexsizeratio.Metadata()
+	assert.Equal(t, "index-size-ratio", m.CheckID)
+	assert.Equal(t, check.CategoryIndexes, m.Category)
+	assert.NotEmpty(t, m.Name)
+	assert.NotEmpty(t, m.Readme)
+	assert.NotEmpty(t, m.SQL)
+}
@@ -0,0 +1,16 @@
+-- name: IndexSizeRatios :many
+-- Tables whose indexes total 100MB or more, with their table size (heap and
+-- TOAST) and total index size. The ratio threshold is applied by the check so
+-- it stays configurable.
+SELECT
+  (n.nspname || '.' || c.relname)::text AS table_name
+  , pg_catalog.pg_table_size(c.oid) AS table_size_bytes
+  , pg_catalog.pg_indexes_size(c.oid) AS index_size_bytes
+  , (SELECT count(*) FROM pg_catalog.pg_index AS i WHERE i.indrelid = c.oid) AS index_count
+FROM pg_catalog.pg_class AS c
+INNER JOIN pg_catalog.pg_namespace AS n ON c.relnamespace = n.oid
+WHERE
+  n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
+  AND c.relkind IN ('r', 'm')
+  AND pg_catalog.pg_indexes_size(c.oid) >= 100 * 1024 * 1024
+ORDER BY pg_catalog.pg_indexes_size(c.oid) DESC;
@@ -0,0 +1,167 @@
+// Package insertvacuum implements a check for append-only tables whose inserts pile up without a vacuum.
+package insertvacuum
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strconv"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type InsertVacuumQueries interface {
+	InsertVacuumSupported(context.Context) (bool, error)
+	InsertVacuum(ctx context.Context, minInserts int64) ([]db.InsertVacuumRow, error)
+}
+
+type checker struct {
+	queries    InsertVacuumQueries
+	minInserts int64 // rows inserted since the last vacuum; default: 1000000
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryVacuum,
+		CheckID:     "insert-vacuum",
+		Name:        "Insert Vacuum",
+		Description: "Finds append-only tables with a large backlog of inserts since their last vacuum, which leaves the visibility map stale for index-only scans",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries InsertVacuumQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries:    queries,
+		minInserts: 1_000_000,
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			if v, ok := myCfg["min_inserts"]; ok {
+				if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+					c.minInserts = n
+				}
+			}
+		}
+	}
+	return c
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	supported, err := c.queries.InsertVacuumSupported(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+	if !supported {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityNotApplicable,
+			Details: "Insert-driven autovacuum requires PostgreSQL 13+; before it, append-only tables are only vacuumed " +
+				"to prevent wraparound, so schedule a manual VACUUM for tables relying on index-only scans",
+		})
+		return report, nil
+	}
+
+	rows, err := c.queries.InsertVacuum(ctx, c.minInserts)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	if len(rows) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  fmt.Sprintf("No table has %s or more rows inserted since its last vacuum", check.FormatNumber(c.minInserts)),
+		})
+		return report, nil
+	}
+
+	var disabled, behind int
+	tableRows := make([]check.TableRow, 0, len(rows))
+	for _, row := range rows {
+		// Autovacuum vacuums a table once its inserts since the last vacuum
+		// pass insert_threshold + insert_scale_factor × reltuples; a
+		// threshold of -1 turns insert-driven vacuums off.
+		fires := "-"
+		var status string
+		switch {
+		case !row.AutovacuumEnabled:
+			disabled++
+			status = "Autovacuum disabled"
+		case row.InsertThreshold < 0:
+			disabled++
+			status = "Insert threshold disabled (-1)"
+		default:
+			trigger := row.InsertThreshold + int64(row.InsertScaleFactor*float64(row.EstimatedRows))
+			fires = check.FormatNumber(trigger)
+			if row.InsertsSinceVacuum >= trigger {
+				behind++
+				status = "Past threshold; autovacuum is behind"
+			} else {
+				status = fmt.Sprintf("Below threshold (scale factor %g)", row.InsertScaleFactor)
+			}
+		}
+
+		lastVacuum := "never"
+		if row.SecondsSinceVacuum >= 0 {
+			lastVacuum = check.FormatDurationSec(row.SecondsSinceVacuum) + " ago"
+		}
+
+		tableRows = append(tableRows, check.TableRow{
+			Cells: []string{
+				row.TableName,
+				check.FormatNumber(row.EstimatedRows),
+				check.FormatNumber(row.InsertsSinceVacuum),
+				fires,
+				lastVacuum,
+				status,
+			},
+			Severity: check.SeverityWarn,
+		})
+	}
+
+	details := fmt.Sprintf("Found %d table(s) with %s or more rows inserted since their last vacuum. "+
+		"Until vacuumed, the new pages are not all-visible, so index-only scans fetch heap pages and the next "+
+		"anti-wraparound vacuum has more to freeze", len(rows), check.FormatNumber(c.minInserts))
+	if disabled > 0 {
+		details += fmt.Sprintf("; %d have insert-driven autovacuum turned off", disabled)
+	}
+	if behind > 0 {
+		details += fmt.Sprintf("; %d are past their threshold and waiting for a worker", behind)
+	}
+
+	first := rows[0]
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: check.SeverityWarn,
+		Details:  details,
+		Reason: fmt.Sprintf("%s: %s inserts since vacuum ≥ min_inserts %s → WARN",
+			first.TableName, check.FormatNumber(first.InsertsSinceVacuum), check.FormatNumber(c.minInserts)),
+		Table: &check.Table{
+			Headers:   []string{"Table", "Rows", "Inserts Since Vacuum", "Vacuum After", "Last Vacuum", "Status"},
+			Key:       []int{0},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignRight, check.AlignRight, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	})
+
+	return report, nil
+}
@@ -0,0 +1,141 @@
+package insertvacuum_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/insertvacuum"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeQueries struct {
+	supported checktest.Result[bool]
+	tables    checktest.Result[[]db.InsertVacuumRow]
+	// minInserts is set by InsertVacuum to the argument it got.
+	minInserts *int64
+}
+
+func (f fakeQueries) InsertVacuumSupported(context.Context) (bool, error) {
+	return f.supported.Get()
+}
+
+func (f fakeQueries) InsertVacuum(_ context.Context, minInserts int64) ([]db.InsertVacuumRow, error) {
+	if f.minInserts != nil {
+		*f.minInserts = minInserts
+	}
+	return f.tables.Get()
+}
+
+func tables(rows ...db.InsertVacuumRow) fakeQueries {
+	return fakeQueries{supported: checktest.Returns(true), tables: checktest.Returns(rows)}
+}
+
+func run(t *testing.T, queries fakeQueries, cfg ...check.Config) check.Finding {
+	t.Helper()
+	return checktest.OnlyFinding(t, checktest.Run(t, insertvacuum.New(queries, cfg...)))
+}
+
+// events has the PostgreSQL 13 defaults: a 1000 row threshold and a scale
+// factor of 0.2, so 100M rows are vacuumed after about 20M inserts.
+func events(inserts int64) db.InsertVacuumRow {
+	return db.InsertVacuumRow{
+		TableName:          "public.events",
+		EstimatedRows:      100_000_000,
+		InsertsSinceVacuum: inserts,
+		SecondsSinceVacuum: 3 * 86400,
+		InsertThreshold:    1000,
+		InsertScaleFactor:  0.2,
+		AutovacuumEnabled:  true,
+	}
+}
+
+func TestInsertVacuum_Metadata(t *testing.T) {
+	t.Parallel()
+	checktest.RequireMetadata(t, insertvacuum.Metadata(), "insert-vacuum", check.CategoryVacuum)
+}
+
+func TestInsertVacuum_QueryErrors(t *testing.T) {
+	t.Parallel()
+
+	checktest.RequireError(t, insertvacuum.New(fakeQueries{supported: checktest.Fails[bool](errors.New("boom"))}))
+	checktest.RequireError(t, insertvacuum.New(fakeQueries{
+		supported: checktest.Returns(true),
+		tables:    checktest.Fails[[]db.InsertVacuumRow](errors.New("boom")),
+	}))
+}
+
+func TestInsertVacuum_BeforePG13(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, fakeQueries{supported: checktest.Returns(false)})
+	assert.Equal(t, check.SeverityNotApplicable, finding.Severity)
+	assert.Contains(t, finding.Details, "requires PostgreSQL 13+")
+}
+
+func TestInsertVacuum_NoBacklog(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, tables())
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "No table has 1.0M or more rows inserted since its last vacuum", finding.Details)
+}
+
+func TestInsertVacuum_Statuses(t *testing.T) {
+	t.Parallel()
+
+	behind := events(25_000_000)
+	below := events(5_000_000)
+	below.TableName = "public.audit_log"
+	below.SecondsSinceVacuum = -1
+	offTable := events(3_000_000)
+	offTable.TableName = "public.logs"
+	offTable.AutovacuumEnabled = false
+	offThreshold := events(2_000_000)
+	offThreshold.TableName = "public.archive"
+	offThreshold.InsertThreshold = -1
+
+	finding := run(t, tables(behind, below, offTable, offThreshold))
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Contains(t, finding.Details, "Found 4 table(s) with 1.0M or more rows inserted")
+	assert.Contains(t, finding.Details, "2 have insert-driven autovacuum turned off")
+	assert.Contains(t, finding.Details, "1 are past their threshold")
+	assert.Equal(t, "public.events: 25.0M inserts since vacuum ≥ min_inserts 1.0M → WARN", finding.Reason)
+	assert.Equal(t, [][]string{
+		{"public.events", "100.0M", "25.0M", "20.0M", "3d ago", "Past threshold; autovacuum is behind"},
+		{"public.audit_log", "100.0M", "5.0M", "20.0M", "never", "Below threshold (scale factor 0.2)"},
+		{"public.logs", "100.0M", "3.0M", "-", "3d ago", "Autovacuum disabled"},
+		{"public.archive", "100.0M", "2.0M", "-", "3d ago", "Insert threshold disabled (-1)"},
+	}, checktest.Rows(finding))
+}
+
+func TestInsertVacuum_Config(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		value string
+		want  int64
+	}{
+		{name: "custom", value: "5000000", want: 5_000_000},
+		{name: "zero", value: "0", want: 1_000_000},
+		{name: "not a number", value: "many", want: 1_000_000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got int64
+			queries := tables()
+			queries.minInserts = &got
+			cfg := check.Config{"insert-vacuum": {"min_inserts": tt.value}}
+			_, err := insertvacuum.New(queries, cfg).Check(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got, "Invalid values keep the default")
+		})
+	}
+}
@@ -0,0 +1,37 @@
+-- name: InsertVacuumSupported :one
+-- Inserts trigger autovacuum, and pg_stat_user_tables counts them, from
+-- PostgreSQL 13.
+SELECT current_setting('server_version_num')::integer >= 130000 AS supported;
+
+-- name: InsertVacuum :many
+-- Tables with at least min_inserts rows inserted since their last vacuum
+-- (PostgreSQL 13+), with the effective insert-driven autovacuum settings:
+-- per-table reloptions, or the global values they inherit. Seconds since the
+-- last vacuum are -1 when the table was never vacuumed.
+SELECT
+  (s.schemaname || '.' || s.relname)::text AS table_name
+  , greatest(c.reltuples, 0)::bigint AS estimated_rows
+  , s.n_ins_since_vacuum::bigint AS inserts_since_vacuum
+  , coalesce(
+    extract(EPOCH FROM now() - greatest(s.last_vacuum, s.last_autovacuum)), -1
+  )::bigint AS seconds_since_vacuum
+  , coalesce((
+    SELECT o.option_value::bigint
+    FROM pg_catalog.pg_options_to_table(c.reloptions) AS o
+    WHERE o.option_name = 'autovacuum_vacuum_insert_threshold'
+  ), current_setting('autovacuum_vacuum_insert_threshold')::bigint) AS insert_threshold
+  , coalesce((
+    SELECT o.option_value::float8
+    FROM pg_catalog.pg_options_to_table(c.reloptions) AS o
+    WHERE o.option_name = 'autovacuum_vacuum_insert_scale_factor'
+  ), current_setting('autovacuum_vacuum_insert_scale_factor')::float8) AS insert_scale_factor
+  , current_setting('autovacuum')::bool AND coalesce((
+    SELECT o.option_value::bool
+    FROM pg_catalog.pg_options_to_table(c.reloptions) AS o
+    WHERE o.option_name = 'autovacuum_enabled'
+  ), true) AS autovacuum_enabled
+FROM pg_catalog.pg_stat_user_tables AS s
+INNER JOIN pg_catalog.pg_class AS c ON c.oid = s.relid
+WHERE s.n_ins_since_vacuum >= sqlc.arg(min_inserts)::bigint
+ORDER BY s.n_ins_since_vacuum DESC
+LIMIT 100;
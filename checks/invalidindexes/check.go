@@ -62,40 +62,80 @@ func (c *checker) Check(ctx context.Context) (*check.Report, error) {
 		return report, nil
 	}
 
-	// One finding, all WARN: a broken index and an abandoned _ccnew/_ccold
-	// leftover are both "clean this up" work, not a 3am page. The Type column
-	// preserves the distinction; the fix for each lives in the README and
-	// `explain` output rather than inline, to keep the run summary terse.
-	var broken, leftover int
+	// One finding. A broken index is "clean this up" work, except when it
+	// is unique or backs a constraint: then the uniqueness it should
+	// guarantee was never verified for existing rows, and the planner can't
+	// rely on it, so it FAILs. An abandoned _ccnew/_ccold leftover is always
+	// WARN: the original index beside it is still valid. The Type and
+	// Enforces columns preserve the distinction; the fix for each lives in the
+	// README and `explain` output rather than inline, to keep the run summary
+	// terse.
+	var broken, leftover, enforcing int
+	severity := check.SeverityWarn
 	tableRows := make([]check.TableRow, 0, len(rows))
 	for _, row := range rows {
 		kind := "broken"
+		rowSeverity := check.SeverityWarn
+		enforced := enforces(row)
 		if row.IsLeftover {
 			kind = "leftover"
 			leftover++
 		} else {
 			broken++
+			if enforced != "" {
+				enforcing++
+				rowSeverity = check.SeverityFail
+				severity = check.SeverityFail
+			}
+		}
+		if enforced == "" {
+			enforced = "-"
 		}
 		tableRows = append(tableRows, check.TableRow{
-			Cells:    []string{row.SchemaName, row.TableName, row.IndexName, kind},
-			Severity: check.SeverityWarn,
+			Cells:    []string{row.SchemaName, row.TableName, row.IndexName, kind, enforced},
+			Severity: rowSeverity,
 		})
 	}
 
+	details := fmt.Sprintf("%s (%d broken, %d leftover)", pluralIndexes(len(rows)), broken, leftover)
+	if enforcing > 0 {
+		details += fmt.Sprintf("; %d broken index(es) enforce uniqueness or a constraint", enforcing)
+	}
+
 	report.AddFinding(check.Finding{
 		ID:       report.CheckID,
 		Name:     report.Name,
-		Severity: check.SeverityWarn,
-		Details:  fmt.Sprintf("%s (%d broken, %d leftover)", pluralIndexes(len(rows)), broken, leftover),
+		Severity: severity,
+		Details:  details,
 		Table: &check.Table{
-			Headers: []string{"Schema", "Table", "Index", "Type"},
-			Rows:    tableRows,
+			Headers:   []string{"Schema", "Table", "Index", "Type", "Enforces"},
+			Key:       []int{0, 1, 2},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.SensitiveIdentifier, check.SensitiveIdentifier},
+			Rows:      tableRows,
 		},
 	})
 
 	return report, nil
 }
 
+// enforces describes what an index guarantees beyond speeding up queries:
+// the kind of constraint it backs, "unique" for a bare unique index, or ""
+// for neither.
+func enforces(row db.BrokenIndexesRow) string {
+	switch row.ConstraintType {
+	case "p":
+		return "primary key"
+	case "u":
+		return "unique constraint"
+	case "x":
+		return "exclusion constraint"
+	}
+	if row.IsUnique {
+		return "unique"
+	}
+	return ""
+}
+
 func pluralIndexes(n int) string {
 	if n == 1 {
 		return "1 invalid index"
@@ -6,30 +6,22 @@ import (
 	"testing"
 
 	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
 	"github.com/emancu/pgdoctor/checks/invalidindexes"
 	"github.com/emancu/pgdoctor/db"
 	"github.com/stretchr/testify/require"
 )
 
-// Mock queryer for testing.
-type mockInvalidIndexesQueryer struct {
-	indexes []db.BrokenIndexesRow
-	err     error
+type fakeQueries struct {
+	indexes checktest.Result[[]db.BrokenIndexesRow]
 }
 
-func (m *mockInvalidIndexesQueryer) BrokenIndexes(context.Context) ([]db.BrokenIndexesRow, error) {
-	if m.err != nil {
-		return nil, m.err
-	}
-	return m.indexes, nil
-}
-
-func newMockQueryer(indexes []db.BrokenIndexesRow) *mockInvalidIndexesQueryer {
-	return &mockInvalidIndexesQueryer{indexes: indexes}
+func (f fakeQueries) BrokenIndexes(context.Context) ([]db.BrokenIndexesRow, error) {
+	return f.indexes.Get()
 }
 
-func newMockQueryerWithError(err error) *mockInvalidIndexesQueryer {
-	return &mockInvalidIndexesQueryer{err: err}
+func newChecker(indexes ...db.BrokenIndexesRow) check.Checker {
+	return invalidindexes.New(fakeQueries{indexes: checktest.Returns(indexes)})
 }
 
 func brokenIndex(schema, table, index string) db.BrokenIndexesRow {
@@ -40,11 +32,12 @@ func leftoverIndex(schema, table, index string) db.BrokenIndexesRow {
 	return db.BrokenIndexesRow{SchemaName: schema, TableName: table, IndexName: index, IsLeftover: true}
 }
 
-// onlyFinding returns the single finding the check always emits.
-func onlyFinding(t *testing.T, report *check.Report) check.Finding {
-	t.Helper()
-	require.Len(t, report.Results, 1, "invalid-indexes emits exactly one finding")
-	return report.Results[0]
+func uniqueIndex(schema, table, index string) db.BrokenIndexesRow {
+	return db.BrokenIndexesRow{SchemaName: schema, TableName: table, IndexName: index, IsUnique: true}
+}
+
+func constraintIndex(schema, table, index, constraintType string) db.BrokenIndexesRow {
+	return db.BrokenIndexesRow{SchemaName: schema, TableName: table, IndexName: index, IsUnique: constraintType != "x", ConstraintType: constraintType}
 }
 
 func Test_InvalidIndexes_Severity(t *testing.T) {
@@ -70,6 +63,23 @@ func Test_InvalidIndexes_Severity(t *testing.T) {
 			Indexes:  []db.BrokenIndexesRow{leftoverIndex("public", "users", "idx_users_email_ccnew")},
 			Severity: check.SeverityWarn,
 		},
+		{
+			Name:     "broken unique index - FAIL",
+			Indexes:  []db.BrokenIndexesRow{uniqueIndex("public", "users", "users_email_key")},
+			Severity: check.SeverityFail,
+		},
+		{
+			Name:     "broken exclusion constraint index - FAIL",
+			Indexes:  []db.BrokenIndexesRow{constraintIndex("public", "bookings", "bookings_room_during_excl", "x")},
+			Severity: check.SeverityFail,
+		},
+		{
+			Name: "leftover of a unique index - WARN",
+			Indexes: []db.BrokenIndexesRow{
+				{SchemaName: "public", TableName: "users", IndexName: "users_email_key_ccnew", IsLeftover: true, IsUnique: true},
+			},
+			Severity: check.SeverityWarn,
+		},
 		{
 			Name: "mixed - WARN",
 			Indexes: []db.BrokenIndexesRow{
@@ -84,15 +94,13 @@ func Test_InvalidIndexes_Severity(t *testing.T) {
 		t.Run(tc.Name, func(t *testing.T) {
 			t.Parallel()
 
-			checker := invalidindexes.New(newMockQueryer(tc.Indexes))
-			report, err := checker.Check(context.Background())
-			require.NoError(t, err)
+			report := checktest.Run(t, newChecker(tc.Indexes...))
 
 			require.Equal(t, check.CategoryIndexes, report.Category)
 			require.Equal(t, "invalid-indexes", report.CheckID)
 			require.Equal(t, tc.Severity, report.Severity)
 
-			finding := onlyFinding(t, report)
+			finding := checktest.OnlyFinding(t, report)
 			require.Equal(t, "invalid-indexes", finding.ID)
 			require.Equal(t, tc.Severity, finding.Severity)
 		})
@@ -102,11 +110,7 @@ func Test_InvalidIndexes_Severity(t *testing.T) {
 func Test_InvalidIndexes_OK_NoDetailsNoTable(t *testing.T) {
 	t.Parallel()
 
-	checker := invalidindexes.New(newMockQueryer(nil))
-	report, err := checker.Check(context.Background())
-	require.NoError(t, err)
-
-	finding := onlyFinding(t, report)
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker()))
 	require.Equal(t, check.SeverityOK, finding.Severity)
 	require.Empty(t, finding.Details, "OK finding carries no details")
 	require.Nil(t, finding.Table, "OK finding carries no table")
@@ -121,11 +125,7 @@ func Test_InvalidIndexes_ClassifiesRowsByType(t *testing.T) {
 		leftoverIndex("app", "posts", "idx_posts_created_at_ccnew"),
 	}
 
-	checker := invalidindexes.New(newMockQueryer(indexes))
-	report, err := checker.Check(context.Background())
-	require.NoError(t, err)
-
-	finding := onlyFinding(t, report)
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(indexes...)))
 	require.Equal(t, check.SeverityWarn, finding.Severity)
 
 	// Terse summary with the per-class breakdown.
@@ -138,25 +138,56 @@ func Test_InvalidIndexes_ClassifiesRowsByType(t *testing.T) {
 
 	// Table carries the broken/leftover distinction in a Type column.
 	require.NotNil(t, finding.Table)
-	require.Equal(t, []string{"Schema", "Table", "Index", "Type"}, finding.Table.Headers)
-	require.Len(t, finding.Table.Rows, 3)
-	require.Equal(t, []string{"public", "users", "idx_users_email", "broken"}, finding.Table.Rows[0].Cells)
-	require.Equal(t, []string{"app", "posts", "idx_posts_created_at_ccnew", "leftover"}, finding.Table.Rows[2].Cells)
+	require.Equal(t, []string{"Schema", "Table", "Index", "Type", "Enforces"}, finding.Table.Headers)
+	require.Equal(t, [][]string{
+		{"public", "users", "idx_users_email", "broken", "-"},
+		{"public", "orders", "idx_orders_status", "broken", "-"},
+		{"app", "posts", "idx_posts_created_at_ccnew", "leftover", "-"},
+	}, checktest.Rows(finding))
 	for _, row := range finding.Table.Rows {
 		require.Equal(t, check.SeverityWarn, row.Severity)
 	}
 }
 
+func Test_InvalidIndexes_FailsOnlyEnforcingRows(t *testing.T) {
+	t.Parallel()
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(
+		brokenIndex("public", "orders", "idx_orders_status"),
+		uniqueIndex("public", "users", "users_email_idx"),
+		constraintIndex("public", "accounts", "accounts_pkey", "p"),
+		constraintIndex("public", "users", "users_handle_key", "u"),
+		leftoverIndex("public", "users", "users_email_key_ccold"),
+	)))
+
+	require.Equal(t, check.SeverityFail, finding.Severity)
+	require.Contains(t, finding.Details, "4 broken, 1 leftover")
+	require.Contains(t, finding.Details, "3 broken index(es) enforce uniqueness or a constraint")
+
+	tests := []struct {
+		index    string
+		enforces string
+		severity check.Severity
+	}{
+		{"idx_orders_status", "-", check.SeverityWarn},
+		{"users_email_idx", "unique", check.SeverityFail},
+		{"accounts_pkey", "primary key", check.SeverityFail},
+		{"users_handle_key", "unique constraint", check.SeverityFail},
+		{"users_email_key_ccold", "-", check.SeverityWarn},
+	}
+	for _, tt := range tests {
+		row := checktest.RowWhere(t, finding, "Index", tt.index)
+		require.Equal(t, tt.enforces, row.Cells[4], tt.index)
+		require.Equal(t, tt.severity, row.Severity, tt.index)
+	}
+}
+
 func Test_InvalidIndexes_SingularPhrasing(t *testing.T) {
 	t.Parallel()
 
-	checker := invalidindexes.New(newMockQueryer([]db.BrokenIndexesRow{
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(
 		leftoverIndex("public", "users", "idx_users_email_ccnew"),
-	}))
-	report, err := checker.Check(context.Background())
-	require.NoError(t, err)
-
-	finding := onlyFinding(t, report)
+	)))
 	require.Contains(t, finding.Details, "1 invalid index ")
 	require.NotContains(t, finding.Details, "invalid indexes")
 }
@@ -164,11 +195,8 @@ func Test_InvalidIndexes_SingularPhrasing(t *testing.T) {
 func Test_InvalidIndexes_QueryError(t *testing.T) {
 	t.Parallel()
 
-	checker := invalidindexes.New(newMockQueryerWithError(fmt.Errorf("database connection error")))
-	_, err := checker.Check(context.Background())
-
-	require.Error(t, err)
-	require.ErrorContains(t, err, "invalid-indexes")
+	checker := invalidindexes.New(fakeQueries{indexes: checktest.Fails[[]db.BrokenIndexesRow](fmt.Errorf("database connection error"))})
+	checktest.RequireError(t, checker)
 }
 
 func Test_InvalidIndexes_Metadata(t *testing.T) {
@@ -176,10 +204,6 @@ func Test_InvalidIndexes_Metadata(t *testing.T) {
 
 	m := invalidindexes.Metadata()
 
-	require.Equal(t, "invalid-indexes", m.CheckID)
+	checktest.RequireMetadata(t, m, "invalid-indexes", check.CategoryIndexes)
 	require.Equal(t, "Invalid Indexes", m.Name)
-	require.Equal(t, check.CategoryIndexes, m.Category)
-	require.NotEmpty(t, m.Description)
-	require.NotEmpty(t, m.SQL)
-	require.NotEmpty(t, m.Readme)
 }
@@ -1,16 +1,26 @@
 -- name: BrokenIndexes :many
 -- Invalid indexes, flagging _ccnew/_ccold REINDEX CONCURRENTLY leftovers via
--- is_leftover. Excludes indexes a concurrent build is still working on (their
+-- is_leftover, unique indexes via is_unique, and the type of primary key
+-- (p), unique (u), or exclusion (x) constraint an index backs via
+-- constraint_type (empty if none).
+-- Excludes indexes a concurrent build is still working on (their
 -- index_relid is in pg_stat_progress_create_index): in flight, not broken.
 SELECT
   n.nspname::text AS schema_name
   , tbl.relname::text AS table_name
   , idx.relname::text AS index_name
   , (idx.relname ~ '_cc(new|old)[0-9]*$') AS is_leftover
+  , i.indisunique AS is_unique
+  , COALESCE(con.contype::text, '')::text AS constraint_type
 FROM pg_index AS i
 INNER JOIN pg_class AS idx ON i.indexrelid = idx.oid
 INNER JOIN pg_class AS tbl ON i.indrelid = tbl.oid
 INNER JOIN pg_namespace AS n ON tbl.relnamespace = n.oid
+LEFT JOIN pg_constraint AS con
+  ON
+    i.indexrelid = con.conindid
+    AND i.indrelid = con.conrelid
+    AND con.contype IN ('p', 'u', 'x')
 WHERE NOT i.indisvalid
   AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
   AND NOT EXISTS (
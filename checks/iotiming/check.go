@@ -0,0 +1,134 @@
+// Package iotiming implements a check for statistics settings that leave I/O timing, function statistics, or query text out.
+package iotiming
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type IoTimingQueries interface {
+	IoTiming(context.Context) (db.IoTimingRow, error)
+}
+
+type checker struct {
+	queries IoTimingQueries
+}
+
+// minQuerySize is the track_activity_query_size from which typical ORM
+// queries fit in pg_stat_activity.
+const minQuerySize = 4096
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryConfigs,
+		CheckID:     "io-timing",
+		Name:        "I/O Timing",
+		Description: "Checks that track_io_timing is on, track_functions covers the database's functions, and track_activity_query_size fits its queries",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries IoTimingQueries, _ ...check.Config) check.Checker {
+	return &checker{queries: queries}
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	settings, err := c.queries.IoTiming(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	ioTiming := row("track_io_timing", settings.TrackIoTiming, "on")
+	if settings.TrackIoTiming != "on" {
+		ioTiming.warn("Off: pg_stat_statements and EXPLAIN (ANALYZE, BUFFERS) show no time spent reading and writing")
+	}
+
+	// Function statistics only matter where there are functions to count.
+	functions := row("track_functions", settings.TrackFunctions, "pl")
+	if settings.TrackFunctions == "none" {
+		if settings.PlFunctions > 0 {
+			functions.warn(fmt.Sprintf("None: pg_stat_user_functions is empty for %d function(s)", settings.PlFunctions))
+		} else {
+			functions.status = "No functions to track"
+		}
+	}
+
+	querySize := row("track_activity_query_size", check.FormatBytes(settings.TrackActivityQuerySizeBytes), "≥ "+check.FormatBytes(minQuerySize))
+	switch {
+	case settings.TrackActivityQuerySizeBytes < minQuerySize && settings.TruncatedQueries > 0:
+		querySize.warn(fmt.Sprintf("Cuts %d running query text(s) in pg_stat_activity", settings.TruncatedQueries))
+	case settings.TrackActivityQuerySizeBytes < minQuerySize:
+		querySize.warn("Cuts longer query text in pg_stat_activity")
+	case settings.TruncatedQueries > 0:
+		querySize.status = fmt.Sprintf("%d running query text(s) at the limit", settings.TruncatedQueries)
+	}
+
+	rows := []*settingRow{ioTiming, functions, querySize}
+	severity := check.SeverityOK
+	var issues int
+	tableRows := make([]check.TableRow, 0, len(rows))
+	for _, r := range rows {
+		severity = max(severity, r.severity)
+		if r.severity != check.SeverityOK {
+			issues++
+		}
+		tableRows = append(tableRows, check.TableRow{
+			Cells:    []string{r.parameter, r.current, r.expected, r.status},
+			Severity: r.severity,
+		})
+	}
+
+	details := "I/O timing, function statistics, and query text are tracked"
+	if issues > 0 {
+		details = fmt.Sprintf("%d statistics setting(s) leave data out of pg_stat_statements, EXPLAIN, or pg_stat_activity, "+
+			"so slow I/O, slow functions, or long queries cannot be told apart. track_io_timing costs little on modern clocks; "+
+			"pg_test_timing measures it", issues)
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: severity,
+		Details:  details,
+		Table: &check.Table{
+			Headers: []string{"Parameter", "Current", "Expected", "Status"},
+			Rows:    tableRows,
+		},
+	})
+
+	return report, nil
+}
+
+// settingRow is one row of the report table.
+type settingRow struct {
+	parameter string
+	current   string
+	expected  string
+	status    string
+	severity  check.Severity
+}
+
+func row(parameter, current, expected string) *settingRow {
+	return &settingRow{parameter: parameter, current: current, expected: expected, status: "OK", severity: check.SeverityOK}
+}
+
+func (r *settingRow) warn(status string) {
+	r.status, r.severity = status, check.SeverityWarn
+}
@@ -0,0 +1,93 @@
+package iotiming_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/iotiming"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeQueries struct {
+	settings checktest.Result[db.IoTimingRow]
+}
+
+func (f fakeQueries) IoTiming(context.Context) (db.IoTimingRow, error) {
+	return f.settings.Get()
+}
+
+func run(t *testing.T, row db.IoTimingRow) check.Finding {
+	t.Helper()
+	return checktest.OnlyFinding(t, checktest.Run(t, iotiming.New(fakeQueries{settings: checktest.Returns(row)})))
+}
+
+func tracked() db.IoTimingRow {
+	return db.IoTimingRow{TrackIoTiming: "on", TrackFunctions: "pl", TrackActivityQuerySizeBytes: 8192, PlFunctions: 12}
+}
+
+func TestIoTiming_Metadata(t *testing.T) {
+	t.Parallel()
+	checktest.RequireMetadata(t, iotiming.Metadata(), "io-timing", check.CategoryConfigs)
+}
+
+func TestIoTiming_QueryError(t *testing.T) {
+	t.Parallel()
+	checktest.RequireError(t, iotiming.New(fakeQueries{settings: checktest.Fails[db.IoTimingRow](errors.New("boom"))}))
+}
+
+func TestIoTiming_Tracked(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, tracked())
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "I/O timing, function statistics, and query text are tracked", finding.Details)
+	assert.Equal(t, [][]string{
+		{"track_io_timing", "on", "on", "OK"},
+		{"track_functions", "pl", "pl", "OK"},
+		{"track_activity_query_size", "8.0KiB", "≥ 4.0KiB", "OK"},
+	}, checktest.Rows(finding))
+}
+
+func TestIoTiming_Defaults(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, db.IoTimingRow{TrackIoTiming: "off", TrackFunctions: "none", TrackActivityQuerySizeBytes: 1024, PlFunctions: 12, TruncatedQueries: 3})
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Contains(t, finding.Details, "3 statistics setting(s) leave data out")
+	assert.Equal(t, [][]string{
+		{"track_io_timing", "off", "on", "Off: pg_stat_statements and EXPLAIN (ANALYZE, BUFFERS) show no time spent reading and writing"},
+		{"track_functions", "none", "pl", "None: pg_stat_user_functions is empty for 12 function(s)"},
+		{"track_activity_query_size", "1.0KiB", "≥ 4.0KiB", "Cuts 3 running query text(s) in pg_stat_activity"},
+	}, checktest.Rows(finding))
+}
+
+func TestIoTiming_NoFunctions(t *testing.T) {
+	t.Parallel()
+
+	row := tracked()
+	row.TrackFunctions, row.PlFunctions = "none", 0
+	finding := run(t, row)
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, []string{"track_functions", "none", "pl", "No functions to track"}, checktest.Rows(finding)[1])
+}
+
+func TestIoTiming_QuerySize(t *testing.T) {
+	t.Parallel()
+
+	row := tracked()
+	row.TrackActivityQuerySizeBytes = 2048
+	finding := run(t, row)
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Equal(t, "Cuts longer query text in pg_stat_activity", checktest.Rows(finding)[2][3])
+
+	// A large enough limit is OK even when a query reaches it.
+	row = tracked()
+	row.TruncatedQueries = 1
+	finding = run(t, row)
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "1 running query text(s) at the limit", checktest.Rows(finding)[2][3])
+}
@@ -0,0 +1,29 @@
+-- name: IoTiming :one
+-- The statistics settings other checks and EXPLAIN rely on, as the session
+-- sees them, with the functions in procedural languages, outside extensions,
+-- that track_functions = pl would count, and the current queries whose text
+-- reached track_activity_query_size and was cut.
+SELECT
+  current_setting('track_io_timing')::text AS track_io_timing
+  , current_setting('track_functions')::text AS track_functions
+  , pg_catalog.pg_size_bytes(current_setting('track_activity_query_size'))::bigint AS track_activity_query_size_bytes
+  , (
+    SELECT count(*)::int
+    FROM pg_catalog.pg_proc AS p
+    JOIN pg_catalog.pg_language AS l ON l.oid = p.prolang
+    JOIN pg_catalog.pg_namespace AS n ON n.oid = p.pronamespace
+    WHERE
+      l.lanname NOT IN ('internal', 'c', 'sql')
+      AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+      AND NOT EXISTS (
+        SELECT 1 FROM pg_catalog.pg_depend AS d
+        WHERE d.classid = 'pg_catalog.pg_proc'::regclass AND d.objid = p.oid AND d.deptype = 'e'
+      )
+  ) AS pl_functions
+  , (
+    SELECT count(*)::int
+    FROM pg_catalog.pg_stat_activity AS a
+    WHERE
+      a.backend_type = 'client backend'
+      AND octet_length(a.query) >= pg_catalog.pg_size_bytes(current_setting('track_activity_query_size')) - 1
+  ) AS truncated_queries;
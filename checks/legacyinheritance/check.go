@@ -0,0 +1,124 @@
+// Package legacyinheritance implements a check for tables split with old-style table inheritance instead of declarative partitioning.
+package legacyinheritance
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+// maxListedChildren is how many child tables a row names before counting the
+// rest.
+const maxListedChildren = 3
+
+type LegacyInheritanceQueries interface {
+	LegacyInheritance(context.Context) ([]db.LegacyInheritanceRow, error)
+}
+
+type checker struct {
+	queries LegacyInheritanceQueries
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategorySchema,
+		CheckID:     "legacy-inheritance",
+		Name:        "Legacy Inheritance",
+		Description: "Finds tables split with old-style table inheritance, often a pre-PostgreSQL 10 partitioning scheme, that should move to declarative partitioning",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries LegacyInheritanceQueries, _ ...check.Config) check.Checker {
+	return &checker{
+		queries: queries,
+	}
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	rows, err := c.queries.LegacyInheritance(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	if len(rows) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  "No table has children through table inheritance outside declarative partitioning",
+		})
+		return report, nil
+	}
+
+	var routed int
+	tableRows := make([]check.TableRow, 0, len(rows))
+	for _, row := range rows {
+		scheme := "Inheritance"
+		if row.InsertTrigger {
+			routed++
+			scheme = "Trigger-routed partitioning"
+		}
+		tableRows = append(tableRows, check.TableRow{
+			Cells: []string{
+				row.ParentTable,
+				check.FormatNumber(int64(len(row.ChildTables))),
+				listChildren(row.ChildTables),
+				check.FormatBytes(row.TotalBytes),
+				scheme,
+			},
+			Severity: check.SeverityWarn,
+		})
+	}
+
+	details := fmt.Sprintf("Found %d table(s) with children through table inheritance rather than declarative partitioning. "+
+		"Inheritance gets none of partitioning's pruning at execution, partition-wise joins, or indexes and keys across children",
+		len(rows))
+	if routed > 0 {
+		details += fmt.Sprintf("; %d route inserts with a trigger, a partitioning scheme from before PostgreSQL 10", routed)
+	}
+
+	first := rows[0]
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: check.SeverityWarn,
+		Details:  details,
+		Reason:   fmt.Sprintf("%s: %d child table(s) by inheritance, not partitioning → WARN", first.ParentTable, len(first.ChildTables)),
+		Table: &check.Table{
+			Headers:   []string{"Parent", "Children", "Child Tables", "Total Size", "Scheme"},
+			Key:       []int{0},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignRight, check.AlignLeft, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.NotSensitive, check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	})
+
+	return report, nil
+}
+
+// listChildren names the first maxListedChildren child tables and counts the
+// rest.
+func listChildren(children []string) string {
+	if len(children) <= maxListedChildren {
+		return strings.Join(children, ", ")
+	}
+	return fmt.Sprintf("%s, +%d more", strings.Join(children[:maxListedChildren], ", "), len(children)-maxListedChildren)
+}
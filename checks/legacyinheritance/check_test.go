@@ -0,0 +1,74 @@
+package legacyinheritance_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/legacyinheritance"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeQueries struct {
+	parents checktest.Result[[]db.LegacyInheritanceRow]
+}
+
+func (f fakeQueries) LegacyInheritance(context.Context) ([]db.LegacyInheritanceRow, error) {
+	return f.parents.Get()
+}
+
+func run(t *testing.T, rows ...db.LegacyInheritanceRow) check.Finding {
+	t.Helper()
+	queries := fakeQueries{parents: checktest.Returns(rows)}
+	return checktest.OnlyFinding(t, checktest.Run(t, legacyinheritance.New(queries)))
+}
+
+func TestLegacyInheritance_Metadata(t *testing.T) {
+	t.Parallel()
+	checktest.RequireMetadata(t, legacyinheritance.Metadata(), "legacy-inheritance", check.CategorySchema)
+}
+
+func TestLegacyInheritance_QueryError(t *testing.T) {
+	t.Parallel()
+	checktest.RequireError(t, legacyinheritance.New(fakeQueries{parents: checktest.Fails[[]db.LegacyInheritanceRow](errors.New("boom"))}))
+}
+
+func TestLegacyInheritance_None(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t)
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "No table has children through table inheritance outside declarative partitioning", finding.Details)
+}
+
+func TestLegacyInheritance_Parents(t *testing.T) {
+	t.Parallel()
+
+	events := db.LegacyInheritanceRow{
+		ParentTable: "public.events",
+		ChildTables: []string{
+			"public.events_2026_01", "public.events_2026_02", "public.events_2026_03",
+			"public.events_2026_04", "public.events_2026_05",
+		},
+		TotalBytes:    50 * check.GiB,
+		InsertTrigger: true,
+	}
+	audit := db.LegacyInheritanceRow{
+		ParentTable: "public.audit",
+		ChildTables: []string{"billing.audit", "public.audit_archive"},
+		TotalBytes:  2 * check.MiB,
+	}
+
+	finding := run(t, events, audit)
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Contains(t, finding.Details, "Found 2 table(s) with children through table inheritance")
+	assert.Contains(t, finding.Details, "1 route inserts with a trigger")
+	assert.Equal(t, "public.events: 5 child table(s) by inheritance, not partitioning → WARN", finding.Reason)
+	assert.Equal(t, [][]string{
+		{"public.events", "5", "public.events_2026_01, public.events_2026_02, public.events_2026_03, +2 more", "50.0GiB", "Trigger-routed partitioning"},
+		{"public.audit", "2", "billing.audit, public.audit_archive", "2.0MiB", "Inheritance"},
+	}, checktest.Rows(finding))
+}
@@ -0,0 +1,32 @@
+-- name: LegacyInheritance :many
+-- Tables inherited from through old-style table inheritance rather than
+-- declarative partitioning: parents that are not partitioned tables (relkind
+-- 'p'), with their children, the size of parent and children together, and
+-- whether a BEFORE INSERT row trigger on the parent routes rows to the
+-- children, as pre-10 partitioning schemes did. TimescaleDB hypertables,
+-- whose chunks inherit from them, are left out.
+SELECT
+  (pn.nspname || '.' || p.relname)::text AS parent_table
+  , array_agg((cn.nspname || '.' || c.relname)::text ORDER BY cn.nspname, c.relname)::text[] AS child_tables
+  , (pg_total_relation_size(p.oid) + sum(pg_total_relation_size(c.oid)))::bigint AS total_bytes
+  , EXISTS (
+    SELECT 1
+    FROM pg_trigger AS t
+    WHERE
+      t.tgrelid = p.oid
+      AND NOT t.tgisinternal
+      -- TRIGGER_TYPE_ROW | TRIGGER_TYPE_BEFORE | TRIGGER_TYPE_INSERT
+      AND (t.tgtype & 7) = 7
+  ) AS insert_trigger
+FROM pg_inherits AS i
+INNER JOIN pg_class AS p ON i.inhparent = p.oid
+INNER JOIN pg_namespace AS pn ON p.relnamespace = pn.oid
+INNER JOIN pg_class AS c ON i.inhrelid = c.oid
+INNER JOIN pg_namespace AS cn ON c.relnamespace = cn.oid
+WHERE
+  p.relkind <> 'p'
+  AND NOT c.relispartition
+  AND pn.nspname NOT IN ('pg_catalog', 'information_schema')
+  AND cn.nspname !~ '^_timescaledb'
+GROUP BY p.oid, pn.nspname, p.relname
+ORDER BY total_bytes DESC, parent_table;
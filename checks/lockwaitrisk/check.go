@@ -0,0 +1,156 @@
+// Package lockwaitrisk implements a check for an unbounded lock_timeout while sessions already wait long on locks.
+package lockwaitrisk
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type LockWaitRiskQueries interface {
+	LockTimeout(context.Context) (int64, error)
+	LockWaits(context.Context) ([]db.LockWaitsRow, error)
+}
+
+type checker struct {
+	queries        LockWaitRiskQueries
+	minWaitSeconds int64 // default: 30
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryPerformance,
+		CheckID:     "lock-wait-risk",
+		Name:        "Lock Wait Risk",
+		Description: "Flags a lock_timeout of 0 while sessions wait long on locks, so DDL queued behind them would wait forever and block every query after it",
+		Readme:      readme,
+		SQL:         querySQL,
+		ClusterWide: true,
+	}
+}
+
+func New(queries LockWaitRiskQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries:        queries,
+		minWaitSeconds: 30,
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			if v, ok := myCfg["min_wait_seconds"]; ok {
+				if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+					c.minWaitSeconds = n
+				}
+			}
+		}
+	}
+	return c
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	timeoutMs, err := c.queries.LockTimeout(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+	waits, err := c.queries.LockWaits(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	setting := "lock_timeout is 0 (unbounded)"
+	if timeoutMs > 0 {
+		setting = fmt.Sprintf("lock_timeout is %dms", timeoutMs)
+	}
+
+	// Only an unbounded lock_timeout turns a long wait into a risk; the
+	// waits are listed either way.
+	rowSeverity := check.SeverityOK
+	if timeoutMs == 0 {
+		rowSeverity = check.SeverityWarn
+	}
+	var tableRows []check.TableRow
+	for _, w := range waits {
+		if w.WaitSeconds < c.minWaitSeconds {
+			continue
+		}
+		tableRows = append(tableRows, check.TableRow{
+			Cells: []string{
+				strconv.Itoa(int(w.Pid)),
+				w.Username,
+				w.DatabaseName,
+				check.FormatDurationSec(w.WaitSeconds),
+				formatPids(w.BlockingPids),
+				truncateString(w.Query, 50),
+			},
+			Severity: rowSeverity,
+		})
+	}
+
+	if len(tableRows) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  fmt.Sprintf("%s; no session has waited on a lock for %s or more", setting, check.FormatDurationSec(c.minWaitSeconds)),
+		})
+		return report, nil
+	}
+
+	var details string
+	if timeoutMs == 0 {
+		details = fmt.Sprintf("%s while %d session(s) have waited on locks for %s or more, the longest for %s: "+
+			"a DDL statement queued behind the transactions holding them would wait forever, and block every query on the table after it",
+			setting, len(tableRows), check.FormatDurationSec(c.minWaitSeconds), check.FormatDurationSec(waits[0].WaitSeconds))
+	} else {
+		details = fmt.Sprintf("%s, so statements give up on a lock instead of queueing forever; %d session(s) have waited on locks for %s or more",
+			setting, len(tableRows), check.FormatDurationSec(c.minWaitSeconds))
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: rowSeverity,
+		Details:  details,
+		Table: &check.Table{
+			Headers:   []string{"PID", "User", "Database", "Waiting", "Blocked By", "Query"},
+			Sensitive: []check.Sensitivity{check.NotSensitive, check.SensitiveIdentifier, check.SensitiveIdentifier, check.NotSensitive, check.NotSensitive, check.SensitiveQuery},
+			Rows:      tableRows,
+		},
+	})
+
+	return report, nil
+}
+
+func formatPids(pids []int32) string {
+	if len(pids) == 0 {
+		return "-"
+	}
+	parts := make([]string, len(pids))
+	for i, pid := range pids {
+		parts[i] = strconv.Itoa(int(pid))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func truncateString(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen-3] + "..."
+}
@@ -0,0 +1,104 @@
+package lockwaitrisk_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/lockwaitrisk"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeQueries struct {
+	timeout checktest.Result[int64]
+	waits   checktest.Result[[]db.LockWaitsRow]
+}
+
+func (f fakeQueries) LockTimeout(context.Context) (int64, error) {
+	return f.timeout.Get()
+}
+
+func (f fakeQueries) LockWaits(context.Context) ([]db.LockWaitsRow, error) {
+	return f.waits.Get()
+}
+
+func run(t *testing.T, timeoutMs int64, waits []db.LockWaitsRow, cfg ...check.Config) check.Finding {
+	t.Helper()
+	queries := fakeQueries{timeout: checktest.Returns(timeoutMs), waits: checktest.Returns(waits)}
+	return checktest.OnlyFinding(t, checktest.Run(t, lockwaitrisk.New(queries, cfg...)))
+}
+
+func waits() []db.LockWaitsRow {
+	return []db.LockWaitsRow{
+		{Pid: 201, Username: "migrator", DatabaseName: "app", WaitSeconds: 300, BlockingPids: []int32{101}, Query: "ALTER TABLE orders ADD COLUMN note text"},
+		{Pid: 202, Username: "app", DatabaseName: "app", WaitSeconds: 290, BlockingPids: []int32{201, 101}, Query: "SELECT * FROM orders WHERE id = $1"},
+		{Pid: 203, Username: "app", DatabaseName: "app", WaitSeconds: 2, BlockingPids: []int32{101}, Query: "UPDATE orders SET status = $1 WHERE id = $2"},
+	}
+}
+
+func TestLockWaitRisk_Metadata(t *testing.T) {
+	t.Parallel()
+	checktest.RequireMetadata(t, lockwaitrisk.Metadata(), "lock-wait-risk", check.CategoryPerformance)
+}
+
+func TestLockWaitRisk_QueryErrors(t *testing.T) {
+	t.Parallel()
+
+	checktest.RequireError(t, lockwaitrisk.New(fakeQueries{timeout: checktest.Fails[int64](errors.New("boom"))}))
+	checktest.RequireError(t, lockwaitrisk.New(fakeQueries{
+		timeout: checktest.Returns(int64(0)),
+		waits:   checktest.Fails[[]db.LockWaitsRow](errors.New("boom")),
+	}))
+}
+
+func TestLockWaitRisk_UnboundedWithLongWaits(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, 0, waits())
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Equal(t, "lock_timeout is 0 (unbounded) while 2 session(s) have waited on locks for 30s or more, the longest for 5m: "+
+		"a DDL statement queued behind the transactions holding them would wait forever, and block every query on the table after it", finding.Details)
+	assert.Equal(t, [][]string{
+		{"201", "migrator", "app", "5m", "101", "ALTER TABLE orders ADD COLUMN note text"},
+		{"202", "app", "app", "4m", "201, 101", "SELECT * FROM orders WHERE id = $1"},
+	}, checktest.Rows(finding))
+}
+
+func TestLockWaitRisk_BoundedTimeout(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, 5000, waits())
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "lock_timeout is 5000ms, so statements give up on a lock instead of queueing forever; 2 session(s) have waited on locks for 30s or more", finding.Details)
+	assert.Len(t, checktest.Rows(finding), 2)
+}
+
+func TestLockWaitRisk_NoLongWaits(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, 0, waits()[2:])
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "lock_timeout is 0 (unbounded); no session has waited on a lock for 30s or more", finding.Details)
+	assert.Nil(t, finding.Table)
+
+	finding = run(t, 0, nil)
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+}
+
+func TestLockWaitRisk_MinWaitSeconds(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, 0, waits(), check.Config{"lock-wait-risk": {"min_wait_seconds": "1"}})
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Len(t, checktest.Rows(finding), 3)
+
+	finding = run(t, 0, waits(), check.Config{"lock-wait-risk": {"min_wait_seconds": "600"}})
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+
+	// Invalid values keep the default.
+	finding = run(t, 0, waits(), check.Config{"lock-wait-risk": {"min_wait_seconds": "0"}})
+	assert.Len(t, checktest.Rows(finding), 2)
+}
@@ -0,0 +1,24 @@
+-- name: LockTimeout :one
+-- The lock_timeout a new session starts with, in milliseconds: reset_val
+-- leaves out pgdoctor's own SET, but includes the database and role
+-- overrides of its session (as in session-settings).
+SELECT s.reset_val::bigint AS lock_timeout_ms
+FROM pg_catalog.pg_settings AS s
+WHERE s.name = 'lock_timeout';
+
+-- name: LockWaits :many
+-- Sessions of every database waiting for a heavyweight lock, longest first,
+-- with the sessions blocking them. A statement waits from its start, so the
+-- wait is measured from query_start.
+SELECT
+  a.pid
+  , coalesce(a.usename::text, '')::text AS username
+  , coalesce(a.datname::text, '')::text AS database_name
+  , coalesce(extract(EPOCH FROM now() - a.query_start), 0)::bigint AS wait_seconds
+  , pg_catalog.pg_blocking_pids(a.pid)::int [] AS blocking_pids
+  , left(coalesce(a.query, ''), 200)::text AS query
+FROM pg_catalog.pg_stat_activity AS a
+WHERE
+  a.wait_event_type = 'Lock'
+  AND a.pid <> pg_catalog.pg_backend_pid()
+ORDER BY wait_seconds DESC, a.pid;
@@ -0,0 +1,204 @@
+// Package namingissues implements a check for table, index, and column names that need quoting or may have been truncated.
+package namingissues
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type NamingIssuesQueries interface {
+	NamingIssues(context.Context) ([]db.NamingIssuesRow, error)
+}
+
+type checker struct {
+	queries         NamingIssuesQueries
+	ignoreMixedCase bool // default: false
+}
+
+// maxIdentifierBytes is NAMEDATALEN - 1: PostgreSQL cuts longer identifiers
+// to this many bytes, with only a NOTICE.
+const maxIdentifierBytes = 63
+
+// issue is one way a name is awkward to use, in the order they are reported.
+type issue int
+
+const (
+	issueTruncated issue = iota
+	issueReserved
+	issueMixedCase
+	issueSpecial
+)
+
+func (i issue) String() string {
+	switch i {
+	case issueTruncated:
+		return "63-byte limit"
+	case issueReserved:
+		return "reserved word"
+	case issueMixedCase:
+		return "mixed case"
+	default:
+		return "special characters"
+	}
+}
+
+func (i issue) risk() string {
+	switch i {
+	case issueTruncated:
+		return "May have been cut: a longer name in DDL or an ORM resolves to this one, and two names sharing the first 63 bytes collide"
+	case issueReserved:
+		return "A syntax error unless quoted in every query"
+	case issueMixedCase:
+		return "Not found unless quoted in every query: unquoted, it is folded to lowercase"
+	default:
+		return "A syntax error unless quoted in every query"
+	}
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategorySchema,
+		CheckID:     "naming-issues",
+		Name:        "Naming Issues",
+		Description: "Flags table, index, and column names that are reserved words, mixed-case, or contain special characters, so they must be quoted, and names at the 63-byte limit that may have been truncated",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries NamingIssuesQueries, cfg ...check.Config) check.Checker {
+	c := &checker{queries: queries}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			c.ignoreMixedCase = myCfg["ignore_mixed_case"] == "true"
+		}
+	}
+	return c
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	rows, err := c.queries.NamingIssues(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	var tableRows []check.TableRow
+	var counts [issueSpecial + 1]int
+	var reason string
+	for _, row := range rows {
+		issues := c.issues(row)
+		if len(issues) == 0 {
+			continue
+		}
+
+		object := row.SchemaName + "." + row.TableName
+		if row.ColumnName != "" {
+			object += "." + row.ColumnName
+		}
+		labels := make([]string, len(issues))
+		for i, is := range issues {
+			counts[is]++
+			labels[i] = is.String()
+		}
+		if reason == "" {
+			reason = fmt.Sprintf("%s: %s name, %s → WARN", object, row.ObjectKind, labels[0])
+		}
+		tableRows = append(tableRows, check.TableRow{
+			Cells:    []string{object, row.ObjectKind, strings.Join(labels, ", "), issues[0].risk()},
+			Severity: check.SeverityWarn,
+		})
+	}
+
+	if len(tableRows) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  "No table, index, or column names need quoting or are at the 63-byte limit",
+		})
+		return report, nil
+	}
+
+	var parts []string
+	for i, n := range counts {
+		if n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, issue(i)))
+		}
+	}
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: check.SeverityWarn,
+		Details: fmt.Sprintf("Found %d name(s) that must be quoted or may have been truncated (%s); "+
+			"tools, ad hoc queries, and migrations that do not quote them fail or hit the wrong object",
+			len(tableRows), strings.Join(parts, ", ")),
+		Reason: reason,
+		Table: &check.Table{
+			Headers:   []string{"Object", "Kind", "Issue", "Risk"},
+			Key:       []int{0, 1},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	})
+
+	return report, nil
+}
+
+// issues returns the problems of row's name, most serious first. Names the
+// query returns only because quote_ident quotes them, such as non-ASCII
+// letters, which PostgreSQL accepts unquoted, have none.
+func (c *checker) issues(row db.NamingIssuesRow) []issue {
+	name := row.TableName
+	if row.ColumnName != "" {
+		name = row.ColumnName
+	}
+
+	var issues []issue
+	if row.NameBytes >= maxIdentifierBytes {
+		issues = append(issues, issueTruncated)
+	}
+	if row.ReservedWord {
+		issues = append(issues, issueReserved)
+	}
+	// Unquoted identifiers are folded to lowercase for ASCII letters only.
+	if !c.ignoreMixedCase && strings.ContainsFunc(name, func(r rune) bool { return r >= 'A' && r <= 'Z' }) {
+		issues = append(issues, issueMixedCase)
+	}
+	if needsQuotes(name) {
+		issues = append(issues, issueSpecial)
+	}
+	return issues
+}
+
+// needsQuotes reports whether name has characters an unquoted identifier
+// cannot: it must start with a letter or underscore, followed by letters,
+// digits, underscores, or dollar signs.
+func needsQuotes(name string) bool {
+	for i, r := range name {
+		switch {
+		case unicode.IsLetter(r) || r == '_':
+		case i > 0 && (unicode.IsDigit(r) || r == '$'):
+		default:
+			return true
+		}
+	}
+	return name == ""
+}
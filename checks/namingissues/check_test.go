@@ -0,0 +1,109 @@
+package namingissues_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/namingissues"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeQueries struct {
+	names checktest.Result[[]db.NamingIssuesRow]
+}
+
+func (f fakeQueries) NamingIssues(context.Context) ([]db.NamingIssuesRow, error) {
+	return f.names.Get()
+}
+
+func run(t *testing.T, cfg map[string]string, names ...db.NamingIssuesRow) check.Finding {
+	t.Helper()
+	checker := namingissues.New(fakeQueries{names: checktest.Returns(names)}, check.Config{"naming-issues": cfg})
+	return checktest.OnlyFinding(t, checktest.Run(t, checker))
+}
+
+func table(name string) db.NamingIssuesRow {
+	return db.NamingIssuesRow{SchemaName: "public", TableName: name, ObjectKind: "table", NameBytes: int32(len(name))}
+}
+
+func column(tableName, name string) db.NamingIssuesRow {
+	return db.NamingIssuesRow{SchemaName: "public", TableName: tableName, ColumnName: name, ObjectKind: "column", NameBytes: int32(len(name))}
+}
+
+func TestNamingIssues_Metadata(t *testing.T) {
+	t.Parallel()
+	checktest.RequireMetadata(t, namingissues.Metadata(), "naming-issues", check.CategorySchema)
+}
+
+func TestNamingIssues_QueryError(t *testing.T) {
+	t.Parallel()
+	checktest.RequireError(t, namingissues.New(fakeQueries{names: checktest.Fails[[]db.NamingIssuesRow](errors.New("boom"))}))
+}
+
+func TestNamingIssues_None(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, nil)
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "No table, index, or column names need quoting or are at the 63-byte limit", finding.Details)
+}
+
+func TestNamingIssues_Found(t *testing.T) {
+	t.Parallel()
+
+	user := table("user")
+	user.ReservedWord = true
+	longName := strings.Repeat("x", 63)
+	index := db.NamingIssuesRow{SchemaName: "public", TableName: longName, ObjectKind: "index", NameBytes: 63}
+
+	finding := run(t, nil,
+		user,
+		column("user", "createdAt"),
+		table("order-items"),
+		index,
+		table("café"),
+	)
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Contains(t, finding.Details, "Found 4 name(s) that must be quoted or may have been truncated "+
+		"(1 63-byte limit, 1 reserved word, 1 mixed case, 1 special characters)")
+	assert.Equal(t, "public.user: table name, reserved word → WARN", finding.Reason)
+
+	rows := checktest.Rows(finding)
+	require.Len(t, rows, 4, "non-ASCII letters are valid unquoted")
+	assert.Equal(t, [][]string{
+		{"public.user", "table", "reserved word"},
+		{"public.user.createdAt", "column", "mixed case"},
+		{"public.order-items", "table", "special characters"},
+		{"public." + longName, "index", "63-byte limit"},
+	}, [][]string{rows[0][:3], rows[1][:3], rows[2][:3], rows[3][:3]})
+	assert.Contains(t, rows[1][3], "folded to lowercase")
+	assert.Contains(t, rows[3][3], "first 63 bytes collide")
+}
+
+func TestNamingIssues_SeveralIssues(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, nil, table("Order Items"))
+	assert.Equal(t, [][]string{
+		{"public.Order Items", "table", "mixed case, special characters", "Not found unless quoted in every query: unquoted, it is folded to lowercase"},
+	}, checktest.Rows(finding))
+}
+
+func TestNamingIssues_IgnoreMixedCase(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, map[string]string{"ignore_mixed_case": "true"},
+		column("Users", "createdAt"),
+		column("Users", "2fa_secret"),
+	)
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Equal(t, [][]string{
+		{"public.Users.2fa_secret", "column", "special characters", "A syntax error unless quoted in every query"},
+	}, checktest.Rows(finding), "only mixed case is ignored")
+}
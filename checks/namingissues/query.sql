@@ -0,0 +1,68 @@
+-- name: NamingIssues :many
+-- Relations and their columns, outside system schemas and extensions, whose
+-- names need quoting or are at the 63-byte limit (NAMEDATALEN - 1) that
+-- longer names are cut to. reserved_word marks keywords that cannot be a
+-- table or column name unquoted (reserved, and type or function names).
+WITH objects AS (
+  SELECT
+    n.nspname::text AS schema_name
+    , c.relname::text AS table_name
+    , ''::text AS column_name
+    , c.relname::text AS object_name
+    , CASE c.relkind
+      WHEN 'r' THEN 'table'
+      WHEN 'p' THEN 'table'
+      WHEN 'v' THEN 'view'
+      WHEN 'm' THEN 'materialized view'
+      WHEN 'f' THEN 'foreign table'
+      WHEN 'S' THEN 'sequence'
+      ELSE 'index'
+    END::text AS object_kind
+  FROM pg_catalog.pg_class AS c
+  INNER JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+  WHERE
+    c.relkind IN ('r', 'p', 'v', 'm', 'f', 'S', 'i', 'I')
+    AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
+    AND n.nspname NOT LIKE 'pg\_temp\_%'
+    AND NOT EXISTS (
+      SELECT 1 FROM pg_catalog.pg_depend AS d
+      WHERE d.classid = 'pg_catalog.pg_class'::regclass AND d.objid = c.oid AND d.deptype = 'e'
+    )
+
+  UNION ALL
+
+  SELECT
+    n.nspname::text
+    , c.relname::text
+    , a.attname::text
+    , a.attname::text
+    , 'column'::text
+  FROM pg_catalog.pg_attribute AS a
+  INNER JOIN pg_catalog.pg_class AS c ON c.oid = a.attrelid
+  INNER JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+  WHERE
+    a.attnum > 0
+    AND NOT a.attisdropped
+    AND c.relkind IN ('r', 'p', 'v', 'm', 'f')
+    AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
+    AND n.nspname NOT LIKE 'pg\_temp\_%'
+    AND NOT EXISTS (
+      SELECT 1 FROM pg_catalog.pg_depend AS d
+      WHERE d.classid = 'pg_catalog.pg_class'::regclass AND d.objid = c.oid AND d.deptype = 'e'
+    )
+)
+SELECT
+  o.schema_name
+  , o.table_name
+  , o.column_name
+  , o.object_kind
+  , EXISTS (
+    SELECT 1 FROM pg_catalog.pg_get_keywords() AS k
+    WHERE k.word = o.object_name AND k.catcode IN ('R', 'T')
+  ) AS reserved_word
+  , octet_length(o.object_name)::int AS name_bytes
+FROM objects AS o
+WHERE
+  quote_ident(o.object_name) <> o.object_name
+  OR octet_length(o.object_name) >= 63
+ORDER BY o.schema_name, o.table_name, o.column_name <> '', o.column_name;
@@ -0,0 +1,126 @@
+// Package nevervacuumed implements a check for tables that have never been vacuumed.
+package nevervacuumed
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strconv"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+// recentStatsResetSeconds is how recent a statistics reset must be for the
+// report to warn that "never vacuumed" may just mean "not since the reset".
+const recentStatsResetSeconds = 7 * 86400
+
+type NeverVacuumedQueries interface {
+	NeverVacuumedTables(ctx context.Context, minTuples int64) ([]db.NeverVacuumedTablesRow, error)
+}
+
+type checker struct {
+	queries   NeverVacuumedQueries
+	minTuples int64 // default: 10000
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryVacuum,
+		CheckID:     "never-vacuumed",
+		Name:        "Never Vacuumed Tables",
+		Description: "Identifies tables holding data that have never been vacuumed, manually or by autovacuum",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries NeverVacuumedQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries:   queries,
+		minTuples: 10_000,
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			if v, ok := myCfg["min_tuples"]; ok {
+				if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+					c.minTuples = n
+				}
+			}
+		}
+	}
+	return c
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	rows, err := c.queries.NeverVacuumedTables(ctx, c.minTuples)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", check.CategoryVacuum, report.CheckID, err)
+	}
+
+	tableRows := make([]check.TableRow, 0, len(rows))
+	for _, row := range rows {
+		live := check.Int8ToInt64(row.NLiveTup)
+		dead := check.Int8ToInt64(row.NDeadTup)
+
+		deadPct := "-"
+		if live+dead > 0 {
+			deadPct = fmt.Sprintf("%.1f%%", float64(dead)*100/float64(live+dead))
+		}
+
+		tableRows = append(tableRows, check.TableRow{
+			Cells: []string{
+				row.TableName,
+				check.FormatNumber(live),
+				check.FormatNumber(dead),
+				deadPct,
+				check.FormatNumber(check.Int8ToInt64(row.TotalChanges)),
+			},
+			Severity: check.SeverityWarn,
+		})
+	}
+
+	if len(tableRows) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details: fmt.Sprintf("Every table with at least %s tuples has been vacuumed",
+				check.FormatNumber(c.minTuples)),
+		})
+		return report, nil
+	}
+
+	details := fmt.Sprintf("Found %d table(s) with data that have never been vacuumed", len(tableRows))
+	if statsAge := rows[0].StatsAgeSeconds; statsAge.Valid && statsAge.Int64 < recentStatsResetSeconds {
+		details += fmt.Sprintf(" (statistics were reset %s ago; earlier vacuums are not recorded)",
+			check.FormatDurationSec(statsAge.Int64))
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: check.SeverityWarn,
+		Details:  details,
+		Table: &check.Table{
+			Headers:   []string{"Table", "Live Tuples", "Dead Tuples", "Dead %", "Changes"},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignRight, check.AlignRight, check.AlignRight, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	})
+
+	return report, nil
+}
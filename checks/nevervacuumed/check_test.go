@@ -0,0 +1,168 @@
+package nevervacuumed_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/checks/nevervacuumed"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockQueryer struct {
+	rows []db.NeverVacuumedTablesRow
+	err  error
+
+	minTuples int64
+}
+
+// NeverVacuumedTables returns the rows with at least minTuples live and dead
+// tuples, as the query does.
+func (m *mockQueryer) NeverVacuumedTables(_ context.Context, minTuples int64) ([]db.NeverVacuumedTablesRow, error) {
+	m.minTuples = minTuples
+	var rows []db.NeverVacuumedTablesRow
+	for _, row := range m.rows {
+		if row.NLiveTup.Int64+row.NDeadTup.Int64 >= minTuples {
+			rows = append(rows, row)
+		}
+	}
+	return rows, m.err
+}
+
+func count(v int64) pgtype.Int8 {
+	return pgtype.Int8{Int64: v, Valid: true}
+}
+
+// neverVacuumed builds a row with statistics collected for 30 days.
+func neverVacuumed(name string, live, dead int64) db.NeverVacuumedTablesRow {
+	return db.NeverVacuumedTablesRow{
+		TableName:       name,
+		NLiveTup:        count(live),
+		NDeadTup:        count(dead),
+		TotalChanges:    count(live + dead*2),
+		StatsAgeSeconds: count(30 * 86400),
+	}
+}
+
+func TestNeverVacuumed_Severity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		rows     []db.NeverVacuumedTablesRow
+		severity check.Severity
+	}{
+		{
+			name:     "every table vacuumed",
+			rows:     nil,
+			severity: check.SeverityOK,
+		},
+		{
+			name:     "small table never vacuumed",
+			rows:     []db.NeverVacuumedTablesRow{neverVacuumed("public.lookup", 2_000, 100)},
+			severity: check.SeverityOK,
+		},
+		{
+			name:     "table with data never vacuumed",
+			rows:     []db.NeverVacuumedTablesRow{neverVacuumed("public.events", 500_000, 80_000)},
+			severity: check.SeverityWarn,
+		},
+		{
+			name:     "dead tuples alone count",
+			rows:     []db.NeverVacuumedTablesRow{neverVacuumed("public.queue", 0, 50_000)},
+			severity: check.SeverityWarn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			report, err := nevervacuumed.New(&mockQueryer{rows: tt.rows}).Check(context.Background())
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.severity, report.Severity)
+			require.Len(t, report.Results, 1)
+			assert.Equal(t, "never-vacuumed", report.Results[0].ID)
+		})
+	}
+}
+
+func TestNeverVacuumed_TableContents(t *testing.T) {
+	t.Parallel()
+
+	report, err := nevervacuumed.New(&mockQueryer{rows: []db.NeverVacuumedTablesRow{
+		neverVacuumed("public.events", 750_000, 250_000),
+		neverVacuumed("public.queue", 0, 50_000),
+	}}).Check(context.Background())
+	require.NoError(t, err)
+
+	finding := report.Results[0]
+	assert.Equal(t, "Found 2 table(s) with data that have never been vacuumed", finding.Details)
+	require.NotNil(t, finding.Table)
+	assert.Equal(t, []string{"Table", "Live Tuples", "Dead Tuples", "Dead %", "Changes"}, finding.Table.Headers)
+	assert.Equal(t, []string{"public.events", "750.0K", "250.0K", "25.0%", "1.2M"}, finding.Table.Rows[0].Cells)
+	assert.Equal(t, "100.0%", finding.Table.Rows[1].Cells[3])
+}
+
+func TestNeverVacuumed_RecentStatsReset(t *testing.T) {
+	t.Parallel()
+
+	row := neverVacuumed("public.events", 500_000, 80_000)
+	row.StatsAgeSeconds = count(3 * 3600)
+
+	report, err := nevervacuumed.New(&mockQueryer{rows: []db.NeverVacuumedTablesRow{row}}).Check(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, report.Results[0].Details, "statistics were reset 3h ago")
+}
+
+func TestNeverVacuumed_StatsNeverReset(t *testing.T) {
+	t.Parallel()
+
+	row := neverVacuumed("public.events", 500_000, 80_000)
+	row.StatsAgeSeconds = pgtype.Int8{}
+
+	report, err := nevervacuumed.New(&mockQueryer{rows: []db.NeverVacuumedTablesRow{row}}).Check(context.Background())
+	require.NoError(t, err)
+	assert.NotContains(t, report.Results[0].Details, "statistics were reset")
+}
+
+func TestNeverVacuumed_ConfigMinTuples(t *testing.T) {
+	t.Parallel()
+
+	queryer := &mockQueryer{rows: []db.NeverVacuumedTablesRow{
+		neverVacuumed("public.lookup", 2_000, 100),
+	}}
+	_, err := nevervacuumed.New(queryer).Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(10_000), queryer.minTuples, "the default is passed to the query")
+
+	cfg := check.Config{"never-vacuumed": {"min_tuples": "1000"}}
+	report, err := nevervacuumed.New(queryer, cfg).Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), queryer.minTuples)
+	assert.Equal(t, check.SeverityWarn, report.Severity)
+}
+
+func TestNeverVacuumed_QueryError(t *testing.T) {
+	t.Parallel()
+
+	_, err := nevervacuumed.New(&mockQueryer{err: fmt.Errorf("connection refused")}).Check(context.Background())
+	require.ErrorContains(t, err, "never-vacuumed")
+}
+
+func TestNeverVacuumed_Metadata(t *testing.T) {
+	t.Parallel()
+
+	m := nevervacuumed.Metadata()
+	assert.Equal(t, "never-vacuumed", m.CheckID)
+	assert.Equal(t, "Never Vacuumed Tables", m.Name)
+	assert.Equal(t, check.CategoryVacuum, m.Category)
+	assert.NotEmpty(t, m.Description)
+	assert.NotEmpty(t, m.SQL)
+	assert.NotEmpty(t, m.Readme)
+}
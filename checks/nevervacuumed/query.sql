@@ -0,0 +1,17 @@
+-- name: NeverVacuumedTables :many
+-- Tables with no recorded manual or automatic vacuum, largest pending work
+-- first. last_vacuum/last_autovacuum are cleared by a statistics reset, so the
+-- age of the database's statistics is returned alongside.
+SELECT
+  (s.schemaname || '.' || s.relname)::text AS table_name
+  , s.n_live_tup
+  , s.n_dead_tup
+  , s.n_tup_ins + s.n_tup_upd + s.n_tup_del AS total_changes
+  , EXTRACT(EPOCH FROM now() - d.stats_reset)::bigint AS stats_age_seconds
+FROM pg_stat_user_tables AS s
+INNER JOIN pg_stat_database AS d ON d.datname = current_database()
+WHERE
+  s.last_vacuum IS NULL
+  AND s.last_autovacuum IS NULL
+  AND s.n_live_tup + s.n_dead_tup >= sqlc.arg(min_tuples)::bigint
+ORDER BY s.n_dead_tup DESC, s.n_live_tup DESC;
@@ -0,0 +1,181 @@
+// Package parallelquery implements a check for parallel query settings that are inconsistent or disable parallelism for analytical workloads.
+package parallelquery
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strconv"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type ParallelQueryQueries interface {
+	ParallelQuery(context.Context) (db.ParallelQueryRow, error)
+}
+
+type checker struct {
+	queries             ParallelQueryQueries
+	minWorkersPerGather int32   // default: 0, not required; the olap profile sets 2
+	maxSetupCost        float64 // default: 0, no limit; the olap profile sets 1000
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryConfigs,
+		CheckID:     "parallel-query",
+		Name:        "Parallel Query",
+		Description: "Checks that max_parallel_workers fits within max_worker_processes and, for analytical workloads, that parallel query is not disabled or priced out",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries ParallelQueryQueries, cfg ...check.Config) check.Checker {
+	c := &checker{queries: queries}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			if v, ok := myCfg["min_workers_per_gather"]; ok {
+				if n, err := strconv.ParseInt(v, 10, 32); err == nil && n >= 0 {
+					c.minWorkersPerGather = int32(n)
+				}
+			}
+			if v, ok := myCfg["max_setup_cost"]; ok {
+				if n, err := strconv.ParseFloat(v, 64); err == nil && n >= 0 {
+					c.maxSetupCost = n
+				}
+			}
+		}
+	}
+	return c
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	settings, err := c.queries.ParallelQuery(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	processes := row("max_worker_processes", strconv.Itoa(int(settings.MaxWorkerProcesses)))
+
+	// Parallel workers are background workers, taken from max_worker_processes
+	// with logical replication and extension workers.
+	workers := row("max_parallel_workers", strconv.Itoa(int(settings.MaxParallelWorkers)))
+	workers.expected = fmt.Sprintf("≤ %d", settings.MaxWorkerProcesses)
+	switch {
+	case settings.MaxParallelWorkers > settings.MaxWorkerProcesses:
+		workers.warn(fmt.Sprintf("Above max_worker_processes: at most %d can start", settings.MaxWorkerProcesses),
+			fmt.Sprintf("max_parallel_workers %d > max_worker_processes %d → WARN", settings.MaxParallelWorkers, settings.MaxWorkerProcesses))
+	case c.minWorkersPerGather > 0 && settings.MaxParallelWorkers < c.minWorkersPerGather:
+		workers.warn(fmt.Sprintf("Below %d: no query can get the workers per Gather expected", c.minWorkersPerGather),
+			fmt.Sprintf("max_parallel_workers %d < min_workers_per_gather %d → WARN", settings.MaxParallelWorkers, c.minWorkersPerGather))
+	}
+
+	perGather := row("max_parallel_workers_per_gather", strconv.Itoa(int(settings.MaxParallelWorkersPerGather)))
+	if c.minWorkersPerGather > 0 {
+		perGather.expected = fmt.Sprintf("≥ %d", c.minWorkersPerGather)
+	}
+	switch {
+	case settings.MaxParallelWorkersPerGather == 0 && c.minWorkersPerGather > 0:
+		perGather.warn("0: parallel query is disabled",
+			fmt.Sprintf("max_parallel_workers_per_gather 0 < min_workers_per_gather %d → WARN", c.minWorkersPerGather))
+	case settings.MaxParallelWorkersPerGather == 0:
+		perGather.status = "0: parallel query is disabled"
+	case settings.MaxParallelWorkersPerGather < c.minWorkersPerGather:
+		perGather.warn(fmt.Sprintf("Below %d for an analytical workload", c.minWorkersPerGather),
+			fmt.Sprintf("max_parallel_workers_per_gather %d < min_workers_per_gather %d → WARN", settings.MaxParallelWorkersPerGather, c.minWorkersPerGather))
+	case settings.MaxParallelWorkersPerGather > settings.MaxParallelWorkers:
+		perGather.warn(fmt.Sprintf("Above max_parallel_workers: capped at %d", settings.MaxParallelWorkers),
+			fmt.Sprintf("max_parallel_workers_per_gather %d > max_parallel_workers %d → WARN", settings.MaxParallelWorkersPerGather, settings.MaxParallelWorkers))
+	}
+
+	setupCost := row("parallel_setup_cost", strconv.FormatFloat(settings.ParallelSetupCost, 'f', -1, 64))
+	if c.maxSetupCost > 0 {
+		setupCost.expected = "≤ " + strconv.FormatFloat(c.maxSetupCost, 'f', -1, 64)
+		if settings.ParallelSetupCost > c.maxSetupCost {
+			setupCost.warn("High: the planner rarely picks parallel plans",
+				fmt.Sprintf("parallel_setup_cost %g > max_setup_cost %g → WARN", settings.ParallelSetupCost, c.maxSetupCost))
+		}
+	}
+
+	rows := []*settingRow{processes, workers, perGather, setupCost}
+	severity := check.SeverityOK
+	var issues int
+	var reason string
+	tableRows := make([]check.TableRow, 0, len(rows))
+	for _, r := range rows {
+		severity = max(severity, r.severity)
+		if r.severity != check.SeverityOK {
+			issues++
+			if reason == "" {
+				reason = r.rule
+			}
+		}
+		tableRows = append(tableRows, check.TableRow{
+			Cells:    []string{r.parameter, r.current, r.expected, r.status},
+			Severity: r.severity,
+		})
+	}
+	if issues > 1 {
+		reason += fmt.Sprintf(" (+%d more)", issues-1)
+	}
+
+	var details string
+	switch {
+	case issues > 0:
+		details = fmt.Sprintf("%d parallel query setting(s) need attention: worker limits above the pool they draw from are never reached, "+
+			"and with parallelism disabled or too costly, large scans and aggregates run on a single core", issues)
+	case settings.MaxParallelWorkersPerGather == 0:
+		details = "Parallel query is disabled (max_parallel_workers_per_gather = 0); use --profile olap to flag this for analytical workloads"
+	default:
+		details = fmt.Sprintf("Parallel query can use up to %d worker(s) per Gather and %d in total",
+			min(settings.MaxParallelWorkersPerGather, settings.MaxParallelWorkers), min(settings.MaxParallelWorkers, settings.MaxWorkerProcesses))
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: severity,
+		Details:  details,
+		Reason:   reason,
+		Table: &check.Table{
+			Headers: []string{"Parameter", "Current", "Expected", "Status"},
+			Rows:    tableRows,
+			// Each limit is drawn from the one above it.
+			KeepOrder: true,
+		},
+	})
+
+	return report, nil
+}
+
+// settingRow is one row of the report table.
+type settingRow struct {
+	parameter string
+	current   string
+	expected  string
+	status    string
+	severity  check.Severity
+	rule      string // the rule that warned, for Finding.Reason
+}
+
+func row(parameter, current string) *settingRow {
+	return &settingRow{parameter: parameter, current: current, expected: "-", status: "OK", severity: check.SeverityOK}
+}
+
+func (r *settingRow) warn(status, rule string) {
+	r.status, r.severity, r.rule = status, check.SeverityWarn, rule
+}
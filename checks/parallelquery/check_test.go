@@ -0,0 +1,119 @@
+package parallelquery_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/parallelquery"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeQueries struct {
+	settings checktest.Result[db.ParallelQueryRow]
+}
+
+func (f fakeQueries) ParallelQuery(context.Context) (db.ParallelQueryRow, error) {
+	return f.settings.Get()
+}
+
+func run(t *testing.T, row db.ParallelQueryRow, cfg ...check.Config) check.Finding {
+	t.Helper()
+	return checktest.OnlyFinding(t, checktest.Run(t, parallelquery.New(fakeQueries{settings: checktest.Returns(row)}, cfg...)))
+}
+
+// defaults are PostgreSQL's.
+func defaults() db.ParallelQueryRow {
+	return db.ParallelQueryRow{MaxWorkerProcesses: 8, MaxParallelWorkers: 8, MaxParallelWorkersPerGather: 2, ParallelSetupCost: 1000}
+}
+
+func olap() check.Config {
+	return check.Config{"parallel-query": {"min_workers_per_gather": "2", "max_setup_cost": "1000"}}
+}
+
+func TestParallelQuery_Metadata(t *testing.T) {
+	t.Parallel()
+	checktest.RequireMetadata(t, parallelquery.Metadata(), "parallel-query", check.CategoryConfigs)
+}
+
+func TestParallelQuery_QueryError(t *testing.T) {
+	t.Parallel()
+	checktest.RequireError(t, parallelquery.New(fakeQueries{settings: checktest.Fails[db.ParallelQueryRow](errors.New("boom"))}))
+}
+
+func TestParallelQuery_Defaults(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, defaults())
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "Parallel query can use up to 2 worker(s) per Gather and 8 in total", finding.Details)
+	assert.Empty(t, finding.Reason)
+	assert.Equal(t, [][]string{
+		{"max_worker_processes", "8", "-", "OK"},
+		{"max_parallel_workers", "8", "≤ 8", "OK"},
+		{"max_parallel_workers_per_gather", "2", "-", "OK"},
+		{"parallel_setup_cost", "1000", "-", "OK"},
+	}, checktest.Rows(finding))
+}
+
+func TestParallelQuery_DisabledWithoutProfile(t *testing.T) {
+	t.Parallel()
+
+	row := defaults()
+	row.MaxParallelWorkersPerGather = 0
+	finding := run(t, row)
+	assert.Equal(t, check.SeverityOK, finding.Severity, "disabling parallelism is a valid OLTP choice")
+	assert.Contains(t, finding.Details, "Parallel query is disabled")
+	assert.Equal(t, []string{"max_parallel_workers_per_gather", "0", "-", "0: parallel query is disabled"}, checktest.Rows(finding)[2])
+}
+
+func TestParallelQuery_DisabledForOLAP(t *testing.T) {
+	t.Parallel()
+
+	row := defaults()
+	row.MaxParallelWorkersPerGather = 0
+	row.ParallelSetupCost = 100000
+	finding := run(t, row, olap())
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Contains(t, finding.Details, "2 parallel query setting(s) need attention")
+	assert.Equal(t, "max_parallel_workers_per_gather 0 < min_workers_per_gather 2 → WARN (+1 more)", finding.Reason)
+	assert.Equal(t, [][]string{
+		{"max_worker_processes", "8", "-", "OK"},
+		{"max_parallel_workers", "8", "≤ 8", "OK"},
+		{"max_parallel_workers_per_gather", "0", "≥ 2", "0: parallel query is disabled"},
+		{"parallel_setup_cost", "100000", "≤ 1000", "High: the planner rarely picks parallel plans"},
+	}, checktest.Rows(finding))
+}
+
+func TestParallelQuery_InconsistentLimits(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, db.ParallelQueryRow{MaxWorkerProcesses: 8, MaxParallelWorkers: 16, MaxParallelWorkersPerGather: 32, ParallelSetupCost: 1000})
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Equal(t, "max_parallel_workers 16 > max_worker_processes 8 → WARN (+1 more)", finding.Reason)
+	rows := checktest.Rows(finding)
+	assert.Equal(t, "Above max_worker_processes: at most 8 can start", rows[1][3])
+	assert.Equal(t, "Above max_parallel_workers: capped at 16", rows[2][3])
+}
+
+func TestParallelQuery_FewWorkersForOLAP(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, db.ParallelQueryRow{MaxWorkerProcesses: 8, MaxParallelWorkers: 1, MaxParallelWorkersPerGather: 1, ParallelSetupCost: 1000}, olap())
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	rows := checktest.Rows(finding)
+	assert.Equal(t, "Below 2: no query can get the workers per Gather expected", rows[1][3])
+	assert.Equal(t, "Below 2 for an analytical workload", rows[2][3])
+}
+
+func TestParallelQuery_InvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	row := defaults()
+	row.MaxParallelWorkersPerGather = 0
+	finding := run(t, row, check.Config{"parallel-query": {"min_workers_per_gather": "-1", "max_setup_cost": "cheap"}})
+	assert.Equal(t, check.SeverityOK, finding.Severity, "Invalid values keep the default")
+}
@@ -0,0 +1,9 @@
+-- name: ParallelQuery :one
+-- The settings that size parallel query, as the session sees them:
+-- max_parallel_workers_per_gather and parallel_setup_cost can be overridden
+-- per database and role, the worker pools only per server.
+SELECT
+  current_setting('max_worker_processes')::integer AS max_worker_processes
+  , current_setting('max_parallel_workers')::integer AS max_parallel_workers
+  , current_setting('max_parallel_workers_per_gather')::integer AS max_parallel_workers_per_gather
+  , current_setting('parallel_setup_cost')::float8 AS parallel_setup_cost;
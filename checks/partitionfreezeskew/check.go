@@ -0,0 +1,150 @@
+// Package partitionfreezeskew implements a check for partitions that lag far behind their siblings in freezing.
+package partitionfreezeskew
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strconv"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type PartitionFreezeSkewQueries interface {
+	PartitionFreezeSkew(context.Context) ([]db.PartitionFreezeSkewRow, error)
+}
+
+type checker struct {
+	queries PartitionFreezeSkewQueries
+	minSkew int64 // default: 100M transactions
+	failAge int64 // default: 1B transactions
+}
+
+const (
+	// Transaction ID wraparound happens at ~2 billion.
+	wraparoundLimit = int64(2_000_000_000)
+
+	// autovacuum_freeze_max_age default, used when the setting can't be read.
+	defaultFreezeMaxAge = int64(200_000_000)
+)
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryVacuum,
+		CheckID:     "partition-freeze-skew",
+		Name:        "Partition Freeze Skew",
+		Description: "Flags partitioned tables whose oldest partition lags far behind the others in freezing, a source of anti-wraparound vacuum storms",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries PartitionFreezeSkewQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries: queries,
+		minSkew: 100_000_000,
+		failAge: 1_000_000_000,
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			if v, ok := myCfg["min_skew"]; ok {
+				if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+					c.minSkew = n
+				}
+			}
+			if v, ok := myCfg["fail_age"]; ok {
+				if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 && n < wraparoundLimit {
+					c.failAge = n
+				}
+			}
+		}
+	}
+	return c
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	rows, err := c.queries.PartitionFreezeSkew(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	severity := check.SeverityOK
+	var tableRows []check.TableRow
+	for _, row := range rows {
+		freezeMaxAge := defaultFreezeMaxAge
+		if row.FreezeMaxAge.Valid {
+			freezeMaxAge = row.FreezeMaxAge.Int64
+		}
+		// Only partitions already due for an anti-wraparound vacuum matter,
+		// and only when their siblings show the table can be frozen.
+		if row.OldestAge < freezeMaxAge || row.OldestAge-row.YoungestAge < c.minSkew {
+			continue
+		}
+
+		rowSeverity := check.SeverityWarn
+		if row.OldestAge >= c.failAge {
+			rowSeverity = check.SeverityFail
+		}
+		if rowSeverity > severity {
+			severity = rowSeverity
+		}
+
+		tableRows = append(tableRows, check.TableRow{
+			Cells: []string{
+				row.ParentTable,
+				row.OldestPartition,
+				check.FormatNumber(row.OldestAge),
+				check.FormatNumber(row.YoungestAge),
+				fmt.Sprintf("%.1f%%", float64(row.OldestAge)/float64(wraparoundLimit)*100),
+				check.FormatNumber(row.PartitionCount),
+			},
+			Severity: rowSeverity,
+		})
+	}
+
+	if len(tableRows) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Tags:     []string{check.TagWraparound},
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  "No partition lags far behind its siblings in freezing",
+		})
+		return report, nil
+	}
+
+	finding := check.Finding{
+		ID:       report.CheckID,
+		Tags:     []string{check.TagWraparound},
+		Name:     report.Name,
+		Severity: severity,
+		Details: fmt.Sprintf("Found %d partitioned table(s) whose oldest partition is past autovacuum_freeze_max_age "+
+			"and %s or more transactions older than the most recently frozen one", len(tableRows), check.FormatNumber(c.minSkew)),
+		Table: &check.Table{
+			Headers:   []string{"Parent Table", "Oldest Partition", "Oldest Age", "Newest Age", "% to Limit", "Partitions"},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignLeft, check.AlignRight, check.AlignRight, check.AlignRight, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	}
+	if severity == check.SeverityFail {
+		// Wraparound stops the database from accepting writes.
+		finding.Priority = check.PriorityUrgent
+	}
+	report.AddFinding(finding)
+
+	return report, nil
+}
@@ -0,0 +1,153 @@
+package partitionfreezeskew_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/checks/partitionfreezeskew"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockQueryer struct {
+	rows    []db.PartitionFreezeSkewRow
+	rowsErr error
+}
+
+func (m *mockQueryer) PartitionFreezeSkew(context.Context) ([]db.PartitionFreezeSkewRow, error) {
+	return m.rows, m.rowsErr
+}
+
+func skew(parent string, oldestAge, youngestAge int64) db.PartitionFreezeSkewRow {
+	return db.PartitionFreezeSkewRow{
+		ParentTable:     parent,
+		OldestPartition: parent + "_2023_01",
+		OldestAge:       oldestAge,
+		YoungestAge:     youngestAge,
+		PartitionCount:  24,
+		FreezeMaxAge:    pgtype.Int8{Int64: 200_000_000, Valid: true},
+	}
+}
+
+func TestPartitionFreezeSkew_NoPartitionedTables(t *testing.T) {
+	t.Parallel()
+
+	report, err := partitionfreezeskew.New(&mockQueryer{}).Check(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, report.Results, 1)
+	assert.Equal(t, check.SeverityOK, report.Results[0].Severity)
+}
+
+func TestPartitionFreezeSkew_Thresholds(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		row      db.PartitionFreezeSkewRow
+		expected check.Severity
+	}{
+		{"young partitions", skew("public.events", 150_000_000, 1_000_000), check.SeverityOK},
+		{"old but evenly frozen", skew("public.events", 400_000_000, 350_000_000), check.SeverityOK},
+		{"lagging partition", skew("public.events", 400_000_000, 5_000_000), check.SeverityWarn},
+		{"near wraparound", skew("public.events", 1_200_000_000, 5_000_000), check.SeverityFail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			report, err := partitionfreezeskew.New(&mockQueryer{
+				rows: []db.PartitionFreezeSkewRow{tt.row},
+			}).Check(context.Background())
+			require.NoError(t, err)
+
+			require.Len(t, report.Results, 1)
+			assert.Equal(t, tt.expected, report.Results[0].Severity)
+		})
+	}
+}
+
+func TestPartitionFreezeSkew_FreezeMaxAge(t *testing.T) {
+	t.Parallel()
+
+	row := skew("public.events", 300_000_000, 5_000_000)
+	row.FreezeMaxAge = pgtype.Int8{Int64: 500_000_000, Valid: true}
+	report, err := partitionfreezeskew.New(&mockQueryer{rows: []db.PartitionFreezeSkewRow{row}}).Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, check.SeverityOK, report.Results[0].Severity, "partitions below autovacuum_freeze_max_age are not due for freezing")
+
+	row.FreezeMaxAge = pgtype.Int8{}
+	report, err = partitionfreezeskew.New(&mockQueryer{rows: []db.PartitionFreezeSkewRow{row}}).Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, check.SeverityWarn, report.Results[0].Severity, "an unreadable setting falls back to 200M")
+}
+
+func TestPartitionFreezeSkew_Table(t *testing.T) {
+	t.Parallel()
+
+	report, err := partitionfreezeskew.New(&mockQueryer{
+		rows: []db.PartitionFreezeSkewRow{
+			skew("public.events", 1_200_000_000, 5_000_000),
+			skew("public.metrics", 400_000_000, 5_000_000),
+			skew("public.logs", 100_000_000, 5_000_000),
+		},
+	}).Check(context.Background())
+	require.NoError(t, err)
+
+	finding := report.Results[0]
+	assert.Equal(t, check.SeverityFail, finding.Severity)
+	assert.Equal(t, check.PriorityUrgent, finding.Priority)
+	assert.Contains(t, finding.Details, "Found 2 partitioned table(s)")
+	require.NotNil(t, finding.Table)
+	require.Len(t, finding.Table.Rows, 2)
+	assert.Equal(t, []string{"public.events", "public.events_2023_01", "1.2B", "5.0M", "60.0%", "24"}, finding.Table.Rows[0].Cells)
+	assert.Equal(t, check.SeverityWarn, finding.Table.Rows[1].Severity)
+}
+
+func TestPartitionFreezeSkew_Config(t *testing.T) {
+	t.Parallel()
+
+	rows := []db.PartitionFreezeSkewRow{skew("public.events", 900_000_000, 750_000_000)}
+
+	report, err := partitionfreezeskew.New(&mockQueryer{rows: rows}).Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, check.SeverityWarn, report.Results[0].Severity)
+
+	cfg := check.Config{"partition-freeze-skew": {"min_skew": "200000000"}}
+	report, err = partitionfreezeskew.New(&mockQueryer{rows: rows}, cfg).Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, check.SeverityOK, report.Results[0].Severity)
+
+	cfg = check.Config{"partition-freeze-skew": {"fail_age": "800000000"}}
+	report, err = partitionfreezeskew.New(&mockQueryer{rows: rows}, cfg).Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, check.SeverityFail, report.Results[0].Severity)
+
+	cfg = check.Config{"partition-freeze-skew": {"fail_age": "3000000000"}}
+	report, err = partitionfreezeskew.New(&mockQueryer{rows: rows}, cfg).Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, check.SeverityWarn, report.Results[0].Severity, "values past the wraparound limit keep the default")
+}
+
+func TestPartitionFreezeSkew_QueryError(t *testing.T) {
+	t.Parallel()
+
+	_, err := partitionfreezeskew.New(&mockQueryer{rowsErr: fmt.Errorf("connection refused")}).Check(context.Background())
+	require.ErrorContains(t, err, "partition-freeze-skew")
+}
+
+func TestPartitionFreezeSkew_Metadata(t *testing.T) {
+	t.Parallel()
+
+	m := partitionfreezeskew.Metadata()
+	assert.Equal(t, "partition-freeze-skew", m.CheckID)
+	assert.Equal(t, check.CategoryVacuum, m.Category)
+	assert.NotEmpty(t, m.Name)
+	assert.NotEmpty(t, m.Readme)
+	assert.NotEmpty(t, m.SQL)
+}
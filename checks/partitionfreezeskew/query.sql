@@ -0,0 +1,47 @@
+-- name: PartitionFreezeSkew :many
+-- For each partitioned table with two or more leaf partitions, the partition
+-- with the oldest relfrozenxid, its age, and the age of the most recently
+-- frozen partition. Partitions are grouped by their top-level parent.
+WITH leaves AS (
+  SELECT
+    pg_partition_root(c.oid) AS root_oid
+    , c.oid
+    , age(c.relfrozenxid)::bigint AS freeze_age
+  FROM pg_class AS c
+  WHERE
+    c.relispartition
+    AND c.relkind = 'r'
+    AND c.relfrozenxid != '0'
+)
+
+, ranked AS (
+  SELECT
+    root_oid
+    , oid
+    , freeze_age
+    , row_number() OVER (PARTITION BY root_oid ORDER BY freeze_age DESC) AS age_rank
+    , min(freeze_age) OVER (PARTITION BY root_oid) AS youngest_age
+    , count(*) OVER (PARTITION BY root_oid) AS partition_count
+  FROM leaves
+)
+
+SELECT
+  (rn.nspname || '.' || r.relname)::text AS parent_table
+  , (pn.nspname || '.' || p.relname)::text AS oldest_partition
+  , o.freeze_age AS oldest_age
+  , o.youngest_age
+  , o.partition_count
+  , (
+    SELECT s.setting::bigint FROM pg_settings AS s
+    WHERE s.name = 'autovacuum_freeze_max_age'
+  ) AS freeze_max_age
+FROM ranked AS o
+INNER JOIN pg_class AS r ON o.root_oid = r.oid
+INNER JOIN pg_namespace AS rn ON r.relnamespace = rn.oid
+INNER JOIN pg_class AS p ON o.oid = p.oid
+INNER JOIN pg_namespace AS pn ON p.relnamespace = pn.oid
+WHERE
+  o.age_rank = 1
+  AND o.partition_count >= 2
+  AND rn.nspname NOT IN ('pg_catalog', 'information_schema')
+ORDER BY o.freeze_age DESC;
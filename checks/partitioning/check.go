@@ -208,8 +208,10 @@ func checkLargeUnpartitioned(rows []db.LargeTablesRow, report *check.Report) {
 		Severity: severity,
 		Details:  fmt.Sprintf("Found %d large table(s) that should be partitioned", len(rows)),
 		Table: &check.Table{
-			Headers: []string{"Table", "Size", "Est. Rows", "Reason", "Status"},
-			Rows:    tableRows,
+			Headers:   []string{"Table", "Size", "Est. Rows", "Reason", "Status"},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignRight, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier},
+			Rows:      tableRows,
 		},
 	})
 }
@@ -244,8 +246,10 @@ func checkTransientUnpartitioned(rows []db.LargeTablesRow, report *check.Report)
 		Severity: check.SeverityFail,
 		Details:  fmt.Sprintf("Found %d large transient table(s) without partitioning", len(rows)),
 		Table: &check.Table{
-			Headers: []string{"Table", "Size", "Est. Rows"},
-			Rows:    tableRows,
+			Headers:   []string{"Table", "Size", "Est. Rows"},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignRight, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier},
+			Rows:      tableRows,
 		},
 	})
 }
@@ -279,8 +283,10 @@ func checkInefficientPartitions(rows []db.LargeTablesRow, report *check.Report)
 		Severity: check.SeverityWarn,
 		Details:  fmt.Sprintf("Found %d partition(s) with >= 25M rows - partition strategy may be inefficient", len(rows)),
 		Table: &check.Table{
-			Headers: []string{"Partition", "Parent Table", "Size", "Est. Rows"},
-			Rows:    tableRows,
+			Headers:   []string{"Partition", "Parent Table", "Size", "Est. Rows"},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignLeft, check.AlignRight, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.SensitiveIdentifier},
+			Rows:      tableRows,
 		},
 	})
 }
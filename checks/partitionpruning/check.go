@@ -0,0 +1,135 @@
+// Package partitionpruning implements a check for partition pruning and constraint exclusion being turned off.
+package partitionpruning
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type PartitionPruningQueries interface {
+	PartitionPruning(context.Context) (db.PartitionPruningRow, error)
+}
+
+type checker struct {
+	queries PartitionPruningQueries
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryConfigs,
+		CheckID:     "partition-pruning",
+		Name:        "Partition Pruning",
+		Description: "Checks that enable_partition_pruning is on and constraint_exclusion is partition, so queries skip the partitions they cannot match",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries PartitionPruningQueries, _ ...check.Config) check.Checker {
+	return &checker{queries: queries}
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	settings, err := c.queries.PartitionPruning(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	// Declarative partitions are pruned by enable_partition_pruning, at
+	// planning and at execution.
+	pruning := row("enable_partition_pruning", settings.EnablePartitionPruning, "on")
+	if settings.EnablePartitionPruning != "on" {
+		if settings.PartitionedTables > 0 {
+			pruning.set(check.SeverityFail, fmt.Sprintf("Off with %d partitioned table(s): every query scans every partition", settings.PartitionedTables))
+		} else {
+			pruning.set(check.SeverityWarn, "Off: partitioned tables would not be pruned")
+		}
+	}
+
+	// Inheritance children, and UNION ALL branches, are skipped by
+	// constraint_exclusion through their CHECK constraints.
+	exclusion := row("constraint_exclusion", settings.ConstraintExclusion, "partition")
+	switch settings.ConstraintExclusion {
+	case "partition": // the default
+	case "off":
+		if settings.InheritanceParents > 0 {
+			exclusion.set(check.SeverityFail, fmt.Sprintf("Off with %d table(s) partitioned by inheritance: every query scans every child", settings.InheritanceParents))
+		} else {
+			exclusion.set(check.SeverityWarn, "Off: tables partitioned by inheritance would not be pruned")
+		}
+	default:
+		exclusion.set(check.SeverityWarn, "Checks the constraints of every table in every query, costing planning time")
+	}
+
+	rows := []*settingRow{pruning, exclusion}
+	severity := check.SeverityOK
+	var issues int
+	tableRows := make([]check.TableRow, 0, len(rows))
+	for _, r := range rows {
+		severity = max(severity, r.severity)
+		if r.severity != check.SeverityOK {
+			issues++
+		}
+		tableRows = append(tableRows, check.TableRow{
+			Cells:    []string{r.parameter, r.current, r.expected, r.status},
+			Severity: r.severity,
+		})
+	}
+
+	var details string
+	switch severity {
+	case check.SeverityOK:
+		details = "Partition pruning and constraint exclusion are on"
+	case check.SeverityFail:
+		details = fmt.Sprintf("%d pruning setting(s) need attention; pruning is off while partitioned tables exist, so queries read every partition "+
+			"instead of the ones that can match. Check ALTER DATABASE and ALTER ROLE overrides as well as the server configuration", issues)
+	default:
+		details = fmt.Sprintf("%d pruning setting(s) differ from the defaults", issues)
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: severity,
+		Details:  details,
+		Table: &check.Table{
+			Headers: []string{"Parameter", "Current", "Expected", "Status"},
+			Rows:    tableRows,
+		},
+	})
+
+	return report, nil
+}
+
+// settingRow is one row of the report table.
+type settingRow struct {
+	parameter string
+	current   string
+	expected  string
+	status    string
+	severity  check.Severity
+}
+
+func row(parameter, current, expected string) *settingRow {
+	return &settingRow{parameter: parameter, current: current, expected: expected, status: "OK", severity: check.SeverityOK}
+}
+
+func (r *settingRow) set(severity check.Severity, status string) {
+	r.severity, r.status = severity, status
+}
@@ -0,0 +1,109 @@
+package partitionpruning_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/partitionpruning"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeQueries struct {
+	settings checktest.Result[db.PartitionPruningRow]
+}
+
+func (f fakeQueries) PartitionPruning(context.Context) (db.PartitionPruningRow, error) {
+	return f.settings.Get()
+}
+
+func run(t *testing.T, row db.PartitionPruningRow) check.Finding {
+	t.Helper()
+	return checktest.OnlyFinding(t, checktest.Run(t, partitionpruning.New(fakeQueries{settings: checktest.Returns(row)})))
+}
+
+func defaults() db.PartitionPruningRow {
+	return db.PartitionPruningRow{EnablePartitionPruning: "on", ConstraintExclusion: "partition", PartitionedTables: 3}
+}
+
+func TestPartitionPruning_Metadata(t *testing.T) {
+	t.Parallel()
+	checktest.RequireMetadata(t, partitionpruning.Metadata(), "partition-pruning", check.CategoryConfigs)
+}
+
+func TestPartitionPruning_QueryError(t *testing.T) {
+	t.Parallel()
+	checktest.RequireError(t, partitionpruning.New(fakeQueries{settings: checktest.Fails[db.PartitionPruningRow](errors.New("boom"))}))
+}
+
+func TestPartitionPruning_Defaults(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, defaults())
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "Partition pruning and constraint exclusion are on", finding.Details)
+	assert.Equal(t, [][]string{
+		{"enable_partition_pruning", "on", "on", "OK"},
+		{"constraint_exclusion", "partition", "partition", "OK"},
+	}, checktest.Rows(finding))
+}
+
+func TestPartitionPruning_Settings(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		modify   func(*db.PartitionPruningRow)
+		row      int
+		status   string
+		severity check.Severity
+	}{
+		{
+			name:     "pruning off with partitioned tables",
+			modify:   func(r *db.PartitionPruningRow) { r.EnablePartitionPruning = "off" },
+			status:   "Off with 3 partitioned table(s): every query scans every partition",
+			severity: check.SeverityFail,
+		},
+		{
+			name:     "pruning off without partitioned tables",
+			modify:   func(r *db.PartitionPruningRow) { r.EnablePartitionPruning, r.PartitionedTables = "off", 0 },
+			status:   "Off: partitioned tables would not be pruned",
+			severity: check.SeverityWarn,
+		},
+		{
+			name:     "exclusion off with inheritance",
+			modify:   func(r *db.PartitionPruningRow) { r.ConstraintExclusion, r.InheritanceParents = "off", 2 },
+			row:      1,
+			status:   "Off with 2 table(s) partitioned by inheritance: every query scans every child",
+			severity: check.SeverityFail,
+		},
+		{
+			name:     "exclusion off without inheritance",
+			modify:   func(r *db.PartitionPruningRow) { r.ConstraintExclusion = "off" },
+			row:      1,
+			status:   "Off: tables partitioned by inheritance would not be pruned",
+			severity: check.SeverityWarn,
+		},
+		{
+			name:     "exclusion on",
+			modify:   func(r *db.PartitionPruningRow) { r.ConstraintExclusion = "on" },
+			row:      1,
+			status:   "Checks the constraints of every table in every query, costing planning time",
+			severity: check.SeverityWarn,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			row := defaults()
+			tt.modify(&row)
+			finding := run(t, row)
+			assert.Equal(t, tt.severity, finding.Severity)
+			assert.Equal(t, tt.status, checktest.Rows(finding)[tt.row][3])
+		})
+	}
+}
@@ -0,0 +1,20 @@
+-- name: PartitionPruning :one
+-- The pruning settings as the current session sees them, so ALTER DATABASE
+-- and ALTER ROLE overrides are included, with the number of declaratively
+-- partitioned tables and of tables partitioned by inheritance (parents of
+-- plain tables) in the current database.
+SELECT
+  current_setting('enable_partition_pruning')::text AS enable_partition_pruning
+  , current_setting('constraint_exclusion')::text AS constraint_exclusion
+  , (
+    SELECT count(*)::int
+    FROM pg_catalog.pg_class AS c
+    INNER JOIN pg_catalog.pg_namespace AS n ON c.relnamespace = n.oid
+    WHERE c.relkind = 'p' AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+  ) AS partitioned_tables
+  , (
+    SELECT count(DISTINCT i.inhparent)::int
+    FROM pg_catalog.pg_inherits AS i
+    INNER JOIN pg_catalog.pg_class AS p ON i.inhparent = p.oid
+    WHERE p.relkind = 'r'
+  ) AS inheritance_parents;
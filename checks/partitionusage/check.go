@@ -217,8 +217,10 @@ func checkPartitionKeyUsage(
 		Severity: overallSeverity,
 		Details:  fmt.Sprintf("Found %d partitioned table(s) with queries not using partition key", len(tableRows)),
 		Table: &check.Table{
-			Headers: []string{"Table", "Partition Key", "Partitions", "Problem Queries", "Total Calls", "Total Time"},
-			Rows:    tableRows,
+			Headers:   []string{"Table", "Partition Key", "Partitions", "Problem Queries", "Total Calls", "Total Time"},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignLeft, check.AlignRight, check.AlignRight, check.AlignRight, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.SensitiveIdentifier},
+			Rows:      tableRows,
 		},
 	})
 }
@@ -375,8 +377,10 @@ func checkJoinsMissingPartitionKey(
 		Severity: overallSeverity,
 		Details:  fmt.Sprintf("Found %d partitioned table(s) with JOINs not using partition key", len(tableRows)),
 		Table: &check.Table{
-			Headers: []string{"Table", "Partition Key", "Problem JOINs", "Total Calls", "Total Time"},
-			Rows:    tableRows,
+			Headers:   []string{"Table", "Partition Key", "Problem JOINs", "Total Calls", "Total Time"},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignLeft, check.AlignRight, check.AlignRight, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.SensitiveIdentifier},
+			Rows:      tableRows,
 		},
 	})
 }
@@ -445,8 +449,10 @@ func checkSequentialScans(tables []db.PartitionedTablesWithKeysRow, report *chec
 		Severity: overallSeverity,
 		Details:  fmt.Sprintf("Found %d partitioned table(s) with high sequential scan ratio", len(tableRows)),
 		Table: &check.Table{
-			Headers: []string{"Table", "Seq Scans", "Idx Scans", "Ratio"},
-			Rows:    tableRows,
+			Headers:   []string{"Table", "Seq Scans", "Idx Scans", "Ratio"},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignRight, check.AlignRight, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier},
+			Rows:      tableRows,
 		},
 	})
 }
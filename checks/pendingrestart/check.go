@@ -0,0 +1,124 @@
+// Package pendingrestart implements a check for configuration changes waiting for a server restart.
+package pendingrestart
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type PendingRestartQueries interface {
+	PendingRestartSettings(context.Context) ([]db.PendingRestartSettingsRow, error)
+	FileSettingsReadable(context.Context) (bool, error)
+	ConfiguredSettings(context.Context, []string) ([]db.ConfiguredSettingsRow, error)
+}
+
+type checker struct {
+	queries PendingRestartQueries
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryConfigs,
+		CheckID:     "pending-restart",
+		Name:        "Settings Pending Restart",
+		Description: "Finds parameters changed in the configuration that only take effect after a server restart",
+		Readme:      readme,
+		SQL:         querySQL,
+		ClusterWide: true,
+	}
+}
+
+func New(queries PendingRestartQueries, _ ...check.Config) check.Checker {
+	return &checker{
+		queries: queries,
+	}
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	pending, err := c.queries.PendingRestartSettings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	if len(pending) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  "The server is running with its configured settings",
+		})
+		return report, nil
+	}
+
+	// The configured values come from pg_file_settings, which only
+	// superusers can read by default; without it the table shows what is
+	// running.
+	readable, err := c.queries.FileSettingsReadable(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+	configured := map[string]db.ConfiguredSettingsRow{}
+	if readable {
+		names := make([]string, 0, len(pending))
+		for _, row := range pending {
+			names = append(names, row.Name)
+		}
+		rows, err := c.queries.ConfiguredSettings(ctx, names)
+		if err != nil {
+			return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+		}
+		for _, row := range rows {
+			configured[row.Name] = row
+		}
+	}
+
+	tableRows := make([]check.TableRow, 0, len(pending))
+	for _, row := range pending {
+		value, source := "-", "-"
+		if file, ok := configured[row.Name]; ok {
+			value, source = file.ConfiguredValue, file.Source
+		}
+		tableRows = append(tableRows, check.TableRow{
+			Cells:    []string{row.Name, row.RunningValue, value, source},
+			Severity: check.SeverityWarn,
+		})
+	}
+
+	details := fmt.Sprintf("%d parameter(s) were changed but only take effect at server start, "+
+		"so the server is not running with its configuration. Restart it in a maintenance window, or revert the change",
+		len(pending))
+	if !readable {
+		details += ".\n\nConfigured values are not shown: reading pg_file_settings requires superuser"
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: check.SeverityWarn,
+		Details:  details,
+		Table: &check.Table{
+			Headers: []string{"Parameter", "Running", "Configured", "Source"},
+			// File paths reveal the server's layout.
+			Sensitive: []check.Sensitivity{check.NotSensitive, check.NotSensitive, check.NotSensitive, check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	})
+
+	return report, nil
+}
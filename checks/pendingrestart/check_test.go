@@ -0,0 +1,112 @@
+package pendingrestart_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/pendingrestart"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeQueries struct {
+	pending    checktest.Result[[]db.PendingRestartSettingsRow]
+	readable   checktest.Result[bool]
+	configured checktest.Result[[]db.ConfiguredSettingsRow]
+	names      *[]string
+}
+
+func (f fakeQueries) PendingRestartSettings(context.Context) ([]db.PendingRestartSettingsRow, error) {
+	return f.pending.Get()
+}
+
+func (f fakeQueries) FileSettingsReadable(context.Context) (bool, error) {
+	return f.readable.Get()
+}
+
+func (f fakeQueries) ConfiguredSettings(_ context.Context, names []string) ([]db.ConfiguredSettingsRow, error) {
+	if f.names != nil {
+		*f.names = names
+	}
+	return f.configured.Get()
+}
+
+var pending = []db.PendingRestartSettingsRow{
+	{Name: "max_connections", RunningValue: "100"},
+	{Name: "shared_buffers", RunningValue: "128MB"},
+}
+
+func TestPendingRestart_Metadata(t *testing.T) {
+	t.Parallel()
+	m := pendingrestart.Metadata()
+	checktest.RequireMetadata(t, m, "pending-restart", check.CategoryConfigs)
+	assert.True(t, m.ClusterWide)
+}
+
+func TestPendingRestart_QueryErrors(t *testing.T) {
+	t.Parallel()
+
+	boom := errors.New("boom")
+	checktest.RequireError(t, pendingrestart.New(fakeQueries{pending: checktest.Fails[[]db.PendingRestartSettingsRow](boom)}))
+	checktest.RequireError(t, pendingrestart.New(fakeQueries{
+		pending:  checktest.Returns(pending),
+		readable: checktest.Fails[bool](boom),
+	}))
+	checktest.RequireError(t, pendingrestart.New(fakeQueries{
+		pending:    checktest.Returns(pending),
+		readable:   checktest.Returns(true),
+		configured: checktest.Fails[[]db.ConfiguredSettingsRow](boom),
+	}))
+}
+
+func TestPendingRestart_None(t *testing.T) {
+	t.Parallel()
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, pendingrestart.New(fakeQueries{})))
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Nil(t, finding.Table)
+}
+
+func TestPendingRestart_Pending(t *testing.T) {
+	t.Parallel()
+
+	var names []string
+	finding := checktest.OnlyFinding(t, checktest.Run(t, pendingrestart.New(fakeQueries{
+		pending:  checktest.Returns(pending),
+		readable: checktest.Returns(true),
+		configured: checktest.Returns([]db.ConfiguredSettingsRow{
+			{Name: "shared_buffers", ConfiguredValue: "4GB", Source: "/var/lib/postgresql/data/postgresql.auto.conf:3"},
+		}),
+		names: &names,
+	})))
+	assert.Equal(t, []string{"max_connections", "shared_buffers"}, names)
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Contains(t, finding.Details, "2 parameter(s) were changed but only take effect at server start")
+	assert.NotContains(t, finding.Details, "superuser")
+	assert.Equal(t, [][]string{
+		{"max_connections", "100", "-", "-"},
+		{"shared_buffers", "128MB", "4GB", "/var/lib/postgresql/data/postgresql.auto.conf:3"},
+	}, checktest.Rows(finding))
+	require.NotNil(t, finding.Table)
+	assert.Equal(t, check.SensitiveIdentifier, finding.Table.ColumnSensitivity(3))
+}
+
+func TestPendingRestart_FileSettingsNotReadable(t *testing.T) {
+	t.Parallel()
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, pendingrestart.New(fakeQueries{
+		pending:    checktest.Returns(pending),
+		readable:   checktest.Returns(false),
+		configured: checktest.Fails[[]db.ConfiguredSettingsRow](errors.New("permission denied for function pg_show_all_file_settings")),
+	})))
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Contains(t, finding.Details, "reading pg_file_settings requires superuser")
+	assert.Equal(t, [][]string{
+		{"max_connections", "100", "-", "-"},
+		{"shared_buffers", "128MB", "-", "-"},
+	}, checktest.Rows(finding))
+}
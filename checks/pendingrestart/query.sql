@@ -0,0 +1,26 @@
+-- name: PendingRestartSettings :many
+-- Parameters changed in the configuration files but only applied at server
+-- start, with the value the server is running with, in its unit.
+SELECT
+  s.name::text AS name
+  , current_setting(s.name)::text AS running_value
+FROM pg_settings AS s
+WHERE s.pending_restart
+ORDER BY s.name;
+
+-- name: FileSettingsReadable :one
+-- pg_file_settings is readable by superusers only, unless EXECUTE on its
+-- function was granted.
+SELECT has_function_privilege('pg_show_all_file_settings()', 'EXECUTE') AS readable;
+
+-- name: ConfiguredSettings :many
+-- The value each of the given parameters is set to in the configuration
+-- files, including postgresql.auto.conf written by ALTER SYSTEM. The last
+-- entry of a parameter is the one the server reads.
+SELECT DISTINCT ON (f.name)
+  f.name::text AS name
+  , coalesce(f.setting, '')::text AS configured_value
+  , (coalesce(f.sourcefile, '') || ':' || coalesce(f.sourceline, 0))::text AS source
+FROM pg_file_settings AS f
+WHERE f.name = ANY(sqlc.arg(names)::text[])
+ORDER BY f.name, f.seqno DESC;
@@ -32,6 +32,7 @@ func Metadata() check.Metadata {
 		Description: "Checks if PostgreSQL version is supported and up to date",
 		Readme:      readme,
 		SQL:         querySQL,
+		ClusterWide: true,
 	}
 }
 
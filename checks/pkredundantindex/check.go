@@ -0,0 +1,105 @@
+// Package pkredundantindex implements a check for secondary indexes that duplicate the primary key.
+package pkredundantindex
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type PKRedundantIndexQueries interface {
+	RedundantPrimaryKeyIndexes(context.Context) ([]db.RedundantPrimaryKeyIndexesRow, error)
+}
+
+type checker struct {
+	queries PKRedundantIndexQueries
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryIndexes,
+		CheckID:     "pk-redundant-index",
+		Name:        "Indexes Duplicating Primary Keys",
+		Description: "Identifies secondary indexes on leading primary key columns, which the primary key index already provides",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries PKRedundantIndexQueries, _ ...check.Config) check.Checker {
+	return &checker{
+		queries: queries,
+	}
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	rows, err := c.queries.RedundantPrimaryKeyIndexes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	if len(rows) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+		})
+		return report, nil
+	}
+
+	var wasted int64
+	tableRows := make([]check.TableRow, 0, len(rows))
+	for _, row := range rows {
+		wasted += row.IndexSizeBytes
+		constraint := row.ConstraintName
+		if constraint == "" {
+			constraint = "-"
+		}
+		tableRows = append(tableRows, check.TableRow{
+			Cells: []string{
+				row.TableName,
+				row.IndexName,
+				row.IndexColumns,
+				row.PrimaryKeyName,
+				constraint,
+				check.FormatBytes(row.IndexSizeBytes),
+			},
+			Severity: check.SeverityWarn,
+		})
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: check.SeverityWarn,
+		Details: fmt.Sprintf("%d index(es) duplicate their table's primary key, wasting %s and slowing every write",
+			len(rows), check.FormatBytes(wasted)),
+		Table: &check.Table{
+			Headers: []string{"Table", "Index", "Columns", "Primary Key", "Constraint", "Size"},
+			Key:     []int{0, 1},
+			Align:   []check.ColumnAlign{check.AlignLeft, check.AlignLeft, check.AlignLeft, check.AlignLeft, check.AlignLeft, check.AlignRight},
+			Sensitive: []check.Sensitivity{
+				check.SensitiveIdentifier, check.SensitiveIdentifier, check.SensitiveIdentifier,
+				check.SensitiveIdentifier, check.SensitiveIdentifier,
+			},
+			Rows: tableRows,
+		},
+	})
+
+	return report, nil
+}
@@ -0,0 +1,68 @@
+package pkredundantindex_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/pkredundantindex"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeQueries struct {
+	indexes checktest.Result[[]db.RedundantPrimaryKeyIndexesRow]
+}
+
+func (f fakeQueries) RedundantPrimaryKeyIndexes(context.Context) ([]db.RedundantPrimaryKeyIndexesRow, error) {
+	return f.indexes.Get()
+}
+
+func newChecker(indexes ...db.RedundantPrimaryKeyIndexesRow) check.Checker {
+	return pkredundantindex.New(fakeQueries{indexes: checktest.Returns(indexes)})
+}
+
+func TestPKRedundantIndex_Metadata(t *testing.T) {
+	t.Parallel()
+	checktest.RequireMetadata(t, pkredundantindex.Metadata(), "pk-redundant-index", check.CategoryIndexes)
+}
+
+func TestPKRedundantIndex_QueryError(t *testing.T) {
+	t.Parallel()
+	checktest.RequireError(t, pkredundantindex.New(fakeQueries{indexes: checktest.Fails[[]db.RedundantPrimaryKeyIndexesRow](errors.New("boom"))}))
+}
+
+func TestPKRedundantIndex_None(t *testing.T) {
+	t.Parallel()
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker()))
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Nil(t, finding.Table)
+}
+
+func TestPKRedundantIndex_Redundant(t *testing.T) {
+	t.Parallel()
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(
+		db.RedundantPrimaryKeyIndexesRow{
+			TableName: "public.orders", IndexName: "orders_id_idx", PrimaryKeyName: "orders_pkey",
+			IndexColumns: "id", IndexSizeBytes: 3 << 20,
+		},
+		db.RedundantPrimaryKeyIndexesRow{
+			TableName: "public.order_items", IndexName: "order_items_key", PrimaryKeyName: "order_items_pkey",
+			IndexColumns: "order_id, line", IndexSizeBytes: 1 << 20, ConstraintName: "order_items_key",
+		},
+	)))
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Equal(t, "2 index(es) duplicate their table's primary key, wasting 4.0MiB and slowing every write", finding.Details)
+	assert.Equal(t, [][]string{
+		{"public.orders", "orders_id_idx", "id", "orders_pkey", "-", "3.0MiB"},
+		{"public.order_items", "order_items_key", "order_id, line", "order_items_pkey", "order_items_key", "1.0MiB"},
+	}, checktest.Rows(finding))
+	require.NotNil(t, finding.Table)
+	assert.Equal(t, check.SensitiveIdentifier, finding.Table.ColumnSensitivity(0))
+	assert.Equal(t, check.NotSensitive, finding.Table.ColumnSensitivity(5), "sizes are not identifiers")
+}
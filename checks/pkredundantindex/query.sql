@@ -0,0 +1,55 @@
+-- name: RedundantPrimaryKeyIndexes :many
+-- Secondary btree indexes the primary key index already provides: their key
+-- columns are the leading key columns of the primary key, with the same
+-- operator classes, collations, and sort options. Unique indexes only count
+-- when they cover every primary key column, since a unique index on a prefix
+-- enforces more than the primary key does.
+-- Excludes: expression, partial, and INCLUDE indexes (not replaceable by the
+-- primary key), indexes referenced by foreign keys, and system schemas.
+SELECT
+  (n.nspname || '.' || t.relname)::text AS table_name
+  , i.relname::text AS index_name
+  , pk.relname::text AS primary_key_name
+  , (
+    SELECT string_agg(a.attname, ', ' ORDER BY k.ord)
+    FROM unnest(idx.indkey) WITH ORDINALITY AS k (attnum, ord)
+    INNER JOIN pg_attribute AS a ON a.attrelid = idx.indrelid AND k.attnum = a.attnum
+  )::text AS index_columns
+  , pg_relation_size(i.oid)::bigint AS index_size_bytes
+  , coalesce(con.conname, '')::text AS constraint_name
+FROM pg_index AS pkidx
+INNER JOIN pg_index AS idx ON
+  pkidx.indrelid = idx.indrelid
+  AND pkidx.indexrelid <> idx.indexrelid
+INNER JOIN pg_class AS pk ON pkidx.indexrelid = pk.oid
+INNER JOIN pg_class AS i ON idx.indexrelid = i.oid
+INNER JOIN pg_class AS t ON idx.indrelid = t.oid
+INNER JOIN pg_namespace AS n ON t.relnamespace = n.oid
+INNER JOIN pg_am AS am ON i.relam = am.oid
+LEFT JOIN pg_constraint AS con ON idx.indexrelid = con.conindid AND con.conrelid = t.oid
+WHERE
+  pkidx.indisprimary
+  AND i.relkind = 'i'
+  AND idx.indisvalid
+  AND am.amname = 'btree'
+  AND idx.indexprs IS NULL
+  AND idx.indpred IS NULL
+  AND idx.indnatts = idx.indnkeyatts
+  AND idx.indnkeyatts <= pkidx.indnkeyatts
+  AND (NOT idx.indisunique OR idx.indnkeyatts = pkidx.indnkeyatts)
+  -- The vectors' text forms are space-separated, so a prefix match on
+  -- "<vector> " compares whole entries.
+  AND (pkidx.indkey::text || ' ') LIKE (idx.indkey::text || ' %')
+  AND (pkidx.indclass::text || ' ') LIKE (idx.indclass::text || ' %')
+  AND (pkidx.indcollation::text || ' ') LIKE (idx.indcollation::text || ' %')
+  AND (pkidx.indoption::text || ' ') LIKE (idx.indoption::text || ' %')
+  AND NOT EXISTS (
+    SELECT 1
+    FROM pg_constraint AS fk
+    WHERE fk.contype = 'f' AND idx.indexrelid = fk.conindid
+  )
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast', 'cron', 'pgpartman', 'debezium')
+ORDER BY
+  index_size_bytes DESC
+  , table_name
+  , index_name;
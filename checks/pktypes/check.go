@@ -95,8 +95,11 @@ func (c *checker) Check(ctx context.Context) (*check.Report, error) {
 		Severity: maxSeverity,
 		Details:  formatDetails(criticalCount, warningCount),
 		Table: &check.Table{
-			Headers: []string{"Table", "Column", "Type", "Usage %", "Rows"},
-			Rows:    tableRows,
+			Headers:   []string{"Table", "Column", "Type", "Usage %", "Rows"},
+			Key:       []int{0, 1},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignLeft, check.AlignLeft, check.AlignRight, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.SensitiveIdentifier},
+			Rows:      tableRows,
 		},
 	})
 
@@ -0,0 +1,192 @@
+// Package preloadlibraries implements a check for libraries missing from shared_preload_libraries.
+package preloadlibraries
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+// preloadRequired maps extensions to the library they only work with when it
+// is in shared_preload_libraries. Extensions that merely benefit from
+// preloading are left out.
+var preloadRequired = map[string]string{
+	"citus":              "citus",
+	"pg_cron":            "pg_cron",
+	"pg_qualstats":       "pg_qualstats",
+	"pg_squeeze":         "pg_squeeze",
+	"pg_stat_kcache":     "pg_stat_kcache",
+	"pg_stat_monitor":    "pg_stat_monitor",
+	"pg_stat_statements": "pg_stat_statements",
+	"pg_wait_sampling":   "pg_wait_sampling",
+	"pgaudit":            "pgaudit",
+	"pglogical":          "pglogical",
+	"pgsentinel":         "pgsentinel",
+	"timescaledb":        "timescaledb",
+}
+
+type PreloadLibrariesQueries interface {
+	PreloadLibraries(context.Context) (db.PreloadLibrariesRow, error)
+	InstalledExtensions(context.Context) ([]string, error)
+}
+
+type checker struct {
+	queries  PreloadLibrariesQueries
+	expected []string
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryConfigs,
+		CheckID:     "preload-libraries",
+		Name:        "Shared Preload Libraries",
+		Description: "Flags expected libraries and installed extensions that are missing from shared_preload_libraries",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries PreloadLibrariesQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries: queries,
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			if v, ok := myCfg["expected"]; ok {
+				c.expected = parseLibraries(v)
+			}
+		}
+	}
+	return c
+}
+
+// parseLibraries splits a shared_preload_libraries style list into library
+// names. Entries may be quoted, carry a directory ("$libdir/pg_cron"), or a
+// shared library suffix; all of those name the same library.
+func parseLibraries(value string) []string {
+	var libraries []string
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.Trim(strings.TrimSpace(entry), `"`)
+		if entry == "" {
+			continue
+		}
+		name := path.Base(entry)
+		for _, suffix := range []string{".so", ".dylib", ".dll"} {
+			name = strings.TrimSuffix(name, suffix)
+		}
+		libraries = append(libraries, name)
+	}
+	return libraries
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+// requirement is one library that must be preloaded, and why.
+type requirement struct {
+	library    string
+	requiredBy []string
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	setting, err := c.queries.PreloadLibraries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+	extensions, err := c.queries.InstalledExtensions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	preloaded := map[string]bool{}
+	for _, library := range parseLibraries(setting.Libraries) {
+		preloaded[library] = true
+	}
+
+	// Configured libraries first, in their order, then those installed
+	// extensions need, by name.
+	var requirements []*requirement
+	byLibrary := map[string]*requirement{}
+	need := func(library, reason string) {
+		r, ok := byLibrary[library]
+		if !ok {
+			r = &requirement{library: library}
+			byLibrary[library] = r
+			requirements = append(requirements, r)
+		}
+		r.requiredBy = append(r.requiredBy, reason)
+	}
+	for _, library := range c.expected {
+		need(library, "configured")
+	}
+	sort.Strings(extensions)
+	for _, extension := range extensions {
+		if library, ok := preloadRequired[extension]; ok {
+			need(library, "extension "+extension)
+		}
+	}
+
+	details := fmt.Sprintf("shared_preload_libraries = '%s'", setting.Libraries)
+	if setting.PendingRestart {
+		details += " (a changed value is waiting for a restart)"
+	}
+
+	if len(requirements) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  details + "; no expected libraries configured and no installed extension needs preloading",
+		})
+		return report, nil
+	}
+
+	severity := check.SeverityOK
+	missing := 0
+	tableRows := make([]check.TableRow, 0, len(requirements))
+	for _, r := range requirements {
+		status, rowSeverity := "Preloaded", check.SeverityOK
+		if !preloaded[r.library] {
+			status, rowSeverity = "Missing", check.SeverityWarn
+			severity = check.SeverityWarn
+			missing++
+		}
+		tableRows = append(tableRows, check.TableRow{
+			Cells:    []string{r.library, strings.Join(r.requiredBy, ", "), status},
+			Severity: rowSeverity,
+		})
+	}
+
+	if missing > 0 {
+		details += fmt.Sprintf("; %d of %d required libraries not preloaded. Add them to shared_preload_libraries and restart the server",
+			missing, len(requirements))
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: severity,
+		Details:  details,
+		Table: &check.Table{
+			Headers: []string{"Library", "Required By", "Status"},
+			Rows:    tableRows,
+		},
+	})
+
+	return report, nil
+}
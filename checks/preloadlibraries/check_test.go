@@ -0,0 +1,103 @@
+package preloadlibraries_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/preloadlibraries"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeQueries struct {
+	setting    checktest.Result[db.PreloadLibrariesRow]
+	extensions checktest.Result[[]string]
+}
+
+func (f fakeQueries) PreloadLibraries(context.Context) (db.PreloadLibrariesRow, error) {
+	return f.setting.Get()
+}
+
+func (f fakeQueries) InstalledExtensions(context.Context) ([]string, error) {
+	return f.extensions.Get()
+}
+
+func newChecker(libraries string, extensions []string, cfg ...check.Config) check.Checker {
+	return preloadlibraries.New(fakeQueries{
+		setting:    checktest.Returns(db.PreloadLibrariesRow{Libraries: libraries}),
+		extensions: checktest.Returns(extensions),
+	}, cfg...)
+}
+
+func TestPreloadLibraries_NothingRequired(t *testing.T) {
+	t.Parallel()
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker("", []string{"plpgsql", "pgcrypto"})))
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "shared_preload_libraries = ''; no expected libraries configured and no installed extension needs preloading", finding.Details)
+	assert.Nil(t, finding.Table)
+}
+
+func TestPreloadLibraries_InstalledExtensionNotPreloaded(t *testing.T) {
+	t.Parallel()
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(
+		`"$libdir/pg_cron.so"`,
+		[]string{"pg_stat_statements", "plpgsql", "pg_cron"},
+	)))
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Contains(t, finding.Details, "1 of 2 required libraries not preloaded")
+	assert.Equal(t, [][]string{
+		{"pg_cron", "extension pg_cron", "Preloaded"},
+		{"pg_stat_statements", "extension pg_stat_statements", "Missing"},
+	}, checktest.Rows(finding), "library paths and suffixes match by name")
+}
+
+func TestPreloadLibraries_Expected(t *testing.T) {
+	t.Parallel()
+
+	cfg := check.Config{"preload-libraries": {"expected": "auto_explain, pg_stat_statements"}}
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(
+		"pg_stat_statements",
+		[]string{"pg_stat_statements"},
+		cfg,
+	)))
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Equal(t, [][]string{
+		{"auto_explain", "configured", "Missing"},
+		{"pg_stat_statements", "configured, extension pg_stat_statements", "Preloaded"},
+	}, checktest.Rows(finding))
+}
+
+func TestPreloadLibraries_AllPreloaded(t *testing.T) {
+	t.Parallel()
+
+	checker := preloadlibraries.New(fakeQueries{
+		setting:    checktest.Returns(db.PreloadLibrariesRow{Libraries: "pg_stat_statements, pgaudit", PendingRestart: true}),
+		extensions: checktest.Returns([]string{"pgaudit", "pg_stat_statements"}),
+	})
+	finding := checktest.OnlyFinding(t, checktest.Run(t, checker))
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "shared_preload_libraries = 'pg_stat_statements, pgaudit' (a changed value is waiting for a restart)", finding.Details)
+	assert.Len(t, checktest.Rows(finding), 2)
+}
+
+func TestPreloadLibraries_QueryError(t *testing.T) {
+	t.Parallel()
+
+	queryErr := fmt.Errorf("connection reset")
+	checktest.RequireError(t, preloadlibraries.New(fakeQueries{setting: checktest.Fails[db.PreloadLibrariesRow](queryErr)}))
+	checktest.RequireError(t, preloadlibraries.New(fakeQueries{
+		setting:    checktest.Returns(db.PreloadLibrariesRow{}),
+		extensions: checktest.Fails[[]string](queryErr),
+	}))
+}
+
+func TestPreloadLibraries_Metadata(t *testing.T) {
+	t.Parallel()
+
+	checktest.RequireMetadata(t, preloadlibraries.Metadata(), "preload-libraries", check.CategoryConfigs)
+}
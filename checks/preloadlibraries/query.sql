@@ -0,0 +1,14 @@
+-- name: PreloadLibraries :one
+-- The running shared_preload_libraries, and whether a changed value is
+-- waiting for a restart to take effect.
+SELECT
+  coalesce(s.setting, '') AS libraries
+  , coalesce(s.pending_restart, false) AS pending_restart
+FROM pg_settings AS s
+WHERE s.name = 'shared_preload_libraries';
+
+-- name: InstalledExtensions :many
+-- Extensions created in the current database.
+SELECT e.extname::text AS extension_name
+FROM pg_extension AS e
+ORDER BY e.extname;
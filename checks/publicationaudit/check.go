@@ -0,0 +1,297 @@
+// Package publicationaudit implements checks for logical replication publication and subscription drift.
+package publicationaudit
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+// tablesKeyPrefix prefixes config keys listing the tables a publication must
+// contain, e.g. "tables.cdc" = "public.orders, public.customers".
+const tablesKeyPrefix = "tables."
+
+type PublicationAuditQueries interface {
+	Publications(context.Context) ([]db.PublicationsRow, error)
+	PublicationTables(context.Context) ([]db.PublicationTablesRow, error)
+	SubscriptionSlots(context.Context) ([]db.SubscriptionSlotsRow, error)
+}
+
+type checker struct {
+	queries PublicationAuditQueries
+	// expected maps a publication name to the tables it must publish.
+	expected map[string][]string
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryConfigs,
+		CheckID:     "publication-audit",
+		Name:        "Publication Audit",
+		Description: "Detects logical replication drift: tables missing from publications, partial operation flags, and subscriptions not streaming",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries PublicationAuditQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries:  queries,
+		expected: map[string][]string{},
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			for key, value := range myCfg {
+				publication, ok := strings.CutPrefix(key, tablesKeyPrefix)
+				if !ok || publication == "" {
+					continue
+				}
+				for _, table := range strings.Split(value, ",") {
+					if table = strings.TrimSpace(table); table != "" {
+						c.expected[publication] = append(c.expected[publication], qualify(table))
+					}
+				}
+			}
+		}
+	}
+	return c
+}
+
+// qualify prefixes unqualified table names with the public schema, matching
+// how pg_publication_tables reports them.
+func qualify(table string) string {
+	if strings.Contains(table, ".") {
+		return table
+	}
+	return "public." + table
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	publications, err := c.queries.Publications(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", check.CategoryConfigs, report.CheckID, err)
+	}
+
+	publicationTables, err := c.queries.PublicationTables(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", check.CategoryConfigs, report.CheckID, err)
+	}
+
+	subscriptions, err := c.queries.SubscriptionSlots(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", check.CategoryConfigs, report.CheckID, err)
+	}
+
+	c.checkMissingTables(publications, publicationTables, report)
+	checkOperationFlags(publications, report)
+	checkSubscriptionSlots(subscriptions, report)
+
+	return report, nil
+}
+
+func (c *checker) checkMissingTables(publications []db.PublicationsRow, publicationTables []db.PublicationTablesRow, report *check.Report) {
+	if len(c.expected) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       "missing-tables",
+			Name:     "Tables Missing From Publications",
+			Severity: check.SeverityOK,
+			Details:  "No expected publication tables configured",
+		})
+		return
+	}
+
+	exists := make(map[string]bool, len(publications))
+	for _, pub := range publications {
+		exists[pub.PublicationName] = true
+	}
+	published := make(map[string]bool, len(publicationTables))
+	for _, row := range publicationTables {
+		published[row.PublicationName+"/"+row.TableName] = true
+	}
+
+	names := make([]string, 0, len(c.expected))
+	for name := range c.expected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var tableRows []check.TableRow
+	expectedCount := 0
+	for _, name := range names {
+		for _, table := range c.expected[name] {
+			expectedCount++
+			problem := ""
+			switch {
+			case !exists[name]:
+				problem = "publication does not exist"
+			case !published[name+"/"+table]:
+				problem = "table not in publication"
+			default:
+				continue
+			}
+			tableRows = append(tableRows, check.TableRow{
+				Cells:    []string{name, table, problem},
+				Severity: check.SeverityWarn,
+			})
+		}
+	}
+
+	if len(tableRows) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       "missing-tables",
+			Name:     "Tables Missing From Publications",
+			Severity: check.SeverityOK,
+			Details:  fmt.Sprintf("All %d expected table(s) are published", expectedCount),
+		})
+		return
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       "missing-tables",
+		Name:     "Tables Missing From Publications",
+		Severity: check.SeverityWarn,
+		Details:  fmt.Sprintf("%d of %d expected table(s) are not published; changes to them are silently not replicated", len(tableRows), expectedCount),
+		Table: &check.Table{
+			Headers:   []string{"Publication", "Table", "Problem"},
+			Key:       []int{0, 1},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	})
+}
+
+func checkOperationFlags(publications []db.PublicationsRow, report *check.Report) {
+	var tableRows []check.TableRow
+	for _, pub := range publications {
+		var missing []string
+		if !pub.Pubinsert {
+			missing = append(missing, "insert")
+		}
+		if !pub.Pubupdate {
+			missing = append(missing, "update")
+		}
+		if !pub.Pubdelete {
+			missing = append(missing, "delete")
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		tableRows = append(tableRows, check.TableRow{
+			Cells:    []string{pub.PublicationName, publishedOperations(pub), strings.Join(missing, ", ")},
+			Severity: check.SeverityWarn,
+		})
+	}
+
+	if len(tableRows) == 0 {
+		details := "No publications defined"
+		if len(publications) > 0 {
+			details = fmt.Sprintf("All %d publication(s) publish inserts, updates, and deletes", len(publications))
+		}
+		report.AddFinding(check.Finding{
+			ID:       "operation-flags",
+			Name:     "Publication Operations",
+			Severity: check.SeverityOK,
+			Details:  details,
+		})
+		return
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       "operation-flags",
+		Name:     "Publication Operations",
+		Severity: check.SeverityWarn,
+		Details:  fmt.Sprintf("Found %d publication(s) that skip inserts, updates, or deletes; subscribers drift from the source unless this is intended", len(tableRows)),
+		Table: &check.Table{
+			Headers:   []string{"Publication", "Publishes", "Skipped"},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	})
+}
+
+func publishedOperations(pub db.PublicationsRow) string {
+	var ops []string
+	for _, op := range []struct {
+		name    string
+		enabled bool
+	}{
+		{"insert", pub.Pubinsert},
+		{"update", pub.Pubupdate},
+		{"delete", pub.Pubdelete},
+		{"truncate", pub.Pubtruncate},
+	} {
+		if op.enabled {
+			ops = append(ops, op.name)
+		}
+	}
+	if len(ops) == 0 {
+		return "-"
+	}
+	return strings.Join(ops, ", ")
+}
+
+func checkSubscriptionSlots(subscriptions []db.SubscriptionSlotsRow, report *check.Report) {
+	var tableRows []check.TableRow
+	for _, sub := range subscriptions {
+		problem := ""
+		switch {
+		case !sub.SlotName.Valid:
+			problem = "no slot (slot_name = NONE)"
+		case !sub.WorkerRunning:
+			problem = "apply worker not running"
+		default:
+			continue
+		}
+		slot := "-"
+		if sub.SlotName.Valid {
+			slot = sub.SlotName.String
+		}
+		tableRows = append(tableRows, check.TableRow{
+			Cells:    []string{sub.SubscriptionName, slot, problem},
+			Severity: check.SeverityWarn,
+		})
+	}
+
+	if len(tableRows) == 0 {
+		details := "No enabled subscriptions in this database"
+		if len(subscriptions) > 0 {
+			details = fmt.Sprintf("All %d enabled subscription(s) are streaming from their slot", len(subscriptions))
+		}
+		report.AddFinding(check.Finding{
+			ID:       "subscription-slots",
+			Name:     "Subscription Slots",
+			Severity: check.SeverityOK,
+			Details:  details,
+		})
+		return
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       "subscription-slots",
+		Name:     "Subscription Slots",
+		Severity: check.SeverityWarn,
+		Details:  fmt.Sprintf("Found %d enabled subscription(s) not streaming from a replication slot", len(tableRows)),
+		Table: &check.Table{
+			Headers:   []string{"Subscription", "Slot", "Problem"},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	})
+}
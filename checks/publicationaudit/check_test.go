@@ -0,0 +1,207 @@
+package publicationaudit_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/checks/publicationaudit"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockQueryer struct {
+	publications  []db.PublicationsRow
+	tables        []db.PublicationTablesRow
+	subscriptions []db.SubscriptionSlotsRow
+	err           error
+}
+
+func (m *mockQueryer) Publications(context.Context) ([]db.PublicationsRow, error) {
+	return m.publications, m.err
+}
+
+func (m *mockQueryer) PublicationTables(context.Context) ([]db.PublicationTablesRow, error) {
+	return m.tables, m.err
+}
+
+func (m *mockQueryer) SubscriptionSlots(context.Context) ([]db.SubscriptionSlotsRow, error) {
+	return m.subscriptions, m.err
+}
+
+// fullPublication publishes every operation.
+func fullPublication(name string) db.PublicationsRow {
+	return db.PublicationsRow{PublicationName: name, Pubinsert: true, Pubupdate: true, Pubdelete: true, Pubtruncate: true}
+}
+
+func published(publication string, tables ...string) []db.PublicationTablesRow {
+	var rows []db.PublicationTablesRow
+	for _, table := range tables {
+		rows = append(rows, db.PublicationTablesRow{PublicationName: publication, TableName: table})
+	}
+	return rows
+}
+
+func streaming(name string) db.SubscriptionSlotsRow {
+	return db.SubscriptionSlotsRow{SubscriptionName: name, SlotName: pgtype.Text{String: name, Valid: true}, WorkerRunning: true}
+}
+
+func findingByID(t *testing.T, report *check.Report, id string) check.Finding {
+	t.Helper()
+
+	for _, f := range report.Results {
+		if f.ID == id {
+			return f
+		}
+	}
+	require.Failf(t, "finding not found", "no finding with ID %q", id)
+	return check.Finding{}
+}
+
+func TestPublicationAudit_NoLogicalReplication(t *testing.T) {
+	t.Parallel()
+
+	report, err := publicationaudit.New(&mockQueryer{}).Check(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, check.SeverityOK, report.Severity)
+	require.Len(t, report.Results, 3)
+	assert.Equal(t, "No expected publication tables configured", findingByID(t, report, "missing-tables").Details)
+	assert.Equal(t, "No publications defined", findingByID(t, report, "operation-flags").Details)
+	assert.Equal(t, "No enabled subscriptions in this database", findingByID(t, report, "subscription-slots").Details)
+}
+
+func TestPublicationAudit_MissingTables(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		cfg      map[string]string
+		severity check.Severity
+		problems [][]string
+	}{
+		{
+			name:     "all expected tables published",
+			cfg:      map[string]string{"tables.cdc": "public.orders, customers"},
+			severity: check.SeverityOK,
+		},
+		{
+			name:     "table added but not published",
+			cfg:      map[string]string{"tables.cdc": "public.orders,public.customers,public.refunds"},
+			severity: check.SeverityWarn,
+			problems: [][]string{{"cdc", "public.refunds", "table not in publication"}},
+		},
+		{
+			name:     "publication does not exist",
+			cfg:      map[string]string{"tables.audit": "billing.invoices"},
+			severity: check.SeverityWarn,
+			problems: [][]string{{"audit", "billing.invoices", "publication does not exist"}},
+		},
+		{
+			name:     "unrelated config keys are ignored",
+			cfg:      map[string]string{"tables.": "public.orders", "min_rows": "10"},
+			severity: check.SeverityOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			queries := &mockQueryer{
+				publications: []db.PublicationsRow{fullPublication("cdc")},
+				tables:       published("cdc", "public.customers", "public.orders"),
+			}
+			cfg := check.Config{"publication-audit": tt.cfg}
+			report, err := publicationaudit.New(queries, cfg).Check(context.Background())
+			require.NoError(t, err)
+
+			finding := findingByID(t, report, "missing-tables")
+			assert.Equal(t, tt.severity, finding.Severity)
+			if tt.problems == nil {
+				assert.Nil(t, finding.Table)
+				return
+			}
+			require.NotNil(t, finding.Table)
+			var cells [][]string
+			for _, row := range finding.Table.Rows {
+				cells = append(cells, row.Cells)
+			}
+			assert.Equal(t, tt.problems, cells)
+		})
+	}
+}
+
+func TestPublicationAudit_OperationFlags(t *testing.T) {
+	t.Parallel()
+
+	insertOnly := db.PublicationsRow{PublicationName: "audit_feed", Pubinsert: true}
+	noTruncate := fullPublication("cdc")
+	noTruncate.Pubtruncate = false
+
+	report, err := publicationaudit.New(&mockQueryer{
+		publications: []db.PublicationsRow{noTruncate, insertOnly},
+	}).Check(context.Background())
+	require.NoError(t, err)
+
+	finding := findingByID(t, report, "operation-flags")
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	require.NotNil(t, finding.Table)
+	require.Len(t, finding.Table.Rows, 1, "skipping only truncate is not drift")
+	assert.Equal(t, []string{"audit_feed", "insert", "update, delete"}, finding.Table.Rows[0].Cells)
+}
+
+func TestPublicationAudit_SubscriptionSlots(t *testing.T) {
+	t.Parallel()
+
+	stalled := streaming("orders_sub")
+	stalled.WorkerRunning = false
+	detached := db.SubscriptionSlotsRow{SubscriptionName: "manual_sub"}
+
+	report, err := publicationaudit.New(&mockQueryer{
+		subscriptions: []db.SubscriptionSlotsRow{streaming("cdc_sub"), stalled, detached},
+	}).Check(context.Background())
+	require.NoError(t, err)
+
+	finding := findingByID(t, report, "subscription-slots")
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	require.NotNil(t, finding.Table)
+	require.Len(t, finding.Table.Rows, 2)
+	assert.Equal(t, []string{"orders_sub", "orders_sub", "apply worker not running"}, finding.Table.Rows[0].Cells)
+	assert.Equal(t, []string{"manual_sub", "-", "no slot (slot_name = NONE)"}, finding.Table.Rows[1].Cells)
+}
+
+func TestPublicationAudit_AllStreaming(t *testing.T) {
+	t.Parallel()
+
+	report, err := publicationaudit.New(&mockQueryer{
+		publications:  []db.PublicationsRow{fullPublication("cdc")},
+		subscriptions: []db.SubscriptionSlotsRow{streaming("cdc_sub")},
+	}).Check(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, check.SeverityOK, report.Severity)
+	assert.Equal(t, "All 1 enabled subscription(s) are streaming from their slot", findingByID(t, report, "subscription-slots").Details)
+}
+
+func TestPublicationAudit_QueryError(t *testing.T) {
+	t.Parallel()
+
+	_, err := publicationaudit.New(&mockQueryer{err: fmt.Errorf("permission denied")}).Check(context.Background())
+	require.ErrorContains(t, err, "publication-audit")
+}
+
+func TestPublicationAudit_Metadata(t *testing.T) {
+	t.Parallel()
+
+	m := publicationaudit.Metadata()
+	assert.Equal(t, "publication-audit", m.CheckID)
+	assert.Equal(t, "Publication Audit", m.Name)
+	assert.Equal(t, check.CategoryConfigs, m.Category)
+	assert.NotEmpty(t, m.Description)
+	assert.NotEmpty(t, m.SQL)
+	assert.NotEmpty(t, m.Readme)
+}
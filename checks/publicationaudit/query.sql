@@ -0,0 +1,41 @@
+-- name: Publications :many
+-- Every publication in the current database with the operations it publishes.
+SELECT
+  p.pubname::text AS publication_name
+  , p.puballtables
+  , p.pubinsert
+  , p.pubupdate
+  , p.pubdelete
+  , p.pubtruncate
+FROM pg_publication AS p
+ORDER BY p.pubname;
+
+-- name: PublicationTables :many
+-- Tables each publication replicates. FOR ALL TABLES (and, on PG 15+,
+-- FOR TABLES IN SCHEMA) publications are expanded by the view.
+SELECT
+  pubname::text AS publication_name
+  , (schemaname || '.' || tablename)::text AS table_name
+FROM pg_publication_tables
+ORDER BY pubname, schemaname, tablename;
+
+-- name: SubscriptionSlots :many
+-- Enabled subscriptions in the current database and whether their apply worker
+-- is streaming. A worker that is not running usually means the slot is missing
+-- on the publisher or the connection fails.
+SELECT
+  s.subname::text AS subscription_name
+  , s.subslotname::text AS slot_name
+  , EXISTS (
+    SELECT 1
+    FROM pg_stat_subscription AS w
+    WHERE
+      w.subid = s.oid
+      AND w.relid IS NULL
+      AND w.pid IS NOT NULL
+  ) AS worker_running
+FROM pg_subscription AS s
+WHERE
+  s.subenabled
+  AND s.subdbid = (SELECT oid FROM pg_database WHERE datname = current_database())
+ORDER BY s.subname;
@@ -0,0 +1,151 @@
+// Package relationsizelimit implements a check for relations approaching PostgreSQL's per-fork size limit.
+package relationsizelimit
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strconv"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+// failLimitPercent is the share of the hard per-fork limit at which a
+// relation stops being a planning item and becomes an imminent outage.
+const failLimitPercent = 75.0
+
+type RelationSizeLimitQueries interface {
+	LargeRelations(context.Context) ([]db.LargeRelationsRow, error)
+}
+
+type checker struct {
+	queries   RelationSizeLimitQueries
+	warnBytes int64 // default: 1TiB
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategorySchema,
+		CheckID:     "relation-size-limit",
+		Name:        "Relation Size Limit",
+		Description: "Identifies tables, indexes, and partitions growing toward the 32TB per-fork limit",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries RelationSizeLimitQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries:   queries,
+		warnBytes: 1024 * check.GiB,
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			if v, ok := myCfg["warn_size_gb"]; ok {
+				if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+					c.warnBytes = n * check.GiB
+				}
+			}
+		}
+	}
+	return c
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	rows, err := c.queries.LargeRelations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	var tableRows []check.TableRow
+	severity := check.SeverityOK
+
+	for _, row := range rows {
+		if row.SizeBytes < c.warnBytes {
+			continue
+		}
+
+		pct := limitPercent(row)
+		rowSeverity := check.SeverityWarn
+		if pct >= failLimitPercent {
+			rowSeverity = check.SeverityFail
+		}
+		if rowSeverity > severity {
+			severity = rowSeverity
+		}
+
+		tableRows = append(tableRows, check.TableRow{
+			Cells: []string{
+				row.SchemaName + "." + row.RelationName,
+				row.RelationKind,
+				formatParent(row.ParentName),
+				check.FormatBytes(row.SizeBytes),
+				fmt.Sprintf("%.1f%%", pct),
+				formatInserted(row),
+			},
+			Severity: rowSeverity,
+		})
+	}
+
+	if len(tableRows) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  fmt.Sprintf("No relation exceeds %s", check.FormatBytes(c.warnBytes)),
+		})
+		return report, nil
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: severity,
+		Details: fmt.Sprintf("Found %d relation(s) larger than %s (hard limit: %s per fork)",
+			len(tableRows), check.FormatBytes(c.warnBytes), check.FormatBytes(rows[0].MaxForkBytes)),
+		Table: &check.Table{
+			Headers:   []string{"Relation", "Kind", "Parent", "Size", "% of Limit", "Rows Inserted"},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignLeft, check.AlignLeft, check.AlignRight, check.AlignRight, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.NotSensitive, check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	})
+
+	return report, nil
+}
+
+func limitPercent(row db.LargeRelationsRow) float64 {
+	if row.MaxForkBytes <= 0 {
+		return 0
+	}
+	return float64(row.SizeBytes) / float64(row.MaxForkBytes) * 100
+}
+
+func formatParent(parent string) string {
+	if parent == "" {
+		return "-"
+	}
+	return parent
+}
+
+// formatInserted renders rows inserted since the last stats reset; indexes and
+// TOAST relations have no tuple counters of their own.
+func formatInserted(row db.LargeRelationsRow) string {
+	if row.RelationKind == "index" || row.RelationKind == "toast" || !row.RowsInserted.Valid {
+		return "-"
+	}
+	return check.FormatNumber(row.RowsInserted.Int64)
+}
@@ -0,0 +1,174 @@
+package relationsizelimit_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/checks/relationsizelimit"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// maxForkBytes is the hard per-fork limit with the default 8KB block size.
+const maxForkBytes = int64(8192) * 4294967295
+
+type mockQueryer struct {
+	rows []db.LargeRelationsRow
+	err  error
+}
+
+func (m *mockQueryer) LargeRelations(context.Context) ([]db.LargeRelationsRow, error) {
+	return m.rows, m.err
+}
+
+func relation(name, kind string, size int64) db.LargeRelationsRow {
+	return db.LargeRelationsRow{
+		SchemaName:   "public",
+		RelationName: name,
+		RelationKind: kind,
+		SizeBytes:    size,
+		MaxForkBytes: maxForkBytes,
+		RowsInserted: pgtype.Int8{Int64: 1_500_000, Valid: true},
+	}
+}
+
+func partition(name, parent string, size int64) db.LargeRelationsRow {
+	row := relation(name, "partition", size)
+	row.ParentName = parent
+	return row
+}
+
+func TestRelationSizeLimit_Severity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		rows     []db.LargeRelationsRow
+		severity check.Severity
+		tableLen int
+	}{
+		{
+			name:     "no large relations",
+			rows:     nil,
+			severity: check.SeverityOK,
+		},
+		{
+			name:     "below warn threshold",
+			rows:     []db.LargeRelationsRow{relation("events", "table", 500*check.GiB)},
+			severity: check.SeverityOK,
+		},
+		{
+			name:     "above warn threshold",
+			rows:     []db.LargeRelationsRow{relation("events", "table", 2048*check.GiB)},
+			severity: check.SeverityWarn,
+			tableLen: 1,
+		},
+		{
+			name:     "near hard limit",
+			rows:     []db.LargeRelationsRow{relation("events", "table", maxForkBytes*80/100)},
+			severity: check.SeverityFail,
+			tableLen: 1,
+		},
+		{
+			name: "only relations above threshold are listed",
+			rows: []db.LargeRelationsRow{
+				relation("events", "table", 4096*check.GiB),
+				relation("events_pkey", "index", 1500*check.GiB),
+				relation("users", "table", 10*check.GiB),
+			},
+			severity: check.SeverityWarn,
+			tableLen: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			checker := relationsizelimit.New(&mockQueryer{rows: tt.rows})
+			report, err := checker.Check(context.Background())
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.severity, report.Severity)
+			require.Len(t, report.Results, 1)
+			assert.Equal(t, "relation-size-limit", report.Results[0].ID)
+
+			if tt.tableLen == 0 {
+				assert.Nil(t, report.Results[0].Table)
+				return
+			}
+			require.NotNil(t, report.Results[0].Table)
+			assert.Len(t, report.Results[0].Table.Rows, tt.tableLen)
+		})
+	}
+}
+
+func TestRelationSizeLimit_TableContents(t *testing.T) {
+	t.Parallel()
+
+	checker := relationsizelimit.New(&mockQueryer{rows: []db.LargeRelationsRow{
+		partition("events_default", "public.events", 2*1024*check.GiB),
+		relation("events_pkey", "index", 1200*check.GiB),
+	}})
+	report, err := checker.Check(context.Background())
+	require.NoError(t, err)
+
+	finding := report.Results[0]
+	require.NotNil(t, finding.Table)
+	assert.Equal(t, []string{"Relation", "Kind", "Parent", "Size", "% of Limit", "Rows Inserted"}, finding.Table.Headers)
+	assert.Equal(t, []string{"public.events_default", "partition", "public.events", "2.0TiB", "6.3%", "1.5M"}, finding.Table.Rows[0].Cells)
+	assert.Equal(t, "-", finding.Table.Rows[1].Cells[2], "unpartitioned relations have no parent")
+	assert.Equal(t, "-", finding.Table.Rows[1].Cells[5], "indexes have no insert counter")
+	assert.Contains(t, finding.Details, "2 relation(s)")
+	assert.Contains(t, finding.Details, "32.0TiB")
+}
+
+func TestRelationSizeLimit_ConfigThreshold(t *testing.T) {
+	t.Parallel()
+
+	cfg := check.Config{"relation-size-limit": {"warn_size_gb": "100"}}
+	checker := relationsizelimit.New(&mockQueryer{rows: []db.LargeRelationsRow{
+		relation("events", "table", 200*check.GiB),
+	}}, cfg)
+
+	report, err := checker.Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, check.SeverityWarn, report.Severity)
+}
+
+func TestRelationSizeLimit_InvalidConfigKeepsDefault(t *testing.T) {
+	t.Parallel()
+
+	cfg := check.Config{"relation-size-limit": {"warn_size_gb": "lots"}}
+	checker := relationsizelimit.New(&mockQueryer{rows: []db.LargeRelationsRow{
+		relation("events", "table", 200*check.GiB),
+	}}, cfg)
+
+	report, err := checker.Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, check.SeverityOK, report.Severity)
+}
+
+func TestRelationSizeLimit_QueryError(t *testing.T) {
+	t.Parallel()
+
+	checker := relationsizelimit.New(&mockQueryer{err: fmt.Errorf("connection refused")})
+	_, err := checker.Check(context.Background())
+	require.ErrorContains(t, err, "relation-size-limit")
+}
+
+func TestRelationSizeLimit_Metadata(t *testing.T) {
+	t.Parallel()
+
+	m := relationsizelimit.Metadata()
+	assert.Equal(t, "relation-size-limit", m.CheckID)
+	assert.Equal(t, "Relation Size Limit", m.Name)
+	assert.Equal(t, check.CategorySchema, m.Category)
+	assert.NotEmpty(t, m.Description)
+	assert.NotEmpty(t, m.SQL)
+	assert.NotEmpty(t, m.Readme)
+}
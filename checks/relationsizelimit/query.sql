@@ -0,0 +1,30 @@
+-- name: LargeRelations :many
+-- Largest tables, indexes, and TOAST relations by main-fork size, with the
+-- per-fork hard limit (2^32 - 1 blocks) for the server's block size.
+-- Candidates are pre-filtered on relpages so pg_relation_size() only stats
+-- the files of relations that are already big.
+SELECT
+  n.nspname::text AS schema_name
+  , c.relname::text AS relation_name
+  , CASE
+    WHEN c.relkind = 'i' THEN 'index'
+    WHEN c.relkind = 't' THEN 'toast'
+    WHEN c.relkind = 'm' THEN 'matview'
+    WHEN c.relispartition AND pg_get_expr(c.relpartbound, c.oid) = 'DEFAULT' THEN 'default partition'
+    WHEN c.relispartition THEN 'partition'
+    ELSE 'table'
+  END::text AS relation_kind
+  , coalesce(parent.oid::regclass::text, '')::text AS parent_name
+  , pg_relation_size(c.oid) AS size_bytes
+  , current_setting('block_size')::bigint * 4294967295 AS max_fork_bytes
+  , pg_stat_get_tuples_inserted(c.oid) AS rows_inserted
+FROM pg_class AS c
+INNER JOIN pg_namespace AS n ON c.relnamespace = n.oid
+LEFT JOIN pg_inherits AS inh ON c.relispartition AND c.oid = inh.inhrelid
+LEFT JOIN pg_class AS parent ON inh.inhparent = parent.oid
+WHERE
+  c.relkind IN ('r', 'i', 't', 'm')
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+  AND c.relpages::bigint * current_setting('block_size')::bigint >= 1073741824
+ORDER BY size_bytes DESC
+LIMIT 100;
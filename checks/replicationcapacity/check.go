@@ -0,0 +1,169 @@
+// Package replicationcapacity implements a check for room for another standby in the replication settings.
+package replicationcapacity
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strconv"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type ReplicationCapacityQueries interface {
+	ReplicationCapacity(context.Context) (db.ReplicationCapacityRow, error)
+}
+
+type checker struct {
+	queries        ReplicationCapacityQueries
+	senderHeadroom int32 // free WAL senders; default: 2
+	slotHeadroom   int32 // free replication slots; default: 1
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryConfigs,
+		CheckID:     "replication-capacity",
+		Name:        "Replication Capacity",
+		Description: "Checks that max_wal_senders, max_replication_slots, and wal_level leave room for another standby",
+		Readme:      readme,
+		SQL:         querySQL,
+		ClusterWide: true,
+	}
+}
+
+func New(queries ReplicationCapacityQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries:        queries,
+		senderHeadroom: 2,
+		slotHeadroom:   1,
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			parsePositive(myCfg, "wal_sender_headroom", &c.senderHeadroom)
+			parsePositive(myCfg, "slot_headroom", &c.slotHeadroom)
+		}
+	}
+	return c
+}
+
+// parsePositive sets *dst to the value of key, when it is a positive integer.
+func parsePositive(cfg map[string]string, key string, dst *int32) {
+	if v, ok := cfg[key]; ok {
+		if n, err := strconv.ParseInt(v, 10, 32); err == nil && n > 0 {
+			*dst = int32(n)
+		}
+	}
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	capacity, err := c.queries.ReplicationCapacity(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	rows := []*settingRow{
+		capacityRow("max_wal_senders", capacity.MaxWalSenders, capacity.WalSenders, c.senderHeadroom, "WAL sender"),
+		capacityRow("max_replication_slots", capacity.MaxReplicationSlots, capacity.Slots, c.slotHeadroom, "slot"),
+		walLevelRow(capacity.WalLevel, capacity.LogicalSlots),
+	}
+
+	severity := check.SeverityOK
+	var issues int
+	tableRows := make([]check.TableRow, 0, len(rows))
+	for _, r := range rows {
+		severity = max(severity, r.severity)
+		if r.severity != check.SeverityOK {
+			issues++
+		}
+		tableRows = append(tableRows, check.TableRow{
+			Cells:    []string{r.parameter, r.current, r.expected, r.status},
+			Severity: r.severity,
+		})
+	}
+
+	var details string
+	switch severity {
+	case check.SeverityOK:
+		details = fmt.Sprintf("Room for another standby: %d of %d WAL sender(s) and %d of %d replication slot(s) in use",
+			capacity.WalSenders, capacity.MaxWalSenders, capacity.Slots, capacity.MaxReplicationSlots)
+	case check.SeverityFail:
+		details = fmt.Sprintf("%d replication setting(s) need attention; a new standby, or a rebuilt one during failover, "+
+			"could not connect or create its slot. Changing these settings requires a restart",
+			issues)
+	default:
+		details = fmt.Sprintf("%d replication setting(s) leave less headroom than configured for another standby or a base backup. "+
+			"Changing these settings requires a restart",
+			issues)
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: severity,
+		Details:  details,
+		Table: &check.Table{
+			Headers: []string{"Parameter", "Current", "Expected", "Status"},
+			Rows:    tableRows,
+		},
+	})
+
+	return report, nil
+}
+
+// capacityRow rates a limit against its use: FAIL when none is free, since
+// the next standby would be refused, and WARN below headroom free.
+func capacityRow(parameter string, limit, used, headroom int32, unit string) *settingRow {
+	r := &settingRow{
+		parameter: parameter,
+		current:   strconv.Itoa(int(limit)),
+		expected:  fmt.Sprintf("≥ %d", used+headroom),
+		status:    fmt.Sprintf("%d in use", used),
+		severity:  check.SeverityOK,
+	}
+	switch free := limit - used; {
+	case free <= 0:
+		r.status, r.severity = fmt.Sprintf("No free %s (%d in use)", unit, used), check.SeverityFail
+	case free < headroom:
+		r.status, r.severity = fmt.Sprintf("%d free %s(s), %d in use", free, unit, used), check.SeverityWarn
+	}
+	return r
+}
+
+// walLevelRow requires replica for physical standbys, and logical once
+// logical slots exist. minimal writes too little WAL for any standby.
+func walLevelRow(level string, logicalSlots int32) *settingRow {
+	r := &settingRow{parameter: "wal_level", current: level, expected: "replica", status: "OK", severity: check.SeverityOK}
+	if logicalSlots > 0 {
+		r.expected = "logical"
+	}
+	switch {
+	case level == "minimal":
+		r.status, r.severity = "Too low for any standby", check.SeverityFail
+	case logicalSlots > 0 && level != "logical":
+		r.status, r.severity = fmt.Sprintf("Too low for %d logical slot(s)", logicalSlots), check.SeverityFail
+	}
+	return r
+}
+
+// settingRow is one row of the report table.
+type settingRow struct {
+	parameter string
+	current   string
+	expected  string
+	status    string
+	severity  check.Severity
+}
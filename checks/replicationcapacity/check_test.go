@@ -0,0 +1,115 @@
+package replicationcapacity_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/replicationcapacity"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeQueries struct {
+	capacity checktest.Result[db.ReplicationCapacityRow]
+}
+
+func (f fakeQueries) ReplicationCapacity(context.Context) (db.ReplicationCapacityRow, error) {
+	return f.capacity.Get()
+}
+
+func run(t *testing.T, row db.ReplicationCapacityRow, cfg ...check.Config) check.Finding {
+	t.Helper()
+	return checktest.OnlyFinding(t, checktest.Run(t, replicationcapacity.New(fakeQueries{capacity: checktest.Returns(row)}, cfg...)))
+}
+
+func healthy() db.ReplicationCapacityRow {
+	return db.ReplicationCapacityRow{MaxWalSenders: 10, MaxReplicationSlots: 10, WalLevel: "replica", WalSenders: 2, Slots: 2}
+}
+
+func TestReplicationCapacity_Metadata(t *testing.T) {
+	t.Parallel()
+	checktest.RequireMetadata(t, replicationcapacity.Metadata(), "replication-capacity", check.CategoryConfigs)
+}
+
+func TestReplicationCapacity_QueryError(t *testing.T) {
+	t.Parallel()
+	checktest.RequireError(t, replicationcapacity.New(fakeQueries{capacity: checktest.Fails[db.ReplicationCapacityRow](errors.New("boom"))}))
+}
+
+func TestReplicationCapacity_Healthy(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, healthy())
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "Room for another standby: 2 of 10 WAL sender(s) and 2 of 10 replication slot(s) in use", finding.Details)
+	assert.Equal(t, [][]string{
+		{"max_wal_senders", "10", "≥ 4", "2 in use"},
+		{"max_replication_slots", "10", "≥ 3", "2 in use"},
+		{"wal_level", "replica", "replica", "OK"},
+	}, checktest.Rows(finding))
+}
+
+func TestReplicationCapacity_Exhausted(t *testing.T) {
+	t.Parallel()
+
+	row := healthy()
+	row.MaxWalSenders, row.WalSenders = 3, 3
+	row.MaxReplicationSlots, row.Slots = 3, 2
+	finding := run(t, row)
+	assert.Equal(t, check.SeverityFail, finding.Severity)
+	assert.Equal(t, [][]string{
+		{"max_wal_senders", "3", "≥ 5", "No free WAL sender (3 in use)"},
+		{"max_replication_slots", "3", "≥ 3", "2 in use"},
+		{"wal_level", "replica", "replica", "OK"},
+	}, checktest.Rows(finding))
+	assert.Contains(t, finding.Details, "1 replication setting(s) need attention")
+}
+
+func TestReplicationCapacity_BelowHeadroom(t *testing.T) {
+	t.Parallel()
+
+	row := healthy()
+	row.MaxWalSenders = 3
+	finding := run(t, row)
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Equal(t, "1 free WAL sender(s), 2 in use", checktest.Rows(finding)[0][3])
+
+	// A smaller headroom accepts it.
+	finding = run(t, row, check.Config{"replication-capacity": {"wal_sender_headroom": "1"}})
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+
+	// Invalid values keep the default.
+	finding = run(t, row, check.Config{"replication-capacity": {"wal_sender_headroom": "-1"}})
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+}
+
+func TestReplicationCapacity_WalLevel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		level        string
+		logicalSlots int32
+		expected     []string
+		severity     check.Severity
+	}{
+		{name: "minimal", level: "minimal", expected: []string{"wal_level", "minimal", "replica", "Too low for any standby"}, severity: check.SeverityFail},
+		{name: "replica with logical slots", level: "replica", logicalSlots: 2, expected: []string{"wal_level", "replica", "logical", "Too low for 2 logical slot(s)"}, severity: check.SeverityFail},
+		{name: "logical with logical slots", level: "logical", logicalSlots: 2, expected: []string{"wal_level", "logical", "logical", "OK"}, severity: check.SeverityOK},
+		{name: "logical without logical slots", level: "logical", expected: []string{"wal_level", "logical", "replica", "OK"}, severity: check.SeverityOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			row := healthy()
+			row.WalLevel, row.LogicalSlots = tt.level, tt.logicalSlots
+			finding := run(t, row)
+			assert.Equal(t, tt.severity, finding.Severity)
+			assert.Equal(t, tt.expected, checktest.Rows(finding)[2])
+		})
+	}
+}
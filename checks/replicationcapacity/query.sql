@@ -0,0 +1,10 @@
+-- name: ReplicationCapacity :one
+-- Replication settings and how much of them is in use. pg_stat_replication
+-- lists every WAL sender, including base backups streaming WAL.
+SELECT
+  current_setting('max_wal_senders')::int AS max_wal_senders
+  , current_setting('max_replication_slots')::int AS max_replication_slots
+  , current_setting('wal_level')::text AS wal_level
+  , (SELECT count(*) FROM pg_stat_replication)::int AS wal_senders
+  , (SELECT count(*) FROM pg_replication_slots)::int AS slots
+  , (SELECT count(*) FROM pg_replication_slots WHERE slot_type = 'logical')::int AS logical_slots;
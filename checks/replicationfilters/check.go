@@ -0,0 +1,230 @@
+// Package replicationfilters implements a check for publication row filters and column lists that drop rows or break replication.
+package replicationfilters
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type ReplicationFiltersQueries interface {
+	ReplicationFiltersSupported(context.Context) (bool, error)
+	ReplicationFilters(context.Context) ([]db.ReplicationFiltersRow, error)
+}
+
+type checker struct {
+	queries ReplicationFiltersQueries
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryConfigs,
+		CheckID:     "replication-filters",
+		Name:        "Replication Filters",
+		Description: "Reviews publication row filters and column lists for ones that break updates and deletes, are ignored, or silently drop rows",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries ReplicationFiltersQueries, _ ...check.Config) check.Checker {
+	return &checker{
+		queries: queries,
+	}
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	supported, err := c.queries.ReplicationFiltersSupported(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+	if !supported {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityNotApplicable,
+			Details:  "Publication row filters and column lists require PostgreSQL 15+; before it, publications replicate every row and column",
+		})
+		return report, nil
+	}
+
+	rows, err := c.queries.ReplicationFilters(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	if len(rows) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  "No publication uses row filters or column lists",
+		})
+		return report, nil
+	}
+
+	severity := check.SeverityOK
+	var failing, warning int
+	var reason string
+	tableRows := make([]check.TableRow, 0, len(rows))
+	for _, row := range rows {
+		rowSeverity, issues := review(row)
+		switch rowSeverity {
+		case check.SeverityFail:
+			failing++
+		case check.SeverityWarn:
+			warning++
+		}
+		if rowSeverity > severity {
+			severity = rowSeverity
+			reason = fmt.Sprintf("%s/%s: %s → %s", row.PublicationName, row.TableName,
+				issues[0], strings.ToUpper(rowSeverity.String()))
+		}
+
+		status := "OK"
+		if len(issues) > 0 {
+			status = strings.Join(issues, "; ")
+		}
+		rowFilter := row.RowFilter
+		if rowFilter == "" {
+			rowFilter = "-"
+		}
+		columnList := "-"
+		if row.HasColumnList {
+			columnList = strings.Join(row.ColumnList, ", ")
+		}
+		tableRows = append(tableRows, check.TableRow{
+			Cells:    []string{row.PublicationName, row.TableName, rowFilter, columnList, status},
+			Severity: rowSeverity,
+		})
+	}
+
+	var details string
+	switch {
+	case severity == check.SeverityOK:
+		details = fmt.Sprintf("Found %d published table(s) with a row filter or column list; none breaks replication or is ignored", len(rows))
+	default:
+		var parts []string
+		if failing > 0 {
+			parts = append(parts, fmt.Sprintf("%d make UPDATE and DELETE fail on the publisher", failing))
+		}
+		if warning > 0 {
+			parts = append(parts, fmt.Sprintf("%d are ignored or skip rows with NULLs", warning))
+		}
+		details = fmt.Sprintf("Found %d published table(s) with a row filter or column list: %s",
+			len(rows), strings.Join(parts, "; "))
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: severity,
+		Details:  details,
+		Reason:   reason,
+		Table: &check.Table{
+			Headers:   []string{"Publication", "Table", "Row Filter", "Column List", "Status"},
+			Key:       []int{0, 1},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.SensitiveIdentifier, check.SensitiveQuery},
+			Rows:      tableRows,
+		},
+	})
+
+	return report, nil
+}
+
+// review returns the severity of one published table's filters and the issues
+// behind it, the most severe first.
+func review(row db.ReplicationFiltersRow) (check.Severity, []string) {
+	var fails, warns []string
+
+	// PostgreSQL rejects UPDATE and DELETE on a table whose publication
+	// cannot identify the old row to subscribers: a column list must include
+	// the replica identity, and a row filter may only read it.
+	if row.PublishesChanges {
+		switch {
+		case len(row.IdentityColumns) == 0:
+			fails = append(fails, fmt.Sprintf("No replica identity (%s); UPDATE and DELETE fail", row.ReplicaIdentity))
+		default:
+			if missing := without(row.IdentityColumns, row.ColumnList); row.HasColumnList && len(missing) > 0 {
+				fails = append(fails, fmt.Sprintf("Column list omits replica identity column(s) %s; UPDATE and DELETE fail",
+					strings.Join(missing, ", ")))
+			}
+			if outside := without(row.FilterColumns, row.IdentityColumns); len(outside) > 0 {
+				fails = append(fails, fmt.Sprintf("Row filter reads %s, outside the replica identity; UPDATE and DELETE fail",
+					strings.Join(outside, ", ")))
+			}
+		}
+	}
+
+	// With partitioned tables, publish_via_partition_root picks whose
+	// filters apply: the root's when on, each partition's when off.
+	switch {
+	case row.Partitioned && !row.ViaPartitionRoot:
+		warns = append(warns, filters(row)+" ignored: publish_via_partition_root is off, so each partition's own apply")
+	case row.AncestorPublished && row.ViaPartitionRoot:
+		warns = append(warns, filters(row)+" ignored: publish_via_partition_root is on, so the published root's apply")
+	}
+
+	// A filter that evaluates to NULL excludes the row, as false does.
+	if row.RowFilter != "" && len(row.NullableFilterColumns) > 0 && !handlesNull(row.RowFilter) {
+		warns = append(warns, fmt.Sprintf("Rows with %s NULL fail the filter and are not replicated",
+			strings.Join(row.NullableFilterColumns, " or ")))
+	}
+
+	switch {
+	case len(fails) > 0:
+		return check.SeverityFail, append(fails, warns...)
+	case len(warns) > 0:
+		return check.SeverityWarn, warns
+	default:
+		return check.SeverityOK, nil
+	}
+}
+
+// filters names what a publication filters a table by.
+func filters(row db.ReplicationFiltersRow) string {
+	switch {
+	case row.RowFilter != "" && row.HasColumnList:
+		return "Row filter and column list"
+	case row.RowFilter != "":
+		return "Row filter"
+	default:
+		return "Column list"
+	}
+}
+
+// handlesNull reports whether a row filter mentions NULL explicitly, as in
+// IS NULL or coalesce(), and so likely decides on purpose what happens to
+// rows with NULLs.
+func handlesNull(filter string) bool {
+	lower := strings.ToLower(filter)
+	return strings.Contains(lower, "null") || strings.Contains(lower, "coalesce(")
+}
+
+// without returns the names in names that are not in exclude.
+func without(names, exclude []string) []string {
+	var rest []string
+	for _, name := range names {
+		if !slices.Contains(exclude, name) {
+			rest = append(rest, name)
+		}
+	}
+	return rest
+}
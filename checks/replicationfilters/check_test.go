@@ -0,0 +1,177 @@
+package replicationfilters_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/replicationfilters"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeQueries struct {
+	supported checktest.Result[bool]
+	filters   checktest.Result[[]db.ReplicationFiltersRow]
+}
+
+func (f fakeQueries) ReplicationFiltersSupported(context.Context) (bool, error) {
+	return f.supported.Get()
+}
+
+func (f fakeQueries) ReplicationFilters(context.Context) ([]db.ReplicationFiltersRow, error) {
+	return f.filters.Get()
+}
+
+func filters(rows ...db.ReplicationFiltersRow) fakeQueries {
+	return fakeQueries{supported: checktest.Returns(true), filters: checktest.Returns(rows)}
+}
+
+func run(t *testing.T, queries fakeQueries) check.Finding {
+	t.Helper()
+	return checktest.OnlyFinding(t, checktest.Run(t, replicationfilters.New(queries)))
+}
+
+// orders is public.orders in publication cdc, which publishes every
+// operation, keyed by its primary key id and filtered on it.
+func orders() db.ReplicationFiltersRow {
+	return db.ReplicationFiltersRow{
+		PublicationName:  "cdc",
+		TableName:        "public.orders",
+		RowFilter:        "(id > 1000)",
+		ReplicaIdentity:  "default",
+		IdentityColumns:  []string{"id"},
+		FilterColumns:    []string{"id"},
+		PublishesChanges: true,
+	}
+}
+
+func TestReplicationFilters_Metadata(t *testing.T) {
+	t.Parallel()
+	checktest.RequireMetadata(t, replicationfilters.Metadata(), "replication-filters", check.CategoryConfigs)
+}
+
+func TestReplicationFilters_QueryErrors(t *testing.T) {
+	t.Parallel()
+
+	checktest.RequireError(t, replicationfilters.New(fakeQueries{supported: checktest.Fails[bool](errors.New("boom"))}))
+	checktest.RequireError(t, replicationfilters.New(fakeQueries{
+		supported: checktest.Returns(true),
+		filters:   checktest.Fails[[]db.ReplicationFiltersRow](errors.New("boom")),
+	}))
+}
+
+func TestReplicationFilters_BeforePG15(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, fakeQueries{supported: checktest.Returns(false)})
+	assert.Equal(t, check.SeverityNotApplicable, finding.Severity)
+	assert.Contains(t, finding.Details, "require PostgreSQL 15+")
+}
+
+func TestReplicationFilters_NoFilters(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, filters())
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "No publication uses row filters or column lists", finding.Details)
+}
+
+func TestReplicationFilters_FiltersOnReplicaIdentity(t *testing.T) {
+	t.Parallel()
+
+	listed := orders()
+	listed.TableName = "public.customers"
+	listed.RowFilter = ""
+	listed.FilterColumns = nil
+	listed.HasColumnList = true
+	listed.ColumnList = []string{"id", "email"}
+
+	finding := run(t, filters(orders(), listed))
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Contains(t, finding.Details, "Found 2 published table(s)")
+	assert.Empty(t, finding.Reason)
+	assert.Equal(t, [][]string{
+		{"cdc", "public.orders", "(id > 1000)", "-", "OK"},
+		{"cdc", "public.customers", "-", "id, email", "OK"},
+	}, checktest.Rows(finding))
+}
+
+func TestReplicationFilters_ReplicaIdentity(t *testing.T) {
+	t.Parallel()
+
+	columns := orders()
+	columns.RowFilter = ""
+	columns.FilterColumns = nil
+	columns.HasColumnList = true
+	columns.ColumnList = []string{"status", "total"}
+	filter := orders()
+	filter.TableName = "public.payments"
+	filter.RowFilter = "((status)::text = 'paid'::text)"
+	filter.FilterColumns = []string{"status"}
+	none := orders()
+	none.TableName = "public.events"
+	none.ReplicaIdentity = "nothing"
+	none.IdentityColumns = nil
+	insertsOnly := filter
+	insertsOnly.PublicationName = "feed"
+	insertsOnly.PublishesChanges = false
+
+	finding := run(t, filters(columns, filter, none, insertsOnly))
+	assert.Equal(t, check.SeverityFail, finding.Severity)
+	assert.Contains(t, finding.Details, "3 make UPDATE and DELETE fail on the publisher")
+	assert.Equal(t, "cdc/public.orders: Column list omits replica identity column(s) id; UPDATE and DELETE fail → FAIL", finding.Reason)
+	assert.Equal(t, [][]string{
+		{"cdc", "public.orders", "-", "status, total", "Column list omits replica identity column(s) id; UPDATE and DELETE fail"},
+		{"cdc", "public.payments", "((status)::text = 'paid'::text)", "-", "Row filter reads status, outside the replica identity; UPDATE and DELETE fail"},
+		{"cdc", "public.events", "(id > 1000)", "-", "No replica identity (nothing); UPDATE and DELETE fail"},
+		{"feed", "public.payments", "((status)::text = 'paid'::text)", "-", "OK"},
+	}, checktest.Rows(finding))
+}
+
+func TestReplicationFilters_Ignored(t *testing.T) {
+	t.Parallel()
+
+	root := orders()
+	root.Partitioned = true
+	partition := orders()
+	partition.TableName = "public.orders_2026"
+	partition.HasColumnList = true
+	partition.ColumnList = []string{"id", "total"}
+	partition.ViaPartitionRoot = true
+	partition.AncestorPublished = true
+
+	finding := run(t, filters(root, partition))
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Contains(t, finding.Details, "2 are ignored or skip rows with NULLs")
+	assert.Equal(t, [][]string{
+		{"cdc", "public.orders", "(id > 1000)", "-",
+			"Row filter ignored: publish_via_partition_root is off, so each partition's own apply"},
+		{"cdc", "public.orders_2026", "(id > 1000)", "id, total",
+			"Row filter and column list ignored: publish_via_partition_root is on, so the published root's apply"},
+	}, checktest.Rows(finding))
+}
+
+func TestReplicationFilters_NullableColumns(t *testing.T) {
+	t.Parallel()
+
+	region := orders()
+	region.ReplicaIdentity = "full"
+	region.IdentityColumns = []string{"id", "region"}
+	region.RowFilter = "(region = 'eu'::text)"
+	region.FilterColumns = []string{"region"}
+	region.NullableFilterColumns = []string{"region"}
+	handled := region
+	handled.TableName = "public.invoices"
+	handled.RowFilter = "((region = 'eu'::text) OR (region IS NULL))"
+
+	finding := run(t, filters(region, handled))
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Equal(t, "cdc/public.orders: Rows with region NULL fail the filter and are not replicated → WARN", finding.Reason)
+	assert.Equal(t, [][]string{
+		{"cdc", "public.orders", "(region = 'eu'::text)", "-", "Rows with region NULL fail the filter and are not replicated"},
+		{"cdc", "public.invoices", "((region = 'eu'::text) OR (region IS NULL))", "-", "OK"},
+	}, checktest.Rows(finding))
+}
@@ -0,0 +1,104 @@
+-- name: ReplicationFiltersSupported :one
+-- Publications take row filters and column lists, in pg_publication_rel's
+-- prqual and prattrs, from PostgreSQL 15.
+SELECT current_setting('server_version_num')::integer >= 150000 AS supported;
+
+-- name: ReplicationFilters :many
+-- Tables published with a row filter or a column list (PostgreSQL 15+), with
+-- the columns the filter reads, taken from the Vars in its parsed tree, and
+-- the table's replica identity columns: every column for REPLICA IDENTITY
+-- FULL, the index's for USING INDEX, the primary key's for DEFAULT, and none
+-- for NOTHING or a DEFAULT without a primary key.
+WITH filtered AS (
+  SELECT
+    pr.prpubid
+    , pr.prrelid
+    , pr.prqual
+    , pr.prattrs
+  FROM pg_publication_rel AS pr
+  WHERE pr.prqual IS NOT NULL OR pr.prattrs IS NOT NULL
+)
+
+, filter_columns AS (
+  SELECT DISTINCT
+    f.prpubid
+    , f.prrelid
+    , m[1]::smallint AS attnum
+  FROM filtered AS f
+  CROSS JOIN LATERAL regexp_matches(f.prqual::text, ':varattno (\d+)', 'g') AS m
+)
+
+, identity_columns AS (
+  SELECT
+    c.oid AS relid
+    , a.attnum
+  FROM pg_class AS c
+  INNER JOIN pg_attribute AS a ON a.attrelid = c.oid
+  WHERE
+    c.oid IN (SELECT f.prrelid FROM filtered AS f)
+    AND a.attnum > 0
+    AND NOT a.attisdropped
+    AND (
+      c.relreplident = 'f'
+      OR EXISTS (
+        SELECT 1
+        FROM pg_index AS i
+        WHERE
+          i.indrelid = c.oid
+          AND a.attnum = ANY(i.indkey)
+          AND (
+            (c.relreplident = 'd' AND i.indisprimary)
+            OR (c.relreplident = 'i' AND i.indisreplident)
+          )
+      )
+    )
+)
+
+SELECT
+  p.pubname::text AS publication_name
+  , (n.nspname || '.' || c.relname)::text AS table_name
+  , coalesce(pg_get_expr(f.prqual, f.prrelid), '')::text AS row_filter
+  , f.prattrs IS NOT NULL AS has_column_list
+  , coalesce((
+    SELECT array_agg(a.attname ORDER BY a.attnum)
+    FROM pg_attribute AS a
+    WHERE a.attrelid = f.prrelid AND a.attnum = ANY(f.prattrs)
+  ), '{}')::text[] AS column_list
+  , CASE c.relreplident
+    WHEN 'd' THEN 'default'
+    WHEN 'n' THEN 'nothing'
+    WHEN 'f' THEN 'full'
+    WHEN 'i' THEN 'index'
+  END::text AS replica_identity
+  , coalesce((
+    SELECT array_agg(a.attname ORDER BY a.attnum)
+    FROM identity_columns AS ic
+    INNER JOIN pg_attribute AS a ON a.attrelid = ic.relid AND a.attnum = ic.attnum
+    WHERE ic.relid = f.prrelid
+  ), '{}')::text[] AS identity_columns
+  , coalesce((
+    SELECT array_agg(a.attname ORDER BY a.attnum)
+    FROM filter_columns AS fc
+    INNER JOIN pg_attribute AS a ON a.attrelid = fc.prrelid AND a.attnum = fc.attnum
+    WHERE fc.prpubid = f.prpubid AND fc.prrelid = f.prrelid
+  ), '{}')::text[] AS filter_columns
+  , coalesce((
+    SELECT array_agg(a.attname ORDER BY a.attnum)
+    FROM filter_columns AS fc
+    INNER JOIN pg_attribute AS a ON a.attrelid = fc.prrelid AND a.attnum = fc.attnum
+    WHERE fc.prpubid = f.prpubid AND fc.prrelid = f.prrelid AND NOT a.attnotnull
+  ), '{}')::text[] AS nullable_filter_columns
+  , p.pubupdate OR p.pubdelete AS publishes_changes
+  , c.relkind = 'p' AS partitioned
+  , p.pubviaroot AS via_partition_root
+  , EXISTS (
+    SELECT 1
+    FROM pg_partition_ancestors(c.oid) AS pa
+    INNER JOIN pg_publication_rel AS ar ON ar.prrelid = pa.relid
+    WHERE pa.relid <> c.oid AND ar.prpubid = p.oid
+  ) AS ancestor_published
+FROM filtered AS f
+INNER JOIN pg_publication AS p ON p.oid = f.prpubid
+INNER JOIN pg_class AS c ON c.oid = f.prrelid
+INNER JOIN pg_namespace AS n ON n.oid = c.relnamespace
+ORDER BY p.pubname, n.nspname, c.relname;
@@ -62,6 +62,7 @@ func Metadata() check.Metadata {
 		Description: "Monitors active replication streams for lag issues",
 		Readme:      readme,
 		SQL:         querySQL,
+		ClusterWide: true,
 	}
 }
 
@@ -178,8 +179,10 @@ func checkPhysicalReplicationLag(rows []db.ReplicationLagRow, report *check.Repo
 		Severity: maxSeverity,
 		Details:  fmt.Sprintf("%d of %d physical replication stream(s) are lagging", len(laggingRows), len(rows)),
 		Table: &check.Table{
-			Headers: []string{"Application", "State", "Replay Lag", "Lag Bytes", "Slot"},
-			Rows:    tableRows,
+			Headers:   []string{"Application", "State", "Replay Lag", "Lag Bytes", "Slot"},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignLeft, check.AlignRight, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.NotSensitive, check.NotSensitive, check.NotSensitive, check.SensitiveIdentifier},
+			Rows:      tableRows,
 		},
 	})
 }
@@ -278,8 +281,10 @@ func checkLogicalReplicationLag(rows []db.ReplicationLagRow, report *check.Repor
 		Severity: maxSeverity,
 		Details:  fmt.Sprintf("%d of %d logical replication stream(s) are lagging", len(laggingRows), len(rows)),
 		Table: &check.Table{
-			Headers: []string{"Application", "State", "Replay Lag", "Lag Bytes", "Slot"},
-			Rows:    tableRows,
+			Headers:   []string{"Application", "State", "Replay Lag", "Lag Bytes", "Slot"},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignLeft, check.AlignRight, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.NotSensitive, check.NotSensitive, check.NotSensitive, check.SensitiveIdentifier},
+			Rows:      tableRows,
 		},
 	})
 }
@@ -327,8 +332,9 @@ func checkReplicationState(rows []db.ReplicationLagRow, report *check.Report) {
 		Severity: check.SeverityWarn,
 		Details:  fmt.Sprintf("%d of %d replication stream(s) are not in 'streaming' state", len(problematicRows), len(rows)),
 		Table: &check.Table{
-			Headers: []string{"Application", "Type", "State", "Slot"},
-			Rows:    tableRows,
+			Headers:   []string{"Application", "Type", "State", "Slot"},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.NotSensitive, check.NotSensitive, check.SensitiveIdentifier},
+			Rows:      tableRows,
 		},
 	})
 }
@@ -386,8 +392,9 @@ func checkWALRetention(rows []db.ReplicationLagRow, report *check.Report) {
 		Severity: maxSeverity,
 		Details:  fmt.Sprintf("%d of %d replication slot(s) have WAL retention issues", len(problematicRows), len(rows)),
 		Table: &check.Table{
-			Headers: []string{"Application", "Type", "Slot", "WAL Status"},
-			Rows:    tableRows,
+			Headers:   []string{"Application", "Type", "Slot", "WAL Status"},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.NotSensitive, check.SensitiveIdentifier},
+			Rows:      tableRows,
 		},
 	})
 }
@@ -39,6 +39,7 @@ func Metadata() check.Metadata {
 		Description: "Validates replication slot configuration and health status",
 		Readme:      readme,
 		SQL:         querySQL,
+		ClusterWide: true,
 	}
 }
 
@@ -150,10 +151,11 @@ func reportInvalidSlots(report *check.Report, slots []db.ReplicationSlotsRow) {
 	}
 
 	report.AddFinding(check.Finding{
-		ID:       "invalid-slots",
-		Name:     "Invalid Replication Slots",
-		Severity: check.SeverityFail,
-		Details:  fmt.Sprintf("Found %d invalid replication slot(s):\n%s", len(slots), strings.Join(lines, "\n")),
+		ID:                 "invalid-slots",
+		Name:               "Invalid Replication Slots",
+		Severity:           check.SeverityFail,
+		Details:            fmt.Sprintf("Found %d invalid replication slot(s):\n%s", len(slots), strings.Join(lines, "\n")),
+		DetailsSensitivity: check.SensitiveIdentifier,
 	})
 }
 
@@ -168,10 +170,11 @@ func reportLostWALSlots(report *check.Report, slots []db.ReplicationSlotsRow) {
 	}
 
 	report.AddFinding(check.Finding{
-		ID:       "lost-wal-slots",
-		Name:     "Slots with Lost WAL",
-		Severity: check.SeverityFail,
-		Details:  fmt.Sprintf("Found %d slot(s) with lost/unreserved WAL:\n%s", len(slots), strings.Join(lines, "\n")),
+		ID:                 "lost-wal-slots",
+		Name:               "Slots with Lost WAL",
+		Severity:           check.SeverityFail,
+		Details:            fmt.Sprintf("Found %d slot(s) with lost/unreserved WAL:\n%s", len(slots), strings.Join(lines, "\n")),
+		DetailsSensitivity: check.SensitiveIdentifier,
 	})
 }
 
@@ -186,10 +189,11 @@ func reportConflictingSlots(report *check.Report, slots []db.ReplicationSlotsRow
 	}
 
 	report.AddFinding(check.Finding{
-		ID:       "conflicting-slots",
-		Name:     "Conflicting Replication Slots",
-		Severity: check.SeverityWarn,
-		Details:  fmt.Sprintf("Found %d slot(s) in conflicting state:\n%s", len(slots), strings.Join(lines, "\n")),
+		ID:                 "conflicting-slots",
+		Name:               "Conflicting Replication Slots",
+		Severity:           check.SeverityWarn,
+		Details:            fmt.Sprintf("Found %d slot(s) in conflicting state:\n%s", len(slots), strings.Join(lines, "\n")),
+		DetailsSensitivity: check.SensitiveIdentifier,
 	})
 }
 
@@ -212,10 +216,11 @@ func reportInactiveSlots(report *check.Report, slots []db.ReplicationSlotsRow) {
 	}
 
 	report.AddFinding(check.Finding{
-		ID:       "inactive-slots",
-		Name:     "Inactive Replication Slots",
-		Severity: check.SeverityWarn,
-		Details:  fmt.Sprintf("Found %d inactive slot(s):\n%s\n\nInactive slots prevent WAL cleanup and can fill disk.", len(slots), strings.Join(lines, "\n")),
+		ID:                 "inactive-slots",
+		Name:               "Inactive Replication Slots",
+		Severity:           check.SeverityWarn,
+		Details:            fmt.Sprintf("Found %d inactive slot(s):\n%s\n\nInactive slots prevent WAL cleanup and can fill disk.", len(slots), strings.Join(lines, "\n")),
+		DetailsSensitivity: check.SensitiveIdentifier,
 	})
 }
 
@@ -230,10 +235,11 @@ func reportCriticalLagSlots(report *check.Report, slots []db.ReplicationSlotsRow
 	}
 
 	report.AddFinding(check.Finding{
-		ID:       "critical-lag",
-		Name:     "Critical Replication Lag",
-		Severity: check.SeverityFail,
-		Details:  fmt.Sprintf("Found %d slot(s) with critical lag (>= 5GB):\n%s\n\nConsumers are severely behind and may never catch up.", len(slots), strings.Join(lines, "\n")),
+		ID:                 "critical-lag",
+		Name:               "Critical Replication Lag",
+		Severity:           check.SeverityFail,
+		Details:            fmt.Sprintf("Found %d slot(s) with critical lag (>= 5GB):\n%s\n\nConsumers are severely behind and may never catch up.", len(slots), strings.Join(lines, "\n")),
+		DetailsSensitivity: check.SensitiveIdentifier,
 	})
 }
 
@@ -248,9 +254,10 @@ func reportHighLagSlots(report *check.Report, slots []db.ReplicationSlotsRow) {
 	}
 
 	report.AddFinding(check.Finding{
-		ID:       "high-lag",
-		Name:     "High Replication Lag",
-		Severity: check.SeverityWarn,
-		Details:  fmt.Sprintf("Found %d slot(s) with high lag (>= 1GB):\n%s\n\nConsumers are falling behind.", len(slots), strings.Join(lines, "\n")),
+		ID:                 "high-lag",
+		Name:               "High Replication Lag",
+		Severity:           check.SeverityWarn,
+		Details:            fmt.Sprintf("Found %d slot(s) with high lag (>= 1GB):\n%s\n\nConsumers are falling behind.", len(slots), strings.Join(lines, "\n")),
+		DetailsSensitivity: check.SensitiveIdentifier,
 	})
 }
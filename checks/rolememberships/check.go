@@ -0,0 +1,271 @@
+// Package rolememberships implements a check for redundant and unexpected role memberships.
+package rolememberships
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type RoleMembershipsQueries interface {
+	RoleMemberships(context.Context) ([]db.RoleMembershipsRow, error)
+}
+
+type checker struct {
+	queries  RoleMembershipsQueries
+	expected []expectedRule // default: none, so no membership is unexpected
+}
+
+// expectedRule is an intended membership, "member:role", where either side
+// may be "*".
+type expectedRule struct {
+	member string
+	role   string
+}
+
+func (r expectedRule) matches(m membership) bool {
+	return (r.member == "*" || r.member == m.member) && (r.role == "*" || r.role == m.role)
+}
+
+// membership is a member's direct membership in a role, with every grant of
+// it.
+type membership struct {
+	member   string
+	role     string
+	grantors []string
+	admin    bool
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategorySchema,
+		CheckID:     "role-memberships",
+		Name:        "Role Memberships",
+		Description: "Finds role memberships granted more than once or also inherited through another role, and memberships missing from an expected list",
+		Readme:      readme,
+		SQL:         querySQL,
+		ClusterWide: true,
+	}
+}
+
+func New(queries RoleMembershipsQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries: queries,
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			if v, ok := myCfg["expected"]; ok {
+				c.expected = parseExpected(v)
+			}
+		}
+	}
+	return c
+}
+
+// parseExpected reads comma-separated "member:role" entries, ignoring entries
+// without both sides.
+func parseExpected(value string) []expectedRule {
+	var rules []expectedRule
+	for _, entry := range strings.Split(value, ",") {
+		member, role, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		member, role = strings.TrimSpace(member), strings.TrimSpace(role)
+		if !ok || member == "" || role == "" {
+			continue
+		}
+		rules = append(rules, expectedRule{member: member, role: role})
+	}
+	return rules
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	rows, err := c.queries.RoleMemberships(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	memberships, graph := collect(rows)
+	c.checkRedundant(report, memberships, graph)
+	if len(c.expected) > 0 {
+		c.checkUnexpected(report, memberships)
+	}
+
+	return report, nil
+}
+
+// collect groups the grants of each membership, in query order, and builds
+// the graph of the roles each role is a direct member of. Memberships of the
+// predefined pg_* roles in each other are in the graph but not returned:
+// they come with the server.
+func collect(rows []db.RoleMembershipsRow) ([]*membership, map[string][]string) {
+	var memberships []*membership
+	index := map[[2]string]*membership{}
+	graph := map[string][]string{}
+	for _, row := range rows {
+		key := [2]string{row.MemberName, row.RoleName}
+		m, ok := index[key]
+		if !ok {
+			m = &membership{member: row.MemberName, role: row.RoleName}
+			index[key] = m
+			graph[row.MemberName] = append(graph[row.MemberName], row.RoleName)
+			if !strings.HasPrefix(row.MemberName, "pg_") {
+				memberships = append(memberships, m)
+			}
+		}
+		grantor := row.GrantorName
+		if grantor == "" {
+			grantor = "-"
+		}
+		m.grantors = append(m.grantors, grantor)
+		m.admin = m.admin || row.AdminOption
+	}
+	return memberships, graph
+}
+
+// otherPath returns the shortest chain of roles, after m.member, through
+// which m.member also belongs to m.role without its direct membership, or nil
+// when there is none.
+func otherPath(graph map[string][]string, m *membership) []string {
+	previous := map[string]string{m.member: ""}
+	queue := []string{m.member}
+	for len(queue) > 0 {
+		role := queue[0]
+		queue = queue[1:]
+		for _, next := range graph[role] {
+			if role == m.member && next == m.role {
+				continue
+			}
+			if _, seen := previous[next]; seen {
+				continue
+			}
+			previous[next] = role
+			if next == m.role {
+				var path []string
+				for r := next; r != m.member; r = previous[r] {
+					path = append([]string{r}, path...)
+				}
+				return path
+			}
+			queue = append(queue, next)
+		}
+	}
+	return nil
+}
+
+// checkRedundant warns on memberships granted more than once, or that the
+// member also has through another role it belongs to. Either way one grant
+// can be revoked without changing what the member can do, as long as the
+// roles on the way inherit.
+func (c *checker) checkRedundant(report *check.Report, memberships []*membership, graph map[string][]string) {
+	var tableRows []check.TableRow
+	for _, m := range memberships {
+		var reasons []string
+		if len(m.grantors) > 1 {
+			reasons = append(reasons, fmt.Sprintf("granted %d times", len(m.grantors)))
+		}
+		if path := otherPath(graph, m); path != nil {
+			reasons = append(reasons, "also via "+strings.Join(path, " → "))
+		}
+		if len(reasons) == 0 {
+			continue
+		}
+		tableRows = append(tableRows, check.TableRow{
+			Cells:    []string{m.member, m.role, strings.Join(m.grantors, ", "), strings.Join(reasons, "; ")},
+			Severity: check.SeverityWarn,
+		})
+	}
+
+	if len(tableRows) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       "redundant-membership",
+			Name:     "Redundant Memberships",
+			Severity: check.SeverityOK,
+			Details:  fmt.Sprintf("None of %d role membership(s) is granted twice or also inherited", len(memberships)),
+		})
+		return
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       "redundant-membership",
+		Name:     "Redundant Memberships",
+		Severity: check.SeverityWarn,
+		Details: fmt.Sprintf("%d role membership(s) are granted more than once or also inherited through another role. "+
+			"Revoking the extra grant keeps the access and leaves one place to change it",
+			len(tableRows)),
+		Table: &check.Table{
+			Headers: []string{"Member", "Role", "Grantor", "Reason"},
+			Key:     []int{0, 1},
+			Sensitive: []check.Sensitivity{
+				check.SensitiveIdentifier, check.SensitiveIdentifier, check.SensitiveIdentifier, check.SensitiveIdentifier,
+			},
+			Rows: tableRows,
+		},
+	})
+}
+
+// checkUnexpected warns on direct memberships that no expected entry allows.
+func (c *checker) checkUnexpected(report *check.Report, memberships []*membership) {
+	var tableRows []check.TableRow
+	for _, m := range memberships {
+		if c.isExpected(m) {
+			continue
+		}
+		admin := "-"
+		if m.admin {
+			admin = "yes"
+		}
+		tableRows = append(tableRows, check.TableRow{
+			Cells:    []string{m.member, m.role, strings.Join(m.grantors, ", "), admin},
+			Severity: check.SeverityWarn,
+		})
+	}
+
+	if len(tableRows) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       "unexpected-membership",
+			Name:     "Unexpected Memberships",
+			Severity: check.SeverityOK,
+			Details:  fmt.Sprintf("All %d role membership(s) are expected", len(memberships)),
+		})
+		return
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       "unexpected-membership",
+		Name:     "Unexpected Memberships",
+		Severity: check.SeverityWarn,
+		Details: fmt.Sprintf("%d role membership(s) are not in the expected list. "+
+			"Revoke them, or add the intended ones under expected",
+			len(tableRows)),
+		Table: &check.Table{
+			Headers:   []string{"Member", "Role", "Grantor", "Admin Option"},
+			Key:       []int{0, 1},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.SensitiveIdentifier, check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	})
+}
+
+func (c *checker) isExpected(m *membership) bool {
+	for _, rule := range c.expected {
+		if rule.matches(*m) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package rolememberships_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/rolememberships"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeQueries struct {
+	memberships checktest.Result[[]db.RoleMembershipsRow]
+}
+
+func (f fakeQueries) RoleMemberships(context.Context) ([]db.RoleMembershipsRow, error) {
+	return f.memberships.Get()
+}
+
+func newChecker(cfg check.Config, memberships ...db.RoleMembershipsRow) check.Checker {
+	return rolememberships.New(fakeQueries{memberships: checktest.Returns(memberships)}, cfg)
+}
+
+func grant(member, role, grantor string) db.RoleMembershipsRow {
+	return db.RoleMembershipsRow{MemberName: member, RoleName: role, GrantorName: grantor}
+}
+
+func TestRoleMemberships_Metadata(t *testing.T) {
+	t.Parallel()
+	m := rolememberships.Metadata()
+	checktest.RequireMetadata(t, m, "role-memberships", check.CategorySchema)
+	assert.True(t, m.ClusterWide)
+}
+
+func TestRoleMemberships_QueryError(t *testing.T) {
+	t.Parallel()
+	checktest.RequireError(t, rolememberships.New(fakeQueries{memberships: checktest.Fails[[]db.RoleMembershipsRow](errors.New("boom"))}))
+}
+
+func TestRoleMemberships_Clean(t *testing.T) {
+	t.Parallel()
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(nil,
+		grant("alice", "developers", "admin"),
+		grant("developers", "app_read", "admin"),
+	)))
+	assert.Equal(t, "redundant-membership", finding.ID)
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "None of 2 role membership(s) is granted twice or also inherited", finding.Details)
+}
+
+func TestRoleMemberships_Redundant(t *testing.T) {
+	t.Parallel()
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(nil,
+		grant("alice", "app_read", "admin"),
+		grant("alice", "app_read", "bob"),
+		grant("alice", "developers", "admin"),
+		grant("carol", "app_read", ""),
+		grant("carol", "pg_monitor", "admin"),
+		grant("carol", "pg_read_all_stats", "admin"),
+		grant("developers", "readers", "admin"),
+		grant("pg_monitor", "pg_read_all_stats", ""),
+		grant("readers", "app_read", "admin"),
+	)))
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Equal(t, [][]string{
+		{"alice", "app_read", "admin, bob", "granted 2 times; also via developers → readers → app_read"},
+		{"carol", "pg_read_all_stats", "admin", "also via pg_monitor → pg_read_all_stats"},
+	}, checktest.Rows(finding))
+	assert.NotContains(t, finding.Details, "alice", "role names stay in the table, where --redact strips them")
+	require.NotNil(t, finding.Table)
+	assert.Equal(t, check.SensitiveIdentifier, finding.Table.ColumnSensitivity(3))
+}
+
+func TestRoleMemberships_Unexpected(t *testing.T) {
+	t.Parallel()
+
+	cfg := check.Config{"role-memberships": {"expected": "app:app_write, app:app_read, *:pg_monitor, bogus"}}
+	report := checktest.Run(t, newChecker(cfg,
+		grant("alice", "app_write", "admin"),
+		grant("alice", "pg_monitor", "admin"),
+		grant("app", "app_read", "admin"),
+		grant("app", "app_write", "admin"),
+		db.RoleMembershipsRow{MemberName: "mallory", RoleName: "app_write", GrantorName: "alice", AdminOption: true},
+		grant("pg_monitor", "pg_read_all_stats", ""),
+	))
+	checktest.RequireSeverities(t, report, map[string]check.Severity{
+		"redundant-membership":  check.SeverityOK,
+		"unexpected-membership": check.SeverityWarn,
+	})
+	finding := checktest.Finding(t, report, "unexpected-membership")
+	assert.Equal(t, "2 role membership(s) are not in the expected list. Revoke them, or add the intended ones under expected", finding.Details)
+	assert.Equal(t, [][]string{
+		{"alice", "app_write", "admin", "-"},
+		{"mallory", "app_write", "alice", "yes"},
+	}, checktest.Rows(finding))
+}
+
+func TestRoleMemberships_AllExpected(t *testing.T) {
+	t.Parallel()
+
+	cfg := check.Config{"role-memberships": {"expected": "app:*"}}
+	report := checktest.Run(t, newChecker(cfg, grant("app", "app_read", "admin"), grant("app", "app_write", "admin")))
+	finding := checktest.Finding(t, report, "unexpected-membership")
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "All 2 role membership(s) are expected", finding.Details)
+}
@@ -0,0 +1,15 @@
+-- name: RoleMemberships :many
+-- Every direct role membership in the cluster, one row per grant. Since
+-- PostgreSQL 16 the same membership can be granted more than once, by
+-- different grantors. Memberships among the predefined pg_* roles are
+-- included so paths through them, such as pg_monitor, are followed.
+SELECT
+  m.rolname::text AS member_name
+  , r.rolname::text AS role_name
+  , coalesce(g.rolname, '')::text AS grantor_name
+  , am.admin_option
+FROM pg_auth_members AS am
+INNER JOIN pg_roles AS m ON m.oid = am.member
+INNER JOIN pg_roles AS r ON r.oid = am.roleid
+LEFT JOIN pg_roles AS g ON g.oid = am.grantor
+ORDER BY m.rolname, r.rolname, g.rolname;
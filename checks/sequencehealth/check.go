@@ -104,6 +104,8 @@ func checkNearExhaustion(rows []db.SequenceHealthRow, report *check.Report) {
 	}
 
 	headers := []string{"Sequence", "Table.Column", "Usage", "Remaining", "Type"}
+	sensitive := []check.Sensitivity{check.SensitiveIdentifier, check.SensitiveIdentifier}
+	align := []check.ColumnAlign{check.AlignLeft, check.AlignLeft, check.AlignRight, check.AlignRight}
 	var tableRows []check.TableRow
 
 	for _, row := range critical {
@@ -145,8 +147,10 @@ func checkNearExhaustion(rows []db.SequenceHealthRow, report *check.Report) {
 		Severity: severity,
 		Details:  details,
 		Table: &check.Table{
-			Headers: headers,
-			Rows:    tableRows,
+			Headers:   headers,
+			Align:     align,
+			Sensitive: sensitive,
+			Rows:      tableRows,
 		},
 	})
 }
@@ -171,6 +175,8 @@ func checkIntegerShouldBeBigint(rows []db.SequenceHealthRow, report *check.Repor
 	}
 
 	headers := []string{"Table", "Column", "Type", "Usage", "Current Value"}
+	sensitive := []check.Sensitivity{check.SensitiveIdentifier, check.SensitiveIdentifier}
+	align := []check.ColumnAlign{check.AlignLeft, check.AlignLeft, check.AlignLeft, check.AlignRight, check.AlignRight}
 	var tableRows []check.TableRow
 	severity := check.SeverityWarn
 
@@ -200,8 +206,11 @@ func checkIntegerShouldBeBigint(rows []db.SequenceHealthRow, report *check.Repor
 		Severity: severity,
 		Details:  fmt.Sprintf("Found %d integer column(s) with >50%% sequence usage that should be migrated to bigint", len(needsMigration)),
 		Table: &check.Table{
-			Headers: headers,
-			Rows:    tableRows,
+			Headers:   headers,
+			Key:       []int{0, 1},
+			Align:     align,
+			Sensitive: sensitive,
+			Rows:      tableRows,
 		},
 	})
 }
@@ -226,6 +235,8 @@ func checkSequenceTypeMismatch(rows []db.SequenceHealthRow, report *check.Report
 	}
 
 	headers := []string{"Sequence", "Table.Column", "Column Type", "Seq Max", "Column Max"}
+	sensitive := []check.Sensitivity{check.SensitiveIdentifier, check.SensitiveIdentifier}
+	align := []check.ColumnAlign{check.AlignLeft, check.AlignLeft, check.AlignLeft, check.AlignRight, check.AlignRight}
 	var tableRows []check.TableRow
 
 	for _, row := range mismatched {
@@ -249,8 +260,10 @@ func checkSequenceTypeMismatch(rows []db.SequenceHealthRow, report *check.Report
 		Severity: check.SeverityFail,
 		Details:  details,
 		Table: &check.Table{
-			Headers: headers,
-			Rows:    tableRows,
+			Headers:   headers,
+			Align:     align,
+			Sensitive: sensitive,
+			Rows:      tableRows,
 		},
 	})
 }
@@ -32,6 +32,7 @@ type settingCheck struct {
 	Expected  string
 	Status    string
 	Severity  check.Severity
+	Rule      string // the threshold a WARN or FAIL crossed, for Finding.Reason
 }
 
 type checker struct {
@@ -120,6 +121,7 @@ func (c *checker) Check(ctx context.Context) (*check.Report, error) {
 				Expected:  "Role exists",
 				Status:    "Role not found",
 				Severity:  check.SeverityWarn,
+				Rule:      "role not found → WARN",
 			})
 			continue
 		}
@@ -171,13 +173,28 @@ func (c *checker) Check(ctx context.Context) (*check.Report, error) {
 		}
 
 		result.Details = fmt.Sprintf("Found %d configuration issue(s)", len(tableRows))
+		result.Reason = reason(checks, overallSeverity)
 		result.Table = &check.Table{
-			Headers: []string{"Role", "Parameter", "Current", "Expected", "Status"},
-			Rows:    tableRows,
+			Headers:   []string{"Role", "Parameter", "Current", "Expected", "Status"},
+			Key:       []int{0, 1},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier},
+			Rows:      tableRows,
 		}
 	}
 
 	report.AddFinding(result)
+
+	// Servers before PG17 have no transaction_timeout at all. Say so once
+	// rather than passing the setting silently or failing every role.
+	if !dbSettings.hasSetting("transaction_timeout") {
+		report.AddFinding(check.Finding{
+			ID:       "transaction-timeout",
+			Name:     "Transaction Timeout",
+			Severity: check.SeverityNotApplicable,
+			Details:  "transaction_timeout requires PostgreSQL 17+",
+		})
+	}
+
 	return report, nil
 }
 
@@ -207,6 +224,7 @@ func (c *checker) checkUserTimeouts(s dbSessionSettings, user string) ([]setting
 			Parameter: "statement_timeout",
 			Current:   "0ms (disabled)",
 			Expected:  expectedTimeout,
+			Rule:      "statement_timeout 0ms (disabled) → FAIL",
 			Status:    "MUST be set",
 			Severity:  check.SeverityFail,
 		})
@@ -218,6 +236,7 @@ func (c *checker) checkUserTimeouts(s dbSessionSettings, user string) ([]setting
 			Expected:  expectedTimeout,
 			Status:    "Too high",
 			Severity:  check.SeverityFail,
+			Rule:      fmt.Sprintf("statement_timeout %dms > timeout_fail %dms → FAIL", stmtTimeout, c.timeoutFail),
 		})
 	} else if stmtTimeout > c.timeoutWarn {
 		checks = append(checks, settingCheck{
@@ -227,6 +246,7 @@ func (c *checker) checkUserTimeouts(s dbSessionSettings, user string) ([]setting
 			Expected:  expectedTimeout,
 			Status:    "High",
 			Severity:  check.SeverityWarn,
+			Rule:      fmt.Sprintf("statement_timeout %dms > timeout_warn %dms → WARN", stmtTimeout, c.timeoutWarn),
 		})
 	} else {
 		checks = append(checks, settingCheck{
@@ -248,6 +268,7 @@ func (c *checker) checkUserTimeouts(s dbSessionSettings, user string) ([]setting
 			Expected:  "60000ms",
 			Status:    "Disabled",
 			Severity:  check.SeverityWarn,
+			Rule:      "idle_in_transaction_session_timeout 0ms (disabled) → WARN",
 		})
 	} else {
 		checks = append(checks, settingCheck{
@@ -261,8 +282,8 @@ func (c *checker) checkUserTimeouts(s dbSessionSettings, user string) ([]setting
 	}
 
 	// Check transaction_timeout (PG17+). When the row is absent the server
-	// predates PG17 and lacks the setting entirely — skip it rather than
-	// false-FAILing every role.
+	// predates PG17 and lacks the setting entirely — Check reports it as not
+	// applicable rather than false-FAILing every role.
 	if txFound {
 		if txTimeout == 0 {
 			checks = append(checks, settingCheck{
@@ -270,6 +291,7 @@ func (c *checker) checkUserTimeouts(s dbSessionSettings, user string) ([]setting
 				Parameter: "transaction_timeout",
 				Current:   "0ms (disabled)",
 				Expected:  expectedTimeout,
+				Rule:      "transaction_timeout 0ms (disabled) → FAIL",
 				Status:    "MUST be set (PG17+)",
 				Severity:  check.SeverityFail,
 			})
@@ -281,6 +303,7 @@ func (c *checker) checkUserTimeouts(s dbSessionSettings, user string) ([]setting
 				Expected:  expectedTimeout,
 				Status:    "Too high",
 				Severity:  check.SeverityFail,
+				Rule:      fmt.Sprintf("transaction_timeout %dms > timeout_fail %dms → FAIL", txTimeout, c.timeoutFail),
 			})
 		} else if txTimeout > c.timeoutWarn {
 			checks = append(checks, settingCheck{
@@ -290,6 +313,7 @@ func (c *checker) checkUserTimeouts(s dbSessionSettings, user string) ([]setting
 				Expected:  expectedTimeout,
 				Status:    "High",
 				Severity:  check.SeverityWarn,
+				Rule:      fmt.Sprintf("transaction_timeout %dms > timeout_warn %dms → WARN", txTimeout, c.timeoutWarn),
 			})
 		} else {
 			checks = append(checks, settingCheck{
@@ -306,6 +330,10 @@ func (c *checker) checkUserTimeouts(s dbSessionSettings, user string) ([]setting
 	return checks, nil
 }
 
+// minLogDuration is the lowest log_min_duration_statement, in milliseconds,
+// that does not flood the logs.
+const minLogDuration = 500
+
 func checkLogStatements(s dbSessionSettings, user string) ([]settingCheck, error) {
 	var checks []settingCheck
 
@@ -322,8 +350,9 @@ func checkLogStatements(s dbSessionSettings, user string) ([]settingCheck, error
 			Expected:  "2000ms",
 			Status:    "Disabled",
 			Severity:  check.SeverityFail,
+			Rule:      "log_min_duration_statement -1 (disabled) → FAIL",
 		})
-	} else if minDuration < 500 {
+	} else if minDuration < minLogDuration {
 		checks = append(checks, settingCheck{
 			Role:      user,
 			Parameter: "log_min_duration",
@@ -331,6 +360,7 @@ func checkLogStatements(s dbSessionSettings, user string) ([]settingCheck, error
 			Expected:  "2000ms",
 			Status:    "Too low",
 			Severity:  check.SeverityFail,
+			Rule:      fmt.Sprintf("log_min_duration_statement %dms < min %dms → FAIL", minDuration, minLogDuration),
 		})
 	} else {
 		checks = append(checks, settingCheck{
@@ -374,6 +404,18 @@ func (s dbSessionSettings) hasRole(role string) bool {
 	return false
 }
 
+// hasSetting reports whether any role has a row for the setting. The query
+// crosses every role with pg_settings, so a setting the server does not know
+// has no row for any role.
+func (s dbSessionSettings) hasSetting(name string) bool {
+	for _, row := range s {
+		if row.SettingName.Valid && row.SettingName.String == name {
+			return true
+		}
+	}
+	return false
+}
+
 // fetch returns the millisecond value of a setting for a user.
 // found is false when no matching row with a valid value exists — for
 // version-gated settings (e.g. transaction_timeout on PG<17) the query emits no
@@ -394,3 +436,27 @@ func (s dbSessionSettings) fetch(user, name string) (value int64, found bool, er
 	}
 	return 0, false, nil
 }
+
+// reason is the rule of the first setting at the finding's severity, with the
+// role it applies to and how many other settings are flagged.
+func reason(checks []settingCheck, severity check.Severity) string {
+	var first *settingCheck
+	flagged := 0
+	for i := range checks {
+		if checks[i].Severity == check.SeverityOK {
+			continue
+		}
+		flagged++
+		if first == nil && checks[i].Severity == severity {
+			first = &checks[i]
+		}
+	}
+	if first == nil {
+		return ""
+	}
+	r := first.Role + ": " + first.Rule
+	if flagged > 1 {
+		r += fmt.Sprintf(" (+%d more)", flagged-1)
+	}
+	return r
+}
@@ -2,25 +2,27 @@ package sessionsettings_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
 	"github.com/emancu/pgdoctor/checks/sessionsettings"
 	"github.com/emancu/pgdoctor/db"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 )
 
-func newStaticSessionSettingsQueryer(rows []db.SessionSettingsRow) sessionSettingsQueryer {
-	return sessionSettingsQueryer(func() []db.SessionSettingsRow {
-		return rows
-	})
+type fakeQueries struct {
+	settings checktest.Result[[]db.SessionSettingsRow]
 }
 
-type sessionSettingsQueryer func() []db.SessionSettingsRow
+func (f fakeQueries) SessionSettings(context.Context) ([]db.SessionSettingsRow, error) {
+	return f.settings.Get()
+}
 
-func (f sessionSettingsQueryer) SessionSettings(context.Context) ([]db.SessionSettingsRow, error) {
-	return f(), nil
+func newFakeQueries(rows []db.SessionSettingsRow) fakeQueries {
+	return fakeQueries{settings: checktest.Returns(rows)}
 }
 
 func mapToSessionSettingsRows(settings map[string]map[string]string) []db.SessionSettingsRow {
@@ -189,14 +191,6 @@ func Test_SessionSettings(t *testing.T) {
 				{ID: "session-settings", Sev: check.SeverityOK},
 			},
 		},
-		{
-			// All roles lack the row ⇒ PG<17 ⇒ no transaction_timeout finding at all.
-			Name: "transaction_timeout absent for all roles (PG < 17) yields no finding",
-			Rows: removeFromAllRoles("transaction_timeout"),
-			Expect: []ExpectedResultCheck{
-				{ID: "session-settings", Sev: check.SeverityOK},
-			},
-		},
 		{
 			// PG17+ present with value 0 must still FAIL (regression guard).
 			Name: "transaction_timeout disabled for app_ro",
@@ -247,16 +241,11 @@ func Test_SessionSettings(t *testing.T) {
 		t.Run(tc.Name, func(t *testing.T) {
 			t.Parallel()
 
-			queryer := newStaticSessionSettingsQueryer(tc.Rows)
-
-			checker := sessionsettings.New(queryer)
-			report, err := checker.Check(context.Background())
-			require.NoError(t, err)
+			queryer := newFakeQueries(tc.Rows)
 
-			results := report.Results
-			require.Equal(t, 1, len(results), "Should have exactly 1 result")
+			report := checktest.Run(t, sessionsettings.New(queryer))
 
-			result := results[0]
+			result := checktest.OnlyFinding(t, report)
 			require.Equal(t, tc.Expect[0].ID, result.ID, "Result ID should match")
 			require.Equal(t, tc.Expect[0].Sev, result.Severity, "Result severity should match")
 
@@ -269,6 +258,29 @@ func Test_SessionSettings(t *testing.T) {
 	}
 }
 
+func Test_SessionSettings_TransactionTimeoutNotApplicable(t *testing.T) {
+	t.Parallel()
+
+	// All roles lack the row ⇒ PG<17 ⇒ transaction_timeout is reported once
+	// as not applicable, and does not change the check's severity.
+	report := checktest.Run(t, sessionsettings.New(newFakeQueries(removeFromAllRoles("transaction_timeout"))))
+
+	checktest.RequireSeverities(t, report, map[string]check.Severity{
+		"session-settings":    check.SeverityOK,
+		"transaction-timeout": check.SeverityNotApplicable,
+	})
+	require.Equal(t, check.SeverityOK, report.Severity)
+	require.Contains(t, checktest.Finding(t, report, "transaction-timeout").Details, "PostgreSQL 17+")
+
+	settings := optimalSessionSettings()
+	settings["app_ro"]["statement_timeout"] = "0"
+	for role := range settings {
+		delete(settings[role], "transaction_timeout")
+	}
+	report = checktest.Run(t, sessionsettings.New(newFakeQueries(mapToSessionSettingsRows(settings))))
+	require.Equal(t, check.SeverityFail, report.Severity, "other settings are still judged")
+}
+
 func Test_SessionSettings_MultipleIssues(t *testing.T) {
 	t.Parallel()
 
@@ -287,16 +299,11 @@ func Test_SessionSettings_MultipleIssues(t *testing.T) {
 		},
 	}
 
-	queryer := newStaticSessionSettingsQueryer(mapToSessionSettingsRows(settings))
-
-	checker := sessionsettings.New(queryer)
-	report, err := checker.Check(context.Background())
-	require.NoError(t, err)
+	queryer := newFakeQueries(mapToSessionSettingsRows(settings))
 
-	results := report.Results
-	require.Equal(t, 1, len(results), "Should have exactly 1 result")
+	report := checktest.Run(t, sessionsettings.New(queryer))
 
-	result := results[0]
+	result := checktest.OnlyFinding(t, report)
 	require.NotNil(t, result.Table, "Result should have a table")
 
 	// Should have multiple issues detected in the table
@@ -315,6 +322,9 @@ func Test_SessionSettings_MultipleIssues(t *testing.T) {
 	}
 	require.True(t, hasFail, "Should have at least one FAIL severity in table rows")
 	require.True(t, hasWarn, "Should have at least one WARN severity in table rows")
+
+	// The reason names the first FAIL rule and counts the other issues
+	require.Equal(t, "app_ro: statement_timeout 0ms (disabled) → FAIL (+6 more)", result.Reason)
 }
 
 func Test_SessionSettings_BothRolesCheckedEqually(t *testing.T) {
@@ -323,16 +333,11 @@ func Test_SessionSettings_BothRolesCheckedEqually(t *testing.T) {
 	// Both roles have the same bad configuration
 	settings := overrideBothRoles("statement_timeout", "0")
 
-	queryer := newStaticSessionSettingsQueryer(settings)
-
-	checker := sessionsettings.New(queryer)
-	report, err := checker.Check(context.Background())
-	require.NoError(t, err)
+	queryer := newFakeQueries(settings)
 
-	results := report.Results
-	require.Equal(t, 1, len(results), "Should have exactly 1 result")
+	report := checktest.Run(t, sessionsettings.New(queryer))
 
-	result := results[0]
+	result := checktest.OnlyFinding(t, report)
 	require.NotNil(t, result.Table, "Result should have a table")
 
 	// Count failures for each role in table rows
@@ -399,30 +404,15 @@ func Test_SessionSettings_SpecificDetailChecks(t *testing.T) {
 		t.Run(tc.Name, func(t *testing.T) {
 			t.Parallel()
 
-			queryer := newStaticSessionSettingsQueryer(tc.Rows)
-
-			checker := sessionsettings.New(queryer)
-			report, err := checker.Check(context.Background())
-			require.NoError(t, err)
+			queryer := newFakeQueries(tc.Rows)
 
-			results := report.Results
-			require.Equal(t, 1, len(results), "Should have exactly 1 result")
+			report := checktest.Run(t, sessionsettings.New(queryer))
 
-			result := results[0]
+			result := checktest.OnlyFinding(t, report)
 			require.NotNil(t, result.Table, "Result should have a table")
 			require.Greater(t, len(result.Table.Rows), 0, "Table should have rows")
 
-			// Find the row matching the expected parameter
-			var foundRow *check.TableRow
-			for _, row := range result.Table.Rows {
-				// Row.Cells = [Role, Parameter, Current, Expected, Status]
-				if len(row.Cells) >= 5 && row.Cells[1] == tc.ExpectedParameter {
-					foundRow = &row
-					break
-				}
-			}
-
-			require.NotNil(t, foundRow, "Should find row for parameter: %s", tc.ExpectedParameter)
+			foundRow := checktest.RowWhere(t, result, "Parameter", tc.ExpectedParameter)
 			require.Equal(t, tc.ExpectedCurrent, foundRow.Cells[2], "Current value should match")
 			require.Equal(t, tc.ExpectedStatus, foundRow.Cells[4], "Status should match")
 		})
@@ -432,16 +422,11 @@ func Test_SessionSettings_SpecificDetailChecks(t *testing.T) {
 func Test_SessionSettings_EmptyRoles(t *testing.T) {
 	t.Parallel()
 
-	queryer := newStaticSessionSettingsQueryer([]db.SessionSettingsRow{})
+	queryer := newFakeQueries([]db.SessionSettingsRow{})
 
-	checker := sessionsettings.New(queryer)
-	report, err := checker.Check(context.Background())
-	require.NoError(t, err)
+	report := checktest.Run(t, sessionsettings.New(queryer))
 
-	results := report.Results
-	require.Equal(t, 1, len(results), "Should have exactly 1 result")
-
-	result := results[0]
+	result := checktest.OnlyFinding(t, report)
 	require.Equal(t, check.SeverityOK, result.Severity, "Empty roles should be OK")
 	require.Equal(t, "No application roles found", result.Details)
 }
@@ -464,15 +449,12 @@ func Test_SessionSettings_ArbitraryRoleNames(t *testing.T) {
 		},
 	}
 
-	queryer := newStaticSessionSettingsQueryer(mapToSessionSettingsRows(settings))
+	queryer := newFakeQueries(mapToSessionSettingsRows(settings))
 
-	checker := sessionsettings.New(queryer)
-	report, err := checker.Check(context.Background())
-	require.NoError(t, err)
+	report := checktest.Run(t, sessionsettings.New(queryer))
 
-	results := report.Results
-	require.Equal(t, 1, len(results), "Should have exactly 1 result")
-	require.Equal(t, check.SeverityOK, results[0].Severity, "Arbitrary role names with optimal settings should be OK")
+	result := checktest.OnlyFinding(t, report)
+	require.Equal(t, check.SeverityOK, result.Severity, "Arbitrary role names with optimal settings should be OK")
 }
 
 func Test_SessionSettings_ConfiguredRoleMissing(t *testing.T) {
@@ -491,28 +473,16 @@ func Test_SessionSettings_ConfiguredRoleMissing(t *testing.T) {
 		"session-settings": {"roles": "api_user,nonexistent"},
 	}
 
-	queryer := newStaticSessionSettingsQueryer(mapToSessionSettingsRows(settings))
-
-	checker := sessionsettings.New(queryer, cfg)
-	report, err := checker.Check(context.Background())
-	require.NoError(t, err)
+	queryer := newFakeQueries(mapToSessionSettingsRows(settings))
 
-	results := report.Results
-	require.Equal(t, 1, len(results), "Should have exactly 1 result")
+	report := checktest.Run(t, sessionsettings.New(queryer, cfg))
 
-	result := results[0]
+	result := checktest.OnlyFinding(t, report)
 	require.Equal(t, check.SeverityWarn, result.Severity, "Missing configured role should warn")
 	require.NotNil(t, result.Table, "Result should have a table")
 
-	// Find the "Role not found" row
-	var foundRow *check.TableRow
-	for _, row := range result.Table.Rows {
-		if len(row.Cells) >= 5 && row.Cells[0] == "nonexistent" && row.Cells[4] == "Role not found" {
-			foundRow = &row
-			break
-		}
-	}
-	require.NotNil(t, foundRow, "Should find 'Role not found' row for nonexistent role")
+	row := checktest.RowWhere(t, result, "Role", "nonexistent")
+	require.Equal(t, "Role not found", row.Cells[4])
 }
 
 func Test_SessionSettings_CustomThresholds_Warn(t *testing.T) {
@@ -537,12 +507,10 @@ func Test_SessionSettings_CustomThresholds_Warn(t *testing.T) {
 		},
 	}
 
-	queryer := newStaticSessionSettingsQueryer(mapToSessionSettingsRows(settings))
-	checker := sessionsettings.New(queryer, cfg)
-	report, err := checker.Check(context.Background())
-	require.NoError(t, err)
+	queryer := newFakeQueries(mapToSessionSettingsRows(settings))
+	report := checktest.Run(t, sessionsettings.New(queryer, cfg))
 
-	result := report.Results[0]
+	result := checktest.OnlyFinding(t, report)
 	require.Equal(t, check.SeverityWarn, result.Severity, "3000ms should WARN when threshold is 2000")
 	require.NotNil(t, result.Table)
 
@@ -555,6 +523,7 @@ func Test_SessionSettings_CustomThresholds_Warn(t *testing.T) {
 		}
 	}
 	require.Equal(t, 2, warnCount, "Both statement_timeout and transaction_timeout should WARN")
+	require.Equal(t, "app_ro: statement_timeout 3000ms > timeout_warn 2000ms → WARN (+1 more)", result.Reason)
 }
 
 func Test_SessionSettings_CustomThresholds_Fail(t *testing.T) {
@@ -579,12 +548,10 @@ func Test_SessionSettings_CustomThresholds_Fail(t *testing.T) {
 		},
 	}
 
-	queryer := newStaticSessionSettingsQueryer(mapToSessionSettingsRows(settings))
-	checker := sessionsettings.New(queryer, cfg)
-	report, err := checker.Check(context.Background())
-	require.NoError(t, err)
+	queryer := newFakeQueries(mapToSessionSettingsRows(settings))
+	report := checktest.Run(t, sessionsettings.New(queryer, cfg))
 
-	result := report.Results[0]
+	result := checktest.OnlyFinding(t, report)
 	require.Equal(t, check.SeverityFail, result.Severity, "7000ms should FAIL when threshold is 5000")
 	require.NotNil(t, result.Table)
 
@@ -612,12 +579,10 @@ func Test_SessionSettings_DefaultThresholds(t *testing.T) {
 		},
 	}
 
-	queryer := newStaticSessionSettingsQueryer(mapToSessionSettingsRows(settings))
-	checker := sessionsettings.New(queryer)
-	report, err := checker.Check(context.Background())
-	require.NoError(t, err)
+	queryer := newFakeQueries(mapToSessionSettingsRows(settings))
+	report := checktest.Run(t, sessionsettings.New(queryer))
 
-	result := report.Results[0]
+	result := checktest.OnlyFinding(t, report)
 	require.Equal(t, check.SeverityWarn, result.Severity, "7000ms should WARN with default thresholds (5000/10000)")
 	require.NotNil(t, result.Table)
 
@@ -650,15 +615,25 @@ func Test_SessionSettings_ConfigOverridesDiscovery(t *testing.T) {
 		"session-settings": {"roles": "api_user"},
 	}
 
-	queryer := newStaticSessionSettingsQueryer(mapToSessionSettingsRows(settings))
+	queryer := newFakeQueries(mapToSessionSettingsRows(settings))
 
-	checker := sessionsettings.New(queryer, cfg)
-	report, err := checker.Check(context.Background())
-	require.NoError(t, err)
+	report := checktest.Run(t, sessionsettings.New(queryer, cfg))
 
-	results := report.Results
-	require.Equal(t, 1, len(results), "Should have exactly 1 result")
+	result := checktest.OnlyFinding(t, report)
 
 	// Only api_user is checked (which has good settings), worker_user is ignored
-	require.Equal(t, check.SeverityOK, results[0].Severity, "Should only check configured roles")
+	require.Equal(t, check.SeverityOK, result.Severity, "Should only check configured roles")
+}
+
+func Test_SessionSettings_QueryError(t *testing.T) {
+	t.Parallel()
+
+	queryer := fakeQueries{settings: checktest.Fails[[]db.SessionSettingsRow](fmt.Errorf("connection refused"))}
+	checktest.RequireError(t, sessionsettings.New(queryer))
+}
+
+func Test_SessionSettings_Metadata(t *testing.T) {
+	t.Parallel()
+
+	checktest.RequireMetadata(t, sessionsettings.Metadata(), "session-settings", check.CategoryConfigs)
 }
@@ -0,0 +1,124 @@
+// Package sparsecolumns implements a check for columns that are almost entirely NULL on large tables.
+package sparsecolumns
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strconv"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type SparseColumnsQueries interface {
+	SparseColumns(context.Context) ([]db.SparseColumnsRow, error)
+}
+
+type checker struct {
+	queries     SparseColumnsQueries
+	nullPercent float64 // default: 95
+	minRows     int64   // default: 100,000
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategorySchema,
+		CheckID:     "sparse-columns",
+		Name:        "Sparse Columns",
+		Description: "Flags columns that are almost entirely NULL on large tables, often a sign they belong in a side table",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries SparseColumnsQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries:     queries,
+		nullPercent: 95,
+		minRows:     100_000,
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			if v, ok := myCfg["null_percent"]; ok {
+				if n, err := strconv.ParseFloat(v, 64); err == nil && n > 0 && n <= 100 {
+					c.nullPercent = n
+				}
+			}
+			if v, ok := myCfg["min_rows"]; ok {
+				if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+					c.minRows = n
+				}
+			}
+		}
+	}
+	return c
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	columns, err := c.queries.SparseColumns(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	var tableRows []check.TableRow
+	tables := make(map[string]bool)
+	for _, col := range columns {
+		rows := check.Int8ToInt64(col.EstimatedRows)
+		nullPercent := col.NullFrac.Float64 * 100
+		if !col.NullFrac.Valid || rows < c.minRows || nullPercent < c.nullPercent {
+			continue
+		}
+		tables[col.TableName] = true
+		tableRows = append(tableRows, check.TableRow{
+			Cells: []string{
+				col.TableName,
+				col.ColumnName,
+				fmt.Sprintf("%.1f%%", nullPercent),
+				check.FormatNumber(rows),
+			},
+			Severity: check.SeverityWarn,
+		})
+	}
+
+	if len(tableRows) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details: fmt.Sprintf("No column is at least %.0f%% NULL on a table with %s+ rows",
+				c.nullPercent, check.FormatNumber(c.minRows)),
+		})
+		return report, nil
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: check.SeverityWarn,
+		Details: fmt.Sprintf("Found %d column(s) across %d table(s) that are at least %.0f%% NULL; "+
+			"consider moving them to a side table keyed by the primary key",
+			len(tableRows), len(tables), c.nullPercent),
+		Table: &check.Table{
+			Headers:   []string{"Table", "Column", "Null %", "Rows"},
+			Key:       []int{0, 1},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignLeft, check.AlignRight, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	})
+
+	return report, nil
+}
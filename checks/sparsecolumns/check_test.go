@@ -0,0 +1,133 @@
+package sparsecolumns_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/checks/sparsecolumns"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockQueryer struct {
+	columns    []db.SparseColumnsRow
+	columnsErr error
+}
+
+func (m *mockQueryer) SparseColumns(context.Context) ([]db.SparseColumnsRow, error) {
+	return m.columns, m.columnsErr
+}
+
+func column(table, name string, nullFrac float64, rows int64) db.SparseColumnsRow {
+	return db.SparseColumnsRow{
+		TableName:     table,
+		ColumnName:    name,
+		NullFrac:      pgtype.Float8{Float64: nullFrac, Valid: true},
+		EstimatedRows: pgtype.Int8{Int64: rows, Valid: true},
+	}
+}
+
+func TestSparseColumns_NoColumns(t *testing.T) {
+	t.Parallel()
+
+	report, err := sparsecolumns.New(&mockQueryer{}).Check(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, report.Results, 1)
+	assert.Equal(t, check.SeverityOK, report.Results[0].Severity)
+}
+
+func TestSparseColumns_Thresholds(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		row      db.SparseColumnsRow
+		expected check.Severity
+	}{
+		{"mostly null on large table", column("public.orders", "refund_reason", 0.99, 5_000_000), check.SeverityWarn},
+		{"exactly at threshold", column("public.orders", "refund_reason", 0.95, 100_000), check.SeverityWarn},
+		{"entirely null", column("public.orders", "legacy_code", 1, 1_000_000), check.SeverityWarn},
+		{"below null threshold", column("public.orders", "coupon", 0.80, 5_000_000), check.SeverityOK},
+		{"small table", column("public.settings", "override", 0.99, 500), check.SeverityOK},
+		{"never analyzed", column("public.orders", "refund_reason", 0.99, -1), check.SeverityOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			report, err := sparsecolumns.New(&mockQueryer{
+				columns: []db.SparseColumnsRow{tt.row},
+			}).Check(context.Background())
+			require.NoError(t, err)
+
+			require.Len(t, report.Results, 1)
+			assert.Equal(t, tt.expected, report.Results[0].Severity)
+		})
+	}
+}
+
+func TestSparseColumns_Table(t *testing.T) {
+	t.Parallel()
+
+	report, err := sparsecolumns.New(&mockQueryer{
+		columns: []db.SparseColumnsRow{
+			column("public.orders", "refund_reason", 0.987, 5_000_000),
+			column("public.orders", "gift_note", 0.96, 5_000_000),
+			column("public.orders", "notes", 0.5, 5_000_000),
+			column("public.users", "fax", 1, 200_000),
+		},
+	}).Check(context.Background())
+	require.NoError(t, err)
+
+	finding := report.Results[0]
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Contains(t, finding.Details, "3 column(s) across 2 table(s)")
+	require.NotNil(t, finding.Table)
+	require.Len(t, finding.Table.Rows, 3)
+	assert.Equal(t, []string{"public.orders", "refund_reason", "98.7%", check.FormatNumber(5_000_000)}, finding.Table.Rows[0].Cells)
+	assert.Equal(t, "100.0%", finding.Table.Rows[2].Cells[2])
+}
+
+func TestSparseColumns_Config(t *testing.T) {
+	t.Parallel()
+
+	columns := []db.SparseColumnsRow{column("public.orders", "coupon", 0.80, 50_000)}
+
+	report, err := sparsecolumns.New(&mockQueryer{columns: columns}).Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, check.SeverityOK, report.Results[0].Severity)
+
+	cfg := check.Config{"sparse-columns": {"null_percent": "75", "min_rows": "10000"}}
+	report, err = sparsecolumns.New(&mockQueryer{columns: columns}, cfg).Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, check.SeverityWarn, report.Results[0].Severity)
+
+	invalid := check.Config{"sparse-columns": {"null_percent": "150", "min_rows": "-1"}}
+	report, err = sparsecolumns.New(&mockQueryer{columns: columns}, invalid).Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, check.SeverityOK, report.Results[0].Severity)
+}
+
+func TestSparseColumns_QueryError(t *testing.T) {
+	t.Parallel()
+
+	_, err := sparsecolumns.New(&mockQueryer{columnsErr: fmt.Errorf("connection refused")}).Check(context.Background())
+	require.ErrorContains(t, err, "sparse-columns")
+}
+
+func TestSparseColumns_Metadata(t *testing.T) {
+	t.Parallel()
+
+	m := sparsecolumns.Metadata()
+	assert.Equal(t, "sparse-columns", m.CheckID)
+	assert.Equal(t, check.CategorySchema, m.Category)
+	assert.NotEmpty(t, m.Name)
+	assert.NotEmpty(t, m.Readme)
+	assert.NotEmpty(t, m.SQL)
+}
@@ -0,0 +1,18 @@
+-- name: SparseColumns :many
+-- Columns of user tables with at least some NULLs, per the planner statistics
+-- gathered by ANALYZE. The null fraction and row thresholds are applied by the
+-- check so they stay configurable.
+SELECT
+  (s.schemaname || '.' || s.tablename)::text AS table_name
+  , s.attname::text AS column_name
+  , s.null_frac::float8 AS null_frac
+  , c.reltuples::bigint AS estimated_rows
+FROM pg_catalog.pg_stats AS s
+INNER JOIN pg_catalog.pg_namespace AS n ON n.nspname = s.schemaname
+INNER JOIN pg_catalog.pg_class AS c ON c.relnamespace = n.oid AND c.relname = s.tablename
+WHERE
+  n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
+  AND c.relkind IN ('r', 'm')
+  AND NOT s.inherited
+  AND s.null_frac > 0
+ORDER BY c.reltuples DESC, s.null_frac DESC, table_name, column_name;
@@ -0,0 +1,193 @@
+// Package statementsreset implements a check for pg_stat_statements reset age and eviction.
+package statementsreset
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+const (
+	// Statistics younger than this cover too little of the workload's daily
+	// cycle to rank queries.
+	minResetAgeSeconds = 24 * 3600
+
+	// Tracking this close to pg_stat_statements.max means new statements soon
+	// evict the least-executed ones.
+	evictionWarnRatio = 0.90
+)
+
+type StatementsResetQueries interface {
+	HasPgStatStatements(context.Context) (bool, error)
+	StatementsSettings(context.Context) (db.StatementsSettingsRow, error)
+	StatementsTracked(context.Context) (int64, error)
+	StatementsInfo(context.Context) (db.StatementsInfoRow, error)
+}
+
+type checker struct {
+	queries StatementsResetQueries
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryConfigs,
+		CheckID:     "statements-reset",
+		Name:        "pg_stat_statements Freshness",
+		Description: "Reports when pg_stat_statements was last reset and whether statements are being evicted",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries StatementsResetQueries, _ ...check.Config) check.Checker {
+	return &checker{
+		queries: queries,
+	}
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	installed, err := c.queries.HasPgStatStatements(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+	if !installed {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  "pg_stat_statements is not installed in this database",
+		})
+		return report, nil
+	}
+
+	settings, err := c.queries.StatementsSettings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+	if !settings.MaxStatements.Valid {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityWarn,
+			Details:  "pg_stat_statements is installed but not loaded; add it to shared_preload_libraries and restart",
+		})
+		return report, nil
+	}
+
+	tracked, err := c.queries.StatementsTracked(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	// pg_stat_statements_info (PG 14+) carries the reset time and eviction count.
+	var info db.StatementsInfoRow
+	if settings.HasInfo {
+		if info, err = c.queries.StatementsInfo(ctx); err != nil {
+			return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+		}
+	}
+
+	checkResetAge(settings, info, report)
+	checkEviction(tracked, settings.MaxStatements.Int64, info, report)
+
+	return report, nil
+}
+
+func checkResetAge(settings db.StatementsSettingsRow, info db.StatementsInfoRow, report *check.Report) {
+	if !settings.HasInfo {
+		report.AddFinding(check.Finding{
+			ID:       "reset-age",
+			Name:     "Statistics Reset Age",
+			Severity: check.SeverityOK,
+			Details:  "Reset time is not recorded before PostgreSQL 14 (pg_stat_statements_info)",
+		})
+		return
+	}
+
+	age := check.Int8ToInt64(info.ResetAgeSeconds)
+	if age < minResetAgeSeconds {
+		report.AddFinding(check.Finding{
+			ID:       "reset-age",
+			Name:     "Statistics Reset Age",
+			Severity: check.SeverityWarn,
+			Details: fmt.Sprintf("pg_stat_statements was reset %s ago; less than a day of data can misrank top queries",
+				check.FormatDurationSec(age)),
+		})
+		return
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       "reset-age",
+		Name:     "Statistics Reset Age",
+		Severity: check.SeverityOK,
+		Details:  fmt.Sprintf("pg_stat_statements was reset %s ago", check.FormatDurationSec(age)),
+	})
+}
+
+func checkEviction(tracked, maxStatements int64, info db.StatementsInfoRow, report *check.Report) {
+	dealloc := check.Int8ToInt64(info.Dealloc)
+	usage := 0.0
+	if maxStatements > 0 {
+		usage = float64(tracked) / float64(maxStatements)
+	}
+
+	deallocCell := "-"
+	if info.Dealloc.Valid {
+		deallocCell = check.FormatNumber(dealloc)
+	}
+	table := &check.Table{
+		Headers: []string{"Tracked", "Max", "Usage", "Evictions"},
+		Align:   []check.ColumnAlign{check.AlignRight, check.AlignRight, check.AlignRight, check.AlignRight},
+		Rows: []check.TableRow{{
+			Cells: []string{
+				check.FormatNumber(tracked),
+				check.FormatNumber(maxStatements),
+				fmt.Sprintf("%.0f%%", usage*100),
+				deallocCell,
+			},
+			Severity: check.SeverityOK,
+		}},
+	}
+
+	if dealloc == 0 && usage < evictionWarnRatio {
+		report.AddFinding(check.Finding{
+			ID:       "eviction",
+			Name:     "Statement Eviction",
+			Severity: check.SeverityOK,
+			Details: fmt.Sprintf("Tracking %s of %s statements; no evictions",
+				check.FormatNumber(tracked), check.FormatNumber(maxStatements)),
+			Table: table,
+		})
+		return
+	}
+
+	table.Rows[0].Severity = check.SeverityWarn
+	details := fmt.Sprintf("Tracking %s of %s statements (%.0f%%); new statements will evict the least-executed ones",
+		check.FormatNumber(tracked), check.FormatNumber(maxStatements), usage*100)
+	if dealloc > 0 {
+		details = fmt.Sprintf("%s statement(s) were evicted since the last reset; their statistics are lost and totals undercount",
+			check.FormatNumber(dealloc))
+	}
+	report.AddFinding(check.Finding{
+		ID:       "eviction",
+		Name:     "Statement Eviction",
+		Severity: check.SeverityWarn,
+		Details:  details,
+		Table:    table,
+	})
+}
@@ -0,0 +1,168 @@
+package statementsreset_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/checks/statementsreset"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockQueryer struct {
+	installed   bool
+	settings    db.StatementsSettingsRow
+	tracked     int64
+	info        db.StatementsInfoRow
+	infoQueried bool
+	err         error
+}
+
+func (m *mockQueryer) HasPgStatStatements(context.Context) (bool, error) {
+	return m.installed, m.err
+}
+
+func (m *mockQueryer) StatementsSettings(context.Context) (db.StatementsSettingsRow, error) {
+	return m.settings, m.err
+}
+
+func (m *mockQueryer) StatementsTracked(context.Context) (int64, error) {
+	return m.tracked, m.err
+}
+
+func (m *mockQueryer) StatementsInfo(context.Context) (db.StatementsInfoRow, error) {
+	m.infoQueried = true
+	return m.info, m.err
+}
+
+func count(v int64) pgtype.Int8 {
+	return pgtype.Int8{Int64: v, Valid: true}
+}
+
+// loaded builds a PG 14+ scenario with a 5000-statement limit.
+func loaded(tracked, dealloc, resetAgeSeconds int64) *mockQueryer {
+	return &mockQueryer{
+		installed: true,
+		settings:  db.StatementsSettingsRow{MaxStatements: count(5000), HasInfo: true},
+		tracked:   tracked,
+		info:      db.StatementsInfoRow{Dealloc: count(dealloc), ResetAgeSeconds: count(resetAgeSeconds)},
+	}
+}
+
+func findingByID(t *testing.T, report *check.Report, id string) check.Finding {
+	t.Helper()
+
+	for _, f := range report.Results {
+		if f.ID == id {
+			return f
+		}
+	}
+	require.Failf(t, "finding not found", "no finding with ID %q", id)
+	return check.Finding{}
+}
+
+func TestStatementsReset_NotInstalled(t *testing.T) {
+	t.Parallel()
+
+	report, err := statementsreset.New(&mockQueryer{}).Check(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, check.SeverityOK, report.Severity)
+	require.Len(t, report.Results, 1)
+	assert.Contains(t, report.Results[0].Details, "not installed")
+}
+
+func TestStatementsReset_NotLoaded(t *testing.T) {
+	t.Parallel()
+
+	report, err := statementsreset.New(&mockQueryer{installed: true}).Check(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, check.SeverityWarn, report.Severity)
+	require.Len(t, report.Results, 1)
+	assert.Contains(t, report.Results[0].Details, "shared_preload_libraries")
+}
+
+func TestStatementsReset_Severity(t *testing.T) {
+	t.Parallel()
+
+	const day = 86400
+
+	tests := []struct {
+		name     string
+		queries  *mockQueryer
+		resetAge check.Severity
+		eviction check.Severity
+	}{
+		{"healthy", loaded(1200, 0, 30*day), check.SeverityOK, check.SeverityOK},
+		{"recent reset", loaded(1200, 0, 3600), check.SeverityWarn, check.SeverityOK},
+		{"near max", loaded(4600, 0, 30*day), check.SeverityOK, check.SeverityWarn},
+		{"evicted", loaded(5000, 312, 30*day), check.SeverityOK, check.SeverityWarn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			report, err := statementsreset.New(tt.queries).Check(context.Background())
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.resetAge, findingByID(t, report, "reset-age").Severity)
+			assert.Equal(t, tt.eviction, findingByID(t, report, "eviction").Severity)
+		})
+	}
+}
+
+func TestStatementsReset_EvictionTable(t *testing.T) {
+	t.Parallel()
+
+	report, err := statementsreset.New(loaded(5000, 312, 30*86400)).Check(context.Background())
+	require.NoError(t, err)
+
+	finding := findingByID(t, report, "eviction")
+	assert.Contains(t, finding.Details, "312 statement(s) were evicted")
+	require.NotNil(t, finding.Table)
+	assert.Equal(t, []string{"5.0K", "5.0K", "100%", "312"}, finding.Table.Rows[0].Cells)
+	assert.Equal(t, check.SeverityWarn, finding.Table.Rows[0].Severity)
+}
+
+func TestStatementsReset_BeforePG14(t *testing.T) {
+	t.Parallel()
+
+	queries := &mockQueryer{
+		installed: true,
+		settings:  db.StatementsSettingsRow{MaxStatements: count(5000)},
+		tracked:   4900,
+	}
+	report, err := statementsreset.New(queries).Check(context.Background())
+	require.NoError(t, err)
+
+	assert.False(t, queries.infoQueried, "pg_stat_statements_info does not exist")
+	assert.Contains(t, findingByID(t, report, "reset-age").Details, "PostgreSQL 14")
+	eviction := findingByID(t, report, "eviction")
+	assert.Equal(t, check.SeverityWarn, eviction.Severity, "usage alone still warns")
+	assert.Equal(t, "-", eviction.Table.Rows[0].Cells[3])
+}
+
+func TestStatementsReset_QueryError(t *testing.T) {
+	t.Parallel()
+
+	_, err := statementsreset.New(&mockQueryer{err: fmt.Errorf("connection refused")}).Check(context.Background())
+	require.ErrorContains(t, err, "statements-reset")
+}
+
+func TestStatementsReset_Metadata(t *testing.T) {
+	t.Parallel()
+
+	m := statementsreset.Metadata()
+	assert.Equal(t, "statements-reset", m.CheckID)
+	assert.Equal(t, "pg_stat_statements Freshness", m.Name)
+	assert.Equal(t, check.CategoryConfigs, m.Category)
+	assert.NotEmpty(t, m.Description)
+	assert.NotEmpty(t, m.SQL)
+	assert.NotEmpty(t, m.Readme)
+}
@@ -0,0 +1,20 @@
+-- name: StatementsSettings :one
+-- pg_stat_statements.max is only defined once the library is loaded via
+-- shared_preload_libraries. pg_stat_statements_info exists from PG 14
+-- (extension version 1.9).
+SELECT
+  current_setting('pg_stat_statements.max', true)::bigint AS max_statements
+  , to_regclass('pg_stat_statements_info') IS NOT NULL AS has_info;
+
+-- name: StatementsTracked :one
+-- Number of distinct statements currently tracked.
+SELECT count(*)::bigint AS tracked_statements
+FROM pg_stat_statements;
+
+-- name: StatementsInfo :one
+-- dealloc counts statements evicted because the hash table was full, since
+-- stats_reset.
+SELECT
+  dealloc
+  , EXTRACT(EPOCH FROM now() - stats_reset)::bigint AS reset_age_seconds
+FROM pg_stat_statements_info;
@@ -92,6 +92,8 @@ func checkHighChurnTables(rows []db.TableActivityRow, report *check.Report) {
 	}
 
 	headers := []string{"Schema", "Table", "Inserts", "Updates", "Deletes", "Total Writes", "Size"}
+	sensitive := []check.Sensitivity{check.SensitiveIdentifier, check.SensitiveIdentifier}
+	align := []check.ColumnAlign{check.AlignLeft, check.AlignLeft, check.AlignRight, check.AlignRight, check.AlignRight, check.AlignRight, check.AlignRight}
 	var tableRows []check.TableRow
 
 	for _, row := range highChurn {
@@ -116,8 +118,11 @@ func checkHighChurnTables(rows []db.TableActivityRow, report *check.Report) {
 		Severity: check.SeverityWarn,
 		Details:  fmt.Sprintf("Found %d table(s) with high write activity (>1M writes)", len(highChurn)),
 		Table: &check.Table{
-			Headers: headers,
-			Rows:    tableRows,
+			Headers:   headers,
+			Key:       []int{0, 1},
+			Align:     align,
+			Sensitive: sensitive,
+			Rows:      tableRows,
 		},
 	})
 }
@@ -156,6 +161,8 @@ func checkLowHOTRatio(rows []db.TableActivityRow, report *check.Report) {
 	}
 
 	headers := []string{"Schema", "Table", "HOT Ratio", "Updates", "HOT Updates", "Live Rows"}
+	sensitive := []check.Sensitivity{check.SensitiveIdentifier, check.SensitiveIdentifier}
+	align := []check.ColumnAlign{check.AlignLeft, check.AlignLeft, check.AlignRight, check.AlignRight, check.AlignRight, check.AlignRight}
 	var tableRows []check.TableRow
 
 	for _, row := range lowHOT {
@@ -179,8 +186,11 @@ func checkLowHOTRatio(rows []db.TableActivityRow, report *check.Report) {
 		Severity: check.SeverityWarn,
 		Details:  fmt.Sprintf("Found %d large table(s) with low HOT update ratio (<50%%)", len(lowHOT)),
 		Table: &check.Table{
-			Headers: headers,
-			Rows:    tableRows,
+			Headers:   headers,
+			Key:       []int{0, 1},
+			Align:     align,
+			Sensitive: sensitive,
+			Rows:      tableRows,
 		},
 	})
 }
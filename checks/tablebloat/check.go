@@ -104,6 +104,8 @@ func checkHighDeadTuples(rows []db.TableBloatRow, report *check.Report) {
 	}
 
 	headers := []string{"Table", "Dead %", "Dead Tuples", "Live Tuples", "Size"}
+	sensitive := []check.Sensitivity{check.SensitiveIdentifier}
+	align := []check.ColumnAlign{check.AlignLeft, check.AlignRight, check.AlignRight, check.AlignRight, check.AlignRight}
 	var tableRows []check.TableRow
 
 	for _, row := range critical {
@@ -138,8 +140,10 @@ func checkHighDeadTuples(rows []db.TableBloatRow, report *check.Report) {
 		Severity: check.SeverityWarn,
 		Details:  fmt.Sprintf("Found %d table(s) with high dead tuple percentage (>20%%)", len(critical)+len(warning)),
 		Table: &check.Table{
-			Headers: headers,
-			Rows:    tableRows,
+			Headers:   headers,
+			Align:     align,
+			Sensitive: sensitive,
+			Rows:      tableRows,
 		},
 	})
 }
@@ -188,6 +192,8 @@ func checkStaleVacuum(rows []db.TableBloatRow, report *check.Report) {
 	}
 
 	headers := []string{"Table", "Last Vacuum", "Dead Tuples", "Autovacuum Count"}
+	sensitive := []check.Sensitivity{check.SensitiveIdentifier}
+	align := []check.ColumnAlign{check.AlignLeft, check.AlignLeft, check.AlignRight, check.AlignRight}
 	var tableRows []check.TableRow
 
 	for _, row := range critical {
@@ -220,8 +226,10 @@ func checkStaleVacuum(rows []db.TableBloatRow, report *check.Report) {
 		Severity: check.SeverityWarn,
 		Details:  fmt.Sprintf("Found %d table(s) not vacuumed recently despite significant dead tuples", len(critical)+len(warning)),
 		Table: &check.Table{
-			Headers: headers,
-			Rows:    tableRows,
+			Headers:   headers,
+			Align:     align,
+			Sensitive: sensitive,
+			Rows:      tableRows,
 		},
 	})
 }
@@ -256,6 +264,8 @@ func checkLargeBloatedTables(rows []db.TableBloatRow, report *check.Report) {
 	}
 
 	headers := []string{"Table", "Size", "Dead %", "Wasted Space (est)"}
+	sensitive := []check.Sensitivity{check.SensitiveIdentifier}
+	align := []check.ColumnAlign{check.AlignLeft, check.AlignRight, check.AlignRight, check.AlignRight}
 	var tableRows []check.TableRow
 
 	for _, row := range critical {
@@ -292,8 +302,10 @@ func checkLargeBloatedTables(rows []db.TableBloatRow, report *check.Report) {
 		Severity: check.SeverityWarn,
 		Details:  fmt.Sprintf("Found %d large table(s) with significant bloat, wasting disk space", len(critical)+len(warning)),
 		Table: &check.Table{
-			Headers: headers,
-			Rows:    tableRows,
+			Headers:   headers,
+			Align:     align,
+			Sensitive: sensitive,
+			Rows:      tableRows,
 		},
 	})
 }
@@ -4,11 +4,14 @@ package tableseqscans
 import (
 	"context"
 	_ "embed"
+	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/emancu/pgdoctor/check"
 	"github.com/emancu/pgdoctor/db"
+	"github.com/jackc/pgx/v5"
 )
 
 //go:embed query.sql
@@ -24,12 +27,22 @@ const (
 	failRatioThreshold = 50.0
 )
 
+// placeholderPattern matches the $n parameters pg_stat_statements substitutes
+// for constants.
+var placeholderPattern = regexp.MustCompile(`\$\d+`)
+
 type TableSeqScansQueries interface {
 	HighSeqScanTables(context.Context) ([]db.HighSeqScanTablesRow, error)
+	HasPgStatStatements(context.Context) (bool, error)
+	SeqScanSampleStatement(context.Context, string) (db.SeqScanSampleStatementRow, error)
+	Explain(context.Context, string, bool) ([]string, error)
 }
 
 type checker struct {
 	queries TableSeqScansQueries
+	// explain adds the plan of a sample query for the top flagged table to
+	// the finding's Debug output. Off by default: it runs extra queries.
+	explain bool
 }
 
 func Metadata() check.Metadata {
@@ -43,10 +56,16 @@ func Metadata() check.Metadata {
 	}
 }
 
-func New(queries TableSeqScansQueries, _ ...check.Config) check.Checker {
-	return &checker{
+func New(queries TableSeqScansQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
 		queries: queries,
 	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			c.explain = myCfg["explain"] == "true"
+		}
+	}
+	return c
 }
 
 func (c *checker) Metadata() check.Metadata {
@@ -70,12 +89,21 @@ func (c *checker) Check(ctx context.Context) (*check.Report, error) {
 		return report, nil
 	}
 
-	checkHighSeqScans(rows, report)
+	top := checkHighSeqScans(rows, report)
+
+	// The top table belongs to the first finding: FAIL tables are listed
+	// before WARN ones.
+	if c.explain && top != "" {
+		report.Results[0].Debug = c.samplePlan(ctx, top)
+	}
 
 	return report, nil
 }
 
-func checkHighSeqScans(rows []db.HighSeqScanTablesRow, report *check.Report) {
+// checkHighSeqScans adds the findings and returns the most-scanned flagged
+// table, or "" when no table is flagged.
+func checkHighSeqScans(rows []db.HighSeqScanTablesRow, report *check.Report) string {
+	var top, topWarn string
 	var failTables []string
 	var warnTables []string
 	failCount := 0
@@ -97,12 +125,18 @@ func checkHighSeqScans(rows []db.HighSeqScanTablesRow, report *check.Report) {
 		sizeMB := float64(row.TableSizeBytes.Int64) / (1024 * 1024)
 
 		if row.EstimatedRows.Int64 >= failRowThreshold && ratio >= failRatioThreshold {
+			if failCount == 0 {
+				top = row.TableName.String
+			}
 			failCount++
 			if len(failTables) < 10 {
 				failTables = append(failTables, fmt.Sprintf("%s (seq: %d, idx: %d, ratio: %.1f, rows: %d, size: %.1f MB)",
 					row.TableName.String, row.SeqScan.Int64, row.IdxScan.Int64, ratio, row.EstimatedRows.Int64, sizeMB))
 			}
 		} else if row.EstimatedRows.Int64 >= warnRowThreshold && ratio >= warnRatioThreshold {
+			if warnCount == 0 {
+				topWarn = row.TableName.String
+			}
 			warnCount++
 			if len(warnTables) < 10 {
 				warnTables = append(warnTables, fmt.Sprintf("%s (seq: %d, idx: %d, ratio: %.1f, rows: %d, size: %.1f MB)",
@@ -152,4 +186,44 @@ func checkHighSeqScans(rows []db.HighSeqScanTablesRow, report *check.Report) {
 			Severity: check.SeverityOK,
 		})
 	}
+
+	if top == "" {
+		top = topWarn
+	}
+	return top
+}
+
+// samplePlan EXPLAINs the most expensive pg_stat_statements SELECT that
+// mentions table. It never fails the check: problems are reported in the
+// returned text instead.
+func (c *checker) samplePlan(ctx context.Context, table string) string {
+	installed, err := c.queries.HasPgStatStatements(ctx)
+	if err != nil {
+		return fmt.Sprintf("EXPLAIN sample unavailable: %v", err)
+	}
+	if !installed {
+		return "EXPLAIN sample unavailable: pg_stat_statements is not installed"
+	}
+
+	name := table[strings.LastIndex(table, ".")+1:]
+	stmt, err := c.queries.SeqScanSampleStatement(ctx, `\m`+regexp.QuoteMeta(name)+`\M`)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Sprintf("EXPLAIN sample unavailable: no SELECT in pg_stat_statements mentions %s", table)
+	}
+	if err != nil {
+		return fmt.Sprintf("EXPLAIN sample unavailable: %v", err)
+	}
+
+	generic := placeholderPattern.MatchString(stmt.Query.String)
+	plan, err := c.queries.Explain(ctx, stmt.Query.String, generic)
+	if err != nil {
+		if generic {
+			return fmt.Sprintf("EXPLAIN sample unavailable: %v (parameterized queries need EXPLAIN (GENERIC_PLAN), PostgreSQL 16+)", err)
+		}
+		return fmt.Sprintf("EXPLAIN sample unavailable: %v", err)
+	}
+
+	return fmt.Sprintf("EXPLAIN sample for %s (calls: %d, mean: %s):\n%s\n\n%s",
+		table, stmt.Calls.Int64, check.FormatDurationMs(stmt.MeanExecTime.Float64),
+		stmt.Query.String, strings.Join(plan, "\n"))
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/emancu/pgdoctor/check"
 	"github.com/emancu/pgdoctor/checks/tableseqscans"
 	"github.com/emancu/pgdoctor/db"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 )
@@ -20,6 +21,17 @@ const (
 type mockTableSeqScansQueryer struct {
 	rows []db.HighSeqScanTablesRow
 	err  error
+
+	hasStatements bool
+	statement     db.SeqScanSampleStatementRow
+	statementErr  error
+	plan          []string
+	explainErr    error
+
+	// Recorded arguments of the EXPLAIN-sample queries.
+	pattern   string
+	explained string
+	generic   bool
 }
 
 func (m *mockTableSeqScansQueryer) HighSeqScanTables(context.Context) ([]db.HighSeqScanTablesRow, error) {
@@ -29,6 +41,20 @@ func (m *mockTableSeqScansQueryer) HighSeqScanTables(context.Context) ([]db.High
 	return m.rows, nil
 }
 
+func (m *mockTableSeqScansQueryer) HasPgStatStatements(context.Context) (bool, error) {
+	return m.hasStatements, nil
+}
+
+func (m *mockTableSeqScansQueryer) SeqScanSampleStatement(_ context.Context, pattern string) (db.SeqScanSampleStatementRow, error) {
+	m.pattern = pattern
+	return m.statement, m.statementErr
+}
+
+func (m *mockTableSeqScansQueryer) Explain(_ context.Context, query string, generic bool) ([]string, error) {
+	m.explained, m.generic = query, generic
+	return m.plan, m.explainErr
+}
+
 func newMockQueryer(rows []db.HighSeqScanTablesRow) *mockTableSeqScansQueryer {
 	return &mockTableSeqScansQueryer{rows: rows}
 }
@@ -511,3 +537,83 @@ func Test_TableSeqScans_NoOKResultWhenIssuesFound(t *testing.T) {
 	require.Equal(t, "high-seq-scans", result.ID)
 	require.Equal(t, check.SeverityFail, result.Severity)
 }
+
+func explainQueryer() *mockTableSeqScansQueryer {
+	q := newMockQueryer([]db.HighSeqScanTablesRow{
+		{
+			TableName:      pgtype.Text{String: "public.orders", Valid: true},
+			SeqScan:        pgtype.Int8{Int64: 10000, Valid: true},
+			IdxScan:        pgtype.Int8{Int64: 100, Valid: true},
+			SeqToIdxRatio:  makeNumeric(100.0),
+			EstimatedRows:  pgtype.Int8{Int64: 75000, Valid: true},
+			TableSizeBytes: pgtype.Int8{Int64: 78643200, Valid: true},
+			IndexCount:     pgtype.Int8{Int64: 3, Valid: true},
+		},
+	})
+	q.hasStatements = true
+	q.statement = db.SeqScanSampleStatementRow{
+		Query:        pgtype.Text{String: "SELECT * FROM orders WHERE customer_id = $1", Valid: true},
+		Calls:        pgtype.Int8{Int64: 5000, Valid: true},
+		MeanExecTime: pgtype.Float8{Float64: 120, Valid: true},
+	}
+	q.plan = []string{"Seq Scan on orders  (cost=0.00..1693.00 rows=50 width=97)", "  Filter: (customer_id = $1)"}
+	return q
+}
+
+var explainConfig = check.Config{"table-seq-scans": {"explain": "true"}}
+
+func Test_TableSeqScans_ExplainDisabledByDefault(t *testing.T) {
+	t.Parallel()
+
+	queryer := explainQueryer()
+	report, err := tableseqscans.New(queryer).Check(context.Background())
+	require.NoError(t, err)
+
+	require.Empty(t, report.Results[0].Debug)
+	require.Empty(t, queryer.explained, "EXPLAIN must not run unless enabled")
+}
+
+func Test_TableSeqScans_ExplainSample(t *testing.T) {
+	t.Parallel()
+
+	queryer := explainQueryer()
+	report, err := tableseqscans.New(queryer, explainConfig).Check(context.Background())
+	require.NoError(t, err)
+
+	require.Equal(t, `\morders\M`, queryer.pattern, "statements are matched on the bare table name")
+	require.Equal(t, "SELECT * FROM orders WHERE customer_id = $1", queryer.explained)
+	require.True(t, queryer.generic, "normalized queries need a generic plan")
+
+	debug := report.Results[0].Debug
+	require.Contains(t, debug, "EXPLAIN sample for public.orders (calls: 5000")
+	require.Contains(t, debug, "SELECT * FROM orders WHERE customer_id = $1")
+	require.Contains(t, debug, "Seq Scan on orders")
+}
+
+func Test_TableSeqScans_ExplainUnavailable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mutate   func(*mockTableSeqScansQueryer)
+		expected string
+	}{
+		{"no pg_stat_statements", func(q *mockTableSeqScansQueryer) { q.hasStatements = false }, "pg_stat_statements is not installed"},
+		{"no matching statement", func(q *mockTableSeqScansQueryer) { q.statementErr = pgx.ErrNoRows }, "no SELECT in pg_stat_statements mentions public.orders"},
+		{"explain fails", func(q *mockTableSeqScansQueryer) { q.explainErr = fmt.Errorf("syntax error") }, "GENERIC_PLAN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			queryer := explainQueryer()
+			tt.mutate(queryer)
+			report, err := tableseqscans.New(queryer, explainConfig).Check(context.Background())
+			require.NoError(t, err, "a failed sample must not fail the check")
+
+			require.Equal(t, check.SeverityFail, report.Severity)
+			require.Contains(t, report.Results[0].Debug, tt.expected)
+		})
+	}
+}
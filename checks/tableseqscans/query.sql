@@ -32,3 +32,19 @@ WHERE
   AND coalesce(s.seq_scan, 0) > 100
 ORDER BY
   coalesce(s.seq_scan, 0) DESC;
+
+-- name: SeqScanSampleStatement :one
+-- The most time-consuming SELECT in pg_stat_statements whose text matches
+-- pattern (a word-bounded regular expression for a table name). Used as a
+-- representative query to EXPLAIN for a table with heavy sequential scans.
+SELECT
+  query::text AS query
+  , calls::bigint AS calls
+  , mean_exec_time::double precision AS mean_exec_time
+FROM pg_stat_statements
+WHERE
+  dbid = (SELECT oid FROM pg_database WHERE datname = current_database())
+  AND query ~* '^\s*select\M'
+  AND query ~* sqlc.arg(pattern)::text
+ORDER BY total_exec_time DESC
+LIMIT 1;
@@ -0,0 +1,204 @@
+// Package tablespaceplacement implements a check for tables and indexes stored outside the tablespace a configured policy expects.
+package tablespaceplacement
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type TablespacePlacementQueries interface {
+	TablespacePlacement(context.Context) ([]db.TablespacePlacementRow, error)
+}
+
+type checker struct {
+	queries TablespacePlacementQueries
+	tables  []placementRule // for tables and materialized views
+	indexes []placementRule
+}
+
+// placementRule is the tablespace expected for the relations of the tables
+// matching "schema.table", where either part may be "*".
+type placementRule struct {
+	pattern    string
+	schema     string
+	table      string
+	tablespace string
+}
+
+func (r placementRule) matches(row db.TablespacePlacementRow) bool {
+	return (r.schema == "*" || r.schema == row.SchemaName) &&
+		(r.table == "*" || r.table == row.TableName)
+}
+
+// specificity ranks matching rules: a named table beats a whole schema, which
+// beats any schema.
+func (r placementRule) specificity() int {
+	n := 0
+	if r.schema != "*" {
+		n += 2
+	}
+	if r.table != "*" {
+		n++
+	}
+	return n
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategorySchema,
+		CheckID:     "tablespace-placement",
+		Name:        "Tablespace Placement",
+		Description: "Flags tables and indexes stored outside the tablespace a configured placement policy expects, such as indexes left on pg_default after a restore",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries TablespacePlacementQueries, cfg ...check.Config) check.Checker {
+	c := &checker{queries: queries}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			if v, ok := myCfg["tables"]; ok {
+				c.tables = parseRules(v)
+			}
+			if v, ok := myCfg["indexes"]; ok {
+				c.indexes = parseRules(v)
+			}
+		}
+	}
+	return c
+}
+
+// parseRules reads comma-separated "schema.table=tablespace" entries. A bare
+// tablespace applies to every table, and an unqualified table is in the
+// public schema. Entries without a tablespace are ignored.
+func parseRules(value string) []placementRule {
+	var rules []placementRule
+	for _, entry := range strings.Split(value, ",") {
+		pattern, tablespace, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			pattern, tablespace = "*.*", pattern
+		}
+		pattern, tablespace = strings.TrimSpace(pattern), strings.TrimSpace(tablespace)
+		if pattern == "" || tablespace == "" {
+			continue
+		}
+		rule := placementRule{pattern: pattern, schema: "public", table: pattern, tablespace: tablespace}
+		if schema, table, ok := strings.Cut(pattern, "."); ok {
+			rule.schema, rule.table = schema, table
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// ruleFor returns the most specific rule matching row; the first listed wins
+// a tie.
+func ruleFor(rules []placementRule, row db.TablespacePlacementRow) (placementRule, bool) {
+	best, found := placementRule{}, false
+	for _, r := range rules {
+		if r.matches(row) && (!found || r.specificity() > best.specificity()) {
+			best, found = r, true
+		}
+	}
+	return best, found
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	if len(c.tables) == 0 && len(c.indexes) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  "No tablespace placement policy configured",
+		})
+		return report, nil
+	}
+
+	rows, err := c.queries.TablespacePlacement(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	var tableRows []check.TableRow
+	var misplacedBytes int64
+	var reason string
+	covered := 0
+	for _, row := range rows {
+		rules := c.tables
+		if row.ObjectKind == "index" {
+			rules = c.indexes
+		}
+		rule, ok := ruleFor(rules, row)
+		if !ok {
+			continue
+		}
+		covered++
+		if row.TablespaceName == rule.tablespace {
+			continue
+		}
+
+		object := row.SchemaName + "." + row.ObjectName
+		if reason == "" {
+			reason = fmt.Sprintf("%s: %s on %s, policy %s=%s → WARN", object, row.ObjectKind, row.TablespaceName, rule.pattern, rule.tablespace)
+		}
+		misplacedBytes += row.SizeBytes
+		tableRows = append(tableRows, check.TableRow{
+			Cells: []string{
+				object,
+				row.ObjectKind,
+				check.FormatBytes(row.SizeBytes),
+				row.TablespaceName,
+				rule.tablespace,
+				rule.pattern,
+			},
+			Severity: check.SeverityWarn,
+		})
+	}
+
+	if len(tableRows) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  fmt.Sprintf("All %d object(s) covered by the placement policy are on their expected tablespace", covered),
+		})
+		return report, nil
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: check.SeverityWarn,
+		Details: fmt.Sprintf("Found %d of %d object(s) covered by the placement policy on another tablespace than expected (%s); "+
+			"restores, migrations, and CREATE statements without TABLESPACE put objects on the database default",
+			len(tableRows), covered, check.FormatBytes(misplacedBytes)),
+		Reason: reason,
+		Table: &check.Table{
+			Headers:   []string{"Object", "Kind", "Size", "Tablespace", "Expected", "Rule"},
+			Key:       []int{0},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignLeft, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.NotSensitive, check.NotSensitive, check.NotSensitive, check.NotSensitive, check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	})
+
+	return report, nil
+}
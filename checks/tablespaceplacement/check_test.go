@@ -0,0 +1,112 @@
+package tablespaceplacement_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/tablespaceplacement"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeQueries struct {
+	objects checktest.Result[[]db.TablespacePlacementRow]
+}
+
+func (f fakeQueries) TablespacePlacement(context.Context) ([]db.TablespacePlacementRow, error) {
+	return f.objects.Get()
+}
+
+func run(t *testing.T, cfg map[string]string, objects ...db.TablespacePlacementRow) check.Finding {
+	t.Helper()
+	checker := tablespaceplacement.New(fakeQueries{objects: checktest.Returns(objects)}, check.Config{"tablespace-placement": cfg})
+	return checktest.OnlyFinding(t, checktest.Run(t, checker))
+}
+
+func table(schema, name, tablespace string) db.TablespacePlacementRow {
+	return db.TablespacePlacementRow{SchemaName: schema, TableName: name, ObjectName: name, ObjectKind: "table", TablespaceName: tablespace, SizeBytes: 8 * check.GiB}
+}
+
+func index(schema, tableName, name, tablespace string) db.TablespacePlacementRow {
+	return db.TablespacePlacementRow{SchemaName: schema, TableName: tableName, ObjectName: name, ObjectKind: "index", TablespaceName: tablespace, SizeBytes: check.GiB}
+}
+
+func TestTablespacePlacement_Metadata(t *testing.T) {
+	t.Parallel()
+	checktest.RequireMetadata(t, tablespaceplacement.Metadata(), "tablespace-placement", check.CategorySchema)
+}
+
+func TestTablespacePlacement_QueryError(t *testing.T) {
+	t.Parallel()
+	checker := tablespaceplacement.New(
+		fakeQueries{objects: checktest.Fails[[]db.TablespacePlacementRow](errors.New("boom"))},
+		check.Config{"tablespace-placement": {"indexes": "fast_ssd"}},
+	)
+	checktest.RequireError(t, checker)
+}
+
+func TestTablespacePlacement_NotConfigured(t *testing.T) {
+	t.Parallel()
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, tablespaceplacement.New(fakeQueries{})))
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "No tablespace placement policy configured", finding.Details)
+}
+
+func TestTablespacePlacement_InPlace(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, map[string]string{"indexes": "fast_ssd", "tables": "pg_default"},
+		table("public", "events", "pg_default"),
+		index("public", "events", "events_pkey", "fast_ssd"),
+	)
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "All 2 object(s) covered by the placement policy are on their expected tablespace", finding.Details)
+}
+
+func TestTablespacePlacement_Misplaced(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, map[string]string{"indexes": "fast_ssd", "tables": "pg_default, archive.*=slow_hdd, archive.recent=pg_default"},
+		table("public", "events", "pg_default"),
+		index("public", "events", "events_pkey", "pg_default"),
+		table("archive", "events_2024", "pg_default"),
+		table("archive", "recent", "pg_default"),
+	)
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Contains(t, finding.Details, "Found 2 of 4 object(s)")
+	assert.Contains(t, finding.Details, "(9.0GiB)")
+	assert.Equal(t, "public.events_pkey: index on pg_default, policy *.*=fast_ssd → WARN", finding.Reason)
+	assert.Equal(t, [][]string{
+		{"public.events_pkey", "index", "1.0GiB", "pg_default", "fast_ssd", "*.*"},
+		{"archive.events_2024", "table", "8.0GiB", "pg_default", "slow_hdd", "archive.*"},
+	}, checktest.Rows(finding))
+}
+
+func TestTablespacePlacement_IndexesMatchTheirTable(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, map[string]string{"indexes": "events=fast_ssd"},
+		table("public", "events", "pg_default"),
+		index("public", "events", "events_created_at_idx", "pg_default"),
+		index("public", "users", "users_pkey", "pg_default"),
+	)
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Equal(t, [][]string{
+		{"public.events_created_at_idx", "index", "1.0GiB", "pg_default", "fast_ssd", "events"},
+	}, checktest.Rows(finding), "only indexes of public.events are covered; tables have no rule")
+}
+
+func TestTablespacePlacement_InvalidEntries(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, map[string]string{"indexes": " , events=, =fast_ssd"},
+		index("public", "events", "events_pkey", "pg_default"),
+	)
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "No tablespace placement policy configured", finding.Details,
+		"Entries without a pattern or tablespace are ignored")
+}
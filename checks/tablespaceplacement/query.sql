@@ -0,0 +1,29 @@
+-- name: TablespacePlacement :many
+-- Tables, materialized views, and indexes of the current database with the
+-- tablespace they are stored in. reltablespace is 0 for the database's
+-- default tablespace, which is named here rather than left implicit. Indexes
+-- carry the table they belong to, which placement rules match on.
+SELECT
+  n.nspname::text AS schema_name
+  , coalesce(tbl.relname, c.relname)::text AS table_name
+  , c.relname::text AS object_name
+  , CASE c.relkind
+    WHEN 'i' THEN 'index'
+    WHEN 'm' THEN 'matview'
+    ELSE 'table'
+  END::text AS object_kind
+  , coalesce(ts.spcname, dts.spcname)::text AS tablespace_name
+  , c.relpages::bigint * current_setting('block_size')::bigint AS size_bytes
+FROM pg_catalog.pg_class AS c
+INNER JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+INNER JOIN pg_catalog.pg_database AS d ON d.datname = current_database()
+INNER JOIN pg_catalog.pg_tablespace AS dts ON dts.oid = d.dattablespace
+LEFT JOIN pg_catalog.pg_tablespace AS ts ON ts.oid = c.reltablespace
+LEFT JOIN pg_catalog.pg_index AS i ON i.indexrelid = c.oid
+LEFT JOIN pg_catalog.pg_class AS tbl ON tbl.oid = i.indrelid
+WHERE
+  c.relkind IN ('r', 'm', 'i')
+  AND c.relpersistence <> 't'
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+  AND n.nspname NOT LIKE 'pg\_toast%'
+ORDER BY size_bytes DESC, n.nspname, c.relname;
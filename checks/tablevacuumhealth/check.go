@@ -100,10 +100,11 @@ func checkAutovacuumDisabled(rows []db.TableVacuumHealthRow, report *check.Repor
 	}
 
 	report.AddFinding(check.Finding{
-		ID:       "autovacuum-disabled",
-		Name:     "Autovacuum Disabled Tables",
-		Severity: check.SeverityWarn,
-		Details:  fmt.Sprintf("Found %d table(s) with autovacuum disabled: %s", len(tableNames), strings.Join(tableNames, ", ")),
+		ID:                 "autovacuum-disabled",
+		Name:               "Autovacuum Disabled Tables",
+		Severity:           check.SeverityWarn,
+		Details:            fmt.Sprintf("Found %d table(s) with autovacuum disabled: %s", len(tableNames), strings.Join(tableNames, ", ")),
+		DetailsSensitivity: check.SensitiveIdentifier,
 	})
 }
 
@@ -154,8 +155,10 @@ func checkLargeTableDefaults(rows []db.TableVacuumHealthRow, report *check.Repor
 		Severity: check.SeverityWarn,
 		Details:  fmt.Sprintf("Found %d large table(s) using default autovacuum settings", len(tablesUsingDefaults)),
 		Table: &check.Table{
-			Headers: []string{"Table", "Rows", "Size", "Pending Work", "Last Autovacuum", "Vacuum Count"},
-			Rows:    tableRows,
+			Headers:   []string{"Table", "Rows", "Size", "Pending Work", "Last Autovacuum", "Vacuum Count"},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignRight, check.AlignRight, check.AlignRight, check.AlignLeft, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier},
+			Rows:      tableRows,
 		},
 	})
 }
@@ -229,8 +232,10 @@ func checkVacuumStale(rows []db.TableVacuumHealthRow, report *check.Report) {
 		Severity: check.SeverityWarn,
 		Details:  fmt.Sprintf("Found %d table(s) with stale vacuum or analyze activity", len(tableRows)),
 		Table: &check.Table{
-			Headers: []string{"Table", "Rows", "Size", "Pending Work", "Last Vacuum", "Last Analyze"},
-			Rows:    tableRows,
+			Headers:   []string{"Table", "Rows", "Size", "Pending Work", "Last Vacuum", "Last Analyze"},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignRight, check.AlignRight, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier},
+			Rows:      tableRows,
 		},
 	})
 }
@@ -283,8 +288,10 @@ func checkAnalyzeNeeded(rows []db.TableVacuumHealthRow, report *check.Report) {
 		Severity: check.SeverityWarn,
 		Details:  fmt.Sprintf("Found %d table(s) with stale statistics (many modifications since last ANALYZE)", len(needsAnalyze)),
 		Table: &check.Table{
-			Headers: []string{"Table", "Rows", "Mods Since Analyze", "Analyze Count", "Last Analyze"},
-			Rows:    tableRows,
+			Headers:   []string{"Table", "Rows", "Mods Since Analyze", "Analyze Count", "Last Analyze"},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignRight, check.AlignRight, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier},
+			Rows:      tableRows,
 		},
 	})
 }
@@ -0,0 +1,253 @@
+// Package temptablespace implements a check for temp files written to the data directory instead of a tablespace of their own.
+package temptablespace
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type TempTablespaceQueries interface {
+	TempTablespaceSettings(context.Context) (db.TempTablespaceSettingsRow, error)
+	TempTablespaceLocations(context.Context) ([]db.TempTablespaceLocationsRow, error)
+}
+
+type checker struct {
+	queries  TempTablespaceQueries
+	minRate  float64  // temp bytes per hour from which temp files in the data directory warn; default: 1GiB
+	expected []string // tablespaces a policy expects temp files in; default: none
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryConfigs,
+		CheckID:     "temp-tablespace",
+		Name:        "Temp Tablespace",
+		Description: "Checks that databases writing many temp files send them to a tablespace outside the data directory through temp_tablespaces",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries TempTablespaceQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries: queries,
+		minRate: check.GiB,
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			if v, ok := myCfg["min_temp_mb_per_hour"]; ok {
+				if f, err := strconv.ParseFloat(v, 64); err == nil && f > 0 {
+					c.minRate = f * check.MiB
+				}
+			}
+			if v, ok := myCfg["expected"]; ok {
+				c.expected = parseList(v)
+			}
+		}
+	}
+	return c
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	settings, err := c.queries.TempTablespaceSettings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+	tablespaces, err := c.queries.TempTablespaceLocations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	locations := make(map[string]string, len(tablespaces))
+	for _, ts := range tablespaces {
+		locations[ts.Name] = ts.Location
+	}
+
+	// PostgreSQL skips names in temp_tablespaces that are not tablespaces,
+	// and uses the database's default tablespace when none is left.
+	var used, missing, inDataDir []string
+	for _, name := range parseList(settings.TempTablespaces) {
+		location, ok := locations[name]
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		used = append(used, name)
+		if isDataDir(location) {
+			inDataDir = append(inDataDir, name)
+		}
+	}
+	if len(used) == 0 && isDataDir(settings.DatabaseTablespaceLocation) {
+		inDataDir = []string{settings.DatabaseTablespace}
+	}
+
+	// Rate temp usage as temp-usage does: since the reset, or over the
+	// server's uptime when the statistics were never reset.
+	rate := float64(settings.TempBytes) / check.StatsWindow(settings.SecondsSinceReset, settings.UptimeSeconds) * 3600
+	note := check.StatsWindowNote(settings.SecondsSinceReset)
+	heavy := rate >= c.minRate
+
+	current := settings.TempTablespaces
+	if current == "" {
+		current = "(empty)"
+	}
+	expected := "Outside the data directory"
+	if len(c.expected) > 0 {
+		expected = strings.Join(c.expected, ", ")
+	}
+	setting := &settingRow{parameter: "temp_tablespaces", current: current, expected: expected, severity: check.SeverityOK}
+
+	var notUsed []string
+	for _, name := range c.expected {
+		if !slices.Contains(used, name) {
+			notUsed = append(notUsed, name)
+		}
+	}
+	var reasons []string
+	if len(notUsed) > 0 {
+		setting.warn(fmt.Sprintf("Not using %s", strings.Join(notUsed, ", ")))
+		reasons = append(reasons, fmt.Sprintf("temp_tablespaces lacks %d expected tablespace(s) → WARN", len(notUsed)))
+	}
+	if len(missing) > 0 {
+		setting.warn(fmt.Sprintf("%s not found, ignored", strings.Join(missing, ", ")))
+		reasons = append(reasons, fmt.Sprintf("temp_tablespaces names %d missing tablespace(s) → WARN", len(missing)))
+	}
+	switch {
+	case len(inDataDir) > 0 && heavy:
+		setting.warn(fmt.Sprintf("Temp files go to the data directory (%s)", strings.Join(inDataDir, ", ")))
+		reasons = append(reasons, fmt.Sprintf("%s/h of temp files ≥ %s/h in the data directory → WARN",
+			check.FormatBytes(int64(rate)), check.FormatBytes(int64(c.minRate))))
+	case len(inDataDir) > 0 && setting.severity == check.SeverityOK:
+		setting.status = fmt.Sprintf("In the data directory (%s); temp usage is light", strings.Join(inDataDir, ", "))
+	case setting.severity == check.SeverityOK && len(used) > 0:
+		setting.status = "In " + strings.Join(used, ", ")
+	case setting.severity == check.SeverityOK:
+		setting.status = "In " + settings.DatabaseTablespace
+	}
+
+	rows := []*settingRow{setting}
+	if len(used) == 0 {
+		location := settings.DatabaseTablespaceLocation
+		if isDataDir(location) {
+			location = "data directory"
+		}
+		rows = append(rows, &settingRow{
+			parameter: "database tablespace",
+			current:   fmt.Sprintf("%s (%s)", settings.DatabaseTablespace, location),
+			expected:  "-",
+			status:    "Holds temp files while temp_tablespaces names none",
+			severity:  check.SeverityOK,
+		})
+	}
+	written := &settingRow{parameter: "temp_bytes", current: check.FormatBytes(int64(rate)) + "/h", expected: "-", severity: check.SeverityOK}
+	written.status = fmt.Sprintf("Light: below %s/h", check.FormatBytes(int64(c.minRate)))
+	if heavy {
+		written.status = fmt.Sprintf("Heavy: at least %s/h", check.FormatBytes(int64(c.minRate)))
+	}
+	if note != "" {
+		written.status += "; estimated"
+	}
+	rows = append(rows, written)
+
+	tableRows := make([]check.TableRow, 0, len(rows))
+	for _, r := range rows {
+		tableRows = append(tableRows, check.TableRow{
+			Cells:    []string{r.parameter, r.current, r.expected, r.status},
+			Severity: r.severity,
+		})
+	}
+
+	var details string
+	switch {
+	case setting.severity == check.SeverityOK && len(inDataDir) > 0:
+		details = "Temp files go to the data directory, but the database writes few of them"
+	case setting.severity == check.SeverityOK:
+		details = "Temp files go to a tablespace outside the data directory"
+	case len(inDataDir) > 0 && heavy:
+		details = fmt.Sprintf("The database writes %s of temp files per hour to the data directory, where a large sort or hash "+
+			"can fill the volume holding tables and WAL and stop the server. Create a tablespace on a separate volume "+
+			"and list it in temp_tablespaces", check.FormatBytes(int64(rate)))
+	default:
+		details = "temp_tablespaces does not send temp files where expected: " + setting.status
+	}
+	if note != "" {
+		details += ". The temp data rate is an estimate: " + note
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Tags:     []string{check.TagTempFiles},
+		Name:     report.Name,
+		Severity: setting.severity,
+		Details:  details,
+		Reason:   strings.Join(reasons, "; "),
+		Table: &check.Table{
+			Headers:   []string{"Parameter", "Current", "Expected", "Status"},
+			Rows:      tableRows,
+			KeepOrder: true,
+			// Tablespace names and locations show up in every column but
+			// the parameter; the reasons count them instead of naming them.
+			Sensitive: []check.Sensitivity{check.NotSensitive, check.SensitiveIdentifier, check.SensitiveIdentifier, check.SensitiveIdentifier},
+		},
+	})
+
+	return report, nil
+}
+
+// isDataDir reports whether a tablespace location is in the data directory:
+// empty for pg_default and pg_global, and relative for in-place tablespaces.
+func isDataDir(location string) bool {
+	return location == "" || strings.HasPrefix(location, "pg_tblspc/")
+}
+
+// parseList splits a comma-separated list of tablespace names, as
+// temp_tablespaces holds them, removing double quotes around quoted names.
+func parseList(value string) []string {
+	var names []string
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if len(entry) >= 2 && strings.HasPrefix(entry, `"`) && strings.HasSuffix(entry, `"`) {
+			entry = strings.ReplaceAll(entry[1:len(entry)-1], `""`, `"`)
+		}
+		if entry != "" {
+			names = append(names, entry)
+		}
+	}
+	return names
+}
+
+// settingRow is one row of the report table.
+type settingRow struct {
+	parameter string
+	current   string
+	expected  string
+	status    string
+	severity  check.Severity
+}
+
+// warn marks the row as a warning, joining status to those of earlier ones.
+func (r *settingRow) warn(status string) {
+	if r.severity == check.SeverityWarn {
+		status = r.status + "; " + status
+	}
+	r.status, r.severity = status, check.SeverityWarn
+}
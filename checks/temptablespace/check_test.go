@@ -0,0 +1,233 @@
+package temptablespace_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/temptablespace"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeQueries struct {
+	settings    checktest.Result[db.TempTablespaceSettingsRow]
+	tablespaces checktest.Result[[]db.TempTablespaceLocationsRow]
+}
+
+func (f fakeQueries) TempTablespaceSettings(context.Context) (db.TempTablespaceSettingsRow, error) {
+	return f.settings.Get()
+}
+
+func (f fakeQueries) TempTablespaceLocations(context.Context) ([]db.TempTablespaceLocationsRow, error) {
+	return f.tablespaces.Get()
+}
+
+// tablespaces are those of a cluster with a temp tablespace on its own
+// volume and an in-place one.
+var tablespaces = []db.TempTablespaceLocationsRow{
+	{Name: "in_place", Location: "pg_tblspc/16390"},
+	{Name: "pg_default"},
+	{Name: "pg_global"},
+	{Name: "temp_ssd", Location: "/mnt/pgtemp"},
+}
+
+// settings are those of a database on pg_default that has written 2GiB of
+// temp files per hour over a day.
+func settings(tempTablespaces string) db.TempTablespaceSettingsRow {
+	return db.TempTablespaceSettingsRow{
+		TempTablespaces:    tempTablespaces,
+		DatabaseTablespace: "pg_default",
+		TempBytes:          48 * check.GiB,
+		SecondsSinceReset:  pgtype.Int8{Int64: 24 * 3600, Valid: true},
+	}
+}
+
+func run(t *testing.T, cfg map[string]string, row db.TempTablespaceSettingsRow) check.Finding {
+	t.Helper()
+	queries := fakeQueries{
+		settings:    checktest.Returns(row),
+		tablespaces: checktest.Returns(tablespaces),
+	}
+	return checktest.OnlyFinding(t, checktest.Run(t, temptablespace.New(queries, check.Config{"temp-tablespace": cfg})))
+}
+
+func TestTempTablespace_Metadata(t *testing.T) {
+	t.Parallel()
+	checktest.RequireMetadata(t, temptablespace.Metadata(), "temp-tablespace", check.CategoryConfigs)
+}
+
+func TestTempTablespace_QueryError(t *testing.T) {
+	t.Parallel()
+
+	checktest.RequireError(t, temptablespace.New(fakeQueries{
+		settings: checktest.Fails[db.TempTablespaceSettingsRow](errors.New("boom")),
+	}))
+	checktest.RequireError(t, temptablespace.New(fakeQueries{
+		settings:    checktest.Returns(settings("")),
+		tablespaces: checktest.Fails[[]db.TempTablespaceLocationsRow](errors.New("boom")),
+	}))
+}
+
+func TestTempTablespace_Severity(t *testing.T) {
+	t.Parallel()
+
+	light := settings("")
+	light.TempBytes = 100 * check.MiB
+
+	// Ten minutes of counters are rated over an hour.
+	fresh := settings("")
+	fresh.TempBytes = 512 * check.MiB
+	fresh.SecondsSinceReset = pgtype.Int8{Int64: 600, Valid: true}
+
+	// Counters since an unknown time are rated over the server's uptime.
+	neverReset := settings("")
+	neverReset.SecondsSinceReset = pgtype.Int8{}
+	neverReset.UptimeSeconds = 96 * 3600
+
+	onTempVolume := settings("")
+	onTempVolume.DatabaseTablespace = "temp_ssd"
+	onTempVolume.DatabaseTablespaceLocation = "/mnt/pgtemp"
+
+	tests := []struct {
+		name     string
+		row      db.TempTablespaceSettingsRow
+		severity check.Severity
+		rows     [][]string
+	}{
+		{
+			name:     "empty with heavy temp usage",
+			row:      settings(""),
+			severity: check.SeverityWarn,
+			rows: [][]string{
+				{"temp_tablespaces", "(empty)", "Outside the data directory", "Temp files go to the data directory (pg_default)"},
+				{"database tablespace", "pg_default (data directory)", "-", "Holds temp files while temp_tablespaces names none"},
+				{"temp_bytes", "2.0GiB/h", "-", "Heavy: at least 1.0GiB/h"},
+			},
+		},
+		{
+			name:     "empty with light temp usage",
+			row:      light,
+			severity: check.SeverityOK,
+		},
+		{
+			name:     "empty with less than an hour of statistics",
+			row:      fresh,
+			severity: check.SeverityOK,
+			rows: [][]string{
+				{"temp_tablespaces", "(empty)", "Outside the data directory", "In the data directory (pg_default); temp usage is light"},
+				{"database tablespace", "pg_default (data directory)", "-", "Holds temp files while temp_tablespaces names none"},
+				{"temp_bytes", "512.0MiB/h", "-", "Light: below 1.0GiB/h; estimated"},
+			},
+		},
+		{
+			name:     "empty with statistics never reset",
+			row:      neverReset,
+			severity: check.SeverityOK,
+			rows: [][]string{
+				{"temp_tablespaces", "(empty)", "Outside the data directory", "In the data directory (pg_default); temp usage is light"},
+				{"database tablespace", "pg_default (data directory)", "-", "Holds temp files while temp_tablespaces names none"},
+				{"temp_bytes", "512.0MiB/h", "-", "Light: below 1.0GiB/h; estimated"},
+			},
+		},
+		{
+			name:     "empty on a database outside the data directory",
+			row:      onTempVolume,
+			severity: check.SeverityOK,
+		},
+		{
+			name:     "temp tablespace",
+			row:      settings("temp_ssd"),
+			severity: check.SeverityOK,
+			rows: [][]string{
+				{"temp_tablespaces", "temp_ssd", "Outside the data directory", "In temp_ssd"},
+				{"temp_bytes", "2.0GiB/h", "-", "Heavy: at least 1.0GiB/h"},
+			},
+		},
+		{
+			name:     "in-place tablespace",
+			row:      settings(`"in_place"`),
+			severity: check.SeverityWarn,
+		},
+		{
+			name:     "missing tablespace",
+			row:      settings("temp_hdd, temp_ssd"),
+			severity: check.SeverityWarn,
+			rows: [][]string{
+				{"temp_tablespaces", "temp_hdd, temp_ssd", "Outside the data directory", "temp_hdd not found, ignored"},
+				{"temp_bytes", "2.0GiB/h", "-", "Heavy: at least 1.0GiB/h"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			finding := run(t, nil, tt.row)
+			assert.Equal(t, tt.severity, finding.Severity)
+			if tt.rows != nil {
+				assert.Equal(t, tt.rows, checktest.Rows(finding))
+			}
+		})
+	}
+}
+
+func TestTempTablespace_EstimatedRate(t *testing.T) {
+	t.Parallel()
+
+	row := settings("")
+	row.SecondsSinceReset = pgtype.Int8{}
+	row.UptimeSeconds = 24 * 3600
+
+	finding := run(t, nil, row)
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Contains(t, finding.Details, "The temp data rate is an estimate: statistics were never reset")
+}
+
+func TestTempTablespace_Reason(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, nil, settings(""))
+	assert.Equal(t, "2.0GiB/h of temp files ≥ 1.0GiB/h in the data directory → WARN", finding.Reason)
+	assert.Contains(t, finding.Details, "writes 2.0GiB of temp files per hour to the data directory")
+}
+
+func TestTempTablespace_Redacted(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, map[string]string{"expected": "temp_ssd"}, settings("temp_hdd, in_place"))
+	assert.Equal(t, "temp_tablespaces lacks 1 expected tablespace(s) → WARN; temp_tablespaces names 1 missing tablespace(s) → WARN; "+
+		"2.0GiB/h of temp files ≥ 1.0GiB/h in the data directory → WARN", finding.Reason)
+
+	report := check.NewReport(temptablespace.Metadata())
+	report.AddFinding(finding)
+	redacted := report.Redact(check.RedactIdentifiers).Results[0]
+	for _, name := range []string{"temp_hdd", "temp_ssd", "in_place"} {
+		assert.NotContains(t, redacted.Details, name)
+		assert.NotContains(t, redacted.Reason, name)
+		for _, row := range checktest.Rows(redacted) {
+			for _, cell := range row {
+				assert.NotContains(t, cell, name)
+			}
+		}
+	}
+}
+
+func TestTempTablespace_Config(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, map[string]string{"min_temp_mb_per_hour": "4096"}, settings(""))
+	assert.Equal(t, check.SeverityOK, finding.Severity, "2GiB/h is light below 4GiB/h")
+
+	finding = run(t, map[string]string{"expected": "temp_ssd"}, settings("in_place"))
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Equal(t, []string{"temp_tablespaces", "in_place", "temp_ssd",
+		"Not using temp_ssd; Temp files go to the data directory (in_place)"}, checktest.Rows(finding)[0])
+
+	finding = run(t, map[string]string{"expected": "temp_ssd"}, settings("temp_ssd"))
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+}
@@ -0,0 +1,25 @@
+-- name: TempTablespaceSettings :one
+-- temp_tablespaces, the current database's default tablespace, where temp
+-- files go when it names none, and the temp bytes the database wrote since
+-- its statistics were reset, with the seconds since then, NULL if never, and
+-- the server's uptime.
+SELECT
+  current_setting('temp_tablespaces')::text AS temp_tablespaces
+  , ts.spcname::text AS database_tablespace
+  , pg_catalog.pg_tablespace_location(ts.oid)::text AS database_tablespace_location
+  , s.temp_bytes::bigint AS temp_bytes
+  , EXTRACT(EPOCH FROM (NOW() - s.stats_reset))::bigint AS seconds_since_reset
+  , EXTRACT(EPOCH FROM (NOW() - pg_postmaster_start_time()))::bigint AS uptime_seconds
+FROM pg_catalog.pg_database AS d
+INNER JOIN pg_catalog.pg_tablespace AS ts ON ts.oid = d.dattablespace
+INNER JOIN pg_catalog.pg_stat_database AS s ON s.datid = d.oid
+WHERE d.datname = current_database();
+
+-- name: TempTablespaceLocations :many
+-- Every tablespace with its directory: empty for pg_default and pg_global,
+-- which live in the data directory, and relative for in-place tablespaces.
+SELECT
+  spcname::text AS name
+  , pg_catalog.pg_tablespace_location(oid)::text AS location
+FROM pg_catalog.pg_tablespace
+ORDER BY spcname;
@@ -5,6 +5,7 @@ import (
 	"context"
 	_ "embed"
 	"fmt"
+	"strconv"
 
 	"github.com/emancu/pgdoctor/check"
 	"github.com/emancu/pgdoctor/db"
@@ -22,7 +23,11 @@ type TempUsageQueries interface {
 }
 
 type checker struct {
-	queries TempUsageQueries
+	queries    TempUsageQueries
+	filesWarn  float64 // temp files per hour; default: 5
+	filesFail  float64 // default: 20
+	volumeWarn float64 // temp bytes per hour; default: 1GiB
+	volumeFail float64 // default: 5GiB
 }
 
 func Metadata() check.Metadata {
@@ -36,9 +41,32 @@ func Metadata() check.Metadata {
 	}
 }
 
-func New(queries TempUsageQueries, _ ...check.Config) check.Checker {
-	return &checker{
-		queries: queries,
+func New(queries TempUsageQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries:    queries,
+		filesWarn:  5,
+		filesFail:  20,
+		volumeWarn: check.GiB,
+		volumeFail: 5 * check.GiB,
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			parsePositive(myCfg, "files_per_hour_warn", 1, &c.filesWarn)
+			parsePositive(myCfg, "files_per_hour_fail", 1, &c.filesFail)
+			parsePositive(myCfg, "volume_mb_per_hour_warn", check.MiB, &c.volumeWarn)
+			parsePositive(myCfg, "volume_mb_per_hour_fail", check.MiB, &c.volumeFail)
+		}
+	}
+	return c
+}
+
+// parsePositive sets *dst to the value of key times unit, when it is a
+// positive number.
+func parsePositive(cfg map[string]string, key string, unit float64, dst *float64) {
+	if v, ok := cfg[key]; ok {
+		if f, err := strconv.ParseFloat(v, 64); err == nil && f > 0 {
+			*dst = f * unit
+		}
 	}
 }
 
@@ -54,71 +82,43 @@ func (c *checker) Check(ctx context.Context) (*check.Report, error) {
 		return nil, fmt.Errorf("running %s/%s: %w", check.CategoryConfigs, report.CheckID, err)
 	}
 
-	// Check if we have enough data (at least 1 hour since stats reset)
-	secondsSinceReset := getSecondsSinceReset(row)
-	if secondsSinceReset < 3600 {
-		report.AddFinding(check.Finding{
-			ID:       report.CheckID,
-			Name:     report.Name,
-			Severity: check.SeverityOK,
-			Details:  fmt.Sprintf("Statistics reset too recently (%.0f minutes ago). Need at least 1 hour of data.", secondsSinceReset/60),
-		})
-		return report, nil
+	// Rate the counters since the reset, or over the server's uptime when
+	// they were never reset, noting when the window is an estimate.
+	window := check.StatsWindow(row.SecondsSinceReset, row.UptimeSeconds)
+	var note string
+	if n := check.StatsWindowNote(row.SecondsSinceReset); n != "" {
+		note = "\n\nRates are estimates: " + n + "."
 	}
 
 	// Run all subchecks
-	checkTempFileRate(row, report)
-	checkTempVolumeRate(row, report)
+	c.checkTempFileRate(row, window, note, report)
+	c.checkTempVolumeRate(row, window, note, report)
 
 	return report, nil
 }
 
-func getSecondsSinceReset(row db.TempUsageRow) float64 {
-	if !row.SecondsSinceReset.Valid {
-		return 0
-	}
-	f, _ := row.SecondsSinceReset.Float64Value()
-	return f.Float64
-}
-
-func getTempFilesPerHour(row db.TempUsageRow) float64 {
-	if !row.TempFilesPerHour.Valid {
-		return 0
-	}
-	f, _ := row.TempFilesPerHour.Float64Value()
-	return f.Float64
-}
-
-func getTempBytesPerHour(row db.TempUsageRow) float64 {
-	if !row.TempBytesPerHour.Valid {
-		return 0
-	}
-	f, _ := row.TempBytesPerHour.Float64Value()
-	return f.Float64
-}
-
 // checkTempFileRate identifies high temp file creation rates.
 // Thresholds are tuned for production scale based on observed baselines (~0.3 files/hour).
 // These catch regressions (query plan changes, work_mem resets) rather than absolute badness.
-func checkTempFileRate(row db.TempUsageRow, report *check.Report) {
-	rate := getTempFilesPerHour(row)
+func (c *checker) checkTempFileRate(row db.TempUsageRow, window float64, note string, report *check.Report) {
+	rate := float64(row.TempFiles.Int64) / window * 3600
 
-	// Threshold: 5 files/hour is ~20x typical production baseline
+	// Default threshold: 5 files/hour is ~20x typical production baseline
 	// Indicates: New inefficient queries, query plan regression, or work_mem issues
-	if rate < 5 {
+	if rate < c.filesWarn {
 		report.AddFinding(check.Finding{
 			ID:       "temp-file-rate",
 			Name:     "Temp File Creation Rate",
 			Severity: check.SeverityOK,
-			Details:  fmt.Sprintf("Temp file creation rate is acceptable: %.1f files/hour", rate),
+			Details:  fmt.Sprintf("Temp file creation rate is acceptable: %.1f files/hour%s", rate, note),
 		})
 		return
 	}
 
 	severity := check.SeverityWarn
-	// Threshold: 20 files/hour is ~75x typical production baseline
+	// Default threshold: 20 files/hour is ~75x typical production baseline
 	// Indicates: Serious regression or multiple problematic queries
-	if rate >= 20 {
+	if rate >= c.filesFail {
 		severity = check.SeverityFail
 	}
 
@@ -132,10 +132,11 @@ func checkTempFileRate(row db.TempUsageRow, report *check.Report) {
 		Name:     "Temp File Creation Rate",
 		Severity: severity,
 		Details: fmt.Sprintf(
-			"High temp file creation rate: %.1f files/hour%s\n\nTotal temp files: %d\nTotal temp data: %s",
+			"High temp file creation rate: %.1f files/hour%s\n\nTotal temp files: %d\nTotal temp data: %s%s",
 			rate, statsResetInfo,
 			row.TempFiles.Int64,
 			check.FormatBytes(row.TempBytes.Int64),
+			note,
 		),
 	})
 }
@@ -143,38 +144,37 @@ func checkTempFileRate(row db.TempUsageRow, report *check.Report) {
 // checkTempVolumeRate identifies high temp data volume.
 // Thresholds are tuned for production scale based on observed baselines (~124MB/hour).
 // These catch significant increases in disk spilling rather than absolute usage.
-func checkTempVolumeRate(row db.TempUsageRow, report *check.Report) {
-	const oneGB = float64(1024 * 1024 * 1024)
-	const fiveGB = float64(5 * 1024 * 1024 * 1024)
-
-	bytesPerHour := getTempBytesPerHour(row)
+func (c *checker) checkTempVolumeRate(row db.TempUsageRow, window float64, note string, report *check.Report) {
+	bytesPerHour := float64(row.TempBytes.Int64) / window * 3600
 
-	// Threshold: 1GB/hour is ~8x typical production baseline
+	// Default threshold: 1GB/hour is ~8x typical production baseline
 	// Indicates: Increased large sorts/hashes, possibly from new features or query changes
-	if bytesPerHour < oneGB {
+	if bytesPerHour < c.volumeWarn {
 		report.AddFinding(check.Finding{
 			ID:       "temp-volume-rate",
+			Tags:     []string{check.TagTempFiles},
 			Name:     "Temp Data Volume Rate",
 			Severity: check.SeverityOK,
-			Details:  fmt.Sprintf("Temp data volume is acceptable: %s/hour", check.FormatBytes(int64(bytesPerHour))),
+			Details:  fmt.Sprintf("Temp data volume is acceptable: %s/hour%s", check.FormatBytes(int64(bytesPerHour)), note),
 		})
 		return
 	}
 
 	severity := check.SeverityWarn
-	// Threshold: 5GB/hour is ~40x typical production baseline
+	// Default threshold: 5GB/hour is ~40x typical production baseline
 	// Indicates: Major regression or multiple large queries spilling to disk
-	if bytesPerHour >= fiveGB {
+	if bytesPerHour >= c.volumeFail {
 		severity = check.SeverityFail
 	}
 
 	report.AddFinding(check.Finding{
 		ID:       "temp-volume-rate",
+		Tags:     []string{check.TagTempFiles},
 		Name:     "Temp Data Volume Rate",
 		Severity: severity,
 		Details: fmt.Sprintf(
-			"High temp data volume: %s/hour\n\nThis causes significant disk I/O and slows queries.",
-			check.FormatBytes(int64(bytesPerHour)),
+			"High temp data volume: %s/hour\n\nThis causes significant disk I/O and slows queries.%s",
+			check.FormatBytes(int64(bytesPerHour)), note,
 		),
 	})
 }
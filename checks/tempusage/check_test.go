@@ -2,7 +2,6 @@ package tempusage_test
 
 import (
 	"context"
-	"fmt"
 	"testing"
 	"time"
 
@@ -23,24 +22,20 @@ func (m *mockQueryer) TempUsage(ctx context.Context) (db.TempUsageRow, error) {
 	return m.row, m.err
 }
 
+// makeTempUsageRow builds the counters of a database writing temp files at
+// the given hourly rates since its statistics were reset.
 func makeTempUsageRow(
-	tempFiles, tempBytes int64,
 	secondsSinceReset float64,
 	tempFilesPerHour, tempBytesPerHour float64,
 	statsReset *time.Time,
 ) db.TempUsageRow {
-	var secondsNumeric, filesPerHourNumeric, bytesPerHourNumeric pgtype.Numeric
-	_ = secondsNumeric.Scan(fmt.Sprintf("%.2f", secondsSinceReset))
-	_ = filesPerHourNumeric.Scan(fmt.Sprintf("%.2f", tempFilesPerHour))
-	_ = bytesPerHourNumeric.Scan(fmt.Sprintf("%.2f", tempBytesPerHour))
-
+	hours := secondsSinceReset / 3600
 	row := db.TempUsageRow{
 		DatabaseName:      pgtype.Text{String: "test_db", Valid: true},
-		TempFiles:         pgtype.Int8{Int64: tempFiles, Valid: true},
-		TempBytes:         pgtype.Int8{Int64: tempBytes, Valid: true},
-		SecondsSinceReset: secondsNumeric,
-		TempFilesPerHour:  filesPerHourNumeric,
-		TempBytesPerHour:  bytesPerHourNumeric,
+		TempFiles:         pgtype.Int8{Int64: int64(tempFilesPerHour * hours), Valid: true},
+		TempBytes:         pgtype.Int8{Int64: int64(tempBytesPerHour * hours), Valid: true},
+		SecondsSinceReset: pgtype.Int8{Int64: int64(secondsSinceReset), Valid: true},
+		UptimeSeconds:     int64(secondsSinceReset),
 	}
 
 	if statsReset != nil {
@@ -55,19 +50,23 @@ func TestTempUsage_StatsResetTooRecent(t *testing.T) {
 
 	tests := []struct {
 		name              string
-		secondsSinceReset float64
+		secondsSinceReset int64
+		note              string
 	}{
 		{
 			name:              "stats reset 30 minutes ago",
-			secondsSinceReset: 1800, // 30 minutes
+			secondsSinceReset: 1800,
+			note:              "statistics were reset 30m ago",
 		},
 		{
 			name:              "stats reset 1 minute ago",
 			secondsSinceReset: 60,
+			note:              "statistics were reset 1m ago",
 		},
 		{
 			name:              "stats reset just now",
 			secondsSinceReset: 0,
+			note:              "statistics were reset 0s ago",
 		},
 	}
 
@@ -75,18 +74,24 @@ func TestTempUsage_StatsResetTooRecent(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			row := makeTempUsageRow(100, 1000000, tt.secondsSinceReset, 10.0, 100000.0, nil)
-			queryer := &mockQueryer{row: row}
-			checker := tempusage.New(queryer)
+			// 100 temp files in less than an hour are rated over the hour.
+			row := db.TempUsageRow{
+				TempFiles:         pgtype.Int8{Int64: 100, Valid: true},
+				TempBytes:         pgtype.Int8{Int64: 1000000, Valid: true},
+				SecondsSinceReset: pgtype.Int8{Int64: tt.secondsSinceReset, Valid: true},
+				UptimeSeconds:     86400,
+			}
+			checker := tempusage.New(&mockQueryer{row: row})
 
 			report, err := checker.Check(context.Background())
 
 			require.NoError(t, err)
-			assert.Equal(t, check.SeverityOK, report.Severity)
-			assert.Len(t, report.Results, 1)
-			assert.Equal(t, "temp-usage", report.Results[0].ID)
-			assert.Contains(t, report.Results[0].Details, "Statistics reset too recently")
-			assert.Contains(t, report.Results[0].Details, "Need at least 1 hour of data")
+			assert.Equal(t, check.SeverityFail, report.Severity)
+			require.Len(t, report.Results, 2)
+			assert.Equal(t, "temp-file-rate", report.Results[0].ID)
+			assert.Contains(t, report.Results[0].Details, "100.0 files/hour")
+			assert.Contains(t, report.Results[0].Details, tt.note+", so the counters are rated over 1h")
+			assert.Contains(t, report.Results[1].Details, tt.note)
 		})
 	}
 }
@@ -100,8 +105,6 @@ func TestTempUsage_AllHealthy(t *testing.T) {
 
 	// Low temp file rate and low volume
 	row := makeTempUsageRow(
-		100,                 // 100 temp files total
-		50*1024*1024,        // 50MB total
 		oneHourInSeconds*24, // 24 hours since reset
 		4.2,                 // 4.2 files/hour (< 5)
 		2*1024*1024,         // 2MB/hour (< 1GB)
@@ -134,8 +137,6 @@ func TestTempUsage_HighFileRate_Warning(t *testing.T) {
 	statsReset := now.Add(-24 * time.Hour)
 
 	row := makeTempUsageRow(
-		2400,                // temp files
-		100*1024*1024,       // 100MB
 		oneHourInSeconds*24, // 24 hours
 		10.0,                // 10 files/hour (>= 5, < 20) -> WARN
 		4*1024*1024,         // 4MB/hour
@@ -164,8 +165,6 @@ func TestTempUsage_HighFileRate_Critical(t *testing.T) {
 	statsReset := now.Add(-24 * time.Hour)
 
 	row := makeTempUsageRow(
-		12000,               // temp files
-		500*1024*1024,       // 500MB
 		oneHourInSeconds*24, // 24 hours
 		50.0,                // 50 files/hour (>= 20) -> FAIL
 		20*1024*1024,        // 20MB/hour
@@ -195,8 +194,6 @@ func TestTempUsage_HighVolumeRate_Warning(t *testing.T) {
 	statsReset := now.Add(-24 * time.Hour)
 
 	row := makeTempUsageRow(
-		100,                 // low file count
-		50*oneGB,            // 50GB total
 		oneHourInSeconds*24, // 24 hours
 		4.2,                 // 4.2 files/hour
 		2*oneGB,             // 2GB/hour (>= 1GB, < 5GB) -> WARN
@@ -226,8 +223,6 @@ func TestTempUsage_HighVolumeRate_Critical(t *testing.T) {
 	statsReset := now.Add(-24 * time.Hour)
 
 	row := makeTempUsageRow(
-		100,                 // low file count
-		200*oneGB,           // 200GB total
 		oneHourInSeconds*24, // 24 hours
 		4.2,                 // 4.2 files/hour
 		8*oneGB,             // 8GB/hour (>= 5GB) -> FAIL
@@ -257,8 +252,6 @@ func TestTempUsage_BothHighRates(t *testing.T) {
 	statsReset := now.Add(-24 * time.Hour)
 
 	row := makeTempUsageRow(
-		20000,               // many temp files
-		150*oneGB,           // 150GB total
 		oneHourInSeconds*24, // 24 hours
 		833.0,               // 833 files/hour (>= 20) -> FAIL
 		6.25*oneGB,          // 6.25GB/hour (>= 5GB) -> FAIL
@@ -342,8 +335,6 @@ func TestTempUsage_EdgeCases_ExactThresholds(t *testing.T) {
 			t.Parallel()
 
 			row := makeTempUsageRow(
-				1000,
-				int64(tt.bytesPerHour*24), // Scale up to 24 hours
 				oneHourInSeconds*24,
 				tt.filesPerHour,
 				tt.bytesPerHour,
@@ -361,20 +352,16 @@ func TestTempUsage_EdgeCases_ExactThresholds(t *testing.T) {
 	}
 }
 
-func TestTempUsage_InvalidNumeric(t *testing.T) {
+func TestTempUsage_NullCounters(t *testing.T) {
 	t.Parallel()
 
 	now := time.Now()
 	statsReset := now.Add(-24 * time.Hour)
 
-	// Row with invalid Numeric values
+	// Row with NULL counters
 	row := db.TempUsageRow{
 		DatabaseName:      pgtype.Text{String: "test_db", Valid: true},
-		TempFiles:         pgtype.Int8{Int64: 100, Valid: true},
-		TempBytes:         pgtype.Int8{Int64: 1000000, Valid: true},
-		SecondsSinceReset: pgtype.Numeric{Valid: false}, // Invalid
-		TempFilesPerHour:  pgtype.Numeric{Valid: false}, // Invalid
-		TempBytesPerHour:  pgtype.Numeric{Valid: false}, // Invalid
+		SecondsSinceReset: pgtype.Int8{Int64: 24 * 3600, Valid: true},
 		StatsReset:        pgtype.Timestamptz{Time: statsReset, Valid: true},
 	}
 
@@ -383,10 +370,87 @@ func TestTempUsage_InvalidNumeric(t *testing.T) {
 	report, err := checker.Check(context.Background())
 
 	require.NoError(t, err)
-	// Should treat invalid numerics as 0 and report stats too recent
+	// Should treat NULL counters as 0 and report acceptable rates
 	assert.Equal(t, check.SeverityOK, report.Severity)
-	assert.Len(t, report.Results, 1)
-	assert.Contains(t, report.Results[0].Details, "Statistics reset too recently")
+	assert.Len(t, report.Results, 2)
+	assert.Contains(t, report.Results[0].Details, "0.0 files/hour")
+}
+
+func TestTempUsage_StatsNeverReset(t *testing.T) {
+	t.Parallel()
+
+	// stats_reset is NULL until the first reset, and so are the seconds
+	// since it: the counters are rated over the server's uptime.
+	row := db.TempUsageRow{
+		DatabaseName:  pgtype.Text{String: "test_db", Valid: true},
+		TempFiles:     pgtype.Int8{Int64: 1200, Valid: true},
+		TempBytes:     pgtype.Int8{Int64: 500 * 1024 * 1024 * 1024, Valid: true},
+		UptimeSeconds: 100 * 3600,
+	}
+
+	report, err := tempusage.New(&mockQueryer{row: row}).Check(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, check.SeverityFail, report.Severity)
+	require.Len(t, report.Results, 2)
+	assert.Equal(t, check.SeverityWarn, report.Results[0].Severity)
+	assert.Contains(t, report.Results[0].Details, "12.0 files/hour")
+	assert.Contains(t, report.Results[0].Details, "Rates are estimates: statistics were never reset")
+	assert.Equal(t, check.SeverityFail, report.Results[1].Severity)
+	assert.Contains(t, report.Results[1].Details, "5.0GiB/hour")
+	assert.Contains(t, report.Results[1].Details, "Rates are estimates: statistics were never reset")
+}
+
+func TestTempUsage_ConfiguredThresholds(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		cfg          map[string]string
+		filesPerHour float64
+		bytesPerHour float64
+		fileSeverity check.Severity
+		volSeverity  check.Severity
+	}{
+		{
+			name:         "lower file thresholds",
+			cfg:          map[string]string{"files_per_hour_warn": "1", "files_per_hour_fail": "2.5"},
+			filesPerHour: 3, bytesPerHour: 0,
+			fileSeverity: check.SeverityFail, volSeverity: check.SeverityOK,
+		},
+		{
+			name:         "higher volume thresholds",
+			cfg:          map[string]string{"volume_mb_per_hour_warn": "10240", "volume_mb_per_hour_fail": "20480"},
+			filesPerHour: 1, bytesPerHour: 6 * 1024 * 1024 * 1024,
+			fileSeverity: check.SeverityOK, volSeverity: check.SeverityOK,
+		},
+		{
+			name:         "lower volume warn",
+			cfg:          map[string]string{"volume_mb_per_hour_warn": "100"},
+			filesPerHour: 1, bytesPerHour: 200 * 1024 * 1024,
+			fileSeverity: check.SeverityOK, volSeverity: check.SeverityWarn,
+		},
+		{
+			name:         "invalid values keep defaults",
+			cfg:          map[string]string{"files_per_hour_warn": "-1", "volume_mb_per_hour_warn": "lots"},
+			filesPerHour: 4, bytesPerHour: 512 * 1024 * 1024,
+			fileSeverity: check.SeverityOK, volSeverity: check.SeverityOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			row := makeTempUsageRow(24*3600, tt.filesPerHour, tt.bytesPerHour, nil)
+			checker := tempusage.New(&mockQueryer{row: row}, check.Config{"temp-usage": tt.cfg})
+			report, err := checker.Check(context.Background())
+			require.NoError(t, err)
+			require.Len(t, report.Results, 2)
+			assert.Equal(t, tt.fileSeverity, report.Results[0].Severity, "temp-file-rate")
+			assert.Equal(t, tt.volSeverity, report.Results[1].Severity, "temp-volume-rate")
+		})
+	}
 }
 
 func TestTempUsage_Metadata(t *testing.T) {
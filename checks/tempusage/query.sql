@@ -1,22 +1,16 @@
 -- name: TempUsage :one
--- Monitors temporary file creation indicating work_mem exhaustion
+-- Monitors temporary file creation indicating work_mem exhaustion.
+-- The counters accumulate from stats_reset, which is NULL until the first
+-- reset, and so are the seconds since it; the server's uptime is returned to
+-- rate them over then.
 WITH temp_stats AS (
   SELECT
     datname::text AS database_name
     , temp_files
     , temp_bytes
     , stats_reset
-    , EXTRACT(EPOCH FROM (NOW() - stats_reset)) AS seconds_since_reset
-    , CASE
-      WHEN EXTRACT(EPOCH FROM (NOW() - stats_reset)) > 0
-        THEN temp_files::numeric / (EXTRACT(EPOCH FROM (NOW() - stats_reset)) / 3600)
-      ELSE 0
-    END AS temp_files_per_hour
-    , CASE
-      WHEN EXTRACT(EPOCH FROM (NOW() - stats_reset)) > 0
-        THEN temp_bytes::numeric / (EXTRACT(EPOCH FROM (NOW() - stats_reset)) / 3600)
-      ELSE 0
-    END AS temp_bytes_per_hour
+    , EXTRACT(EPOCH FROM (NOW() - stats_reset))::bigint AS seconds_since_reset
+    , EXTRACT(EPOCH FROM (NOW() - pg_postmaster_start_time()))::bigint AS uptime_seconds
   FROM pg_stat_database
   WHERE datname = CURRENT_DATABASE()
 )
@@ -51,12 +45,11 @@ SELECT
   , ts.temp_bytes
   , ts.stats_reset
   , ts.seconds_since_reset
+  , ts.uptime_seconds
   , ms.work_mem
   , ms.temp_file_limit
   , ms.log_temp_files
   , ms.max_connections
   , ms.shared_buffers
-  , ROUND(ts.temp_files_per_hour::numeric, 2) AS temp_files_per_hour
-  , ROUND(ts.temp_bytes_per_hour::numeric, 0) AS temp_bytes_per_hour
 FROM temp_stats AS ts
 CROSS JOIN memory_settings AS ms;
@@ -0,0 +1,196 @@
+// Package toastconfig implements checks for per-table TOAST settings that keep large values inline or uncompressed.
+package toastconfig
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+const (
+	// defaultToastTupleTarget is PostgreSQL's default toast_tuple_target
+	// (TOAST_TUPLE_TARGET for 8KB pages).
+	defaultToastTupleTarget = 2032
+
+	// At this target rows up to half a page stay inline, halving the rows that
+	// fit on a heap page for every scan that never reads the large values.
+	raisedTargetWarnBytes = 4096
+
+	// Columns averaging wider than this cross the TOAST threshold, so their
+	// storage strategy decides where and how they are stored.
+	wideColumnBytes = 2000
+)
+
+type ToastConfigQueries interface {
+	ToastTupleTargets(context.Context) ([]db.ToastTupleTargetsRow, error)
+	ToastColumnStorage(context.Context) ([]db.ToastColumnStorageRow, error)
+}
+
+type checker struct {
+	queries ToastConfigQueries
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategorySchema,
+		CheckID:     "toast-config",
+		Name:        "TOAST Configuration",
+		Description: "Flags raised toast_tuple_target settings and column storage strategies that keep large values inline or uncompressed",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries ToastConfigQueries, _ ...check.Config) check.Checker {
+	return &checker{
+		queries: queries,
+	}
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	targets, err := c.queries.ToastTupleTargets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	columns, err := c.queries.ToastColumnStorage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	checkTupleTarget(targets, report)
+	checkColumnStorage(columns, report)
+
+	return report, nil
+}
+
+func checkTupleTarget(rows []db.ToastTupleTargetsRow, report *check.Report) {
+	var tableRows []check.TableRow
+	for _, row := range rows {
+		target := int64(row.ToastTupleTarget.Int32)
+		if !row.ToastTupleTarget.Valid || target < raisedTargetWarnBytes {
+			continue
+		}
+		tableRows = append(tableRows, check.TableRow{
+			Cells: []string{
+				row.TableName,
+				fmt.Sprintf("%d", target),
+				check.FormatBytes(check.Int8ToInt64(row.TableSize)),
+			},
+			Severity: check.SeverityWarn,
+		})
+	}
+
+	if len(tableRows) == 0 {
+		details := "No tables raise toast_tuple_target"
+		if len(rows) > 0 {
+			details = fmt.Sprintf("%d table(s) override toast_tuple_target, none at %d bytes or more",
+				len(rows), raisedTargetWarnBytes)
+		}
+		report.AddFinding(check.Finding{
+			ID:       "toast-tuple-target",
+			Name:     "TOAST Tuple Target",
+			Severity: check.SeverityOK,
+			Details:  details,
+		})
+		return
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       "toast-tuple-target",
+		Name:     "TOAST Tuple Target",
+		Severity: check.SeverityWarn,
+		Details: fmt.Sprintf("Found %d table(s) with toast_tuple_target of %d bytes or more (default %d); large values stay inline and fewer rows fit per page",
+			len(tableRows), raisedTargetWarnBytes, defaultToastTupleTarget),
+		Table: &check.Table{
+			Headers:   []string{"Table", "toast_tuple_target", "Size"},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignRight, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	})
+}
+
+func checkColumnStorage(rows []db.ToastColumnStorageRow, report *check.Report) {
+	var tableRows []check.TableRow
+	for _, row := range rows {
+		problem := storageProblem(row)
+		if problem == "" {
+			continue
+		}
+		width := "-"
+		if row.AvgWidth.Valid {
+			width = check.FormatBytes(int64(row.AvgWidth.Int32))
+		}
+		tableRows = append(tableRows, check.TableRow{
+			Cells:    []string{row.TableName, row.ColumnName, row.ColumnType, row.Storage, width, problem},
+			Severity: check.SeverityWarn,
+		})
+	}
+
+	if len(tableRows) == 0 {
+		details := "All variable-length columns use their type's default storage"
+		if len(rows) > 0 {
+			details = fmt.Sprintf("%d column(s) override their storage strategy, none in a way that hurts wide values", len(rows))
+		}
+		report.AddFinding(check.Finding{
+			ID:       "column-storage",
+			Name:     "Column Storage Strategy",
+			Severity: check.SeverityOK,
+			Details:  details,
+		})
+		return
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       "column-storage",
+		Name:     "Column Storage Strategy",
+		Severity: check.SeverityWarn,
+		Details:  fmt.Sprintf("Found %d column(s) whose storage strategy keeps large values inline or uncompressed", len(tableRows)),
+		Table: &check.Table{
+			Headers:   []string{"Table", "Column", "Type", "Storage", "Avg Width", "Problem"},
+			Key:       []int{0, 1},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignLeft, check.AlignLeft, check.AlignLeft, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	})
+}
+
+// storageProblem explains why a column's non-default storage strategy is
+// suboptimal, or returns "" when it is reasonable for the data it holds.
+func storageProblem(row db.ToastColumnStorageRow) string {
+	wide := row.AvgWidth.Valid && row.AvgWidth.Int32 > wideColumnBytes
+
+	switch row.Storage {
+	case "PLAIN":
+		// Never compressed or moved out of line: rows that outgrow a page fail.
+		return "never compressed or toasted; rows over a page fail to insert"
+	case "MAIN":
+		if wide {
+			return "kept inline when possible; wide values bloat the heap"
+		}
+	case "EXTERNAL":
+		// EXTERNAL only pays off for substring access on text/bytea; JSON is
+		// always read whole, so it just loses compression.
+		if wide && (row.TypeName == "json" || row.TypeName == "jsonb") {
+			return "compression disabled; JSON gains nothing from uncompressed slicing"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,181 @@
+package toastconfig_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/checks/toastconfig"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockQueryer struct {
+	targets    []db.ToastTupleTargetsRow
+	columns    []db.ToastColumnStorageRow
+	targetsErr error
+	columnsErr error
+}
+
+func (m *mockQueryer) ToastTupleTargets(context.Context) ([]db.ToastTupleTargetsRow, error) {
+	return m.targets, m.targetsErr
+}
+
+func (m *mockQueryer) ToastColumnStorage(context.Context) ([]db.ToastColumnStorageRow, error) {
+	return m.columns, m.columnsErr
+}
+
+func target(table string, bytes int32) db.ToastTupleTargetsRow {
+	return db.ToastTupleTargetsRow{
+		TableName:        table,
+		ToastTupleTarget: pgtype.Int4{Int32: bytes, Valid: true},
+		TableSize:        pgtype.Int8{Int64: 10 * check.GiB, Valid: true},
+	}
+}
+
+func column(typeName, storage string, avgWidth int32) db.ToastColumnStorageRow {
+	return db.ToastColumnStorageRow{
+		TableName:      "public.events",
+		ColumnName:     "payload",
+		ColumnType:     typeName,
+		TypeName:       typeName,
+		Storage:        storage,
+		DefaultStorage: "EXTENDED",
+		AvgWidth:       pgtype.Int4{Int32: avgWidth, Valid: avgWidth > 0},
+	}
+}
+
+func findingByID(t *testing.T, report *check.Report, id string) check.Finding {
+	t.Helper()
+	for _, f := range report.Results {
+		if f.ID == id {
+			return f
+		}
+	}
+	t.Fatalf("finding %q not found", id)
+	return check.Finding{}
+}
+
+func TestToastConfig_NoOverrides(t *testing.T) {
+	t.Parallel()
+
+	report, err := toastconfig.New(&mockQueryer{}).Check(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, report.Results, 2)
+	assert.Equal(t, check.SeverityOK, findingByID(t, report, "toast-tuple-target").Severity)
+	assert.Equal(t, check.SeverityOK, findingByID(t, report, "column-storage").Severity)
+}
+
+func TestToastConfig_TupleTarget(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		target   int32
+		expected check.Severity
+	}{
+		{"lowered", 256, check.SeverityOK},
+		{"slightly raised", 3000, check.SeverityOK},
+		{"half page", 4096, check.SeverityWarn},
+		{"near page size", 8160, check.SeverityWarn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			report, err := toastconfig.New(&mockQueryer{
+				targets: []db.ToastTupleTargetsRow{target("public.events", tt.target)},
+			}).Check(context.Background())
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, findingByID(t, report, "toast-tuple-target").Severity)
+		})
+	}
+}
+
+func TestToastConfig_TupleTargetTable(t *testing.T) {
+	t.Parallel()
+
+	report, err := toastconfig.New(&mockQueryer{
+		targets: []db.ToastTupleTargetsRow{target("public.events", 8160), target("public.small", 1024)},
+	}).Check(context.Background())
+	require.NoError(t, err)
+
+	finding := findingByID(t, report, "toast-tuple-target")
+	require.NotNil(t, finding.Table)
+	require.Len(t, finding.Table.Rows, 1)
+	assert.Equal(t, []string{"public.events", "8160", "10.0GiB"}, finding.Table.Rows[0].Cells)
+}
+
+func TestToastConfig_ColumnStorage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		row      db.ToastColumnStorageRow
+		expected check.Severity
+	}{
+		{"plain without stats", column("text", "PLAIN", 0), check.SeverityWarn},
+		{"main narrow", column("jsonb", "MAIN", 500), check.SeverityOK},
+		{"main wide", column("jsonb", "MAIN", 6000), check.SeverityWarn},
+		{"main without stats", column("jsonb", "MAIN", 0), check.SeverityOK},
+		{"external wide jsonb", column("jsonb", "EXTERNAL", 6000), check.SeverityWarn},
+		{"external wide bytea", column("bytea", "EXTERNAL", 6000), check.SeverityOK},
+		{"external wide text", column("text", "EXTERNAL", 6000), check.SeverityOK},
+		{"extended on non-default type", column("text", "EXTENDED", 6000), check.SeverityOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			report, err := toastconfig.New(&mockQueryer{
+				columns: []db.ToastColumnStorageRow{tt.row},
+			}).Check(context.Background())
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, findingByID(t, report, "column-storage").Severity)
+		})
+	}
+}
+
+func TestToastConfig_ColumnStorageTable(t *testing.T) {
+	t.Parallel()
+
+	report, err := toastconfig.New(&mockQueryer{
+		columns: []db.ToastColumnStorageRow{column("jsonb", "MAIN", 6000), column("text", "PLAIN", 0)},
+	}).Check(context.Background())
+	require.NoError(t, err)
+
+	finding := findingByID(t, report, "column-storage")
+	require.NotNil(t, finding.Table)
+	require.Len(t, finding.Table.Rows, 2)
+	assert.Equal(t, []string{"public.events", "payload", "jsonb", "MAIN", "5.9KiB"}, finding.Table.Rows[0].Cells[:5])
+	assert.Equal(t, "-", finding.Table.Rows[1].Cells[4])
+}
+
+func TestToastConfig_QueryError(t *testing.T) {
+	t.Parallel()
+
+	_, err := toastconfig.New(&mockQueryer{targetsErr: fmt.Errorf("connection refused")}).Check(context.Background())
+	require.ErrorContains(t, err, "toast-config")
+
+	_, err = toastconfig.New(&mockQueryer{columnsErr: fmt.Errorf("connection refused")}).Check(context.Background())
+	require.ErrorContains(t, err, "toast-config")
+}
+
+func TestToastConfig_Metadata(t *testing.T) {
+	t.Parallel()
+
+	m := toastconfig.Metadata()
+	assert.Equal(t, "toast-config", m.CheckID)
+	assert.Equal(t, check.CategorySchema, m.Category)
+	assert.NotEmpty(t, m.Name)
+	assert.NotEmpty(t, m.Readme)
+	assert.NotEmpty(t, m.SQL)
+}
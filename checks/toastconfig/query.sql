@@ -0,0 +1,62 @@
+-- name: ToastTupleTargets :many
+-- Tables whose toast_tuple_target reloption differs from the 2032-byte
+-- default. A higher target keeps larger values inline in the heap.
+SELECT
+  (n.nspname || '.' || c.relname)::text AS table_name
+  , (
+    SELECT o.option_value::int
+    FROM pg_options_to_table(c.reloptions) AS o
+    WHERE o.option_name = 'toast_tuple_target'
+  ) AS toast_tuple_target
+  , pg_table_size(c.oid) AS table_size
+FROM pg_class AS c
+INNER JOIN pg_namespace AS n ON c.relnamespace = n.oid
+WHERE
+  c.relkind IN ('r', 'p', 'm')
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+  AND n.nspname NOT LIKE 'pg_toast%'
+  AND EXISTS (
+    SELECT 1
+    FROM pg_options_to_table(c.reloptions) AS o
+    WHERE o.option_name = 'toast_tuple_target' AND o.option_value::int <> 2032
+  )
+ORDER BY table_size DESC;
+
+-- name: ToastColumnStorage :many
+-- Variable-length columns whose storage strategy (ALTER TABLE ... SET STORAGE)
+-- differs from their type's default, with the average width from pg_stats.
+SELECT
+  (n.nspname || '.' || c.relname)::text AS table_name
+  , a.attname::text AS column_name
+  , format_type(a.atttypid, a.atttypmod)::text AS column_type
+  , t.typname::text AS type_name
+  , CASE a.attstorage
+    WHEN 'p' THEN 'PLAIN'
+    WHEN 'e' THEN 'EXTERNAL'
+    WHEN 'x' THEN 'EXTENDED'
+    WHEN 'm' THEN 'MAIN'
+  END::text AS storage
+  , CASE t.typstorage
+    WHEN 'p' THEN 'PLAIN'
+    WHEN 'e' THEN 'EXTERNAL'
+    WHEN 'x' THEN 'EXTENDED'
+    WHEN 'm' THEN 'MAIN'
+  END::text AS default_storage
+  , (
+    SELECT max(s.avg_width)
+    FROM pg_stats AS s
+    WHERE s.schemaname = n.nspname AND s.tablename = c.relname AND s.attname = a.attname
+  ) AS avg_width
+FROM pg_attribute AS a
+INNER JOIN pg_class AS c ON a.attrelid = c.oid
+INNER JOIN pg_namespace AS n ON c.relnamespace = n.oid
+INNER JOIN pg_type AS t ON a.atttypid = t.oid
+WHERE
+  a.attnum > 0
+  AND NOT a.attisdropped
+  AND a.attlen = -1
+  AND a.attstorage <> t.typstorage
+  AND c.relkind IN ('r', 'p', 'm')
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+  AND n.nspname NOT LIKE 'pg_toast%'
+ORDER BY table_name, a.attnum;
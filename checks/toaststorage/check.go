@@ -120,6 +120,8 @@ func checkExcessiveToastRatio(rows []db.ToastStorageRow, report *check.Report) {
 	}
 
 	headers := []string{"Table", "TOAST %", "TOAST Size", "Main Size", "Total"}
+	sensitive := []check.Sensitivity{check.SensitiveIdentifier}
+	align := []check.ColumnAlign{check.AlignLeft, check.AlignRight, check.AlignRight, check.AlignRight, check.AlignRight}
 	var tableRows []check.TableRow
 
 	for _, row := range critical {
@@ -154,8 +156,10 @@ func checkExcessiveToastRatio(rows []db.ToastStorageRow, report *check.Report) {
 		Severity: check.SeverityWarn,
 		Details:  fmt.Sprintf("Found %d table(s) with high TOAST storage ratio (>50%%)", len(critical)+len(warning)),
 		Table: &check.Table{
-			Headers: headers,
-			Rows:    tableRows,
+			Headers:   headers,
+			Align:     align,
+			Sensitive: sensitive,
+			Rows:      tableRows,
 		},
 	})
 }
@@ -185,6 +189,8 @@ func checkLargeToastTables(rows []db.ToastStorageRow, report *check.Report) {
 	}
 
 	headers := []string{"Table", "TOAST Size", "TOAST %", "Wide Columns"}
+	sensitive := []check.Sensitivity{check.SensitiveIdentifier, check.NotSensitive, check.NotSensitive, check.SensitiveIdentifier}
+	align := []check.ColumnAlign{check.AlignLeft, check.AlignRight, check.AlignRight}
 	var tableRows []check.TableRow
 
 	for _, row := range critical {
@@ -217,8 +223,10 @@ func checkLargeToastTables(rows []db.ToastStorageRow, report *check.Report) {
 		Severity: check.SeverityWarn,
 		Details:  fmt.Sprintf("Found %d table(s) with very large TOAST storage", len(critical)+len(warning)),
 		Table: &check.Table{
-			Headers: headers,
-			Rows:    tableRows,
+			Headers:   headers,
+			Align:     align,
+			Sensitive: sensitive,
+			Rows:      tableRows,
 		},
 	})
 }
@@ -261,6 +269,8 @@ func checkToastBloat(rows []db.ToastStorageRow, report *check.Report) {
 	}
 
 	headers := []string{"Table", "TOAST Size", "Dead Tuples %", "Dead Tuples", "Live Tuples"}
+	sensitive := []check.Sensitivity{check.SensitiveIdentifier}
+	align := []check.ColumnAlign{check.AlignLeft, check.AlignRight, check.AlignRight, check.AlignRight, check.AlignRight}
 	var tableRows []check.TableRow
 
 	for _, row := range critical {
@@ -301,8 +311,10 @@ func checkToastBloat(rows []db.ToastStorageRow, report *check.Report) {
 		Severity: check.SeverityWarn,
 		Details:  fmt.Sprintf("Found %d TOAST table(s) with excessive dead tuples", len(critical)+len(warning)),
 		Table: &check.Table{
-			Headers: headers,
-			Rows:    tableRows,
+			Headers:   headers,
+			Align:     align,
+			Sensitive: sensitive,
+			Rows:      tableRows,
 		},
 	})
 }
@@ -360,6 +372,8 @@ func checkWideColumns(rows []db.ToastStorageRow, report *check.Report) {
 	}
 
 	headers := []string{"Table", "Column", "Avg Width", "Type", "TOAST Size"}
+	sensitive := []check.Sensitivity{check.SensitiveIdentifier, check.SensitiveIdentifier}
+	align := []check.ColumnAlign{check.AlignLeft, check.AlignLeft, check.AlignRight, check.AlignLeft, check.AlignRight}
 	var tableRows []check.TableRow
 
 	// JSONB columns first (often the biggest offenders)
@@ -395,8 +409,11 @@ func checkWideColumns(rows []db.ToastStorageRow, report *check.Report) {
 		Severity: check.SeverityWarn,
 		Details:  fmt.Sprintf("Found %d JSONB and %d text columns with large average widths", len(jsonbColumns), len(largeTextColumns)),
 		Table: &check.Table{
-			Headers: headers,
-			Rows:    tableRows,
+			Headers:   headers,
+			Key:       []int{0, 1},
+			Align:     align,
+			Sensitive: sensitive,
+			Rows:      tableRows,
 		},
 	})
 }
@@ -473,6 +490,7 @@ func checkCompressionAlgorithm(ctx context.Context, rows []db.ToastStorageRow, r
 	}
 
 	headers := []string{"Table", "Column", "Type", "Current", "Storage", "Recommendation"}
+	sensitive := []check.Sensitivity{check.SensitiveIdentifier, check.SensitiveIdentifier}
 	var tableRows []check.TableRow
 
 	for _, issue := range suboptimalColumns {
@@ -495,8 +513,10 @@ func checkCompressionAlgorithm(ctx context.Context, rows []db.ToastStorageRow, r
 		Severity: check.SeverityWarn,
 		Details:  fmt.Sprintf("Found %d column(s) using suboptimal compression (pglz instead of lz4)", len(suboptimalColumns)),
 		Table: &check.Table{
-			Headers: headers,
-			Rows:    tableRows,
+			Headers:   headers,
+			Key:       []int{0, 1},
+			Sensitive: sensitive,
+			Rows:      tableRows,
 		},
 	})
 }
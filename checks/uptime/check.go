@@ -0,0 +1,118 @@
+// Package uptime implements a check for recent server restarts and statistics resets.
+package uptime
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strconv"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type UptimeQueries interface {
+	ServerUptime(context.Context) (db.ServerUptimeRow, error)
+}
+
+type checker struct {
+	queries     UptimeQueries
+	windowHours int64 // default: 24
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryConfigs,
+		CheckID:     "uptime",
+		Name:        "Server Uptime",
+		Description: "Reports how long the server has been up, warning on a recent restart, and when statistics were last reset",
+		Readme:      readme,
+		SQL:         querySQL,
+		ClusterWide: true,
+	}
+}
+
+func New(queries UptimeQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries:     queries,
+		windowHours: 24,
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			if v, ok := myCfg["window_hours"]; ok {
+				if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+					c.windowHours = n
+				}
+			}
+		}
+	}
+	return c
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	row, err := c.queries.ServerUptime(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	window := c.windowHours * 3600
+	c.checkRestart(row, window, report)
+	checkStatsReset(row, window, report)
+
+	return report, nil
+}
+
+func (c *checker) checkRestart(row db.ServerUptimeRow, window int64, report *check.Report) {
+	if row.UptimeSeconds < window {
+		report.AddFinding(check.Finding{
+			ID:       "restart",
+			Name:     "Recent Restart",
+			Severity: check.SeverityWarn,
+			Details: fmt.Sprintf("Server restarted %s ago (started %s), within the last %dh; if the restart was not planned, look for a crash or OOM kill in the server log",
+				check.FormatDurationSec(row.UptimeSeconds), row.StartedAt, c.windowHours),
+		})
+		return
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       "restart",
+		Name:     "Recent Restart",
+		Severity: check.SeverityOK,
+		Details:  fmt.Sprintf("Up for %s (started %s)", check.FormatDurationSec(row.UptimeSeconds), row.StartedAt),
+	})
+}
+
+// checkStatsReset only informs: usage-based checks read cumulative counters,
+// which cover just the time since the last reset.
+func checkStatsReset(row db.ServerUptimeRow, window int64, report *check.Report) {
+	var details string
+	switch {
+	case row.StatsResetAgeSeconds < 0:
+		details = fmt.Sprintf("Statistics of database %s have not been reset", row.DatabaseName)
+	case row.StatsResetAgeSeconds < window:
+		details = fmt.Sprintf("Statistics of database %s were reset %s ago; usage-based checks such as index-usage and table-seq-scans cover only that period",
+			row.DatabaseName, check.FormatDurationSec(row.StatsResetAgeSeconds))
+	default:
+		details = fmt.Sprintf("Statistics of database %s were last reset %s ago",
+			row.DatabaseName, check.FormatDurationSec(row.StatsResetAgeSeconds))
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       "stats-reset",
+		Name:     "Statistics Reset",
+		Severity: check.SeverityOK,
+		Details:  details,
+	})
+}
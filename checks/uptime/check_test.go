@@ -0,0 +1,94 @@
+package uptime_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/uptime"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeQueries struct {
+	uptime checktest.Result[db.ServerUptimeRow]
+}
+
+func (f fakeQueries) ServerUptime(context.Context) (db.ServerUptimeRow, error) {
+	return f.uptime.Get()
+}
+
+func newChecker(row db.ServerUptimeRow, cfg ...check.Config) check.Checker {
+	return uptime.New(fakeQueries{uptime: checktest.Returns(row)}, cfg...)
+}
+
+func server(uptimeSeconds, statsResetAgeSeconds int64) db.ServerUptimeRow {
+	return db.ServerUptimeRow{
+		UptimeSeconds:        uptimeSeconds,
+		StartedAt:            "2026-10-01 08:00 UTC",
+		DatabaseName:         "app",
+		StatsResetAgeSeconds: statsResetAgeSeconds,
+	}
+}
+
+func TestUptime_Metadata(t *testing.T) {
+	t.Parallel()
+	checktest.RequireMetadata(t, uptime.Metadata(), "uptime", check.CategoryConfigs)
+}
+
+func TestUptime_QueryError(t *testing.T) {
+	t.Parallel()
+	checktest.RequireError(t, uptime.New(fakeQueries{uptime: checktest.Fails[db.ServerUptimeRow](errors.New("boom"))}))
+}
+
+func TestUptime_LongRunning(t *testing.T) {
+	t.Parallel()
+
+	report := checktest.Run(t, newChecker(server(12*86400, -1)))
+	checktest.RequireSeverities(t, report, map[string]check.Severity{
+		"restart":     check.SeverityOK,
+		"stats-reset": check.SeverityOK,
+	})
+	assert.Equal(t, "Up for 12d (started 2026-10-01 08:00 UTC)", checktest.Finding(t, report, "restart").Details)
+	assert.Equal(t, "Statistics of database app have not been reset", checktest.Finding(t, report, "stats-reset").Details)
+}
+
+func TestUptime_RecentRestart(t *testing.T) {
+	t.Parallel()
+
+	report := checktest.Run(t, newChecker(server(35*60, 35*60)))
+	checktest.RequireSeverities(t, report, map[string]check.Severity{
+		"restart":     check.SeverityWarn,
+		"stats-reset": check.SeverityOK,
+	})
+	assert.Equal(t, "Server restarted 35m ago (started 2026-10-01 08:00 UTC), within the last 24h; if the restart was not planned, look for a crash or OOM kill in the server log",
+		checktest.Finding(t, report, "restart").Details)
+	assert.Contains(t, checktest.Finding(t, report, "stats-reset").Details, "were reset 35m ago; usage-based checks")
+}
+
+func TestUptime_Window(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		uptime   int64
+		cfg      check.Config
+		expected check.Severity
+	}{
+		{"just past the default window", 24 * 3600, nil, check.SeverityOK},
+		{"inside a wider window", 2 * 86400, check.Config{"uptime": {"window_hours": "72"}}, check.SeverityWarn},
+		{"outside a narrower window", 7 * 3600, check.Config{"uptime": {"window_hours": "6"}}, check.SeverityOK},
+		{"invalid window keeps default", 3600, check.Config{"uptime": {"window_hours": "0"}}, check.SeverityWarn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			report := checktest.Run(t, newChecker(server(tt.uptime, 30*86400), tt.cfg))
+			assert.Equal(t, tt.expected, checktest.Finding(t, report, "restart").Severity)
+		})
+	}
+}
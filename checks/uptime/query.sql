@@ -0,0 +1,11 @@
+-- name: ServerUptime :one
+-- Time since the postmaster started, and since the statistics of the current
+-- database were last reset. stats_reset is NULL until the first reset, which
+-- stats_reset_age_seconds reports as -1.
+SELECT
+  EXTRACT(EPOCH FROM now() - pg_postmaster_start_time())::bigint AS uptime_seconds
+  , (to_char(pg_postmaster_start_time() AT TIME ZONE 'UTC', 'YYYY-MM-DD HH24:MI') || ' UTC')::text AS started_at
+  , current_database()::text AS database_name
+  , coalesce(EXTRACT(EPOCH FROM now() - d.stats_reset)::bigint, -1)::bigint AS stats_reset_age_seconds
+FROM pg_stat_database AS d
+WHERE d.datname = current_database();
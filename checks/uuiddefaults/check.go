@@ -90,8 +90,10 @@ func (c *checker) Check(ctx context.Context) (*check.Report, error) {
 		Severity: check.SeverityWarn,
 		Details:  fmt.Sprintf("Found %d indexed UUID column(s) using random v4 defaults - may cause index bloat", len(indexedRandomUUIDs)),
 		Table: &check.Table{
-			Headers: []string{"Table", "Column", "Default"},
-			Rows:    tableRows,
+			Headers:   []string{"Table", "Column", "Default"},
+			Key:       []int{0, 1},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.SensitiveIdentifier},
+			Rows:      tableRows,
 		},
 	})
 
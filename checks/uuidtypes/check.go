@@ -82,8 +82,11 @@ func (c *checker) Check(ctx context.Context) (*check.Report, error) {
 		Severity: check.SeverityWarn,
 		Details:  fmt.Sprintf("Found %d UUID column(s) stored as string types", len(rows)),
 		Table: &check.Table{
-			Headers: []string{"Table", "Column", "Type", "Size"},
-			Rows:    tableRows,
+			Headers:   []string{"Table", "Column", "Type", "Size"},
+			Key:       []int{0, 1},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignLeft, check.AlignLeft, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.SensitiveIdentifier},
+			Rows:      tableRows,
 		},
 	})
 
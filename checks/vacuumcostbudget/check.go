@@ -0,0 +1,173 @@
+// Package vacuumcostbudget implements a check for tables whose dead tuples outpace autovacuum's cost budget.
+package vacuumcostbudget
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type VacuumCostBudgetQueries interface {
+	VacuumCostBudget(context.Context) ([]db.VacuumCostBudgetRow, error)
+}
+
+type checker struct {
+	queries     VacuumCostBudgetQueries
+	budgetRatio float64 // default: 1
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryVacuum,
+		CheckID:     "vacuum-cost-budget",
+		Name:        "Vacuum Cost Budget",
+		Description: "Estimates whether autovacuum's cost limit and delay let it remove dead tuples as fast as each table produces them",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries VacuumCostBudgetQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries:     queries,
+		budgetRatio: 1,
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			if v, ok := myCfg["budget_ratio"]; ok {
+				if n, err := strconv.ParseFloat(v, 64); err == nil && n > 0 {
+					c.budgetRatio = n
+				}
+			}
+		}
+	}
+	return c
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+// estimate is the steady state of one table under its autovacuum settings.
+type estimate struct {
+	deadPerSecond  float64 // dead tuples produced per second
+	passSeconds    float64 // one vacuum pass at the full cost budget
+	triggerSeconds float64 // time to accumulate the autovacuum threshold
+}
+
+// ratio is the share of the cost budget the table needs for itself: at 1 or
+// more, dead tuples reach the threshold again before a pass can finish.
+func (e estimate) ratio() float64 {
+	return e.passSeconds / e.triggerSeconds
+}
+
+// estimateTable models a vacuum pass as reading every page not marked
+// all-visible plus every index page (vacuum_cost_page_miss each, assuming
+// nothing is cached) and dirtying one heap page per dead tuple up to the
+// table size (vacuum_cost_page_dirty). ok is false when the table cannot be
+// starved by the budget: autovacuum is off for it, or not throttled.
+func estimateTable(row db.VacuumCostBudgetRow, windowSeconds float64) (e estimate, ok bool) {
+	if !row.AutovacuumEnabled || row.CostDelayMs <= 0 || row.CostLimit <= 0 {
+		return estimate{}, false
+	}
+
+	threshold := math.Max(row.VacuumThreshold+row.ScaleFactor*row.EstimatedRows, 1)
+	heapPages := float64(row.HeapPages)
+	dirtyPages := math.Min(heapPages, threshold)
+	scanPages := math.Max(heapPages-float64(row.AllVisiblePages), dirtyPages)
+	cost := (scanPages+float64(row.IndexPages))*row.PageMissCost + dirtyPages*row.PageDirtyCost
+
+	budgetPerSecond := row.CostLimit * 1000 / row.CostDelayMs
+	e.passSeconds = cost / budgetPerSecond
+	e.deadPerSecond = float64(row.DeadTuplesProduced) / windowSeconds
+	e.triggerSeconds = threshold / e.deadPerSecond
+	return e, true
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	rows, err := c.queries.VacuumCostBudget(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	// Every row carries the same window: the current database's.
+	var window float64
+	var note string
+	if len(rows) > 0 {
+		window = check.StatsWindow(rows[0].SecondsSinceReset, rows[0].UptimeSeconds)
+		if n := check.StatsWindowNote(rows[0].SecondsSinceReset); n != "" {
+			note = ". Dead-tuple rates are estimates: " + n
+		}
+	}
+
+	var tableRows []check.TableRow
+	worst := 0.0
+	throttled := 0
+	for _, row := range rows {
+		e, ok := estimateTable(row, window)
+		if !ok {
+			continue
+		}
+		throttled++
+		if e.ratio() < c.budgetRatio {
+			continue
+		}
+		worst = math.Max(worst, e.ratio())
+		tableRows = append(tableRows, check.TableRow{
+			Cells: []string{
+				row.TableName,
+				check.FormatNumber(int64(math.Round(e.deadPerSecond))),
+				check.FormatDurationMs(e.passSeconds * 1000),
+				check.FormatDurationMs(e.triggerSeconds * 1000),
+				fmt.Sprintf("%.1fx", e.ratio()),
+			},
+			Severity: check.SeverityWarn,
+		})
+	}
+
+	if len(tableRows) == 0 {
+		details := fmt.Sprintf("Autovacuum's cost budget keeps up with the dead tuples of all %d throttled table(s) with updates or deletes", throttled)
+		if throttled == 0 {
+			details = "No tables with updates or deletes are throttled by an autovacuum cost delay"
+		}
+		details += note
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  details,
+		})
+		return report, nil
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: check.SeverityWarn,
+		Details: fmt.Sprintf("%d table(s) need %.0f%% or more of autovacuum's cost budget to keep up with their dead tuples "+
+			"(the worst needs %.1fx the budget); past 100%% vacuum runs back to back and still falls behind. "+
+			"Raise autovacuum_vacuum_cost_limit or lower autovacuum_vacuum_cost_delay, globally or for these tables%s",
+			len(tableRows), c.budgetRatio*100, worst, note),
+		Table: &check.Table{
+			Headers:   []string{"Table", "Dead Tuples/s", "Vacuum Pass", "Threshold Every", "Budget Needed"},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignRight, check.AlignRight, check.AlignRight, check.AlignRight},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	})
+
+	return report, nil
+}
@@ -0,0 +1,194 @@
+package vacuumcostbudget_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/vacuumcostbudget"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeQueries struct {
+	tables checktest.Result[[]db.VacuumCostBudgetRow]
+}
+
+func (f fakeQueries) VacuumCostBudget(context.Context) ([]db.VacuumCostBudgetRow, error) {
+	return f.tables.Get()
+}
+
+// table is a 1M-row, 10,000-page table with no all-visible pages and no
+// indexes, under the PostgreSQL 14+ defaults: a pass costs 10,000 × 2 (page
+// misses) + 10,000 × 20 (dirtied pages) = 220,000 cost units, which at 200
+// units every 2ms (100,000/s) takes 2.2s. The threshold is 50 + 0.2 × 1M =
+// 200,050 dead tuples.
+func table(name string, deadPerSecond float64) db.VacuumCostBudgetRow {
+	const window = 86400
+	return db.VacuumCostBudgetRow{
+		TableName:          name,
+		EstimatedRows:      1_000_000,
+		HeapPages:          10_000,
+		DeadTuplesProduced: int64(deadPerSecond * window),
+		SecondsSinceReset:  pgtype.Int8{Int64: window, Valid: true},
+		AutovacuumEnabled:  true,
+		VacuumThreshold:    50,
+		ScaleFactor:        0.2,
+		CostLimit:          200,
+		CostDelayMs:        2,
+		PageMissCost:       2,
+		PageDirtyCost:      20,
+	}
+}
+
+func newChecker(rows []db.VacuumCostBudgetRow, cfg ...check.Config) check.Checker {
+	return vacuumcostbudget.New(fakeQueries{tables: checktest.Returns(rows)}, cfg...)
+}
+
+func TestVacuumCostBudget_Metadata(t *testing.T) {
+	t.Parallel()
+	checktest.RequireMetadata(t, vacuumcostbudget.Metadata(), "vacuum-cost-budget", check.CategoryVacuum)
+}
+
+func TestVacuumCostBudget_QueryError(t *testing.T) {
+	t.Parallel()
+
+	checker := vacuumcostbudget.New(fakeQueries{tables: checktest.Fails[[]db.VacuumCostBudgetRow](errors.New("boom"))})
+	checktest.RequireError(t, checker)
+}
+
+func TestVacuumCostBudget_Thresholds(t *testing.T) {
+	t.Parallel()
+
+	// The budget is exhausted when 2.2s of vacuum is needed every
+	// 200,050 / rate seconds, i.e. at about 90,932 dead tuples/s.
+	tests := []struct {
+		name     string
+		rate     float64
+		expected check.Severity
+	}{
+		{"quiet", 100, check.SeverityOK},
+		{"busy but keeping up", 80_000, check.SeverityOK},
+		{"starved", 100_000, check.SeverityWarn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker([]db.VacuumCostBudgetRow{table("public.events", tt.rate)})))
+			assert.Equal(t, tt.expected, finding.Severity)
+		})
+	}
+}
+
+func TestVacuumCostBudget_ReportsShortfall(t *testing.T) {
+	t.Parallel()
+
+	report := checktest.Run(t, newChecker([]db.VacuumCostBudgetRow{
+		table("public.events", 200_000),
+		table("public.users", 10),
+	}))
+	finding := checktest.OnlyFinding(t, report)
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Contains(t, finding.Details, "1 table(s) need 100% or more")
+	assert.Contains(t, finding.Details, "the worst needs 2.2x the budget")
+
+	require.NotNil(t, finding.Table)
+	assert.Equal(t, []string{"Table", "Dead Tuples/s", "Vacuum Pass", "Threshold Every", "Budget Needed"}, finding.Table.Headers)
+	assert.Equal(t, [][]string{{"public.events", "200.0K", "2.2s", "1.0s", "2.2x"}}, checktest.Rows(finding))
+}
+
+func TestVacuumCostBudget_Unthrottled(t *testing.T) {
+	t.Parallel()
+
+	disabled := table("public.events", 200_000)
+	disabled.AutovacuumEnabled = false
+	noDelay := table("public.orders", 200_000)
+	noDelay.CostDelayMs = 0
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker([]db.VacuumCostBudgetRow{disabled, noDelay})))
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "No tables with updates or deletes are throttled by an autovacuum cost delay", finding.Details)
+}
+
+func TestVacuumCostBudget_TableOverrides(t *testing.T) {
+	t.Parallel()
+
+	// The same starved table with ten times the cost limit keeps up.
+	row := table("public.events", 200_000)
+	row.CostLimit = 2000
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker([]db.VacuumCostBudgetRow{row})))
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Contains(t, finding.Details, "all 1 throttled table(s)")
+}
+
+func TestVacuumCostBudget_AllVisiblePagesAreSkipped(t *testing.T) {
+	t.Parallel()
+
+	// Only the pages holding dead tuples are read when the rest are
+	// all-visible, so a small threshold on a mostly frozen table is cheap.
+	row := table("public.events", 10)
+	row.ScaleFactor = 0
+	row.VacuumThreshold = 1000
+	row.AllVisiblePages = 9_990
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker([]db.VacuumCostBudgetRow{row})))
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+}
+
+func TestVacuumCostBudget_RecentStatsReset(t *testing.T) {
+	t.Parallel()
+
+	// A day of dead tuples counted over ten minutes is rated over the hour
+	// StatsWindow rounds the window up to, and still starves the table.
+	row := table("public.events", 200_000)
+	row.SecondsSinceReset = pgtype.Int8{Int64: 600, Valid: true}
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker([]db.VacuumCostBudgetRow{row})))
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Contains(t, finding.Details, "Dead-tuple rates are estimates: statistics were reset 10m ago, so the counters are rated over 1h")
+	assert.Equal(t, "4.8M", finding.Table.Rows[0].Cells[1])
+}
+
+func TestVacuumCostBudget_StatsNeverReset(t *testing.T) {
+	t.Parallel()
+
+	// Without a reset the counters are rated over the server's uptime, and
+	// the finding says they may be older.
+	row := table("public.events", 200_000)
+	row.SecondsSinceReset = pgtype.Int8{}
+	row.UptimeSeconds = 86400
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker([]db.VacuumCostBudgetRow{row})))
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Contains(t, finding.Details, "Dead-tuple rates are estimates: statistics were never reset")
+	assert.Equal(t, "200.0K", finding.Table.Rows[0].Cells[1])
+}
+
+func TestVacuumCostBudget_BudgetRatioConfig(t *testing.T) {
+	t.Parallel()
+
+	rows := []db.VacuumCostBudgetRow{table("public.events", 50_000)} // needs ~55%
+	cfg := check.Config{"vacuum-cost-budget": {"budget_ratio": "0.5"}}
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(rows, cfg)))
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Contains(t, finding.Details, "need 50% or more")
+
+	invalid := check.Config{"vacuum-cost-budget": {"budget_ratio": "-1"}}
+	finding = checktest.OnlyFinding(t, checktest.Run(t, newChecker(rows, invalid)))
+	assert.Equal(t, check.SeverityOK, finding.Severity, "invalid values keep the default")
+}
+
+func TestVacuumCostBudget_NoTables(t *testing.T) {
+	t.Parallel()
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(nil)))
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+}
@@ -0,0 +1,88 @@
+-- name: VacuumCostBudget :many
+-- Per-table inputs for estimating whether autovacuum's cost budget keeps up
+-- with dead-tuple accumulation: the dead tuples produced since the last stats
+-- reset (updates that were not HOT, plus deletes) and the seconds since then,
+-- NULL when the statistics were never reset, the server's uptime, the pages
+-- a vacuum pass reads, and the effective autovacuum threshold and cost
+-- settings, with per-table reloptions overriding the global ones. Cost
+-- delays are in milliseconds; -1 cost settings fall back to the
+-- vacuum_cost_* values.
+WITH settings AS (
+  SELECT
+    max(setting::float8) FILTER (WHERE name = 'autovacuum_vacuum_threshold') AS vacuum_threshold
+    , max(setting::float8) FILTER (WHERE name = 'autovacuum_vacuum_scale_factor') AS scale_factor
+    , max(setting::float8) FILTER (WHERE name = 'autovacuum_vacuum_cost_limit') AS autovacuum_cost_limit
+    , max(setting::float8) FILTER (WHERE name = 'autovacuum_vacuum_cost_delay') AS autovacuum_cost_delay
+    , max(setting::float8) FILTER (WHERE name = 'vacuum_cost_limit') AS cost_limit
+    , max(setting::float8) FILTER (WHERE name = 'vacuum_cost_delay') AS cost_delay
+    , max(setting::float8) FILTER (WHERE name = 'vacuum_cost_page_miss') AS page_miss
+    , max(setting::float8) FILTER (WHERE name = 'vacuum_cost_page_dirty') AS page_dirty
+  FROM pg_settings
+  WHERE name IN (
+    'autovacuum_vacuum_threshold', 'autovacuum_vacuum_scale_factor'
+    , 'autovacuum_vacuum_cost_limit', 'autovacuum_vacuum_cost_delay'
+    , 'vacuum_cost_limit', 'vacuum_cost_delay'
+    , 'vacuum_cost_page_miss', 'vacuum_cost_page_dirty'
+  )
+)
+
+, stats_window AS (
+  SELECT
+    extract(EPOCH FROM now() - d.stats_reset)::bigint AS seconds_since_reset
+    , extract(EPOCH FROM now() - pg_postmaster_start_time())::bigint AS uptime_seconds
+  FROM pg_stat_database AS d
+  WHERE d.datname = current_database()
+)
+
+SELECT
+  (n.nspname || '.' || c.relname)::text AS table_name
+  , greatest(c.reltuples, 0)::float8 AS estimated_rows
+  , c.relpages::bigint AS heap_pages
+  , c.relallvisible::bigint AS all_visible_pages
+  , coalesce((
+    SELECT sum(i.relpages)
+    FROM pg_index AS x
+    INNER JOIN pg_class AS i ON x.indexrelid = i.oid
+    WHERE x.indrelid = c.oid
+  ), 0)::bigint AS index_pages
+  , (s.n_tup_upd - s.n_tup_hot_upd + s.n_tup_del)::bigint AS dead_tuples_produced
+  , w.seconds_since_reset
+  , w.uptime_seconds
+  , coalesce((
+    SELECT o.option_value::bool
+    FROM pg_options_to_table(c.reloptions) AS o
+    WHERE o.option_name = 'autovacuum_enabled'
+  ), true) AS autovacuum_enabled
+  , coalesce((
+    SELECT o.option_value::float8
+    FROM pg_options_to_table(c.reloptions) AS o
+    WHERE o.option_name = 'autovacuum_vacuum_threshold'
+  ), st.vacuum_threshold)::float8 AS vacuum_threshold
+  , coalesce((
+    SELECT o.option_value::float8
+    FROM pg_options_to_table(c.reloptions) AS o
+    WHERE o.option_name = 'autovacuum_vacuum_scale_factor'
+  ), st.scale_factor)::float8 AS scale_factor
+  , coalesce((
+    SELECT nullif(o.option_value::float8, -1)
+    FROM pg_options_to_table(c.reloptions) AS o
+    WHERE o.option_name = 'autovacuum_vacuum_cost_limit'
+  ), nullif(st.autovacuum_cost_limit, -1), st.cost_limit)::float8 AS cost_limit
+  , coalesce((
+    SELECT nullif(o.option_value::float8, -1)
+    FROM pg_options_to_table(c.reloptions) AS o
+    WHERE o.option_name = 'autovacuum_vacuum_cost_delay'
+  ), nullif(st.autovacuum_cost_delay, -1), st.cost_delay)::float8 AS cost_delay_ms
+  , st.page_miss::float8 AS page_miss_cost
+  , st.page_dirty::float8 AS page_dirty_cost
+FROM pg_class AS c
+INNER JOIN pg_namespace AS n ON c.relnamespace = n.oid
+INNER JOIN pg_stat_user_tables AS s ON c.oid = s.relid
+CROSS JOIN settings AS st
+CROSS JOIN stats_window AS w
+WHERE
+  c.relkind IN ('r', 'm')
+  AND c.relpages > 0
+  AND s.n_tup_upd - s.n_tup_hot_upd + s.n_tup_del > 0
+ORDER BY dead_tuples_produced DESC
+LIMIT 500;
@@ -0,0 +1,216 @@
+// Package vacuumscalefactors implements checks for per-table autovacuum scale factor configuration.
+package vacuumscalefactors
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type VacuumScaleFactorsQueries interface {
+	VacuumScaleFactors(context.Context) ([]db.VacuumScaleFactorsRow, error)
+}
+
+type checker struct {
+	queries VacuumScaleFactorsQueries
+	minRows int64 // default: 1M
+}
+
+const (
+	// Tables at or above this size should never rely on the global scale factor
+	// unless it has been tuned down for them.
+	veryLargeTableMinRows = 10_000_000
+
+	// Target number of changed rows before autovacuum/autoanalyze fires on a
+	// large table. The recommended scale factor is derived from these.
+	targetVacuumRows  = 100_000
+	targetAnalyzeRows = 50_000
+
+	// Lowest scale factor ever recommended; beyond this, a fixed
+	// autovacuum_*_threshold is the better tool.
+	minRecommendedScaleFactor = 0.0001
+)
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryVacuum,
+		CheckID:     "vacuum-scale-factors",
+		Name:        "Vacuum Scale Factors",
+		Description: "Identifies large tables whose effective autovacuum/autoanalyze scale factors let too many changes accumulate",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries VacuumScaleFactorsQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries: queries,
+		minRows: 1_000_000,
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			if v, ok := myCfg["min_rows"]; ok {
+				if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+					c.minRows = n
+				}
+			}
+		}
+	}
+	return c
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	rows, err := c.queries.VacuumScaleFactors(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", check.CategoryVacuum, report.CheckID, err)
+	}
+
+	c.checkOverridesAboveGlobal(rows, report)
+	checkInheritedOnVeryLarge(rows, report)
+
+	return report, nil
+}
+
+// scaleFactor is one of the two scale factor settings for a single table.
+type scaleFactor struct {
+	setting     string
+	tableValue  pgtype.Float8
+	globalValue float64
+	targetRows  int64
+}
+
+func scaleFactors(row db.VacuumScaleFactorsRow) []scaleFactor {
+	return []scaleFactor{
+		{"vacuum", row.TableVacuumScaleFactor, check.Float8ToFloat64(row.GlobalVacuumScaleFactor), targetVacuumRows},
+		{"analyze", row.TableAnalyzeScaleFactor, check.Float8ToFloat64(row.GlobalAnalyzeScaleFactor), targetAnalyzeRows},
+	}
+}
+
+func (c *checker) checkOverridesAboveGlobal(rows []db.VacuumScaleFactorsRow, report *check.Report) {
+	var tableRows []check.TableRow
+	for _, row := range rows {
+		estimatedRows := check.Int8ToInt64(row.EstimatedRows)
+		if estimatedRows < c.minRows {
+			continue
+		}
+		for _, sf := range scaleFactors(row) {
+			if !sf.tableValue.Valid || sf.tableValue.Float64 <= sf.globalValue {
+				continue
+			}
+			tableRows = append(tableRows, scaleFactorRow(row.TableName, estimatedRows, sf, sf.tableValue.Float64))
+		}
+	}
+
+	if len(tableRows) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       "override-above-global",
+			Name:     "Scale Factor Above Global",
+			Severity: check.SeverityOK,
+			Details: fmt.Sprintf("No tables with more than %s rows override a scale factor above the global value",
+				check.FormatNumber(c.minRows)),
+		})
+		return
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       "override-above-global",
+		Name:     "Scale Factor Above Global",
+		Severity: check.SeverityWarn,
+		Details: fmt.Sprintf("Found %d per-table scale factor(s) higher than the global value on large tables; autovacuum rarely fires on them",
+			len(tableRows)),
+		Table: newTable(tableRows),
+	})
+}
+
+func checkInheritedOnVeryLarge(rows []db.VacuumScaleFactorsRow, report *check.Report) {
+	var tableRows []check.TableRow
+	for _, row := range rows {
+		estimatedRows := check.Int8ToInt64(row.EstimatedRows)
+		if estimatedRows < veryLargeTableMinRows {
+			continue
+		}
+		for _, sf := range scaleFactors(row) {
+			if sf.tableValue.Valid || sf.globalValue <= recommendedScaleFactor(estimatedRows, sf.targetRows) {
+				continue
+			}
+			tableRows = append(tableRows, scaleFactorRow(row.TableName, estimatedRows, sf, sf.globalValue))
+		}
+	}
+
+	if len(tableRows) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       "inherited-on-very-large",
+			Name:     "Global Scale Factor on Very Large Tables",
+			Severity: check.SeverityOK,
+			Details: fmt.Sprintf("No tables with more than %s rows inherit a global scale factor above the recommended value",
+				check.FormatNumber(veryLargeTableMinRows)),
+		})
+		return
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       "inherited-on-very-large",
+		Name:     "Global Scale Factor on Very Large Tables",
+		Severity: check.SeverityWarn,
+		Details: fmt.Sprintf("Found %d scale factor(s) inherited from the global setting on tables with more than %s rows",
+			len(tableRows), check.FormatNumber(veryLargeTableMinRows)),
+		Table: newTable(tableRows),
+	})
+}
+
+// newTable builds the table shared by both subchecks. Headers are built per
+// call so reports never alias each other's slices.
+func newTable(rows []check.TableRow) *check.Table {
+	return &check.Table{
+		Headers:   []string{"Table", "Rows", "Setting", "Effective", "Global", "Fires After", "Recommended"},
+		Align:     []check.ColumnAlign{check.AlignLeft, check.AlignRight, check.AlignLeft, check.AlignRight, check.AlignRight, check.AlignRight, check.AlignRight},
+		Sensitive: []check.Sensitivity{check.SensitiveIdentifier},
+		Rows:      rows,
+	}
+}
+
+func scaleFactorRow(table string, estimatedRows int64, sf scaleFactor, effective float64) check.TableRow {
+	return check.TableRow{
+		Cells: []string{
+			table,
+			check.FormatNumber(estimatedRows),
+			sf.setting,
+			formatScaleFactor(effective),
+			formatScaleFactor(sf.globalValue),
+			check.FormatNumber(int64(float64(estimatedRows) * effective)),
+			formatScaleFactor(recommendedScaleFactor(estimatedRows, sf.targetRows)),
+		},
+		Severity: check.SeverityWarn,
+	}
+}
+
+// recommendedScaleFactor returns the scale factor that makes autovacuum fire
+// after roughly targetRows changes on a table of the given size.
+func recommendedScaleFactor(estimatedRows, targetRows int64) float64 {
+	if estimatedRows <= 0 {
+		return 1
+	}
+	return math.Max(float64(targetRows)/float64(estimatedRows), minRecommendedScaleFactor)
+}
+
+func formatScaleFactor(v float64) string {
+	return strconv.FormatFloat(v, 'g', 2, 64)
+}
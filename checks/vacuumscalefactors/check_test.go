@@ -0,0 +1,199 @@
+package vacuumscalefactors_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/checks/vacuumscalefactors"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockQueryer struct {
+	rows []db.VacuumScaleFactorsRow
+	err  error
+}
+
+func (m *mockQueryer) VacuumScaleFactors(context.Context) ([]db.VacuumScaleFactorsRow, error) {
+	return m.rows, m.err
+}
+
+func float8(v float64) pgtype.Float8 {
+	return pgtype.Float8{Float64: v, Valid: true}
+}
+
+// table builds a row with the PostgreSQL default global scale factors.
+func table(name string, rows int64) db.VacuumScaleFactorsRow {
+	return db.VacuumScaleFactorsRow{
+		TableName:                name,
+		EstimatedRows:            pgtype.Int8{Int64: rows, Valid: true},
+		GlobalVacuumScaleFactor:  float8(0.2),
+		GlobalAnalyzeScaleFactor: float8(0.1),
+	}
+}
+
+func withOverrides(row db.VacuumScaleFactorsRow, vacuum, analyze float64) db.VacuumScaleFactorsRow {
+	row.TableVacuumScaleFactor = float8(vacuum)
+	row.TableAnalyzeScaleFactor = float8(analyze)
+	return row
+}
+
+func findingByID(t *testing.T, report *check.Report, id string) check.Finding {
+	t.Helper()
+	for _, f := range report.Results {
+		if f.ID == id {
+			return f
+		}
+	}
+	require.Failf(t, "finding not found", "id %q", id)
+	return check.Finding{}
+}
+
+func TestVacuumScaleFactors_Severity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		rows              []db.VacuumScaleFactorsRow
+		overrideSeverity  check.Severity
+		inheritedSeverity check.Severity
+	}{
+		{
+			name:              "no tables",
+			rows:              nil,
+			overrideSeverity:  check.SeverityOK,
+			inheritedSeverity: check.SeverityOK,
+		},
+		{
+			name:              "small table with high override",
+			rows:              []db.VacuumScaleFactorsRow{withOverrides(table("public.small", 10_000), 0.5, 0.5)},
+			overrideSeverity:  check.SeverityOK,
+			inheritedSeverity: check.SeverityOK,
+		},
+		{
+			name:              "large table with override above global",
+			rows:              []db.VacuumScaleFactorsRow{withOverrides(table("public.events", 5_000_000), 0.4, 0.05)},
+			overrideSeverity:  check.SeverityWarn,
+			inheritedSeverity: check.SeverityOK,
+		},
+		{
+			name:              "large table tuned below global",
+			rows:              []db.VacuumScaleFactorsRow{withOverrides(table("public.events", 50_000_000), 0.002, 0.001)},
+			overrideSeverity:  check.SeverityOK,
+			inheritedSeverity: check.SeverityOK,
+		},
+		{
+			name:              "very large table on defaults",
+			rows:              []db.VacuumScaleFactorsRow{table("public.events", 50_000_000)},
+			overrideSeverity:  check.SeverityOK,
+			inheritedSeverity: check.SeverityWarn,
+		},
+		{
+			name:              "large but not very large table on defaults",
+			rows:              []db.VacuumScaleFactorsRow{table("public.events", 5_000_000)},
+			overrideSeverity:  check.SeverityOK,
+			inheritedSeverity: check.SeverityOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			report, err := vacuumscalefactors.New(&mockQueryer{rows: tt.rows}).Check(context.Background())
+			require.NoError(t, err)
+
+			require.Len(t, report.Results, 2)
+			assert.Equal(t, tt.overrideSeverity, findingByID(t, report, "override-above-global").Severity)
+			assert.Equal(t, tt.inheritedSeverity, findingByID(t, report, "inherited-on-very-large").Severity)
+		})
+	}
+}
+
+func TestVacuumScaleFactors_TableContents(t *testing.T) {
+	t.Parallel()
+
+	report, err := vacuumscalefactors.New(&mockQueryer{rows: []db.VacuumScaleFactorsRow{
+		withOverrides(table("public.events", 5_000_000), 0.4, 0.05),
+		table("public.audit_log", 200_000_000),
+	}}).Check(context.Background())
+	require.NoError(t, err)
+
+	override := findingByID(t, report, "override-above-global")
+	require.NotNil(t, override.Table)
+	assert.Equal(t, []string{"Table", "Rows", "Setting", "Effective", "Global", "Fires After", "Recommended"}, override.Table.Headers)
+	require.Len(t, override.Table.Rows, 1, "the analyze override is below the global value")
+	assert.Equal(t, []string{"public.events", "5.0M", "vacuum", "0.4", "0.2", "2.0M", "0.02"}, override.Table.Rows[0].Cells)
+
+	inherited := findingByID(t, report, "inherited-on-very-large")
+	require.NotNil(t, inherited.Table)
+	require.Len(t, inherited.Table.Rows, 2)
+	assert.Equal(t, []string{"public.audit_log", "200.0M", "vacuum", "0.2", "0.2", "40.0M", "0.0005"}, inherited.Table.Rows[0].Cells)
+	assert.Equal(t, []string{"public.audit_log", "200.0M", "analyze", "0.1", "0.1", "20.0M", "0.00025"}, inherited.Table.Rows[1].Cells)
+}
+
+func TestVacuumScaleFactors_TablesDoNotAlias(t *testing.T) {
+	t.Parallel()
+
+	checker := vacuumscalefactors.New(&mockQueryer{rows: []db.VacuumScaleFactorsRow{
+		withOverrides(table("public.events", 5_000_000), 0.4, 0.05),
+		table("public.audit_log", 200_000_000),
+	}})
+	first, err := checker.Check(context.Background())
+	require.NoError(t, err)
+	second, err := checker.Check(context.Background())
+	require.NoError(t, err)
+
+	// Both subchecks and both runs build their own header slices.
+	findingByID(t, first, "override-above-global").Table.Headers[0] = "changed"
+	assert.Equal(t, "Table", findingByID(t, first, "inherited-on-very-large").Table.Headers[0])
+	assert.Equal(t, "Table", findingByID(t, second, "override-above-global").Table.Headers[0])
+}
+
+func TestVacuumScaleFactors_RecommendationFloor(t *testing.T) {
+	t.Parallel()
+
+	report, err := vacuumscalefactors.New(&mockQueryer{rows: []db.VacuumScaleFactorsRow{
+		table("public.huge", 10_000_000_000),
+	}}).Check(context.Background())
+	require.NoError(t, err)
+
+	inherited := findingByID(t, report, "inherited-on-very-large")
+	require.NotNil(t, inherited.Table)
+	assert.Equal(t, "0.0001", inherited.Table.Rows[0].Cells[6])
+}
+
+func TestVacuumScaleFactors_ConfigMinRows(t *testing.T) {
+	t.Parallel()
+
+	cfg := check.Config{"vacuum-scale-factors": {"min_rows": "1000"}}
+	report, err := vacuumscalefactors.New(&mockQueryer{rows: []db.VacuumScaleFactorsRow{
+		withOverrides(table("public.small", 10_000), 0.5, 0.05),
+	}}, cfg).Check(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, check.SeverityWarn, findingByID(t, report, "override-above-global").Severity)
+}
+
+func TestVacuumScaleFactors_QueryError(t *testing.T) {
+	t.Parallel()
+
+	_, err := vacuumscalefactors.New(&mockQueryer{err: fmt.Errorf("connection refused")}).Check(context.Background())
+	require.ErrorContains(t, err, "vacuum-scale-factors")
+}
+
+func TestVacuumScaleFactors_Metadata(t *testing.T) {
+	t.Parallel()
+
+	m := vacuumscalefactors.Metadata()
+	assert.Equal(t, "vacuum-scale-factors", m.CheckID)
+	assert.Equal(t, "Vacuum Scale Factors", m.Name)
+	assert.Equal(t, check.CategoryVacuum, m.Category)
+	assert.NotEmpty(t, m.Description)
+	assert.NotEmpty(t, m.SQL)
+	assert.NotEmpty(t, m.Readme)
+}
@@ -0,0 +1,28 @@
+-- name: VacuumScaleFactors :many
+-- Effective autovacuum/autoanalyze scale factors for every table, alongside
+-- the global settings they override (or inherit). Per-table values come from
+-- reloptions; NULL means the table inherits the global value.
+SELECT
+  (n.nspname || '.' || c.relname)::text AS table_name
+  , coalesce(s.n_live_tup, greatest(c.reltuples, 0)::bigint) AS estimated_rows
+  , current_setting('autovacuum_vacuum_scale_factor')::float8 AS global_vacuum_scale_factor
+  , current_setting('autovacuum_analyze_scale_factor')::float8 AS global_analyze_scale_factor
+  , (
+    SELECT o.option_value::float8
+    FROM pg_options_to_table(c.reloptions) AS o
+    WHERE o.option_name = 'autovacuum_vacuum_scale_factor'
+  ) AS table_vacuum_scale_factor
+  , (
+    SELECT o.option_value::float8
+    FROM pg_options_to_table(c.reloptions) AS o
+    WHERE o.option_name = 'autovacuum_analyze_scale_factor'
+  ) AS table_analyze_scale_factor
+FROM pg_class AS c
+INNER JOIN pg_namespace AS n ON c.relnamespace = n.oid
+LEFT JOIN pg_stat_user_tables AS s ON c.oid = s.relid
+WHERE
+  c.relkind IN ('r', 'm')
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+  AND n.nspname NOT LIKE 'pg_toast%'
+ORDER BY estimated_rows DESC
+LIMIT 500;
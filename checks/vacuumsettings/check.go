@@ -35,6 +35,7 @@ func Metadata() check.Metadata {
 		Description: "Validates autovacuum, maintenance memory, and vacuum cost settings",
 		Readme:      readme,
 		SQL:         querySQL,
+		ClusterWide: true,
 	}
 }
 
@@ -0,0 +1,231 @@
+// Package vacuumsla implements a check for tables vacuumed or analyzed less often than configured.
+package vacuumsla
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type VacuumSLAQueries interface {
+	VacuumSLATables(context.Context) ([]db.VacuumSLATablesRow, error)
+}
+
+type checker struct {
+	queries    VacuumSLAQueries
+	vacuum     []slaRule
+	analyze    []slaRule
+	failFactor float64 // default: 2
+}
+
+// slaRule is a maximum age for the tables matching "schema.table", where
+// either part may be "*".
+type slaRule struct {
+	pattern string
+	schema  string
+	table   string
+	seconds int64
+}
+
+func (r slaRule) matches(row db.VacuumSLATablesRow) bool {
+	return (r.schema == "*" || r.schema == row.SchemaName) &&
+		(r.table == "*" || r.table == row.TableName)
+}
+
+// specificity ranks matching rules: a named table beats a whole schema, which
+// beats any schema.
+func (r slaRule) specificity() int {
+	n := 0
+	if r.schema != "*" {
+		n += 2
+	}
+	if r.table != "*" {
+		n++
+	}
+	return n
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryVacuum,
+		CheckID:     "vacuum-sla",
+		Name:        "Vacuum and Analyze SLA",
+		Description: "Flags tables whose last vacuum or analyze is older than the maximum age configured for them",
+		Readme:      readme,
+		SQL:         querySQL,
+	}
+}
+
+func New(queries VacuumSLAQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries:    queries,
+		failFactor: 2,
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			if v, ok := myCfg["vacuum"]; ok {
+				c.vacuum = parseRules(v)
+			}
+			if v, ok := myCfg["analyze"]; ok {
+				c.analyze = parseRules(v)
+			}
+			if v, ok := myCfg["fail_factor"]; ok {
+				if f, err := strconv.ParseFloat(v, 64); err == nil && f > 1 {
+					c.failFactor = f
+				}
+			}
+		}
+	}
+	return c
+}
+
+// parseRules reads comma-separated "schema.table=age" entries. Unqualified
+// tables are in the public schema, and a bare age is in hours. Entries that
+// do not parse are ignored.
+func parseRules(value string) []slaRule {
+	var rules []slaRule
+	for _, entry := range strings.Split(value, ",") {
+		pattern, age, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		pattern, age = strings.TrimSpace(pattern), strings.TrimSpace(age)
+		if !ok || pattern == "" {
+			continue
+		}
+		ms, err := check.ParseDurationMs(age, "h")
+		if err != nil || ms <= 0 {
+			continue
+		}
+		rule := slaRule{pattern: pattern, schema: "public", table: pattern, seconds: ms / 1000}
+		if schema, table, ok := strings.Cut(pattern, "."); ok {
+			rule.schema, rule.table = schema, table
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// ruleFor returns the most specific rule matching row; the first listed wins
+// a tie.
+func ruleFor(rules []slaRule, row db.VacuumSLATablesRow) (slaRule, bool) {
+	best, found := slaRule{}, false
+	for _, r := range rules {
+		if r.matches(row) && (!found || r.specificity() > best.specificity()) {
+			best, found = r, true
+		}
+	}
+	return best, found
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	if len(c.vacuum) == 0 && len(c.analyze) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  "No vacuum or analyze SLAs configured",
+		})
+		return report, nil
+	}
+
+	rows, err := c.queries.VacuumSLATables(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	if len(c.vacuum) > 0 {
+		c.checkSLA(report, rows, "vacuum-sla", "Vacuum SLA", "vacuumed", c.vacuum,
+			func(row db.VacuumSLATablesRow) int64 { return row.VacuumAgeSeconds })
+	}
+	if len(c.analyze) > 0 {
+		c.checkSLA(report, rows, "analyze-sla", "Analyze SLA", "analyzed", c.analyze,
+			func(row db.VacuumSLATablesRow) int64 { return row.AnalyzeAgeSeconds })
+	}
+
+	return report, nil
+}
+
+// checkSLA adds a finding listing the tables whose age exceeds the SLA of
+// their most specific rule: WARN when over it, FAIL at failFactor times it.
+// A table that never was vacuumed or analyzed has no age to compare, so it
+// warns.
+func (c *checker) checkSLA(
+	report *check.Report,
+	rows []db.VacuumSLATablesRow,
+	id, name, verb string,
+	rules []slaRule,
+	age func(db.VacuumSLATablesRow) int64,
+) {
+	var tableRows []check.TableRow
+	severity := check.SeverityOK
+	covered := 0
+	for _, row := range rows {
+		rule, ok := ruleFor(rules, row)
+		if !ok {
+			continue
+		}
+		covered++
+
+		seconds := age(row)
+		if seconds >= 0 && seconds <= rule.seconds {
+			continue
+		}
+		rowSeverity, ageCell, over := check.SeverityWarn, "never", "-"
+		if seconds >= 0 {
+			if float64(seconds) >= c.failFactor*float64(rule.seconds) {
+				rowSeverity = check.SeverityFail
+			}
+			ageCell = check.FormatDurationSec(seconds)
+			over = fmt.Sprintf("%.1fx", float64(seconds)/float64(rule.seconds))
+		}
+		severity = max(severity, rowSeverity)
+		tableRows = append(tableRows, check.TableRow{
+			Cells: []string{
+				row.SchemaName + "." + row.TableName,
+				ageCell,
+				check.FormatDurationSec(rule.seconds),
+				over,
+				rule.pattern,
+			},
+			Severity: rowSeverity,
+		})
+	}
+
+	if len(tableRows) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       id,
+			Name:     name,
+			Severity: check.SeverityOK,
+			Details:  fmt.Sprintf("All %d table(s) with an SLA were %s within it", covered, verb),
+		})
+		return
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       id,
+		Name:     name,
+		Severity: severity,
+		Details:  fmt.Sprintf("%d of %d table(s) with an SLA were not %s within it", len(tableRows), covered, verb),
+		Table: &check.Table{
+			Headers:   []string{"Table", "Last " + strings.TrimSuffix(name, " SLA"), "SLA", "Over", "Pattern"},
+			Align:     []check.ColumnAlign{check.AlignLeft, check.AlignRight, check.AlignRight, check.AlignRight, check.AlignLeft},
+			Sensitive: []check.Sensitivity{check.SensitiveIdentifier, check.NotSensitive, check.NotSensitive, check.NotSensitive, check.SensitiveIdentifier},
+			Rows:      tableRows,
+		},
+	})
+}
@@ -0,0 +1,146 @@
+package vacuumsla_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/vacuumsla"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const hour = 3600
+
+type fakeQueries struct {
+	tables checktest.Result[[]db.VacuumSLATablesRow]
+}
+
+func (f fakeQueries) VacuumSLATables(context.Context) ([]db.VacuumSLATablesRow, error) {
+	return f.tables.Get()
+}
+
+func newChecker(cfg map[string]string, tables ...db.VacuumSLATablesRow) check.Checker {
+	return vacuumsla.New(fakeQueries{tables: checktest.Returns(tables)}, check.Config{"vacuum-sla": cfg})
+}
+
+func table(schema, name string, vacuumAge, analyzeAge int64) db.VacuumSLATablesRow {
+	return db.VacuumSLATablesRow{SchemaName: schema, TableName: name, VacuumAgeSeconds: vacuumAge, AnalyzeAgeSeconds: analyzeAge}
+}
+
+func TestVacuumSLA_Metadata(t *testing.T) {
+	t.Parallel()
+	checktest.RequireMetadata(t, vacuumsla.Metadata(), "vacuum-sla", check.CategoryVacuum)
+}
+
+func TestVacuumSLA_QueryError(t *testing.T) {
+	t.Parallel()
+	checker := vacuumsla.New(
+		fakeQueries{tables: checktest.Fails[[]db.VacuumSLATablesRow](errors.New("boom"))},
+		check.Config{"vacuum-sla": {"vacuum": "public.*=1d"}},
+	)
+	checktest.RequireError(t, checker)
+}
+
+func TestVacuumSLA_NotConfigured(t *testing.T) {
+	t.Parallel()
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, vacuumsla.New(fakeQueries{})))
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "No vacuum or analyze SLAs configured", finding.Details)
+}
+
+func TestVacuumSLA_WithinSLA(t *testing.T) {
+	t.Parallel()
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(
+		map[string]string{"vacuum": "public.*=1d"},
+		table("public", "orders", 2*hour, -1),
+		table("archive", "orders_2020", -1, -1),
+	)))
+	assert.Equal(t, "vacuum-sla", finding.ID)
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "All 1 table(s) with an SLA were vacuumed within it", finding.Details)
+}
+
+func TestVacuumSLA_OverSLA(t *testing.T) {
+	t.Parallel()
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(
+		map[string]string{"vacuum": "public.*=2d, public.events=6h, *.*=30d"},
+		table("public", "events", 9*hour, 0),
+		table("public", "orders", 5*24*hour, 0),
+		table("public", "users", 24*hour, 0),
+		table("archive", "orders_2020", -1, 0),
+		table("archive", "orders_2021", 10*24*hour, 0),
+	)))
+	assert.Equal(t, check.SeverityFail, finding.Severity)
+	assert.Equal(t, "3 of 5 table(s) with an SLA were not vacuumed within it", finding.Details)
+	assert.Equal(t, [][]string{
+		{"public.events", "9h", "6h", "1.5x", "public.events"},
+		{"public.orders", "5d", "2d", "2.5x", "public.*"},
+		{"archive.orders_2020", "never", "30d", "-", "*.*"},
+	}, checktest.Rows(finding))
+	require.NotNil(t, finding.Table)
+	assert.Equal(t, []check.Severity{check.SeverityWarn, check.SeverityFail, check.SeverityWarn}, []check.Severity{
+		finding.Table.Rows[0].Severity, finding.Table.Rows[1].Severity, finding.Table.Rows[2].Severity,
+	})
+	assert.Equal(t, check.SensitiveIdentifier, finding.Table.ColumnSensitivity(0))
+	assert.Equal(t, check.NotSensitive, finding.Table.ColumnSensitivity(1))
+}
+
+func TestVacuumSLA_Analyze(t *testing.T) {
+	t.Parallel()
+
+	report := checktest.Run(t, newChecker(
+		map[string]string{"vacuum": "orders=1d", "analyze": "orders=12"},
+		table("public", "orders", hour, 18*hour),
+	))
+	checktest.RequireSeverities(t, report, map[string]check.Severity{
+		"vacuum-sla":  check.SeverityOK,
+		"analyze-sla": check.SeverityWarn,
+	})
+	finding := checktest.Finding(t, report, "analyze-sla")
+	assert.Equal(t, [][]string{{"public.orders", "18h", "12h", "1.5x", "orders"}}, checktest.Rows(finding))
+	assert.Equal(t, "Last Analyze", finding.Table.Headers[1])
+}
+
+func TestVacuumSLA_FailFactor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		factor   string
+		expected check.Severity
+	}{
+		{name: "default", factor: "", expected: check.SeverityFail},
+		{name: "higher", factor: "4", expected: check.SeverityWarn},
+		{name: "invalid keeps default", factor: "0.5", expected: check.SeverityFail},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := map[string]string{"vacuum": "public.*=1h"}
+			if tt.factor != "" {
+				cfg["fail_factor"] = tt.factor
+			}
+			finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(cfg, table("public", "orders", 3*hour, 0))))
+			assert.Equal(t, tt.expected, finding.Severity)
+		})
+	}
+}
+
+func TestVacuumSLA_InvalidEntriesIgnored(t *testing.T) {
+	t.Parallel()
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(
+		map[string]string{"vacuum": "public.orders, public.orders=soon, =1h, public.orders=0, public.*=30min"},
+		table("public", "orders", 45*60, 0),
+	)))
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Equal(t, [][]string{{"public.orders", "45m", "30m", "1.5x", "public.*"}}, checktest.Rows(finding))
+}
@@ -0,0 +1,17 @@
+-- name: VacuumSLATables :many
+-- Seconds since each user table was last vacuumed and analyzed, manually or by
+-- autovacuum; -1 when it never was. The timestamps are cleared by a statistics
+-- reset.
+SELECT
+  s.schemaname::text AS schema_name
+  , s.relname::text AS table_name
+  , coalesce(
+    EXTRACT(EPOCH FROM now() - greatest(s.last_vacuum, s.last_autovacuum))::bigint
+    , -1
+  )::bigint AS vacuum_age_seconds
+  , coalesce(
+    EXTRACT(EPOCH FROM now() - greatest(s.last_analyze, s.last_autoanalyze))::bigint
+    , -1
+  )::bigint AS analyze_age_seconds
+FROM pg_stat_user_tables AS s
+ORDER BY s.schemaname, s.relname;
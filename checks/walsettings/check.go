@@ -0,0 +1,202 @@
+// Package walsettings implements a check for WAL and checkpoint settings that do not suit the cluster's WAL rate.
+package walsettings
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strconv"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type WalSettingsQueries interface {
+	WalSettings(context.Context) (db.WalSettingsRow, error)
+	WalStats(context.Context) (db.WalStatsRow, error)
+}
+
+type checker struct {
+	queries       WalSettingsQueries
+	busyMBPerHour int64 // WAL rate from which compression and buffers matter; default: 1024
+}
+
+// walSegment is the default WAL segment size, which wal_buffers autotunes up
+// to.
+const walSegment = 16 * 1024 * 1024
+
+// keepRedundantBytes is the wal_keep_size from which it is reported as
+// redundant with physical slots.
+const keepRedundantBytes = 1024 * 1024 * 1024
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryConfigs,
+		CheckID:     "wal-settings",
+		Name:        "WAL Settings",
+		Description: "Compares wal_compression, wal_buffers, max_wal_size, min_wal_size, and wal_keep_size with the cluster's WAL rate and replication slots",
+		Readme:      readme,
+		SQL:         querySQL,
+		ClusterWide: true,
+	}
+}
+
+func New(queries WalSettingsQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries:       queries,
+		busyMBPerHour: 1024,
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			if v, ok := myCfg["busy_wal_mb_per_hour"]; ok {
+				if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+					c.busyMBPerHour = n
+				}
+			}
+		}
+	}
+	return c
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	settings, err := c.queries.WalSettings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	// WAL bytes per hour, or 0 when pg_stat_wal is missing. note says when
+	// the rate is an estimate.
+	var rate float64
+	var note string
+	if settings.HasWalStats {
+		stats, err := c.queries.WalStats(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+		}
+		rate = stats.WalBytes / check.StatsWindow(stats.SecondsSinceReset, stats.UptimeSeconds) * 3600
+		note = check.StatsWindowNote(stats.SecondsSinceReset)
+	}
+	busy := rate >= float64(c.busyMBPerHour*1024*1024)
+	rateLabel := check.FormatBytes(int64(rate)) + "/h"
+
+	compression := row("wal_compression", settings.WalCompression)
+	if busy {
+		compression.expected = "on (lz4 or zstd)"
+		if settings.WalCompression == "off" {
+			compression.warn(fmt.Sprintf("Off with %s of WAL", rateLabel))
+		}
+	}
+
+	buffers := row("wal_buffers", check.FormatBytes(settings.WalBuffersBytes))
+	if busy {
+		buffers.expected = "≥ " + check.FormatBytes(walSegment)
+		if settings.WalBuffersBytes < walSegment {
+			buffers.warn(fmt.Sprintf("Below one WAL segment with %s of WAL", rateLabel))
+		}
+	}
+
+	// A checkpoint is requested once max_wal_size / (1 +
+	// checkpoint_completion_target) of WAL was written since the last one,
+	// so max_wal_size must hold that much more than a checkpoint_timeout's
+	// worth of WAL for checkpoints to stay timed.
+	maxSize := row("max_wal_size", check.FormatBytes(settings.MaxWalSizeBytes))
+	if rate > 0 && settings.CheckpointTimeoutSeconds > 0 {
+		timeout := float64(settings.CheckpointTimeoutSeconds)
+		factor := 1 + settings.CheckpointCompletionTarget
+		needed := rate * timeout / 3600 * factor
+		maxSize.expected = "≥ " + check.FormatBytes(int64(needed))
+		if float64(settings.MaxWalSizeBytes) < needed {
+			every := float64(settings.MaxWalSizeBytes) / factor / rate * 3600
+			maxSize.warn(fmt.Sprintf("Checkpoints about every %s, not every %s",
+				check.FormatDurationSec(int64(every)), check.FormatDurationSec(int64(timeout))))
+		}
+	}
+
+	minSize := row("min_wal_size", check.FormatBytes(settings.MinWalSizeBytes))
+	minSize.expected = "≤ max_wal_size"
+	if settings.MinWalSizeBytes > settings.MaxWalSizeBytes {
+		minSize.warn("Above max_wal_size")
+	}
+
+	keep := row("wal_keep_size", check.FormatBytes(settings.WalKeepSizeBytes))
+	if settings.PhysicalSlots > 0 {
+		keep.expected = "0 with slots"
+		if settings.WalKeepSizeBytes >= keepRedundantBytes {
+			keep.warn(fmt.Sprintf("Redundant with %d physical slot(s)", settings.PhysicalSlots))
+		}
+	}
+
+	rows := []*settingRow{compression, buffers, maxSize, minSize, keep}
+	severity := check.SeverityOK
+	var issues int
+	tableRows := make([]check.TableRow, 0, len(rows))
+	for _, r := range rows {
+		severity = max(severity, r.severity)
+		if r.severity != check.SeverityOK {
+			issues++
+		}
+		tableRows = append(tableRows, check.TableRow{
+			Cells:    []string{r.parameter, r.current, r.expected, r.status},
+			Severity: r.severity,
+		})
+	}
+
+	var details string
+	switch {
+	case issues > 0 && rate > 0:
+		details = fmt.Sprintf("%d WAL setting(s) do not suit about %s of WAL: expect frequent checkpoints, "+
+			"more full-page writes, or WAL kept without need", issues, rateLabel)
+	case issues > 0:
+		details = fmt.Sprintf("%d WAL setting(s) need attention", issues)
+	case rate > 0:
+		details = fmt.Sprintf("WAL settings suit about %s of WAL", rateLabel)
+	default:
+		details = "WAL settings are consistent; the WAL rate is unknown (pg_stat_wal needs PostgreSQL 14), " +
+			"so they were not compared with it"
+	}
+	if rate > 0 && note != "" {
+		details += ". The WAL rate is an estimate: " + note
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: severity,
+		Details:  details,
+		Table: &check.Table{
+			Headers: []string{"Parameter", "Current", "Expected", "Status"},
+			Rows:    tableRows,
+		},
+	})
+
+	return report, nil
+}
+
+// settingRow is one row of the report table.
+type settingRow struct {
+	parameter string
+	current   string
+	expected  string
+	status    string
+	severity  check.Severity
+}
+
+func row(parameter, current string) *settingRow {
+	return &settingRow{parameter: parameter, current: current, expected: "-", status: "OK", severity: check.SeverityOK}
+}
+
+func (r *settingRow) warn(status string) {
+	r.status, r.severity = status, check.SeverityWarn
+}
@@ -0,0 +1,183 @@
+package walsettings_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/walsettings"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeQueries struct {
+	settings checktest.Result[db.WalSettingsRow]
+	stats    checktest.Result[db.WalStatsRow]
+}
+
+func (f fakeQueries) WalSettings(context.Context) (db.WalSettingsRow, error) {
+	return f.settings.Get()
+}
+
+func (f fakeQueries) WalStats(context.Context) (db.WalStatsRow, error) {
+	return f.stats.Get()
+}
+
+const (
+	mib = 1024 * 1024
+	gib = 1024 * mib
+)
+
+func defaults() db.WalSettingsRow {
+	return db.WalSettingsRow{
+		WalCompression:             "off",
+		WalBuffersBytes:            4 * mib,
+		MaxWalSizeBytes:            gib,
+		MinWalSizeBytes:            80 * mib,
+		CheckpointTimeoutSeconds:   300,
+		CheckpointCompletionTarget: 0.9,
+		HasWalStats:                true,
+	}
+}
+
+// perHour is a day of pg_stat_wal statistics at the given WAL rate.
+func perHour(bytes float64) db.WalStatsRow {
+	return db.WalStatsRow{WalBytes: bytes * 24, SecondsSinceReset: pgtype.Int8{Int64: 24 * 3600, Valid: true}}
+}
+
+func run(t *testing.T, settings db.WalSettingsRow, stats db.WalStatsRow, cfg ...check.Config) check.Finding {
+	t.Helper()
+	return checktest.OnlyFinding(t, checktest.Run(t, walsettings.New(fakeQueries{
+		settings: checktest.Returns(settings),
+		stats:    checktest.Returns(stats),
+	}, cfg...)))
+}
+
+func TestWalSettings_Metadata(t *testing.T) {
+	t.Parallel()
+	checktest.RequireMetadata(t, walsettings.Metadata(), "wal-settings", check.CategoryConfigs)
+}
+
+func TestWalSettings_QueryErrors(t *testing.T) {
+	t.Parallel()
+
+	checktest.RequireError(t, walsettings.New(fakeQueries{settings: checktest.Fails[db.WalSettingsRow](errors.New("boom"))}))
+	checktest.RequireError(t, walsettings.New(fakeQueries{
+		settings: checktest.Returns(defaults()),
+		stats:    checktest.Fails[db.WalStatsRow](errors.New("boom")),
+	}))
+}
+
+func TestWalSettings_QuietCluster(t *testing.T) {
+	t.Parallel()
+
+	finding := run(t, defaults(), perHour(50*mib))
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "WAL settings suit about 50.0MiB/h of WAL", finding.Details)
+	assert.Equal(t, [][]string{
+		{"wal_compression", "off", "-", "OK"},
+		{"wal_buffers", "4.0MiB", "-", "OK"},
+		{"max_wal_size", "1.0GiB", "≥ 7.9MiB", "OK"},
+		{"min_wal_size", "80.0MiB", "≤ max_wal_size", "OK"},
+		{"wal_keep_size", "0B", "-", "OK"},
+	}, checktest.Rows(finding))
+}
+
+func TestWalSettings_BusyCluster(t *testing.T) {
+	t.Parallel()
+
+	// 24GiB/h is 2GiB per 5 minute checkpoint_timeout: max_wal_size must be
+	// 3.8GiB for checkpoints to stay timed.
+	finding := run(t, defaults(), perHour(24*gib))
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	assert.Contains(t, finding.Details, "3 WAL setting(s) do not suit about 24.0GiB/h of WAL")
+	assert.Equal(t, [][]string{
+		{"wal_compression", "off", "on (lz4 or zstd)", "Off with 24.0GiB/h of WAL"},
+		{"wal_buffers", "4.0MiB", "≥ 16.0MiB", "Below one WAL segment with 24.0GiB/h of WAL"},
+		{"max_wal_size", "1.0GiB", "≥ 3.8GiB", "Checkpoints about every 1m, not every 5m"},
+		{"min_wal_size", "80.0MiB", "≤ max_wal_size", "OK"},
+		{"wal_keep_size", "0B", "-", "OK"},
+	}, checktest.Rows(finding))
+
+	tuned := defaults()
+	tuned.WalCompression, tuned.WalBuffersBytes, tuned.MaxWalSizeBytes = "lz4", 64*mib, 16*gib
+	assert.Equal(t, check.SeverityOK, run(t, tuned, perHour(24*gib)).Severity)
+
+	// A higher threshold leaves compression and buffers alone.
+	tuned.WalCompression, tuned.WalBuffersBytes = "off", 4*mib
+	finding = run(t, tuned, perHour(24*gib), check.Config{"wal-settings": {"busy_wal_mb_per_hour": "50000"}})
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+}
+
+func TestWalSettings_UnknownRate(t *testing.T) {
+	t.Parallel()
+
+	noStats := defaults()
+	noStats.HasWalStats = false
+
+	// The stats query is never called without pg_stat_wal.
+	report := checktest.Run(t, walsettings.New(fakeQueries{
+		settings: checktest.Returns(noStats),
+		stats:    checktest.Fails[db.WalStatsRow](errors.New("unexpected")),
+	}))
+	finding := checktest.OnlyFinding(t, report)
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Contains(t, finding.Details, "the WAL rate is unknown")
+	assert.Equal(t, []string{"max_wal_size", "1.0GiB", "-", "OK"}, checktest.Rows(finding)[2])
+}
+
+func TestWalSettings_EstimatedRate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		stats db.WalStatsRow
+		rate  string
+		note  string
+	}{
+		{
+			// Ten minutes of WAL are rated over an hour.
+			name:  "statistics reset recently",
+			stats: db.WalStatsRow{WalBytes: 10 * gib, SecondsSinceReset: pgtype.Int8{Int64: 600, Valid: true}},
+			rate:  "10.0GiB/h",
+			note:  "The WAL rate is an estimate: statistics were reset 10m ago",
+		},
+		{
+			name:  "statistics never reset",
+			stats: db.WalStatsRow{WalBytes: 20 * gib, UptimeSeconds: 2 * 3600},
+			rate:  "10.0GiB/h",
+			note:  "The WAL rate is an estimate: statistics were never reset",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			finding := run(t, defaults(), tt.stats)
+			assert.Equal(t, check.SeverityWarn, finding.Severity)
+			assert.Contains(t, finding.Details, "do not suit about "+tt.rate+" of WAL")
+			assert.Contains(t, finding.Details, tt.note)
+		})
+	}
+}
+
+func TestWalSettings_SizesAndSlots(t *testing.T) {
+	t.Parallel()
+
+	settings := defaults()
+	settings.MinWalSizeBytes = 2 * gib
+	settings.WalKeepSizeBytes = 50 * gib
+	settings.PhysicalSlots = 2
+	finding := run(t, settings, perHour(50*mib))
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+	rows := checktest.Rows(finding)
+	assert.Equal(t, []string{"min_wal_size", "2.0GiB", "≤ max_wal_size", "Above max_wal_size"}, rows[3])
+	assert.Equal(t, []string{"wal_keep_size", "50.0GiB", "0 with slots", "Redundant with 2 physical slot(s)"}, rows[4])
+
+	// Without slots, wal_keep_size is what keeps WAL for standbys.
+	settings.PhysicalSlots = 0
+	assert.Equal(t, []string{"wal_keep_size", "50.0GiB", "-", "OK"}, checktest.Rows(run(t, settings, perHour(50*mib)))[4])
+}
@@ -0,0 +1,23 @@
+-- name: WalSettings :one
+-- WAL and checkpoint settings, with sizes in bytes and checkpoint_timeout in
+-- seconds, the number of physical replication slots, and whether
+-- pg_stat_wal (PostgreSQL 14+) exists to measure the WAL rate.
+SELECT
+  current_setting('wal_compression')::text AS wal_compression
+  , pg_catalog.pg_size_bytes(current_setting('wal_buffers'))::bigint AS wal_buffers_bytes
+  , pg_catalog.pg_size_bytes(current_setting('max_wal_size'))::bigint AS max_wal_size_bytes
+  , pg_catalog.pg_size_bytes(current_setting('min_wal_size'))::bigint AS min_wal_size_bytes
+  , pg_catalog.pg_size_bytes(current_setting('wal_keep_size'))::bigint AS wal_keep_size_bytes
+  , (SELECT s.setting::int FROM pg_catalog.pg_settings AS s WHERE s.name = 'checkpoint_timeout') AS checkpoint_timeout_seconds
+  , current_setting('checkpoint_completion_target')::float8 AS checkpoint_completion_target
+  , (SELECT count(*)::int FROM pg_catalog.pg_replication_slots WHERE slot_type = 'physical') AS physical_slots
+  , to_regclass('pg_catalog.pg_stat_wal') IS NOT NULL AS has_wal_stats;
+
+-- name: WalStats :one
+-- WAL written since the statistics were reset, how long ago that was in
+-- seconds, NULL when they never were, and the server's uptime.
+SELECT
+  w.wal_bytes::float8 AS wal_bytes
+  , extract(EPOCH FROM now() - w.stats_reset)::bigint AS seconds_since_reset
+  , extract(EPOCH FROM now() - pg_postmaster_start_time())::bigint AS uptime_seconds
+FROM pg_catalog.pg_stat_wal AS w;
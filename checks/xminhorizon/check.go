@@ -0,0 +1,171 @@
+// Package xminhorizon implements a check for what holds back the xmin horizon vacuum cleans up to.
+package xminhorizon
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strconv"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+)
+
+//go:embed query.sql
+var querySQL string
+
+//go:embed README.md
+var readme string
+
+type XminHorizonQueries interface {
+	XminHorizonHolders(context.Context) ([]db.XminHorizonHoldersRow, error)
+}
+
+type checker struct {
+	queries     XminHorizonQueries
+	xidWarn     int64 // transactions; default: 10M
+	xidFail     int64 // default: 50M
+	minutesWarn int64 // default: 60
+	minutesFail int64 // default: 360
+}
+
+func Metadata() check.Metadata {
+	return check.Metadata{
+		Category:    check.CategoryVacuum,
+		CheckID:     "xmin-horizon",
+		Name:        "Xmin Horizon",
+		Description: "Finds the sessions, standbys, replication slots, and prepared transactions holding back how far vacuum can clean up",
+		Readme:      readme,
+		SQL:         querySQL,
+		ClusterWide: true,
+	}
+}
+
+func New(queries XminHorizonQueries, cfg ...check.Config) check.Checker {
+	c := &checker{
+		queries:     queries,
+		xidWarn:     10_000_000,
+		xidFail:     50_000_000,
+		minutesWarn: 60,
+		minutesFail: 360,
+	}
+	if len(cfg) > 0 && cfg[0] != nil {
+		if myCfg, ok := cfg[0][Metadata().CheckID]; ok {
+			parsePositive(myCfg, "xid_age_warn", &c.xidWarn)
+			parsePositive(myCfg, "xid_age_fail", &c.xidFail)
+			parsePositive(myCfg, "age_minutes_warn", &c.minutesWarn)
+			parsePositive(myCfg, "age_minutes_fail", &c.minutesFail)
+		}
+	}
+	return c
+}
+
+// parsePositive sets *dst to the value of key, when it is a positive integer.
+func parsePositive(cfg map[string]string, key string, dst *int64) {
+	if v, ok := cfg[key]; ok {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+			*dst = n
+		}
+	}
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return Metadata()
+}
+
+// severity rates a holder by the higher of its age in transactions and, when
+// known, in time.
+func (c *checker) severity(row db.XminHorizonHoldersRow) check.Severity {
+	minutes := row.AgeSeconds / 60
+	switch {
+	case row.XminAge >= c.xidFail, row.AgeSeconds >= 0 && minutes >= c.minutesFail:
+		return check.SeverityFail
+	case row.XminAge >= c.xidWarn, row.AgeSeconds >= 0 && minutes >= c.minutesWarn:
+		return check.SeverityWarn
+	default:
+		return check.SeverityOK
+	}
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(Metadata())
+
+	rows, err := c.queries.XminHorizonHolders(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	if len(rows) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Tags:     []string{check.TagXminHorizon},
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  "No session, standby, replication slot, or prepared transaction holds back the xmin horizon",
+		})
+		return report, nil
+	}
+
+	// Rows come oldest first, so the first one is the horizon.
+	oldest := rows[0]
+	severity := check.SeverityOK
+	var tableRows []check.TableRow
+	for _, row := range rows {
+		rowSeverity := c.severity(row)
+		if rowSeverity == check.SeverityOK {
+			continue
+		}
+		severity = max(severity, rowSeverity)
+		duration := "-"
+		if row.AgeSeconds >= 0 {
+			duration = check.FormatDurationSec(row.AgeSeconds)
+		}
+		database := row.DatabaseName
+		if database == "" {
+			database = "-"
+		}
+		tableRows = append(tableRows, check.TableRow{
+			Cells: []string{
+				row.Source,
+				row.Holder,
+				database,
+				check.FormatNumber(row.XminAge),
+				duration,
+				row.Detail,
+			},
+			Severity: rowSeverity,
+		})
+	}
+
+	if len(tableRows) == 0 {
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Tags:     []string{check.TagXminHorizon},
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  fmt.Sprintf("The xmin horizon is %s transactions old, held by a %s", check.FormatNumber(oldest.XminAge), oldest.Source),
+		})
+		return report, nil
+	}
+
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Tags:     []string{check.TagXminHorizon},
+		Name:     report.Name,
+		Severity: severity,
+		Details: fmt.Sprintf("The xmin horizon is %s transactions old, held by a %s. "+
+			"Vacuum cannot remove rows deleted or updated after it, so dead tuples and bloat accumulate until it moves on",
+			check.FormatNumber(oldest.XminAge), oldest.Source),
+		Table: &check.Table{
+			Headers: []string{"Source", "Holder", "Database", "XID Age", "Duration", "Detail"},
+			Align:   []check.ColumnAlign{check.AlignLeft, check.AlignLeft, check.AlignLeft, check.AlignRight, check.AlignRight, check.AlignLeft},
+			Sensitive: []check.Sensitivity{
+				check.NotSensitive, check.SensitiveIdentifier, check.SensitiveIdentifier,
+				check.NotSensitive, check.NotSensitive, check.SensitiveIdentifier,
+			},
+			Rows: tableRows,
+		},
+	})
+
+	return report, nil
+}
@@ -0,0 +1,95 @@
+package xminhorizon_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/xminhorizon"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeQueries struct {
+	holders checktest.Result[[]db.XminHorizonHoldersRow]
+}
+
+func (f fakeQueries) XminHorizonHolders(context.Context) ([]db.XminHorizonHoldersRow, error) {
+	return f.holders.Get()
+}
+
+func newChecker(cfg check.Config, holders ...db.XminHorizonHoldersRow) check.Checker {
+	return xminhorizon.New(fakeQueries{holders: checktest.Returns(holders)}, cfg)
+}
+
+func TestXminHorizon_Metadata(t *testing.T) {
+	t.Parallel()
+	m := xminhorizon.Metadata()
+	checktest.RequireMetadata(t, m, "xmin-horizon", check.CategoryVacuum)
+	assert.True(t, m.ClusterWide)
+}
+
+func TestXminHorizon_QueryError(t *testing.T) {
+	t.Parallel()
+	checktest.RequireError(t, xminhorizon.New(fakeQueries{holders: checktest.Fails[[]db.XminHorizonHoldersRow](errors.New("boom"))}))
+}
+
+func TestXminHorizon_NoHolders(t *testing.T) {
+	t.Parallel()
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(nil)))
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Nil(t, finding.Table)
+}
+
+func TestXminHorizon_YoungHolders(t *testing.T) {
+	t.Parallel()
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(nil,
+		db.XminHorizonHoldersRow{Source: "session", Holder: "pid 42", DatabaseName: "app", XminAge: 1200, AgeSeconds: 3},
+	)))
+	assert.Equal(t, check.SeverityOK, finding.Severity)
+	assert.Equal(t, "The xmin horizon is 1.2K transactions old, held by a session", finding.Details)
+	assert.Nil(t, finding.Table)
+}
+
+func TestXminHorizon_Holders(t *testing.T) {
+	t.Parallel()
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(nil,
+		db.XminHorizonHoldersRow{Source: "replication slot", Holder: "etl", XminAge: 60_000_000, AgeSeconds: -1, Detail: "physical, inactive"},
+		db.XminHorizonHoldersRow{Source: "prepared transaction", Holder: "tx-1", DatabaseName: "app", XminAge: 12_000_000, AgeSeconds: 600, Detail: "app"},
+		db.XminHorizonHoldersRow{Source: "session", Holder: "pid 42", DatabaseName: "app", XminAge: 300, AgeSeconds: 2 * 3600, Detail: "app, psql, idle in transaction"},
+		db.XminHorizonHoldersRow{Source: "session", Holder: "pid 43", DatabaseName: "app", XminAge: 100, AgeSeconds: 5, Detail: "app, active"},
+	)))
+	assert.Equal(t, check.SeverityFail, finding.Severity)
+	assert.Contains(t, finding.Details, "The xmin horizon is 60.0M transactions old, held by a replication slot.")
+	assert.NotContains(t, finding.Details, "etl", "holder names stay in the table, where --redact strips them")
+	assert.Equal(t, [][]string{
+		{"replication slot", "etl", "-", "60.0M", "-", "physical, inactive"},
+		{"prepared transaction", "tx-1", "app", "12.0M", "10m", "app"},
+		{"session", "pid 42", "app", "300", "2h", "app, psql, idle in transaction"},
+	}, checktest.Rows(finding))
+	require.NotNil(t, finding.Table)
+	assert.Equal(t, check.SeverityFail, finding.Table.Rows[0].Severity)
+	assert.Equal(t, check.SeverityWarn, finding.Table.Rows[1].Severity)
+	assert.Equal(t, check.SeverityWarn, finding.Table.Rows[2].Severity)
+	assert.Equal(t, check.SensitiveIdentifier, finding.Table.ColumnSensitivity(1))
+}
+
+func TestXminHorizon_ConfiguredThresholds(t *testing.T) {
+	t.Parallel()
+
+	session := db.XminHorizonHoldersRow{Source: "session", Holder: "pid 42", XminAge: 2_000_000, AgeSeconds: 20 * 60}
+	cfg := check.Config{"xmin-horizon": {"xid_age_warn": "1000000", "age_minutes_fail": "15", "xid_age_fail": "nope"}}
+
+	finding := checktest.OnlyFinding(t, checktest.Run(t, newChecker(cfg, session)))
+	assert.Equal(t, check.SeverityFail, finding.Severity, "20 minutes is past age_minutes_fail")
+
+	cfg = check.Config{"xmin-horizon": {"xid_age_warn": "1000000"}}
+	finding = checktest.OnlyFinding(t, checktest.Run(t, newChecker(cfg, session)))
+	assert.Equal(t, check.SeverityWarn, finding.Severity)
+}
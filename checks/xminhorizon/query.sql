@@ -0,0 +1,55 @@
+-- name: XminHorizonHolders :many
+-- What holds back the xmin horizon, the oldest transaction whose view of the
+-- data vacuum must preserve: open transactions and snapshots of sessions,
+-- hot_standby_feedback of standbys (on their walsender), replication slots,
+-- and prepared transactions. Vacuum processes are left out because other
+-- vacuums ignore their snapshots, and so is pgdoctor's own session. Slots
+-- record no start time, so their age_seconds is -1.
+WITH holders AS (
+  SELECT
+    CASE WHEN a.backend_type = 'walsender' THEN 'standby' ELSE 'session' END AS source
+    , ('pid ' || a.pid) AS holder
+    , coalesce(a.datname::text, '') AS database_name
+    , greatest(age(a.backend_xmin), age(a.backend_xid)) AS xmin_age
+    , coalesce(EXTRACT(EPOCH FROM now() - a.xact_start)::bigint, -1) AS age_seconds
+    , concat_ws(', ', a.usename, nullif(a.application_name, ''), a.state) AS detail
+  FROM pg_stat_activity AS a
+  WHERE
+    (a.backend_xmin IS NOT NULL OR a.backend_xid IS NOT NULL)
+    AND a.backend_type <> 'autovacuum worker'
+    AND a.pid <> pg_backend_pid()
+
+  UNION ALL
+
+  SELECT
+    'replication slot' AS source
+    , s.slot_name::text AS holder
+    , coalesce(s.database::text, '') AS database_name
+    , greatest(age(s.xmin), age(s.catalog_xmin)) AS xmin_age
+    , -1 AS age_seconds
+    , concat_ws(', ', s.slot_type, CASE WHEN s.active THEN 'active' ELSE 'inactive' END) AS detail
+  FROM pg_replication_slots AS s
+  WHERE s.xmin IS NOT NULL OR s.catalog_xmin IS NOT NULL
+
+  UNION ALL
+
+  SELECT
+    'prepared transaction' AS source
+    , p.gid AS holder
+    , p.database::text AS database_name
+    , age(p.transaction) AS xmin_age
+    , EXTRACT(EPOCH FROM now() - p.prepared)::bigint AS age_seconds
+    , p.owner::text AS detail
+  FROM pg_prepared_xacts AS p
+)
+
+SELECT
+  h.source::text AS source
+  , h.holder::text AS holder
+  , h.database_name::text AS database_name
+  , h.xmin_age::bigint AS xmin_age
+  , h.age_seconds::bigint AS age_seconds
+  , h.detail::text AS detail
+FROM holders AS h
+ORDER BY h.xmin_age DESC, h.age_seconds DESC
+LIMIT 20;
@@ -0,0 +1,33 @@
+package db
+
+import "context"
+
+// Explain returns the plan PostgreSQL would use for query, one line per
+// element. Plain EXPLAIN plans the statement without executing it. Set generic
+// for statements with $n placeholders, as pg_stat_statements normalizes them;
+// EXPLAIN (GENERIC_PLAN) requires PostgreSQL 16+.
+//
+// Hand-written because sqlc cannot generate a query whose text is dynamic.
+func (q *Queries) Explain(ctx context.Context, query string, generic bool) ([]string, error) {
+	stmt := "EXPLAIN " + query
+	if generic {
+		stmt = "EXPLAIN (GENERIC_PLAN) " + query
+	}
+	rows, err := q.db.Query(ctx, stmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var lines []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
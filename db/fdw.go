@@ -0,0 +1,13 @@
+package db
+
+import "context"
+
+// ProbeForeignTable reads at most one row from table, a quoted foreign table
+// name, which makes its wrapper open a connection to the remote server. It
+// returns the error the remote side reports, if any.
+//
+// Hand-written because sqlc cannot generate a query whose text is dynamic.
+func (q *Queries) ProbeForeignTable(ctx context.Context, table string) error {
+	_, err := q.db.Exec(ctx, "SELECT 1 FROM "+table+" LIMIT 1")
+	return err
+}
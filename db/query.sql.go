@@ -11,16 +11,251 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const advisoryLocks = `-- name: AdvisoryLocks :many
+SELECT
+  l.pid
+  , CASE
+    WHEN l.objsubid = 1 THEN ((l.classid::bigint << 32) | l.objid::bigint)::text
+    ELSE l.classid::text || ',' || l.objid::text
+  END::text AS lock_key
+  , l.mode::text AS lock_mode
+  , coalesce(a.usename::text, '')::text AS username
+  , coalesce(a.application_name, '')::text AS application_name
+  , coalesce(a.state, '')::text AS state
+  , extract(EPOCH FROM (now() - a.backend_start))::bigint AS session_age_seconds
+  , extract(EPOCH FROM (now() - a.state_change))::bigint AS state_age_seconds
+FROM pg_locks AS l
+INNER JOIN pg_stat_activity AS a ON l.pid = a.pid
+WHERE
+  l.locktype = 'advisory'
+  AND l.granted
+  AND l.database = (SELECT oid FROM pg_database WHERE datname = current_database())
+ORDER BY session_age_seconds DESC, l.pid
+`
+
+type AdvisoryLocksRow struct {
+	Pid               pgtype.Int4
+	LockKey           string
+	LockMode          string
+	Username          string
+	ApplicationName   string
+	State             string
+	SessionAgeSeconds pgtype.Int8
+	StateAgeSeconds   pgtype.Int8
+}
+
+// Granted advisory locks with the holding session's age and state.
+// PostgreSQL does not record when a lock was acquired, so the session age is
+// the upper bound on how long the lock may have been held. Keys taken as a
+// single bigint (objsubid = 1) are reassembled from classid/objid; two-int4
+// keys (objsubid = 2) are shown as "classid,objid".
+func (q *Queries) AdvisoryLocks(ctx context.Context) ([]AdvisoryLocksRow, error) {
+	rows, err := q.db.Query(ctx, advisoryLocks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AdvisoryLocksRow
+	for rows.Next() {
+		var i AdvisoryLocksRow
+		if err := rows.Scan(
+			&i.Pid,
+			&i.LockKey,
+			&i.LockMode,
+			&i.Username,
+			&i.ApplicationName,
+			&i.State,
+			&i.SessionAgeSeconds,
+			&i.StateAgeSeconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const autovacuumMaxWorkers = `-- name: AutovacuumMaxWorkers :one
+SELECT current_setting('autovacuum_max_workers')::int AS max_workers
+`
+
+// The number of autovacuum workers the launcher may run at once.
+func (q *Queries) AutovacuumMaxWorkers(ctx context.Context) (int32, error) {
+	row := q.db.QueryRow(ctx, autovacuumMaxWorkers)
+	var max_workers int32
+	err := row.Scan(&max_workers)
+	return max_workers, err
+}
+
+const autovacuumWorkers = `-- name: AutovacuumWorkers :many
+SELECT
+  a.pid
+  , coalesce(a.datname, '')::text AS database_name
+  , regexp_replace(coalesce(a.query, ''), '^autovacuum: ', '')::text AS activity
+  , coalesce(a.query LIKE '%(to prevent wraparound)', false)::bool AS wraparound
+  , coalesce(EXTRACT(EPOCH FROM now() - a.xact_start), 0)::bigint AS running_seconds
+  , p.phase::text AS phase
+  , CASE
+    WHEN p.heap_blks_total > 0
+      THEN (p.heap_blks_scanned * 100.0 / p.heap_blks_total)::float8
+  END AS scanned_percent
+FROM pg_stat_activity AS a
+LEFT JOIN pg_stat_progress_vacuum AS p ON p.pid = a.pid
+WHERE a.backend_type = 'autovacuum worker'
+ORDER BY running_seconds DESC
+`
+
+type AutovacuumWorkersRow struct {
+	Pid            int32
+	DatabaseName   string
+	Activity       string
+	Wraparound     bool
+	RunningSeconds int64
+	Phase          pgtype.Text
+	ScannedPercent pgtype.Float8
+}
+
+// Autovacuum workers running now, in every database. The query text names
+// the table and whether the run prevents wraparound; for backends of other
+// roles it is only visible with pg_read_all_stats.
+func (q *Queries) AutovacuumWorkers(ctx context.Context) ([]AutovacuumWorkersRow, error) {
+	rows, err := q.db.Query(ctx, autovacuumWorkers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AutovacuumWorkersRow
+	for rows.Next() {
+		var i AutovacuumWorkersRow
+		if err := rows.Scan(
+			&i.Pid,
+			&i.DatabaseName,
+			&i.Activity,
+			&i.Wraparound,
+			&i.RunningSeconds,
+			&i.Phase,
+			&i.ScannedPercent,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const brinCandidates = `-- name: BrinCandidates :many
+SELECT
+  n.nspname::text AS schema_name
+  , t.relname::text AS table_name
+  , i.relname::text AS index_name
+  , a.attname::text AS column_name
+  , pg_catalog.pg_relation_size(i.oid)::bigint AS index_size_bytes
+  , t.relpages::bigint AS table_pages
+  , coalesce(s.n_tup_ins, 0)::bigint AS inserts
+  , coalesce(s.n_tup_upd, 0)::bigint AS updates
+  , coalesce(s.n_tup_del, 0)::bigint AS deletes
+  , coalesce(st.correlation, 0)::double precision AS correlation
+FROM pg_catalog.pg_index AS idx
+INNER JOIN pg_catalog.pg_class AS i ON idx.indexrelid = i.oid
+INNER JOIN pg_catalog.pg_class AS t ON idx.indrelid = t.oid
+INNER JOIN pg_catalog.pg_namespace AS n ON t.relnamespace = n.oid
+INNER JOIN pg_catalog.pg_am AS am ON i.relam = am.oid
+INNER JOIN pg_catalog.pg_attribute AS a ON a.attrelid = t.oid AND a.attnum = idx.indkey[0]
+INNER JOIN pg_catalog.pg_stat_user_tables AS s ON s.relid = t.oid
+LEFT JOIN LATERAL (
+  -- Partitioned tables only have inherited statistics.
+  SELECT ps.correlation
+  FROM pg_catalog.pg_stats AS ps
+  WHERE ps.schemaname = n.nspname AND ps.tablename = t.relname AND ps.attname = a.attname
+  ORDER BY ps.inherited
+  LIMIT 1
+) AS st ON TRUE
+WHERE
+  am.amname = 'btree'
+  AND t.relkind = 'r'
+  AND idx.indnatts = 1
+  AND NOT idx.indisunique
+  AND idx.indisvalid
+  AND idx.indexprs IS NULL
+  AND idx.indpred IS NULL
+  AND a.atttypid = ANY('{timestamp,timestamptz,date,int2,int4,int8}'::regtype [])
+  AND pg_catalog.pg_relation_size(i.oid) >= $1::bigint
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
+ORDER BY pg_catalog.pg_relation_size(i.oid) DESC
+`
+
+type BrinCandidatesRow struct {
+	SchemaName     string
+	TableName      string
+	IndexName      string
+	ColumnName     string
+	IndexSizeBytes int64
+	TablePages     int64
+	Inserts        int64
+	Updates        int64
+	Deletes        int64
+	Correlation    float64
+}
+
+// Single-column btree indexes of at least min_index_bytes on timestamp, date,
+// and integer columns, with the table's size in pages, its insert, update,
+// and delete counts since the statistics were reset, and the column's
+// physical order correlation (0 without statistics). Unique and partial
+// indexes are left out: BRIN can replace neither.
+func (q *Queries) BrinCandidates(ctx context.Context, minIndexBytes int64) ([]BrinCandidatesRow, error) {
+	rows, err := q.db.Query(ctx, brinCandidates, minIndexBytes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []BrinCandidatesRow
+	for rows.Next() {
+		var i BrinCandidatesRow
+		if err := rows.Scan(
+			&i.SchemaName,
+			&i.TableName,
+			&i.IndexName,
+			&i.ColumnName,
+			&i.IndexSizeBytes,
+			&i.TablePages,
+			&i.Inserts,
+			&i.Updates,
+			&i.Deletes,
+			&i.Correlation,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const brokenIndexes = `-- name: BrokenIndexes :many
 SELECT
   n.nspname::text AS schema_name
   , tbl.relname::text AS table_name
   , idx.relname::text AS index_name
   , (idx.relname ~ '_cc(new|old)[0-9]*$') AS is_leftover
+  , i.indisunique AS is_unique
+  , COALESCE(con.contype::text, '')::text AS constraint_type
 FROM pg_index AS i
 INNER JOIN pg_class AS idx ON i.indexrelid = idx.oid
 INNER JOIN pg_class AS tbl ON i.indrelid = tbl.oid
 INNER JOIN pg_namespace AS n ON tbl.relnamespace = n.oid
+LEFT JOIN pg_constraint AS con
+  ON
+    i.indexrelid = con.conindid
+    AND i.indrelid = con.conrelid
+    AND con.contype IN ('p', 'u', 'x')
 WHERE NOT i.indisvalid
   AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
   AND NOT EXISTS (
@@ -31,14 +266,19 @@ ORDER BY is_leftover, n.nspname, tbl.relname, idx.relname
 `
 
 type BrokenIndexesRow struct {
-	SchemaName string
-	TableName  string
-	IndexName  string
-	IsLeftover bool
+	SchemaName     string
+	TableName      string
+	IndexName      string
+	IsLeftover     bool
+	IsUnique       bool
+	ConstraintType string
 }
 
 // Invalid indexes, flagging _ccnew/_ccold REINDEX CONCURRENTLY leftovers via
-// is_leftover. Excludes indexes a concurrent build is still working on (their
+// is_leftover, unique indexes via is_unique, and the type of primary key
+// (p), unique (u), or exclusion (x) constraint an index backs via
+// constraint_type (empty if none).
+// Excludes indexes a concurrent build is still working on (their
 // index_relid is in pg_stat_progress_create_index): in flight, not broken.
 func (q *Queries) BrokenIndexes(ctx context.Context) ([]BrokenIndexesRow, error) {
 	rows, err := q.db.Query(ctx, brokenIndexes)
@@ -54,6 +294,191 @@ func (q *Queries) BrokenIndexes(ctx context.Context) ([]BrokenIndexesRow, error)
 			&i.TableName,
 			&i.IndexName,
 			&i.IsLeftover,
+			&i.IsUnique,
+			&i.ConstraintType,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const catalogChurn = `-- name: CatalogChurn :many
+SELECT
+  s.relname::text AS catalog_name
+  , pg_total_relation_size(s.relid) AS total_bytes
+  , COALESCE(s.n_live_tup, 0)::bigint AS live_rows
+  , COALESCE(s.n_dead_tup, 0)::bigint AS dead_rows
+  , (COALESCE(s.n_tup_ins, 0) + COALESCE(s.n_tup_del, 0))::bigint AS changed_rows
+FROM pg_stat_sys_tables AS s
+WHERE
+  s.schemaname = 'pg_catalog'
+  AND s.relname IN ('pg_class', 'pg_attribute', 'pg_type', 'pg_depend')
+ORDER BY pg_total_relation_size(s.relid) DESC
+`
+
+type CatalogChurnRow struct {
+	CatalogName string
+	TotalBytes  int64
+	LiveRows    int64
+	DeadRows    int64
+	ChangedRows int64
+}
+
+// Size and row turnover of the catalogs every CREATE and DROP writes to.
+// Inserted and deleted rows are counted since the last statistics reset.
+func (q *Queries) CatalogChurn(ctx context.Context) ([]CatalogChurnRow, error) {
+	rows, err := q.db.Query(ctx, catalogChurn)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CatalogChurnRow
+	for rows.Next() {
+		var i CatalogChurnRow
+		if err := rows.Scan(
+			&i.CatalogName,
+			&i.TotalBytes,
+			&i.LiveRows,
+			&i.DeadRows,
+			&i.ChangedRows,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const collationVersionMismatches = `-- name: CollationVersionMismatches :many
+SELECT
+  (n.nspname || '.' || c.collname)::text AS collation_name
+  , CASE c.collprovider
+    WHEN 'c' THEN 'libc'
+    WHEN 'i' THEN 'icu'
+    WHEN 'b' THEN 'builtin'
+    ELSE 'default'
+  END::text AS provider
+  , c.collversion::text AS recorded_version
+  , pg_collation_actual_version(c.oid)::text AS actual_version
+  , (
+    SELECT count(*)
+    FROM pg_index AS i
+    WHERE c.oid = ANY(i.indcollation::oid [])
+  ) AS index_count
+FROM pg_collation AS c
+INNER JOIN pg_namespace AS n ON c.collnamespace = n.oid
+WHERE
+  c.collversion IS NOT NULL
+  AND pg_collation_actual_version(c.oid) IS NOT NULL
+  AND c.collversion <> pg_collation_actual_version(c.oid)
+ORDER BY index_count DESC, collation_name
+`
+
+type CollationVersionMismatchesRow struct {
+	CollationName   string
+	Provider        string
+	RecordedVersion string
+	ActualVersion   string
+	IndexCount      int64
+}
+
+// Collations whose recorded version differs from the version the provider
+// (glibc or ICU) reports now, with the number of indexes that sort by them.
+// Collations never used by an index are included: new indexes would be built
+// with the new ordering while queries compare against old data.
+func (q *Queries) CollationVersionMismatches(ctx context.Context) ([]CollationVersionMismatchesRow, error) {
+	rows, err := q.db.Query(ctx, collationVersionMismatches)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CollationVersionMismatchesRow
+	for rows.Next() {
+		var i CollationVersionMismatchesRow
+		if err := rows.Scan(
+			&i.CollationName,
+			&i.Provider,
+			&i.RecordedVersion,
+			&i.ActualVersion,
+			&i.IndexCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const commitTimestamp = `-- name: CommitTimestamp :one
+SELECT
+  current_setting('track_commit_timestamp')::text AS track_commit_timestamp
+  , (SELECT count(*)::int FROM pg_catalog.pg_subscription) AS subscriptions
+  , (SELECT count(*)::int FROM pg_catalog.pg_subscription WHERE subenabled) AS enabled_subscriptions
+`
+
+type CommitTimestampRow struct {
+	TrackCommitTimestamp string
+	Subscriptions        int32
+	EnabledSubscriptions int32
+}
+
+// track_commit_timestamp and the logical replication subscriptions on the
+// cluster. The setting is cluster-wide and pg_subscription is shared, so
+// subscriptions in every database count.
+func (q *Queries) CommitTimestamp(ctx context.Context) (CommitTimestampRow, error) {
+	row := q.db.QueryRow(ctx, commitTimestamp)
+	var i CommitTimestampRow
+	err := row.Scan(
+		&i.TrackCommitTimestamp,
+		&i.Subscriptions,
+		&i.EnabledSubscriptions,
+	)
+	return i, err
+}
+
+const configuredSettings = `-- name: ConfiguredSettings :many
+SELECT DISTINCT ON (f.name)
+  f.name::text AS name
+  , coalesce(f.setting, '')::text AS configured_value
+  , (coalesce(f.sourcefile, '') || ':' || coalesce(f.sourceline, 0))::text AS source
+FROM pg_file_settings AS f
+WHERE f.name = ANY($1::text[])
+ORDER BY f.name, f.seqno DESC
+`
+
+type ConfiguredSettingsRow struct {
+	Name            string
+	ConfiguredValue string
+	Source          string
+}
+
+// The value each of the given parameters is set to in the configuration
+// files, including postgresql.auto.conf written by ALTER SYSTEM. The last
+// entry of a parameter is the one the server reads.
+func (q *Queries) ConfiguredSettings(ctx context.Context, names []string) ([]ConfiguredSettingsRow, error) {
+	rows, err := q.db.Query(ctx, configuredSettings, names)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ConfiguredSettingsRow
+	for rows.Next() {
+		var i ConfiguredSettingsRow
+		if err := rows.Scan(
+			&i.Name,
+			&i.ConfiguredValue,
+			&i.Source,
 		); err != nil {
 			return nil, err
 		}
@@ -107,6 +532,133 @@ func (q *Queries) ConnectionStats(ctx context.Context) (ConnectionStatsRow, erro
 	return i, err
 }
 
+const costParams = `-- name: CostParams :one
+SELECT
+  current_setting('random_page_cost')::float8 AS random_page_cost
+  , current_setting('seq_page_cost')::float8 AS seq_page_cost
+  , current_setting('effective_io_concurrency')::int AS effective_io_concurrency
+`
+
+type CostParamsRow struct {
+	RandomPageCost         float64
+	SeqPageCost            float64
+	EffectiveIoConcurrency int32
+}
+
+// The planner's I/O cost settings as the current session sees them, so
+// ALTER DATABASE and ALTER ROLE overrides are included.
+func (q *Queries) CostParams(ctx context.Context) (CostParamsRow, error) {
+	row := q.db.QueryRow(ctx, costParams)
+	var i CostParamsRow
+	err := row.Scan(
+		&i.RandomPageCost,
+		&i.SeqPageCost,
+		&i.EffectiveIoConcurrency,
+	)
+	return i, err
+}
+
+const cronClock = `-- name: CronClock :one
+SELECT
+  now() AS now
+  , current_setting('cron.timezone', true) AS timezone
+`
+
+type CronClockRow struct {
+	Now      pgtype.Timestamptz
+	Timezone pgtype.Text
+}
+
+// The server clock and the time zone pg_cron evaluates schedules in
+// (cron.timezone, pg_cron 1.5+; GMT when unset).
+func (q *Queries) CronClock(ctx context.Context) (CronClockRow, error) {
+	row := q.db.QueryRow(ctx, cronClock)
+	var i CronClockRow
+	err := row.Scan(
+		&i.Now,
+		&i.Timezone,
+	)
+	return i, err
+}
+
+const cronJobs = `-- name: CronJobs :many
+SELECT
+  j.jobid
+  , coalesce(j.jobname, 'job ' || j.jobid)::text AS job_name
+  , j.schedule::text AS schedule
+  , j.database::text AS database_name
+  , j.active
+  , last_run.status AS last_status
+  , last_run.start_time AS last_start_time
+  , coalesce(failures.failed_runs, 0)::bigint AS recent_failures
+  , failures.last_error
+FROM cron.job AS j
+LEFT JOIN LATERAL (
+  SELECT
+    d.status
+    , d.start_time
+  FROM cron.job_run_details AS d
+  WHERE d.jobid = j.jobid
+  ORDER BY d.runid DESC
+  LIMIT 1
+) AS last_run ON true
+LEFT JOIN LATERAL (
+  SELECT
+    count(*) AS failed_runs
+    , (array_agg(d.return_message ORDER BY d.runid DESC))[1] AS last_error
+  FROM cron.job_run_details AS d
+  WHERE
+    d.jobid = j.jobid
+    AND d.status = 'failed'
+    AND d.start_time >= now() - make_interval(hours => $1::int)
+) AS failures ON true
+ORDER BY job_name
+`
+
+type CronJobsRow struct {
+	Jobid          int64
+	JobName        string
+	Schedule       string
+	DatabaseName   string
+	Active         bool
+	LastStatus     pgtype.Text
+	LastStartTime  pgtype.Timestamptz
+	RecentFailures int64
+	LastError      pgtype.Text
+}
+
+// Every scheduled job with its most recent run and the failed runs in the
+// last window_hours. Runs are only recorded while cron.log_run is on.
+func (q *Queries) CronJobs(ctx context.Context, windowHours int32) ([]CronJobsRow, error) {
+	rows, err := q.db.Query(ctx, cronJobs, windowHours)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CronJobsRow
+	for rows.Next() {
+		var i CronJobsRow
+		if err := rows.Scan(
+			&i.Jobid,
+			&i.JobName,
+			&i.Schedule,
+			&i.DatabaseName,
+			&i.Active,
+			&i.LastStatus,
+			&i.LastStartTime,
+			&i.RecentFailures,
+			&i.LastError,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const databaseCacheEfficiency = `-- name: DatabaseCacheEfficiency :one
 SELECT
   blks_hit
@@ -147,6 +699,53 @@ func (q *Queries) DatabaseCacheEfficiency(ctx context.Context) (DatabaseCacheEff
 	return i, err
 }
 
+const databaseCollationMismatches = `-- name: DatabaseCollationMismatches :many
+SELECT
+  d.datname::text AS database_name
+  , d.datcollversion::text AS recorded_version
+  , pg_database_collation_actual_version(d.oid)::text AS actual_version
+FROM pg_database AS d
+WHERE
+  d.datallowconn
+  AND d.datcollversion IS NOT NULL
+  AND pg_database_collation_actual_version(d.oid) IS NOT NULL
+  AND d.datcollversion <> pg_database_collation_actual_version(d.oid)
+ORDER BY d.datname
+`
+
+type DatabaseCollationMismatchesRow struct {
+	DatabaseName    string
+	RecordedVersion string
+	ActualVersion   string
+}
+
+// Databases whose default collation version (PG 15+) differs from the version
+// the provider reports now. Every text index in them that uses the default
+// collation is affected.
+func (q *Queries) DatabaseCollationMismatches(ctx context.Context) ([]DatabaseCollationMismatchesRow, error) {
+	rows, err := q.db.Query(ctx, databaseCollationMismatches)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DatabaseCollationMismatchesRow
+	for rows.Next() {
+		var i DatabaseCollationMismatchesRow
+		if err := rows.Scan(
+			&i.DatabaseName,
+			&i.RecordedVersion,
+			&i.ActualVersion,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const databaseFreezeAge = `-- name: DatabaseFreezeAge :many
 SELECT
   datname::text AS database_name
@@ -194,16 +793,106 @@ func (q *Queries) DatabaseFreezeAge(ctx context.Context) ([]DatabaseFreezeAgeRow
 	return items, nil
 }
 
-const duplicateIndexes = `-- name: DuplicateIndexes :many
-WITH index_columns AS (
-  SELECT
-    idx.indexrelid
-    , idx.indrelid
-    , i.relname AS index_name
-    , t.relname AS table_name
-    , n.nspname AS schema_name
-    , idx.indkey::int [] AS column_positions
-    , idx.indnkeyatts AS num_key_columns
+const databaseSettings = `-- name: DatabaseSettings :many
+WITH settings AS (
+  SELECT s.name, s.reset_val, s.unit
+  FROM pg_settings AS s
+  WHERE s.name IN (
+    'statement_timeout'
+    , 'lock_timeout'
+    , 'idle_in_transaction_session_timeout'
+  )
+)
+
+, overrides AS (
+  SELECT
+    drs.setdatabase AS db_oid
+    , split_part(cfg, '=', 1) AS param_name
+    , substring(cfg FROM position('=' IN cfg) + 1) AS param_value
+  FROM pg_db_role_setting AS drs
+  CROSS JOIN LATERAL unnest(coalesce(drs.setconfig, ARRAY[]::text [])) AS cfg
+  WHERE
+    drs.setrole = 0
+    AND split_part(cfg, '=', 1) IN (
+      'statement_timeout'
+      , 'lock_timeout'
+      , 'idle_in_transaction_session_timeout'
+    )
+)
+
+SELECT
+  d.datname::text AS database_name
+  , s.name::text AS setting_name
+  , coalesce(od.param_value, oa.param_value, s.reset_val)::text AS setting_value
+  , coalesce(s.unit, '')::text AS unit
+  , CASE
+    WHEN od.param_value IS NOT NULL THEN 'database'
+    WHEN oa.param_value IS NOT NULL THEN 'all roles'
+    ELSE 'cluster'
+  END::text AS source
+FROM pg_database AS d
+CROSS JOIN settings AS s
+LEFT JOIN overrides AS od ON od.db_oid = d.oid AND od.param_name = s.name
+LEFT JOIN overrides AS oa ON oa.db_oid = 0 AND oa.param_name = s.name
+WHERE
+  d.datallowconn
+  AND NOT d.datistemplate
+ORDER BY d.datname, s.name
+`
+
+type DatabaseSettingsRow struct {
+	DatabaseName string
+	SettingName  string
+	SettingValue string
+	Unit         string
+	Source       string
+}
+
+// The timeout defaults each connectable database gives sessions that have no
+// role-level override: ALTER DATABASE d SET (setrole = 0, setdatabase = d),
+// then ALTER ROLE ALL SET (setrole = 0, setdatabase = 0), then the cluster
+// default. source says which level the value came from.
+//
+// Caveat: the cluster default is pg_settings.reset_val, which includes the
+// database and role overrides of pgdoctor's own session. Run pgdoctor from a
+// database and role without overrides for these settings, or the fallback
+// for other databases can be wrong (as in session-settings).
+func (q *Queries) DatabaseSettings(ctx context.Context) ([]DatabaseSettingsRow, error) {
+	rows, err := q.db.Query(ctx, databaseSettings)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DatabaseSettingsRow
+	for rows.Next() {
+		var i DatabaseSettingsRow
+		if err := rows.Scan(
+			&i.DatabaseName,
+			&i.SettingName,
+			&i.SettingValue,
+			&i.Unit,
+			&i.Source,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const duplicateIndexes = `-- name: DuplicateIndexes :many
+WITH index_columns AS (
+  SELECT
+    idx.indexrelid
+    , idx.indrelid
+    , i.relname AS index_name
+    , t.relname AS table_name
+    , n.nspname AS schema_name
+    , idx.indkey::int [] AS column_positions
+    , idx.indnkeyatts AS num_key_columns
     -- Extract column list as array for prefix comparison
     , pg_get_indexdef(idx.indexrelid) AS index_def
     , pg_relation_size(i.oid) AS index_size_bytes
@@ -339,6 +1028,190 @@ func (q *Queries) DuplicateIndexes(ctx context.Context) ([]DuplicateIndexesRow,
 	return items, nil
 }
 
+const extensionSecurity = `-- name: ExtensionSecurity :many
+SELECT
+  e.extname::text AS extension_name
+  , e.extversion::text AS extension_version
+  , n.nspname::text AS schema_name
+  , o.rolname::text AS owner_name
+  , o.rolsuper AS owner_superuser
+  , coalesce((to_jsonb(v) ->> 'trusted')::boolean, false) AS trusted
+  , (
+    SELECT count(*)::int
+    FROM pg_catalog.pg_depend AS d
+    JOIN pg_catalog.pg_proc AS p ON p.oid = d.objid
+    JOIN pg_catalog.pg_language AS l ON l.oid = p.prolang
+    JOIN pg_catalog.pg_roles AS po ON po.oid = p.proowner
+    WHERE
+      d.classid = 'pg_catalog.pg_proc'::regclass
+      AND d.refclassid = 'pg_catalog.pg_extension'::regclass
+      AND d.refobjid = e.oid
+      AND d.deptype = 'e'
+      AND l.lanname = 'c'
+      AND NOT po.rolsuper
+  ) AS non_superuser_c_functions
+  , coalesce((
+    SELECT string_agg(w.name, ', ' ORDER BY w.name <> 'PUBLIC', w.name)
+    FROM (
+      SELECT 'PUBLIC'::text AS name
+      WHERE has_schema_privilege('public', n.oid, 'CREATE')
+      UNION ALL
+      SELECT r.rolname::text
+      FROM pg_catalog.pg_roles AS r
+      WHERE
+        NOT r.rolsuper
+        AND r.rolname NOT LIKE 'pg\_%'
+        AND NOT has_schema_privilege('public', n.oid, 'CREATE')
+        AND has_schema_privilege(r.oid, n.oid, 'CREATE')
+    ) AS w
+  ), '')::text AS schema_writers
+FROM pg_catalog.pg_extension AS e
+JOIN pg_catalog.pg_namespace AS n ON n.oid = e.extnamespace
+JOIN pg_catalog.pg_roles AS o ON o.oid = e.extowner
+LEFT JOIN pg_catalog.pg_available_extension_versions AS v
+  ON v.name = e.extname AND v.version = e.extversion
+WHERE n.nspname <> 'pg_catalog'
+ORDER BY e.extname
+`
+
+type ExtensionSecurityRow struct {
+	ExtensionName          string
+	ExtensionVersion       string
+	SchemaName             string
+	OwnerName              string
+	OwnerSuperuser         bool
+	Trusted                bool
+	NonSuperuserCFunctions int32
+	SchemaWriters          string
+}
+
+// Installed extensions outside pg_catalog with what makes them a privilege
+// risk: whether the installed version is trusted (installable without
+// superuser; the trusted column is PostgreSQL 13+, and every extension needed
+// a superuser before), whether its owner is a superuser, its C functions owned
+// by non-superusers, and the non-superuser roles that can create objects in
+// its schema, PUBLIC counting as one.
+func (q *Queries) ExtensionSecurity(ctx context.Context) ([]ExtensionSecurityRow, error) {
+	rows, err := q.db.Query(ctx, extensionSecurity)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ExtensionSecurityRow
+	for rows.Next() {
+		var i ExtensionSecurityRow
+		if err := rows.Scan(
+			&i.ExtensionName,
+			&i.ExtensionVersion,
+			&i.SchemaName,
+			&i.OwnerName,
+			&i.OwnerSuperuser,
+			&i.Trusted,
+			&i.NonSuperuserCFunctions,
+			&i.SchemaWriters,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const fileSettingsReadable = `-- name: FileSettingsReadable :one
+SELECT has_function_privilege('pg_show_all_file_settings()', 'EXECUTE') AS readable
+`
+
+// pg_file_settings is readable by superusers only, unless EXECUTE on its
+// function was granted.
+func (q *Queries) FileSettingsReadable(ctx context.Context) (bool, error) {
+	row := q.db.QueryRow(ctx, fileSettingsReadable)
+	var readable bool
+	err := row.Scan(&readable)
+	return readable, err
+}
+
+const foreignServers = `-- name: ForeignServers :many
+SELECT
+  s.srvname::text AS server_name
+  , w.fdwname::text AS wrapper_name
+  , coalesce(array_to_string(ARRAY(
+    SELECT o
+    FROM unnest(s.srvoptions) AS o
+    WHERE o NOT LIKE 'password=%'
+  ), ', '), '')::text AS options
+  , (SELECT count(*) FROM pg_catalog.pg_user_mappings AS um WHERE um.srvid = s.oid) AS user_mappings
+  , (SELECT count(*) FROM pg_catalog.pg_foreign_table AS ft WHERE ft.ftserver = s.oid) AS foreign_tables
+  , coalesce((
+    SELECT format('%I.%I', n.nspname, c.relname)
+    FROM pg_catalog.pg_foreign_table AS ft
+    INNER JOIN pg_catalog.pg_class AS c ON ft.ftrelid = c.oid
+    INNER JOIN pg_catalog.pg_namespace AS n ON c.relnamespace = n.oid
+    WHERE ft.ftserver = s.oid
+    ORDER BY c.oid
+    LIMIT 1
+  ), '')::text AS probe_table
+FROM pg_catalog.pg_foreign_server AS s
+INNER JOIN pg_catalog.pg_foreign_data_wrapper AS w ON s.srvfdw = w.oid
+ORDER BY s.srvname
+`
+
+type ForeignServersRow struct {
+	ServerName    string
+	WrapperName   string
+	Options       string
+	UserMappings  int64
+	ForeignTables int64
+	ProbeTable    string
+}
+
+// Foreign servers with their wrapper, options (never passwords), how many user
+// mappings and foreign tables use them, and one foreign table to probe
+// connectivity through (empty when the server has none).
+func (q *Queries) ForeignServers(ctx context.Context) ([]ForeignServersRow, error) {
+	rows, err := q.db.Query(ctx, foreignServers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ForeignServersRow
+	for rows.Next() {
+		var i ForeignServersRow
+		if err := rows.Scan(
+			&i.ServerName,
+			&i.WrapperName,
+			&i.Options,
+			&i.UserMappings,
+			&i.ForeignTables,
+			&i.ProbeTable,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const hasPgCron = `-- name: HasPgCron :one
+SELECT EXISTS(
+  SELECT 1 FROM pg_extension
+  WHERE extname = 'pg_cron'
+)
+`
+
+// Checks if the pg_cron extension is installed in this database.
+func (q *Queries) HasPgCron(ctx context.Context) (bool, error) {
+	row := q.db.QueryRow(ctx, hasPgCron)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
 const hasPgStatStatements = `-- name: HasPgStatStatements :one
 SELECT EXISTS(
   SELECT 1 FROM pg_extension
@@ -354,6 +1227,21 @@ func (q *Queries) HasPgStatStatements(ctx context.Context) (bool, error) {
 	return exists, err
 }
 
+const hasPgstattuple = `-- name: HasPgstattuple :one
+SELECT EXISTS(
+  SELECT 1 FROM pg_extension
+  WHERE extname = 'pgstattuple'
+)
+`
+
+// Checks if the pgstattuple extension is installed.
+func (q *Queries) HasPgstattuple(ctx context.Context) (bool, error) {
+	row := q.db.QueryRow(ctx, hasPgstattuple)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
 const highSeqScanTables = `-- name: HighSeqScanTables :many
 WITH table_indexes AS (
   SELECT
@@ -428,6 +1316,41 @@ func (q *Queries) HighSeqScanTables(ctx context.Context) ([]HighSeqScanTablesRow
 	return items, nil
 }
 
+const hugePages = `-- name: HugePages :one
+SELECT
+  current_setting('huge_pages')::text AS huge_pages
+  , coalesce(current_setting('huge_pages_status', true), '')::text AS huge_pages_status
+  , coalesce(pg_catalog.pg_size_bytes(current_setting('shared_memory_size', true)), 0)::bigint AS shared_memory_size_bytes
+  , coalesce(current_setting('shared_memory_size_in_huge_pages', true)::bigint, 0)::bigint AS huge_pages_needed
+  , coalesce(pg_catalog.pg_size_bytes(current_setting('huge_page_size', true)), 0)::bigint AS huge_page_size_bytes
+`
+
+type HugePagesRow struct {
+	HugePages             string
+	HugePagesStatus       string
+	SharedMemorySizeBytes int64
+	HugePagesNeeded       int64
+	HugePageSizeBytes     int64
+}
+
+// huge_pages, with what the server reports about the shared memory it
+// allocated at start: shared_memory_size and the number of huge pages it
+// needs (PostgreSQL 15+, -1 when the platform has no huge pages, 0 before
+// 15), huge_page_size (14+, 0 for the system default), and whether huge
+// pages are in use (huge_pages_status, 17+, empty before).
+func (q *Queries) HugePages(ctx context.Context) (HugePagesRow, error) {
+	row := q.db.QueryRow(ctx, hugePages)
+	var i HugePagesRow
+	err := row.Scan(
+		&i.HugePages,
+		&i.HugePagesStatus,
+		&i.SharedMemorySizeBytes,
+		&i.HugePagesNeeded,
+		&i.HugePageSizeBytes,
+	)
+	return i, err
+}
+
 const idleInTransaction = `-- name: IdleInTransaction :many
 SELECT
   pg_stat_activity.pid
@@ -491,6 +1414,135 @@ func (q *Queries) IdleInTransaction(ctx context.Context) ([]IdleInTransactionRow
 	return items, nil
 }
 
+const inactiveLogicalSlots = `-- name: InactiveLogicalSlots :many
+WITH current_lsn AS (
+  SELECT
+    CASE
+      WHEN pg_is_in_recovery() THEN pg_last_wal_receive_lsn()
+      ELSE pg_current_wal_lsn()
+    END AS lsn
+)
+
+SELECT
+  s.slot_name::text AS slot_name
+  , s.plugin::text AS plugin
+  , s.database::text AS database
+  , pg_wal_lsn_diff(l.lsn, s.restart_lsn)::bigint AS retained_bytes
+  , pg_wal_lsn_diff(l.lsn, s.confirmed_flush_lsn)::bigint AS unconfirmed_bytes
+  , extract(EPOCH FROM (now() - s.inactive_since))::bigint AS inactive_seconds
+FROM pg_replication_slots AS s
+CROSS JOIN current_lsn AS l
+WHERE
+  s.slot_type = 'logical'
+  AND NOT s.active
+  AND NOT s.temporary
+  AND s.wal_status IS DISTINCT FROM 'lost'
+ORDER BY retained_bytes DESC NULLS LAST
+`
+
+type InactiveLogicalSlotsRow struct {
+	SlotName         string
+	Plugin           string
+	Database         string
+	RetainedBytes    pgtype.Int8
+	UnconfirmedBytes pgtype.Int8
+	InactiveSeconds  pgtype.Int8
+}
+
+// For PostgreSQL 17+: inactive, non-temporary logical slots with the WAL they
+// retain, how far their consumer is behind, and how long they have been
+// inactive. Slots whose WAL is already lost retain nothing and are excluded.
+func (q *Queries) InactiveLogicalSlots(ctx context.Context) ([]InactiveLogicalSlotsRow, error) {
+	rows, err := q.db.Query(ctx, inactiveLogicalSlots)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InactiveLogicalSlotsRow
+	for rows.Next() {
+		var i InactiveLogicalSlotsRow
+		if err := rows.Scan(
+			&i.SlotName,
+			&i.Plugin,
+			&i.Database,
+			&i.RetainedBytes,
+			&i.UnconfirmedBytes,
+			&i.InactiveSeconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const inactiveLogicalSlotsPG15 = `-- name: InactiveLogicalSlotsPG15 :many
+WITH current_lsn AS (
+  SELECT
+    CASE
+      WHEN pg_is_in_recovery() THEN pg_last_wal_receive_lsn()
+      ELSE pg_current_wal_lsn()
+    END AS lsn
+)
+
+SELECT
+  s.slot_name::text AS slot_name
+  , s.plugin::text AS plugin
+  , s.database::text AS database
+  , pg_wal_lsn_diff(l.lsn, s.restart_lsn)::bigint AS retained_bytes
+  , pg_wal_lsn_diff(l.lsn, s.confirmed_flush_lsn)::bigint AS unconfirmed_bytes
+  , NULL::bigint AS inactive_seconds
+FROM pg_replication_slots AS s
+CROSS JOIN current_lsn AS l
+WHERE
+  s.slot_type = 'logical'
+  AND NOT s.active
+  AND NOT s.temporary
+  AND s.wal_status IS DISTINCT FROM 'lost'
+ORDER BY retained_bytes DESC NULLS LAST
+`
+
+type InactiveLogicalSlotsPG15Row struct {
+	SlotName         string
+	Plugin           string
+	Database         string
+	RetainedBytes    pgtype.Int8
+	UnconfirmedBytes pgtype.Int8
+	InactiveSeconds  pgtype.Int8
+}
+
+// For PostgreSQL 15/16: inactive_since doesn't exist, so the inactive
+// duration is unknown.
+func (q *Queries) InactiveLogicalSlotsPG15(ctx context.Context) ([]InactiveLogicalSlotsPG15Row, error) {
+	rows, err := q.db.Query(ctx, inactiveLogicalSlotsPG15)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InactiveLogicalSlotsPG15Row
+	for rows.Next() {
+		var i InactiveLogicalSlotsPG15Row
+		if err := rows.Scan(
+			&i.SlotName,
+			&i.Plugin,
+			&i.Database,
+			&i.RetainedBytes,
+			&i.UnconfirmedBytes,
+			&i.InactiveSeconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const indexBloat = `-- name: IndexBloat :many
 WITH index_info AS (
   SELECT
@@ -638,75 +1690,248 @@ func (q *Queries) IndexBloat(ctx context.Context) ([]IndexBloatRow, error) {
 	return items, nil
 }
 
-const indexUsageStats = `-- name: IndexUsageStats :many
+const indexColumnOrderIndexes = `-- name: IndexColumnOrderIndexes :many
 SELECT
-  (n.nspname || '.' || tbl.relname)::text AS table_name
-  , psai.indexrelname::text AS index_name
-  , c.reltuples::bigint AS num_rows
-  , x.indisprimary AS is_primary
-  , x.indisunique AS is_unique
-  , pg_relation_size(psai.indexrelid) AS index_size_bytes
-  , coalesce(psai.idx_scan, 0) AS idx_scan
-  , coalesce(psai.idx_tup_read, 0) AS idx_tup_read
-  , coalesce(psai.idx_tup_fetch, 0) AS idx_tup_fetch
-  , coalesce(ut.n_tup_ins, 0) + coalesce(ut.n_tup_upd, 0) + coalesce(ut.n_tup_del, 0) AS table_writes
-  , coalesce(psaio.idx_blks_hit, 0) AS idx_blks_hit
-  , coalesce(psaio.idx_blks_read, 0) AS idx_blks_read
-  , CASE
-    WHEN coalesce(psaio.idx_blks_hit, 0) + coalesce(psaio.idx_blks_read, 0) = 0 THEN NULL
-    ELSE round(
-      100.0 * psaio.idx_blks_hit / (psaio.idx_blks_hit + psaio.idx_blks_read)
-      , 2
-    )
-  END AS cache_hit_ratio
-  , pg_get_indexdef(psai.indexrelid) AS indexdef
-FROM pg_stat_user_indexes AS psai
-INNER JOIN pg_index AS x ON psai.indexrelid = x.indexrelid
-INNER JOIN pg_class AS tbl ON x.indrelid = tbl.oid
-INNER JOIN pg_namespace AS n ON tbl.relnamespace = n.oid
-LEFT JOIN pg_class AS c ON psai.relid = c.oid
-LEFT JOIN pg_stat_user_tables AS ut ON tbl.oid = ut.relid
-LEFT JOIN pg_statio_user_indexes AS psaio ON psai.indexrelid = psaio.indexrelid
+  n.nspname::text AS schema_name
+  , t.relname::text AS table_name
+  , i.relname::text AS index_name
+  , array_agg(a.attname::text ORDER BY k.n)::text [] AS columns
+  -- pg_stats stores n_distinct as a negative fraction of the rows when it
+  -- scales with the table
+  , array_agg(
+    coalesce(
+      CASE WHEN st.n_distinct < 0 THEN -st.n_distinct * greatest(t.reltuples, 0) ELSE st.n_distinct END
+      , -1
+    ) ORDER BY k.n
+  )::double precision [] AS column_distinct
+FROM pg_index AS idx
+INNER JOIN pg_class AS i ON idx.indexrelid = i.oid
+INNER JOIN pg_class AS t ON idx.indrelid = t.oid
+INNER JOIN pg_namespace AS n ON t.relnamespace = n.oid
+INNER JOIN pg_am AS am ON i.relam = am.oid
+CROSS JOIN LATERAL unnest(idx.indkey::int []) WITH ORDINALITY AS k (attnum, n)
+INNER JOIN pg_attribute AS a ON a.attrelid = t.oid AND k.attnum = a.attnum
+LEFT JOIN LATERAL (
+  -- Partitioned tables only have inherited statistics.
+  SELECT s.n_distinct
+  FROM pg_stats AS s
+  WHERE s.schemaname = n.nspname AND s.tablename = t.relname AND s.attname = a.attname
+  ORDER BY s.inherited
+  LIMIT 1
+) AS st ON TRUE
 WHERE
-  n.nspname = 'public'
-ORDER BY
-  pg_relation_size(psai.indexrelid) DESC
+  am.amname = 'btree'
+  AND idx.indisvalid
+  AND idx.indexprs IS NULL
+  AND k.n <= idx.indnkeyatts
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast', 'cron', 'pgpartman', 'debezium')
+GROUP BY n.nspname, t.relname, i.relname
+ORDER BY n.nspname, t.relname, i.relname
 `
 
-type IndexUsageStatsRow struct {
-	TableName      pgtype.Text
-	IndexName      pgtype.Text
-	NumRows        pgtype.Int8
-	IsPrimary      bool
-	IsUnique       bool
-	IndexSizeBytes pgtype.Int8
-	IdxScan        pgtype.Int8
-	IdxTupRead     pgtype.Int8
-	IdxTupFetch    pgtype.Int8
-	TableWrites    pgtype.Int8
-	IdxBlksHit     pgtype.Int8
-	IdxBlksRead    pgtype.Int8
-	CacheHitRatio  pgtype.Numeric
-	Indexdef       pgtype.Text
+type IndexColumnOrderIndexesRow struct {
+	SchemaName     string
+	TableName      string
+	IndexName      string
+	Columns        []string
+	ColumnDistinct []float64
 }
 
-// Identifies indexes with usage statistics for health analysis.
-// Excludes: system schemas.
-// Returns data for subchecks: unused-indexes, low-usage-indexes, index-cache-ratio.
-func (q *Queries) IndexUsageStats(ctx context.Context) ([]IndexUsageStatsRow, error) {
-	rows, err := q.db.Query(ctx, indexUsageStats)
+// Btree indexes on plain columns, with their key columns in index order and
+// each column's estimated number of distinct values (-1 without statistics).
+// Single-column indexes are included so the check can tell when another
+// index already leads with a column.
+func (q *Queries) IndexColumnOrderIndexes(ctx context.Context) ([]IndexColumnOrderIndexesRow, error) {
+	rows, err := q.db.Query(ctx, indexColumnOrderIndexes)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []IndexUsageStatsRow
+	var items []IndexColumnOrderIndexesRow
 	for rows.Next() {
-		var i IndexUsageStatsRow
+		var i IndexColumnOrderIndexesRow
 		if err := rows.Scan(
+			&i.SchemaName,
 			&i.TableName,
 			&i.IndexName,
-			&i.NumRows,
-			&i.IsPrimary,
+			&i.Columns,
+			&i.ColumnDistinct,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const indexColumnOrderStatements = `-- name: IndexColumnOrderStatements :many
+SELECT
+  query::text AS query
+  , calls::bigint AS calls
+FROM pg_stat_statements
+WHERE
+  dbid = (SELECT oid FROM pg_database WHERE datname = current_database())
+  AND calls >= $1::bigint
+  AND query ~* '\mwhere\M'
+  AND query !~* '\mjoin\M'
+  AND query ~* '^\s*(select|update|delete)\M'
+ORDER BY calls DESC
+LIMIT 1000
+`
+
+type IndexColumnOrderStatementsRow struct {
+	Query string
+	Calls int64
+}
+
+// The most called statements of this database with a WHERE clause, whose
+// predicates the check matches against index columns. Statements with joins
+// are left out: their column names cannot be told apart by table.
+func (q *Queries) IndexColumnOrderStatements(ctx context.Context, minCalls int64) ([]IndexColumnOrderStatementsRow, error) {
+	rows, err := q.db.Query(ctx, indexColumnOrderStatements, minCalls)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []IndexColumnOrderStatementsRow
+	for rows.Next() {
+		var i IndexColumnOrderStatementsRow
+		if err := rows.Scan(
+			&i.Query,
+			&i.Calls,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const indexSizeRatios = `-- name: IndexSizeRatios :many
+SELECT
+  (n.nspname || '.' || c.relname)::text AS table_name
+  , pg_catalog.pg_table_size(c.oid) AS table_size_bytes
+  , pg_catalog.pg_indexes_size(c.oid) AS index_size_bytes
+  , (SELECT count(*) FROM pg_catalog.pg_index AS i WHERE i.indrelid = c.oid) AS index_count
+FROM pg_catalog.pg_class AS c
+INNER JOIN pg_catalog.pg_namespace AS n ON c.relnamespace = n.oid
+WHERE
+  n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
+  AND c.relkind IN ('r', 'm')
+  AND pg_catalog.pg_indexes_size(c.oid) >= 100 * 1024 * 1024
+ORDER BY pg_catalog.pg_indexes_size(c.oid) DESC
+`
+
+type IndexSizeRatiosRow struct {
+	TableName      string
+	TableSizeBytes pgtype.Int8
+	IndexSizeBytes pgtype.Int8
+	IndexCount     int64
+}
+
+// Tables whose indexes total 100MB or more, with their table size (heap and
+// TOAST) and total index size. The ratio threshold is applied by the check so
+// it stays configurable.
+func (q *Queries) IndexSizeRatios(ctx context.Context) ([]IndexSizeRatiosRow, error) {
+	rows, err := q.db.Query(ctx, indexSizeRatios)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []IndexSizeRatiosRow
+	for rows.Next() {
+		var i IndexSizeRatiosRow
+		if err := rows.Scan(
+			&i.TableName,
+			&i.TableSizeBytes,
+			&i.IndexSizeBytes,
+			&i.IndexCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const indexUsageStats = `-- name: IndexUsageStats :many
+SELECT
+  (n.nspname || '.' || tbl.relname)::text AS table_name
+  , psai.indexrelname::text AS index_name
+  , c.reltuples::bigint AS num_rows
+  , x.indisprimary AS is_primary
+  , x.indisunique AS is_unique
+  , pg_relation_size(psai.indexrelid) AS index_size_bytes
+  , coalesce(psai.idx_scan, 0) AS idx_scan
+  , coalesce(psai.idx_tup_read, 0) AS idx_tup_read
+  , coalesce(psai.idx_tup_fetch, 0) AS idx_tup_fetch
+  , coalesce(ut.n_tup_ins, 0) + coalesce(ut.n_tup_upd, 0) + coalesce(ut.n_tup_del, 0) AS table_writes
+  , coalesce(psaio.idx_blks_hit, 0) AS idx_blks_hit
+  , coalesce(psaio.idx_blks_read, 0) AS idx_blks_read
+  , CASE
+    WHEN coalesce(psaio.idx_blks_hit, 0) + coalesce(psaio.idx_blks_read, 0) = 0 THEN NULL
+    ELSE round(
+      100.0 * psaio.idx_blks_hit / (psaio.idx_blks_hit + psaio.idx_blks_read)
+      , 2
+    )
+  END AS cache_hit_ratio
+  , pg_get_indexdef(psai.indexrelid) AS indexdef
+FROM pg_stat_user_indexes AS psai
+INNER JOIN pg_index AS x ON psai.indexrelid = x.indexrelid
+INNER JOIN pg_class AS tbl ON x.indrelid = tbl.oid
+INNER JOIN pg_namespace AS n ON tbl.relnamespace = n.oid
+LEFT JOIN pg_class AS c ON psai.relid = c.oid
+LEFT JOIN pg_stat_user_tables AS ut ON tbl.oid = ut.relid
+LEFT JOIN pg_statio_user_indexes AS psaio ON psai.indexrelid = psaio.indexrelid
+WHERE
+  n.nspname = 'public'
+ORDER BY
+  pg_relation_size(psai.indexrelid) DESC
+`
+
+type IndexUsageStatsRow struct {
+	TableName      pgtype.Text
+	IndexName      pgtype.Text
+	NumRows        pgtype.Int8
+	IsPrimary      bool
+	IsUnique       bool
+	IndexSizeBytes pgtype.Int8
+	IdxScan        pgtype.Int8
+	IdxTupRead     pgtype.Int8
+	IdxTupFetch    pgtype.Int8
+	TableWrites    pgtype.Int8
+	IdxBlksHit     pgtype.Int8
+	IdxBlksRead    pgtype.Int8
+	CacheHitRatio  pgtype.Numeric
+	Indexdef       pgtype.Text
+}
+
+// Identifies indexes with usage statistics for health analysis.
+// Excludes: system schemas.
+// Returns data for subchecks: unused-indexes, low-usage-indexes, index-cache-ratio.
+func (q *Queries) IndexUsageStats(ctx context.Context) ([]IndexUsageStatsRow, error) {
+	rows, err := q.db.Query(ctx, indexUsageStats)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []IndexUsageStatsRow
+	for rows.Next() {
+		var i IndexUsageStatsRow
+		if err := rows.Scan(
+			&i.TableName,
+			&i.IndexName,
+			&i.NumRows,
+			&i.IsPrimary,
 			&i.IsUnique,
 			&i.IndexSizeBytes,
 			&i.IdxScan,
@@ -728,6 +1953,118 @@ func (q *Queries) IndexUsageStats(ctx context.Context) ([]IndexUsageStatsRow, er
 	return items, nil
 }
 
+const insertVacuum = `-- name: InsertVacuum :many
+SELECT
+  (s.schemaname || '.' || s.relname)::text AS table_name
+  , greatest(c.reltuples, 0)::bigint AS estimated_rows
+  , s.n_ins_since_vacuum::bigint AS inserts_since_vacuum
+  , coalesce(
+    extract(EPOCH FROM now() - greatest(s.last_vacuum, s.last_autovacuum)), -1
+  )::bigint AS seconds_since_vacuum
+  , coalesce((
+    SELECT o.option_value::bigint
+    FROM pg_catalog.pg_options_to_table(c.reloptions) AS o
+    WHERE o.option_name = 'autovacuum_vacuum_insert_threshold'
+  ), current_setting('autovacuum_vacuum_insert_threshold')::bigint) AS insert_threshold
+  , coalesce((
+    SELECT o.option_value::float8
+    FROM pg_catalog.pg_options_to_table(c.reloptions) AS o
+    WHERE o.option_name = 'autovacuum_vacuum_insert_scale_factor'
+  ), current_setting('autovacuum_vacuum_insert_scale_factor')::float8) AS insert_scale_factor
+  , current_setting('autovacuum')::bool AND coalesce((
+    SELECT o.option_value::bool
+    FROM pg_catalog.pg_options_to_table(c.reloptions) AS o
+    WHERE o.option_name = 'autovacuum_enabled'
+  ), true) AS autovacuum_enabled
+FROM pg_catalog.pg_stat_user_tables AS s
+INNER JOIN pg_catalog.pg_class AS c ON c.oid = s.relid
+WHERE s.n_ins_since_vacuum >= $1::bigint
+ORDER BY s.n_ins_since_vacuum DESC
+LIMIT 100
+`
+
+type InsertVacuumRow struct {
+	TableName          string
+	EstimatedRows      int64
+	InsertsSinceVacuum int64
+	SecondsSinceVacuum int64
+	InsertThreshold    int64
+	InsertScaleFactor  float64
+	AutovacuumEnabled  bool
+}
+
+// Tables with at least min_inserts rows inserted since their last vacuum
+// (PostgreSQL 13+), with the effective insert-driven autovacuum settings:
+// per-table reloptions, or the global values they inherit. Seconds since the
+// last vacuum are -1 when the table was never vacuumed.
+func (q *Queries) InsertVacuum(ctx context.Context, minInserts int64) ([]InsertVacuumRow, error) {
+	rows, err := q.db.Query(ctx, insertVacuum, minInserts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InsertVacuumRow
+	for rows.Next() {
+		var i InsertVacuumRow
+		if err := rows.Scan(
+			&i.TableName,
+			&i.EstimatedRows,
+			&i.InsertsSinceVacuum,
+			&i.SecondsSinceVacuum,
+			&i.InsertThreshold,
+			&i.InsertScaleFactor,
+			&i.AutovacuumEnabled,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertVacuumSupported = `-- name: InsertVacuumSupported :one
+SELECT current_setting('server_version_num')::integer >= 130000 AS supported
+`
+
+// Inserts trigger autovacuum, and pg_stat_user_tables counts them, from
+// PostgreSQL 13.
+func (q *Queries) InsertVacuumSupported(ctx context.Context) (bool, error) {
+	row := q.db.QueryRow(ctx, insertVacuumSupported)
+	var supported bool
+	err := row.Scan(&supported)
+	return supported, err
+}
+
+const installedExtensions = `-- name: InstalledExtensions :many
+SELECT e.extname::text AS extension_name
+FROM pg_extension AS e
+ORDER BY e.extname
+`
+
+// Extensions created in the current database.
+func (q *Queries) InstalledExtensions(ctx context.Context) ([]string, error) {
+	rows, err := q.db.Query(ctx, installedExtensions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var extension_name string
+		if err := rows.Scan(&extension_name); err != nil {
+			return nil, err
+		}
+		items = append(items, extension_name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const invalidPrimaryKeyTypes = `-- name: InvalidPrimaryKeyTypes :many
 WITH pk_tables AS (
   SELECT
@@ -850,6 +2187,128 @@ func (q *Queries) InvalidPrimaryKeyTypes(ctx context.Context) ([]InvalidPrimaryK
 	return items, nil
 }
 
+const ioTiming = `-- name: IoTiming :one
+SELECT
+  current_setting('track_io_timing')::text AS track_io_timing
+  , current_setting('track_functions')::text AS track_functions
+  , pg_catalog.pg_size_bytes(current_setting('track_activity_query_size'))::bigint AS track_activity_query_size_bytes
+  , (
+    SELECT count(*)::int
+    FROM pg_catalog.pg_proc AS p
+    JOIN pg_catalog.pg_language AS l ON l.oid = p.prolang
+    JOIN pg_catalog.pg_namespace AS n ON n.oid = p.pronamespace
+    WHERE
+      l.lanname NOT IN ('internal', 'c', 'sql')
+      AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+      AND NOT EXISTS (
+        SELECT 1 FROM pg_catalog.pg_depend AS d
+        WHERE d.classid = 'pg_catalog.pg_proc'::regclass AND d.objid = p.oid AND d.deptype = 'e'
+      )
+  ) AS pl_functions
+  , (
+    SELECT count(*)::int
+    FROM pg_catalog.pg_stat_activity AS a
+    WHERE
+      a.backend_type = 'client backend'
+      AND octet_length(a.query) >= pg_catalog.pg_size_bytes(current_setting('track_activity_query_size')) - 1
+  ) AS truncated_queries
+`
+
+type IoTimingRow struct {
+	TrackIoTiming               string
+	TrackFunctions              string
+	TrackActivityQuerySizeBytes int64
+	PlFunctions                 int32
+	TruncatedQueries            int32
+}
+
+// The statistics settings other checks and EXPLAIN rely on, as the session
+// sees them, with the functions in procedural languages, outside extensions,
+// that track_functions = pl would count, and the current queries whose text
+// reached track_activity_query_size and was cut.
+func (q *Queries) IoTiming(ctx context.Context) (IoTimingRow, error) {
+	row := q.db.QueryRow(ctx, ioTiming)
+	var i IoTimingRow
+	err := row.Scan(
+		&i.TrackIoTiming,
+		&i.TrackFunctions,
+		&i.TrackActivityQuerySizeBytes,
+		&i.PlFunctions,
+		&i.TruncatedQueries,
+	)
+	return i, err
+}
+
+const largeRelations = `-- name: LargeRelations :many
+SELECT
+  n.nspname::text AS schema_name
+  , c.relname::text AS relation_name
+  , CASE
+    WHEN c.relkind = 'i' THEN 'index'
+    WHEN c.relkind = 't' THEN 'toast'
+    WHEN c.relkind = 'm' THEN 'matview'
+    WHEN c.relispartition AND pg_get_expr(c.relpartbound, c.oid) = 'DEFAULT' THEN 'default partition'
+    WHEN c.relispartition THEN 'partition'
+    ELSE 'table'
+  END::text AS relation_kind
+  , coalesce(parent.oid::regclass::text, '')::text AS parent_name
+  , pg_relation_size(c.oid) AS size_bytes
+  , current_setting('block_size')::bigint * 4294967295 AS max_fork_bytes
+  , pg_stat_get_tuples_inserted(c.oid) AS rows_inserted
+FROM pg_class AS c
+INNER JOIN pg_namespace AS n ON c.relnamespace = n.oid
+LEFT JOIN pg_inherits AS inh ON c.relispartition AND c.oid = inh.inhrelid
+LEFT JOIN pg_class AS parent ON inh.inhparent = parent.oid
+WHERE
+  c.relkind IN ('r', 'i', 't', 'm')
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+  AND c.relpages::bigint * current_setting('block_size')::bigint >= 1073741824
+ORDER BY size_bytes DESC
+LIMIT 100
+`
+
+type LargeRelationsRow struct {
+	SchemaName   string
+	RelationName string
+	RelationKind string
+	ParentName   string
+	SizeBytes    int64
+	MaxForkBytes int64
+	RowsInserted pgtype.Int8
+}
+
+// Largest tables, indexes, and TOAST relations by main-fork size, with the
+// per-fork hard limit (2^32 - 1 blocks) for the server's block size.
+// Candidates are pre-filtered on relpages so pg_relation_size() only stats
+// the files of relations that are already big.
+func (q *Queries) LargeRelations(ctx context.Context) ([]LargeRelationsRow, error) {
+	rows, err := q.db.Query(ctx, largeRelations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LargeRelationsRow
+	for rows.Next() {
+		var i LargeRelationsRow
+		if err := rows.Scan(
+			&i.SchemaName,
+			&i.RelationName,
+			&i.RelationKind,
+			&i.ParentName,
+			&i.SizeBytes,
+			&i.MaxForkBytes,
+			&i.RowsInserted,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const largeTables = `-- name: LargeTables :many
 WITH inheritance_info AS (
   SELECT DISTINCT ON (i.inhrelid)
@@ -929,43 +2388,179 @@ func (q *Queries) LargeTables(ctx context.Context) ([]LargeTablesRow, error) {
 	return items, nil
 }
 
-const longIdleConnections = `-- name: LongIdleConnections :many
+const legacyInheritance = `-- name: LegacyInheritance :many
 SELECT
-  pid
-  , usename::text AS username
-  , datname::text AS database_name
-  , application_name::text AS application_name
-  , client_addr::text AS client_address
-  , state::text AS state
-  , extract(EPOCH FROM (now() - state_change))::bigint AS idle_duration_seconds
-  , extract(EPOCH FROM (now() - backend_start))::bigint AS connection_age_seconds
-FROM pg_stat_activity
+  (pn.nspname || '.' || p.relname)::text AS parent_table
+  , array_agg((cn.nspname || '.' || c.relname)::text ORDER BY cn.nspname, c.relname)::text[] AS child_tables
+  , (pg_total_relation_size(p.oid) + sum(pg_total_relation_size(c.oid)))::bigint AS total_bytes
+  , EXISTS (
+    SELECT 1
+    FROM pg_trigger AS t
+    WHERE
+      t.tgrelid = p.oid
+      AND NOT t.tgisinternal
+      -- TRIGGER_TYPE_ROW | TRIGGER_TYPE_BEFORE | TRIGGER_TYPE_INSERT
+      AND (t.tgtype & 7) = 7
+  ) AS insert_trigger
+FROM pg_inherits AS i
+INNER JOIN pg_class AS p ON i.inhparent = p.oid
+INNER JOIN pg_namespace AS pn ON p.relnamespace = pn.oid
+INNER JOIN pg_class AS c ON i.inhrelid = c.oid
+INNER JOIN pg_namespace AS cn ON c.relnamespace = cn.oid
 WHERE
-  state = 'idle'
-  AND pid != pg_backend_pid()
-  AND (now() - state_change) > interval '30 minutes'
-ORDER BY state_change ASC
+  p.relkind <> 'p'
+  AND NOT c.relispartition
+  AND pn.nspname NOT IN ('pg_catalog', 'information_schema')
+  AND cn.nspname !~ '^_timescaledb'
+GROUP BY p.oid, pn.nspname, p.relname
+ORDER BY total_bytes DESC, parent_table
 `
 
-type LongIdleConnectionsRow struct {
-	Pid                  pgtype.Int4
-	Username             pgtype.Text
-	DatabaseName         pgtype.Text
-	ApplicationName      pgtype.Text
-	ClientAddress        pgtype.Text
-	State                pgtype.Text
-	IdleDurationSeconds  pgtype.Int8
-	ConnectionAgeSeconds pgtype.Int8
+type LegacyInheritanceRow struct {
+	ParentTable   string
+	ChildTables   []string
+	TotalBytes    int64
+	InsertTrigger bool
 }
 
-// Identifies connections that have been idle for too long (potential pool leak).
-func (q *Queries) LongIdleConnections(ctx context.Context) ([]LongIdleConnectionsRow, error) {
-	rows, err := q.db.Query(ctx, longIdleConnections)
+// Tables inherited from through old-style table inheritance rather than
+// declarative partitioning: parents that are not partitioned tables (relkind
+// 'p'), with their children, the size of parent and children together, and
+// whether a BEFORE INSERT row trigger on the parent routes rows to the
+// children, as pre-10 partitioning schemes did. TimescaleDB hypertables,
+// whose chunks inherit from them, are left out.
+func (q *Queries) LegacyInheritance(ctx context.Context) ([]LegacyInheritanceRow, error) {
+	rows, err := q.db.Query(ctx, legacyInheritance)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []LongIdleConnectionsRow
+	var items []LegacyInheritanceRow
+	for rows.Next() {
+		var i LegacyInheritanceRow
+		if err := rows.Scan(
+			&i.ParentTable,
+			&i.ChildTables,
+			&i.TotalBytes,
+			&i.InsertTrigger,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockTimeout = `-- name: LockTimeout :one
+SELECT s.reset_val::bigint AS lock_timeout_ms
+FROM pg_catalog.pg_settings AS s
+WHERE s.name = 'lock_timeout'
+`
+
+// The lock_timeout a new session starts with, in milliseconds: reset_val
+// leaves out pgdoctor's own SET, but includes the database and role
+// overrides of its session (as in session-settings).
+func (q *Queries) LockTimeout(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, lockTimeout)
+	var lock_timeout_ms int64
+	err := row.Scan(&lock_timeout_ms)
+	return lock_timeout_ms, err
+}
+
+const lockWaits = `-- name: LockWaits :many
+SELECT
+  a.pid
+  , coalesce(a.usename::text, '')::text AS username
+  , coalesce(a.datname::text, '')::text AS database_name
+  , coalesce(extract(EPOCH FROM now() - a.query_start), 0)::bigint AS wait_seconds
+  , pg_catalog.pg_blocking_pids(a.pid)::int [] AS blocking_pids
+  , left(coalesce(a.query, ''), 200)::text AS query
+FROM pg_catalog.pg_stat_activity AS a
+WHERE
+  a.wait_event_type = 'Lock'
+  AND a.pid <> pg_catalog.pg_backend_pid()
+ORDER BY wait_seconds DESC, a.pid
+`
+
+type LockWaitsRow struct {
+	Pid          int32
+	Username     string
+	DatabaseName string
+	WaitSeconds  int64
+	BlockingPids []int32
+	Query        string
+}
+
+// Sessions of every database waiting for a heavyweight lock, longest first,
+// with the sessions blocking them. A statement waits from its start, so the
+// wait is measured from query_start.
+func (q *Queries) LockWaits(ctx context.Context) ([]LockWaitsRow, error) {
+	rows, err := q.db.Query(ctx, lockWaits)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LockWaitsRow
+	for rows.Next() {
+		var i LockWaitsRow
+		if err := rows.Scan(
+			&i.Pid,
+			&i.Username,
+			&i.DatabaseName,
+			&i.WaitSeconds,
+			&i.BlockingPids,
+			&i.Query,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const longIdleConnections = `-- name: LongIdleConnections :many
+SELECT
+  pid
+  , usename::text AS username
+  , datname::text AS database_name
+  , application_name::text AS application_name
+  , client_addr::text AS client_address
+  , state::text AS state
+  , extract(EPOCH FROM (now() - state_change))::bigint AS idle_duration_seconds
+  , extract(EPOCH FROM (now() - backend_start))::bigint AS connection_age_seconds
+FROM pg_stat_activity
+WHERE
+  state = 'idle'
+  AND pid != pg_backend_pid()
+  AND (now() - state_change) > interval '30 minutes'
+ORDER BY state_change ASC
+`
+
+type LongIdleConnectionsRow struct {
+	Pid                  pgtype.Int4
+	Username             pgtype.Text
+	DatabaseName         pgtype.Text
+	ApplicationName      pgtype.Text
+	ClientAddress        pgtype.Text
+	State                pgtype.Text
+	IdleDurationSeconds  pgtype.Int8
+	ConnectionAgeSeconds pgtype.Int8
+}
+
+// Identifies connections that have been idle for too long (potential pool leak).
+func (q *Queries) LongIdleConnections(ctx context.Context) ([]LongIdleConnectionsRow, error) {
+	rows, err := q.db.Query(ctx, longIdleConnections)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LongIdleConnectionsRow
 	for rows.Next() {
 		var i LongIdleConnectionsRow
 		if err := rows.Scan(
@@ -988,6 +2583,165 @@ func (q *Queries) LongIdleConnections(ctx context.Context) ([]LongIdleConnection
 	return items, nil
 }
 
+const namingIssues = `-- name: NamingIssues :many
+WITH objects AS (
+  SELECT
+    n.nspname::text AS schema_name
+    , c.relname::text AS table_name
+    , ''::text AS column_name
+    , c.relname::text AS object_name
+    , CASE c.relkind
+      WHEN 'r' THEN 'table'
+      WHEN 'p' THEN 'table'
+      WHEN 'v' THEN 'view'
+      WHEN 'm' THEN 'materialized view'
+      WHEN 'f' THEN 'foreign table'
+      WHEN 'S' THEN 'sequence'
+      ELSE 'index'
+    END::text AS object_kind
+  FROM pg_catalog.pg_class AS c
+  INNER JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+  WHERE
+    c.relkind IN ('r', 'p', 'v', 'm', 'f', 'S', 'i', 'I')
+    AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
+    AND n.nspname NOT LIKE 'pg\_temp\_%'
+    AND NOT EXISTS (
+      SELECT 1 FROM pg_catalog.pg_depend AS d
+      WHERE d.classid = 'pg_catalog.pg_class'::regclass AND d.objid = c.oid AND d.deptype = 'e'
+    )
+
+  UNION ALL
+
+  SELECT
+    n.nspname::text
+    , c.relname::text
+    , a.attname::text
+    , a.attname::text
+    , 'column'::text
+  FROM pg_catalog.pg_attribute AS a
+  INNER JOIN pg_catalog.pg_class AS c ON c.oid = a.attrelid
+  INNER JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+  WHERE
+    a.attnum > 0
+    AND NOT a.attisdropped
+    AND c.relkind IN ('r', 'p', 'v', 'm', 'f')
+    AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
+    AND n.nspname NOT LIKE 'pg\_temp\_%'
+    AND NOT EXISTS (
+      SELECT 1 FROM pg_catalog.pg_depend AS d
+      WHERE d.classid = 'pg_catalog.pg_class'::regclass AND d.objid = c.oid AND d.deptype = 'e'
+    )
+)
+SELECT
+  o.schema_name
+  , o.table_name
+  , o.column_name
+  , o.object_kind
+  , EXISTS (
+    SELECT 1 FROM pg_catalog.pg_get_keywords() AS k
+    WHERE k.word = o.object_name AND k.catcode IN ('R', 'T')
+  ) AS reserved_word
+  , octet_length(o.object_name)::int AS name_bytes
+FROM objects AS o
+WHERE
+  quote_ident(o.object_name) <> o.object_name
+  OR octet_length(o.object_name) >= 63
+ORDER BY o.schema_name, o.table_name, o.column_name <> '', o.column_name
+`
+
+type NamingIssuesRow struct {
+	SchemaName   string
+	TableName    string
+	ColumnName   string
+	ObjectKind   string
+	ReservedWord bool
+	NameBytes    int32
+}
+
+// Relations and their columns, outside system schemas and extensions, whose
+// names need quoting or are at the 63-byte limit (NAMEDATALEN - 1) that
+// longer names are cut to. reserved_word marks keywords that cannot be a
+// table or column name unquoted (reserved, and type or function names).
+func (q *Queries) NamingIssues(ctx context.Context) ([]NamingIssuesRow, error) {
+	rows, err := q.db.Query(ctx, namingIssues)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NamingIssuesRow
+	for rows.Next() {
+		var i NamingIssuesRow
+		if err := rows.Scan(
+			&i.SchemaName,
+			&i.TableName,
+			&i.ColumnName,
+			&i.ObjectKind,
+			&i.ReservedWord,
+			&i.NameBytes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const neverVacuumedTables = `-- name: NeverVacuumedTables :many
+SELECT
+  (s.schemaname || '.' || s.relname)::text AS table_name
+  , s.n_live_tup
+  , s.n_dead_tup
+  , s.n_tup_ins + s.n_tup_upd + s.n_tup_del AS total_changes
+  , EXTRACT(EPOCH FROM now() - d.stats_reset)::bigint AS stats_age_seconds
+FROM pg_stat_user_tables AS s
+INNER JOIN pg_stat_database AS d ON d.datname = current_database()
+WHERE
+  s.last_vacuum IS NULL
+  AND s.last_autovacuum IS NULL
+  AND s.n_live_tup + s.n_dead_tup >= $1::bigint
+ORDER BY s.n_dead_tup DESC, s.n_live_tup DESC
+`
+
+type NeverVacuumedTablesRow struct {
+	TableName       string
+	NLiveTup        pgtype.Int8
+	NDeadTup        pgtype.Int8
+	TotalChanges    pgtype.Int8
+	StatsAgeSeconds pgtype.Int8
+}
+
+// Tables with no recorded manual or automatic vacuum, largest pending work
+// first. last_vacuum/last_autovacuum are cleared by a statistics reset, so the
+// age of the database's statistics is returned alongside.
+func (q *Queries) NeverVacuumedTables(ctx context.Context, minTuples int64) ([]NeverVacuumedTablesRow, error) {
+	rows, err := q.db.Query(ctx, neverVacuumedTables, minTuples)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NeverVacuumedTablesRow
+	for rows.Next() {
+		var i NeverVacuumedTablesRow
+		if err := rows.Scan(
+			&i.TableName,
+			&i.NLiveTup,
+			&i.NDeadTup,
+			&i.TotalChanges,
+			&i.StatsAgeSeconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const pGVersion = `-- name: PGVersion :one
 SELECT
   current_setting('server_version_num')::integer / 10000 AS major
@@ -1006,6 +2760,162 @@ func (q *Queries) PGVersion(ctx context.Context) (PGVersionRow, error) {
 	return i, err
 }
 
+const parallelQuery = `-- name: ParallelQuery :one
+SELECT
+  current_setting('max_worker_processes')::integer AS max_worker_processes
+  , current_setting('max_parallel_workers')::integer AS max_parallel_workers
+  , current_setting('max_parallel_workers_per_gather')::integer AS max_parallel_workers_per_gather
+  , current_setting('parallel_setup_cost')::float8 AS parallel_setup_cost
+`
+
+type ParallelQueryRow struct {
+	MaxWorkerProcesses          int32
+	MaxParallelWorkers          int32
+	MaxParallelWorkersPerGather int32
+	ParallelSetupCost           float64
+}
+
+// The settings that size parallel query, as the session sees them:
+// max_parallel_workers_per_gather and parallel_setup_cost can be overridden
+// per database and role, the worker pools only per server.
+func (q *Queries) ParallelQuery(ctx context.Context) (ParallelQueryRow, error) {
+	row := q.db.QueryRow(ctx, parallelQuery)
+	var i ParallelQueryRow
+	err := row.Scan(
+		&i.MaxWorkerProcesses,
+		&i.MaxParallelWorkers,
+		&i.MaxParallelWorkersPerGather,
+		&i.ParallelSetupCost,
+	)
+	return i, err
+}
+
+const partitionFreezeSkew = `-- name: PartitionFreezeSkew :many
+WITH leaves AS (
+  SELECT
+    pg_partition_root(c.oid) AS root_oid
+    , c.oid
+    , age(c.relfrozenxid)::bigint AS freeze_age
+  FROM pg_class AS c
+  WHERE
+    c.relispartition
+    AND c.relkind = 'r'
+    AND c.relfrozenxid != '0'
+)
+
+, ranked AS (
+  SELECT
+    root_oid
+    , oid
+    , freeze_age
+    , row_number() OVER (PARTITION BY root_oid ORDER BY freeze_age DESC) AS age_rank
+    , min(freeze_age) OVER (PARTITION BY root_oid) AS youngest_age
+    , count(*) OVER (PARTITION BY root_oid) AS partition_count
+  FROM leaves
+)
+
+SELECT
+  (rn.nspname || '.' || r.relname)::text AS parent_table
+  , (pn.nspname || '.' || p.relname)::text AS oldest_partition
+  , o.freeze_age AS oldest_age
+  , o.youngest_age
+  , o.partition_count
+  , (
+    SELECT s.setting::bigint FROM pg_settings AS s
+    WHERE s.name = 'autovacuum_freeze_max_age'
+  ) AS freeze_max_age
+FROM ranked AS o
+INNER JOIN pg_class AS r ON o.root_oid = r.oid
+INNER JOIN pg_namespace AS rn ON r.relnamespace = rn.oid
+INNER JOIN pg_class AS p ON o.oid = p.oid
+INNER JOIN pg_namespace AS pn ON p.relnamespace = pn.oid
+WHERE
+  o.age_rank = 1
+  AND o.partition_count >= 2
+  AND rn.nspname NOT IN ('pg_catalog', 'information_schema')
+ORDER BY o.freeze_age DESC
+`
+
+type PartitionFreezeSkewRow struct {
+	ParentTable     string
+	OldestPartition string
+	OldestAge       int64
+	YoungestAge     int64
+	PartitionCount  int64
+	FreezeMaxAge    pgtype.Int8
+}
+
+// For each partitioned table with two or more leaf partitions, the partition
+// with the oldest relfrozenxid, its age, and the age of the most recently
+// frozen partition. Partitions are grouped by their top-level parent.
+func (q *Queries) PartitionFreezeSkew(ctx context.Context) ([]PartitionFreezeSkewRow, error) {
+	rows, err := q.db.Query(ctx, partitionFreezeSkew)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PartitionFreezeSkewRow
+	for rows.Next() {
+		var i PartitionFreezeSkewRow
+		if err := rows.Scan(
+			&i.ParentTable,
+			&i.OldestPartition,
+			&i.OldestAge,
+			&i.YoungestAge,
+			&i.PartitionCount,
+			&i.FreezeMaxAge,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const partitionPruning = `-- name: PartitionPruning :one
+SELECT
+  current_setting('enable_partition_pruning')::text AS enable_partition_pruning
+  , current_setting('constraint_exclusion')::text AS constraint_exclusion
+  , (
+    SELECT count(*)::int
+    FROM pg_catalog.pg_class AS c
+    INNER JOIN pg_catalog.pg_namespace AS n ON c.relnamespace = n.oid
+    WHERE c.relkind = 'p' AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+  ) AS partitioned_tables
+  , (
+    SELECT count(DISTINCT i.inhparent)::int
+    FROM pg_catalog.pg_inherits AS i
+    INNER JOIN pg_catalog.pg_class AS p ON i.inhparent = p.oid
+    WHERE p.relkind = 'r'
+  ) AS inheritance_parents
+`
+
+type PartitionPruningRow struct {
+	EnablePartitionPruning string
+	ConstraintExclusion    string
+	PartitionedTables      int32
+	InheritanceParents     int32
+}
+
+// The pruning settings as the current session sees them, so ALTER DATABASE
+// and ALTER ROLE overrides are included, with the number of declaratively
+// partitioned tables and of tables partitioned by inheritance (parents of
+// plain tables) in the current database.
+func (q *Queries) PartitionPruning(ctx context.Context) (PartitionPruningRow, error) {
+	row := q.db.QueryRow(ctx, partitionPruning)
+	var i PartitionPruningRow
+	err := row.Scan(
+		&i.EnablePartitionPruning,
+		&i.ConstraintExclusion,
+		&i.PartitionedTables,
+		&i.InheritanceParents,
+	)
+	return i, err
+}
+
 const partitionedTablesWithKeys = `-- name: PartitionedTablesWithKeys :many
 WITH partition_stats AS (
   -- Single aggregation of all partition metrics from child tables
@@ -1097,6 +3007,267 @@ func (q *Queries) PartitionedTablesWithKeys(ctx context.Context) ([]PartitionedT
 	return items, nil
 }
 
+const pendingRestartSettings = `-- name: PendingRestartSettings :many
+SELECT
+  s.name::text AS name
+  , current_setting(s.name)::text AS running_value
+FROM pg_settings AS s
+WHERE s.pending_restart
+ORDER BY s.name
+`
+
+type PendingRestartSettingsRow struct {
+	Name         string
+	RunningValue string
+}
+
+// Parameters changed in the configuration files but only applied at server
+// start, with the value the server is running with, in its unit.
+func (q *Queries) PendingRestartSettings(ctx context.Context) ([]PendingRestartSettingsRow, error) {
+	rows, err := q.db.Query(ctx, pendingRestartSettings)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PendingRestartSettingsRow
+	for rows.Next() {
+		var i PendingRestartSettingsRow
+		if err := rows.Scan(
+			&i.Name,
+			&i.RunningValue,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const plaintextPasswordMappings = `-- name: PlaintextPasswordMappings :many
+SELECT
+  srvname::text AS server_name
+  , usename::text AS user_name
+FROM pg_catalog.pg_user_mappings
+WHERE EXISTS (SELECT 1 FROM unnest(umoptions) AS o WHERE o LIKE 'password=%')
+ORDER BY srvname, usename
+`
+
+type PlaintextPasswordMappingsRow struct {
+	ServerName string
+	UserName   string
+}
+
+// User mappings that store a password in their options. pg_user_mappings only
+// shows options to superusers and to the mapped user, so other mappings are
+// not visible to an unprivileged role.
+func (q *Queries) PlaintextPasswordMappings(ctx context.Context) ([]PlaintextPasswordMappingsRow, error) {
+	rows, err := q.db.Query(ctx, plaintextPasswordMappings)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PlaintextPasswordMappingsRow
+	for rows.Next() {
+		var i PlaintextPasswordMappingsRow
+		if err := rows.Scan(
+			&i.ServerName,
+			&i.UserName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const preciseTableBloat = `-- name: PreciseTableBloat :many
+WITH candidates AS (
+  SELECT
+    s.relid
+    , (s.schemaname || '.' || s.relname)::text AS table_name
+    , CASE
+      WHEN s.n_tup_upd > 0
+        THEN (s.n_tup_hot_upd::float8 / s.n_tup_upd * 100)
+    END AS hot_update_percent
+  FROM pg_stat_user_tables AS s
+  WHERE
+    s.n_dead_tup > 0
+    AND pg_relation_size(s.relid) >= 8 * 1024 * 1024
+  ORDER BY s.n_dead_tup DESC
+  LIMIT $1::int
+)
+SELECT
+  c.table_name
+  , st.table_len
+  , st.dead_tuple_len
+  , st.dead_tuple_percent
+  , st.free_space
+  , st.free_percent
+  , c.hot_update_percent
+FROM candidates AS c
+CROSS JOIN LATERAL pgstattuple(c.relid) AS st
+ORDER BY (st.dead_tuple_len + st.free_space) DESC
+`
+
+type PreciseTableBloatRow struct {
+	TableName        string
+	TableLen         pgtype.Int8
+	DeadTupleLen     pgtype.Int8
+	DeadTuplePercent pgtype.Float8
+	FreeSpace        pgtype.Int8
+	FreePercent      pgtype.Float8
+	HotUpdatePercent pgtype.Float8
+}
+
+// Exact dead tuple and free space figures from pgstattuple() for the tables
+// (8MB or larger) with the most dead tuples. pgstattuple reads every page of
+// each table, so the number of tables scanned is capped by max_tables.
+func (q *Queries) PreciseTableBloat(ctx context.Context, maxTables int32) ([]PreciseTableBloatRow, error) {
+	rows, err := q.db.Query(ctx, preciseTableBloat, maxTables)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PreciseTableBloatRow
+	for rows.Next() {
+		var i PreciseTableBloatRow
+		if err := rows.Scan(
+			&i.TableName,
+			&i.TableLen,
+			&i.DeadTupleLen,
+			&i.DeadTuplePercent,
+			&i.FreeSpace,
+			&i.FreePercent,
+			&i.HotUpdatePercent,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const preloadLibraries = `-- name: PreloadLibraries :one
+SELECT
+  coalesce(s.setting, '') AS libraries
+  , coalesce(s.pending_restart, false) AS pending_restart
+FROM pg_settings AS s
+WHERE s.name = 'shared_preload_libraries'
+`
+
+type PreloadLibrariesRow struct {
+	Libraries      string
+	PendingRestart bool
+}
+
+// The running shared_preload_libraries, and whether a changed value is
+// waiting for a restart to take effect.
+func (q *Queries) PreloadLibraries(ctx context.Context) (PreloadLibrariesRow, error) {
+	row := q.db.QueryRow(ctx, preloadLibraries)
+	var i PreloadLibrariesRow
+	err := row.Scan(
+		&i.Libraries,
+		&i.PendingRestart,
+	)
+	return i, err
+}
+
+const publicationTables = `-- name: PublicationTables :many
+SELECT
+  pubname::text AS publication_name
+  , (schemaname || '.' || tablename)::text AS table_name
+FROM pg_publication_tables
+ORDER BY pubname, schemaname, tablename
+`
+
+type PublicationTablesRow struct {
+	PublicationName string
+	TableName       string
+}
+
+// Tables each publication replicates. FOR ALL TABLES (and, on PG 15+,
+// FOR TABLES IN SCHEMA) publications are expanded by the view.
+func (q *Queries) PublicationTables(ctx context.Context) ([]PublicationTablesRow, error) {
+	rows, err := q.db.Query(ctx, publicationTables)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PublicationTablesRow
+	for rows.Next() {
+		var i PublicationTablesRow
+		if err := rows.Scan(
+			&i.PublicationName,
+			&i.TableName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const publications = `-- name: Publications :many
+SELECT
+  p.pubname::text AS publication_name
+  , p.puballtables
+  , p.pubinsert
+  , p.pubupdate
+  , p.pubdelete
+  , p.pubtruncate
+FROM pg_publication AS p
+ORDER BY p.pubname
+`
+
+type PublicationsRow struct {
+	PublicationName string
+	Puballtables    bool
+	Pubinsert       bool
+	Pubupdate       bool
+	Pubdelete       bool
+	Pubtruncate     bool
+}
+
+// Every publication in the current database with the operations it publishes.
+func (q *Queries) Publications(ctx context.Context) ([]PublicationsRow, error) {
+	rows, err := q.db.Query(ctx, publications)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PublicationsRow
+	for rows.Next() {
+		var i PublicationsRow
+		if err := rows.Scan(
+			&i.PublicationName,
+			&i.Puballtables,
+			&i.Pubinsert,
+			&i.Pubupdate,
+			&i.Pubdelete,
+			&i.Pubtruncate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const queryStatsFromStatStatements = `-- name: QueryStatsFromStatStatements :many
 SELECT
   queryid::bigint AS query_id
@@ -1156,6 +3327,323 @@ func (q *Queries) QueryStatsFromStatStatements(ctx context.Context) ([]QueryStat
 	return items, nil
 }
 
+const recentBackends = `-- name: RecentBackends :one
+SELECT
+  count(*)::bigint AS client_backends
+  , count(*) FILTER (WHERE a.backend_start > now() - interval '60 seconds')::bigint AS recent_backends
+FROM pg_catalog.pg_stat_activity AS a
+WHERE a.backend_type = 'client backend'
+`
+
+type RecentBackendsRow struct {
+	ClientBackends int64
+	RecentBackends int64
+}
+
+// Client backends now connected, and how many of them connected within the
+// last minute: the fallback sample for servers without session statistics.
+func (q *Queries) RecentBackends(ctx context.Context) (RecentBackendsRow, error) {
+	row := q.db.QueryRow(ctx, recentBackends)
+	var i RecentBackendsRow
+	err := row.Scan(
+		&i.ClientBackends,
+		&i.RecentBackends,
+	)
+	return i, err
+}
+
+const redundantPrimaryKeyIndexes = `-- name: RedundantPrimaryKeyIndexes :many
+SELECT
+  (n.nspname || '.' || t.relname)::text AS table_name
+  , i.relname::text AS index_name
+  , pk.relname::text AS primary_key_name
+  , (
+    SELECT string_agg(a.attname, ', ' ORDER BY k.ord)
+    FROM unnest(idx.indkey) WITH ORDINALITY AS k (attnum, ord)
+    INNER JOIN pg_attribute AS a ON a.attrelid = idx.indrelid AND k.attnum = a.attnum
+  )::text AS index_columns
+  , pg_relation_size(i.oid)::bigint AS index_size_bytes
+  , coalesce(con.conname, '')::text AS constraint_name
+FROM pg_index AS pkidx
+INNER JOIN pg_index AS idx ON
+  pkidx.indrelid = idx.indrelid
+  AND pkidx.indexrelid <> idx.indexrelid
+INNER JOIN pg_class AS pk ON pkidx.indexrelid = pk.oid
+INNER JOIN pg_class AS i ON idx.indexrelid = i.oid
+INNER JOIN pg_class AS t ON idx.indrelid = t.oid
+INNER JOIN pg_namespace AS n ON t.relnamespace = n.oid
+INNER JOIN pg_am AS am ON i.relam = am.oid
+LEFT JOIN pg_constraint AS con ON idx.indexrelid = con.conindid AND con.conrelid = t.oid
+WHERE
+  pkidx.indisprimary
+  AND i.relkind = 'i'
+  AND idx.indisvalid
+  AND am.amname = 'btree'
+  AND idx.indexprs IS NULL
+  AND idx.indpred IS NULL
+  AND idx.indnatts = idx.indnkeyatts
+  AND idx.indnkeyatts <= pkidx.indnkeyatts
+  AND (NOT idx.indisunique OR idx.indnkeyatts = pkidx.indnkeyatts)
+  -- The vectors' text forms are space-separated, so a prefix match on
+  -- "<vector> " compares whole entries.
+  AND (pkidx.indkey::text || ' ') LIKE (idx.indkey::text || ' %')
+  AND (pkidx.indclass::text || ' ') LIKE (idx.indclass::text || ' %')
+  AND (pkidx.indcollation::text || ' ') LIKE (idx.indcollation::text || ' %')
+  AND (pkidx.indoption::text || ' ') LIKE (idx.indoption::text || ' %')
+  AND NOT EXISTS (
+    SELECT 1
+    FROM pg_constraint AS fk
+    WHERE fk.contype = 'f' AND idx.indexrelid = fk.conindid
+  )
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast', 'cron', 'pgpartman', 'debezium')
+ORDER BY
+  index_size_bytes DESC
+  , table_name
+  , index_name
+`
+
+type RedundantPrimaryKeyIndexesRow struct {
+	TableName      string
+	IndexName      string
+	PrimaryKeyName string
+	IndexColumns   string
+	IndexSizeBytes int64
+	ConstraintName string
+}
+
+// Secondary btree indexes the primary key index already provides: their key
+// columns are the leading key columns of the primary key, with the same
+// operator classes, collations, and sort options. Unique indexes only count
+// when they cover every primary key column, since a unique index on a prefix
+// enforces more than the primary key does.
+// Excludes: expression, partial, and INCLUDE indexes (not replaceable by the
+// primary key), indexes referenced by foreign keys, and system schemas.
+func (q *Queries) RedundantPrimaryKeyIndexes(ctx context.Context) ([]RedundantPrimaryKeyIndexesRow, error) {
+	rows, err := q.db.Query(ctx, redundantPrimaryKeyIndexes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RedundantPrimaryKeyIndexesRow
+	for rows.Next() {
+		var i RedundantPrimaryKeyIndexesRow
+		if err := rows.Scan(
+			&i.TableName,
+			&i.IndexName,
+			&i.PrimaryKeyName,
+			&i.IndexColumns,
+			&i.IndexSizeBytes,
+			&i.ConstraintName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const replicationCapacity = `-- name: ReplicationCapacity :one
+SELECT
+  current_setting('max_wal_senders')::int AS max_wal_senders
+  , current_setting('max_replication_slots')::int AS max_replication_slots
+  , current_setting('wal_level')::text AS wal_level
+  , (SELECT count(*) FROM pg_stat_replication)::int AS wal_senders
+  , (SELECT count(*) FROM pg_replication_slots)::int AS slots
+  , (SELECT count(*) FROM pg_replication_slots WHERE slot_type = 'logical')::int AS logical_slots
+`
+
+type ReplicationCapacityRow struct {
+	MaxWalSenders       int32
+	MaxReplicationSlots int32
+	WalLevel            string
+	WalSenders          int32
+	Slots               int32
+	LogicalSlots        int32
+}
+
+// Replication settings and how much of them is in use. pg_stat_replication
+// lists every WAL sender, including base backups streaming WAL.
+func (q *Queries) ReplicationCapacity(ctx context.Context) (ReplicationCapacityRow, error) {
+	row := q.db.QueryRow(ctx, replicationCapacity)
+	var i ReplicationCapacityRow
+	err := row.Scan(
+		&i.MaxWalSenders,
+		&i.MaxReplicationSlots,
+		&i.WalLevel,
+		&i.WalSenders,
+		&i.Slots,
+		&i.LogicalSlots,
+	)
+	return i, err
+}
+
+const replicationFilters = `-- name: ReplicationFilters :many
+WITH filtered AS (
+  SELECT
+    pr.prpubid
+    , pr.prrelid
+    , pr.prqual
+    , pr.prattrs
+  FROM pg_publication_rel AS pr
+  WHERE pr.prqual IS NOT NULL OR pr.prattrs IS NOT NULL
+)
+
+, filter_columns AS (
+  SELECT DISTINCT
+    f.prpubid
+    , f.prrelid
+    , m[1]::smallint AS attnum
+  FROM filtered AS f
+  CROSS JOIN LATERAL regexp_matches(f.prqual::text, ':varattno (\d+)', 'g') AS m
+)
+
+, identity_columns AS (
+  SELECT
+    c.oid AS relid
+    , a.attnum
+  FROM pg_class AS c
+  INNER JOIN pg_attribute AS a ON a.attrelid = c.oid
+  WHERE
+    c.oid IN (SELECT f.prrelid FROM filtered AS f)
+    AND a.attnum > 0
+    AND NOT a.attisdropped
+    AND (
+      c.relreplident = 'f'
+      OR EXISTS (
+        SELECT 1
+        FROM pg_index AS i
+        WHERE
+          i.indrelid = c.oid
+          AND a.attnum = ANY(i.indkey)
+          AND (
+            (c.relreplident = 'd' AND i.indisprimary)
+            OR (c.relreplident = 'i' AND i.indisreplident)
+          )
+      )
+    )
+)
+
+SELECT
+  p.pubname::text AS publication_name
+  , (n.nspname || '.' || c.relname)::text AS table_name
+  , coalesce(pg_get_expr(f.prqual, f.prrelid), '')::text AS row_filter
+  , f.prattrs IS NOT NULL AS has_column_list
+  , coalesce((
+    SELECT array_agg(a.attname ORDER BY a.attnum)
+    FROM pg_attribute AS a
+    WHERE a.attrelid = f.prrelid AND a.attnum = ANY(f.prattrs)
+  ), '{}')::text[] AS column_list
+  , CASE c.relreplident
+    WHEN 'd' THEN 'default'
+    WHEN 'n' THEN 'nothing'
+    WHEN 'f' THEN 'full'
+    WHEN 'i' THEN 'index'
+  END::text AS replica_identity
+  , coalesce((
+    SELECT array_agg(a.attname ORDER BY a.attnum)
+    FROM identity_columns AS ic
+    INNER JOIN pg_attribute AS a ON a.attrelid = ic.relid AND a.attnum = ic.attnum
+    WHERE ic.relid = f.prrelid
+  ), '{}')::text[] AS identity_columns
+  , coalesce((
+    SELECT array_agg(a.attname ORDER BY a.attnum)
+    FROM filter_columns AS fc
+    INNER JOIN pg_attribute AS a ON a.attrelid = fc.prrelid AND a.attnum = fc.attnum
+    WHERE fc.prpubid = f.prpubid AND fc.prrelid = f.prrelid
+  ), '{}')::text[] AS filter_columns
+  , coalesce((
+    SELECT array_agg(a.attname ORDER BY a.attnum)
+    FROM filter_columns AS fc
+    INNER JOIN pg_attribute AS a ON a.attrelid = fc.prrelid AND a.attnum = fc.attnum
+    WHERE fc.prpubid = f.prpubid AND fc.prrelid = f.prrelid AND NOT a.attnotnull
+  ), '{}')::text[] AS nullable_filter_columns
+  , p.pubupdate OR p.pubdelete AS publishes_changes
+  , c.relkind = 'p' AS partitioned
+  , p.pubviaroot AS via_partition_root
+  , EXISTS (
+    SELECT 1
+    FROM pg_partition_ancestors(c.oid) AS pa
+    INNER JOIN pg_publication_rel AS ar ON ar.prrelid = pa.relid
+    WHERE pa.relid <> c.oid AND ar.prpubid = p.oid
+  ) AS ancestor_published
+FROM filtered AS f
+INNER JOIN pg_publication AS p ON p.oid = f.prpubid
+INNER JOIN pg_class AS c ON c.oid = f.prrelid
+INNER JOIN pg_namespace AS n ON n.oid = c.relnamespace
+ORDER BY p.pubname, n.nspname, c.relname
+`
+
+type ReplicationFiltersRow struct {
+	PublicationName       string
+	TableName             string
+	RowFilter             string
+	HasColumnList         bool
+	ColumnList            []string
+	ReplicaIdentity       string
+	IdentityColumns       []string
+	FilterColumns         []string
+	NullableFilterColumns []string
+	PublishesChanges      bool
+	Partitioned           bool
+	ViaPartitionRoot      bool
+	AncestorPublished     bool
+}
+
+// Tables published with a row filter or a column list (PostgreSQL 15+), with
+// the columns the filter reads, taken from the Vars in its parsed tree, and
+// the table's replica identity columns: every column for REPLICA IDENTITY
+// FULL, the index's for USING INDEX, the primary key's for DEFAULT, and none
+// for NOTHING or a DEFAULT without a primary key.
+func (q *Queries) ReplicationFilters(ctx context.Context) ([]ReplicationFiltersRow, error) {
+	rows, err := q.db.Query(ctx, replicationFilters)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReplicationFiltersRow
+	for rows.Next() {
+		var i ReplicationFiltersRow
+		if err := rows.Scan(
+			&i.PublicationName,
+			&i.TableName,
+			&i.RowFilter,
+			&i.HasColumnList,
+			&i.ColumnList,
+			&i.ReplicaIdentity,
+			&i.IdentityColumns,
+			&i.FilterColumns,
+			&i.NullableFilterColumns,
+			&i.PublishesChanges,
+			&i.Partitioned,
+			&i.ViaPartitionRoot,
+			&i.AncestorPublished,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const replicationFiltersSupported = `-- name: ReplicationFiltersSupported :one
+SELECT current_setting('server_version_num')::integer >= 150000 AS supported
+`
+
+// Publications take row filters and column lists, in pg_publication_rel's
+// prqual and prattrs, from PostgreSQL 15.
+func (q *Queries) ReplicationFiltersSupported(ctx context.Context) (bool, error) {
+	row := q.db.QueryRow(ctx, replicationFiltersSupported)
+	var supported bool
+	err := row.Scan(&supported)
+	return supported, err
+}
+
 const replicationLag = `-- name: ReplicationLag :many
 SELECT
   -- Consumer/replica identity
@@ -1407,6 +3895,89 @@ func (q *Queries) ReplicationSlotsPG15(ctx context.Context) ([]ReplicationSlotsP
 	return items, nil
 }
 
+const roleMemberships = `-- name: RoleMemberships :many
+SELECT
+  m.rolname::text AS member_name
+  , r.rolname::text AS role_name
+  , coalesce(g.rolname, '')::text AS grantor_name
+  , am.admin_option
+FROM pg_auth_members AS am
+INNER JOIN pg_roles AS m ON m.oid = am.member
+INNER JOIN pg_roles AS r ON r.oid = am.roleid
+LEFT JOIN pg_roles AS g ON g.oid = am.grantor
+ORDER BY m.rolname, r.rolname, g.rolname
+`
+
+type RoleMembershipsRow struct {
+	MemberName  string
+	RoleName    string
+	GrantorName string
+	AdminOption bool
+}
+
+// Every direct role membership in the cluster, one row per grant. Since
+// PostgreSQL 16 the same membership can be granted more than once, by
+// different grantors. Memberships among the predefined pg_* roles are
+// included so paths through them, such as pg_monitor, are followed.
+func (q *Queries) RoleMemberships(ctx context.Context) ([]RoleMembershipsRow, error) {
+	rows, err := q.db.Query(ctx, roleMemberships)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RoleMembershipsRow
+	for rows.Next() {
+		var i RoleMembershipsRow
+		if err := rows.Scan(
+			&i.MemberName,
+			&i.RoleName,
+			&i.GrantorName,
+			&i.AdminOption,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const seqScanSampleStatement = `-- name: SeqScanSampleStatement :one
+SELECT
+  query::text AS query
+  , calls::bigint AS calls
+  , mean_exec_time::double precision AS mean_exec_time
+FROM pg_stat_statements
+WHERE
+  dbid = (SELECT oid FROM pg_database WHERE datname = current_database())
+  AND query ~* '^\s*select\M'
+  AND query ~* $1::text
+ORDER BY total_exec_time DESC
+LIMIT 1
+`
+
+type SeqScanSampleStatementRow struct {
+	Query        pgtype.Text
+	Calls        pgtype.Int8
+	MeanExecTime pgtype.Float8
+}
+
+// The most time-consuming SELECT in pg_stat_statements whose text matches
+// pattern (a word-bounded regular expression for a table name). Used as a
+// representative query to EXPLAIN for a table with heavy sequential scans.
+func (q *Queries) SeqScanSampleStatement(ctx context.Context, pattern string) (SeqScanSampleStatementRow, error) {
+	row := q.db.QueryRow(ctx, seqScanSampleStatement, pattern)
+	var i SeqScanSampleStatementRow
+	err := row.Scan(
+		&i.Query,
+		&i.Calls,
+		&i.MeanExecTime,
+	)
+	return i, err
+}
+
 const sequenceHealth = `-- name: SequenceHealth :many
 WITH sequence_info AS (
   SELECT
@@ -1574,6 +4145,93 @@ func (q *Queries) SequenceHealth(ctx context.Context) ([]SequenceHealthRow, erro
 	return items, nil
 }
 
+const serverUptime = `-- name: ServerUptime :one
+SELECT
+  EXTRACT(EPOCH FROM now() - pg_postmaster_start_time())::bigint AS uptime_seconds
+  , (to_char(pg_postmaster_start_time() AT TIME ZONE 'UTC', 'YYYY-MM-DD HH24:MI') || ' UTC')::text AS started_at
+  , current_database()::text AS database_name
+  , coalesce(EXTRACT(EPOCH FROM now() - d.stats_reset)::bigint, -1)::bigint AS stats_reset_age_seconds
+FROM pg_stat_database AS d
+WHERE d.datname = current_database()
+`
+
+type ServerUptimeRow struct {
+	UptimeSeconds        int64
+	StartedAt            string
+	DatabaseName         string
+	StatsResetAgeSeconds int64
+}
+
+// Time since the postmaster started, and since the statistics of the current
+// database were last reset. stats_reset is NULL until the first reset, which
+// stats_reset_age_seconds reports as -1.
+func (q *Queries) ServerUptime(ctx context.Context) (ServerUptimeRow, error) {
+	row := q.db.QueryRow(ctx, serverUptime)
+	var i ServerUptimeRow
+	err := row.Scan(
+		&i.UptimeSeconds,
+		&i.StartedAt,
+		&i.DatabaseName,
+		&i.StatsResetAgeSeconds,
+	)
+	return i, err
+}
+
+const sessionChurn = `-- name: SessionChurn :many
+SELECT
+  d.datname::text AS database_name
+  , d.sessions::bigint AS sessions
+  , (d.xact_commit + d.xact_rollback)::bigint AS transactions
+  , coalesce(d.session_time, 0)::double precision AS session_time_ms
+  , extract(EPOCH FROM now() - d.stats_reset)::bigint AS seconds_since_reset
+  , extract(EPOCH FROM now() - pg_postmaster_start_time())::bigint AS uptime_seconds
+FROM pg_catalog.pg_stat_database AS d
+WHERE
+  d.datname IS NOT NULL
+  AND d.datname NOT IN ('template0', 'template1')
+ORDER BY d.sessions DESC, d.datname
+`
+
+type SessionChurnRow struct {
+	DatabaseName      string
+	Sessions          int64
+	Transactions      int64
+	SessionTimeMs     float64
+	SecondsSinceReset pgtype.Int8
+	UptimeSeconds     int64
+}
+
+// Sessions opened in each database since its statistics were reset
+// (PostgreSQL 14+), with the transactions and session time they ran, the
+// seconds since the reset, NULL when the statistics were never reset, and the
+// server's uptime.
+func (q *Queries) SessionChurn(ctx context.Context) ([]SessionChurnRow, error) {
+	rows, err := q.db.Query(ctx, sessionChurn)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SessionChurnRow
+	for rows.Next() {
+		var i SessionChurnRow
+		if err := rows.Scan(
+			&i.DatabaseName,
+			&i.Sessions,
+			&i.Transactions,
+			&i.SessionTimeMs,
+			&i.SecondsSinceReset,
+			&i.UptimeSeconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const sessionSettings = `-- name: SessionSettings :many
 /*
  * PostgreSQL GUC precedence for ALTER ROLE / ALTER DATABASE settings,
@@ -1793,6 +4451,119 @@ func (q *Queries) SessionStatistics(ctx context.Context) (SessionStatisticsRow,
 	return i, err
 }
 
+const sparseColumns = `-- name: SparseColumns :many
+SELECT
+  (s.schemaname || '.' || s.tablename)::text AS table_name
+  , s.attname::text AS column_name
+  , s.null_frac::float8 AS null_frac
+  , c.reltuples::bigint AS estimated_rows
+FROM pg_catalog.pg_stats AS s
+INNER JOIN pg_catalog.pg_namespace AS n ON n.nspname = s.schemaname
+INNER JOIN pg_catalog.pg_class AS c ON c.relnamespace = n.oid AND c.relname = s.tablename
+WHERE
+  n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
+  AND c.relkind IN ('r', 'm')
+  AND NOT s.inherited
+  AND s.null_frac > 0
+ORDER BY c.reltuples DESC, s.null_frac DESC, table_name, column_name
+`
+
+type SparseColumnsRow struct {
+	TableName     string
+	ColumnName    string
+	NullFrac      pgtype.Float8
+	EstimatedRows pgtype.Int8
+}
+
+// Columns of user tables with at least some NULLs, per the planner statistics
+// gathered by ANALYZE. The null fraction and row thresholds are applied by the
+// check so they stay configurable.
+func (q *Queries) SparseColumns(ctx context.Context) ([]SparseColumnsRow, error) {
+	rows, err := q.db.Query(ctx, sparseColumns)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SparseColumnsRow
+	for rows.Next() {
+		var i SparseColumnsRow
+		if err := rows.Scan(
+			&i.TableName,
+			&i.ColumnName,
+			&i.NullFrac,
+			&i.EstimatedRows,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const statementsInfo = `-- name: StatementsInfo :one
+SELECT
+  dealloc
+  , EXTRACT(EPOCH FROM now() - stats_reset)::bigint AS reset_age_seconds
+FROM pg_stat_statements_info
+`
+
+type StatementsInfoRow struct {
+	Dealloc         pgtype.Int8
+	ResetAgeSeconds pgtype.Int8
+}
+
+// dealloc counts statements evicted because the hash table was full, since
+// stats_reset.
+func (q *Queries) StatementsInfo(ctx context.Context) (StatementsInfoRow, error) {
+	row := q.db.QueryRow(ctx, statementsInfo)
+	var i StatementsInfoRow
+	err := row.Scan(
+		&i.Dealloc,
+		&i.ResetAgeSeconds,
+	)
+	return i, err
+}
+
+const statementsSettings = `-- name: StatementsSettings :one
+SELECT
+  current_setting('pg_stat_statements.max', true)::bigint AS max_statements
+  , to_regclass('pg_stat_statements_info') IS NOT NULL AS has_info
+`
+
+type StatementsSettingsRow struct {
+	MaxStatements pgtype.Int8
+	HasInfo       bool
+}
+
+// pg_stat_statements.max is only defined once the library is loaded via
+// shared_preload_libraries. pg_stat_statements_info exists from PG 14
+// (extension version 1.9).
+func (q *Queries) StatementsSettings(ctx context.Context) (StatementsSettingsRow, error) {
+	row := q.db.QueryRow(ctx, statementsSettings)
+	var i StatementsSettingsRow
+	err := row.Scan(
+		&i.MaxStatements,
+		&i.HasInfo,
+	)
+	return i, err
+}
+
+const statementsTracked = `-- name: StatementsTracked :one
+SELECT count(*)::bigint AS tracked_statements
+FROM pg_stat_statements
+`
+
+// Number of distinct statements currently tracked.
+func (q *Queries) StatementsTracked(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, statementsTracked)
+	var tracked_statements int64
+	err := row.Scan(&tracked_statements)
+	return tracked_statements, err
+}
+
 const statisticsFreshness = `-- name: StatisticsFreshness :one
 SELECT
   stats_reset
@@ -1820,6 +4591,58 @@ func (q *Queries) StatisticsFreshness(ctx context.Context) (StatisticsFreshnessR
 	return i, err
 }
 
+const subscriptionSlots = `-- name: SubscriptionSlots :many
+SELECT
+  s.subname::text AS subscription_name
+  , s.subslotname::text AS slot_name
+  , EXISTS (
+    SELECT 1
+    FROM pg_stat_subscription AS w
+    WHERE
+      w.subid = s.oid
+      AND w.relid IS NULL
+      AND w.pid IS NOT NULL
+  ) AS worker_running
+FROM pg_subscription AS s
+WHERE
+  s.subenabled
+  AND s.subdbid = (SELECT oid FROM pg_database WHERE datname = current_database())
+ORDER BY s.subname
+`
+
+type SubscriptionSlotsRow struct {
+	SubscriptionName string
+	SlotName         pgtype.Text
+	WorkerRunning    bool
+}
+
+// Enabled subscriptions in the current database and whether their apply worker
+// is streaming. A worker that is not running usually means the slot is missing
+// on the publisher or the connection fails.
+func (q *Queries) SubscriptionSlots(ctx context.Context) ([]SubscriptionSlotsRow, error) {
+	rows, err := q.db.Query(ctx, subscriptionSlots)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SubscriptionSlotsRow
+	for rows.Next() {
+		var i SubscriptionSlotsRow
+		if err := rows.Scan(
+			&i.SubscriptionName,
+			&i.SlotName,
+			&i.WorkerRunning,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const tableActivity = `-- name: TableActivity :many
 SELECT
   schemaname
@@ -2013,6 +4836,68 @@ func (q *Queries) TableFreezeAge(ctx context.Context) ([]TableFreezeAgeRow, erro
 	return items, nil
 }
 
+const tableGrants = `-- name: TableGrants :many
+SELECT
+  n.nspname::text AS schema_name
+  , c.relname::text AS table_name
+  , g.grantee
+  , array_agg(a.privilege_type ORDER BY a.privilege_type)::text[] AS privileges
+  , coalesce(bool_or(a.is_grantable), false) AS grantable
+FROM pg_class AS c
+JOIN pg_namespace AS n ON n.oid = c.relnamespace
+CROSS JOIN LATERAL aclexplode(c.relacl) AS a
+CROSS JOIN LATERAL (
+  SELECT
+    CASE WHEN a.grantee = 0 THEN 'PUBLIC' ELSE pg_get_userbyid(a.grantee) END::text AS grantee
+) AS g
+WHERE
+  c.relkind IN ('r', 'p', 'v', 'f')
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
+  AND n.nspname NOT LIKE 'pg_temp_%'
+  AND a.grantee <> c.relowner
+  AND g.grantee = ANY($1::text[])
+GROUP BY n.nspname, c.relname, g.grantee
+ORDER BY n.nspname, c.relname, g.grantee
+`
+
+type TableGrantsRow struct {
+	SchemaName string
+	TableName  string
+	Grantee    string
+	Privileges []string
+	Grantable  bool
+}
+
+// Privileges on tables, views, and foreign tables held by the given roles, one
+// row per table and grantee. 'PUBLIC' matches grants to every role. Owners'
+// own privileges are implicit and not listed; tables still on the default
+// ACL (relacl IS NULL) grant nothing to anyone else.
+func (q *Queries) TableGrants(ctx context.Context, grantees []string) ([]TableGrantsRow, error) {
+	rows, err := q.db.Query(ctx, tableGrants, grantees)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TableGrantsRow
+	for rows.Next() {
+		var i TableGrantsRow
+		if err := rows.Scan(
+			&i.SchemaName,
+			&i.TableName,
+			&i.Grantee,
+			&i.Privileges,
+			&i.Grantable,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const tableVacuumHealth = `-- name: TableVacuumHealth :many
 SELECT
   (n.nspname || '.' || c.relname)::text AS table_name
@@ -2061,22 +4946,158 @@ func (q *Queries) TableVacuumHealth(ctx context.Context) ([]TableVacuumHealthRow
 		return nil, err
 	}
 	defer rows.Close()
-	var items []TableVacuumHealthRow
+	var items []TableVacuumHealthRow
+	for rows.Next() {
+		var i TableVacuumHealthRow
+		if err := rows.Scan(
+			&i.TableName,
+			&i.LastAutovacuum,
+			&i.EstimatedRows,
+			&i.TableSizeBytes,
+			&i.NDeadTup,
+			&i.AutovacuumCount,
+			&i.Reloptions,
+			&i.LastVacuumAny,
+			&i.LastAnalyzeAny,
+			&i.NModSinceAnalyze,
+			&i.AutoanalyzeCount,
+			&i.NInsSinceVacuum,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const tablespacePlacement = `-- name: TablespacePlacement :many
+SELECT
+  n.nspname::text AS schema_name
+  , coalesce(tbl.relname, c.relname)::text AS table_name
+  , c.relname::text AS object_name
+  , CASE c.relkind
+    WHEN 'i' THEN 'index'
+    WHEN 'm' THEN 'matview'
+    ELSE 'table'
+  END::text AS object_kind
+  , coalesce(ts.spcname, dts.spcname)::text AS tablespace_name
+  , c.relpages::bigint * current_setting('block_size')::bigint AS size_bytes
+FROM pg_catalog.pg_class AS c
+INNER JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+INNER JOIN pg_catalog.pg_database AS d ON d.datname = current_database()
+INNER JOIN pg_catalog.pg_tablespace AS dts ON dts.oid = d.dattablespace
+LEFT JOIN pg_catalog.pg_tablespace AS ts ON ts.oid = c.reltablespace
+LEFT JOIN pg_catalog.pg_index AS i ON i.indexrelid = c.oid
+LEFT JOIN pg_catalog.pg_class AS tbl ON tbl.oid = i.indrelid
+WHERE
+  c.relkind IN ('r', 'm', 'i')
+  AND c.relpersistence <> 't'
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+  AND n.nspname NOT LIKE 'pg\_toast%'
+ORDER BY size_bytes DESC, n.nspname, c.relname
+`
+
+type TablespacePlacementRow struct {
+	SchemaName     string
+	TableName      string
+	ObjectName     string
+	ObjectKind     string
+	TablespaceName string
+	SizeBytes      int64
+}
+
+// Tables, materialized views, and indexes of the current database with the
+// tablespace they are stored in. reltablespace is 0 for the database's
+// default tablespace, which is named here rather than left implicit. Indexes
+// carry the table they belong to, which placement rules match on.
+func (q *Queries) TablespacePlacement(ctx context.Context) ([]TablespacePlacementRow, error) {
+	rows, err := q.db.Query(ctx, tablespacePlacement)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TablespacePlacementRow
+	for rows.Next() {
+		var i TablespacePlacementRow
+		if err := rows.Scan(
+			&i.SchemaName,
+			&i.TableName,
+			&i.ObjectName,
+			&i.ObjectKind,
+			&i.TablespaceName,
+			&i.SizeBytes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const tempRelations = `-- name: TempRelations :one
+SELECT
+  (
+    SELECT count(*) FROM pg_namespace
+    WHERE nspname LIKE 'pg\_temp\_%'
+  ) AS temp_schemas
+  , (
+    SELECT count(*) FROM pg_class
+    WHERE relpersistence = 't' AND relkind IN ('r', 'p')
+  ) AS temp_tables
+`
+
+type TempRelationsRow struct {
+	TempSchemas int64
+	TempTables  int64
+}
+
+// Temporary schemas and the temporary tables in them right now. A backend
+// gets a pg_temp_N schema the first time it creates a temporary table, and
+// the schema outlives the session, so the count is how many backend slots
+// have ever used temporary tables.
+func (q *Queries) TempRelations(ctx context.Context) (TempRelationsRow, error) {
+	row := q.db.QueryRow(ctx, tempRelations)
+	var i TempRelationsRow
+	err := row.Scan(
+		&i.TempSchemas,
+		&i.TempTables,
+	)
+	return i, err
+}
+
+const tempTablespaceLocations = `-- name: TempTablespaceLocations :many
+SELECT
+  spcname::text AS name
+  , pg_catalog.pg_tablespace_location(oid)::text AS location
+FROM pg_catalog.pg_tablespace
+ORDER BY spcname
+`
+
+type TempTablespaceLocationsRow struct {
+	Name     string
+	Location string
+}
+
+// Every tablespace with its directory: empty for pg_default and pg_global,
+// which live in the data directory, and relative for in-place tablespaces.
+func (q *Queries) TempTablespaceLocations(ctx context.Context) ([]TempTablespaceLocationsRow, error) {
+	rows, err := q.db.Query(ctx, tempTablespaceLocations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TempTablespaceLocationsRow
 	for rows.Next() {
-		var i TableVacuumHealthRow
+		var i TempTablespaceLocationsRow
 		if err := rows.Scan(
-			&i.TableName,
-			&i.LastAutovacuum,
-			&i.EstimatedRows,
-			&i.TableSizeBytes,
-			&i.NDeadTup,
-			&i.AutovacuumCount,
-			&i.Reloptions,
-			&i.LastVacuumAny,
-			&i.LastAnalyzeAny,
-			&i.NModSinceAnalyze,
-			&i.AutoanalyzeCount,
-			&i.NInsSinceVacuum,
+			&i.Name,
+			&i.Location,
 		); err != nil {
 			return nil, err
 		}
@@ -2088,6 +5109,47 @@ func (q *Queries) TableVacuumHealth(ctx context.Context) ([]TableVacuumHealthRow
 	return items, nil
 }
 
+const tempTablespaceSettings = `-- name: TempTablespaceSettings :one
+SELECT
+  current_setting('temp_tablespaces')::text AS temp_tablespaces
+  , ts.spcname::text AS database_tablespace
+  , pg_catalog.pg_tablespace_location(ts.oid)::text AS database_tablespace_location
+  , s.temp_bytes::bigint AS temp_bytes
+  , EXTRACT(EPOCH FROM (NOW() - s.stats_reset))::bigint AS seconds_since_reset
+  , EXTRACT(EPOCH FROM (NOW() - pg_postmaster_start_time()))::bigint AS uptime_seconds
+FROM pg_catalog.pg_database AS d
+INNER JOIN pg_catalog.pg_tablespace AS ts ON ts.oid = d.dattablespace
+INNER JOIN pg_catalog.pg_stat_database AS s ON s.datid = d.oid
+WHERE d.datname = current_database()
+`
+
+type TempTablespaceSettingsRow struct {
+	TempTablespaces            string
+	DatabaseTablespace         string
+	DatabaseTablespaceLocation string
+	TempBytes                  int64
+	SecondsSinceReset          pgtype.Int8
+	UptimeSeconds              int64
+}
+
+// temp_tablespaces, the current database's default tablespace, where temp
+// files go when it names none, and the temp bytes the database wrote since
+// its statistics were reset, with the seconds since then, NULL if never, and
+// the server's uptime.
+func (q *Queries) TempTablespaceSettings(ctx context.Context) (TempTablespaceSettingsRow, error) {
+	row := q.db.QueryRow(ctx, tempTablespaceSettings)
+	var i TempTablespaceSettingsRow
+	err := row.Scan(
+		&i.TempTablespaces,
+		&i.DatabaseTablespace,
+		&i.DatabaseTablespaceLocation,
+		&i.TempBytes,
+		&i.SecondsSinceReset,
+		&i.UptimeSeconds,
+	)
+	return i, err
+}
+
 const tempUsage = `-- name: TempUsage :one
 WITH temp_stats AS (
   SELECT
@@ -2095,17 +5157,8 @@ WITH temp_stats AS (
     , temp_files
     , temp_bytes
     , stats_reset
-    , EXTRACT(EPOCH FROM (NOW() - stats_reset)) AS seconds_since_reset
-    , CASE
-      WHEN EXTRACT(EPOCH FROM (NOW() - stats_reset)) > 0
-        THEN temp_files::numeric / (EXTRACT(EPOCH FROM (NOW() - stats_reset)) / 3600)
-      ELSE 0
-    END AS temp_files_per_hour
-    , CASE
-      WHEN EXTRACT(EPOCH FROM (NOW() - stats_reset)) > 0
-        THEN temp_bytes::numeric / (EXTRACT(EPOCH FROM (NOW() - stats_reset)) / 3600)
-      ELSE 0
-    END AS temp_bytes_per_hour
+    , EXTRACT(EPOCH FROM (NOW() - stats_reset))::bigint AS seconds_since_reset
+    , EXTRACT(EPOCH FROM (NOW() - pg_postmaster_start_time()))::bigint AS uptime_seconds
   FROM pg_stat_database
   WHERE datname = CURRENT_DATABASE()
 )
@@ -2140,13 +5193,12 @@ SELECT
   , ts.temp_bytes
   , ts.stats_reset
   , ts.seconds_since_reset
+  , ts.uptime_seconds
   , ms.work_mem
   , ms.temp_file_limit
   , ms.log_temp_files
   , ms.max_connections
   , ms.shared_buffers
-  , ROUND(ts.temp_files_per_hour::numeric, 2) AS temp_files_per_hour
-  , ROUND(ts.temp_bytes_per_hour::numeric, 0) AS temp_bytes_per_hour
 FROM temp_stats AS ts
 CROSS JOIN memory_settings AS ms
 `
@@ -2156,17 +5208,19 @@ type TempUsageRow struct {
 	TempFiles         pgtype.Int8
 	TempBytes         pgtype.Int8
 	StatsReset        pgtype.Timestamptz
-	SecondsSinceReset pgtype.Numeric
+	SecondsSinceReset pgtype.Int8
+	UptimeSeconds     int64
 	WorkMem           pgtype.Text
 	TempFileLimit     pgtype.Text
 	LogTempFiles      pgtype.Text
 	MaxConnections    pgtype.Text
 	SharedBuffers     pgtype.Text
-	TempFilesPerHour  pgtype.Numeric
-	TempBytesPerHour  pgtype.Numeric
 }
 
-// Monitors temporary file creation indicating work_mem exhaustion
+// Monitors temporary file creation indicating work_mem exhaustion.
+// The counters accumulate from stats_reset, which is NULL until the first
+// reset, and so are the seconds since it; the server's uptime is returned to
+// rate them over then.
 func (q *Queries) TempUsage(ctx context.Context) (TempUsageRow, error) {
 	row := q.db.QueryRow(ctx, tempUsage)
 	var i TempUsageRow
@@ -2176,17 +5230,94 @@ func (q *Queries) TempUsage(ctx context.Context) (TempUsageRow, error) {
 		&i.TempBytes,
 		&i.StatsReset,
 		&i.SecondsSinceReset,
+		&i.UptimeSeconds,
 		&i.WorkMem,
 		&i.TempFileLimit,
 		&i.LogTempFiles,
 		&i.MaxConnections,
 		&i.SharedBuffers,
-		&i.TempFilesPerHour,
-		&i.TempBytesPerHour,
 	)
 	return i, err
 }
 
+const toastColumnStorage = `-- name: ToastColumnStorage :many
+SELECT
+  (n.nspname || '.' || c.relname)::text AS table_name
+  , a.attname::text AS column_name
+  , format_type(a.atttypid, a.atttypmod)::text AS column_type
+  , t.typname::text AS type_name
+  , CASE a.attstorage
+    WHEN 'p' THEN 'PLAIN'
+    WHEN 'e' THEN 'EXTERNAL'
+    WHEN 'x' THEN 'EXTENDED'
+    WHEN 'm' THEN 'MAIN'
+  END::text AS storage
+  , CASE t.typstorage
+    WHEN 'p' THEN 'PLAIN'
+    WHEN 'e' THEN 'EXTERNAL'
+    WHEN 'x' THEN 'EXTENDED'
+    WHEN 'm' THEN 'MAIN'
+  END::text AS default_storage
+  , (
+    SELECT max(s.avg_width)
+    FROM pg_stats AS s
+    WHERE s.schemaname = n.nspname AND s.tablename = c.relname AND s.attname = a.attname
+  ) AS avg_width
+FROM pg_attribute AS a
+INNER JOIN pg_class AS c ON a.attrelid = c.oid
+INNER JOIN pg_namespace AS n ON c.relnamespace = n.oid
+INNER JOIN pg_type AS t ON a.atttypid = t.oid
+WHERE
+  a.attnum > 0
+  AND NOT a.attisdropped
+  AND a.attlen = -1
+  AND a.attstorage <> t.typstorage
+  AND c.relkind IN ('r', 'p', 'm')
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+  AND n.nspname NOT LIKE 'pg_toast%'
+ORDER BY table_name, a.attnum
+`
+
+type ToastColumnStorageRow struct {
+	TableName      string
+	ColumnName     string
+	ColumnType     string
+	TypeName       string
+	Storage        string
+	DefaultStorage string
+	AvgWidth       pgtype.Int4
+}
+
+// Variable-length columns whose storage strategy (ALTER TABLE ... SET STORAGE)
+// differs from their type's default, with the average width from pg_stats.
+func (q *Queries) ToastColumnStorage(ctx context.Context) ([]ToastColumnStorageRow, error) {
+	rows, err := q.db.Query(ctx, toastColumnStorage)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ToastColumnStorageRow
+	for rows.Next() {
+		var i ToastColumnStorageRow
+		if err := rows.Scan(
+			&i.TableName,
+			&i.ColumnName,
+			&i.ColumnType,
+			&i.TypeName,
+			&i.Storage,
+			&i.DefaultStorage,
+			&i.AvgWidth,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const toastStorage = `-- name: ToastStorage :many
 WITH toast_info AS (
   SELECT
@@ -2353,6 +5484,61 @@ func (q *Queries) ToastStorage(ctx context.Context) ([]ToastStorageRow, error) {
 	return items, nil
 }
 
+const toastTupleTargets = `-- name: ToastTupleTargets :many
+SELECT
+  (n.nspname || '.' || c.relname)::text AS table_name
+  , (
+    SELECT o.option_value::int
+    FROM pg_options_to_table(c.reloptions) AS o
+    WHERE o.option_name = 'toast_tuple_target'
+  ) AS toast_tuple_target
+  , pg_table_size(c.oid) AS table_size
+FROM pg_class AS c
+INNER JOIN pg_namespace AS n ON c.relnamespace = n.oid
+WHERE
+  c.relkind IN ('r', 'p', 'm')
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+  AND n.nspname NOT LIKE 'pg_toast%'
+  AND EXISTS (
+    SELECT 1
+    FROM pg_options_to_table(c.reloptions) AS o
+    WHERE o.option_name = 'toast_tuple_target' AND o.option_value::int <> 2032
+  )
+ORDER BY table_size DESC
+`
+
+type ToastTupleTargetsRow struct {
+	TableName        string
+	ToastTupleTarget pgtype.Int4
+	TableSize        pgtype.Int8
+}
+
+// Tables whose toast_tuple_target reloption differs from the 2032-byte
+// default. A higher target keeps larger values inline in the heap.
+func (q *Queries) ToastTupleTargets(ctx context.Context) ([]ToastTupleTargetsRow, error) {
+	rows, err := q.db.Query(ctx, toastTupleTargets)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ToastTupleTargetsRow
+	for rows.Next() {
+		var i ToastTupleTargetsRow
+		if err := rows.Scan(
+			&i.TableName,
+			&i.ToastTupleTarget,
+			&i.TableSize,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const uuidColumnDefaults = `-- name: UuidColumnDefaults :many
 WITH indexed_columns AS (
   SELECT
@@ -2468,6 +5654,267 @@ func (q *Queries) UuidColumnsAsString(ctx context.Context) ([]UuidColumnsAsStrin
 	return items, nil
 }
 
+const vacuumCostBudget = `-- name: VacuumCostBudget :many
+WITH settings AS (
+  SELECT
+    max(setting::float8) FILTER (WHERE name = 'autovacuum_vacuum_threshold') AS vacuum_threshold
+    , max(setting::float8) FILTER (WHERE name = 'autovacuum_vacuum_scale_factor') AS scale_factor
+    , max(setting::float8) FILTER (WHERE name = 'autovacuum_vacuum_cost_limit') AS autovacuum_cost_limit
+    , max(setting::float8) FILTER (WHERE name = 'autovacuum_vacuum_cost_delay') AS autovacuum_cost_delay
+    , max(setting::float8) FILTER (WHERE name = 'vacuum_cost_limit') AS cost_limit
+    , max(setting::float8) FILTER (WHERE name = 'vacuum_cost_delay') AS cost_delay
+    , max(setting::float8) FILTER (WHERE name = 'vacuum_cost_page_miss') AS page_miss
+    , max(setting::float8) FILTER (WHERE name = 'vacuum_cost_page_dirty') AS page_dirty
+  FROM pg_settings
+  WHERE name IN (
+    'autovacuum_vacuum_threshold', 'autovacuum_vacuum_scale_factor'
+    , 'autovacuum_vacuum_cost_limit', 'autovacuum_vacuum_cost_delay'
+    , 'vacuum_cost_limit', 'vacuum_cost_delay'
+    , 'vacuum_cost_page_miss', 'vacuum_cost_page_dirty'
+  )
+)
+
+, stats_window AS (
+  SELECT
+    extract(EPOCH FROM now() - d.stats_reset)::bigint AS seconds_since_reset
+    , extract(EPOCH FROM now() - pg_postmaster_start_time())::bigint AS uptime_seconds
+  FROM pg_stat_database AS d
+  WHERE d.datname = current_database()
+)
+
+SELECT
+  (n.nspname || '.' || c.relname)::text AS table_name
+  , greatest(c.reltuples, 0)::float8 AS estimated_rows
+  , c.relpages::bigint AS heap_pages
+  , c.relallvisible::bigint AS all_visible_pages
+  , coalesce((
+    SELECT sum(i.relpages)
+    FROM pg_index AS x
+    INNER JOIN pg_class AS i ON x.indexrelid = i.oid
+    WHERE x.indrelid = c.oid
+  ), 0)::bigint AS index_pages
+  , (s.n_tup_upd - s.n_tup_hot_upd + s.n_tup_del)::bigint AS dead_tuples_produced
+  , w.seconds_since_reset
+  , w.uptime_seconds
+  , coalesce((
+    SELECT o.option_value::bool
+    FROM pg_options_to_table(c.reloptions) AS o
+    WHERE o.option_name = 'autovacuum_enabled'
+  ), true) AS autovacuum_enabled
+  , coalesce((
+    SELECT o.option_value::float8
+    FROM pg_options_to_table(c.reloptions) AS o
+    WHERE o.option_name = 'autovacuum_vacuum_threshold'
+  ), st.vacuum_threshold)::float8 AS vacuum_threshold
+  , coalesce((
+    SELECT o.option_value::float8
+    FROM pg_options_to_table(c.reloptions) AS o
+    WHERE o.option_name = 'autovacuum_vacuum_scale_factor'
+  ), st.scale_factor)::float8 AS scale_factor
+  , coalesce((
+    SELECT nullif(o.option_value::float8, -1)
+    FROM pg_options_to_table(c.reloptions) AS o
+    WHERE o.option_name = 'autovacuum_vacuum_cost_limit'
+  ), nullif(st.autovacuum_cost_limit, -1), st.cost_limit)::float8 AS cost_limit
+  , coalesce((
+    SELECT nullif(o.option_value::float8, -1)
+    FROM pg_options_to_table(c.reloptions) AS o
+    WHERE o.option_name = 'autovacuum_vacuum_cost_delay'
+  ), nullif(st.autovacuum_cost_delay, -1), st.cost_delay)::float8 AS cost_delay_ms
+  , st.page_miss::float8 AS page_miss_cost
+  , st.page_dirty::float8 AS page_dirty_cost
+FROM pg_class AS c
+INNER JOIN pg_namespace AS n ON c.relnamespace = n.oid
+INNER JOIN pg_stat_user_tables AS s ON c.oid = s.relid
+CROSS JOIN settings AS st
+CROSS JOIN stats_window AS w
+WHERE
+  c.relkind IN ('r', 'm')
+  AND c.relpages > 0
+  AND s.n_tup_upd - s.n_tup_hot_upd + s.n_tup_del > 0
+ORDER BY dead_tuples_produced DESC
+LIMIT 500
+`
+
+type VacuumCostBudgetRow struct {
+	TableName          string
+	EstimatedRows      float64
+	HeapPages          int64
+	AllVisiblePages    int64
+	IndexPages         int64
+	DeadTuplesProduced int64
+	SecondsSinceReset  pgtype.Int8
+	UptimeSeconds      int64
+	AutovacuumEnabled  bool
+	VacuumThreshold    float64
+	ScaleFactor        float64
+	CostLimit          float64
+	CostDelayMs        float64
+	PageMissCost       float64
+	PageDirtyCost      float64
+}
+
+// Per-table inputs for estimating whether autovacuum's cost budget keeps up
+// with dead-tuple accumulation: the dead tuples produced since the last stats
+// reset (updates that were not HOT, plus deletes) and the seconds since then,
+// NULL when the statistics were never reset, the server's uptime, the pages
+// a vacuum pass reads, and the effective autovacuum threshold and cost
+// settings, with per-table reloptions overriding the global ones. Cost
+// delays are in milliseconds; -1 cost settings fall back to the
+// vacuum_cost_* values.
+func (q *Queries) VacuumCostBudget(ctx context.Context) ([]VacuumCostBudgetRow, error) {
+	rows, err := q.db.Query(ctx, vacuumCostBudget)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []VacuumCostBudgetRow
+	for rows.Next() {
+		var i VacuumCostBudgetRow
+		if err := rows.Scan(
+			&i.TableName,
+			&i.EstimatedRows,
+			&i.HeapPages,
+			&i.AllVisiblePages,
+			&i.IndexPages,
+			&i.DeadTuplesProduced,
+			&i.SecondsSinceReset,
+			&i.UptimeSeconds,
+			&i.AutovacuumEnabled,
+			&i.VacuumThreshold,
+			&i.ScaleFactor,
+			&i.CostLimit,
+			&i.CostDelayMs,
+			&i.PageMissCost,
+			&i.PageDirtyCost,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const vacuumSLATables = `-- name: VacuumSLATables :many
+SELECT
+  s.schemaname::text AS schema_name
+  , s.relname::text AS table_name
+  , coalesce(
+    EXTRACT(EPOCH FROM now() - greatest(s.last_vacuum, s.last_autovacuum))::bigint
+    , -1
+  )::bigint AS vacuum_age_seconds
+  , coalesce(
+    EXTRACT(EPOCH FROM now() - greatest(s.last_analyze, s.last_autoanalyze))::bigint
+    , -1
+  )::bigint AS analyze_age_seconds
+FROM pg_stat_user_tables AS s
+ORDER BY s.schemaname, s.relname
+`
+
+type VacuumSLATablesRow struct {
+	SchemaName        string
+	TableName         string
+	VacuumAgeSeconds  int64
+	AnalyzeAgeSeconds int64
+}
+
+// Seconds since each user table was last vacuumed and analyzed, manually or by
+// autovacuum; -1 when it never was. The timestamps are cleared by a statistics
+// reset.
+func (q *Queries) VacuumSLATables(ctx context.Context) ([]VacuumSLATablesRow, error) {
+	rows, err := q.db.Query(ctx, vacuumSLATables)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []VacuumSLATablesRow
+	for rows.Next() {
+		var i VacuumSLATablesRow
+		if err := rows.Scan(
+			&i.SchemaName,
+			&i.TableName,
+			&i.VacuumAgeSeconds,
+			&i.AnalyzeAgeSeconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const vacuumScaleFactors = `-- name: VacuumScaleFactors :many
+SELECT
+  (n.nspname || '.' || c.relname)::text AS table_name
+  , coalesce(s.n_live_tup, greatest(c.reltuples, 0)::bigint) AS estimated_rows
+  , current_setting('autovacuum_vacuum_scale_factor')::float8 AS global_vacuum_scale_factor
+  , current_setting('autovacuum_analyze_scale_factor')::float8 AS global_analyze_scale_factor
+  , (
+    SELECT o.option_value::float8
+    FROM pg_options_to_table(c.reloptions) AS o
+    WHERE o.option_name = 'autovacuum_vacuum_scale_factor'
+  ) AS table_vacuum_scale_factor
+  , (
+    SELECT o.option_value::float8
+    FROM pg_options_to_table(c.reloptions) AS o
+    WHERE o.option_name = 'autovacuum_analyze_scale_factor'
+  ) AS table_analyze_scale_factor
+FROM pg_class AS c
+INNER JOIN pg_namespace AS n ON c.relnamespace = n.oid
+LEFT JOIN pg_stat_user_tables AS s ON c.oid = s.relid
+WHERE
+  c.relkind IN ('r', 'm')
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+  AND n.nspname NOT LIKE 'pg_toast%'
+ORDER BY estimated_rows DESC
+LIMIT 500
+`
+
+type VacuumScaleFactorsRow struct {
+	TableName                string
+	EstimatedRows            pgtype.Int8
+	GlobalVacuumScaleFactor  pgtype.Float8
+	GlobalAnalyzeScaleFactor pgtype.Float8
+	TableVacuumScaleFactor   pgtype.Float8
+	TableAnalyzeScaleFactor  pgtype.Float8
+}
+
+// Effective autovacuum/autoanalyze scale factors for every table, alongside
+// the global settings they override (or inherit). Per-table values come from
+// reloptions; NULL means the table inherits the global value.
+func (q *Queries) VacuumScaleFactors(ctx context.Context) ([]VacuumScaleFactorsRow, error) {
+	rows, err := q.db.Query(ctx, vacuumScaleFactors)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []VacuumScaleFactorsRow
+	for rows.Next() {
+		var i VacuumScaleFactorsRow
+		if err := rows.Scan(
+			&i.TableName,
+			&i.EstimatedRows,
+			&i.GlobalVacuumScaleFactor,
+			&i.GlobalAnalyzeScaleFactor,
+			&i.TableVacuumScaleFactor,
+			&i.TableAnalyzeScaleFactor,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const vacuumSettings = `-- name: VacuumSettings :many
 SELECT
   name::varchar
@@ -2519,3 +5966,168 @@ func (q *Queries) VacuumSettings(ctx context.Context) ([]VacuumSettingsRow, erro
 	}
 	return items, nil
 }
+
+const walSettings = `-- name: WalSettings :one
+SELECT
+  current_setting('wal_compression')::text AS wal_compression
+  , pg_catalog.pg_size_bytes(current_setting('wal_buffers'))::bigint AS wal_buffers_bytes
+  , pg_catalog.pg_size_bytes(current_setting('max_wal_size'))::bigint AS max_wal_size_bytes
+  , pg_catalog.pg_size_bytes(current_setting('min_wal_size'))::bigint AS min_wal_size_bytes
+  , pg_catalog.pg_size_bytes(current_setting('wal_keep_size'))::bigint AS wal_keep_size_bytes
+  , (SELECT s.setting::int FROM pg_catalog.pg_settings AS s WHERE s.name = 'checkpoint_timeout') AS checkpoint_timeout_seconds
+  , current_setting('checkpoint_completion_target')::float8 AS checkpoint_completion_target
+  , (SELECT count(*)::int FROM pg_catalog.pg_replication_slots WHERE slot_type = 'physical') AS physical_slots
+  , to_regclass('pg_catalog.pg_stat_wal') IS NOT NULL AS has_wal_stats
+`
+
+type WalSettingsRow struct {
+	WalCompression             string
+	WalBuffersBytes            int64
+	MaxWalSizeBytes            int64
+	MinWalSizeBytes            int64
+	WalKeepSizeBytes           int64
+	CheckpointTimeoutSeconds   int32
+	CheckpointCompletionTarget float64
+	PhysicalSlots              int32
+	HasWalStats                bool
+}
+
+// WAL and checkpoint settings, with sizes in bytes and checkpoint_timeout in
+// seconds, the number of physical replication slots, and whether
+// pg_stat_wal (PostgreSQL 14+) exists to measure the WAL rate.
+func (q *Queries) WalSettings(ctx context.Context) (WalSettingsRow, error) {
+	row := q.db.QueryRow(ctx, walSettings)
+	var i WalSettingsRow
+	err := row.Scan(
+		&i.WalCompression,
+		&i.WalBuffersBytes,
+		&i.MaxWalSizeBytes,
+		&i.MinWalSizeBytes,
+		&i.WalKeepSizeBytes,
+		&i.CheckpointTimeoutSeconds,
+		&i.CheckpointCompletionTarget,
+		&i.PhysicalSlots,
+		&i.HasWalStats,
+	)
+	return i, err
+}
+
+const walStats = `-- name: WalStats :one
+SELECT
+  w.wal_bytes::float8 AS wal_bytes
+  , extract(EPOCH FROM now() - w.stats_reset)::bigint AS seconds_since_reset
+  , extract(EPOCH FROM now() - pg_postmaster_start_time())::bigint AS uptime_seconds
+FROM pg_catalog.pg_stat_wal AS w
+`
+
+type WalStatsRow struct {
+	WalBytes          float64
+	SecondsSinceReset pgtype.Int8
+	UptimeSeconds     int64
+}
+
+// WAL written since the statistics were reset, how long ago that was in
+// seconds, NULL when they never were, and the server's uptime.
+func (q *Queries) WalStats(ctx context.Context) (WalStatsRow, error) {
+	row := q.db.QueryRow(ctx, walStats)
+	var i WalStatsRow
+	err := row.Scan(
+		&i.WalBytes,
+		&i.SecondsSinceReset,
+		&i.UptimeSeconds,
+	)
+	return i, err
+}
+
+const xminHorizonHolders = `-- name: XminHorizonHolders :many
+WITH holders AS (
+  SELECT
+    CASE WHEN a.backend_type = 'walsender' THEN 'standby' ELSE 'session' END AS source
+    , ('pid ' || a.pid) AS holder
+    , coalesce(a.datname::text, '') AS database_name
+    , greatest(age(a.backend_xmin), age(a.backend_xid)) AS xmin_age
+    , coalesce(EXTRACT(EPOCH FROM now() - a.xact_start)::bigint, -1) AS age_seconds
+    , concat_ws(', ', a.usename, nullif(a.application_name, ''), a.state) AS detail
+  FROM pg_stat_activity AS a
+  WHERE
+    (a.backend_xmin IS NOT NULL OR a.backend_xid IS NOT NULL)
+    AND a.backend_type <> 'autovacuum worker'
+    AND a.pid <> pg_backend_pid()
+
+  UNION ALL
+
+  SELECT
+    'replication slot' AS source
+    , s.slot_name::text AS holder
+    , coalesce(s.database::text, '') AS database_name
+    , greatest(age(s.xmin), age(s.catalog_xmin)) AS xmin_age
+    , -1 AS age_seconds
+    , concat_ws(', ', s.slot_type, CASE WHEN s.active THEN 'active' ELSE 'inactive' END) AS detail
+  FROM pg_replication_slots AS s
+  WHERE s.xmin IS NOT NULL OR s.catalog_xmin IS NOT NULL
+
+  UNION ALL
+
+  SELECT
+    'prepared transaction' AS source
+    , p.gid AS holder
+    , p.database::text AS database_name
+    , age(p.transaction) AS xmin_age
+    , EXTRACT(EPOCH FROM now() - p.prepared)::bigint AS age_seconds
+    , p.owner::text AS detail
+  FROM pg_prepared_xacts AS p
+)
+
+SELECT
+  h.source::text AS source
+  , h.holder::text AS holder
+  , h.database_name::text AS database_name
+  , h.xmin_age::bigint AS xmin_age
+  , h.age_seconds::bigint AS age_seconds
+  , h.detail::text AS detail
+FROM holders AS h
+ORDER BY h.xmin_age DESC, h.age_seconds DESC
+LIMIT 20
+`
+
+type XminHorizonHoldersRow struct {
+	Source       string
+	Holder       string
+	DatabaseName string
+	XminAge      int64
+	AgeSeconds   int64
+	Detail       string
+}
+
+// What holds back the xmin horizon, the oldest transaction whose view of the
+// data vacuum must preserve: open transactions and snapshots of sessions,
+// hot_standby_feedback of standbys (on their walsender), replication slots,
+// and prepared transactions. Vacuum processes are left out because other
+// vacuums ignore their snapshots, and so is pgdoctor's own session. Slots
+// record no start time, so their age_seconds is -1.
+func (q *Queries) XminHorizonHolders(ctx context.Context) ([]XminHorizonHoldersRow, error) {
+	rows, err := q.db.Query(ctx, xminHorizonHolders)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []XminHorizonHoldersRow
+	for rows.Next() {
+		var i XminHorizonHoldersRow
+		if err := rows.Scan(
+			&i.Source,
+			&i.Holder,
+			&i.DatabaseName,
+			&i.XminAge,
+			&i.AgeSeconds,
+			&i.Detail,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,33 @@
+package pgdoctor
+
+import "context"
+
+// HeavyLimit bounds how many heavy checks (check.Metadata.Heavy) run at once
+// across the Run calls sharing it, so that a run over several databases at
+// once does not scan relations in all of them at the same time and slow down
+// the server it is diagnosing. Other checks are not limited. Pass the same
+// HeavyLimit to every Run; it is safe for concurrent use.
+type HeavyLimit struct {
+	slots chan struct{}
+}
+
+// NewHeavyLimit returns a HeavyLimit letting n heavy checks run at once. An n
+// below 1 is treated as 1.
+func NewHeavyLimit(n int) *HeavyLimit {
+	return &HeavyLimit{slots: make(chan struct{}, max(1, n))}
+}
+
+// acquire waits for a free slot, or until ctx is done.
+func (l *HeavyLimit) acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees a slot taken by acquire.
+func (l *HeavyLimit) release() {
+	<-l.slots
+}
@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/emancu/pgdoctor/check"
+)
+
+// baseline maps the identity of each WARN and FAIL finding of a run saved
+// with --output json to when it was first seen, for --baseline. Findings that
+// list objects in a table are tracked per row instead, so each object keeps
+// its own first_seen while others come and go.
+type baseline map[string]time.Time
+
+// loadBaseline reads a saved run. A finding's or row's first_seen comes from
+// the saved document when an earlier --baseline recorded it, and is otherwise
+// the saved run's start. An empty path returns a nil baseline.
+func loadBaseline(path string) (baseline, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading baseline: %w", err)
+	}
+	defer f.Close()
+	run, reports, err := decodeJSON(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading baseline %s: %w", path, err)
+	}
+
+	b := baseline{}
+	for _, r := range reports {
+		for _, finding := range r.Results {
+			if finding.Severity < check.SeverityWarn {
+				continue
+			}
+			seen := finding.FirstSeen
+			if seen.IsZero() {
+				seen = run.startedAt
+			}
+			if !hasRows(finding) {
+				if !seen.IsZero() {
+					b[findingKey(r, finding)] = seen
+				}
+				continue
+			}
+			// Rows of runs saved before rows had first_seen take the
+			// finding's.
+			for _, row := range finding.Table.Rows {
+				rowSeen := row.FirstSeen
+				if rowSeen.IsZero() {
+					rowSeen = seen
+				}
+				if !rowSeen.IsZero() {
+					b[rowKey(r, finding, row)] = rowSeen
+				}
+			}
+		}
+	}
+	return b, nil
+}
+
+// annotate returns r with FirstSeen set on its WARN and FAIL findings, and on
+// the rows of their tables: from the baseline when it has the same finding or
+// row, otherwise now, as they are new. A finding with rows was first seen
+// when its oldest row was. Reports can be shared with the run's cache, so r
+// itself is not modified.
+func (b baseline) annotate(r *check.Report, now time.Time) *check.Report {
+	if b == nil {
+		return r
+	}
+	annotated := *r
+	annotated.Results = slices.Clone(r.Results)
+	for i, finding := range annotated.Results {
+		if finding.Severity < check.SeverityWarn {
+			continue
+		}
+		if !hasRows(finding) {
+			annotated.Results[i].FirstSeen = b.firstSeen(findingKey(r, finding), now)
+			continue
+		}
+		table := *finding.Table
+		table.Rows = slices.Clone(finding.Table.Rows)
+		oldest := now
+		for j, row := range table.Rows {
+			table.Rows[j].FirstSeen = b.firstSeen(rowKey(r, finding, row), now)
+			if table.Rows[j].FirstSeen.Before(oldest) {
+				oldest = table.Rows[j].FirstSeen
+			}
+		}
+		annotated.Results[i].Table = &table
+		annotated.Results[i].FirstSeen = oldest
+	}
+	return &annotated
+}
+
+// firstSeen returns when key was first seen, or now when the baseline does
+// not have it.
+func (b baseline) firstSeen(key string, now time.Time) time.Time {
+	if seen, ok := b[key]; ok {
+		return seen
+	}
+	return now
+}
+
+func (b baseline) annotateAll(reports []*check.Report, now time.Time) []*check.Report {
+	if b == nil {
+		return reports
+	}
+	out := make([]*check.Report, 0, len(reports))
+	for _, r := range reports {
+		out = append(out, b.annotate(r, now))
+	}
+	return out
+}
+
+func hasRows(f check.Finding) bool {
+	return f.Table != nil && len(f.Table.Rows) > 0
+}
+
+// findingKey identifies a finding without table rows across runs: its
+// database, check, and finding IDs.
+func findingKey(r *check.Report, f check.Finding) string {
+	return r.Database + "/" + r.CheckID + "/" + f.ID
+}
+
+// rowKey identifies a row of a finding's table across runs by the finding's
+// key and the row's fingerprint (Table.RowFingerprint), from the columns that
+// name what the row is about. Sizes and counts in the other columns change
+// from run to run; the object stays the same. Redacted runs only match runs
+// redacted the same way.
+func rowKey(r *check.Report, f check.Finding, row check.TableRow) string {
+	return findingKey(r, f) + "#" + f.Table.RowFingerprint(row)
+}
+
+// firstSeenLabel describes a finding's FirstSeen relative to the run it is
+// in, with how many of its rows are new when it is not, or returns "" when
+// the run was not compared with a baseline.
+func firstSeenLabel(f check.Finding, startedAt time.Time) string {
+	if f.FirstSeen.IsZero() {
+		return ""
+	}
+	if !f.FirstSeen.Before(startedAt) {
+		return "New since the baseline"
+	}
+	label := fmt.Sprintf("First seen %s, %s before this run",
+		f.FirstSeen.UTC().Format(time.DateOnly), check.FormatDurationSec(int64(startedAt.Sub(f.FirstSeen).Seconds())))
+	if f.Table != nil {
+		var fresh int
+		for _, row := range f.Table.Rows {
+			if !row.FirstSeen.IsZero() && !row.FirstSeen.Before(startedAt) {
+				fresh++
+			}
+		}
+		if fresh > 0 {
+			label += fmt.Sprintf("; %d of %d row(s) new since the baseline", fresh, len(f.Table.Rows))
+		}
+	}
+	return label
+}
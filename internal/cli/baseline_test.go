@@ -0,0 +1,176 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emancu/pgdoctor/check"
+)
+
+// saveRun writes reports as --output json would, returning the file's path.
+func saveRun(t *testing.T, run runInfo, reports []*check.Report) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	var buf bytes.Buffer
+	require.NoError(t, formatJSON(&buf, run, reports))
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o600))
+	return path
+}
+
+func bloatReport(tables ...string) *check.Report {
+	r := check.NewReport(check.Metadata{CheckID: "table-bloat", Name: "Table Bloat", Category: check.CategoryVacuum})
+	table := &check.Table{Headers: []string{"Table", "Bloat"}}
+	for i, name := range tables {
+		table.Rows = append(table.Rows, check.TableRow{Cells: []string{name, []string{"61%", "72%", "80%"}[i%3]}, Severity: check.SeverityWarn})
+	}
+	r.AddFinding(check.Finding{ID: "bloat", Name: "Bloat", Severity: check.SeverityWarn, Table: table})
+	r.AddFinding(check.Finding{ID: "toast", Name: "TOAST", Severity: check.SeverityOK})
+	return r
+}
+
+func TestBaseline_FirstSeen(t *testing.T) {
+	t.Parallel()
+
+	first := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	second := first.Add(24 * time.Hour)
+	third := second.Add(48 * time.Hour)
+
+	// The first baseline has no first_seen: its start stands in.
+	path := saveRun(t, runInfo{startedAt: first}, []*check.Report{bloatReport("public.events", "public.users")})
+	base, err := loadBaseline(path)
+	require.NoError(t, err)
+
+	current := bloatReport("public.users", "public.events")
+	current.Results[0].Table.Rows[0].Cells[1] = "90%"
+	annotated := base.annotate(current, second)
+	assert.Equal(t, first, annotated.Results[0].FirstSeen, "row order and other columns do not change the identity")
+	assert.True(t, annotated.Results[1].FirstSeen.IsZero(), "passing findings are not tracked")
+	assert.True(t, current.Results[0].FirstSeen.IsZero(), "the run's report is not modified")
+
+	changed := base.annotate(bloatReport("public.events", "public.orders"), second)
+	assert.Equal(t, first, changed.Results[0].FirstSeen, "the finding was first seen with its oldest row")
+	assert.Equal(t, first, changed.Results[0].Table.Rows[0].FirstSeen, "each row keeps its own first_seen")
+	assert.Equal(t, second, changed.Results[0].Table.Rows[1].FirstSeen, "a table listed for the first time is new")
+	assert.True(t, current.Results[0].Table.Rows[0].FirstSeen.IsZero(), "the run's table is not modified")
+
+	gone := base.annotate(bloatReport("public.orders"), second)
+	assert.Equal(t, second, gone.Results[0].FirstSeen, "a finding listing only new tables is new")
+
+	// Saving the annotated run carries first_seen to the next comparison.
+	path = saveRun(t, runInfo{startedAt: second}, []*check.Report{annotated})
+	base, err = loadBaseline(path)
+	require.NoError(t, err)
+	assert.Equal(t, first, base.annotate(bloatReport("public.events", "public.users"), third).Results[0].FirstSeen)
+
+	// Rows carry their first_seen through the saved JSON too.
+	path = saveRun(t, runInfo{startedAt: second}, []*check.Report{changed})
+	base, err = loadBaseline(path)
+	require.NoError(t, err)
+	rows := base.annotate(bloatReport("public.orders"), third).Results[0]
+	assert.Equal(t, second, rows.FirstSeen)
+	assert.Equal(t, second, rows.Table.Rows[0].FirstSeen)
+
+	// Findings without a table are tracked as a whole.
+	single := singleFindingReport()
+	base, err = loadBaseline(saveRun(t, runInfo{startedAt: first}, []*check.Report{single}))
+	require.NoError(t, err)
+	assert.Equal(t, first, base.annotate(single, second).Results[0].FirstSeen)
+
+	var nilBaseline baseline
+	r := bloatReport("public.events")
+	assert.Same(t, r, nilBaseline.annotate(r, third), "no --baseline keeps reports as they are")
+}
+
+func TestBaseline_TableKey(t *testing.T) {
+	t.Parallel()
+
+	first := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	indexes := func(sizes ...string) *check.Report {
+		r := check.NewReport(check.Metadata{CheckID: "index-bloat", Name: "Index Bloat", Category: check.CategoryIndexes})
+		r.AddFinding(check.Finding{ID: "bloat", Name: "Bloat", Severity: check.SeverityWarn, Table: &check.Table{
+			Headers: []string{"Table", "Size", "Index"},
+			Key:     []int{0, 2},
+			Rows: []check.TableRow{
+				{Cells: []string{"public.events", sizes[0], "events_pkey"}, Severity: check.SeverityWarn},
+				{Cells: []string{"public.events", sizes[1], "events_at_idx"}, Severity: check.SeverityWarn},
+			},
+		}})
+		return r
+	}
+
+	base, err := loadBaseline(saveRun(t, runInfo{startedAt: first}, []*check.Report{indexes("1GiB", "2GiB")}))
+	require.NoError(t, err)
+	annotated := base.annotate(indexes("3GiB", "4GiB"), first.Add(time.Hour))
+	assert.Equal(t, first, annotated.Results[0].FirstSeen, "the key survives the saved JSON, and sizes are not part of it")
+
+	renamed := indexes("1GiB", "2GiB")
+	renamed.Results[0].Table.Rows[1].Cells[2] = "events_created_idx"
+	annotated = base.annotate(renamed, first.Add(time.Hour))
+	assert.Equal(t, first, annotated.Results[0].Table.Rows[0].FirstSeen)
+	assert.Equal(t, first.Add(time.Hour), annotated.Results[0].Table.Rows[1].FirstSeen,
+		"a different index on the same table is a new row")
+}
+
+func TestLoadBaseline_Errors(t *testing.T) {
+	t.Parallel()
+
+	base, err := loadBaseline("")
+	require.NoError(t, err)
+	assert.Nil(t, base)
+
+	_, err = loadBaseline(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorContains(t, err, "reading baseline")
+
+	path := filepath.Join(t.TempDir(), "bad.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"not": "a report"}`), 0o600))
+	_, err = loadBaseline(path)
+	assert.ErrorContains(t, err, "not a pgdoctor report")
+
+	newer := filepath.Join(t.TempDir(), "newer.json")
+	require.NoError(t, os.WriteFile(newer, []byte(`{"schema_version": 2, "reports": []}`), 0o600))
+	_, err = loadBaseline(newer)
+	assert.ErrorContains(t, err, "schema_version 2", "a baseline from a newer pgdoctor is refused, not misread")
+}
+
+func TestFirstSeenLabel(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 10, 14, 9, 30, 0, 0, time.UTC)
+	assert.Empty(t, firstSeenLabel(check.Finding{}, start))
+	assert.Equal(t, "New since the baseline", firstSeenLabel(check.Finding{FirstSeen: start}, start))
+	assert.Equal(t, "First seen 2026-10-11, 3d before this run",
+		firstSeenLabel(check.Finding{FirstSeen: start.Add(-72 * time.Hour)}, start))
+
+	rows := &check.Table{Rows: []check.TableRow{
+		{Cells: []string{"public.events"}, FirstSeen: start.Add(-72 * time.Hour)},
+		{Cells: []string{"public.orders"}, FirstSeen: start},
+	}}
+	assert.Equal(t, "First seen 2026-10-11, 3d before this run; 1 of 2 row(s) new since the baseline",
+		firstSeenLabel(check.Finding{FirstSeen: start.Add(-72 * time.Hour), Table: rows}, start))
+}
+
+func TestRenderCommand_FirstSeen(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 10, 14, 9, 30, 0, 0, time.UTC)
+	chronic := bloatReport("public.events")
+	chronic.Results[0].FirstSeen = start.Add(-72 * time.Hour)
+	fresh := singleFindingReport()
+	fresh.Results[0].FirstSeen = start
+	path := saveRun(t, runInfo{startedAt: start}, []*check.Report{chronic, fresh})
+
+	var out bytes.Buffer
+	cmd := newRootCommand("test")
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"render", "--input", path, "--width", "0"})
+	_ = cmd.Execute()
+
+	assert.Contains(t, out.String(), "  First seen 2026-10-11, 3d before this run\n")
+	assert.Contains(t, out.String(), "  something looks off\n  New since the baseline\n")
+}
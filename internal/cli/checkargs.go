@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/emancu/pgdoctor"
+	"github.com/emancu/pgdoctor/check"
+)
+
+// runCheckArgs runs the checks named by bare arguments to the root command:
+// `pgdoctor table-bloat index-bloat` is `pgdoctor run --only
+// table-bloat,index-bloat`. An argument that looks like a connection string
+// is taken as the DSN; without one, PGDOCTOR_DSN is used.
+func runCheckArgs(cmd *cobra.Command, opts *runOptions, args []string) error {
+	ids, dsn, err := splitCheckArgs(args)
+	if err != nil {
+		return err
+	}
+
+	allChecks, err := availableChecks(opts.checksDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return &SilentError{ExitCode: 1}
+	}
+
+	_, invalid := pgdoctor.ValidateFilters(allChecks, ids)
+	if len(invalid) > 0 {
+		names := suggestionPool(cmd, allChecks)
+		for _, name := range invalid {
+			if suggestions := suggest(names, name); len(suggestions) > 0 {
+				fmt.Fprintf(os.Stderr, "Error: unknown check %q (did you mean %s?)\n", name, strings.Join(suggestions, ", "))
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: unknown check %q\n", name)
+			}
+		}
+		fmt.Fprintln(os.Stderr, "Run 'pgdoctor list' to see all checks.")
+		return &SilentError{ExitCode: 1}
+	}
+
+	if dsn == "" {
+		dsn = os.Getenv("PGDOCTOR_DSN")
+	}
+	if dsn == "" {
+		return fmt.Errorf("connection string required: pgdoctor <check-id>... <DSN> or set PGDOCTOR_DSN environment variable")
+	}
+
+	opts.only = append(opts.only, ids...)
+	return runChecks(cmd, opts, dsn)
+}
+
+// splitCheckArgs separates check IDs from the connection string among the
+// root command's arguments. Check IDs never contain "=" or "://", which every
+// key/value and URL connection string does.
+func splitCheckArgs(args []string) (ids []string, dsn string, err error) {
+	for _, arg := range args {
+		if !strings.Contains(arg, "=") && !strings.Contains(arg, "://") {
+			ids = append(ids, arg)
+			continue
+		}
+		if dsn != "" {
+			return nil, "", fmt.Errorf("more than one connection string given")
+		}
+		dsn = arg
+	}
+	return ids, dsn, nil
+}
+
+// suggestionPool lists what a mistyped argument may have meant: check IDs,
+// categories, and the root command's subcommands.
+func suggestionPool(cmd *cobra.Command, checks []check.Package) []string {
+	seen := map[string]struct{}{}
+	var names []string
+	add := func(name string) {
+		if _, ok := seen[name]; !ok {
+			seen[name] = struct{}{}
+			names = append(names, name)
+		}
+	}
+	for _, pkg := range checks {
+		metadata := pkg.Metadata()
+		add(metadata.CheckID)
+		add(string(metadata.Category))
+	}
+	for _, sub := range cmd.Commands() {
+		if !sub.Hidden {
+			add(sub.Name())
+		}
+	}
+	return names
+}
+
+// maxSuggestions caps how many alternatives an unknown check error lists.
+const maxSuggestions = 3
+
+// suggest returns up to maxSuggestions names close to name: within a few
+// edits of it, or containing it, as "bloat" does "table-bloat". Closest
+// first.
+func suggest(names []string, name string) []string {
+	maxDistance := max(2, len(name)/3)
+
+	type candidate struct {
+		name     string
+		distance int
+	}
+	var candidates []candidate
+	for _, n := range names {
+		d := editDistance(n, name)
+		if d > maxDistance && !strings.Contains(n, name) {
+			continue
+		}
+		candidates = append(candidates, candidate{n, d})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].name < candidates[j].name
+	})
+
+	var out []string
+	for i := 0; i < len(candidates) && i < maxSuggestions; i++ {
+		out = append(out, candidates[i].name)
+	}
+	return out
+}
+
+// editDistance is the Levenshtein distance between a and b, in bytes.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
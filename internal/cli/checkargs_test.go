@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitCheckArgs(t *testing.T) {
+	t.Parallel()
+
+	ids, dsn, err := splitCheckArgs([]string{"table-bloat", "postgres://app@db/prod", "index-bloat"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"table-bloat", "index-bloat"}, ids)
+	assert.Equal(t, "postgres://app@db/prod", dsn)
+
+	ids, dsn, err = splitCheckArgs([]string{"host=db dbname=prod", "vacuum"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vacuum"}, ids)
+	assert.Equal(t, "host=db dbname=prod", dsn)
+
+	_, _, err = splitCheckArgs([]string{"postgres://a", "postgres://b"})
+	require.ErrorContains(t, err, "more than one connection string")
+}
+
+func TestSuggest(t *testing.T) {
+	t.Parallel()
+
+	names := []string{"table-bloat", "index-bloat", "hot-chain-bloat", "index-usage", "vacuum", "list"}
+
+	assert.Equal(t, []string{"table-bloat"}, suggest(names, "table-blot"))
+	assert.Equal(t, []string{"index-bloat", "table-bloat", "hot-chain-bloat"}, suggest(names, "bloat"), "substrings match, closest first")
+	assert.Equal(t, []string{"list"}, suggest(names, "lsit"))
+	assert.Empty(t, suggest(names, "replication"))
+}
+
+func TestEditDistance(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0, editDistance("vacuum", "vacuum"))
+	assert.Equal(t, 1, editDistance("table-bloat", "table-blot"))
+	assert.Equal(t, 2, editDistance("list", "lsit"))
+	assert.Equal(t, 3, editDistance("", "abc"))
+}
+
+func TestRootCommand_UnknownCheckID(t *testing.T) {
+	t.Setenv(checksDirEnv, "")
+
+	cmd := newRootCommand("test")
+	cmd.SetArgs([]string{"table-blot"})
+	err := cmd.Execute()
+
+	var silent *SilentError
+	require.ErrorAs(t, err, &silent)
+	assert.Equal(t, 1, silent.ExitCode)
+}
+
+func TestRootCommand_CheckIDNeedsDSN(t *testing.T) {
+	t.Setenv(checksDirEnv, "")
+	t.Setenv("PGDOCTOR_DSN", "")
+
+	cmd := newRootCommand("test")
+	cmd.SetArgs([]string{"table-bloat", "--detail", "verbose"})
+	err := cmd.Execute()
+	require.ErrorContains(t, err, "connection string required")
+}
+
+func TestRootCommand_SubcommandsWin(t *testing.T) {
+	t.Setenv(checksDirEnv, "")
+
+	var out bytes.Buffer
+	cmd := newRootCommand("test")
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"list"})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), "table-bloat")
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/emancu/pgdoctor/check"
+)
+
+// configFileEnv points at a YAML file of per-check settings. It is the
+// default for --config.
+const configFileEnv = "PGDOCTOR_CONFIG"
+
+// loadConfigFile reads per-check settings from a YAML file shaped like
+// check.Config, plus an optional categories section of per-category
+// settings:
+//
+//	table-bloat:
+//	  enabled: false
+//	session-settings:
+//	  timeout_warn: 2000
+//	categories:
+//	  indexes:
+//	    max_severity: warn
+//
+// Category settings are stored under the category name, where the runner
+// reads them (check.Config.SeverityLimit). An empty path returns an empty
+// config.
+func loadConfigFile(path string) (check.Config, error) {
+	if path == "" {
+		return check.Config{}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+	return parseConfigFile(path, data)
+}
+
+// categoriesKey is the config file section of per-category settings.
+const categoriesKey = "categories"
+
+type configFile struct {
+	Categories map[string]map[string]string `yaml:"categories"`
+	Checks     check.Config                 `yaml:",inline"`
+}
+
+func parseConfigFile(path string, data []byte) (check.Config, error) {
+	var file configFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+
+	cfg := file.Checks
+	if cfg == nil {
+		cfg = check.Config{}
+	}
+	for checkID, keys := range cfg {
+		if value, ok := keys[check.EnabledKey]; ok {
+			if _, err := strconv.ParseBool(value); err != nil {
+				return nil, fmt.Errorf("config file %s: %s.%s must be true or false, got %q", path, checkID, check.EnabledKey, value)
+			}
+		}
+	}
+
+	for category, keys := range file.Categories {
+		for _, key := range []string{check.MinSeverityKey, check.MaxSeverityKey} {
+			if value, ok := keys[key]; ok {
+				if _, err := check.ParseSeverity(value); err != nil {
+					return nil, fmt.Errorf("config file %s: %s.%s.%s: %w", path, categoriesKey, category, key, err)
+				}
+			}
+		}
+		if limit := (check.Config{category: keys}).SeverityLimit(check.Category(category)); limit.Max > check.SeverityOK && limit.Min > limit.Max {
+			return nil, fmt.Errorf("config file %s: %s.%s: %s %s is above %s %s", path, categoriesKey, category, check.MinSeverityKey, limit.Min, check.MaxSeverityKey, limit.Max)
+		}
+		cfg = cfg.Merge(check.Config{category: keys})
+	}
+	return cfg, nil
+}
+
+// parseConfigArgs parses the <check-id>.<key>=<value> settings of --arg into a
+// config to layer over the config file; a later --arg for the same key wins.
+// Like config file values, the values are strings each check interprets. The
+// check IDs are verified by unknownConfigChecks once the checks are loaded.
+func parseConfigArgs(values []string) (check.Config, error) {
+	cfg := check.Config{}
+	for _, v := range values {
+		setting, value, ok := strings.Cut(v, "=")
+		checkID, key, dotted := strings.Cut(setting, ".")
+		if !ok || !dotted || checkID == "" || key == "" {
+			return nil, fmt.Errorf("--arg %q: want <check-id>.<key>=<value>", v)
+		}
+		if key == check.EnabledKey {
+			if _, err := strconv.ParseBool(value); err != nil {
+				return nil, fmt.Errorf("--arg %q: %s must be true or false", v, check.EnabledKey)
+			}
+		}
+		if cfg[checkID] == nil {
+			cfg[checkID] = map[string]string{}
+		}
+		cfg[checkID][key] = value
+	}
+	return cfg, nil
+}
+
+// unknownConfigChecks returns the check IDs of cfg that are not among checks,
+// in order.
+func unknownConfigChecks(cfg check.Config, checks []check.Package) []string {
+	known := make(map[string]bool, len(checks))
+	for _, pkg := range checks {
+		known[pkg.Metadata().CheckID] = true
+	}
+	var unknown []string
+	for checkID := range cfg {
+		if !known[checkID] {
+			unknown = append(unknown, checkID)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emancu/pgdoctor/check"
+)
+
+func TestLoadConfigFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "pgdoctor.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+table-bloat:
+  enabled: false
+session-settings:
+  timeout_warn: 2000
+`), 0o600))
+
+	cfg, err := loadConfigFile(path)
+	require.NoError(t, err)
+
+	assert.False(t, cfg.Enabled("table-bloat"))
+	assert.True(t, cfg.Enabled("session-settings"))
+	assert.Equal(t, "2000", cfg["session-settings"]["timeout_warn"], "YAML scalars load as strings")
+}
+
+func TestParseConfigFile_Categories(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := parseConfigFile("pgdoctor.yaml", []byte(`
+table-bloat:
+  enabled: false
+categories:
+  indexes:
+    max_severity: warn
+  configs:
+    min_severity: fail
+`))
+	require.NoError(t, err)
+
+	assert.False(t, cfg.Enabled("table-bloat"))
+	assert.Equal(t, check.SeverityLimit{Max: check.SeverityWarn}, cfg.SeverityLimit(check.CategoryIndexes))
+	assert.Equal(t, check.SeverityLimit{Min: check.SeverityFail}, cfg.SeverityLimit(check.CategoryConfigs))
+	assert.NotContains(t, cfg, "categories")
+}
+
+func TestParseConfigFile_CategoryErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := parseConfigFile("bad.yaml", []byte("categories:\n  indexes:\n    max_severity: critical\n"))
+	require.ErrorContains(t, err, `categories.indexes.max_severity: unknown severity "critical"`)
+
+	_, err = parseConfigFile("bad.yaml", []byte("categories:\n  indexes:\n    min_severity: fail\n    max_severity: warn\n"))
+	require.ErrorContains(t, err, "min_severity fail is above max_severity warn")
+}
+
+func TestLoadConfigFile_NoPath(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := loadConfigFile("")
+	require.NoError(t, err)
+	assert.Empty(t, cfg)
+}
+
+func TestLoadConfigFile_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := loadConfigFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.ErrorContains(t, err, "reading config file")
+
+	_, err = parseConfigFile("bad.yaml", []byte("table-bloat: [1, 2]"))
+	require.ErrorContains(t, err, "parsing config file bad.yaml")
+
+	_, err = parseConfigFile("bad.yaml", []byte("table-bloat:\n  enabled: sometimes\n"))
+	require.ErrorContains(t, err, `table-bloat.enabled must be true or false, got "sometimes"`)
+}
+
+func TestParseConfigFile_Empty(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := parseConfigFile("empty.yaml", nil)
+	require.NoError(t, err)
+	assert.NotNil(t, cfg)
+}
+
+func TestParseConfigArgs(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := parseConfigArgs([]string{
+		"session-settings.timeout_warn=2000",
+		"session-settings.roles=app,reporting",
+		"table-bloat.enabled=false",
+		"session-settings.timeout_warn=3000",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, check.Config{
+		"session-settings": {"timeout_warn": "3000", "roles": "app,reporting"},
+		"table-bloat":      {"enabled": "false"},
+	}, cfg, "a later --arg for the same key wins")
+
+	fileCfg := check.Config{"session-settings": {"timeout_warn": "1000", "timeout_fail": "9000"}}
+	merged := fileCfg.Merge(cfg)
+	assert.Equal(t, "3000", merged["session-settings"]["timeout_warn"], "--arg is layered over the config file")
+	assert.Equal(t, "9000", merged["session-settings"]["timeout_fail"])
+
+	cfg, err = parseConfigArgs([]string{"cost-params.storage=ssd=fast"})
+	require.NoError(t, err)
+	assert.Equal(t, "ssd=fast", cfg["cost-params"]["storage"], "values may contain =")
+
+	cfg, err = parseConfigArgs(nil)
+	require.NoError(t, err)
+	assert.Empty(t, cfg)
+}
+
+func TestParseConfigArgs_Errors(t *testing.T) {
+	t.Parallel()
+
+	for _, arg := range []string{"timeout_warn=2000", "session-settings.timeout_warn", ".timeout_warn=1", "session-settings.=1"} {
+		_, err := parseConfigArgs([]string{arg})
+		require.ErrorContains(t, err, "want <check-id>.<key>=<value>", arg)
+	}
+
+	_, err := parseConfigArgs([]string{"table-bloat.enabled=sometimes"})
+	require.ErrorContains(t, err, "enabled must be true or false")
+}
+
+func TestUnknownConfigChecks(t *testing.T) {
+	t.Parallel()
+
+	cfg := check.Config{"table-bloat": {"enabled": "false"}, "table-blaot": {"x": "1"}, "bloat": {"x": "1"}}
+	checks := []check.Package{{Metadata: func() check.Metadata { return check.Metadata{CheckID: "table-bloat"} }}}
+	assert.Equal(t, []string{"bloat", "table-blaot"}, unknownConfigChecks(cfg, checks))
+}
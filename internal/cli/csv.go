@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/emancu/pgdoctor/check"
+)
+
+// csvSummaryFile is the file formatCSV lists every check in.
+const csvSummaryFile = "_summary.csv"
+
+// csvTable is one CSV file being assembled from the tables of one check.
+type csvTable struct {
+	name    string
+	columns []string // the check.Table headers the rows have
+	headers []string
+	rows    [][]string
+}
+
+// unsafeFileChars are replaced in finding IDs used in file names.
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// formatCSV writes the reports as CSV files for spreadsheets into dir,
+// creating it if needed. They go to a directory rather than stdout since
+// checks' tables have different columns: <check-id>.csv holds the rows of a
+// check's tables, under the finding and row severity and the table's own
+// headers, and _summary.csv has a row per check with its severity and the
+// files holding its tables. When several databases were checked, both start
+// with a Database column and a check's file holds the rows of every
+// database. A finding whose table has other headers than the check's first
+// table with rows gets its own <check-id>.<finding-id>.csv.
+//
+// Files of earlier runs are overwritten, and other files are left alone.
+func formatCSV(dir string, reports []*check.Report) error {
+	multiDB := slices.ContainsFunc(reports, func(r *check.Report) bool { return r.Database != "" })
+	lead := func(database string, cells ...string) []string {
+		if multiDB {
+			return append([]string{database}, cells...)
+		}
+		return cells
+	}
+
+	var files []*csvTable
+	byName := map[string]*csvTable{}
+	summary := &csvTable{name: csvSummaryFile, headers: lead("Database", "Category", "Check", "Name", "Severity", "Tables")}
+	for _, r := range reports {
+		var tables []string
+		for _, f := range r.Results {
+			if f.Table == nil || len(f.Table.Headers) == 0 || len(f.Table.Rows) == 0 {
+				continue
+			}
+			name := r.CheckID + ".csv"
+			if file, ok := byName[name]; ok && !slices.Equal(file.columns, f.Table.Headers) {
+				name = r.CheckID + "." + unsafeFileChars.ReplaceAllString(f.ID, "_") + ".csv"
+			}
+			file, ok := byName[name]
+			if !ok {
+				file = &csvTable{
+					name:    name,
+					columns: f.Table.Headers,
+					headers: lead("Database", append([]string{"Finding", "Severity"}, f.Table.Headers...)...),
+				}
+				byName[name] = file
+				files = append(files, file)
+			}
+			for _, row := range f.Table.Rows {
+				file.rows = append(file.rows, lead(r.Database, append([]string{f.ID, row.Severity.String()}, row.Cells...)...))
+			}
+			if !slices.Contains(tables, name) {
+				tables = append(tables, name)
+			}
+		}
+		summary.rows = append(summary.rows, lead(r.Database, string(r.Category), r.CheckID, r.Name, r.Severity.String(), strings.Join(tables, " ")))
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating --output-dir: %w", err)
+	}
+	for _, file := range append(files, summary) {
+		if err := file.write(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// write writes the file into dir.
+func (t *csvTable) write(dir string) error {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write(t.headers)
+	_ = w.WriteAll(t.rows) // flushes
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("writing %s: %w", t.name, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, t.name), buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", t.name, err)
+	}
+	return nil
+}
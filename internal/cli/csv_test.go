@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emancu/pgdoctor/check"
+)
+
+func readCSVFile(t *testing.T, dir, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestFormatCSV(t *testing.T) {
+	t.Parallel()
+
+	reports := sampleReports()
+	reports[1].Results[1].Table.Rows[0].Cells[0] = `public."events", archived`
+	dir := filepath.Join(t.TempDir(), "reports")
+	require.NoError(t, formatCSV(dir, reports))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	assert.Equal(t, []string{"_summary.csv", "table-bloat.csv"}, names, "checks without table rows have no file")
+
+	assert.Equal(t, "Finding,Severity,Table,Bloat\n"+
+		`bloat,fail,"public.""events"", archived",72%`+"\n"+
+		"bloat,warn,public.users,41%\n",
+		readCSVFile(t, dir, "table-bloat.csv"))
+	assert.Equal(t, "Category,Check,Name,Severity,Tables\n"+
+		"configs,pg-version,PostgreSQL Version,pass,\n"+
+		"vacuum,table-bloat,Table Bloat,fail,table-bloat.csv\n"+
+		"indexes,index-usage,Index Usage,pass,\n"+
+		"performance,temp-usage,Temp Usage,skip,\n"+
+		"configs,statements-reset,Statements Reset,n/a,\n",
+		readCSVFile(t, dir, "_summary.csv"))
+}
+
+func TestFormatCSV_DatabasesAndMixedHeaders(t *testing.T) {
+	t.Parallel()
+
+	report := func(database string) *check.Report {
+		r := check.NewReport(check.Metadata{CheckID: "index-usage", Name: "Index Usage", Category: check.CategoryIndexes})
+		r.Database = database
+		r.AddFinding(check.Finding{ID: "unused", Severity: check.SeverityWarn, Table: &check.Table{
+			Headers: []string{"Index", "Size"},
+			Rows:    []check.TableRow{{Cells: []string{"public.a_idx", "1.0GiB"}, Severity: check.SeverityWarn}},
+		}})
+		r.AddFinding(check.Finding{ID: "low/usage", Severity: check.SeverityWarn, Table: &check.Table{
+			Headers: []string{"Index", "Scans"},
+			Rows:    []check.TableRow{{Cells: []string{"public.b_idx", "3"}, Severity: check.SeverityWarn}},
+		}})
+		return r
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, formatCSV(dir, []*check.Report{report("app"), report("billing")}))
+
+	assert.Equal(t, "Database,Finding,Severity,Index,Size\n"+
+		"app,unused,warn,public.a_idx,1.0GiB\n"+
+		"billing,unused,warn,public.a_idx,1.0GiB\n",
+		readCSVFile(t, dir, "index-usage.csv"))
+	assert.Equal(t, "Database,Finding,Severity,Index,Scans\n"+
+		"app,low/usage,warn,public.b_idx,3\n"+
+		"billing,low/usage,warn,public.b_idx,3\n",
+		readCSVFile(t, dir, "index-usage.low_usage.csv"))
+	assert.Equal(t, "Database,Category,Check,Name,Severity,Tables\n"+
+		"app,indexes,index-usage,Index Usage,warn,index-usage.csv index-usage.low_usage.csv\n"+
+		"billing,indexes,index-usage,Index Usage,warn,index-usage.csv index-usage.low_usage.csv\n",
+		readCSVFile(t, dir, "_summary.csv"))
+}
+
+func TestFormatCSV_CheckNamedSummary(t *testing.T) {
+	t.Parallel()
+
+	r := check.NewReport(check.Metadata{CheckID: "summary", Name: "Summary", Category: check.CategoryConfigs})
+	r.AddFinding(check.Finding{ID: "rows", Severity: check.SeverityWarn, Table: &check.Table{
+		Headers: []string{"Name"},
+		Rows:    []check.TableRow{{Cells: []string{"a"}, Severity: check.SeverityWarn}},
+	}})
+
+	dir := t.TempDir()
+	require.NoError(t, formatCSV(dir, []*check.Report{r}))
+
+	assert.Equal(t, "Finding,Severity,Name\nrows,warn,a\n", readCSVFile(t, dir, "summary.csv"))
+	assert.Equal(t, "Category,Check,Name,Severity,Tables\n"+
+		"configs,summary,Summary,warn,summary.csv\n",
+		readCSVFile(t, dir, "_summary.csv"))
+}
+
+func TestRenderCommand_CSV(t *testing.T) {
+	t.Parallel()
+
+	var saved bytes.Buffer
+	require.NoError(t, formatJSON(&saved, sampleRun(), sampleReports()))
+
+	dir := t.TempDir()
+	var out bytes.Buffer
+	cmd := newRootCommand("test")
+	cmd.SetIn(bytes.NewReader(saved.Bytes()))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"render", "--input", "-", "--output", "csv", "--output-dir", dir})
+	require.NoError(t, cmd.Execute())
+	assert.Empty(t, out.String(), "nothing is written to stdout")
+	assert.Contains(t, readCSVFile(t, dir, "_summary.csv"), "vacuum,table-bloat,Table Bloat,fail,table-bloat.csv\n")
+
+	for _, args := range [][]string{
+		{"render", "--input", "-", "--output", "csv"},
+		{"render", "--input", "-", "--output-dir", dir},
+	} {
+		cmd := newRootCommand("test")
+		cmd.SetIn(bytes.NewReader(saved.Bytes()))
+		cmd.SetArgs(args)
+		var silent *SilentError
+		require.ErrorAs(t, cmd.Execute(), &silent, "%v", args)
+	}
+}
@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/emancu/pgdoctor"
+	"github.com/emancu/pgdoctor/check"
+)
+
+// setStatementTimeout makes PostgreSQL kill pgdoctor's slow queries. Checks
+// whose query is cancelled are reported as skipped.
+func setStatementTimeout(ctx context.Context, conn *pgx.Conn, ms int64) error {
+	if _, err := conn.Exec(ctx, fmt.Sprintf("SET statement_timeout = %d", ms)); err != nil {
+		return fmt.Errorf("failed to set statement_timeout: %w", err)
+	}
+	return nil
+}
+
+// listDatabases returns the databases --all-databases visits: every database
+// that accepts connections, except the templates.
+func listDatabases(ctx context.Context, conn *pgx.Conn) ([]string, error) {
+	rows, err := conn.Query(ctx, "SELECT datname::text FROM pg_database WHERE NOT datistemplate AND datallowconn ORDER BY datname")
+	if err != nil {
+		return nil, fmt.Errorf("listing databases: %w", err)
+	}
+	names, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("listing databases: %w", err)
+	}
+	return names, nil
+}
+
+// runAllDatabases runs cluster-wide checks once on conn, then the remaining
+// checks in every database, each over its own connection with the same
+// credentials and statement_timeout. Up to concurrency databases are checked
+// at once, so the run holds at most concurrency+1 connections. A pgx
+// connection, and so a pool, is bound to one database, which is why each
+// database gets a connection of its own rather than one from a shared pool.
+// Per-database reports carry the database's name, and reach opts.OnReport
+// one at a time. A database that can't be reached is reported on stderr and
+// skipped. Once ctx is done, the checks of every database not yet checked
+// are reported as skipped.
+func runAllDatabases(ctx context.Context, conn *pgx.Conn, opts pgdoctor.Options, timeoutMs int64, concurrency int) error {
+	databases, err := listDatabases(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	clusterWide, perDatabase := pgdoctor.SplitClusterWide(opts.Checks)
+
+	clusterOpts := opts
+	clusterOpts.Checks = clusterWide
+	pgdoctor.Run(ctx, conn, clusterOpts)
+
+	if len(perDatabase) == 0 {
+		return nil
+	}
+
+	var mu sync.Mutex
+	onReport := opts.OnReport
+	if onReport != nil {
+		opts.OnReport = func(r *check.Report) {
+			mu.Lock()
+			defer mu.Unlock()
+			onReport(r)
+		}
+	}
+
+	cfg := conn.Config()
+	dbOpts := opts
+	dbOpts.Checks = perDatabase
+	runIn := func(name string, dbConn *pgx.Conn) {
+		runOpts := dbOpts
+		runOpts.Database = name
+		pgdoctor.Run(ctx, dbConn, runOpts)
+	}
+	notVisited := forEachDatabase(ctx, databases, concurrency, func(name string) {
+		dbCfg := cfg.Copy()
+		dbCfg.Database = name
+		dbConn, err := pgx.ConnectConfig(ctx, dbCfg)
+		if err != nil {
+			// A cancelled run fails to connect because of the cancel, which
+			// is no news about the database: report its checks as skipped.
+			if ctx.Err() != nil {
+				runIn(name, nil)
+				return
+			}
+			fmt.Fprintf(os.Stderr, "Warning: skipping database %s: %v\n", name, err)
+			return
+		}
+		defer dbConn.Close(ctx)
+		if err := setStatementTimeout(ctx, dbConn, timeoutMs); err != nil {
+			if ctx.Err() != nil {
+				runIn(name, nil)
+				return
+			}
+			fmt.Fprintf(os.Stderr, "Warning: skipping database %s: %v\n", name, err)
+			return
+		}
+
+		runIn(name, dbConn)
+	})
+
+	// Run reports every check as skipped, without touching the connection,
+	// once ctx is done; databases the run never reached report theirs too, so
+	// --fail-fast counts them among the checks that did not run.
+	for _, name := range notVisited {
+		runIn(name, nil)
+	}
+	return nil
+}
+
+// forEachDatabase calls fn for each database from up to concurrency
+// goroutines, in order of names as workers free up, and returns once every
+// call has. Databases not started when ctx is done are not visited; their
+// names are returned in order.
+func forEachDatabase(ctx context.Context, names []string, concurrency int, fn func(name string)) (notVisited []string) {
+	concurrency = max(1, min(concurrency, len(names)))
+	queue := make(chan string)
+	var mu sync.Mutex
+	dropped := make(map[string]bool)
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range queue {
+				if ctx.Err() != nil {
+					mu.Lock()
+					dropped[name] = true
+					mu.Unlock()
+					continue
+				}
+				fn(name)
+			}
+		}()
+	}
+
+	sent := 0
+	for _, name := range names {
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case queue <- name:
+			sent++
+		case <-ctx.Done():
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	for i, name := range names {
+		if i >= sent || dropped[name] {
+			notVisited = append(notVisited, name)
+		}
+	}
+	return notVisited
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForEachDatabase_BoundsConcurrency(t *testing.T) {
+	t.Parallel()
+
+	names := []string{"a", "b", "c", "d", "e", "f", "g"}
+	var running, peak atomic.Int32
+	var mu sync.Mutex
+	var visited []string
+	forEachDatabase(context.Background(), names, 3, func(name string) {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+
+		mu.Lock()
+		visited = append(visited, name)
+		mu.Unlock()
+	})
+
+	assert.ElementsMatch(t, names, visited)
+	assert.LessOrEqual(t, peak.Load(), int32(3))
+}
+
+func TestForEachDatabase_Sequential(t *testing.T) {
+	t.Parallel()
+
+	var visited []string
+	forEachDatabase(context.Background(), []string{"a", "b", "c"}, 1, func(name string) {
+		visited = append(visited, name)
+	})
+	assert.Equal(t, []string{"a", "b", "c"}, visited, "one at a time keeps the database order")
+
+	forEachDatabase(context.Background(), nil, 4, func(string) { t.Fatal("no databases") })
+}
+
+func TestForEachDatabase_StopsWhenCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var visited []string
+	notVisited := forEachDatabase(ctx, []string{"a", "b", "c"}, 1, func(name string) {
+		visited = append(visited, name)
+		cancel()
+	})
+	assert.Equal(t, []string{"a"}, visited)
+	assert.Equal(t, []string{"b", "c"}, notVisited)
+
+	notVisited = forEachDatabase(ctx, []string{"a", "b"}, 2, func(string) { t.Fatal("cancelled before the first database") })
+	assert.Equal(t, []string{"a", "b"}, notVisited)
+}
+
+func TestRunCommand_ConcurrencyValidation(t *testing.T) {
+	t.Parallel()
+
+	for name, args := range map[string][]string{
+		"without --all-databases":       {"--concurrency", "4"},
+		"zero":                          {"--all-databases", "--concurrency", "0"},
+		"heavy without --all-databases": {"--heavy-concurrency", "2"},
+		"heavy zero":                    {"--all-databases", "--heavy-concurrency", "0"},
+	} {
+		cmd := newRootCommand("test")
+		cmd.SetArgs(append([]string{"run", "postgres://localhost:1/app"}, args...))
+		var silent *SilentError
+		require.ErrorAs(t, cmd.Execute(), &silent, name)
+		assert.Equal(t, 1, silent.ExitCode, "%s fails before connecting", name)
+	}
+}
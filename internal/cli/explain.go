@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/charmbracelet/glamour"
 	"github.com/fatih/color"
@@ -27,7 +28,14 @@ func newExplainCommand() *cobra.Command {
 
 	cmd.PersistentFlags().BoolVar(&sqlOnly, "sql-only", false, "Show only the SQL query used by the check")
 
-	for _, pkg := range pgdoctor.AllChecks() {
+	// A broken external checks directory is reported by run and list; here
+	// explain falls back to the built-in checks.
+	checks, err := availableChecks(os.Getenv(checksDirEnv))
+	if err != nil {
+		checks = pgdoctor.AllChecks()
+	}
+
+	for _, pkg := range checks {
 		metadata := pkg.Metadata()
 		cmd.AddCommand(newExplainCheckCommand(metadata, &sqlOnly))
 	}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/emancu/pgdoctor"
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/sqlcheck"
+)
+
+// checksDirEnv points at a directory of external SQL-only checks. It is the
+// default for --checks-dir and the only source `explain` can use, since its
+// subcommands are registered before flags are parsed.
+const checksDirEnv = "PGDOCTOR_CHECKS_DIR"
+
+// availableChecks returns the built-in checks plus the external SQL checks
+// loaded from dir, if any.
+func availableChecks(dir string) ([]check.Package, error) {
+	checks := pgdoctor.AllChecks()
+	if dir == "" {
+		return checks, nil
+	}
+
+	external, err := sqlcheck.LoadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	builtin := make(map[string]struct{}, len(checks))
+	for _, pkg := range checks {
+		builtin[pkg.Metadata().CheckID] = struct{}{}
+	}
+	for _, pkg := range external {
+		if _, ok := builtin[pkg.Metadata().CheckID]; ok {
+			return nil, fmt.Errorf("external check %q in %s conflicts with a built-in check", pkg.Metadata().CheckID, dir)
+		}
+	}
+
+	return append(checks, external...), nil
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/emancu/pgdoctor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeExternalCheck(t *testing.T, dir, checkID string) {
+	t.Helper()
+
+	checkDir := filepath.Join(dir, checkID)
+	require.NoError(t, os.MkdirAll(checkDir, 0o755))
+	metadata := "check_id: " + checkID + "\nname: External\ncategory: schema\n"
+	require.NoError(t, os.WriteFile(filepath.Join(checkDir, "metadata.yaml"), []byte(metadata), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(checkDir, "query.sql"), []byte("SELECT 1"), 0o600))
+}
+
+func TestAvailableChecks_AppendsExternalChecks(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeExternalCheck(t, dir, "org-naming")
+
+	checks, err := availableChecks(dir)
+	require.NoError(t, err)
+	require.Len(t, checks, len(pgdoctor.AllChecks())+1)
+	assert.Equal(t, "org-naming", checks[len(checks)-1].Metadata().CheckID)
+}
+
+func TestAvailableChecks_RejectsBuiltinConflict(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeExternalCheck(t, dir, "pg-version")
+
+	_, err := availableChecks(dir)
+	require.ErrorContains(t, err, `"pg-version"`)
+}
+
+func TestAvailableChecks_NoDirectory(t *testing.T) {
+	t.Parallel()
+
+	checks, err := availableChecks("")
+	require.NoError(t, err)
+	assert.Len(t, checks, len(pgdoctor.AllChecks()))
+}
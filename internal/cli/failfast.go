@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/emancu/pgdoctor"
+	"github.com/emancu/pgdoctor/check"
+)
+
+// failFast stops a run at the first failing check, for --fail-fast, by
+// cancelling the context the checks run with. Checks already running are
+// interrupted; the runner reports them, and those it did not start, as
+// skipped, and failFast counts them instead of passing them on.
+type failFast struct {
+	cancel context.CancelCauseFunc
+	// stoppedBy is the check that failed first, as checkLabel names it, or
+	// empty while the run goes on.
+	stoppedBy string
+	notRun    int
+}
+
+// newFailFast returns a context for the run's checks and the failFast that
+// cancels it.
+func newFailFast(ctx context.Context) (context.Context, *failFast) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return ctx, &failFast{cancel: cancel}
+}
+
+// wrap returns a handler that passes reports on to onReport and stops the run
+// after the first one that fails. Reports must reach it one at a time.
+func (f *failFast) wrap(onReport pgdoctor.ReportHandler) pgdoctor.ReportHandler {
+	return func(r *check.Report) {
+		if f.stoppedBy != "" && isSkipped(r) {
+			f.notRun++
+			return
+		}
+		onReport(r)
+		if f.stoppedBy == "" && r.Severity == check.SeverityFail {
+			f.stoppedBy = checkLabel(r)
+			f.cancel(fmt.Errorf("stopped by --fail-fast after %s failed", f.stoppedBy))
+		}
+	}
+}
+
+// record notes in run where the run stopped, if it did.
+func (f *failFast) record(run *runInfo) {
+	if f.stoppedBy != "" {
+		run.stoppedEarly = &jsonStoppedEarly{Failed: f.stoppedBy, NotRun: f.notRun}
+	}
+}
+
+// stoppedEarlyNote tells where --fail-fast stopped a run, or returns "" when
+// it ran to the end.
+func stoppedEarlyNote(run runInfo) string {
+	if run.stoppedEarly == nil {
+		return ""
+	}
+	return fmt.Sprintf("Stopped early by --fail-fast: %s failed; %d check(s) did not run",
+		run.stoppedEarly.Failed, run.stoppedEarly.NotRun)
+}
+
+// printStoppedEarly prints where --fail-fast stopped the run in text output,
+// ahead of the summary.
+func printStoppedEarly(w io.Writer, run runInfo) {
+	if note := stoppedEarlyNote(run); note != "" {
+		fmt.Fprintf(w, "\n%s\n", colorForSeverity(check.SeverityFail)(note))
+	}
+}
+
+// isSkipped reports whether the runner skipped r without running it to the
+// end, as it does for checks cut off by a cancelled run.
+func isSkipped(r *check.Report) bool {
+	for _, f := range r.Results {
+		if f.ID == pgdoctor.SkippedFindingID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emancu/pgdoctor"
+	"github.com/emancu/pgdoctor/check"
+)
+
+func TestFailFast(t *testing.T) {
+	t.Parallel()
+
+	report := func(id string, severity check.Severity, findingID string) *check.Report {
+		r := check.NewReport(check.Metadata{CheckID: id, Name: id, Category: check.CategoryVacuum})
+		r.AddFinding(check.Finding{ID: findingID, Name: id, Severity: severity})
+		return r
+	}
+
+	ctx, stop := newFailFast(context.Background())
+	var reports []*check.Report
+	onReport := stop.wrap(pgdoctor.Collect(&reports))
+
+	onReport(report("freeze-age", check.SeverityWarn, "freeze-age"))
+	require.NoError(t, ctx.Err(), "warnings do not stop the run")
+
+	onReport(report("table-bloat", check.SeverityFail, "table-bloat"))
+	require.Error(t, ctx.Err())
+	assert.EqualError(t, context.Cause(ctx), "stopped by --fail-fast after table-bloat failed")
+
+	onReport(report("index-bloat", check.SeveritySkip, pgdoctor.SkippedFindingID))
+	onReport(report("vacuum-settings", check.SeveritySkip, pgdoctor.SkippedFindingID))
+	failed := report("xmin-horizon", check.SeverityFail, "xmin-horizon")
+	onReport(failed)
+
+	require.Len(t, reports, 3, "checks cut off by the stop are left out")
+	assert.Same(t, failed, reports[2], "checks that finished before the stop are kept")
+
+	var run runInfo
+	stop.record(&run)
+	assert.Equal(t, "Stopped early by --fail-fast: table-bloat failed; 2 check(s) did not run", stoppedEarlyNote(run))
+}
+
+func TestFailFast_NotStopped(t *testing.T) {
+	t.Parallel()
+
+	_, stop := newFailFast(context.Background())
+	var run runInfo
+	stop.record(&run)
+	assert.Nil(t, run.stoppedEarly)
+	assert.Empty(t, stoppedEarlyNote(run))
+}
+
+func TestRenderCommand_StoppedEarly(t *testing.T) {
+	t.Parallel()
+
+	run := sampleRun()
+	run.stoppedEarly = &jsonStoppedEarly{Failed: "table-bloat", NotRun: 2}
+
+	var saved bytes.Buffer
+	require.NoError(t, formatJSON(&saved, run, sampleReports()))
+	assert.NoError(t, validateJSON(t, compileSchema(t), saved.Bytes()))
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	require.NoError(t, os.WriteFile(path, saved.Bytes(), 0o600))
+
+	var out bytes.Buffer
+	cmd := newRootCommand("test")
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"render", "--input", path})
+	_ = cmd.Execute()
+	assert.Contains(t, out.String(), "\nStopped early by --fail-fast: table-bloat failed; 2 check(s) did not run\n")
+}
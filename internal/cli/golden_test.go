@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/check/checktest"
+	"github.com/emancu/pgdoctor/checks/iotiming"
+	"github.com/emancu/pgdoctor/db"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/ with the current output")
+
+type ioTimingQueries struct{}
+
+func (ioTimingQueries) IoTiming(context.Context) (db.IoTimingRow, error) {
+	return db.IoTimingRow{TrackIoTiming: "off", TrackFunctions: "none", TrackActivityQuerySizeBytes: 1024, PlFunctions: 12, TruncatedQueries: 3}, nil
+}
+
+// goldenReports are sampleReports with the report of a real check, run on
+// fake query results, for a table of settings with mixed severities.
+func goldenReports(t *testing.T) []*check.Report {
+	t.Helper()
+	reports := sampleReports()
+	reports[1].Duration = 42_000_000 // 42ms
+	return append(reports, checktest.Run(t, iotiming.New(ioTimingQueries{})))
+}
+
+// renderGolden renders reports, saved as JSON, with render and args.
+func renderGolden(t *testing.T, reports []*check.Report, args ...string) []byte {
+	t.Helper()
+
+	var saved bytes.Buffer
+	require.NoError(t, formatJSON(&saved, sampleRun(), reports))
+
+	var out bytes.Buffer
+	cmd := newRootCommand("test")
+	cmd.SetIn(&saved)
+	cmd.SetOut(&out)
+	// A fixed width, so the output does not depend on $COLUMNS.
+	cmd.SetArgs(append([]string{"render", "--input", "-", "--width", "100"}, args...))
+	_ = cmd.Execute() // text output exits 1 for the failing check
+	return out.Bytes()
+}
+
+// TestGolden pins the output of each format for a fixed set of reports, so
+// that changes to the formatters show up as a diff of testdata/. After an
+// intended change, run go test ./internal/cli -run TestGolden -update and
+// review the diff.
+func TestGolden(t *testing.T) {
+	t.Parallel()
+
+	for name, args := range map[string][]string{
+		"text.golden":         nil,
+		"text-verbose.golden": {"--detail", "verbose", "--reasons"},
+		"text-compact.golden": {"--compact"},
+		"text-top.golden":     {"--top", "3", "--hide-passing"},
+		"json.golden":         {"--output", "json"},
+		"summary-json.golden": {"--output", "summary-json"},
+		"openmetrics.golden":  {"--output", "openmetrics"},
+		"junit.golden":        {"--output", "junit"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := renderGolden(t, goldenReports(t), args...)
+			path := filepath.Join("testdata", name)
+			if *update {
+				require.NoError(t, os.WriteFile(path, got, 0o644))
+				return
+			}
+			want, err := os.ReadFile(path)
+			require.NoError(t, err, "run with -update to create it")
+			assert.Equal(t, string(want), string(got), "output changed; if intended, rerun with -update and review the diff")
+		})
+	}
+}
+
+// TestGolden_IndependentOfReportOrder checks that output does not depend on
+// the order reports complete in, which varies between runs with
+// --all-databases --concurrency N: reports are sorted before any format
+// prints them.
+func TestGolden_IndependentOfReportOrder(t *testing.T) {
+	t.Parallel()
+
+	for _, args := range [][]string{nil, {"--output", "json"}, {"--output", "summary-json"}} {
+		want := renderGolden(t, goldenReports(t), args...)
+
+		reversed := goldenReports(t)
+		slices.Reverse(reversed)
+		assert.Equal(t, string(want), string(renderGolden(t, reversed, args...)), "%v", args)
+		assert.Equal(t, string(want), string(renderGolden(t, goldenReports(t), args...)), "%v: rendering twice", args)
+	}
+}
+
+// TestGolden_CSV is TestGolden for the csv format, which writes a directory
+// of files rather than stdout: each file is pinned in testdata/csv/.
+func TestGolden_CSV(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	renderGolden(t, goldenReports(t), "--output", "csv", "--output-dir", dir)
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	goldenDir := filepath.Join("testdata", "csv")
+	if *update {
+		require.NoError(t, os.RemoveAll(goldenDir))
+		require.NoError(t, os.Mkdir(goldenDir, 0o755))
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+		got, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		require.NoError(t, err)
+		path := filepath.Join(goldenDir, e.Name())
+		if *update {
+			require.NoError(t, os.WriteFile(path, got, 0o644))
+			continue
+		}
+		want, err := os.ReadFile(path)
+		require.NoError(t, err, "run with -update to create it")
+		assert.Equal(t, string(want), string(got), "%s changed; if intended, rerun with -update and review the diff", e.Name())
+	}
+
+	goldens, err := os.ReadDir(goldenDir)
+	require.NoError(t, err)
+	var want []string
+	for _, e := range goldens {
+		want = append(want, e.Name())
+	}
+	assert.Equal(t, want, names, "files written; if intended, rerun with -update and review the diff")
+}
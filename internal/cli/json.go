@@ -5,46 +5,144 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/emancu/pgdoctor/check"
 )
 
+// runInfo is the run-level context recorded alongside the reports, so an
+// archived JSON document says where and how it was produced.
+type runInfo struct {
+	version       string
+	startedAt     time.Time
+	duration      time.Duration
+	serverVersion string
+	database      string
+	redact        string
+	preset        string
+	profile       string
+	only          []string
+	ignored       []string
+	checks        []string
+	tags          map[string]string
+	stoppedEarly  *jsonStoppedEarly // --fail-fast stopped the run
+}
+
+// jsonSchemaVersion is the version of the document formatJSON writes. Bump it
+// when a change would make an older render or --baseline misread a document:
+// a field removed, renamed, or given another meaning. Adding a field does not
+// need a bump.
+const jsonSchemaVersion = 1
+
+type jsonRun struct {
+	SchemaVersion   int               `json:"schema_version"`
+	PgdoctorVersion string            `json:"pgdoctor_version"`
+	StartedAt       string            `json:"started_at"`
+	DurationMs      int64             `json:"duration_ms"`
+	ServerVersion   string            `json:"server_version"`
+	Database        string            `json:"database"`
+	Redact          string            `json:"redact"`
+	Selection       jsonSelection     `json:"selection"`
+	Tags            map[string]string `json:"tags,omitempty"`
+	StoppedEarly    *jsonStoppedEarly `json:"stopped_early,omitempty"`
+	Reports         []jsonReport      `json:"reports"`
+}
+
+// jsonStoppedEarly records that --fail-fast stopped a run: the check that
+// failed first, and how many checks were left out after it.
+type jsonStoppedEarly struct {
+	Failed string `json:"failed"`
+	NotRun int    `json:"not_run"`
+}
+
+type jsonSelection struct {
+	Preset  string   `json:"preset"`
+	Profile string   `json:"profile"`
+	Only    []string `json:"only"`
+	Ignore  []string `json:"ignore"`
+	Checks  []string `json:"checks"`
+}
+
 type jsonReport struct {
-	CheckID  string        `json:"check_id"`
-	Name     string        `json:"name"`
-	Category string        `json:"category"`
-	Severity string        `json:"severity"`
-	Results  []jsonFinding `json:"results"`
+	CheckID    string        `json:"check_id"`
+	Name       string        `json:"name"`
+	Category   string        `json:"category"`
+	Database   string        `json:"database,omitempty"`
+	Severity   string        `json:"severity"`
+	DurationMs int64         `json:"duration_ms"`
+	Errored    bool          `json:"errored,omitempty"`
+	Results    []jsonFinding `json:"results"`
 }
 
 type jsonFinding struct {
-	ID       string     `json:"id"`
-	Name     string     `json:"name"`
-	Severity string     `json:"severity"`
-	Details  string     `json:"details,omitempty"`
-	Table    *jsonTable `json:"table,omitempty"`
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Severity  string     `json:"severity"`
+	Details   string     `json:"details,omitempty"`
+	Reason    string     `json:"reason,omitempty"`
+	Tags      []string   `json:"tags,omitempty"`
+	SeeAlso   []string   `json:"see_also,omitempty"`
+	FirstSeen string     `json:"first_seen,omitempty"`
+	Table     *jsonTable `json:"table,omitempty"`
 }
 
 type jsonTable struct {
 	Headers []string  `json:"headers"`
+	Key     []int     `json:"key,omitempty"`
 	Rows    []jsonRow `json:"rows"`
 }
 
 type jsonRow struct {
-	Cells    []string `json:"cells"`
-	Severity string   `json:"severity"`
+	Cells     []string `json:"cells"`
+	Severity  string   `json:"severity"`
+	FirstSeen string   `json:"first_seen,omitempty"`
 }
 
-func formatJSON(w io.Writer, reports []*check.Report) error {
-	output := make([]jsonReport, 0, len(reports))
+func formatJSON(w io.Writer, run runInfo, reports []*check.Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(newJSONRun(run, reports)); err != nil {
+		return fmt.Errorf("encoding JSON: %w", err)
+	}
+	return nil
+}
+
+// newJSONRun builds the document --output json writes, which is also the data
+// --output template executes against.
+func newJSONRun(run runInfo, reports []*check.Report) jsonRun {
+	if run.redact == "" {
+		run.redact = string(redactNone)
+	}
+	output := jsonRun{
+		SchemaVersion:   jsonSchemaVersion,
+		PgdoctorVersion: run.version,
+		StartedAt:       run.startedAt.UTC().Format(time.RFC3339),
+		DurationMs:      run.duration.Milliseconds(),
+		ServerVersion:   run.serverVersion,
+		Database:        run.database,
+		Redact:          run.redact,
+		Selection: jsonSelection{
+			Preset:  run.preset,
+			Profile: run.profile,
+			Only:    nonNil(run.only),
+			Ignore:  nonNil(run.ignored),
+			Checks:  nonNil(run.checks),
+		},
+		Tags:         run.tags,
+		StoppedEarly: run.stoppedEarly,
+		Reports:      make([]jsonReport, 0, len(reports)),
+	}
 
 	for _, report := range reports {
 		jr := jsonReport{
-			CheckID:  report.CheckID,
-			Name:     report.Name,
-			Category: string(report.Category),
-			Severity: report.Severity.String(),
-			Results:  make([]jsonFinding, 0, len(report.Results)),
+			CheckID:    report.CheckID,
+			Name:       report.Name,
+			Category:   string(report.Category),
+			Database:   report.Database,
+			Severity:   report.Severity.String(),
+			DurationMs: report.Duration.Milliseconds(),
+			Errored:    report.Errored,
+			Results:    make([]jsonFinding, 0, len(report.Results)),
 		}
 
 		for _, result := range report.Results {
@@ -53,18 +151,29 @@ func formatJSON(w io.Writer, reports []*check.Report) error {
 				Name:     result.Name,
 				Severity: result.Severity.String(),
 				Details:  result.Details,
+				Reason:   result.Reason,
+				Tags:     result.Tags,
+				SeeAlso:  result.SeeAlso,
+			}
+			if !result.FirstSeen.IsZero() {
+				jf.FirstSeen = result.FirstSeen.UTC().Format(time.RFC3339)
 			}
 
 			if result.Table != nil {
 				jt := &jsonTable{
-					Headers: result.Table.Headers,
+					Headers: nonNil(result.Table.Headers),
+					Key:     result.Table.Key,
 					Rows:    make([]jsonRow, 0, len(result.Table.Rows)),
 				}
 				for _, row := range result.Table.Rows {
-					jt.Rows = append(jt.Rows, jsonRow{
-						Cells:    row.Cells,
+					jrow := jsonRow{
+						Cells:    nonNil(row.Cells),
 						Severity: row.Severity.String(),
-					})
+					}
+					if !row.FirstSeen.IsZero() {
+						jrow.FirstSeen = row.FirstSeen.UTC().Format(time.RFC3339)
+					}
+					jt.Rows = append(jt.Rows, jrow)
 				}
 				jf.Table = jt
 			}
@@ -72,14 +181,142 @@ func formatJSON(w io.Writer, reports []*check.Report) error {
 			jr.Results = append(jr.Results, jf)
 		}
 
-		output = append(output, jr)
+		output.Reports = append(output.Reports, jr)
 	}
 
-	enc := json.NewEncoder(w)
-	enc.SetIndent("", "  ")
-	if err := enc.Encode(output); err != nil {
-		return fmt.Errorf("encoding JSON: %w", err)
+	return output
+}
+
+// decodeJSON reads a document written by formatJSON back into the run context
+// and reports, for render and --baseline. Table alignment, sensitivity
+// markers, and debug output are not part of the document, so they do not
+// survive the round trip. Documents without a schema_version predate it and
+// are read as version 1.
+func decodeJSON(r io.Reader) (runInfo, []*check.Report, error) {
+	var doc jsonRun
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return runInfo{}, nil, fmt.Errorf("decoding JSON: %w", err)
+	}
+	if doc.Reports == nil {
+		return runInfo{}, nil, fmt.Errorf("not a pgdoctor report: no \"reports\" array (render reads run --output json, not summary-json or other formats)")
+	}
+	if doc.SchemaVersion > jsonSchemaVersion {
+		return runInfo{}, nil, fmt.Errorf("report has schema_version %d, but this pgdoctor reads up to %d; upgrade pgdoctor to read it",
+			doc.SchemaVersion, jsonSchemaVersion)
 	}
 
-	return nil
+	run := runInfo{
+		version:       doc.PgdoctorVersion,
+		duration:      time.Duration(doc.DurationMs) * time.Millisecond,
+		serverVersion: doc.ServerVersion,
+		database:      doc.Database,
+		redact:        doc.Redact,
+		preset:        doc.Selection.Preset,
+		profile:       doc.Selection.Profile,
+		only:          doc.Selection.Only,
+		ignored:       doc.Selection.Ignore,
+		checks:        doc.Selection.Checks,
+		tags:          doc.Tags,
+		stoppedEarly:  doc.StoppedEarly,
+	}
+	if doc.StartedAt != "" {
+		startedAt, err := time.Parse(time.RFC3339, doc.StartedAt)
+		if err != nil {
+			return runInfo{}, nil, fmt.Errorf("parsing started_at: %w", err)
+		}
+		run.startedAt = startedAt
+	}
+
+	reports := make([]*check.Report, 0, len(doc.Reports))
+	for _, jr := range doc.Reports {
+		severity, err := decodeSeverity(jr.Severity)
+		if err != nil {
+			return runInfo{}, nil, fmt.Errorf("report %s: %w", jr.CheckID, err)
+		}
+		report := &check.Report{
+			Metadata: check.Metadata{
+				Category: check.Category(jr.Category),
+				CheckID:  jr.CheckID,
+				Name:     jr.Name,
+			},
+			Database: jr.Database,
+			Severity: severity,
+			Duration: time.Duration(jr.DurationMs) * time.Millisecond,
+			Errored:  jr.Errored,
+			Results:  make([]check.Finding, 0, len(jr.Results)),
+		}
+
+		for _, jf := range jr.Results {
+			severity, err := decodeSeverity(jf.Severity)
+			if err != nil {
+				return runInfo{}, nil, fmt.Errorf("report %s, result %s: %w", jr.CheckID, jf.ID, err)
+			}
+			finding := check.Finding{
+				ID:       jf.ID,
+				Name:     jf.Name,
+				Severity: severity,
+				Details:  jf.Details,
+				Reason:   jf.Reason,
+				Tags:     jf.Tags,
+				SeeAlso:  jf.SeeAlso,
+			}
+			if jf.FirstSeen != "" {
+				firstSeen, err := time.Parse(time.RFC3339, jf.FirstSeen)
+				if err != nil {
+					return runInfo{}, nil, fmt.Errorf("report %s, result %s: parsing first_seen: %w", jr.CheckID, jf.ID, err)
+				}
+				finding.FirstSeen = firstSeen
+			}
+
+			if jf.Table != nil {
+				table := &check.Table{
+					Headers: jf.Table.Headers,
+					Key:     jf.Table.Key,
+					Rows:    make([]check.TableRow, 0, len(jf.Table.Rows)),
+				}
+				for _, row := range jf.Table.Rows {
+					severity, err := decodeSeverity(row.Severity)
+					if err != nil {
+						return runInfo{}, nil, fmt.Errorf("report %s, result %s: %w", jr.CheckID, jf.ID, err)
+					}
+					tableRow := check.TableRow{Cells: row.Cells, Severity: severity}
+					if row.FirstSeen != "" {
+						firstSeen, err := time.Parse(time.RFC3339, row.FirstSeen)
+						if err != nil {
+							return runInfo{}, nil, fmt.Errorf("report %s, result %s: parsing row first_seen: %w", jr.CheckID, jf.ID, err)
+						}
+						tableRow.FirstSeen = firstSeen
+					}
+					table.Rows = append(table.Rows, tableRow)
+				}
+				finding.Table = table
+			}
+
+			report.Results = append(report.Results, finding)
+		}
+
+		reports = append(reports, report)
+	}
+
+	return run, reports, nil
+}
+
+// decodeSeverity is the inverse of Severity.String, accepting every severity
+// the JSON output can contain.
+func decodeSeverity(s string) (check.Severity, error) {
+	for _, severity := range []check.Severity{check.SeverityNotApplicable, check.SeveritySkip, check.SeverityOK, check.SeverityWarn, check.SeverityFail} {
+		if s == severity.String() {
+			return severity, nil
+		}
+	}
+	return check.SeverityOK, fmt.Errorf("unknown severity %q", s)
+}
+
+// nonNil keeps empty slices serialized as [] rather than null, as schema.json
+// requires.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
 }
@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/emancu/pgdoctor"
+	"github.com/emancu/pgdoctor/check"
+)
+
+// JUnit XML as Jenkins, GitLab, and most CI test report parsers read it: a
+// testsuite per category and a testcase per check. Failing checks carry a
+// <failure>, skipped and not applicable checks a <skipped>, and panicked and
+// errored checks an <error>; warnings pass. Every testcase has the check's text
+// output at the verbose level in <system-out>, so the evidence travels with
+// the result.
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Errors    int         `xml:"errors,attr"`
+	Skipped   int         `xml:"skipped,attr"`
+	Time      string      `xml:"time,attr"`
+	Timestamp string      `xml:"timestamp,attr,omitempty"`
+	Hostname  string      `xml:"hostname,attr,omitempty"`
+	Cases     []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// ansiEscape matches the color codes of text output, which have no place in
+// XML.
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// formatJUnit writes the reports as a JUnit XML document. encoding/xml
+// escapes markup in details and cells and replaces characters XML cannot
+// hold.
+func formatJUnit(w io.Writer, run runInfo, reports []*check.Report) error {
+	doc := junitSuites{Name: "pgdoctor", Time: junitSeconds(run.duration)}
+
+	// Suites follow the order categories first appear in, which is the
+	// --sort order of their reports.
+	index := map[check.Category]int{}
+	var durations []time.Duration
+	for _, r := range reports {
+		i, ok := index[r.Category]
+		if !ok {
+			i = len(doc.Suites)
+			index[r.Category] = i
+			suite := junitSuite{Name: string(r.Category), Hostname: run.database}
+			if !run.startedAt.IsZero() {
+				suite.Timestamp = run.startedAt.UTC().Format("2006-01-02T15:04:05")
+			}
+			doc.Suites = append(doc.Suites, suite)
+			durations = append(durations, 0)
+		}
+		suite := &doc.Suites[i]
+		c := junitTestCase(r)
+		suite.Cases = append(suite.Cases, c)
+		suite.Tests++
+		durations[i] += r.Duration
+		switch {
+		case c.Error != nil:
+			suite.Errors++
+		case c.Failure != nil:
+			suite.Failures++
+		case c.Skipped != nil:
+			suite.Skipped++
+		}
+	}
+	for i := range doc.Suites {
+		s := &doc.Suites[i]
+		s.Time = junitSeconds(durations[i])
+		doc.Tests += s.Tests
+		doc.Failures += s.Failures
+		doc.Errors += s.Errors
+		doc.Skipped += s.Skipped
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("writing JUnit XML: %w", err)
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("encoding JUnit XML: %w", err)
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return fmt.Errorf("writing JUnit XML: %w", err)
+	}
+	return nil
+}
+
+func junitTestCase(r *check.Report) junitCase {
+	name := r.CheckID
+	if r.Database != "" {
+		name += " [" + r.Database + "]"
+	}
+	c := junitCase{
+		ClassName: "pgdoctor." + string(r.Category),
+		Name:      name,
+		Time:      junitSeconds(r.Duration),
+		SystemOut: junitSystemOut(r),
+	}
+
+	switch {
+	case pgdoctor.Panicked(r):
+		c.Error = &junitProblem{Message: "check panicked", Type: "panic", Text: r.Results[0].Details}
+	case pgdoctor.Errored(r):
+		c.Error = &junitProblem{Message: "check errored", Type: "error", Text: r.Results[0].Details}
+	case r.Severity == check.SeverityFail:
+		var names, details []string
+		for _, f := range r.Results {
+			if f.Severity != check.SeverityFail {
+				continue
+			}
+			names = append(names, f.Name)
+			if f.Details != "" {
+				details = append(details, f.Name+": "+f.Details)
+			}
+		}
+		c.Failure = &junitProblem{
+			Message: fmt.Sprintf("%d finding(s) failed: %s", len(names), strings.Join(names, ", ")),
+			Type:    r.Severity.String(),
+			Text:    strings.Join(details, "\n"),
+		}
+	case r.Severity < check.SeverityOK:
+		var reason string
+		if len(r.Results) > 0 {
+			reason = r.Results[0].Details
+		}
+		c.Skipped = &junitSkipped{Message: reason}
+	}
+	return c
+}
+
+// junitSystemOut renders a report as text output does at the verbose level,
+// without colors or a width limit.
+func junitSystemOut(r *check.Report) string {
+	var buf bytes.Buffer
+	printCheckReport(&buf, r, &runOptions{detail: string(detailVerbose)})
+	return ansiEscape.ReplaceAllString(buf.String(), "")
+}
+
+// junitSeconds formats d as the decimal seconds of JUnit time attributes.
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
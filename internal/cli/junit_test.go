@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emancu/pgdoctor"
+	"github.com/emancu/pgdoctor/check"
+)
+
+func decodeJUnit(t *testing.T, data []byte) junitSuites {
+	t.Helper()
+	require.True(t, bytes.HasPrefix(data, []byte(xml.Header)), "starts with the XML declaration")
+	var doc junitSuites
+	require.NoError(t, xml.Unmarshal(data, &doc))
+	return doc
+}
+
+func TestFormatJUnit(t *testing.T) {
+	t.Parallel()
+
+	reports := sampleReports()
+	reports[1].Duration = 1500 * time.Millisecond
+	reports[1].Results[1].Table.Rows[0].Cells[0] = `public."<events>" & co`
+
+	var buf bytes.Buffer
+	require.NoError(t, formatJUnit(&buf, sampleRun(), reports))
+	doc := decodeJUnit(t, buf.Bytes())
+
+	assert.Equal(t, 5, doc.Tests)
+	assert.Equal(t, 1, doc.Failures)
+	assert.Equal(t, 2, doc.Skipped, "skipped and not applicable checks")
+	assert.Equal(t, 0, doc.Errors)
+	assert.Equal(t, "1.500", doc.Time)
+
+	require.Len(t, doc.Suites, 4, "one suite per category")
+	assert.Equal(t, "configs", doc.Suites[0].Name)
+	assert.Equal(t, "2026-10-14T09:30:00", doc.Suites[0].Timestamp)
+	assert.Equal(t, "app", doc.Suites[0].Hostname)
+
+	vacuum := doc.Suites[1]
+	require.Equal(t, "vacuum", vacuum.Name)
+	assert.Equal(t, "1.500", vacuum.Time)
+	bloat := vacuum.Cases[0]
+	assert.Equal(t, "pgdoctor.vacuum", bloat.ClassName)
+	assert.Equal(t, "table-bloat", bloat.Name)
+	assert.Equal(t, "1.500", bloat.Time)
+	require.NotNil(t, bloat.Failure)
+	assert.Equal(t, "1 finding(s) failed: Bloat", bloat.Failure.Message)
+	assert.Equal(t, "Bloat: 1 table", bloat.Failure.Text)
+	assert.Contains(t, bloat.SystemOut, "[FAIL] Table Bloat (table-bloat)")
+	assert.Contains(t, bloat.SystemOut, `public."<events>" & co`, "cells round-trip through the escaping")
+	assert.NotContains(t, bloat.SystemOut, "\x1b[", "no color codes")
+
+	assert.Contains(t, buf.String(), `public.&#34;&lt;events&gt;&#34; &amp; co`)
+
+	skipped := doc.Suites[3].Cases[0]
+	require.Equal(t, "temp-usage", skipped.Name)
+	require.NotNil(t, skipped.Skipped)
+	assert.Equal(t, "query cancelled by statement_timeout", skipped.Skipped.Message)
+	assert.Nil(t, skipped.Failure)
+
+	pass := doc.Suites[0].Cases[0]
+	assert.Nil(t, pass.Failure)
+	assert.Nil(t, pass.Skipped)
+	assert.Contains(t, pass.SystemOut, "[PASS] PostgreSQL Version")
+}
+
+func TestFormatJUnit_PanicAndDatabase(t *testing.T) {
+	t.Parallel()
+
+	report := check.NewReport(check.Metadata{CheckID: "demo", Name: "Demo", Category: check.CategoryConfigs})
+	report.Severity = check.SeveritySkip
+	report.Database = "orders"
+	report.AddFinding(check.Finding{ID: pgdoctor.PanicFindingID, Name: "Check Panicked", Severity: check.SeveritySkip, Details: "runtime error: index out of range"})
+
+	var buf bytes.Buffer
+	require.NoError(t, formatJUnit(&buf, runInfo{}, []*check.Report{report}))
+	doc := decodeJUnit(t, buf.Bytes())
+
+	assert.Equal(t, 1, doc.Errors)
+	assert.Equal(t, 0, doc.Skipped)
+	c := doc.Suites[0].Cases[0]
+	assert.Equal(t, "demo [orders]", c.Name)
+	require.NotNil(t, c.Error)
+	assert.Equal(t, "runtime error: index out of range", c.Error.Text)
+	assert.Empty(t, doc.Suites[0].Timestamp, "zero start time is left out")
+}
+
+func TestFormatJUnit_Errored(t *testing.T) {
+	t.Parallel()
+
+	report := check.NewReport(check.Metadata{CheckID: "demo", Name: "Demo", Category: check.CategoryConfigs})
+	report.Severity = check.SeveritySkip
+	report.Errored = true
+	report.AddFinding(check.Finding{ID: pgdoctor.ErrorFindingID, Name: "Check Error", Severity: check.SeveritySkip, Details: "permission denied"})
+
+	var buf bytes.Buffer
+	require.NoError(t, formatJUnit(&buf, runInfo{}, []*check.Report{report}))
+	doc := decodeJUnit(t, buf.Bytes())
+
+	assert.Equal(t, 1, doc.Errors)
+	assert.Equal(t, 0, doc.Skipped)
+	c := doc.Suites[0].Cases[0]
+	require.NotNil(t, c.Error)
+	assert.Equal(t, "check errored", c.Error.Message)
+	assert.Equal(t, "permission denied", c.Error.Text)
+}
+
+func TestFormatJUnit_InvalidCharacters(t *testing.T) {
+	t.Parallel()
+
+	report := check.NewReport(check.Metadata{CheckID: "demo", Name: "Demo", Category: check.CategoryConfigs})
+	report.AddFinding(check.Finding{ID: "demo", Name: "Demo", Severity: check.SeverityFail, Details: "bad byte \x00 here"})
+
+	var buf bytes.Buffer
+	require.NoError(t, formatJUnit(&buf, runInfo{}, []*check.Report{report}))
+	doc := decodeJUnit(t, buf.Bytes())
+	assert.Equal(t, "Demo: bad byte � here", doc.Suites[0].Cases[0].Failure.Text)
+}
+
+func TestRenderCommand_JUnit(t *testing.T) {
+	t.Parallel()
+
+	var saved bytes.Buffer
+	require.NoError(t, formatJSON(&saved, sampleRun(), sampleReports()))
+
+	var out bytes.Buffer
+	cmd := newRootCommand("test")
+	cmd.SetIn(bytes.NewReader(saved.Bytes()))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"render", "--input", "-", "--output", "junit"})
+	require.NoError(t, cmd.Execute())
+	assert.True(t, strings.HasSuffix(out.String(), "</testsuites>\n"))
+	assert.Equal(t, 5, decodeJUnit(t, out.Bytes()).Tests)
+}
@@ -2,24 +2,28 @@ package cli
 
 import (
 	"fmt"
+	"os"
 	"sort"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
-	"github.com/emancu/pgdoctor"
 	"github.com/emancu/pgdoctor/check"
 )
 
 func newListCommand() *cobra.Command {
 	var categories []string
+	var checksDir string
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all available checks",
 		Long:  `List all available pgdoctor checks organized by category.`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			checks := pgdoctor.AllChecks()
+			checks, err := availableChecks(checksDir)
+			if err != nil {
+				return err
+			}
 
 			grouped := map[string][]check.Metadata{}
 			for _, pkg := range checks {
@@ -76,6 +80,7 @@ func newListCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringSliceVar(&categories, "category", nil, "Filter by category")
+	cmd.Flags().StringVar(&checksDir, "checks-dir", os.Getenv(checksDirEnv), "Directory of external SQL-only checks (env: "+checksDirEnv+")")
 
 	return cmd
 }
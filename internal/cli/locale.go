@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/emancu/pgdoctor/check"
+)
+
+// localeDefault keeps the separators pgdoctor has always used.
+const localeDefault = "en"
+
+// locales maps --locale languages to the separators of formatted sizes and
+// counts. Suffixes (KiB, M) stay the same in every locale.
+var locales = map[string]check.NumberFormat{
+	"en": {},
+	"de": {Decimal: ",", Grouping: "."},
+	"es": {Decimal: ",", Grouping: "."},
+	"fr": {Decimal: ",", Grouping: "\u202f"}, // narrow no-break space
+	"it": {Decimal: ",", Grouping: "."},
+	"nl": {Decimal: ",", Grouping: "."},
+	"pt": {Decimal: ",", Grouping: "."},
+}
+
+// parseLocale resolves --locale to a NumberFormat. The language is all that
+// matters, so "de", "de-AT", and "de_DE.UTF-8" are the same.
+func parseLocale(value string) (check.NumberFormat, error) {
+	lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(value)), ".")
+	lang, _, _ = strings.Cut(lang, "_")
+	lang, _, _ = strings.Cut(lang, "-")
+	if f, ok := locales[lang]; ok {
+		return f, nil
+	}
+	valid := make([]string, 0, len(locales))
+	for l := range locales {
+		valid = append(valid, l)
+	}
+	sort.Strings(valid)
+	return check.NumberFormat{}, fmt.Errorf("unknown --locale %q (valid: %s)", value, strings.Join(valid, ", "))
+}
@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emancu/pgdoctor/check"
+)
+
+func TestParseLocale(t *testing.T) {
+	t.Parallel()
+
+	for _, value := range []string{"de", "DE", "de-AT", "de_DE.UTF-8"} {
+		f, err := parseLocale(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, check.NumberFormat{Decimal: ",", Grouping: "."}, f, value)
+	}
+
+	f, err := parseLocale(localeDefault)
+	require.NoError(t, err)
+	assert.Equal(t, check.NumberFormat{}, f, "the default keeps today's output")
+
+	_, err = parseLocale("tlh")
+	require.ErrorContains(t, err, `unknown --locale "tlh" (valid: de, en, es, fr, it, nl, pt)`)
+}
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/emancu/pgdoctor/check"
+)
+
+// formatOpenMetrics writes the reports in the OpenMetrics text format, for
+// node_exporter's textfile collector and other scrapers. Severities are
+// gauges holding the check.Severity value: 2 fail, 1 warn, 0 pass, -1 skip,
+// -2 not applicable, with pgdoctor_check_errored telling errored checks from
+// skipped ones. Samples carry no timestamps, which the textfile collector
+// rejects; the run's start time is its own gauge instead. The run's --tag
+// pairs label every sample.
+func formatOpenMetrics(w io.Writer, run runInfo, reports []*check.Report) error {
+	bw := bufio.NewWriter(w)
+	tags := tagLabels(run.tags)
+
+	// An info family would suit this best, but the Prometheus text parser,
+	// which node_exporter's textfile collector uses, rejects its type; a
+	// gauge of 1 is the usual stand-in.
+	family(bw, "pgdoctor_run_info", "gauge", "", "The pgdoctor run these metrics come from; always 1.")
+	sample(bw, "pgdoctor_run_info", append([]string{
+		"version", run.version,
+		"server_version", run.serverVersion,
+		"database", run.database,
+	}, tags...), 1)
+
+	family(bw, "pgdoctor_run_timestamp_seconds", "gauge", "seconds", "When the first check started, in Unix time.")
+	sample(bw, "pgdoctor_run_timestamp_seconds", tags, float64(run.startedAt.UnixMilli())/1000)
+
+	family(bw, "pgdoctor_run_duration_seconds", "gauge", "seconds", "How long the run took.")
+	sample(bw, "pgdoctor_run_duration_seconds", tags, run.duration.Seconds())
+
+	family(bw, "pgdoctor_check_severity", "gauge", "", "Severity of each check: 2 fail, 1 warn, 0 pass, -1 skip, -2 not applicable.")
+	for _, r := range reports {
+		sample(bw, "pgdoctor_check_severity", append(reportLabels(r, "category", string(r.Category)), tags...), float64(r.Severity))
+	}
+
+	family(bw, "pgdoctor_check_errored", "gauge", "", "1 for a check that returned an error instead of findings, whose severity is -1 skip; 0 otherwise.")
+	for _, r := range reports {
+		errored := 0.0
+		if r.Errored {
+			errored = 1
+		}
+		sample(bw, "pgdoctor_check_errored", append(reportLabels(r), tags...), errored)
+	}
+
+	family(bw, "pgdoctor_check_duration_seconds", "gauge", "seconds", "How long each check took.")
+	for _, r := range reports {
+		sample(bw, "pgdoctor_check_duration_seconds", append(reportLabels(r), tags...), r.Duration.Seconds())
+	}
+
+	family(bw, "pgdoctor_finding_severity", "gauge", "", "Severity of each finding, with the same values as pgdoctor_check_severity.")
+	for _, r := range reports {
+		// A label set may appear once; checks that repeat a finding ID
+		// report its worst severity.
+		worst := map[string]check.Severity{}
+		var ids []string
+		for _, f := range r.Results {
+			s, seen := worst[f.ID]
+			if !seen {
+				ids = append(ids, f.ID)
+			}
+			if !seen || f.Severity > s {
+				worst[f.ID] = f.Severity
+			}
+		}
+		for _, id := range ids {
+			sample(bw, "pgdoctor_finding_severity", append(reportLabels(r, "finding", id), tags...), float64(worst[id]))
+		}
+	}
+
+	fmt.Fprintln(bw, "# EOF")
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("writing OpenMetrics: %w", err)
+	}
+	return nil
+}
+
+// reportLabels are the labels identifying a report, followed by extra
+// name/value pairs. The database label is only set in --all-databases runs.
+func reportLabels(r *check.Report, extra ...string) []string {
+	labels := []string{"check", r.CheckID}
+	if r.Database != "" {
+		labels = append(labels, "database", r.Database)
+	}
+	return append(labels, extra...)
+}
+
+// family writes the metadata lines that start a metric family.
+func family(w io.Writer, name, typ, unit, help string) {
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, typ)
+	if unit != "" {
+		fmt.Fprintf(w, "# UNIT %s %s\n", name, unit)
+	}
+	fmt.Fprintf(w, "# HELP %s %s\n", name, escapeOpenMetrics(help, false))
+}
+
+// sample writes one sample line; labels are name/value pairs.
+func sample(w io.Writer, name string, labels []string, value float64) {
+	fmt.Fprint(w, name)
+	if len(labels) > 0 {
+		pairs := make([]string, 0, len(labels)/2)
+		for i := 0; i+1 < len(labels); i += 2 {
+			pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", labels[i], escapeOpenMetrics(labels[i+1], true)))
+		}
+		fmt.Fprintf(w, "{%s}", strings.Join(pairs, ","))
+	}
+	fmt.Fprintf(w, " %s\n", strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+// escapeOpenMetrics escapes backslashes and newlines, and in label values
+// double quotes, as the text format requires.
+func escapeOpenMetrics(s string, labelValue bool) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	if labelValue {
+		s = strings.ReplaceAll(s, `"`, `\"`)
+	}
+	return s
+}
@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emancu/pgdoctor/check"
+)
+
+// parseOpenMetrics parses text with the Prometheus text parser, which
+// node_exporter's textfile collector uses, and returns each sample's value by
+// its name and labels as written, such as `name{a="1",b="2"}`. It also checks
+// what the parser lets through: the text ends with "# EOF", every family has
+// help, and label sets are unique.
+func parseOpenMetrics(t *testing.T, text string) map[string]float64 {
+	t.Helper()
+
+	require.True(t, strings.HasSuffix(text, "\n# EOF\n"), "ends with # EOF")
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(strings.NewReader(text))
+	require.NoError(t, err)
+
+	samples := map[string]float64{}
+	for name, family := range families {
+		require.NotEmpty(t, family.GetHelp(), "family %s has help", name)
+		require.Equal(t, dto.MetricType_GAUGE, family.GetType(), "family %s is a gauge", name)
+		for _, metric := range family.GetMetric() {
+			key := name
+			if len(metric.GetLabel()) > 0 {
+				pairs := make([]string, 0, len(metric.GetLabel()))
+				for _, label := range metric.GetLabel() {
+					pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", label.GetName(), escapeOpenMetrics(label.GetValue(), true)))
+				}
+				key += "{" + strings.Join(pairs, ",") + "}"
+			}
+			_, dup := samples[key]
+			require.False(t, dup, "duplicate sample %s", key)
+			samples[key] = metric.GetGauge().GetValue()
+		}
+	}
+	return samples
+}
+
+func TestFormatOpenMetrics(t *testing.T) {
+	t.Parallel()
+
+	reports := sampleReports()
+	reports[1].Duration = 1500_000_000 // 1.5s
+	reports[1].Database = `we"ird\db`
+
+	var buf bytes.Buffer
+	require.NoError(t, formatOpenMetrics(&buf, sampleRun(), reports))
+	samples := parseOpenMetrics(t, buf.String())
+
+	assert.Equal(t, 1.0, samples[`pgdoctor_run_info{version="v0.4.0",server_version="16.4",database="app"}`])
+	assert.Equal(t, float64(sampleRun().startedAt.Unix()), samples["pgdoctor_run_timestamp_seconds"])
+	assert.Equal(t, 1.5, samples["pgdoctor_run_duration_seconds"])
+
+	assert.Equal(t, 0.0, samples[`pgdoctor_check_severity{check="pg-version",category="configs"}`])
+	assert.Equal(t, 2.0, samples[`pgdoctor_check_severity{check="table-bloat",database="we\"ird\\db",category="vacuum"}`])
+	assert.Equal(t, -1.0, samples[`pgdoctor_check_severity{check="temp-usage",category="performance"}`])
+	assert.Equal(t, -2.0, samples[`pgdoctor_check_severity{check="statements-reset",category="configs"}`])
+	assert.Equal(t, 1.5, samples[`pgdoctor_check_duration_seconds{check="table-bloat",database="we\"ird\\db"}`])
+
+	assert.Equal(t, 1.0, samples[`pgdoctor_finding_severity{check="table-bloat",database="we\"ird\\db",finding="dead-tuples"}`])
+	assert.Equal(t, 2.0, samples[`pgdoctor_finding_severity{check="table-bloat",database="we\"ird\\db",finding="bloat"}`])
+}
+
+func TestFormatOpenMetrics_RepeatedFindingIDs(t *testing.T) {
+	t.Parallel()
+
+	report := check.NewReport(check.Metadata{CheckID: "demo", Category: check.CategoryConfigs})
+	report.AddFinding(check.Finding{ID: "setting", Severity: check.SeverityOK})
+	report.AddFinding(check.Finding{ID: "setting", Severity: check.SeverityWarn})
+	report.AddFinding(check.Finding{ID: "setting", Severity: check.SeverityOK})
+
+	var buf bytes.Buffer
+	require.NoError(t, formatOpenMetrics(&buf, sampleRun(), []*check.Report{report}))
+	samples := parseOpenMetrics(t, buf.String())
+	assert.Equal(t, 1.0, samples[`pgdoctor_finding_severity{check="demo",finding="setting"}`], "worst severity wins")
+}
+
+func TestFormatOpenMetrics_Errored(t *testing.T) {
+	t.Parallel()
+
+	errored := check.NewReport(check.Metadata{CheckID: "freeze-age", Category: check.CategoryVacuum})
+	errored.Severity = check.SeveritySkip
+	errored.Errored = true
+	skipped := check.NewReport(check.Metadata{CheckID: "index-bloat", Category: check.CategoryIndexes})
+	skipped.Severity = check.SeveritySkip
+
+	var buf bytes.Buffer
+	require.NoError(t, formatOpenMetrics(&buf, sampleRun(), []*check.Report{errored, skipped}))
+	samples := parseOpenMetrics(t, buf.String())
+	assert.Equal(t, 1.0, samples[`pgdoctor_check_errored{check="freeze-age"}`])
+	assert.Equal(t, 0.0, samples[`pgdoctor_check_errored{check="index-bloat"}`], "a skipped check did not error")
+	assert.Equal(t, samples[`pgdoctor_check_severity{check="freeze-age",category="vacuum"}`], samples[`pgdoctor_check_severity{check="index-bloat",category="indexes"}`])
+}
+
+func TestFormatOpenMetrics_NoReports(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, formatOpenMetrics(&buf, runInfo{}, nil))
+	samples := parseOpenMetrics(t, buf.String())
+	assert.Len(t, samples, 3, "only the run-level samples")
+}
+
+func TestRenderCommand_OpenMetrics(t *testing.T) {
+	t.Parallel()
+
+	var saved bytes.Buffer
+	require.NoError(t, formatJSON(&saved, sampleRun(), sampleReports()))
+
+	var out bytes.Buffer
+	cmd := newRootCommand("test")
+	cmd.SetIn(bytes.NewReader(saved.Bytes()))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"render", "--input", "-", "--output", "openmetrics"})
+	require.NoError(t, cmd.Execute())
+
+	samples := parseOpenMetrics(t, out.String())
+	assert.Equal(t, 2.0, samples[`pgdoctor_check_severity{check="table-bloat",category="vacuum"}`])
+}
+
+func TestRenderCommand_UnknownOutput(t *testing.T) {
+	t.Parallel()
+
+	cmd := newRootCommand("test")
+	cmd.SetArgs([]string{"render", "--input", "-", "--output", "prometheus"})
+	var silent *SilentError
+	require.ErrorAs(t, cmd.Execute(), &silent)
+	assert.Equal(t, 1, silent.ExitCode)
+}
@@ -3,12 +3,18 @@ package cli
 import (
 	"fmt"
 	"io"
+	"os"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/fatih/color"
+	"golang.org/x/term"
 
+	"github.com/emancu/pgdoctor"
 	"github.com/emancu/pgdoctor/check"
 )
 
@@ -16,8 +22,132 @@ func showTiming(opts *runOptions) bool {
 	return opts.detail == string(detailVerbose) || opts.detail == string(detailDebug)
 }
 
+// groupsByCategory reports whether the output is ordered, and so can be
+// grouped, by category. An unset order is the default category order.
+func (o *runOptions) groupsByCategory() bool {
+	return o.sortBy == "" || o.sortBy == string(sortCategory)
+}
+
+// textPrinter streams reports as text. In category order it prints a category
+// header whenever the category changes; other orders print a flat list, and
+// --collapse-passing folds all passing checks into one count at the end. With
+// --all-databases it also prints a database header whenever the database
+// changes, restarting the category headers below it.
+type textPrinter struct {
+	w               io.Writer
+	opts            *runOptions
+	currentCategory check.Category
+	started         bool
+	currentDatabase string
+	databaseStarted bool
+	passing         int // passing checks held back by --collapse-passing
+}
+
+func (p *textPrinter) print(r *check.Report) {
+	if p.opts.compact {
+		p.printCompact(r)
+		return
+	}
+
+	if p.opts.allDatabases && (!p.databaseStarted || r.Database != p.currentDatabase) {
+		p.flush()
+		if p.databaseStarted {
+			fmt.Fprintln(p.w)
+		}
+		printDatabaseHeader(p.w, r.Database)
+		p.currentDatabase = r.Database
+		p.databaseStarted = true
+		p.started = false
+	}
+
+	if p.opts.groupsByCategory() && (!p.started || r.Category != p.currentCategory) {
+		p.flush()
+		if p.started {
+			fmt.Fprintln(p.w)
+		}
+		printCategoryHeader(p.w, r.Category, p.opts)
+		p.currentCategory = r.Category
+		p.started = true
+	}
+
+	if r.Severity == check.SeverityOK {
+		if p.opts.hidePassing {
+			return
+		}
+		if p.opts.collapse {
+			p.passing++
+			return
+		}
+	}
+
+	if p.opts.detail == string(detailSummary) {
+		printCheckSummary(p.w, r, p.opts)
+	} else {
+		printCheckReport(p.w, r, p.opts)
+	}
+}
+
+// printCompact prints r as one line, without category or database headers;
+// --all-databases names the database in the line instead.
+func (p *textPrinter) printCompact(r *check.Report) {
+	if r.Severity == check.SeverityOK {
+		if p.opts.hidePassing {
+			return
+		}
+		if p.opts.collapse {
+			p.passing++
+			return
+		}
+	}
+	printCheckCompact(p.w, r, p.opts)
+}
+
+// printAll prints finished reports in --sort order, below the --top list when
+// one is asked for.
+func (p *textPrinter) printAll(reports []*check.Report) {
+	sortReports(reports, sortOrder(p.opts.sortBy))
+	if p.opts.top > 0 {
+		printTopFindings(p.w, topFindings(reports, p.opts.top), p.opts.glyphs)
+	}
+	for _, r := range reports {
+		p.print(r)
+	}
+}
+
+// flush prints the collapsed passing count for the current category, if any.
+func (p *textPrinter) flush() {
+	if p.passing == 0 {
+		return
+	}
+	badge := severityBadge(check.SeverityOK, p.opts.glyphs)
+	fmt.Fprintf(p.w, "%s %s\n", badge, dimColor()(fmt.Sprintf("%d passing check(s)", p.passing)))
+	p.passing = 0
+}
+
+// printDatabaseHeader starts the reports of one database in an
+// --all-databases run. Cluster-wide checks have no database.
+func printDatabaseHeader(w io.Writer, database string) {
+	title := "CLUSTER-WIDE"
+	if database != "" {
+		title = "DATABASE " + database
+	}
+	fmt.Fprintln(w, title)
+	fmt.Fprintln(w, strings.Repeat("━", utf8.RuneCountInString(title)))
+}
+
+func printCategoryHeader(w io.Writer, category check.Category, opts *runOptions) {
+	title := strings.ToUpper(string(category))
+	rule := strings.Repeat("─", len(title))
+	if opts.groupBy == string(groupByCategory) {
+		colorFunc := colorForCategory(category)
+		title, rule = colorFunc(title), colorFunc(rule)
+	}
+	fmt.Fprintln(w, title)
+	fmt.Fprintln(w, rule)
+}
+
 func printCheckSummary(w io.Writer, report *check.Report, opts *runOptions) {
-	label, colorFunc := severityDisplay(report.Severity)
+	badge := severityBadge(report.Severity, opts.glyphs)
 	dimFunc := dimColor()
 
 	var timingStr string
@@ -25,10 +155,11 @@ func printCheckSummary(w io.Writer, report *check.Report, opts *runOptions) {
 		timingStr = " " + dimFunc(fmt.Sprintf("[%s]", check.FormatDurationMs(float64(report.Duration.Milliseconds()))))
 	}
 
-	// For skipped checks, show the reason inline instead of pass/total count
-	if report.Severity == check.SeveritySkip && len(report.Results) > 0 {
+	// For skipped and not applicable checks, show the reason inline instead
+	// of pass/total count
+	if report.Severity < check.SeverityOK && len(report.Results) > 0 {
 		fmt.Fprintf(w, "%s %s %s%s — %s\n",
-			colorFunc(fmt.Sprintf("[%s]", label)),
+			badge,
 			report.Name,
 			dimFunc(fmt.Sprintf("(%s)", report.CheckID)),
 			timingStr,
@@ -36,24 +167,86 @@ func printCheckSummary(w io.Writer, report *check.Report, opts *runOptions) {
 		return
 	}
 
-	okCount := 0
+	okCount, total := 0, 0
 	for _, result := range report.Results {
+		if result.Severity == check.SeverityNotApplicable {
+			continue
+		}
+		total++
 		if result.Severity == check.SeverityOK {
 			okCount++
 		}
 	}
-	total := len(report.Results)
 
 	fmt.Fprintf(w, "%s %s %s %s%s\n",
-		colorFunc(fmt.Sprintf("[%s]", label)),
+		badge,
 		report.Name,
 		dimFunc(fmt.Sprintf("(%s)", report.CheckID)),
 		dimFunc(fmt.Sprintf("(%d/%d)", okCount, total)),
 		timingStr)
 }
 
+// printCheckCompact prints a report as "[FAIL] table-bloat: <summary>", the
+// summary being the first sentence of its most severe finding's details.
+func printCheckCompact(w io.Writer, report *check.Report, opts *runOptions) {
+	badge := severityBadge(report.Severity, opts.glyphs)
+
+	id := report.CheckID
+	if report.Database != "" {
+		id = report.Database + "/" + id
+	}
+
+	var summary string
+	if top, others := topFinding(report); top != nil {
+		summary = firstSentence(top.Details)
+		if others > 0 {
+			summary += fmt.Sprintf(" (+%d more)", others)
+		}
+	}
+	line := id
+	if summary != "" {
+		line += ": " + summary
+	}
+	if opts.width > 0 {
+		line = ellipsize(line, max(opts.width-badgeWidth(report.Severity, opts.glyphs)-1, minColumnWidth))
+	}
+	fmt.Fprintf(w, "%s %s\n", badge, line)
+}
+
+// topFinding returns the first of a report's most severe findings, and how
+// many other findings are WARN or FAIL.
+func topFinding(report *check.Report) (*check.Finding, int) {
+	var top *check.Finding
+	flagged := 0
+	for i := range report.Results {
+		f := &report.Results[i]
+		if f.Severity >= check.SeverityWarn {
+			flagged++
+		}
+		if top == nil || f.Severity > top.Severity {
+			top = f
+		}
+	}
+	if top == nil {
+		return nil, 0
+	}
+	if top.Severity >= check.SeverityWarn {
+		flagged--
+	}
+	return top, flagged
+}
+
+// firstSentence cuts details at the end of its first line or sentence.
+func firstSentence(details string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(details), "\n")
+	if sentence, _, ok := strings.Cut(line, ". "); ok {
+		return sentence
+	}
+	return strings.TrimSuffix(line, ".")
+}
+
 func printCheckReport(w io.Writer, report *check.Report, opts *runOptions) {
-	label, colorFunc := severityDisplay(report.Severity)
+	badge := severityBadge(report.Severity, opts.glyphs)
 	dimFunc := dimColor()
 
 	var timingStr string
@@ -61,14 +254,21 @@ func printCheckReport(w io.Writer, report *check.Report, opts *runOptions) {
 		timingStr = " " + dimFunc(fmt.Sprintf("[%s]", check.FormatDurationMs(float64(report.Duration.Milliseconds()))))
 	}
 
-	// Skipped checks render as a single line with the reason, same as summary mode
-	if report.Severity == check.SeveritySkip && len(report.Results) > 0 {
+	// Skipped and not applicable checks render as a single line with the
+	// reason, same as summary mode, plus the stack trace of a panic at the
+	// debug level
+	if report.Severity < check.SeverityOK && len(report.Results) > 0 {
 		fmt.Fprintf(w, "%s %s %s%s — %s\n",
-			colorFunc(fmt.Sprintf("[%s]", label)),
+			badge,
 			report.Name,
 			dimFunc(fmt.Sprintf("(%s)", report.CheckID)),
 			timingStr,
 			dimFunc(report.Results[0].Details))
+		if opts.detail == string(detailDebug) && report.Results[0].Debug != "" {
+			fmt.Fprintln(w)
+			fmt.Fprintln(w, "  Debug:")
+			fmt.Fprintf(w, "%s\n", indent(report.Results[0].Debug, 4))
+		}
 		return
 	}
 
@@ -78,13 +278,22 @@ func printCheckReport(w io.Writer, report *check.Report, opts *runOptions) {
 	if singleFinding {
 		result := report.Results[0]
 		fmt.Fprintf(w, "%s %s %s%s\n",
-			colorFunc(fmt.Sprintf("[%s]", label)),
+			badge,
 			report.Name,
 			dimFunc(fmt.Sprintf("(%s)", report.CheckID)),
 			timingStr)
 		if result.Severity != check.SeverityOK && result.Details != "" {
 			fmt.Fprintf(w, "%s\n", indent(result.Details, 2))
 		}
+		if seen := firstSeenLabel(result, opts.startedAt); seen != "" {
+			fmt.Fprintf(w, "%s\n", indent(dimFunc(seen), 2))
+		}
+		if why := reasonLabel(result, opts); why != "" {
+			fmt.Fprintf(w, "%s\n", indent(dimFunc(why), 2))
+		}
+		if seeAlso := seeAlsoLabel(result); seeAlso != "" {
+			fmt.Fprintf(w, "%s\n", indent(dimFunc(seeAlso), 2))
+		}
 		if result.Table != nil {
 			fmt.Fprintln(w)
 			printTable(w, result.Table, 2, opts)
@@ -96,7 +305,7 @@ func printCheckReport(w io.Writer, report *check.Report, opts *runOptions) {
 		}
 	} else {
 		fmt.Fprintf(w, "%s %s %s%s\n",
-			colorFunc(fmt.Sprintf("[%s]", label)),
+			badge,
 			report.Name,
 			dimFunc(fmt.Sprintf("(%s)", report.CheckID)),
 			timingStr)
@@ -123,7 +332,7 @@ func printCheckReport(w io.Writer, report *check.Report, opts *runOptions) {
 }
 
 func printSubcheck(w io.Writer, report *check.Report, result check.Finding, opts *runOptions) {
-	label, colorFunc := severityDisplay(result.Severity)
+	badge := severityBadge(result.Severity, opts.glyphs)
 	dimFunc := dimColor()
 
 	fullID := report.CheckID
@@ -131,14 +340,33 @@ func printSubcheck(w io.Writer, report *check.Report, result check.Finding, opts
 		fullID = report.CheckID + "/" + result.ID
 	}
 
+	// A not applicable finding has nothing to show beyond its reason.
+	if result.Severity == check.SeverityNotApplicable {
+		fmt.Fprintf(w, "%s %s %s — %s\n",
+			badge,
+			result.Name,
+			dimFunc(fmt.Sprintf("(%s)", fullID)),
+			dimFunc(result.Details))
+		return
+	}
+
 	fmt.Fprintf(w, "%s %s %s\n",
-		colorFunc(fmt.Sprintf("[%s]", label)),
+		badge,
 		result.Name,
 		dimFunc(fmt.Sprintf("(%s)", fullID)))
 
 	if result.Severity != check.SeverityOK && result.Details != "" {
 		fmt.Fprintf(w, "%s\n", indent(result.Details, 2))
 	}
+	if seen := firstSeenLabel(result, opts.startedAt); seen != "" {
+		fmt.Fprintf(w, "%s\n", indent(dimFunc(seen), 2))
+	}
+	if why := reasonLabel(result, opts); why != "" {
+		fmt.Fprintf(w, "%s\n", indent(dimFunc(why), 2))
+	}
+	if seeAlso := seeAlsoLabel(result); seeAlso != "" {
+		fmt.Fprintf(w, "%s\n", indent(dimFunc(seeAlso), 2))
+	}
 
 	if result.Table != nil {
 		fmt.Fprintln(w)
@@ -152,6 +380,24 @@ func printSubcheck(w io.Writer, report *check.Report, result check.Finding, opts
 	}
 }
 
+// reasonLabel shows the rule behind a WARN or FAIL finding with --reasons, or
+// returns "" when it is not shown or the check gives none.
+func reasonLabel(f check.Finding, opts *runOptions) string {
+	if !opts.reasons || f.Severity < check.SeverityWarn || f.Reason == "" {
+		return ""
+	}
+	return "Why: " + f.Reason
+}
+
+// seeAlsoLabel lists the related findings of other checks that --see-also
+// linked to f, or returns "" when there are none.
+func seeAlsoLabel(f check.Finding) string {
+	if len(f.SeeAlso) == 0 {
+		return ""
+	}
+	return "See also: " + strings.Join(f.SeeAlso, ", ")
+}
+
 func printTable(w io.Writer, table *check.Table, indentSpaces int, opts *runOptions) {
 	if len(table.Rows) == 0 {
 		return
@@ -161,29 +407,30 @@ func printTable(w io.Writer, table *check.Table, indentSpaces int, opts *runOpti
 
 	const maxRowsBrief = 10
 	totalRows := len(table.Rows)
-	rowsToShow := table.Rows
+	rowsToShow := rowsBySeverity(table)
 	truncated := false
 
-	if opts.detail == string(detailBrief) && totalRows > maxRowsBrief {
-		rowsToShow = table.Rows[:maxRowsBrief]
+	if opts.detail == string(detailBrief) && !opts.full && totalRows > maxRowsBrief {
+		rowsToShow = rowsToShow[:maxRowsBrief]
 		truncated = true
 	}
 
 	widths := make([]int, len(table.Headers))
 	for i, header := range table.Headers {
-		widths[i] = len(header)
+		widths[i] = utf8.RuneCountInString(header)
 	}
 	for _, row := range table.Rows {
 		for i, cell := range row.Cells {
-			if i < len(widths) && len(cell) > widths[i] {
-				widths[i] = len(cell)
+			if i < len(widths) && utf8.RuneCountInString(cell) > widths[i] {
+				widths[i] = utf8.RuneCountInString(cell)
 			}
 		}
 	}
+	fitWidths(widths, indentSpaces, opts.width)
 
 	fmt.Fprint(w, indentStr)
 	for i, header := range table.Headers {
-		fmt.Fprintf(w, "%-*s  ", widths[i], header)
+		fmt.Fprintf(w, "%s  ", pad(ellipsize(header, widths[i]), widths[i], table.ColumnAlign(i)))
 	}
 	fmt.Fprintln(w)
 
@@ -198,7 +445,7 @@ func printTable(w io.Writer, table *check.Table, indentSpaces int, opts *runOpti
 
 		fmt.Fprint(w, indentStr)
 		for i, cell := range row.Cells {
-			fmt.Fprintf(w, "%s  ", colorFunc(fmt.Sprintf("%-*s", widths[i], cell)))
+			fmt.Fprintf(w, "%s  ", colorFunc(pad(ellipsize(cell, widths[i]), widths[i], table.ColumnAlign(i))))
 		}
 		fmt.Fprintln(w)
 	}
@@ -211,11 +458,103 @@ func printTable(w io.Writer, table *check.Table, indentSpaces int, opts *runOpti
 	}
 }
 
+// rowsBySeverity returns the rows of table worst first, so that a failing row
+// is not buried below warnings or cut by the brief row limit. Rows of the same
+// severity keep the check's order, as do all rows with Table.KeepOrder.
+func rowsBySeverity(table *check.Table) []check.TableRow {
+	if table.KeepOrder {
+		return table.Rows
+	}
+	rows := slices.Clone(table.Rows)
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].Severity > rows[j].Severity })
+	return rows
+}
+
+// minColumnWidth is the narrowest fitWidths shrinks a column to, so that
+// truncated cells keep enough text to be recognizable.
+const minColumnWidth = 8
+
+// fitWidths shrinks column widths in place so a table row, including its
+// indent and the separator after every column, fits in maxWidth. It takes from the widest
+// column first and never shrinks a column below minColumnWidth, so very narrow
+// terminals can still overflow. A maxWidth of 0 means no limit.
+func fitWidths(widths []int, indentSpaces, maxWidth int) {
+	if maxWidth <= 0 || len(widths) == 0 {
+		return
+	}
+	total := indentSpaces + 2*len(widths)
+	for _, width := range widths {
+		total += width
+	}
+	for total > maxWidth {
+		widest := 0
+		for i, width := range widths {
+			if width > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= minColumnWidth {
+			return
+		}
+		widths[widest]--
+		total--
+	}
+}
+
+// ellipsize cuts cell to width runes, ending in "…" when anything was cut.
+// The full value is still in the JSON output.
+func ellipsize(cell string, width int) string {
+	if utf8.RuneCountInString(cell) <= width {
+		return cell
+	}
+	runes := []rune(cell)
+	return string(runes[:width-1]) + "…"
+}
+
+// terminalWidth returns the column count of f when it is a terminal, otherwise
+// falls back to $COLUMNS. It returns 0 (no limit) when neither is known, so
+// piped output keeps full cell values.
+func terminalWidth(f *os.File) int {
+	if term.IsTerminal(int(f.Fd())) {
+		if width, _, err := term.GetSize(int(f.Fd())); err == nil && width > 0 {
+			return width
+		}
+	}
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+	return 0
+}
+
+func pad(cell string, width int, align check.ColumnAlign) string {
+	if align == check.AlignRight {
+		return fmt.Sprintf("%*s", width, cell)
+	}
+	return fmt.Sprintf("%-*s", width, cell)
+}
+
+// printHeader prints the first lines of text output: the database and, when
+// the run was tagged, its tags.
+func printHeader(w io.Writer, database string, tags map[string]string, glyphs glyphSet) {
+	fmt.Fprintf(w, "Database Health Check: %s\n", database)
+	if len(tags) > 0 {
+		fmt.Fprintf(w, "Tags: %s\n", formatTags(tags))
+	}
+	printLegend(w, glyphs)
+	fmt.Fprintln(w)
+}
+
 func printSummary(w io.Writer, reports []*check.Report) {
-	okCount, warnCount, failCount, skipCount := 0, 0, 0, 0
+	okCount, warnCount, failCount, errorCount, skipCount, naCount := 0, 0, 0, 0, 0, 0
 	var totalDuration time.Duration
 	for _, report := range reports {
 		totalDuration += report.Duration
+		// Panicked and errored checks are skipped, but their results are
+		// missing rather than not needed.
+		if pgdoctor.Errored(report) || pgdoctor.Panicked(report) {
+			errorCount++
+			continue
+		}
 		switch report.Severity {
 		case check.SeverityOK:
 			okCount++
@@ -225,6 +564,8 @@ func printSummary(w io.Writer, reports []*check.Report) {
 			failCount++
 		case check.SeveritySkip:
 			skipCount++
+		case check.SeverityNotApplicable:
+			naCount++
 		}
 	}
 
@@ -240,9 +581,15 @@ func printSummary(w io.Writer, reports []*check.Report) {
 	if okCount > 0 {
 		summaryParts = append(summaryParts, colorForSeverity(check.SeverityOK)(fmt.Sprintf("%d passed", okCount)))
 	}
+	if errorCount > 0 {
+		summaryParts = append(summaryParts, colorForSeverity(check.SeverityFail)(fmt.Sprintf("%d errored", errorCount)))
+	}
 	if skipCount > 0 {
 		summaryParts = append(summaryParts, colorForSeverity(check.SeveritySkip)(fmt.Sprintf("%d skipped", skipCount)))
 	}
+	if naCount > 0 {
+		summaryParts = append(summaryParts, colorForSeverity(check.SeverityNotApplicable)(fmt.Sprintf("%d not applicable", naCount)))
+	}
 
 	dimFunc := dimColor()
 	fmt.Fprintf(w, "Summary: %s %s\n", strings.Join(summaryParts, ", "),
@@ -250,6 +597,98 @@ func printSummary(w io.Writer, reports []*check.Report) {
 	fmt.Fprintln(w)
 }
 
+// printFooter prints the summary and, below the verbose detail levels, how to
+// see more; command is the invocation to repeat, as "pgdoctor run ...".
+func printFooter(w io.Writer, reports []*check.Report, opts *runOptions, command string) {
+	if opts.compact {
+		printSummary(w, reports)
+		return
+	}
+	fmt.Fprintln(w)
+	printSummary(w, reports)
+
+	if opts.detail == string(detailSummary) || opts.detail == string(detailBrief) {
+		dimFunc := dimColor()
+		fmt.Fprintf(w, "%s\n", dimFunc("To see more: "+command+" --detail verbose"))
+		fmt.Fprintf(w, "%s\n", dimFunc("To see how to fix: pgdoctor explain <check-id>"))
+		fmt.Fprintln(w)
+	}
+}
+
+// glyphSet is a --glyphs value: how the text output marks severities.
+type glyphSet string
+
+const (
+	glyphsASCII glyphSet = "ascii" // [PASS], [WARN], ...
+	glyphsEmoji glyphSet = "emoji"
+	glyphsNerd  glyphSet = "nerd" // Nerd Font icons
+)
+
+// severityGlyphs are the symbols put before the severity label. The label
+// stays, so severities never depend on telling colors or symbols apart.
+var severityGlyphs = map[glyphSet]map[check.Severity]string{
+	glyphsEmoji: {
+		check.SeverityOK:            "✅",
+		check.SeverityWarn:          "⚠️",
+		check.SeverityFail:          "❌",
+		check.SeveritySkip:          "⏭️",
+		check.SeverityNotApplicable: "➖",
+	},
+	glyphsNerd: {
+		check.SeverityOK:            "\uf00c", // nf-fa-check
+		check.SeverityWarn:          "\uf071", // nf-fa-warning
+		check.SeverityFail:          "\uf00d", // nf-fa-times
+		check.SeveritySkip:          "\uf04e", // nf-fa-forward
+		check.SeverityNotApplicable: "\uf068", // nf-fa-minus
+	},
+}
+
+// legendSeverities are the severities the legend explains, with what each
+// means for the reader.
+var legendSeverities = []struct {
+	severity check.Severity
+	meaning  string
+}{
+	{check.SeverityOK, "ok"},
+	{check.SeverityWarn, "needs attention"},
+	{check.SeverityFail, "action required"},
+	{check.SeveritySkip, "could not run"},
+	{check.SeverityNotApplicable, "not applicable"},
+}
+
+// severityBadge is how every text printer marks a severity: "[WARN]" in the
+// ascii glyph set, or the glyph and the label, in the severity's color.
+func severityBadge(severity check.Severity, glyphs glyphSet) string {
+	label, colorFunc := severityDisplay(severity)
+	if glyph, ok := severityGlyphs[glyphs][severity]; ok {
+		return colorFunc(glyph + " " + label)
+	}
+	return colorFunc("[" + label + "]")
+}
+
+// badgeWidth is the number of terminal columns severityBadge takes; emoji
+// are two columns wide.
+func badgeWidth(severity check.Severity, glyphs glyphSet) int {
+	label, _ := severityDisplay(severity)
+	if glyphs == glyphsEmoji {
+		return len(label) + 3
+	}
+	return len(label) + 2
+}
+
+// printLegend prints one line explaining the severity badges, when they are
+// colored or use glyphs; plain [PASS] and [FAIL] explain themselves.
+func printLegend(w io.Writer, glyphs glyphSet) {
+	if color.NoColor && severityGlyphs[glyphs] == nil {
+		return
+	}
+	parts := make([]string, 0, len(legendSeverities))
+	for _, l := range legendSeverities {
+		parts = append(parts, severityBadge(l.severity, glyphs)+" "+l.meaning)
+	}
+	fmt.Fprintf(w, "%s %s\n", dimColor()("Legend:"), strings.Join(parts, "  "))
+}
+
 func severityDisplay(severity check.Severity) (string, func(string) string) {
 	switch severity {
 	case check.SeverityOK:
@@ -281,11 +720,38 @@ func colorForSeverity(severity check.Severity) func(string) string {
 	case check.SeveritySkip:
 		fn := color.New(color.FgMagenta).SprintFunc()
 		return func(s string) string { return fn(s) }
+	case check.SeverityNotApplicable:
+		fn := color.New(color.FgBlue).SprintFunc()
+		return func(s string) string { return fn(s) }
 	default:
 		return func(s string) string { return s }
 	}
 }
 
+// colorForCategory avoids the severity colors so a header is never mistaken
+// for a result.
+func colorForCategory(category check.Category) func(string) string {
+	if color.NoColor {
+		return func(s string) string { return s }
+	}
+
+	attrs := []color.Attribute{color.Bold}
+	switch category {
+	case check.CategoryConfigs:
+		attrs = append(attrs, color.FgCyan)
+	case check.CategoryIndexes:
+		attrs = append(attrs, color.FgBlue)
+	case check.CategoryPerformance:
+		attrs = append(attrs, color.FgHiCyan)
+	case check.CategorySchema:
+		attrs = append(attrs, color.FgHiBlue)
+	case check.CategoryVacuum:
+		attrs = append(attrs, color.FgHiWhite)
+	}
+	fn := color.New(attrs...).SprintFunc()
+	return func(s string) string { return fn(s) }
+}
+
 func dimColor() func(string) string {
 	if color.NoColor {
 		return func(s string) string { return s }
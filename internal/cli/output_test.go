@@ -2,10 +2,15 @@ package cli
 
 import (
 	"bytes"
+	"fmt"
+	"os"
+	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/emancu/pgdoctor/check"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // singleFindingReport builds a report whose only finding has ID == CheckID,
@@ -41,3 +46,316 @@ func TestPrintCheckReport_SingleFinding_HidesDebugWithoutDebugDetail(t *testing.
 
 	assert.NotContains(t, buf.String(), "Debug:", "debug must stay hidden unless --detail debug")
 }
+
+func TestPrintCheckReport_NotApplicableShowsReason(t *testing.T) {
+	t.Parallel()
+
+	report := check.NewReport(check.Metadata{CheckID: "session-settings", Name: "Session Configs"})
+	report.AddFinding(check.Finding{ID: "session-settings", Name: "Session Configs", Severity: check.SeverityOK})
+	report.AddFinding(check.Finding{ID: "transaction-timeout", Name: "Transaction Timeout", Severity: check.SeverityNotApplicable, Details: "requires PostgreSQL 17+"})
+
+	var buf bytes.Buffer
+	printCheckReport(&buf, report, &runOptions{detail: string(detailBrief)})
+	assert.Contains(t, buf.String(), "[PASS] Session Configs (session-settings)\n")
+	assert.Contains(t, buf.String(), "[N/A] Transaction Timeout (session-settings/transaction-timeout) — requires PostgreSQL 17+\n")
+
+	buf.Reset()
+	printCheckSummary(&buf, report, &runOptions{detail: string(detailSummary)})
+	assert.Contains(t, buf.String(), "(1/1)", "N/A findings are not counted")
+
+	buf.Reset()
+	whole := check.NewReport(check.Metadata{CheckID: "demo", Name: "Demo Check"})
+	whole.AddFinding(check.Finding{ID: "demo", Name: "Demo Check", Severity: check.SeverityNotApplicable, Details: "extension not installed"})
+	printCheckReport(&buf, whole, &runOptions{detail: string(detailBrief)})
+	assert.Equal(t, "[N/A] Demo Check (demo) — extension not installed\n", buf.String())
+}
+
+func TestPrintSummary_CountsNotApplicableSeparately(t *testing.T) {
+	t.Parallel()
+
+	notApplicable := check.NewReport(check.Metadata{CheckID: "demo", Name: "demo"})
+	notApplicable.AddFinding(check.Finding{ID: "demo", Severity: check.SeverityNotApplicable})
+
+	var buf bytes.Buffer
+	printSummary(&buf, []*check.Report{passingReport(check.CategoryConfigs, "pg-version"), notApplicable})
+	assert.Contains(t, buf.String(), "1 passed, 1 not applicable")
+}
+
+func passingReport(category check.Category, id string) *check.Report {
+	report := check.NewReport(check.Metadata{Category: category, CheckID: id, Name: id})
+	report.AddFinding(check.Finding{ID: id, Name: id, Severity: check.SeverityOK})
+	return report
+}
+
+func TestTextPrinter_PrintsHeaderPerCategory(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	printer := &textPrinter{w: &buf, opts: &runOptions{detail: string(detailSummary), groupBy: string(groupByCategory)}}
+	printer.print(passingReport(check.CategoryConfigs, "a"))
+	printer.print(passingReport(check.CategoryConfigs, "b"))
+	printer.print(passingReport(check.CategoryIndexes, "c"))
+	printer.flush()
+
+	out := buf.String()
+	assert.Equal(t, 1, strings.Count(out, "CONFIGS\n"))
+	assert.Equal(t, 1, strings.Count(out, "INDEXES\n"))
+	assert.Contains(t, out, "(a)")
+	assert.Contains(t, out, "(c)")
+}
+
+func TestTextPrinter_PrintsHeaderPerDatabase(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	printer := &textPrinter{w: &buf, opts: &runOptions{detail: string(detailSummary), groupBy: string(groupByCategory), allDatabases: true}}
+	printer.print(passingReport(check.CategoryConfigs, "pg-version"))
+	for _, database := range []string{"app", "orders"} {
+		report := passingReport(check.CategoryIndexes, "index-bloat")
+		report.Database = database
+		printer.print(report)
+	}
+	printer.flush()
+
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "CLUSTER-WIDE\n"), "cluster-wide checks come first")
+	assert.Contains(t, out, "DATABASE app\n")
+	assert.Contains(t, out, "DATABASE orders\n")
+	assert.Equal(t, 2, strings.Count(out, "INDEXES\n"), "category headers restart in each database")
+}
+
+func TestTextPrinter_CollapsesPassingChecksPerCategory(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	printer := &textPrinter{w: &buf, opts: &runOptions{detail: string(detailSummary), collapse: true}}
+	printer.print(passingReport(check.CategoryConfigs, "a"))
+	printer.print(passingReport(check.CategoryConfigs, "b"))
+	warning := singleFindingReport()
+	warning.Category = check.CategoryIndexes
+	printer.print(warning)
+	printer.print(passingReport(check.CategoryVacuum, "c"))
+	printer.flush()
+
+	out := buf.String()
+	assert.NotContains(t, out, "(a)")
+	assert.Contains(t, out, "[PASS] 2 passing check(s)\n\nINDEXES\n", "count is printed before the next category header")
+	assert.Contains(t, out, "(demo)", "non-passing checks are never collapsed")
+	assert.True(t, strings.HasSuffix(out, "[PASS] 1 passing check(s)\n"), "flush prints the last category's count")
+}
+
+func TestTextPrinter_HidePassingWinsOverCollapse(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	printer := &textPrinter{w: &buf, opts: &runOptions{detail: string(detailSummary), collapse: true, hidePassing: true}}
+	printer.print(passingReport(check.CategoryConfigs, "a"))
+	printer.flush()
+
+	assert.NotContains(t, buf.String(), "passing check(s)")
+}
+
+func TestPrintTable_RightAlignsColumns(t *testing.T) {
+	t.Parallel()
+
+	table := &check.Table{
+		Headers: []string{"Table", "Size"},
+		Align:   []check.ColumnAlign{check.AlignLeft, check.AlignRight},
+		Rows: []check.TableRow{
+			{Cells: []string{"public.events", "1.2 GB"}},
+			{Cells: []string{"public.a", "12 kB"}},
+		},
+	}
+
+	var buf bytes.Buffer
+	printTable(&buf, table, 0, &runOptions{detail: string(detailVerbose)})
+
+	out := buf.String()
+	assert.Contains(t, out, "Table            Size  \n", "header follows its column's alignment")
+	assert.Contains(t, out, "public.events  1.2 GB  \n")
+	assert.Contains(t, out, "public.a        12 kB  \n")
+}
+
+func TestPrintTable_EllipsizesToWidth(t *testing.T) {
+	t.Parallel()
+
+	query := "SELECT * FROM events WHERE account_id = $1 ORDER BY created_at DESC"
+	table := &check.Table{
+		Headers: []string{"Query", "Calls"},
+		Align:   []check.ColumnAlign{check.AlignLeft, check.AlignRight},
+		Rows:    []check.TableRow{{Cells: []string{query, "42"}}},
+	}
+
+	var buf bytes.Buffer
+	printTable(&buf, table, 2, &runOptions{detail: string(detailVerbose), width: 40})
+
+	for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+		assert.LessOrEqual(t, utf8.RuneCountInString(line), 40, "line %q", line)
+	}
+	assert.Contains(t, buf.String(), "  SELECT * FROM events WHERE a…     42  \n")
+	assert.NotContains(t, buf.String(), query)
+}
+
+func TestPrintTable_NoWidthKeepsFullCells(t *testing.T) {
+	t.Parallel()
+
+	query := "SELECT * FROM events WHERE account_id = $1 ORDER BY created_at DESC"
+	table := &check.Table{
+		Headers: []string{"Query"},
+		Rows:    []check.TableRow{{Cells: []string{query}}},
+	}
+
+	var buf bytes.Buffer
+	printTable(&buf, table, 2, &runOptions{detail: string(detailVerbose)})
+
+	assert.Contains(t, buf.String(), query)
+}
+
+func TestPrintTable_FullShowsEveryRow(t *testing.T) {
+	t.Parallel()
+
+	table := &check.Table{Headers: []string{"Table"}}
+	for i := range 12 {
+		table.Rows = append(table.Rows, check.TableRow{Cells: []string{fmt.Sprintf("public.t%02d", i)}})
+	}
+
+	var brief bytes.Buffer
+	printTable(&brief, table, 2, &runOptions{detail: string(detailBrief)})
+	assert.NotContains(t, brief.String(), "public.t11")
+	assert.Contains(t, brief.String(), "(showing 10 of 12 rows")
+
+	var full bytes.Buffer
+	printTable(&full, table, 2, &runOptions{detail: string(detailBrief), full: true})
+	assert.Contains(t, full.String(), "public.t11")
+	assert.NotContains(t, full.String(), "showing")
+}
+
+func TestPrintTable_WorstRowsFirst(t *testing.T) {
+	t.Parallel()
+
+	table := &check.Table{Headers: []string{"Setting"}}
+	for i := range 11 {
+		table.Rows = append(table.Rows, check.TableRow{Cells: []string{fmt.Sprintf("warn_%02d", i)}, Severity: check.SeverityWarn})
+	}
+	table.Rows = append(table.Rows,
+		check.TableRow{Cells: []string{"ok"}, Severity: check.SeverityOK},
+		check.TableRow{Cells: []string{"statement_timeout"}, Severity: check.SeverityFail},
+	)
+
+	var buf bytes.Buffer
+	printTable(&buf, table, 0, &runOptions{detail: string(detailBrief)})
+	lines := strings.Split(buf.String(), "\n")
+	assert.Equal(t, "statement_timeout  ", lines[2], "the failing row is not cut by the brief limit")
+	assert.Equal(t, "warn_00            ", lines[3], "rows of one severity keep the check's order")
+	assert.NotContains(t, buf.String(), "warn_10")
+	assert.Equal(t, "statement_timeout", table.Rows[12].Cells[0], "the check's table is not reordered")
+
+	table.KeepOrder = true
+	buf.Reset()
+	printTable(&buf, table, 0, &runOptions{detail: string(detailVerbose)})
+	lines = strings.Split(buf.String(), "\n")
+	assert.Equal(t, "warn_00            ", lines[2])
+	assert.Equal(t, "statement_timeout  ", lines[14])
+}
+
+func TestFitWidths(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		widths   []int
+		maxWidth int
+		expected []int
+	}{
+		{"no limit", []int{50, 10}, 0, []int{50, 10}},
+		{"already fits", []int{20, 10}, 80, []int{20, 10}},
+		{"shrinks widest first", []int{60, 30}, 60, []int{28, 28}},
+		{"stops at minimum", []int{30, 30}, 10, []int{minColumnWidth, minColumnWidth}},
+		{"never grows narrow columns", []int{4, 100}, 30, []int{4, 22}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fitWidths(tt.widths, 0, tt.maxWidth)
+			assert.Equal(t, tt.expected, tt.widths)
+		})
+	}
+}
+
+func TestTerminalWidth_FallsBackToColumns(t *testing.T) {
+	t.Setenv("COLUMNS", "97")
+
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	require.NoError(t, err)
+	defer f.Close()
+
+	assert.Equal(t, 97, terminalWidth(f))
+
+	t.Setenv("COLUMNS", "")
+	assert.Equal(t, 0, terminalWidth(f))
+}
+
+func TestTextPrinter_Compact(t *testing.T) {
+	t.Parallel()
+
+	flagged := check.NewReport(check.Metadata{CheckID: "table-bloat", Name: "Table Bloat", Category: check.CategoryVacuum})
+	flagged.AddFinding(check.Finding{ID: "dead-tuples", Severity: check.SeverityWarn, Details: "2 tables with dead tuples"})
+	flagged.AddFinding(check.Finding{ID: "bloat", Severity: check.SeverityFail, Details: "3 tables over 60% bloat. Run pg_repack on them.\nMore detail"})
+	flagged.AddFinding(check.Finding{ID: "toast", Severity: check.SeverityOK, Details: "fine"})
+	flagged.Database = "app"
+
+	var buf bytes.Buffer
+	printer := &textPrinter{w: &buf, opts: &runOptions{detail: string(detailBrief), compact: true, groupBy: string(groupByCategory), allDatabases: true}}
+	printer.print(passingReport(check.CategoryConfigs, "pg-version"))
+	printer.print(flagged)
+	printer.flush()
+
+	assert.Equal(t, "[PASS] pg-version\n[FAIL] app/table-bloat: 3 tables over 60% bloat (+1 more)\n", buf.String(),
+		"no category or database headers, and no tables")
+}
+
+func TestPrintCheckCompact_EllipsizesToWidth(t *testing.T) {
+	t.Parallel()
+
+	report := singleFindingReport()
+	var buf bytes.Buffer
+	printCheckCompact(&buf, report, &runOptions{compact: true, width: 20})
+	assert.Equal(t, "[WARN] demo: someth…\n", buf.String())
+	assert.Equal(t, 20, utf8.RuneCountInString(strings.TrimSuffix(buf.String(), "\n")))
+}
+
+func TestSeverityBadge(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "[WARN]", severityBadge(check.SeverityWarn, glyphsASCII))
+	assert.Equal(t, "[N/A]", severityBadge(check.SeverityNotApplicable, ""), "an unset glyph set is ascii")
+	assert.Equal(t, "❌ FAIL", severityBadge(check.SeverityFail, glyphsEmoji))
+	assert.Equal(t, "\uf00c PASS", severityBadge(check.SeverityOK, glyphsNerd))
+
+	for glyphs, symbols := range severityGlyphs {
+		for _, l := range legendSeverities {
+			assert.NotEmpty(t, symbols[l.severity], "%s has a glyph for %s", glyphs, l.severity)
+		}
+	}
+}
+
+func TestPrintCheckCompact_GlyphWidth(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	printCheckCompact(&buf, singleFindingReport(), &runOptions{compact: true, width: 20, glyphs: glyphsEmoji})
+	assert.Equal(t, "⚠️ WARN demo: somet…\n", buf.String(), "the emoji takes two columns")
+}
+
+func TestPrintLegend(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	printLegend(&buf, glyphsASCII)
+	assert.Empty(t, buf.String(), "uncolored [PASS] labels need no legend")
+
+	printLegend(&buf, glyphsEmoji)
+	assert.Equal(t, "Legend: ✅ PASS ok  ⚠️ WARN needs attention  ❌ FAIL action required  ⏭️ SKIP could not run  ➖ N/A not applicable\n", buf.String())
+}
@@ -1,8 +1,9 @@
 package cli
 
 const (
-	presetAll    = "all"
-	presetTriage = "triage"
+	presetAll      = "all"
+	presetTriage   = "triage"
+	presetSecurity = "security"
 )
 
 var triageChecks = []string{
@@ -18,10 +19,21 @@ var triageChecks = []string{
 	"cache-efficiency",
 }
 
+// securityChecks audit who can read, change, or run what, for a review of
+// privileges rather than health.
+var securityChecks = []string{
+	"grant-audit",
+	"role-memberships",
+	"fdw-health",
+	"extension-security",
+}
+
 func getPresetChecks(preset string) []string {
 	switch preset {
 	case presetTriage:
 		return triageChecks
+	case presetSecurity:
+		return securityChecks
 	default:
 		return nil
 	}
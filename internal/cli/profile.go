@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+// startProfiles starts the CPU profile of --cpuprofile and arranges the heap
+// profile of --memprofile. The returned stop writes and closes both; callers
+// defer it so the profiles are flushed on every return path, including runs
+// that exit non-zero.
+func startProfiles(cpuPath, memPath string) (stop func(), err error) {
+	var cpuFile, memFile *os.File
+
+	// Create both files up front so a bad path fails before the run.
+	if memPath != "" {
+		if memFile, err = os.Create(memPath); err != nil {
+			return nil, fmt.Errorf("creating memory profile: %w", err)
+		}
+	}
+	if cpuPath != "" {
+		if cpuFile, err = os.Create(cpuPath); err != nil {
+			closeFile(memFile)
+			return nil, fmt.Errorf("creating CPU profile: %w", err)
+		}
+		if err := pprof.StartCPUProfile(cpuFile); err != nil {
+			closeFile(cpuFile)
+			closeFile(memFile)
+			return nil, fmt.Errorf("starting CPU profile: %w", err)
+		}
+	}
+
+	return func() {
+		if cpuFile != nil {
+			pprof.StopCPUProfile()
+			if err := cpuFile.Close(); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: writing CPU profile: %v\n", err)
+			}
+		}
+		if memFile != nil {
+			// Count only live objects, as of the end of the run.
+			runtime.GC()
+			err := pprof.WriteHeapProfile(memFile)
+			err = errors.Join(err, memFile.Close())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: writing memory profile: %v\n", err)
+			}
+		}
+	}, nil
+}
+
+func closeFile(f *os.File) {
+	if f != nil {
+		_ = f.Close()
+	}
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Not parallel: only one CPU profile can run per process.
+func TestStartProfiles(t *testing.T) {
+	dir := t.TempDir()
+	cpuPath := filepath.Join(dir, "cpu.pprof")
+	memPath := filepath.Join(dir, "mem.pprof")
+
+	stop, err := startProfiles(cpuPath, memPath)
+	require.NoError(t, err)
+	stop()
+
+	for _, path := range []string{cpuPath, memPath} {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Positive(t, info.Size(), "%s is flushed on stop", filepath.Base(path))
+	}
+}
+
+func TestStartProfiles_Disabled(t *testing.T) {
+	t.Parallel()
+
+	stop, err := startProfiles("", "")
+	require.NoError(t, err)
+	stop()
+}
+
+func TestStartProfiles_BadPath(t *testing.T) {
+	t.Parallel()
+
+	missing := filepath.Join(t.TempDir(), "missing", "profile.pprof")
+	_, err := startProfiles("", missing)
+	require.ErrorContains(t, err, "creating memory profile")
+	_, err = startProfiles(missing, "")
+	require.ErrorContains(t, err, "creating CPU profile")
+}
+
+func TestRunCommand_ProfileFlagsHidden(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	cmd := newRootCommand("test")
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"run", "--help"})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), "--query-timeout")
+	assert.NotContains(t, out.String(), "cpuprofile")
+	assert.NotContains(t, out.String(), "pprof")
+}
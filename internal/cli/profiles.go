@@ -0,0 +1,47 @@
+package cli
+
+import (
+	_ "embed"
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/emancu/pgdoctor/check"
+)
+
+const profileDefault = "default"
+
+//go:embed recommended.yaml
+var recommendedYAML []byte
+
+// loadProfile returns the recommended check.Config baseline for a workload profile.
+func loadProfile(name string) (check.Config, error) {
+	profiles, err := parseProfiles(recommendedYAML)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, ok := profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown profile %q (available: %v)", name, profileNames(profiles))
+	}
+	return cfg, nil
+}
+
+func parseProfiles(data []byte) (map[string]check.Config, error) {
+	var profiles map[string]check.Config
+	if err := yaml.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("parsing recommended profiles: %w", err)
+	}
+	return profiles, nil
+}
+
+func profileNames(profiles map[string]check.Config) []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
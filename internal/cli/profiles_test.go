@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/emancu/pgdoctor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadProfile_DefaultIsEmpty(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := loadProfile(profileDefault)
+	require.NoError(t, err)
+	assert.Empty(t, cfg, "default profile must leave every check on its built-in thresholds")
+}
+
+func TestLoadProfile_OLTPTightensSessionTimeouts(t *testing.T) {
+	t.Parallel()
+
+	oltp, err := loadProfile("oltp")
+	require.NoError(t, err)
+	olap, err := loadProfile("olap")
+	require.NoError(t, err)
+
+	assert.Equal(t, "2000", oltp["session-settings"]["timeout_warn"])
+	assert.Equal(t, "300000", olap["session-settings"]["timeout_warn"])
+}
+
+func TestLoadProfile_Unknown(t *testing.T) {
+	t.Parallel()
+
+	_, err := loadProfile("batch")
+	require.ErrorContains(t, err, `unknown profile "batch"`)
+	assert.ErrorContains(t, err, "oltp")
+}
+
+func TestRecommendedProfiles_ReferenceKnownChecks(t *testing.T) {
+	t.Parallel()
+
+	profiles, err := parseProfiles(recommendedYAML)
+	require.NoError(t, err)
+	require.Contains(t, profiles, profileDefault)
+
+	known := map[string]struct{}{}
+	for _, pkg := range pgdoctor.AllChecks() {
+		known[pkg.Metadata().CheckID] = struct{}{}
+	}
+
+	for name, cfg := range profiles {
+		for checkID := range cfg {
+			assert.Contains(t, known, checkID, "profile %q configures unknown check %q", name, checkID)
+		}
+	}
+}
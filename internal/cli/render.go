@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/emancu/pgdoctor"
+)
+
+func newRenderCommand() *cobra.Command {
+	opts := &runOptions{}
+	var input string
+
+	cmd := &cobra.Command{
+		Use:   "render --input <report.json>",
+		Short: "Print a saved JSON report as text",
+		Long: `Print a report saved with 'pgdoctor run --output json' again, without
+connecting to the database. The text output flags of run apply, so an archived
+run can be read at any detail level, sorted, or narrowed to its top issues.
+
+The document records what each check found, not how it was displayed: tables
+lose their column alignment, and debug output is not kept. Use --input - to
+read from stdin, and --output json, openmetrics, template, junit, or csv to
+convert the report. Text output exits 1 when the saved run has a failing check, like run.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return renderReport(cmd, opts, input)
+		},
+	}
+
+	cmd.Flags().StringVar(&input, "input", "", "JSON report to read, as written by run --output json (- for stdin)")
+	_ = cmd.MarkFlagRequired("input")
+	cmd.Flags().StringVar(&opts.detail, "detail", string(detailBrief), "Detail level: summary, brief (default), verbose")
+	cmd.Flags().BoolVar(&opts.hidePassing, "hide-passing", false, "Hide passing checks")
+	cmd.Flags().BoolVar(&opts.collapse, "collapse-passing", false, "Collapse passing checks into a count per category")
+	cmd.Flags().StringVar(&opts.groupBy, "group-by", string(groupByNone), "Grouping: none (default), category (colored category headers)")
+	cmd.Flags().StringVar(&opts.sortBy, "sort", string(sortCategory), "Report order: category (default, then check ID), id, severity (worst first)")
+	cmd.Flags().StringVar(&opts.output, "output", outputText, "Output format: text (default), json, summary-json, openmetrics, template, junit, csv")
+	cmd.Flags().StringVar(&opts.templateFile, "template", "", "Go text/template file for --output template, executed against the JSON document")
+	cmd.Flags().StringVar(&opts.outputDir, "output-dir", "", "Directory for --output csv: a <check-id>.csv per check table and a _summary.csv")
+	cmd.Flags().StringSliceVar(&opts.tagFilter, "tag-filter", nil, "Only report findings tagged with one of these finding tags, e.g. wraparound")
+	cmd.Flags().BoolVar(&opts.reasons, "reasons", false, "Show the rule that set the severity of each warning and failure (text only)")
+	cmd.Flags().BoolVar(&opts.seeAlso, "see-also", false, "Link warnings and failures of different checks sharing a finding tag, listing the others under each")
+	cmd.Flags().StringVar((*string)(&opts.glyphs), "glyphs", string(glyphsASCII), "Severity markers in text output: ascii (default, [PASS]), emoji, nerd (Nerd Font icons); colored or glyph markers add a legend")
+	cmd.Flags().IntVar(&opts.top, "top", 0, "List the N most urgent findings across all checks above the detailed output (text only)")
+	cmd.Flags().IntVar(&opts.width, "width", 0, "Maximum text table width; longer cells are cut with … (default: terminal width, or $COLUMNS when not a TTY; 0 for no limit)")
+	cmd.Flags().BoolVar(&opts.full, "full", false, "Print every table row and full cell values in text output, regardless of --detail and terminal width")
+	cmd.Flags().BoolVar(&opts.compact, "compact", false, "Print one line per check in text output: its severity, ID, and a summary of its most severe finding")
+
+	return cmd
+}
+
+// renderReport prints the JSON report at input in the format of opts.
+func renderReport(cmd *cobra.Command, opts *runOptions, input string) error {
+	if err := validateOutputOptions(cmd, opts); err != nil {
+		return err
+	}
+
+	var r io.Reader = cmd.InOrStdin()
+	source := "stdin"
+	if input == "-" {
+		// Waiting on a terminal for JSON nobody is going to type looks
+		// like a hang.
+		if f, ok := r.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+			fmt.Fprintln(os.Stderr, "Error: --input - reads a report from stdin, but stdin is a terminal; pipe one in, e.g. pgdoctor run \"$DSN\" --output json | pgdoctor render --input -")
+			return &SilentError{ExitCode: 1}
+		}
+	} else {
+		source = input
+		f, err := os.Open(input)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return &SilentError{ExitCode: 1}
+		}
+		defer f.Close()
+		r = f
+	}
+
+	run, reports, err := decodeJSON(r)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: reading %s: %v\n", source, err)
+		return &SilentError{ExitCode: 1}
+	}
+
+	reports = filterReportsTagged(reports, opts.tagFilter)
+	if opts.seeAlso {
+		reports = pgdoctor.LinkRelated(reports)
+	}
+
+	w := cmd.OutOrStdout()
+	if opts.output != outputText {
+		sortReports(reports, sortOrder(opts.sortBy))
+		if err := formatReports(w, opts, run, reports); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return &SilentError{ExitCode: 1}
+		}
+		return nil
+	}
+
+	// Reports carry a database only when the run covered every database.
+	for _, r := range reports {
+		if r.Database != "" {
+			opts.allDatabases = true
+			break
+		}
+	}
+
+	if !opts.compact {
+		printHeader(w, run.database, run.tags, opts.glyphs)
+	}
+	opts.startedAt = run.startedAt
+	printer := &textPrinter{w: w, opts: opts}
+	printer.printAll(reports)
+	printer.flush()
+	printStoppedEarly(w, run)
+	printFooter(w, reports, opts, "pgdoctor render ...")
+
+	return failedError(reports)
+}
@@ -0,0 +1,362 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emancu/pgdoctor"
+	"github.com/emancu/pgdoctor/check"
+)
+
+func TestDecodeJSON_RoundTrips(t *testing.T) {
+	t.Parallel()
+
+	reports := sampleReports()
+	reports[1].Duration = 42_000_000 // 42ms
+	var saved bytes.Buffer
+	require.NoError(t, formatJSON(&saved, sampleRun(), reports))
+
+	run, decoded, err := decodeJSON(bytes.NewReader(saved.Bytes()))
+	require.NoError(t, err)
+	require.Len(t, decoded, 5)
+	assert.Equal(t, "app", run.database)
+	assert.Equal(t, sampleRun().startedAt, run.startedAt)
+	assert.Equal(t, check.SeverityFail, decoded[1].Severity)
+	assert.Equal(t, reports[1].Duration, decoded[1].Duration)
+	assert.Equal(t, check.SeveritySkip, decoded[3].Severity)
+	assert.Equal(t, check.SeverityNotApplicable, decoded[4].Severity)
+	assert.Equal(t, check.SeverityWarn, decoded[1].Results[1].Table.Rows[1].Severity)
+
+	var again bytes.Buffer
+	require.NoError(t, formatJSON(&again, run, decoded))
+	assert.JSONEq(t, saved.String(), again.String(), "decoding and re-encoding is lossless")
+}
+
+func TestDecodeJSON_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := decodeJSON(strings.NewReader(`{"reports": [`))
+	require.ErrorContains(t, err, "decoding JSON")
+
+	_, _, err = decodeJSON(strings.NewReader(`{"database": "app"}`))
+	require.ErrorContains(t, err, "not a pgdoctor report")
+
+	_, _, err = decodeJSON(strings.NewReader(`{"reports": [{"check_id": "pg-version", "severity": "critical", "results": []}]}`))
+	require.ErrorContains(t, err, `report pg-version: unknown severity "critical"`)
+
+	_, _, err = decodeJSON(strings.NewReader(`{"schema_version": 2, "reports": []}`))
+	require.ErrorContains(t, err, "report has schema_version 2, but this pgdoctor reads up to 1")
+}
+
+func TestDecodeJSON_WithoutSchemaVersion(t *testing.T) {
+	t.Parallel()
+
+	run, reports, err := decodeJSON(strings.NewReader(`{"database": "app", "reports": []}`))
+	require.NoError(t, err, "documents from before schema_version are read as version 1")
+	assert.Equal(t, "app", run.database)
+	assert.Empty(t, reports)
+}
+
+func TestRenderCommand_Text(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	var saved bytes.Buffer
+	require.NoError(t, formatJSON(&saved, sampleRun(), sampleReports()))
+	require.NoError(t, os.WriteFile(path, saved.Bytes(), 0o600))
+
+	var out bytes.Buffer
+	cmd := newRootCommand("test")
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"render", "--input", path, "--detail", "verbose", "--width", "0"})
+	err := cmd.Execute()
+
+	var silent *SilentError
+	require.ErrorAs(t, err, &silent, "the saved run has a failing check")
+	assert.Equal(t, 1, silent.ExitCode)
+
+	text := out.String()
+	assert.Contains(t, text, "Database Health Check: app\n")
+	assert.Contains(t, text, "[FAIL] Table Bloat (table-bloat)")
+	assert.Contains(t, text, "public.events  72%")
+	assert.Contains(t, text, "[N/A] Statements Reset (statements-reset) [0ms] — pg_stat_statements is not installed")
+	assert.Contains(t, text, "Summary: 1 failures, 2 passed, 1 skipped, 1 not applicable")
+}
+
+func TestRenderCommand_Stdin(t *testing.T) {
+	t.Parallel()
+
+	var saved bytes.Buffer
+	require.NoError(t, formatJSON(&saved, sampleRun(), sampleReports()))
+
+	render := func(input string) (string, error) {
+		var out bytes.Buffer
+		cmd := newRootCommand("test")
+		cmd.SetIn(strings.NewReader(input))
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{"render", "--input", "-", "--output", "json"})
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := render(saved.String())
+	require.NoError(t, err)
+	var doc jsonRun
+	require.NoError(t, json.Unmarshal([]byte(out), &doc))
+	assert.Equal(t, "app", doc.Database)
+	assert.Len(t, doc.Reports, 5, "a report piped in renders like one read from a file")
+
+	_, err = render(`{"status": "warn", "checks": 42}`)
+	var silent *SilentError
+	require.ErrorAs(t, err, &silent, "summary JSON is not a report")
+	assert.Equal(t, 1, silent.ExitCode)
+}
+
+func TestRenderCommand_Reasons(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	var saved bytes.Buffer
+	require.NoError(t, formatJSON(&saved, sampleRun(), sampleReports()))
+	require.NoError(t, os.WriteFile(path, saved.Bytes(), 0o600))
+
+	render := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		cmd := newRootCommand("test")
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(append([]string{"render", "--input", path}, args...))
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	text, _ := render()
+	assert.NotContains(t, text, "Why:", "reasons are only shown with --reasons")
+
+	text, _ = render("--reasons")
+	assert.Contains(t, text, "  1 table\n  Why: bloat 72% > max 50% → FAIL\n")
+
+	_, err := render("--reasons", "--output", "json")
+	var silent *SilentError
+	require.ErrorAs(t, err, &silent, "JSON always has the reasons")
+	assert.Equal(t, 1, silent.ExitCode)
+}
+
+func TestRenderCommand_SeeAlso(t *testing.T) {
+	t.Parallel()
+
+	horizon := check.NewReport(check.Metadata{CheckID: "xmin-horizon", Name: "Xmin Horizon", Category: check.CategoryVacuum})
+	horizon.AddFinding(check.Finding{ID: "xmin-horizon", Name: "Xmin Horizon", Severity: check.SeverityWarn, Details: "held by a session", Tags: []string{check.TagXminHorizon}})
+	idle := check.NewReport(check.Metadata{CheckID: "connection-health", Name: "Connection Health", Category: check.CategoryConfigs})
+	idle.AddFinding(check.Finding{ID: "idle-in-transaction", Name: "Idle In Transaction", Severity: check.SeverityWarn, Details: "1 connection", Tags: []string{check.TagXminHorizon}})
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	var saved bytes.Buffer
+	require.NoError(t, formatJSON(&saved, sampleRun(), []*check.Report{horizon, idle}))
+	require.NoError(t, os.WriteFile(path, saved.Bytes(), 0o600))
+
+	render := func(args ...string) string {
+		var out bytes.Buffer
+		cmd := newRootCommand("test")
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(append([]string{"render", "--input", path}, args...))
+		require.NoError(t, cmd.Execute())
+		return out.String()
+	}
+
+	assert.NotContains(t, render(), "See also:", "findings are only linked with --see-also")
+
+	text := render("--see-also")
+	assert.Contains(t, text, "  held by a session\n  See also: connection-health/idle-in-transaction\n")
+	assert.Contains(t, text, "  1 connection\n  See also: xmin-horizon\n")
+
+	assert.Contains(t, render("--see-also", "--output", "json"), `"see_also": [
+            "xmin-horizon"
+          ]`)
+}
+
+func TestRenderCommand_Glyphs(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	var saved bytes.Buffer
+	require.NoError(t, formatJSON(&saved, sampleRun(), sampleReports()))
+	require.NoError(t, os.WriteFile(path, saved.Bytes(), 0o600))
+
+	render := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		cmd := newRootCommand("test")
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(append([]string{"render", "--input", path}, args...))
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	text, _ := render()
+	assert.Contains(t, text, "[FAIL] Table Bloat")
+	assert.NotContains(t, text, "Legend:")
+
+	text, _ = render("--glyphs", "emoji")
+	assert.Contains(t, text, "❌ FAIL Table Bloat")
+	assert.Contains(t, text, "Database Health Check: app\nLegend: ✅ PASS ok")
+
+	for _, args := range [][]string{{"--glyphs", "unicode"}, {"--glyphs", "nerd", "--output", "json"}} {
+		_, err := render(args...)
+		var silent *SilentError
+		require.ErrorAs(t, err, &silent, "%v", args)
+		assert.Equal(t, 1, silent.ExitCode)
+	}
+}
+
+func TestRenderCommand_FullConflictsWithWidth(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	var saved bytes.Buffer
+	require.NoError(t, formatJSON(&saved, sampleRun(), sampleReports()))
+	require.NoError(t, os.WriteFile(path, saved.Bytes(), 0o600))
+
+	cmd := newRootCommand("test")
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"render", "--input", path, "--full", "--width", "80"})
+	var silent *SilentError
+	require.ErrorAs(t, cmd.Execute(), &silent, "--full turns --width off, so both is a mistake")
+	assert.Equal(t, 1, silent.ExitCode)
+}
+
+func TestRenderCommand_JSONFromStdin(t *testing.T) {
+	t.Parallel()
+
+	var saved bytes.Buffer
+	require.NoError(t, formatJSON(&saved, sampleRun(), sampleReports()))
+
+	var out bytes.Buffer
+	cmd := newRootCommand("test")
+	cmd.SetIn(bytes.NewReader(saved.Bytes()))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"render", "--input", "-", "--output", "json", "--sort", "id"})
+	require.NoError(t, cmd.Execute(), "JSON output does not carry the exit status")
+
+	_, decoded, err := decodeJSON(&out)
+	require.NoError(t, err)
+	var ids []string
+	for _, r := range decoded {
+		ids = append(ids, r.CheckID)
+	}
+	assert.Equal(t, []string{"index-usage", "pg-version", "statements-reset", "table-bloat", "temp-usage"}, ids)
+}
+
+func TestRenderCommand_RequiresInput(t *testing.T) {
+	t.Parallel()
+
+	cmd := newRootCommand("test")
+	cmd.SetArgs([]string{"render"})
+	require.ErrorContains(t, cmd.Execute(), `required flag(s) "input" not set`)
+}
+
+func TestRenderCommand_PanickedCheck(t *testing.T) {
+	t.Parallel()
+
+	panicked := check.NewReport(check.Metadata{CheckID: "index-bloat", Name: "Index Bloat", Category: check.CategoryIndexes})
+	panicked.Severity = check.SeveritySkip
+	panicked.AddFinding(check.Finding{ID: pgdoctor.PanicFindingID, Severity: check.SeveritySkip, Details: "check panicked: boom"})
+	reports := append(sampleReports(), panicked)
+
+	var saved bytes.Buffer
+	require.NoError(t, formatJSON(&saved, sampleRun(), reports))
+
+	var out bytes.Buffer
+	cmd := newRootCommand("test")
+	cmd.SetIn(bytes.NewReader(saved.Bytes()))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"render", "--input", "-"})
+
+	var silent *SilentError
+	require.ErrorAs(t, cmd.Execute(), &silent)
+	assert.Equal(t, exitInternalError, silent.ExitCode, "a panic outranks the failing check")
+	assert.Contains(t, out.String(), "[FAIL] Table Bloat (table-bloat)", "the other checks are still printed")
+	assert.Contains(t, out.String(), "check panicked: boom")
+}
+
+func TestRenderCommand_ErroredCheck(t *testing.T) {
+	t.Parallel()
+
+	errored := check.NewReport(check.Metadata{CheckID: "index-bloat", Name: "Index Bloat", Category: check.CategoryIndexes})
+	errored.Severity = check.SeveritySkip
+	errored.Errored = true
+	errored.AddFinding(check.Finding{ID: pgdoctor.ErrorFindingID, Name: "Check Error", Severity: check.SeveritySkip, Details: "permission denied for table pg_statistic"})
+	reports := append(sampleReports(), errored)
+
+	var saved bytes.Buffer
+	require.NoError(t, formatJSON(&saved, sampleRun(), reports))
+	assert.Equal(t, 1, strings.Count(saved.String(), `"errored": true`), "only the errored check is marked, not the skipped one")
+
+	var out bytes.Buffer
+	cmd := newRootCommand("test")
+	cmd.SetIn(bytes.NewReader(saved.Bytes()))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"render", "--input", "-", "--detail", "summary"})
+
+	var silent *SilentError
+	require.ErrorAs(t, cmd.Execute(), &silent)
+	assert.Equal(t, exitCheckError, silent.ExitCode, "an errored check outranks the failing check")
+	assert.Contains(t, out.String(), "[FAIL] Table Bloat (table-bloat)", "the other checks are still printed")
+	assert.Contains(t, out.String(), "2 passed, 1 errored, 1 skipped", "the timed out check is only skipped")
+}
+
+func TestPrintCheckReport_PanicStackAtDebug(t *testing.T) {
+	t.Parallel()
+
+	report := check.NewReport(check.Metadata{CheckID: "index-bloat", Name: "Index Bloat", Category: check.CategoryIndexes})
+	report.Severity = check.SeveritySkip
+	report.AddFinding(check.Finding{ID: pgdoctor.PanicFindingID, Severity: check.SeveritySkip, Details: "check panicked: boom", Debug: "goroutine 1 [running]:"})
+
+	var buf bytes.Buffer
+	printCheckReport(&buf, report, &runOptions{detail: string(detailVerbose)})
+	assert.NotContains(t, buf.String(), "goroutine")
+
+	buf.Reset()
+	printCheckReport(&buf, report, &runOptions{detail: string(detailDebug)})
+	assert.Contains(t, buf.String(), "Debug:\n    goroutine 1 [running]:")
+}
+
+func TestRenderCommand_Compact(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	var saved bytes.Buffer
+	require.NoError(t, formatJSON(&saved, sampleRun(), sampleReports()))
+	require.NoError(t, os.WriteFile(path, saved.Bytes(), 0o600))
+
+	var out bytes.Buffer
+	cmd := newRootCommand("test")
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"render", "--input", path, "--compact", "--width", "0"})
+	var silent *SilentError
+	require.ErrorAs(t, cmd.Execute(), &silent, "the saved run has a failing check")
+
+	text := out.String()
+	assert.Contains(t, text, "[FAIL] table-bloat: 1 table (+1 more)\n")
+	assert.Contains(t, text, "[SKIP] temp-usage: query cancelled by statement_timeout\n")
+	assert.Contains(t, text, "Summary: 1 failures, 2 passed, 1 skipped, 1 not applicable")
+	assert.NotContains(t, text, "Database Health Check")
+	assert.NotContains(t, text, "public.events")
+	assert.NotContains(t, text, "To see more")
+
+	for _, args := range [][]string{{"--full"}, {"--detail", "verbose"}, {"--output", "json"}} {
+		cmd := newRootCommand("test")
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetArgs(append([]string{"render", "--input", path, "--compact"}, args...))
+		require.ErrorAs(t, cmd.Execute(), &silent, "--compact with %v", args)
+		assert.Equal(t, 1, silent.ExitCode)
+	}
+}
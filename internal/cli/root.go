@@ -3,11 +3,18 @@ package cli
 import (
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 func Execute(version string) error {
+	return newRootCommand(version).Execute()
+}
+
+func newRootCommand(version string) *cobra.Command {
+	opts := &runOptions{}
+
 	cmd := &cobra.Command{
-		Use:   "pgdoctor",
+		Use:   "pgdoctor [check-id...] [DSN]",
 		Short: "Checks for best-practice PostgreSQL databases",
 		Long: `pgdoctor implements a suite of checks that run against PostgreSQL
 databases, highlighting any potential issues or action items that the database
@@ -15,7 +22,19 @@ owner may want to address in order to ensure the health & scalability of their
 database.
 
 The list of checks is not exhaustive, but is a good baseline standard that
-all production databases should pass.`,
+all production databases should pass.
+
+Run a few checks by naming them: "pgdoctor table-bloat index-bloat" is short
+for "pgdoctor run --only table-bloat,index-bloat" and accepts the same flags.
+The connection string can follow the check IDs or come from PGDOCTOR_DSN.`,
+		// Arguments that are not subcommands are check IDs.
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return cmd.Help()
+			}
+			return runCheckArgs(cmd, opts, args)
+		},
 		Version:           version,
 		SilenceUsage:      true,
 		SilenceErrors:     true,
@@ -25,6 +44,10 @@ all production databases should pass.`,
 		},
 	}
 
+	// The flags of run also apply to the shorthand, but are documented there.
+	addRunFlags(cmd, opts)
+	cmd.Flags().VisitAll(func(f *pflag.Flag) { f.Hidden = true })
+
 	var noColor bool
 	cmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output")
 	cmd.PersistentFlags().BoolVar(&noColor, "no-colour", false, "Disable colored output")
@@ -39,10 +62,12 @@ all production databases should pass.`,
 	cmd.AddCommand(newRunCommand())
 	cmd.AddCommand(newListCommand())
 	cmd.AddCommand(newExplainCommand())
+	cmd.AddCommand(newSchemaCommand())
+	cmd.AddCommand(newRenderCommand())
 
 	cmd.SetHelpCommand(&cobra.Command{Hidden: true})
 
-	return cmd.Execute()
+	return cmd
 }
 
 // SilentError is an error that has already been reported to the user.
@@ -2,10 +2,12 @@ package cli
 
 import (
 	"fmt"
+	"io"
 	"net/url"
 	"os"
-	"sort"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/spf13/cobra"
@@ -23,13 +25,81 @@ const (
 	detailDebug   detailLevel = "debug"
 )
 
+// Values of --output.
+const (
+	outputText        = "text"
+	outputJSON        = "json"
+	outputOpenMetrics = "openmetrics"
+	outputTemplate    = "template"
+	outputJUnit       = "junit"
+	outputCSV         = "csv"
+	outputSummaryJSON = "summary-json"
+)
+
+type groupBy string
+
+const (
+	groupByNone     groupBy = "none"
+	groupByCategory groupBy = "category"
+)
+
+type redactMode string
+
+const (
+	redactNone        redactMode = "none"
+	redactQueries     redactMode = "queries"
+	redactIdentifiers redactMode = "identifiers"
+)
+
+// redactModes maps --redact values to the runner's redaction level.
+var redactModes = map[redactMode]check.Redaction{
+	redactNone:        check.RedactNone,
+	redactQueries:     check.RedactQueries,
+	redactIdentifiers: check.RedactIdentifiers,
+}
+
 type runOptions struct {
-	ignored     []string
-	only        []string
-	preset      string
-	detail      string
-	hidePassing bool
-	output      string
+	ignored          []string
+	only             []string
+	preset           string
+	profile          string
+	checksDir        string
+	configFile       string
+	detail           string
+	hidePassing      bool
+	collapse         bool
+	groupBy          string
+	sortBy           string
+	output           string
+	templateFile     string
+	outputDir        string             // --output csv
+	tmpl             *template.Template // parsed --template
+	baseline         string
+	startedAt        time.Time // when the run started, which --baseline ages findings against
+	redact           string
+	explain          bool
+	reasons          bool
+	seeAlso          bool // --see-also
+	failFast         bool
+	syslog           bool
+	syslogTag        string
+	syslogFacility   string
+	glyphs           glyphSet
+	probeFDW         bool
+	width            int
+	full             bool
+	compact          bool
+	tagFilter        []string
+	top              int
+	queryTimeout     time.Duration
+	allDatabases     bool
+	concurrency      int
+	heavyConcurrency int
+	locale           string
+	tags             []string
+	configArgs       []string // --arg <check-id>.<key>=<value>
+	cpuProfile       string
+	memProfile       string
 }
 
 func newRunCommand() *cobra.Command {
@@ -56,147 +126,554 @@ the level of detail, and --hide-passing to only show failures and warnings.`,
 				return fmt.Errorf("connection string required: pgdoctor run <DSN> or set PGDOCTOR_DSN environment variable")
 			}
 
-			// Default to 'brief' detail when --only is used
-			if len(opts.only) > 0 && !cmd.Flags().Changed("detail") {
-				opts.detail = string(detailBrief)
-			}
+			return runChecks(cmd, opts, dsn)
+		},
+	}
 
-			ctx := cmd.Context()
+	addRunFlags(cmd, opts)
 
-			conn, err := pgx.Connect(ctx, dsn)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: failed to connect to database: %v\n", err)
-				return &SilentError{ExitCode: 2}
-			}
-			defer conn.Close(ctx)
+	return cmd
+}
 
-			// Set statement_timeout so PostgreSQL kills individual slow queries.
-			if _, err := conn.Exec(ctx, fmt.Sprintf("SET statement_timeout = %d", pgdoctor.DefaultStatementTimeoutMs)); err != nil {
-				fmt.Fprintf(os.Stderr, "Error: failed to set statement_timeout: %v\n", err)
-				return &SilentError{ExitCode: 2}
-			}
+// runChecks runs the selected checks against dsn and prints the reports. It
+// backs both run and the bare check ID shorthand on the root command.
+func runChecks(cmd *cobra.Command, opts *runOptions, dsn string) error {
+	// Default to 'brief' detail when --only is used
+	if len(opts.only) > 0 && !cmd.Flags().Changed("detail") {
+		opts.detail = string(detailBrief)
+	}
 
-			allChecks := pgdoctor.AllChecks()
+	if err := validateOutputOptions(cmd, opts); err != nil {
+		return err
+	}
 
-			// Apply preset filter
-			if opts.preset != presetAll {
-				presetChecks := getPresetChecks(opts.preset)
-				if len(opts.only) == 0 {
-					opts.only = presetChecks
-				} else {
-					opts.only = intersect(opts.only, presetChecks)
-				}
-			}
+	if opts.queryTimeout < 0 {
+		fmt.Fprintf(os.Stderr, "Error: --query-timeout must be 0 or more, got %s\n", opts.queryTimeout)
+		return &SilentError{ExitCode: 1}
+	}
 
-			// Validate and apply filters
-			validOnly, invalidOnly := pgdoctor.ValidateFilters(allChecks, opts.only)
-			validIgnored, invalidIgnored := pgdoctor.ValidateFilters(allChecks, opts.ignored)
+	if opts.concurrency < 1 {
+		fmt.Fprintf(os.Stderr, "Error: --concurrency must be 1 or more, got %d\n", opts.concurrency)
+		return &SilentError{ExitCode: 1}
+	}
+	if opts.concurrency > 1 && !opts.allDatabases {
+		fmt.Fprintln(os.Stderr, "Error: --concurrency requires --all-databases")
+		return &SilentError{ExitCode: 1}
+	}
+	if opts.heavyConcurrency < 1 {
+		fmt.Fprintf(os.Stderr, "Error: --heavy-concurrency must be 1 or more, got %d\n", opts.heavyConcurrency)
+		return &SilentError{ExitCode: 1}
+	}
+	if cmd.Flags().Changed("heavy-concurrency") && !opts.allDatabases {
+		fmt.Fprintln(os.Stderr, "Error: --heavy-concurrency requires --all-databases")
+		return &SilentError{ExitCode: 1}
+	}
 
-			var allInvalid []string
-			allInvalid = append(allInvalid, invalidOnly...)
-			allInvalid = append(allInvalid, invalidIgnored...)
+	tags, err := parseTags(opts.tags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return &SilentError{ExitCode: 1}
+	}
 
-			if len(allInvalid) > 0 {
-				fmt.Fprintf(os.Stderr, "Warning: ignoring invalid filter(s): %v\n\n", allInvalid)
-			}
+	argCfg, err := parseConfigArgs(opts.configArgs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return &SilentError{ExitCode: 1}
+	}
 
-			if len(opts.only) > 0 && len(validOnly) == 0 {
-				fmt.Fprintf(os.Stderr, "Error: no valid checks found for --only filter(s): %v\n", invalidOnly)
-				return &SilentError{ExitCode: 1}
-			}
+	numberFormat, err := parseLocale(opts.locale)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return &SilentError{ExitCode: 1}
+	}
+	check.SetNumberFormat(numberFormat)
 
-			checks := pgdoctor.Filter(allChecks, validOnly, validIgnored)
-			sortChecksByCategory(checks)
+	redaction, ok := redactModes[redactMode(opts.redact)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: unknown --redact %q (valid: %s, %s, %s)\n", opts.redact, redactNone, redactQueries, redactIdentifiers)
+		return &SilentError{ExitCode: 1}
+	}
 
-			runOpts := pgdoctor.Options{
-				Checks: checks,
-			}
+	logger, err := openSyslog(opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return &SilentError{ExitCode: 1}
+	}
+	if logger != nil {
+		defer logger.Close()
+	}
 
-			// JSON output: batch collect then render
-			if opts.output == "json" {
-				var reports []*check.Report
-				runOpts.OnReport = pgdoctor.Collect(&reports)
-				pgdoctor.Run(ctx, conn, runOpts)
-
-				w := cmd.OutOrStdout()
-				if err := formatJSON(w, reports); err != nil {
-					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-					return &SilentError{ExitCode: 1}
-				}
-				return nil
-			}
+	// The plan lands in Finding.Debug, which only the text output shows.
+	if opts.explain && (opts.detail != string(detailDebug) || opts.output != outputText) {
+		fmt.Fprintln(os.Stderr, "Error: --explain requires --detail debug and text output")
+		return &SilentError{ExitCode: 1}
+	}
+
+	cfg, err := loadProfile(opts.profile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return &SilentError{ExitCode: 1}
+	}
+	fileCfg, err := loadConfigFile(opts.configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return &SilentError{ExitCode: 1}
+	}
+	cfg = cfg.Merge(fileCfg).Merge(argCfg)
+	base, err := loadBaseline(opts.baseline)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return &SilentError{ExitCode: 1}
+	}
+	if opts.explain {
+		cfg = cfg.Merge(check.Config{"table-seq-scans": {"explain": "true"}})
+	}
+	if opts.probeFDW {
+		cfg = cfg.Merge(check.Config{"fdw-health": {"probe": "true"}})
+	}
 
-			// Text output: stream results with category headers
-			w := cmd.OutOrStdout()
-			dbLabel := parseDSNLabel(dsn)
-			fmt.Fprintf(w, "Database Health Check: %s\n\n", dbLabel)
-
-			var reports []*check.Report
-			var currentCategory string
-			maxSeverity := check.SeverityOK
-
-			runOpts.OnReport = func(r *check.Report) {
-				reports = append(reports, r)
-				if r.Severity > maxSeverity {
-					maxSeverity = r.Severity
-				}
-
-				// Print category header on transition
-				cat := string(r.Category)
-				if cat != currentCategory {
-					if currentCategory != "" {
-						fmt.Fprintln(w)
-					}
-					title := strings.ToUpper(cat)
-					fmt.Fprintln(w, title)
-					fmt.Fprintln(w, strings.Repeat("─", len(title)))
-					currentCategory = cat
-				}
-
-				if r.Severity == check.SeverityOK && opts.hidePassing {
-					return
-				}
-
-				if opts.detail == string(detailSummary) {
-					printCheckSummary(w, r, opts)
-				} else {
-					printCheckReport(w, r, opts)
-				}
+	allChecks, err := availableChecks(opts.checksDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return &SilentError{ExitCode: 1}
+	}
+	if unknown := unknownConfigChecks(argCfg, allChecks); len(unknown) > 0 {
+		var ids []string
+		for _, pkg := range allChecks {
+			ids = append(ids, pkg.Metadata().CheckID)
+		}
+		for _, name := range unknown {
+			if suggestions := suggest(ids, name); len(suggestions) > 0 {
+				fmt.Fprintf(os.Stderr, "Error: --arg: unknown check %q (did you mean %s?)\n", name, strings.Join(suggestions, ", "))
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: --arg: unknown check %q\n", name)
 			}
-			pgdoctor.Run(ctx, conn, runOpts)
+		}
+		fmt.Fprintln(os.Stderr, "Run 'pgdoctor list' to see all checks.")
+		return &SilentError{ExitCode: 1}
+	}
 
-			fmt.Fprintln(w)
-			printSummary(w, reports)
+	stopProfiles, err := startProfiles(opts.cpuProfile, opts.memProfile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return &SilentError{ExitCode: 1}
+	}
+	defer stopProfiles()
 
-			if opts.detail == string(detailSummary) || opts.detail == string(detailBrief) {
-				dimFunc := dimColor()
-				fmt.Fprintf(w, "%s\n", dimFunc("To see more: pgdoctor run ... --detail verbose"))
-				fmt.Fprintf(w, "%s\n", dimFunc("To see how to fix: pgdoctor explain <check-id>"))
-				fmt.Fprintln(w)
-			}
+	ctx := cmd.Context()
+
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to connect to database: %v\n", err)
+		return &SilentError{ExitCode: 2}
+	}
+	defer conn.Close(ctx)
+
+	timeoutMs := statementTimeoutMs(opts.queryTimeout)
+	if err := setStatementTimeout(ctx, conn, timeoutMs); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return &SilentError{ExitCode: 2}
+	}
+
+	// Record the selection as given, before the preset narrows it.
+	run := runInfo{
+		version:       cmd.Root().Version,
+		serverVersion: conn.PgConn().ParameterStatus("server_version"),
+		database:      conn.Config().Database,
+		redact:        opts.redact,
+		preset:        opts.preset,
+		profile:       opts.profile,
+		only:          opts.only,
+		ignored:       opts.ignored,
+		tags:          tags,
+	}
+
+	// Apply preset filter
+	if opts.preset != presetAll {
+		presetChecks := getPresetChecks(opts.preset)
+		if len(opts.only) == 0 {
+			opts.only = presetChecks
+		} else {
+			opts.only = intersect(opts.only, presetChecks)
+		}
+	}
+
+	// Validate and apply filters
+	validOnly, invalidOnly := pgdoctor.ValidateFilters(allChecks, opts.only)
+	validIgnored, invalidIgnored := pgdoctor.ValidateFilters(allChecks, opts.ignored)
 
-			if maxSeverity == check.SeverityFail {
-				return &SilentError{ExitCode: 1}
+	var allInvalid []string
+	allInvalid = append(allInvalid, invalidOnly...)
+	allInvalid = append(allInvalid, invalidIgnored...)
+
+	if len(allInvalid) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring invalid filter(s): %v\n\n", allInvalid)
+	}
+
+	if len(opts.only) > 0 && len(validOnly) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: no valid checks found for --only filter(s): %v\n", invalidOnly)
+		return &SilentError{ExitCode: 1}
+	}
+
+	checks := pgdoctor.Filter(allChecks, validOnly, validIgnored)
+	// Checks named in --only run even when the config disables them;
+	// preset checks do not.
+	forced, _ := pgdoctor.ValidateFilters(allChecks, run.only)
+	checks = pgdoctor.FilterDisabled(checks, cfg, forced)
+	sortChecks(checks, sortOrder(opts.sortBy))
+
+	runOpts := pgdoctor.Options{
+		Checks: checks,
+		Config: cfg,
+		Redact: redaction,
+		// Finding.Debug is only shown by the text output at debug detail.
+		RecordQueries: opts.detail == string(detailDebug) && opts.output == outputText,
+	}
+	if redaction >= check.RedactIdentifiers {
+		run.database = redactedLabel
+	}
+
+	if opts.allDatabases {
+		// Databases checked at once would otherwise scan their tables at the
+		// same time, competing for the I/O of the server being diagnosed.
+		runOpts.HeavyLimit = pgdoctor.NewHeavyLimit(opts.heavyConcurrency)
+	}
+
+	// --fail-fast cancels the checks' context, not the connection's.
+	runCtx, stop := ctx, (*failFast)(nil)
+	if opts.failFast {
+		runCtx, stop = newFailFast(ctx)
+	}
+	runAll := func() error {
+		if stop != nil {
+			runOpts.OnReport = stop.wrap(runOpts.OnReport)
+		}
+		var err error
+		if opts.allDatabases {
+			err = runAllDatabases(runCtx, conn, runOpts, timeoutMs, opts.concurrency)
+		} else {
+			pgdoctor.Run(runCtx, conn, runOpts)
+		}
+		if stop != nil {
+			stop.record(&run)
+		}
+		return err
+	}
+
+	// JSON, OpenMetrics, template, and JUnit output: batch collect then render
+	if opts.output != outputText {
+		for _, c := range checks {
+			run.checks = append(run.checks, c.Metadata().CheckID)
+		}
+
+		var reports []*check.Report
+		runOpts.OnReport = pgdoctor.Collect(&reports)
+		run.startedAt = time.Now()
+		if err := runAll(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return &SilentError{ExitCode: 2}
+		}
+		run.duration = time.Since(run.startedAt)
+		reports = base.annotateAll(filterReportsTagged(reports, opts.tagFilter), run.startedAt)
+		if opts.seeAlso {
+			reports = pgdoctor.LinkRelated(reports)
+		}
+		sortReports(reports, sortOrder(opts.sortBy))
+
+		if err := formatReports(cmd.OutOrStdout(), opts, run, reports); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return &SilentError{ExitCode: 1}
+		}
+		syslogErr := logReports(logger, reports)
+		if note := stoppedEarlyNote(run); note != "" {
+			fmt.Fprintln(os.Stderr, note)
+			if err := incompleteError(reports); err != nil {
+				return err
 			}
+			return &SilentError{ExitCode: 1}
+		}
+		if err := incompleteError(reports); err != nil {
+			return err
+		}
+		return syslogErr
+	}
 
-			return nil
-		},
+	// Text output: stream results with category headers
+	w := cmd.OutOrStdout()
+	dbLabel := parseDSNLabel(dsn)
+	if redaction >= check.RedactIdentifiers {
+		dbLabel = redactedLabel
+	}
+	if !opts.compact {
+		printHeader(w, dbLabel, tags, opts.glyphs)
 	}
 
+	var reports []*check.Report
+	printer := &textPrinter{w: w, opts: opts}
+
+	// Category order matches the run order, so reports stream as they
+	// complete; other orders, --top, --see-also, and --all-databases
+	// (grouped by database name) print once every check has finished.
+	stream := sortOrder(opts.sortBy) == sortCategory && opts.top == 0 && !opts.seeAlso && !opts.allDatabases
+	opts.startedAt = time.Now()
+	runOpts.OnReport = func(r *check.Report) {
+		if r = filterTagged(r, opts.tagFilter); r == nil {
+			return
+		}
+		r = base.annotate(r, opts.startedAt)
+		reports = append(reports, r)
+		if stream {
+			printer.print(r)
+		}
+	}
+	if err := runAll(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return &SilentError{ExitCode: 2}
+	}
+	if opts.seeAlso {
+		reports = pgdoctor.LinkRelated(reports)
+	}
+	if !stream {
+		printer.printAll(reports)
+	}
+	printer.flush()
+	printStoppedEarly(w, run)
+	printFooter(w, reports, opts, "pgdoctor run ...")
+
+	syslogErr := logReports(logger, reports)
+	if err := failedError(reports); err != nil {
+		return err
+	}
+	return syslogErr
+}
+
+// validateOutputOptions checks the flags that shape the output, shared by run
+// and render, and resolves the default --width.
+func validateOutputOptions(cmd *cobra.Command, opts *runOptions) error {
+	if opts.groupBy != string(groupByNone) && opts.groupBy != string(groupByCategory) {
+		fmt.Fprintf(os.Stderr, "Error: unknown --group-by %q (valid: %s, %s)\n", opts.groupBy, groupByNone, groupByCategory)
+		return &SilentError{ExitCode: 1}
+	}
+
+	if _, ok := sortOrders[sortOrder(opts.sortBy)]; !ok {
+		fmt.Fprintf(os.Stderr, "Error: unknown --sort %q (valid: %s, %s, %s)\n", opts.sortBy, sortCategory, sortID, sortSeverity)
+		return &SilentError{ExitCode: 1}
+	}
+	if opts.groupBy == string(groupByCategory) && !opts.groupsByCategory() {
+		fmt.Fprintf(os.Stderr, "Error: --group-by %s requires --sort %s\n", groupByCategory, sortCategory)
+		return &SilentError{ExitCode: 1}
+	}
+
+	switch opts.output {
+	case outputText, outputJSON, outputOpenMetrics, outputTemplate, outputJUnit, outputCSV, outputSummaryJSON:
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown --output %q (valid: %s, %s, %s, %s, %s, %s, %s)\n",
+			opts.output, outputText, outputJSON, outputOpenMetrics, outputTemplate, outputJUnit, outputCSV, outputSummaryJSON)
+		return &SilentError{ExitCode: 1}
+	}
+	if (opts.output == outputCSV) != (opts.outputDir != "") {
+		fmt.Fprintf(os.Stderr, "Error: --output %s and --output-dir go together\n", outputCSV)
+		return &SilentError{ExitCode: 1}
+	}
+	if (opts.output == outputTemplate) != (opts.templateFile != "") {
+		fmt.Fprintf(os.Stderr, "Error: --output %s and --template go together\n", outputTemplate)
+		return &SilentError{ExitCode: 1}
+	}
+	if opts.templateFile != "" {
+		tmpl, err := parseTemplate(opts.templateFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return &SilentError{ExitCode: 1}
+		}
+		opts.tmpl = tmpl
+	}
+
+	if opts.top < 0 {
+		fmt.Fprintf(os.Stderr, "Error: --top must be 0 or more, got %d\n", opts.top)
+		return &SilentError{ExitCode: 1}
+	}
+	if opts.top > 0 && opts.output != outputText {
+		fmt.Fprintln(os.Stderr, "Error: --top requires text output")
+		return &SilentError{ExitCode: 1}
+	}
+	if _, ok := severityGlyphs[opts.glyphs]; !ok && opts.glyphs != glyphsASCII {
+		fmt.Fprintf(os.Stderr, "Error: unknown --glyphs %q (valid: %s, %s, %s)\n", opts.glyphs, glyphsASCII, glyphsEmoji, glyphsNerd)
+		return &SilentError{ExitCode: 1}
+	}
+	if opts.glyphs != glyphsASCII && opts.output != outputText {
+		fmt.Fprintln(os.Stderr, "Error: --glyphs requires text output")
+		return &SilentError{ExitCode: 1}
+	}
+	if opts.reasons && opts.output != outputText {
+		fmt.Fprintln(os.Stderr, "Error: --reasons requires text output; JSON output always includes the reasons")
+		return &SilentError{ExitCode: 1}
+	}
+
+	if opts.width < 0 {
+		fmt.Fprintf(os.Stderr, "Error: --width must be 0 or more, got %d\n", opts.width)
+		return &SilentError{ExitCode: 1}
+	}
+	if opts.compact {
+		switch {
+		case opts.output != outputText:
+			fmt.Fprintln(os.Stderr, "Error: --compact requires text output")
+			return &SilentError{ExitCode: 1}
+		case opts.full:
+			fmt.Fprintln(os.Stderr, "Error: --compact and --full cannot be used together")
+			return &SilentError{ExitCode: 1}
+		case cmd.Flags().Changed("detail"):
+			fmt.Fprintln(os.Stderr, "Error: --compact and --detail cannot be used together")
+			return &SilentError{ExitCode: 1}
+		}
+	}
+	if opts.full && cmd.Flags().Changed("width") {
+		fmt.Fprintln(os.Stderr, "Error: --full and --width cannot be used together")
+		return &SilentError{ExitCode: 1}
+	}
+	if !opts.full && !cmd.Flags().Changed("width") {
+		opts.width = terminalWidth(os.Stdout)
+	}
+
+	return nil
+}
+
+// formatReports writes reports in one of the batch formats: json,
+// summary-json, openmetrics, template, junit, or csv, which writes to
+// --output-dir instead of w.
+func formatReports(w io.Writer, opts *runOptions, run runInfo, reports []*check.Report) error {
+	switch opts.output {
+	case outputCSV:
+		return formatCSV(opts.outputDir, reports)
+	case outputOpenMetrics:
+		return formatOpenMetrics(w, run, reports)
+	case outputTemplate:
+		return formatTemplate(w, opts.tmpl, run, reports)
+	case outputJUnit:
+		return formatJUnit(w, run, reports)
+	case outputSummaryJSON:
+		return formatSummaryJSON(w, run, reports)
+	default:
+		return formatJSON(w, run, reports)
+	}
+}
+
+// exitInternalError is the exit code when a check panicked, so its results
+// are missing from the output.
+const exitInternalError = 3
+
+// exitCheckError is the exit code when a check returned an error, such as a
+// permission error, so its results are missing from the output.
+const exitCheckError = 4
+
+// failedError is the exit status of a text run or render: exit code 1 when any
+// check failed, unless a check's results are missing (see incompleteError).
+func failedError(reports []*check.Report) error {
+	if err := incompleteError(reports); err != nil {
+		return err
+	}
+	for _, r := range reports {
+		if r.Severity == check.SeverityFail {
+			return &SilentError{ExitCode: 1}
+		}
+	}
+	return nil
+}
+
+// incompleteError reports the checks whose results are missing on stderr and
+// returns exitInternalError when one panicked, exitCheckError when one
+// returned an error, or nil when every check ran.
+func incompleteError(reports []*check.Report) error {
+	var panicked, errored []string
+	for _, r := range reports {
+		switch {
+		case pgdoctor.Panicked(r):
+			panicked = append(panicked, checkLabel(r))
+		case pgdoctor.Errored(r):
+			errored = append(errored, checkLabel(r))
+		}
+	}
+	if len(errored) > 0 {
+		fmt.Fprintf(os.Stderr, "Error: %d check(s) errored, their results are missing: %s\n", len(errored), strings.Join(errored, ", "))
+	}
+	if len(panicked) > 0 {
+		fmt.Fprintf(os.Stderr, "Error: check(s) panicked, their results are missing: %s\n", strings.Join(panicked, ", "))
+		fmt.Fprintln(os.Stderr, "This is a bug in pgdoctor; --detail debug shows the stack trace to include in a report.")
+		return &SilentError{ExitCode: exitInternalError}
+	}
+	if len(errored) > 0 {
+		return &SilentError{ExitCode: exitCheckError}
+	}
+	return nil
+}
+
+// checkLabel names a report's check on stderr, with its database when the
+// run covered several.
+func checkLabel(r *check.Report) string {
+	if r.Database != "" {
+		return r.Database + "/" + r.CheckID
+	}
+	return r.CheckID
+}
+
+// addRunFlags registers the flags of run on cmd, bound to opts.
+func addRunFlags(cmd *cobra.Command, opts *runOptions) {
 	cmd.Flags().StringSliceVar(&opts.ignored, "ignore", nil, "Checks or categories to ignore")
 	cmd.Flags().StringSliceVar(&opts.only, "only", nil, "Only run these checks or categories")
-	cmd.Flags().StringVar(&opts.preset, "preset", presetAll, "Check preset: all (default), triage")
+	cmd.Flags().StringVar(&opts.preset, "preset", presetAll, "Check preset: all (default), triage, security")
+	cmd.Flags().StringVar(&opts.profile, "profile", profileDefault, "Recommended thresholds: default, oltp, olap")
+	cmd.Flags().StringVar(&opts.configFile, "config", os.Getenv(configFileEnv), "YAML file of per-check settings, layered over --profile (env: "+configFileEnv+")")
+	cmd.Flags().StringArrayVar(&opts.configArgs, "arg", nil, "Set a check's config key, <check-id>.<key>=<value> (repeatable), e.g. session-settings.timeout_warn=2000; layered over --config")
+	cmd.Flags().StringVar(&opts.checksDir, "checks-dir", os.Getenv(checksDirEnv), "Directory of external SQL-only checks (env: "+checksDirEnv+")")
 	cmd.Flags().StringVar(&opts.detail, "detail", string(detailBrief), "Detail level: summary, brief (default), verbose, debug")
 	cmd.Flags().BoolVar(&opts.hidePassing, "hide-passing", false, "Hide passing checks")
-	cmd.Flags().StringVar(&opts.output, "output", "text", "Output format: text (default), json")
-
-	return cmd
+	cmd.Flags().BoolVar(&opts.collapse, "collapse-passing", false, "Collapse passing checks into a count per category")
+	cmd.Flags().StringVar(&opts.groupBy, "group-by", string(groupByNone), "Grouping: none (default), category (colored category headers)")
+	cmd.Flags().StringVar(&opts.sortBy, "sort", string(sortCategory), "Report order: category (default, then check ID), id, severity (worst first)")
+	cmd.Flags().StringVar(&opts.output, "output", outputText, "Output format: text (default), json, summary-json, openmetrics, template, junit, csv")
+	cmd.Flags().StringVar(&opts.templateFile, "template", "", "Go text/template file for --output template, executed against the JSON document")
+	cmd.Flags().StringVar(&opts.outputDir, "output-dir", "", "Directory for --output csv: a <check-id>.csv per check table and a _summary.csv")
+	cmd.Flags().StringVar(&opts.baseline, "baseline", "", "Compare with a run saved by --output json, marking each warning and failure with when it was first seen")
+	cmd.Flags().StringSliceVar(&opts.tagFilter, "tag-filter", nil, "Only report findings tagged with one of these finding tags, e.g. wraparound")
+	cmd.Flags().StringArrayVar(&opts.tags, "tag", nil, "Tag the run with key=value (repeatable), e.g. env=prod; recorded in JSON, as OpenMetrics labels, and in the text header")
+	cmd.Flags().StringVar(&opts.locale, "locale", localeDefault, "Decimal and thousands separators of sizes and counts: en (default), de, es, fr, it, nl, pt")
+	cmd.Flags().StringVar(&opts.redact, "redact", string(redactNone), "Strip sensitive data: none (default), queries (SQL text), identifiers (SQL text and object names)")
+	cmd.Flags().Lookup("redact").NoOptDefVal = string(redactQueries)
+	cmd.Flags().BoolVar(&opts.probeFDW, "probe-fdw", false, "Let fdw-health read one row through a foreign table on each foreign server (connects to remote servers)")
+	cmd.Flags().BoolVar(&opts.failFast, "fail-fast", false, "Stop at the first failing check, skipping the rest, and exit 1; for quick yes/no gates")
+	cmd.Flags().BoolVar(&opts.syslog, "syslog", false, "Also send each warning and failure to the local syslog daemon, at priority warning or err (not on Windows)")
+	cmd.Flags().StringVar(&opts.syslogTag, "syslog-tag", "pgdoctor", "Program name of --syslog messages")
+	cmd.Flags().StringVar(&opts.syslogFacility, "syslog-facility", "user", "Facility of --syslog messages: user (default), daemon, local0 to local7")
+	cmd.Flags().IntVar(&opts.top, "top", 0, "List the N most urgent findings across all checks above the detailed output (text only)")
+	cmd.Flags().DurationVar(&opts.queryTimeout, "query-timeout", pgdoctor.DefaultStatementTimeoutMs*time.Millisecond, "statement_timeout for pgdoctor's own queries; slower checks are skipped (0 disables)")
+	cmd.Flags().IntVar(&opts.width, "width", 0, "Maximum text table width; longer cells are cut with … (default: terminal width, or $COLUMNS when not a TTY; 0 for no limit)")
+	cmd.Flags().BoolVar(&opts.full, "full", false, "Print every table row and full cell values in text output, regardless of --detail and terminal width")
+	cmd.Flags().BoolVar(&opts.compact, "compact", false, "Print one line per check in text output: its severity, ID, and a summary of its most severe finding")
+	cmd.Flags().BoolVar(&opts.allDatabases, "all-databases", false, "Run per-database checks in every database that accepts connections; cluster-wide checks run once")
+	cmd.Flags().IntVar(&opts.concurrency, "concurrency", 1, "With --all-databases, the number of databases checked at once, each over one connection")
+	cmd.Flags().IntVar(&opts.heavyConcurrency, "heavy-concurrency", 1, "With --all-databases, the number of heavy checks (scanning whole tables, like hot-chain-bloat) run at once across databases; others run with --concurrency")
+	cmd.Flags().BoolVar(&opts.reasons, "reasons", false, "Show the rule that set the severity of each warning and failure, such as a threshold it crossed (text only; JSON always has it)")
+	cmd.Flags().BoolVar(&opts.seeAlso, "see-also", false, "Link warnings and failures of different checks sharing a finding tag, e.g. xmin-horizon, listing the others under each")
+	cmd.Flags().StringVar((*string)(&opts.glyphs), "glyphs", string(glyphsASCII), "Severity markers in text output: ascii (default, [PASS]), emoji, nerd (Nerd Font icons); colored or glyph markers add a legend")
+	cmd.Flags().BoolVar(&opts.explain, "explain", false, "With --detail debug, EXPLAIN a pg_stat_statements query for the top table-seq-scans table (runs extra queries)")
+
+	// Profiling pgdoctor itself is for maintainers, so it stays out of help.
+	cmd.Flags().StringVar(&opts.cpuProfile, "cpuprofile", "", "Write a pprof CPU profile of the run to this file")
+	cmd.Flags().StringVar(&opts.memProfile, "memprofile", "", "Write a pprof heap profile, taken after the run, to this file")
+	_ = cmd.Flags().MarkHidden("cpuprofile")
+	_ = cmd.Flags().MarkHidden("memprofile")
 }
 
-func sortChecksByCategory(checks []check.Package) {
-	sort.SliceStable(checks, func(i, j int) bool {
-		return checks[i].Metadata().Category < checks[j].Metadata().Category
-	})
+// redactedLabel stands in for the database name when identifiers are redacted.
+const redactedLabel = "[redacted]"
+
+// statementTimeoutMs converts --query-timeout to statement_timeout
+// milliseconds, rounding sub-millisecond values up so they don't disable it.
+func statementTimeoutMs(d time.Duration) int64 {
+	ms := d.Milliseconds()
+	if d > 0 && ms == 0 {
+		return 1
+	}
+	return ms
 }
 
 // parseDSNLabel extracts a human-readable label from a DSN.
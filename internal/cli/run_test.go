@@ -0,0 +1,17 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatementTimeoutMs(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, int64(2000), statementTimeoutMs(2*time.Second))
+	assert.Equal(t, int64(90_000), statementTimeoutMs(90*time.Second))
+	assert.Equal(t, int64(0), statementTimeoutMs(0), "0 disables statement_timeout")
+	assert.Equal(t, int64(1), statementTimeoutMs(time.Microsecond), "sub-millisecond values must not disable it")
+}
@@ -0,0 +1,28 @@
+package cli
+
+import (
+	_ "embed"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// jsonSchema describes the output of formatJSON. Keep the two in sync;
+// schema_test.go validates real output against it.
+//
+//go:embed schema.json
+var jsonSchema string
+
+func newSchemaCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema for --output json",
+		Long: `Print the JSON Schema (draft 2020-12) describing the output of
+'pgdoctor run --output json', for consumers that want to validate it.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			fmt.Fprint(cmd.OutOrStdout(), jsonSchema)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,223 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func compileSchema(t *testing.T) *jsonschema.Schema {
+	t.Helper()
+
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(jsonSchema))
+	require.NoError(t, err)
+
+	c := jsonschema.NewCompiler()
+	require.NoError(t, c.AddResource("schema.json", doc))
+	schema, err := c.Compile("schema.json")
+	require.NoError(t, err)
+	return schema
+}
+
+func validateJSON(t *testing.T, schema *jsonschema.Schema, data []byte) error {
+	t.Helper()
+
+	inst, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	require.NoError(t, err)
+	return schema.Validate(inst)
+}
+
+// sampleReports covers every shape formatJSON can emit: all severities,
+// single and multi-finding checks, tables, and skipped and not applicable
+// checks.
+func sampleReports() []*check.Report {
+	single := check.NewReport(check.Metadata{CheckID: "pg-version", Name: "PostgreSQL Version", Category: check.CategoryConfigs})
+	single.AddFinding(check.Finding{ID: "pg-version", Name: "PostgreSQL Version", Severity: check.SeverityOK})
+
+	multi := check.NewReport(check.Metadata{CheckID: "table-bloat", Name: "Table Bloat", Category: check.CategoryVacuum})
+	multi.AddFinding(check.Finding{ID: "dead-tuples", Name: "Dead Tuples", Severity: check.SeverityWarn, Details: "2 tables"})
+	multi.AddFinding(check.Finding{
+		ID:       "bloat",
+		Name:     "Bloat",
+		Severity: check.SeverityFail,
+		Details:  "1 table",
+		Reason:   "bloat 72% > max 50% → FAIL",
+		Tags:     []string{"storage"},
+		Table: &check.Table{
+			Headers: []string{"Table", "Bloat"},
+			Rows: []check.TableRow{
+				{Cells: []string{"public.events", "72%"}, Severity: check.SeverityFail},
+				{Cells: []string{"public.users", "41%"}, Severity: check.SeverityWarn},
+			},
+		},
+	})
+
+	emptyTable := check.NewReport(check.Metadata{CheckID: "index-usage", Name: "Index Usage", Category: check.CategoryIndexes})
+	emptyTable.AddFinding(check.Finding{ID: "index-usage", Name: "Index Usage", Severity: check.SeverityOK, Table: &check.Table{}})
+
+	skipped := check.NewReport(check.Metadata{CheckID: "temp-usage", Name: "Temp Usage", Category: check.CategoryPerformance})
+	skipped.Severity = check.SeveritySkip
+	skipped.AddFinding(check.Finding{ID: "skipped", Name: "Check Skipped", Severity: check.SeveritySkip, Details: "query cancelled by statement_timeout"})
+
+	notApplicable := check.NewReport(check.Metadata{CheckID: "statements-reset", Name: "Statements Reset", Category: check.CategoryConfigs})
+	notApplicable.AddFinding(check.Finding{ID: "statements-reset", Name: "Statements Reset", Severity: check.SeverityNotApplicable, Details: "pg_stat_statements is not installed"})
+
+	return []*check.Report{single, multi, emptyTable, skipped, notApplicable}
+}
+
+func sampleRun() runInfo {
+	return runInfo{
+		version:       "v0.4.0",
+		startedAt:     time.Date(2026, 10, 14, 9, 30, 0, 0, time.UTC),
+		duration:      1500 * time.Millisecond,
+		serverVersion: "16.4",
+		database:      "app",
+		redact:        string(redactNone),
+		preset:        presetAll,
+		profile:       profileDefault,
+		only:          []string{"vacuum"},
+		checks:        []string{"pg-version", "table-bloat", "index-usage", "temp-usage"},
+	}
+}
+
+func TestJSONSchema_ValidatesOutput(t *testing.T) {
+	t.Parallel()
+
+	schema := compileSchema(t)
+
+	for name, reports := range map[string][]*check.Report{
+		"sample reports": sampleReports(),
+		"no reports":     nil,
+	} {
+		var buf bytes.Buffer
+		require.NoError(t, formatJSON(&buf, sampleRun(), reports))
+		assert.NoError(t, validateJSON(t, schema, buf.Bytes()), name)
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, formatJSON(&buf, runInfo{}, nil))
+	assert.NoError(t, validateJSON(t, schema, buf.Bytes()), "zero run info")
+}
+
+func TestFormatJSON_Envelope(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, formatJSON(&buf, sampleRun(), sampleReports()))
+
+	var out jsonRun
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+
+	assert.Equal(t, jsonSchemaVersion, out.SchemaVersion)
+	assert.Equal(t, "2026-10-14T09:30:00Z", out.StartedAt)
+	assert.Equal(t, int64(1500), out.DurationMs)
+	assert.Equal(t, "v0.4.0", out.PgdoctorVersion)
+	assert.Equal(t, "16.4", out.ServerVersion)
+	assert.Equal(t, "app", out.Database)
+	assert.Equal(t, []string{"vacuum"}, out.Selection.Only)
+	assert.Equal(t, []string{}, out.Selection.Ignore, "unset filters serialize as []")
+	assert.Len(t, out.Reports, 5)
+	assert.Equal(t, "n/a", out.Reports[4].Severity)
+}
+
+func TestJSONSchema_RejectsInvalidOutput(t *testing.T) {
+	t.Parallel()
+
+	schema := compileSchema(t)
+
+	// envelope wraps a reports array in otherwise valid run metadata.
+	envelope := func(durationMs, reports string) string {
+		return `{"schema_version":1,"pgdoctor_version":"dev","started_at":"2026-10-14T09:30:00Z","duration_ms":` + durationMs +
+			`,"server_version":"16.4","database":"app","redact":"none",` +
+			`"selection":{"preset":"all","profile":"default","only":[],"ignore":[],"checks":[]},"reports":` + reports + `}`
+	}
+
+	tests := map[string]string{
+		"unknown severity":   envelope("0", `[{"check_id":"a","name":"A","category":"configs","severity":"critical","results":[]}]`),
+		"missing results":    envelope("0", `[{"check_id":"a","name":"A","category":"configs","severity":"pass"}]`),
+		"unknown field":      envelope("0", `[{"check_id":"a","name":"A","category":"configs","severity":"pass","results":[],"extra":1}]`),
+		"row without cells":  envelope("0", `[{"check_id":"a","name":"A","category":"configs","severity":"warn","results":[{"id":"a","name":"A","severity":"warn","table":{"headers":[],"rows":[{"severity":"warn"}]}}]}]`),
+		"negative duration":  envelope("-1", `[]`),
+		"newer version":      strings.Replace(envelope("0", `[]`), `"schema_version":1`, `"schema_version":2`, 1),
+		"bare reports array": `[]`,
+	}
+
+	require.NoError(t, validateJSON(t, schema, []byte(envelope("0", `[]`))), "envelope itself must be valid")
+	for name, doc := range tests {
+		assert.Error(t, validateJSON(t, schema, []byte(doc)), name)
+	}
+}
+
+// TestJSONSchema_CoversEveryField fails when a field is added to (or removed
+// from) the JSON output types without updating schema.json.
+func TestJSONSchema_CoversEveryField(t *testing.T) {
+	t.Parallel()
+
+	var schema struct {
+		Defs map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"$defs"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(jsonSchema), &schema))
+
+	for def, typ := range map[string]reflect.Type{
+		"run":       reflect.TypeOf(jsonRun{}),
+		"selection": reflect.TypeOf(jsonSelection{}),
+		"report":    reflect.TypeOf(jsonReport{}),
+		"finding":   reflect.TypeOf(jsonFinding{}),
+		"table":     reflect.TypeOf(jsonTable{}),
+		"row":       reflect.TypeOf(jsonRow{}),
+	} {
+		var fields []string
+		for i := 0; i < typ.NumField(); i++ {
+			fields = append(fields, strings.Split(typ.Field(i).Tag.Get("json"), ",")[0])
+		}
+		var properties []string
+		for name := range schema.Defs[def].Properties {
+			properties = append(properties, name)
+		}
+		sort.Strings(fields)
+		sort.Strings(properties)
+		assert.Equal(t, fields, properties, "$defs/%s properties must match %s", def, typ.Name())
+	}
+}
+
+// TestJSONSchema_Version fails when jsonSchemaVersion is bumped without
+// updating schema.json to describe the new version.
+func TestJSONSchema_Version(t *testing.T) {
+	t.Parallel()
+
+	var schema struct {
+		Defs struct {
+			Run struct {
+				Properties struct {
+					SchemaVersion struct {
+						Const int `json:"const"`
+					} `json:"schema_version"`
+				} `json:"properties"`
+			} `json:"run"`
+		} `json:"$defs"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(jsonSchema), &schema))
+	assert.Equal(t, jsonSchemaVersion, schema.Defs.Run.Properties.SchemaVersion.Const)
+}
+
+func TestSchemaCommand_PrintsSchema(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	cmd := newSchemaCommand()
+	cmd.SetOut(&buf)
+	require.NoError(t, cmd.Execute())
+
+	assert.JSONEq(t, jsonSchema, buf.String())
+}
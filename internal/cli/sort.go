@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"sort"
+
+	"github.com/emancu/pgdoctor/check"
+)
+
+type sortOrder string
+
+const (
+	// sortCategory orders by category, then check ID. It is the default and
+	// the only order the text output groups under category headers.
+	sortCategory sortOrder = "category"
+	sortID       sortOrder = "id"
+	// sortSeverity puts the worst reports first (FAIL, WARN, OK, SKIP, N/A),
+	// then orders by category and check ID.
+	sortSeverity sortOrder = "severity"
+)
+
+var sortOrders = map[sortOrder]struct{}{
+	sortCategory: {},
+	sortID:       {},
+	sortSeverity: {},
+}
+
+// metadataLess orders checks by category then ID, or by ID alone.
+func metadataLess(a, b check.Metadata, order sortOrder) bool {
+	if order != sortID && a.Category != b.Category {
+		return a.Category < b.Category
+	}
+	return a.CheckID < b.CheckID
+}
+
+// sortChecks sets the order checks run in. Severity is only known once a
+// check has run, so sortSeverity runs checks in category order and relies on
+// sortReports afterwards.
+func sortChecks(checks []check.Package, order sortOrder) {
+	sort.SliceStable(checks, func(i, j int) bool {
+		return metadataLess(checks[i].Metadata(), checks[j].Metadata(), order)
+	})
+}
+
+// sortReports puts completed reports in their output order. Output must not
+// depend on the order reports arrived in. Reports from different databases
+// (--all-databases) stay grouped: cluster-wide reports, whose Database is
+// empty, first, then each database by name.
+func sortReports(reports []*check.Report, order sortOrder) {
+	sort.SliceStable(reports, func(i, j int) bool {
+		a, b := reports[i], reports[j]
+		if a.Database != b.Database {
+			return a.Database < b.Database
+		}
+		if order == sortSeverity && a.Severity != b.Severity {
+			return a.Severity > b.Severity
+		}
+		return metadataLess(a.Metadata, b.Metadata, order)
+	})
+}
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/emancu/pgdoctor"
+	"github.com/emancu/pgdoctor/check"
+)
+
+func reportWith(category check.Category, id string, severity check.Severity) *check.Report {
+	report := check.NewReport(check.Metadata{Category: category, CheckID: id, Name: id})
+	report.AddFinding(check.Finding{ID: id, Name: id, Severity: severity})
+	return report
+}
+
+func reportIDs(reports []*check.Report) []string {
+	ids := make([]string, 0, len(reports))
+	for _, r := range reports {
+		ids = append(ids, r.CheckID)
+	}
+	return ids
+}
+
+func TestSortReports(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		order    sortOrder
+		expected []string
+	}{
+		{sortCategory, []string{"pg-version", "replication-lag", "index-bloat", "invalid-indexes", "freeze-age"}},
+		{sortID, []string{"freeze-age", "index-bloat", "invalid-indexes", "pg-version", "replication-lag"}},
+		{sortSeverity, []string{"invalid-indexes", "replication-lag", "freeze-age", "pg-version", "index-bloat"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.order), func(t *testing.T) {
+			t.Parallel()
+
+			// Two arrival orders must produce the same output order.
+			newReports := func() []*check.Report {
+				return []*check.Report{
+					reportWith(check.CategoryVacuum, "freeze-age", check.SeverityWarn),
+					reportWith(check.CategoryIndexes, "invalid-indexes", check.SeverityFail),
+					reportWith(check.CategoryConfigs, "replication-lag", check.SeverityWarn),
+					reportWith(check.CategoryIndexes, "index-bloat", check.SeveritySkip),
+					reportWith(check.CategoryConfigs, "pg-version", check.SeverityOK),
+				}
+			}
+			forward := newReports()
+			backward := newReports()
+			for i, j := 0, len(backward)-1; i < j; i, j = i+1, j-1 {
+				backward[i], backward[j] = backward[j], backward[i]
+			}
+
+			sortReports(forward, tt.order)
+			sortReports(backward, tt.order)
+
+			assert.Equal(t, tt.expected, reportIDs(forward))
+			assert.Equal(t, tt.expected, reportIDs(backward))
+		})
+	}
+}
+
+func TestSortReports_GroupsByDatabase(t *testing.T) {
+	t.Parallel()
+
+	inDatabase := func(database, id string) *check.Report {
+		report := reportWith(check.CategoryIndexes, id, check.SeverityOK)
+		report.Database = database
+		return report
+	}
+	reports := []*check.Report{
+		inDatabase("orders", "index-bloat"),
+		inDatabase("app", "invalid-indexes"),
+		inDatabase("", "pg-version"),
+		inDatabase("app", "index-bloat"),
+	}
+	sortReports(reports, sortCategory)
+
+	var got []string
+	for _, r := range reports {
+		got = append(got, r.Database+"/"+r.CheckID)
+	}
+	assert.Equal(t, []string{"/pg-version", "app/index-bloat", "app/invalid-indexes", "orders/index-bloat"}, got)
+}
+
+func TestSortChecks_CategoryThenID(t *testing.T) {
+	t.Parallel()
+
+	checks := pgdoctor.AllChecks()
+	sortChecks(checks, sortCategory)
+
+	for i := 1; i < len(checks); i++ {
+		prev, cur := checks[i-1].Metadata(), checks[i].Metadata()
+		if prev.Category == cur.Category {
+			assert.Less(t, prev.CheckID, cur.CheckID)
+		} else {
+			assert.Less(t, prev.Category, cur.Category)
+		}
+	}
+}
+
+func TestTextPrinter_FlatWhenNotSortedByCategory(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	printer := &textPrinter{w: &buf, opts: &runOptions{detail: string(detailSummary), sortBy: string(sortSeverity), collapse: true}}
+	printer.print(reportWith(check.CategoryIndexes, "invalid-indexes", check.SeverityFail))
+	printer.print(reportWith(check.CategoryConfigs, "pg-version", check.SeverityOK))
+	printer.print(reportWith(check.CategoryVacuum, "freeze-age", check.SeverityOK))
+	printer.flush()
+
+	out := buf.String()
+	assert.NotContains(t, out, "INDEXES\n")
+	assert.NotContains(t, out, "CONFIGS\n")
+	assert.Contains(t, out, "(invalid-indexes)")
+	assert.True(t, strings.HasSuffix(out, "[PASS] 2 passing check(s)\n"), "passing checks collapse into a single count")
+}
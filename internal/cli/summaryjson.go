@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/emancu/pgdoctor"
+	"github.com/emancu/pgdoctor/check"
+)
+
+// jsonSummary is the one-line rollup --output summary-json writes, for log
+// pipelines that keep a line per run. Its field names are part of the output
+// contract, like those of jsonRun: add fields, but do not rename or remove
+// them.
+type jsonSummary struct {
+	PgdoctorVersion string             `json:"pgdoctor_version"`
+	StartedAt       string             `json:"started_at"`
+	DurationMs      int64              `json:"duration_ms"`
+	ServerVersion   string             `json:"server_version"`
+	Database        string             `json:"database"`
+	Tags            map[string]string  `json:"tags,omitempty"`
+	Severity        string             `json:"severity"`
+	Checks          int                `json:"checks"`
+	Counts          jsonSeverityCounts `json:"counts"`
+	Failing         []string           `json:"failing"`
+	Warning         []string           `json:"warning"`
+	Incomplete      []string           `json:"incomplete"`
+	Errored         []string           `json:"errored"`
+	StoppedEarly    *jsonStoppedEarly  `json:"stopped_early,omitempty"`
+}
+
+// jsonSeverityCounts counts the checks of a run by severity.
+type jsonSeverityCounts struct {
+	Fail          int `json:"fail"`
+	Warn          int `json:"warn"`
+	Pass          int `json:"pass"`
+	Skip          int `json:"skip"`
+	NotApplicable int `json:"not_applicable"`
+}
+
+// formatSummaryJSON writes the run as a single line of JSON: the worst
+// severity of any check, the number of checks of each severity, and the
+// failing, warning, incomplete (panicked or errored), and errored checks. In
+// --all-databases runs, checks are counted once per database and listed as
+// <database>/<check-id>.
+func formatSummaryJSON(w io.Writer, run runInfo, reports []*check.Report) error {
+	summary := jsonSummary{
+		PgdoctorVersion: run.version,
+		StartedAt:       run.startedAt.UTC().Format(time.RFC3339),
+		DurationMs:      run.duration.Milliseconds(),
+		ServerVersion:   run.serverVersion,
+		Database:        run.database,
+		Tags:            run.tags,
+		Checks:          len(reports),
+		Failing:         []string{},
+		Warning:         []string{},
+		Incomplete:      []string{},
+		Errored:         []string{},
+		StoppedEarly:    run.stoppedEarly,
+	}
+
+	worst := check.SeverityOK
+	for _, r := range reports {
+		worst = max(worst, r.Severity)
+		switch r.Severity {
+		case check.SeverityFail:
+			summary.Counts.Fail++
+			summary.Failing = append(summary.Failing, checkLabel(r))
+		case check.SeverityWarn:
+			summary.Counts.Warn++
+			summary.Warning = append(summary.Warning, checkLabel(r))
+		case check.SeverityOK:
+			summary.Counts.Pass++
+		case check.SeveritySkip:
+			summary.Counts.Skip++
+		case check.SeverityNotApplicable:
+			summary.Counts.NotApplicable++
+		}
+		if pgdoctor.Panicked(r) || pgdoctor.Errored(r) {
+			summary.Incomplete = append(summary.Incomplete, checkLabel(r))
+		}
+		if pgdoctor.Errored(r) {
+			summary.Errored = append(summary.Errored, checkLabel(r))
+		}
+	}
+	summary.Severity = worst.String()
+
+	if err := json.NewEncoder(w).Encode(summary); err != nil {
+		return fmt.Errorf("encoding JSON: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emancu/pgdoctor"
+	"github.com/emancu/pgdoctor/check"
+)
+
+func TestFormatSummaryJSON(t *testing.T) {
+	t.Parallel()
+
+	run := sampleRun()
+	run.tags = map[string]string{"env": "prod"}
+
+	var buf bytes.Buffer
+	require.NoError(t, formatSummaryJSON(&buf, run, sampleReports()))
+	require.Equal(t, 1, strings.Count(buf.String(), "\n"), "one line")
+	require.True(t, strings.HasSuffix(buf.String(), "\n"))
+
+	var summary jsonSummary
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &summary))
+	assert.Equal(t, jsonSummary{
+		PgdoctorVersion: "v0.4.0",
+		StartedAt:       "2026-10-14T09:30:00Z",
+		DurationMs:      1500,
+		ServerVersion:   "16.4",
+		Database:        "app",
+		Tags:            map[string]string{"env": "prod"},
+		Severity:        "fail",
+		Checks:          5,
+		Counts:          jsonSeverityCounts{Fail: 1, Pass: 2, Skip: 1, NotApplicable: 1},
+		Failing:         []string{"table-bloat"},
+		Warning:         []string{},
+		Incomplete:      []string{},
+		Errored:         []string{},
+	}, summary)
+}
+
+// The field names are a contract with log pipelines; renaming one breaks
+// their parsing and alerts.
+func TestFormatSummaryJSON_FieldNames(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, formatSummaryJSON(&buf, runInfo{}, nil))
+
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &fields))
+	var names []string
+	for name := range fields {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	assert.Equal(t, []string{
+		"checks", "counts", "database", "duration_ms", "errored", "failing", "incomplete",
+		"pgdoctor_version", "server_version", "severity", "started_at", "warning",
+	}, names)
+	assert.JSONEq(t, `{"fail":0,"warn":0,"pass":0,"skip":0,"not_applicable":0}`, string(fields["counts"]))
+	assert.JSONEq(t, `"pass"`, string(fields["severity"]), "a run without checks passes")
+	assert.JSONEq(t, `[]`, string(fields["failing"]))
+}
+
+func TestFormatSummaryJSON_AllDatabasesAndIncomplete(t *testing.T) {
+	t.Parallel()
+
+	bloat := sampleReports()[1]
+	bloat.Database = "orders"
+	warned := check.NewReport(check.Metadata{CheckID: "index-usage", Category: check.CategoryIndexes})
+	warned.AddFinding(check.Finding{ID: "index-usage", Severity: check.SeverityWarn})
+	warned.Database = "app"
+	errored := check.NewReport(check.Metadata{CheckID: "freeze-age", Category: check.CategoryVacuum})
+	errored.Severity = check.SeveritySkip
+	errored.Errored = true
+	errored.AddFinding(check.Finding{ID: pgdoctor.ErrorFindingID, Name: "Check Error", Severity: check.SeveritySkip, Details: "permission denied"})
+	panicked := check.NewReport(check.Metadata{CheckID: "index-bloat", Category: check.CategoryIndexes})
+	panicked.Severity = check.SeveritySkip
+	panicked.AddFinding(check.Finding{ID: pgdoctor.PanicFindingID, Name: "Check Panicked", Severity: check.SeveritySkip, Details: "boom"})
+
+	var buf bytes.Buffer
+	require.NoError(t, formatSummaryJSON(&buf, sampleRun(), []*check.Report{bloat, warned, errored, panicked}))
+
+	var summary jsonSummary
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &summary))
+	assert.Equal(t, "fail", summary.Severity)
+	assert.Equal(t, jsonSeverityCounts{Fail: 1, Warn: 1, Skip: 2}, summary.Counts)
+	assert.Equal(t, []string{"orders/table-bloat"}, summary.Failing)
+	assert.Equal(t, []string{"app/index-usage"}, summary.Warning)
+	assert.Equal(t, []string{"freeze-age", "index-bloat"}, summary.Incomplete)
+	assert.Equal(t, []string{"freeze-age"}, summary.Errored, "errored checks are told apart from panics and skips")
+}
+
+func TestRenderCommand_SummaryJSON(t *testing.T) {
+	t.Parallel()
+
+	var saved bytes.Buffer
+	require.NoError(t, formatJSON(&saved, sampleRun(), sampleReports()))
+
+	var out bytes.Buffer
+	cmd := newRootCommand("test")
+	cmd.SetIn(bytes.NewReader(saved.Bytes()))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"render", "--input", "-", "--output", "summary-json"})
+	require.NoError(t, cmd.Execute())
+
+	var summary jsonSummary
+	require.NoError(t, json.Unmarshal(out.Bytes(), &summary))
+	assert.Equal(t, []string{"table-bloat"}, summary.Failing)
+	assert.Equal(t, int64(1500), summary.DurationMs)
+}
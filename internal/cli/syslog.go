@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/emancu/pgdoctor/check"
+)
+
+// syslogFacilities are the values of --syslog-facility.
+var syslogFacilities = []string{"user", "daemon", "local0", "local1", "local2", "local3", "local4", "local5", "local6", "local7"}
+
+// syslogWriter is the part of a log/syslog Writer that --syslog uses, one
+// method per priority it sends at.
+type syslogWriter interface {
+	Warning(m string) error
+	Err(m string) error
+	Close() error
+}
+
+// openSyslog connects to the local syslog daemon for --syslog, or returns nil
+// when it is off. Platforms without syslog return an error (see dialSyslog).
+func openSyslog(opts *runOptions) (syslogWriter, error) {
+	if !opts.syslog {
+		return nil, nil
+	}
+	if !slices.Contains(syslogFacilities, opts.syslogFacility) {
+		return nil, fmt.Errorf("unknown --syslog-facility %q (valid: %s)", opts.syslogFacility, strings.Join(syslogFacilities, ", "))
+	}
+	return dialSyslog(opts.syslogFacility, opts.syslogTag)
+}
+
+// sendSyslog sends every warning and failure in reports as one message, at
+// priority warning or err. Passing, skipped, and not applicable findings are
+// not sent.
+func sendSyslog(w syslogWriter, reports []*check.Report) error {
+	for _, r := range reports {
+		for _, f := range r.Results {
+			var err error
+			switch f.Severity {
+			case check.SeverityWarn:
+				err = w.Warning(syslogMessage(r, f))
+			case check.SeverityFail:
+				err = w.Err(syslogMessage(r, f))
+			default:
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("sending to syslog: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// logReports sends reports to syslog when --syslog is on. A failure is
+// reported on stderr and turns into exit code 1, as the run itself finished.
+func logReports(w syslogWriter, reports []*check.Report) error {
+	if w == nil {
+		return nil
+	}
+	if err := sendSyslog(w, reports); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return &SilentError{ExitCode: 1}
+	}
+	return nil
+}
+
+// syslogMessage is the syslog line for finding f of r: its check, with the
+// database in multi-database runs, the finding ID when it differs from the
+// check ID, and its details on one line.
+func syslogMessage(r *check.Report, f check.Finding) string {
+	label := checkLabel(r)
+	if f.ID != r.CheckID {
+		label += "/" + f.ID
+	}
+	return fmt.Sprintf("%s %s: %s", strings.ToUpper(f.Severity.String()), label, strings.Join(strings.Fields(f.Details), " "))
+}
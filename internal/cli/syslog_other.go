@@ -0,0 +1,13 @@
+//go:build windows || plan9
+
+package cli
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// dialSyslog fails: log/syslog does not exist on this platform.
+func dialSyslog(string, string) (syslogWriter, error) {
+	return nil, fmt.Errorf("--syslog is not supported on %s", runtime.GOOS)
+}
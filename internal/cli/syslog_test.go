@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emancu/pgdoctor/check"
+)
+
+// fakeSyslog records messages as "<priority>: <message>".
+type fakeSyslog struct {
+	messages []string
+	err      error
+}
+
+func (f *fakeSyslog) Warning(m string) error {
+	f.messages = append(f.messages, "warning: "+m)
+	return f.err
+}
+
+func (f *fakeSyslog) Err(m string) error {
+	f.messages = append(f.messages, "err: "+m)
+	return f.err
+}
+
+func (f *fakeSyslog) Close() error { return nil }
+
+func TestSendSyslog(t *testing.T) {
+	t.Parallel()
+
+	freeze := check.NewReport(check.Metadata{CheckID: "freeze-age", Name: "Freeze Age", Category: check.CategoryVacuum})
+	freeze.AddFinding(check.Finding{ID: "freeze-age", Severity: check.SeverityFail, Details: "Database is\n1.5B transactions from wraparound"})
+	conns := check.NewReport(check.Metadata{CheckID: "connection-health", Name: "Connection Health", Category: check.CategoryConfigs})
+	conns.Database = "app"
+	conns.AddFinding(check.Finding{ID: "total-connections", Severity: check.SeverityOK, Details: "fine"})
+	conns.AddFinding(check.Finding{ID: "idle-in-transaction", Severity: check.SeverityWarn, Details: "2 sessions idle in transaction"})
+	skipped := check.NewReport(check.Metadata{CheckID: "table-bloat", Name: "Table Bloat", Category: check.CategoryVacuum})
+	skipped.AddFinding(check.Finding{ID: "table-bloat", Severity: check.SeveritySkip, Details: "timed out"})
+
+	w := &fakeSyslog{}
+	require.NoError(t, sendSyslog(w, []*check.Report{freeze, conns, skipped}))
+	assert.Equal(t, []string{
+		"err: FAIL freeze-age: Database is 1.5B transactions from wraparound",
+		"warning: WARN app/connection-health/idle-in-transaction: 2 sessions idle in transaction",
+	}, w.messages)
+
+	w = &fakeSyslog{err: errors.New("connection refused")}
+	assert.EqualError(t, sendSyslog(w, []*check.Report{freeze, conns}), "sending to syslog: connection refused")
+	assert.Len(t, w.messages, 1, "sending stops at the first error")
+}
+
+func TestOpenSyslog(t *testing.T) {
+	t.Parallel()
+
+	w, err := openSyslog(&runOptions{})
+	require.NoError(t, err)
+	assert.Nil(t, w, "off without --syslog")
+
+	_, err = openSyslog(&runOptions{syslog: true, syslogTag: "pgdoctor", syslogFacility: "mail"})
+	assert.EqualError(t, err, `unknown --syslog-facility "mail" (valid: user, daemon, local0, local1, local2, local3, local4, local5, local6, local7)`)
+}
@@ -0,0 +1,32 @@
+//go:build !windows && !plan9
+
+package cli
+
+import (
+	"fmt"
+	"log/syslog"
+)
+
+// syslogPriorities maps syslogFacilities to their log/syslog facility.
+var syslogPriorities = map[string]syslog.Priority{
+	"user":   syslog.LOG_USER,
+	"daemon": syslog.LOG_DAEMON,
+	"local0": syslog.LOG_LOCAL0,
+	"local1": syslog.LOG_LOCAL1,
+	"local2": syslog.LOG_LOCAL2,
+	"local3": syslog.LOG_LOCAL3,
+	"local4": syslog.LOG_LOCAL4,
+	"local5": syslog.LOG_LOCAL5,
+	"local6": syslog.LOG_LOCAL6,
+	"local7": syslog.LOG_LOCAL7,
+}
+
+// dialSyslog connects to the local syslog daemon, sending as facility with
+// tag as the program name.
+func dialSyslog(facility, tag string) (syslogWriter, error) {
+	w, err := syslog.New(syslogPriorities[facility]|syslog.LOG_WARNING, tag)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to syslog: %w", err)
+	}
+	return w, nil
+}
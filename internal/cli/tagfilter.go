@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"slices"
+
+	"github.com/emancu/pgdoctor"
+	"github.com/emancu/pgdoctor/check"
+)
+
+// filterTagged narrows a report to its findings tagged with one of the
+// --tag-filter tags, with the severity of what is left, and returns nil when
+// none are. With no tags it returns r unchanged. A panicked or errored check
+// keeps its report: its findings are missing, not untagged.
+func filterTagged(r *check.Report, tags []string) *check.Report {
+	if len(tags) == 0 || pgdoctor.Panicked(r) || pgdoctor.Errored(r) {
+		return r
+	}
+	filtered := *r
+	filtered.Severity = check.SeverityOK
+	filtered.Results = nil
+	for _, f := range r.Results {
+		if slices.ContainsFunc(tags, f.HasTag) {
+			filtered.AddFinding(f)
+		}
+	}
+	if len(filtered.Results) == 0 {
+		return nil
+	}
+	return &filtered
+}
+
+// filterReportsTagged applies filterTagged to every report, dropping those
+// left empty.
+func filterReportsTagged(reports []*check.Report, tags []string) []*check.Report {
+	if len(tags) == 0 {
+		return reports
+	}
+	var out []*check.Report
+	for _, r := range reports {
+		if r = filterTagged(r, tags); r != nil {
+			out = append(out, r)
+		}
+	}
+	return out
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emancu/pgdoctor"
+	"github.com/emancu/pgdoctor/check"
+)
+
+func TestFilterTagged(t *testing.T) {
+	t.Parallel()
+
+	r := check.NewReport(check.Metadata{CheckID: "freeze-age", Name: "Freeze Age", Category: check.CategoryVacuum})
+	r.AddFinding(check.Finding{ID: "database-freeze-age", Severity: check.SeverityOK, Tags: []string{check.TagWraparound}})
+	r.AddFinding(check.Finding{ID: "table-freeze-age", Severity: check.SeverityWarn, Tags: []string{"storage", check.TagWraparound}})
+	r.AddFinding(check.Finding{ID: "other", Severity: check.SeverityFail})
+
+	assert.Same(t, r, filterTagged(r, nil), "no filter keeps the report")
+	assert.Nil(t, filterTagged(r, []string{"security"}))
+
+	filtered := filterTagged(r, []string{"storage"})
+	require.NotNil(t, filtered)
+	assert.Equal(t, check.SeverityWarn, filtered.Severity, "the untagged FAIL no longer counts")
+	require.Len(t, filtered.Results, 1)
+	assert.Equal(t, "table-freeze-age", filtered.Results[0].ID)
+	assert.Len(t, r.Results, 3, "the original report is not modified")
+
+	filtered = filterTagged(r, []string{"security", check.TagWraparound})
+	require.NotNil(t, filtered)
+	assert.Len(t, filtered.Results, 2)
+}
+
+func TestFilterTagged_KeepsPanickedAndErrored(t *testing.T) {
+	t.Parallel()
+
+	for _, id := range []string{pgdoctor.PanicFindingID, pgdoctor.ErrorFindingID} {
+		missing := check.NewReport(check.Metadata{CheckID: "freeze-age"})
+		missing.Severity = check.SeveritySkip
+		missing.Errored = id == pgdoctor.ErrorFindingID
+		missing.AddFinding(check.Finding{ID: id, Severity: check.SeveritySkip, Details: "boom"})
+
+		assert.Same(t, missing, filterTagged(missing, []string{check.TagWraparound}), id)
+	}
+}
+
+func TestRenderCommand_TagFilter(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	var saved bytes.Buffer
+	require.NoError(t, formatJSON(&saved, sampleRun(), sampleReports()))
+	require.NoError(t, os.WriteFile(path, saved.Bytes(), 0o600))
+
+	var out bytes.Buffer
+	cmd := newRootCommand("test")
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"render", "--input", path, "--output", "json", "--tag-filter", "storage"})
+	require.NoError(t, cmd.Execute())
+
+	_, decoded, err := decodeJSON(&out)
+	require.NoError(t, err)
+	require.Len(t, decoded, 1)
+	assert.Equal(t, "table-bloat", decoded[0].CheckID)
+	require.Len(t, decoded[0].Results, 1)
+	assert.Equal(t, []string{"storage"}, decoded[0].Results[0].Tags, "tags survive the round trip")
+}
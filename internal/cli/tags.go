@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// tagKey is the OpenMetrics label name syntax, so every tag can become a
+// label.
+var tagKey = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// reservedTagKeys are the labels pgdoctor's own metrics already carry.
+var reservedTagKeys = map[string]bool{
+	"check":          true,
+	"category":       true,
+	"database":       true,
+	"finding":        true,
+	"version":        true,
+	"server_version": true,
+}
+
+// parseTags parses the key=value pairs of --tag.
+func parseTags(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	tags := make(map[string]string, len(values))
+	for _, v := range values {
+		key, value, ok := strings.Cut(v, "=")
+		if !ok {
+			return nil, fmt.Errorf("--tag %q: want key=value", v)
+		}
+		if !tagKey.MatchString(key) || strings.HasPrefix(key, "__") {
+			return nil, fmt.Errorf("--tag %q: keys are letters, digits, and underscores, not starting with a digit or __", v)
+		}
+		if reservedTagKeys[key] {
+			return nil, fmt.Errorf("--tag %q: %s is a label of pgdoctor's own metrics", v, key)
+		}
+		if _, dup := tags[key]; dup {
+			return nil, fmt.Errorf("--tag %q: %s is already set", v, key)
+		}
+		tags[key] = value
+	}
+	return tags, nil
+}
+
+// sortedTagKeys returns the keys of tags in order, for stable output.
+func sortedTagKeys(tags map[string]string) []string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// tagLabels returns tags as label name/value pairs.
+func tagLabels(tags map[string]string) []string {
+	labels := make([]string, 0, 2*len(tags))
+	for _, k := range sortedTagKeys(tags) {
+		labels = append(labels, k, tags[k])
+	}
+	return labels
+}
+
+// formatTags renders tags as "key=value, key=value" for text output.
+func formatTags(tags map[string]string) string {
+	pairs := make([]string, 0, len(tags))
+	for _, k := range sortedTagKeys(tags) {
+		pairs = append(pairs, k+"="+tags[k])
+	}
+	return strings.Join(pairs, ", ")
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTags(t *testing.T) {
+	t.Parallel()
+
+	tags, err := parseTags([]string{"env=prod", "team=payments", "note=a=b", "empty="})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"env": "prod", "team": "payments", "note": "a=b", "empty": ""}, tags)
+
+	tags, err = parseTags(nil)
+	require.NoError(t, err)
+	assert.Nil(t, tags)
+}
+
+func TestParseTags_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"env":               "want key=value",
+		"=prod":             "keys are letters",
+		"1env=prod":         "keys are letters",
+		"team-name=x":       "keys are letters",
+		"__name__=x":        "keys are letters",
+		"database=other":    "label of pgdoctor's own metrics",
+		"check=table-bloat": "label of pgdoctor's own metrics",
+	}
+	for value, want := range tests {
+		_, err := parseTags([]string{value})
+		require.ErrorContains(t, err, want, value)
+	}
+
+	_, err := parseTags([]string{"env=prod", "env=staging"})
+	require.ErrorContains(t, err, "env is already set")
+}
+
+func taggedRun() runInfo {
+	run := sampleRun()
+	run.tags = map[string]string{"team": "payments", "env": "prod"}
+	return run
+}
+
+func TestFormatJSON_Tags(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, formatJSON(&buf, taggedRun(), sampleReports()))
+	require.NoError(t, validateJSON(t, compileSchema(t), buf.Bytes()))
+
+	var out jsonRun
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+	assert.Equal(t, map[string]string{"env": "prod", "team": "payments"}, out.Tags)
+
+	run, _, err := decodeJSON(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, taggedRun().tags, run.tags, "render keeps the tags")
+
+	buf.Reset()
+	require.NoError(t, formatJSON(&buf, sampleRun(), nil))
+	assert.NotContains(t, buf.String(), `"tags"`, "untagged runs leave the key out")
+}
+
+func TestFormatOpenMetrics_Tags(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, formatOpenMetrics(&buf, taggedRun(), sampleReports()))
+	samples := parseOpenMetrics(t, buf.String())
+
+	assert.Equal(t, 1.0, samples[`pgdoctor_run_info{version="v0.4.0",server_version="16.4",database="app",env="prod",team="payments"}`])
+	assert.Equal(t, 1.5, samples[`pgdoctor_run_duration_seconds{env="prod",team="payments"}`])
+	assert.Equal(t, 2.0, samples[`pgdoctor_check_severity{check="table-bloat",category="vacuum",env="prod",team="payments"}`])
+	assert.Equal(t, 1.0, samples[`pgdoctor_finding_severity{check="table-bloat",finding="dead-tuples",env="prod",team="payments"}`])
+}
+
+func TestRenderCommand_TagsInHeader(t *testing.T) {
+	t.Parallel()
+
+	var saved bytes.Buffer
+	require.NoError(t, formatJSON(&saved, taggedRun(), sampleReports()))
+
+	var out bytes.Buffer
+	cmd := newRootCommand("test")
+	cmd.SetIn(bytes.NewReader(saved.Bytes()))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"render", "--input", "-"})
+	_ = cmd.Execute()
+	assert.Contains(t, out.String(), "Database Health Check: app\nTags: env=prod, team=payments\n\n")
+}
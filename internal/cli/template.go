@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/emancu/pgdoctor/check"
+)
+
+// templateFuncs are the helpers --output template adds to text/template's
+// builtins, the same formatting the text output uses.
+var templateFuncs = template.FuncMap{
+	"formatBytes":    check.FormatBytes,
+	"formatNumber":   check.FormatNumber,
+	"formatDuration": func(ms int64) string { return check.FormatDurationMs(float64(ms)) },
+	"formatPercent":  func(pct float64) string { return fmt.Sprintf("%.1f%%", pct) },
+	"severityLabel": func(severity string) (string, error) {
+		s, err := decodeSeverity(severity)
+		if err != nil {
+			return "", err
+		}
+		label, _ := severityDisplay(s)
+		return label, nil
+	},
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// parseTemplate reads and parses the --template file, so a broken template
+// fails before any check runs.
+func parseTemplate(path string) (*template.Template, error) {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs).Parse(string(text))
+	if err != nil {
+		return nil, fmt.Errorf("parsing template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// formatTemplate executes tmpl against the document --output json writes.
+func formatTemplate(w io.Writer, tmpl *template.Template, run runInfo, reports []*check.Report) error {
+	if err := tmpl.Execute(w, newJSONRun(run, reports)); err != nil {
+		return fmt.Errorf("executing template: %w", err)
+	}
+	return nil
+}
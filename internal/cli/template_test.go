@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTemplate(t *testing.T, text string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "report.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte(text), 0o600))
+	return path
+}
+
+func TestFormatTemplate(t *testing.T) {
+	t.Parallel()
+
+	// The example from the README.
+	tmpl, err := parseTemplate(writeTemplate(t, `{{range .Reports}}{{if ne .Severity "pass"}}{{severityLabel .Severity}} {{.CheckID}} ({{formatDuration .DurationMs}})
+{{range .Results}}{{if .Details}}  - {{.Name}}: {{.Details}}
+{{end}}{{end}}{{end}}{{end}}`))
+	require.NoError(t, err)
+
+	reports := sampleReports()
+	reports[1].Duration = 1500_000_000 // 1.5s
+
+	var buf bytes.Buffer
+	require.NoError(t, formatTemplate(&buf, tmpl, sampleRun(), reports))
+	out := buf.String()
+	assert.Contains(t, out, "FAIL table-bloat (1.5s)\n  - Dead Tuples: 2 tables\n")
+	assert.Contains(t, out, "N/A statements-reset (0ms)\n")
+	assert.NotContains(t, out, "pg-version", "passing checks are left out")
+}
+
+func TestFormatTemplate_Helpers(t *testing.T) {
+	t.Parallel()
+
+	tmpl, err := parseTemplate(writeTemplate(t,
+		`{{.PgdoctorVersion}}|{{formatBytes 1048576}}|{{formatNumber 1500}}|{{formatPercent 12.345}}|{{upper .Database}}|{{join .Selection.Checks ","}}`))
+	require.NoError(t, err)
+
+	run := sampleRun()
+	run.checks = []string{"pg-version", "table-bloat"}
+	var buf bytes.Buffer
+	require.NoError(t, formatTemplate(&buf, tmpl, run, nil))
+	assert.Equal(t, "v0.4.0|1.0MiB|1.5K|12.3%|APP|pg-version,table-bloat", buf.String())
+}
+
+func TestParseTemplate_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := parseTemplate(writeTemplate(t, "{{range .Reports}"))
+	require.ErrorContains(t, err, "parsing template")
+
+	_, err = parseTemplate(writeTemplate(t, "{{noSuchFunc}}"))
+	require.ErrorContains(t, err, `function "noSuchFunc" not defined`)
+
+	_, err = parseTemplate(filepath.Join(t.TempDir(), "missing.tmpl"))
+	require.Error(t, err)
+}
+
+func TestFormatTemplate_ExecutionError(t *testing.T) {
+	t.Parallel()
+
+	tmpl, err := parseTemplate(writeTemplate(t, "{{range .Reports}}{{.NoSuchField}}{{end}}"))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.ErrorContains(t, formatTemplate(&buf, tmpl, sampleRun(), sampleReports()), "executing template")
+}
+
+func TestRenderCommand_Template(t *testing.T) {
+	t.Parallel()
+
+	var saved bytes.Buffer
+	require.NoError(t, formatJSON(&saved, sampleRun(), sampleReports()))
+
+	var out bytes.Buffer
+	cmd := newRootCommand("test")
+	cmd.SetIn(bytes.NewReader(saved.Bytes()))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"render", "--input", "-", "--output", "template", "--template",
+		writeTemplate(t, "{{range .Reports}}{{.CheckID}}={{.Severity}}\n{{end}}"), "--sort", "severity"})
+	require.NoError(t, cmd.Execute())
+	assert.True(t, strings.HasPrefix(out.String(), "table-bloat=fail\n"), "sorted worst first: %q", out.String())
+}
+
+func TestRenderCommand_TemplateFlags(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"template output without a file", []string{"--output", "template"}},
+		{"file without template output", []string{"--template", "report.tmpl"}},
+		{"broken template", []string{"--output", "template", "--template", writeTemplate(t, "{{if}}")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cmd := newRootCommand("test")
+			cmd.SetIn(bytes.NewReader(nil))
+			cmd.SetArgs(append([]string{"render", "--input", "-"}, tt.args...))
+			var silent *SilentError
+			require.ErrorAs(t, cmd.Execute(), &silent, "fails before reading the input")
+			assert.Equal(t, 1, silent.ExitCode)
+		})
+	}
+}
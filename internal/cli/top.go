@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/emancu/pgdoctor/check"
+)
+
+// rankedFinding is a finding together with the report it came from, for the
+// --top summary.
+type rankedFinding struct {
+	report  *check.Report
+	finding check.Finding
+}
+
+// topFindings returns at most n WARN and FAIL findings across reports, most
+// urgent first (see check.CompareFindings). Findings that rank equally keep
+// the order of reports, so the result is stable for sorted input.
+func topFindings(reports []*check.Report, n int) []rankedFinding {
+	var ranked []rankedFinding
+	for _, report := range reports {
+		for _, finding := range report.Results {
+			if finding.Severity == check.SeverityWarn || finding.Severity == check.SeverityFail {
+				ranked = append(ranked, rankedFinding{report: report, finding: finding})
+			}
+		}
+	}
+	slices.SortStableFunc(ranked, func(a, b rankedFinding) int {
+		return check.CompareFindings(a.finding, b.finding)
+	})
+	if len(ranked) > n {
+		ranked = ranked[:n]
+	}
+	return ranked
+}
+
+// printTopFindings prints the --top summary: one line per finding, naming the
+// check and subcheck to look up in the detailed output below it.
+func printTopFindings(w io.Writer, ranked []rankedFinding, glyphs glyphSet) {
+	title := "TOP ISSUES"
+	fmt.Fprintln(w, title)
+	fmt.Fprintln(w, strings.Repeat("─", len(title)))
+
+	dimFunc := dimColor()
+	if len(ranked) == 0 {
+		fmt.Fprintln(w, dimFunc("No warnings or failures"))
+		fmt.Fprintln(w)
+		return
+	}
+
+	for i, r := range ranked {
+		id := r.report.CheckID
+		if r.finding.ID != r.report.CheckID {
+			id += "/" + r.finding.ID
+		}
+		line := fmt.Sprintf("%2d. %s %s %s", i+1, severityBadge(r.finding.Severity, glyphs), r.finding.Name, dimFunc(fmt.Sprintf("(%s)", id)))
+		if r.finding.Details != "" {
+			line += " — " + r.finding.Details
+		}
+		fmt.Fprintln(w, line)
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emancu/pgdoctor/check"
+)
+
+func rankedIDs(ranked []rankedFinding) []string {
+	ids := make([]string, 0, len(ranked))
+	for _, r := range ranked {
+		ids = append(ids, r.report.CheckID+"/"+r.finding.ID)
+	}
+	return ids
+}
+
+func TestTopFindings(t *testing.T) {
+	t.Parallel()
+
+	multi := check.NewReport(check.Metadata{Category: check.CategoryVacuum, CheckID: "freeze-age", Name: "Freeze Age"})
+	multi.AddFinding(check.Finding{ID: "table-freeze-age", Severity: check.SeverityFail})
+	multi.AddFinding(check.Finding{ID: "database-freeze-age", Severity: check.SeverityFail, Priority: check.PriorityUrgent})
+	multi.AddFinding(check.Finding{ID: "ok", Severity: check.SeverityOK})
+
+	reports := []*check.Report{
+		reportWith(check.CategoryConfigs, "a", check.SeverityWarn),
+		reportWith(check.CategoryConfigs, "b", check.SeverityFail),
+		reportWith(check.CategoryIndexes, "c", check.SeveritySkip),
+		reportWith(check.CategoryIndexes, "d", check.SeverityOK),
+		multi,
+	}
+
+	assert.Equal(t, []string{
+		"freeze-age/database-freeze-age",
+		"b/b",
+		"freeze-age/table-freeze-age",
+		"a/a",
+	}, rankedIDs(topFindings(reports, 10)), "OK and SKIP findings are left out; equal ranks keep report order")
+	assert.Equal(t, []string{"freeze-age/database-freeze-age", "b/b"}, rankedIDs(topFindings(reports, 2)))
+}
+
+func TestPrintTopFindings(t *testing.T) {
+	t.Parallel()
+
+	report := check.NewReport(check.Metadata{CheckID: "freeze-age", Name: "Freeze Age"})
+	report.AddFinding(check.Finding{ID: "database-freeze-age", Name: "Database Freeze Age", Severity: check.SeverityFail, Details: "Found 1 database(s)"})
+	single := reportWith(check.CategoryConfigs, "pg-version", check.SeverityWarn)
+
+	var buf bytes.Buffer
+	printTopFindings(&buf, topFindings([]*check.Report{report, single}, 5), glyphsASCII)
+
+	out := buf.String()
+	require.Contains(t, out, "TOP ISSUES\n")
+	assert.Contains(t, out, " 1. [FAIL] Database Freeze Age (freeze-age/database-freeze-age) — Found 1 database(s)\n")
+	assert.Contains(t, out, " 2. [WARN] pg-version (pg-version)\n", "single-finding checks are named by check ID alone")
+}
+
+func TestPrintTopFindings_None(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	printTopFindings(&buf, nil, glyphsASCII)
+
+	assert.Contains(t, buf.String(), "No warnings or failures")
+}
@@ -5,6 +5,8 @@ package pgdoctor
 import (
 	"context"
 	"errors"
+	"fmt"
+	"runtime/debug"
 	"strings"
 	"time"
 
@@ -30,12 +32,42 @@ type Options struct {
 	Checks   []check.Package
 	Config   check.Config
 	OnReport ReportHandler
+	// Redact strips SQL text and, optionally, object names from every report
+	// before OnReport sees it. See check.Report.Redact.
+	Redact check.Redaction
+	// Database, when set, is recorded in every report's Database. Set it when
+	// running checks against several databases of a cluster.
+	Database string
+	// RecordQueries appends the SQL each check ran, with its arguments, to
+	// the Debug of its WARN and FAIL findings, so the rows behind a finding
+	// can be reproduced by hand. It is off by default: recording copies every
+	// statement and its arguments.
+	RecordQueries bool
+	// HeavyLimit, when set, makes heavy checks (check.Metadata.Heavy) wait for
+	// a slot before they start, so that concurrent Run calls sharing it run
+	// only so many of them at once. The wait does not count toward a report's
+	// Duration. See HeavyLimit.
+	HeavyLimit *HeavyLimit
 }
 
 // Run executes checks sequentially against the given connection.
 //
+// A check that returns an error, or panics, is reported as skipped with the
+// error in its finding, and the remaining checks still run; see Errored and
+// Panicked. A check whose query hits statement_timeout is skipped without
+// counting as errored, as are the checks left when ctx is cancelled, with
+// context.Cause(ctx) in their finding.
+//
+// Each report's severities are bounded by the limits Config sets for its
+// category (check.Config.SeverityLimit) before OnReport sees it.
+//
 // Important: callers should SET statement_timeout on the connection before calling Run()
 // to prevent slow queries from blocking the database. See DefaultStatementTimeoutMs.
+//
+// Run may be called from several goroutines at once with the same Checks and
+// Config, as long as each call has its own connection (a pgx.Conn is not safe
+// for concurrent use). Every call builds fresh checkers and reports, and
+// OnReport is invoked on the calling goroutine.
 func Run(ctx context.Context, conn db.DBTX, opts Options) {
 	onReport := opts.OnReport
 	if onReport == nil {
@@ -43,35 +75,174 @@ func Run(ctx context.Context, conn db.DBTX, opts Options) {
 	}
 
 	for _, pkg := range opts.Checks {
-		checker := pkg.New(conn, opts.Config)
+		// Once the root context is done, every query would fail immediately;
+		// don't start the remaining checks, but still report them so callers
+		// see the full check list.
+		if err := ctx.Err(); err != nil {
+			report := skippedReport(pkg.Metadata(), "run cancelled before check started: "+context.Cause(ctx).Error())
+			report.Database = opts.Database
+			onReport(report.Redact(opts.Redact))
+			continue
+		}
+
+		checkConn, log := conn, (*queryLog)(nil)
+		if opts.RecordQueries {
+			log = &queryLog{conn: conn}
+			checkConn = log
+		}
+
+		heavy := opts.HeavyLimit != nil && pkg.Metadata().Heavy
+		if heavy {
+			if err := opts.HeavyLimit.acquire(ctx); err != nil {
+				report := skippedReport(pkg.Metadata(), "run cancelled before check started: "+context.Cause(ctx).Error())
+				report.Database = opts.Database
+				onReport(report.Redact(opts.Redact))
+				continue
+			}
+		}
 
 		start := time.Now()
-		report, err := checker.Check(ctx)
+		report, err := runCheck(ctx, pkg, checkConn, opts.Config)
 		elapsed := time.Since(start)
+		if heavy {
+			opts.HeavyLimit.release()
+		}
 
+		if err == nil && report == nil {
+			err = errors.New("check returned no report")
+		}
 		if err != nil {
-			metadata := checker.Metadata()
-			report = check.NewReport(metadata)
-			report.Severity = check.SeveritySkip
-
-			detail := err.Error()
-			if isStatementTimeout(err) {
-				detail = "query cancelled by statement_timeout"
+			switch {
+			case ctx.Err() != nil:
+				// The check's queries fail once the run is cancelled; that
+				// is not an error of the check. A cancelled query looks like
+				// a statement_timeout, so this comes first.
+				report = skippedReport(pkg.Metadata(), "run cancelled while check ran: "+context.Cause(ctx).Error())
+			case isStatementTimeout(err):
+				report = skippedReport(pkg.Metadata(), "query cancelled by statement_timeout")
+			default:
+				report = errorReport(pkg.Metadata(), err)
 			}
-
-			report.AddFinding(check.Finding{
-				ID:       "error",
-				Name:     "Check Error",
-				Severity: check.SeveritySkip,
-				Details:  detail,
-			})
 		}
 
 		report.Duration = elapsed
-		onReport(report)
+		if err == nil && log != nil {
+			attachQueries(report, log)
+		}
+		onReport(finishReport(report, opts))
 	}
 }
 
+// finishReport applies the run-wide options to a check's report: the
+// category's severity bounds, the database, and redaction.
+func finishReport(report *check.Report, opts Options) *check.Report {
+	report = report.ClampSeverity(opts.Config.SeverityLimit(report.Category))
+	report.Database = opts.Database
+	return report.Redact(opts.Redact)
+}
+
+// PanicFindingID is the ID of the finding Run reports for a check that
+// panicked. See Panicked.
+const PanicFindingID = "panic"
+
+// runCheck builds and runs one check. A panic in either is recovered into a
+// skipped report carrying the stack trace, so one broken check does not end
+// the run.
+func runCheck(ctx context.Context, pkg check.Package, conn db.DBTX, cfg check.Config) (report *check.Report, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			report, err = panicReport(pkg.Metadata(), v, debug.Stack()), nil
+		}
+	}()
+	return pkg.New(conn, cfg).Check(ctx)
+}
+
+// panicReport builds the report for a check that panicked with v.
+func panicReport(metadata check.Metadata, v any, stack []byte) *check.Report {
+	report := check.NewReport(metadata)
+	report.Severity = check.SeveritySkip
+	report.AddFinding(check.Finding{
+		ID:       PanicFindingID,
+		Name:     "Check Panicked",
+		Severity: check.SeveritySkip,
+		Details:  fmt.Sprintf("check panicked: %v (this is a bug in pgdoctor, please report it)", v),
+		// The panic value may quote anything the check was handling.
+		DetailsSensitivity: check.SensitiveIdentifier,
+		Debug:              string(stack),
+	})
+	return report
+}
+
+// Panicked reports whether report is the result of a check that panicked.
+func Panicked(report *check.Report) bool {
+	for _, f := range report.Results {
+		if f.ID == PanicFindingID && f.Severity == check.SeveritySkip {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrorFindingID is the ID of the finding Run reports for a check that
+// returned an error. See Errored.
+const ErrorFindingID = "error"
+
+// errorReport builds the report for a check that returned err.
+func errorReport(metadata check.Metadata, err error) *check.Report {
+	report := check.NewReport(metadata)
+	report.Severity = check.SeveritySkip
+	report.Errored = true
+	report.AddFinding(check.Finding{
+		ID:       ErrorFindingID,
+		Name:     "Check Error",
+		Severity: check.SeveritySkip,
+		Details:  err.Error(),
+		// Database errors quote relation and column names.
+		DetailsSensitivity: check.SensitiveIdentifier,
+	})
+	return report
+}
+
+// Errored reports whether report is the result of a check that returned an
+// error, such as a permission error or a missing relation, rather than
+// findings (check.Report.Errored). Unlike a panic, this is usually a problem
+// of the database or the role pgdoctor connects as, not a bug.
+func Errored(report *check.Report) bool {
+	return report.Errored
+}
+
+// SkippedFindingID is the ID of the finding Run reports for a check it did
+// not run to completion, because the run was cancelled or its query hit
+// statement_timeout.
+const SkippedFindingID = "skipped"
+
+// skippedReport builds the report for a check that could not run.
+func skippedReport(metadata check.Metadata, detail string) *check.Report {
+	report := check.NewReport(metadata)
+	report.Severity = check.SeveritySkip
+	report.AddFinding(check.Finding{
+		ID:       SkippedFindingID,
+		Name:     "Check Skipped",
+		Severity: check.SeveritySkip,
+		Details:  detail,
+	})
+	return report
+}
+
+// SplitClusterWide separates checks marked check.Metadata.ClusterWide, which
+// need to run only once per cluster, from those that depend on the database
+// they run in. Both keep the input order.
+func SplitClusterWide(checks []check.Package) (clusterWide, perDatabase []check.Package) {
+	for _, pkg := range checks {
+		if pkg.Metadata().ClusterWide {
+			clusterWide = append(clusterWide, pkg)
+		} else {
+			perDatabase = append(perDatabase, pkg)
+		}
+	}
+	return clusterWide, perDatabase
+}
+
 // Filter returns checks matching the only/ignored filters.
 // If only is non-empty, only checks matching those check IDs or categories are included.
 // Checks matching ignored check IDs or categories are excluded.
@@ -109,6 +280,23 @@ func Filter(checks []check.Package, only, ignored []string) []check.Package {
 	return filtered
 }
 
+// FilterDisabled drops checks that cfg disables (check.EnabledKey set to
+// false), except those whose check ID is in forced, so an explicit --only can
+// still run a disabled check. Categories in forced do not re-enable checks.
+func FilterDisabled(checks []check.Package, cfg check.Config, forced []string) []check.Package {
+	forcedMap := toSet(forced)
+
+	var filtered []check.Package
+	for _, pkg := range checks {
+		checkID := pkg.Metadata().CheckID
+		if _, ok := forcedMap[checkID]; !ok && !cfg.Enabled(checkID) {
+			continue
+		}
+		filtered = append(filtered, pkg)
+	}
+	return filtered
+}
+
 func toSet(items []string) map[string]struct{} {
 	m := make(map[string]struct{}, len(items))
 	for _, item := range items {
@@ -2,11 +2,16 @@ package pgdoctor
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/emancu/pgdoctor/check"
 	"github.com/emancu/pgdoctor/db"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -83,6 +88,62 @@ func TestValidateFilters(t *testing.T) {
 	}
 }
 
+func TestFilterDisabled(t *testing.T) {
+	t.Parallel()
+
+	checks := []check.Package{
+		fakePackage("table-bloat", check.CategoryVacuum, nil, nil),
+		fakePackage("freeze-age", check.CategoryVacuum, nil, nil),
+		fakePackage("pk-types", check.CategorySchema, nil, nil),
+	}
+	cfg := check.Config{
+		"table-bloat": {"enabled": "false"},
+		"pk-types":    {"enabled": "false"},
+	}
+
+	ids := func(pkgs []check.Package) []string {
+		var out []string
+		for _, pkg := range pkgs {
+			out = append(out, pkg.Metadata().CheckID)
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"freeze-age"}, ids(FilterDisabled(checks, cfg, nil)))
+	assert.Equal(t, []string{"freeze-age", "pk-types"}, ids(FilterDisabled(checks, cfg, []string{"pk-types"})),
+		"a check forced by ID runs even when disabled")
+	assert.Equal(t, []string{"freeze-age"}, ids(FilterDisabled(checks, cfg, []string{"vacuum"})),
+		"a category does not force its disabled checks")
+	assert.Len(t, FilterDisabled(checks, nil, nil), 3)
+}
+
+func TestSplitClusterWide(t *testing.T) {
+	t.Parallel()
+
+	clusterPkg := func(id string) check.Package {
+		meta := check.Metadata{CheckID: id, Name: id, Category: check.CategoryConfigs, ClusterWide: true}
+		return check.Package{Metadata: func() check.Metadata { return meta }}
+	}
+	checks := []check.Package{
+		clusterPkg("pg-version"),
+		fakePackage("table-bloat", check.CategoryVacuum, nil, nil),
+		clusterPkg("replication-lag"),
+		fakePackage("pk-types", check.CategorySchema, nil, nil),
+	}
+
+	ids := func(pkgs []check.Package) []string {
+		var out []string
+		for _, pkg := range pkgs {
+			out = append(out, pkg.Metadata().CheckID)
+		}
+		return out
+	}
+
+	clusterWide, perDatabase := SplitClusterWide(checks)
+	assert.Equal(t, []string{"pg-version", "replication-lag"}, ids(clusterWide))
+	assert.Equal(t, []string{"table-bloat", "pk-types"}, ids(perDatabase))
+}
+
 // fakeChecker is a test double that implements check.Checker.
 type fakeChecker struct {
 	metadata check.Metadata
@@ -129,6 +190,8 @@ func TestRun_ContinuesAfterStatementTimeout(t *testing.T) {
 	assert.Equal(t, "slow-check", reports[0].CheckID)
 	require.Len(t, reports[0].Results, 1)
 	assert.Contains(t, reports[0].Results[0].Details, "statement_timeout")
+	assert.Equal(t, SkippedFindingID, reports[0].Results[0].ID)
+	assert.False(t, Errored(reports[0]), "a timeout is skipped, not errored")
 
 	assert.Equal(t, check.SeverityOK, reports[1].Severity)
 	assert.Equal(t, "fast-check", reports[1].CheckID)
@@ -154,7 +217,638 @@ func TestRun_ContinuesAfterCheckError(t *testing.T) {
 	assert.Equal(t, "broken-check", reports[0].CheckID)
 	require.Len(t, reports[0].Results, 1)
 	assert.Contains(t, reports[0].Results[0].Details, "connection refused")
+	assert.Equal(t, ErrorFindingID, reports[0].Results[0].ID)
+	assert.True(t, Errored(reports[0]))
+	assert.False(t, Panicked(reports[0]))
 
 	assert.Equal(t, check.SeverityOK, reports[1].Severity)
 	assert.Equal(t, "good-check", reports[1].CheckID)
+	assert.False(t, Errored(reports[1]))
+}
+
+// panickingChecker is a check.Checker whose Check panics.
+type panickingChecker struct{ fakeChecker }
+
+func (p *panickingChecker) Check(_ context.Context) (*check.Report, error) {
+	var m map[string]int
+	m["boom"]++ // assignment to entry in nil map
+	return nil, nil
+}
+
+func TestRun_ContinuesAfterCheckPanic(t *testing.T) {
+	t.Parallel()
+
+	goodReport := check.NewReport(check.Metadata{CheckID: "good-check", Name: "Good", Category: check.CategoryConfigs})
+	goodReport.AddFinding(check.Finding{ID: "ok", Name: "OK", Severity: check.SeverityOK})
+
+	panicMeta := check.Metadata{CheckID: "panicking-check", Name: "Panicking", Category: check.CategoryConfigs}
+	var reports []*check.Report
+	Run(context.Background(), nil, Options{
+		Checks: []check.Package{
+			{
+				Metadata: func() check.Metadata { return panicMeta },
+				New: func(_ db.DBTX, _ check.Config) check.Checker {
+					return &panickingChecker{fakeChecker{metadata: panicMeta}}
+				},
+			},
+			{
+				Metadata: func() check.Metadata {
+					return check.Metadata{CheckID: "panicking-new", Category: check.CategoryConfigs}
+				},
+				New: func(_ db.DBTX, _ check.Config) check.Checker { panic("bad config") },
+			},
+			fakePackage("good-check", check.CategoryConfigs, goodReport, nil),
+		},
+		OnReport: Collect(&reports),
+	})
+	require.Len(t, reports, 3)
+
+	for i, id := range []string{"panicking-check", "panicking-new"} {
+		assert.Equal(t, id, reports[i].CheckID)
+		assert.Equal(t, check.SeveritySkip, reports[i].Severity)
+		assert.True(t, Panicked(reports[i]))
+		assert.False(t, Errored(reports[i]))
+		require.Len(t, reports[i].Results, 1)
+		assert.Equal(t, PanicFindingID, reports[i].Results[0].ID)
+		assert.Contains(t, reports[i].Results[0].Debug, "goroutine", "carries the stack trace")
+	}
+	assert.Contains(t, reports[0].Results[0].Details, "assignment to entry in nil map")
+	assert.Contains(t, reports[1].Results[0].Details, "bad config")
+
+	assert.Equal(t, "good-check", reports[2].CheckID)
+	assert.Equal(t, check.SeverityOK, reports[2].Severity)
+	assert.False(t, Panicked(reports[2]))
+}
+
+func TestRun_NilReportIsSkipped(t *testing.T) {
+	t.Parallel()
+
+	var reports []*check.Report
+	Run(context.Background(), nil, Options{
+		Checks:   []check.Package{fakePackage("empty-check", check.CategoryConfigs, nil, nil)},
+		OnReport: Collect(&reports),
+	})
+	require.Len(t, reports, 1)
+	assert.Equal(t, check.SeveritySkip, reports[0].Severity)
+	assert.Contains(t, reports[0].Results[0].Details, "no report")
+	assert.False(t, Panicked(reports[0]))
+	assert.True(t, Errored(reports[0]))
+}
+
+// blockingDB is a db.DBTX whose every call blocks until its context is done,
+// standing in for a query stuck on the server. It counts calls still blocked
+// so tests can assert none outlive Run.
+type blockingDB struct {
+	calls    atomic.Int64
+	inFlight atomic.Int64
+}
+
+func (b *blockingDB) wait(ctx context.Context) error {
+	b.calls.Add(1)
+	b.inFlight.Add(1)
+	defer b.inFlight.Add(-1)
+
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (b *blockingDB) Exec(ctx context.Context, _ string, _ ...interface{}) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, b.wait(ctx)
+}
+
+func (b *blockingDB) Query(ctx context.Context, _ string, _ ...interface{}) (pgx.Rows, error) {
+	return nil, b.wait(ctx)
+}
+
+func (b *blockingDB) QueryRow(ctx context.Context, _ string, _ ...interface{}) pgx.Row {
+	return errRow{err: b.wait(ctx)}
+}
+
+type errRow struct{ err error }
+
+func (r errRow) Scan(...any) error { return r.err }
+
+func TestRun_CancelledContextInterruptsBlockedQueries(t *testing.T) {
+	t.Parallel()
+
+	conn := &blockingDB{}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var reports []*check.Report
+	start := time.Now()
+	Run(ctx, conn, Options{Checks: AllChecks(), OnReport: Collect(&reports)})
+	elapsed := time.Since(start)
+
+	// Only the check in flight at cancellation waits on the deadline; had any
+	// check ignored ctx, its query would block forever and Run would hang.
+	assert.Less(t, elapsed, 5*time.Second, "Run must return promptly once the context is cancelled")
+	assert.Zero(t, conn.inFlight.Load(), "no query may outlive Run")
+	assert.Equal(t, int64(1), conn.calls.Load(), "no query may start after cancellation")
+
+	require.Len(t, reports, len(AllChecks()), "every check must still be reported")
+	for _, r := range reports {
+		assert.Equal(t, check.SeveritySkip, r.Severity, "%s should be skipped", r.CheckID)
+		require.Len(t, r.Results, 1)
+		assert.Contains(t, r.Results[0].Details, context.DeadlineExceeded.Error(), "%s should surface the cancellation", r.CheckID)
+	}
+}
+
+func TestRun_EveryCheckHonoursContext(t *testing.T) {
+	t.Parallel()
+
+	// Run each check on its own against an already-cancelled context: every
+	// query it issues must return instead of blocking on the fake. Instance
+	// metadata for a current version makes version-gated checks query too.
+	for _, pkg := range AllChecks() {
+		t.Run(pkg.Metadata().CheckID, func(t *testing.T) {
+			t.Parallel()
+
+			conn := &blockingDB{}
+			ctx := check.ContextWithInstanceMetadata(context.Background(), &check.InstanceMetadata{EngineVersionMajor: 17})
+			ctx, cancel := context.WithCancel(ctx)
+			cancel()
+
+			done := make(chan error, 1)
+			go func() {
+				_, err := pkg.New(conn, nil).Check(ctx)
+				done <- err
+			}()
+
+			select {
+			case err := <-done:
+				if conn.calls.Load() > 0 {
+					require.ErrorIs(t, err, context.Canceled, "a cancelled query must fail the check")
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("Check did not return after context cancellation")
+			}
+			assert.Zero(t, conn.inFlight.Load())
+		})
+	}
+}
+
+func TestRun_SkipsRemainingChecksAfterCancellation(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	okReport := check.NewReport(check.Metadata{CheckID: "first", Name: "First", Category: check.CategoryConfigs})
+	okReport.AddFinding(check.Finding{ID: "ok", Name: "OK", Severity: check.SeverityOK})
+
+	var started []string
+	pkgFor := func(id string) check.Package {
+		meta := check.Metadata{CheckID: id, Name: id, Category: check.CategoryConfigs}
+		return check.Package{
+			Metadata: func() check.Metadata { return meta },
+			New: func(_ db.DBTX, _ check.Config) check.Checker {
+				started = append(started, id)
+				return &fakeChecker{metadata: meta, report: okReport}
+			},
+		}
+	}
+
+	var reports []*check.Report
+	Run(ctx, nil, Options{
+		Checks: []check.Package{pkgFor("first"), pkgFor("second")},
+		OnReport: func(r *check.Report) {
+			reports = append(reports, r)
+			cancel() // cancel after the first check reports
+		},
+	})
+
+	assert.Equal(t, []string{"first"}, started, "no check may start after cancellation")
+	require.Len(t, reports, 2)
+	assert.Equal(t, check.SeverityOK, reports[0].Severity)
+	assert.Equal(t, "second", reports[1].CheckID)
+	assert.Equal(t, check.SeveritySkip, reports[1].Severity)
+	assert.Contains(t, reports[1].Results[0].Details, "run cancelled")
+	assert.False(t, Errored(reports[1]))
+}
+
+func TestRun_CancelledWhileCheckRuns(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	stopped := errors.New("stopped by --fail-fast")
+
+	meta := check.Metadata{CheckID: "in-flight", Name: "In Flight", Category: check.CategoryConfigs}
+	inFlight := check.Package{
+		Metadata: func() check.Metadata { return meta },
+		New: func(_ db.DBTX, _ check.Config) check.Checker {
+			cancel(stopped)
+			return &fakeChecker{metadata: meta, err: fmt.Errorf("running query: %w", context.Canceled)}
+		},
+	}
+
+	var reports []*check.Report
+	Run(ctx, nil, Options{
+		Checks:   []check.Package{inFlight, fakePackage("next", check.CategoryConfigs, nil, nil)},
+		OnReport: Collect(&reports),
+	})
+
+	require.Len(t, reports, 2)
+	for _, r := range reports {
+		assert.Equal(t, check.SeveritySkip, r.Severity, "%s should be skipped", r.CheckID)
+		assert.False(t, Errored(r), "%s was cancelled, not broken", r.CheckID)
+		assert.Contains(t, r.Results[0].Details, stopped.Error(), "%s should give the cause", r.CheckID)
+	}
+	assert.Contains(t, reports[0].Results[0].Details, "run cancelled while check ran")
+}
+
+func TestRun_CancelledQueryIsNotATimeout(t *testing.T) {
+	t.Parallel()
+
+	// pgx cancels a running query when ctx is done, and PostgreSQL reports
+	// that with the statement_timeout SQLSTATE.
+	ctx, cancel := context.WithCancelCause(context.Background())
+	stopped := errors.New("stopped by --fail-fast")
+
+	meta := check.Metadata{CheckID: "in-flight", Name: "In Flight", Category: check.CategoryConfigs}
+	inFlight := check.Package{
+		Metadata: func() check.Metadata { return meta },
+		New: func(_ db.DBTX, _ check.Config) check.Checker {
+			cancel(stopped)
+			return &fakeChecker{metadata: meta, err: &pgconn.PgError{Code: "57014", Message: "canceling statement due to user request"}}
+		},
+	}
+
+	var reports []*check.Report
+	Run(ctx, nil, Options{Checks: []check.Package{inFlight}, OnReport: Collect(&reports)})
+
+	require.Len(t, reports, 1)
+	assert.Equal(t, "run cancelled while check ran: stopped by --fail-fast", reports[0].Results[0].Details)
+}
+
+func TestRun_RedactsReports(t *testing.T) {
+	t.Parallel()
+
+	sensitive := check.NewReport(check.Metadata{CheckID: "stuck-queries", Name: "Stuck", Category: check.CategoryPerformance})
+	sensitive.AddFinding(check.Finding{
+		ID:       "stuck-queries",
+		Name:     "Stuck",
+		Severity: check.SeverityWarn,
+		Table: &check.Table{
+			Headers:   []string{"PID", "Query"},
+			Sensitive: []check.Sensitivity{check.NotSensitive, check.SensitiveQuery},
+			Rows:      []check.TableRow{{Cells: []string{"42", "SELECT secret"}}},
+		},
+	})
+
+	var reports []*check.Report
+	Run(context.Background(), nil, Options{
+		Checks: []check.Package{
+			fakePackage("stuck-queries", check.CategoryPerformance, sensitive, nil),
+			fakePackage("broken-check", check.CategoryConfigs, nil, fmt.Errorf(`relation "public.secrets" does not exist`)),
+		},
+		OnReport: Collect(&reports),
+		Redact:   check.RedactIdentifiers,
+	})
+	require.Len(t, reports, 2)
+
+	assert.Equal(t, "42", reports[0].Results[0].Table.Rows[0].Cells[0])
+	assert.NotContains(t, reports[0].Results[0].Table.Rows[0].Cells[1], "secret")
+	assert.Equal(t, "[redacted]", reports[1].Results[0].Details, "database errors quote identifiers")
+}
+
+func TestRun_StampsDatabase(t *testing.T) {
+	t.Parallel()
+
+	okReport := func() *check.Report {
+		report := check.NewReport(check.Metadata{CheckID: "ok-check", Name: "OK", Category: check.CategoryConfigs})
+		report.AddFinding(check.Finding{ID: "ok-check", Name: "OK", Severity: check.SeverityOK})
+		return report
+	}
+	checks := func() []check.Package {
+		return []check.Package{
+			fakePackage("ok-check", check.CategoryConfigs, okReport(), nil),
+			fakePackage("broken-check", check.CategoryConfigs, nil, fmt.Errorf("permission denied")),
+		}
+	}
+
+	var reports []*check.Report
+	Run(context.Background(), nil, Options{Checks: checks(), OnReport: Collect(&reports), Database: "billing"})
+	require.Len(t, reports, 2)
+	assert.Equal(t, "billing", reports[0].Database)
+	assert.Equal(t, "billing", reports[1].Database, "skipped checks are stamped too")
+
+	reports = nil
+	Run(context.Background(), nil, Options{Checks: checks(), OnReport: Collect(&reports)})
+	assert.Empty(t, reports[0].Database)
+
+	reports = nil
+	Run(context.Background(), nil, Options{Checks: checks(), OnReport: Collect(&reports), Database: "billing", Redact: check.RedactIdentifiers})
+	assert.NotContains(t, reports[0].Database, "billing")
+	assert.Contains(t, reports[0].Database, "<id:")
+}
+
+// emptyDB answers every query with no rows, so each check runs its real
+// report-building code without a server.
+type emptyDB struct{}
+
+func (emptyDB) Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, nil
+}
+
+func (emptyDB) Query(context.Context, string, ...interface{}) (pgx.Rows, error) {
+	return emptyRows{}, nil
+}
+
+func (emptyDB) QueryRow(context.Context, string, ...interface{}) pgx.Row {
+	return errRow{err: pgx.ErrNoRows}
+}
+
+type emptyRows struct{}
+
+func (emptyRows) Close()                                       {}
+func (emptyRows) Err() error                                   { return nil }
+func (emptyRows) CommandTag() pgconn.CommandTag                { return pgconn.CommandTag{} }
+func (emptyRows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (emptyRows) Next() bool                                   { return false }
+func (emptyRows) Scan(...any) error                            { return nil }
+func (emptyRows) Values() ([]any, error)                       { return nil, nil }
+func (emptyRows) RawValues() [][]byte                          { return nil }
+func (emptyRows) Conn() *pgx.Conn                              { return nil }
+
+// TestRun_ConcurrentRuns runs every check from many goroutines at once,
+// sharing the check list and config. Under -race (as in CI) it fails if a
+// check or the runner shares mutable state between runs.
+func TestRun_ConcurrentRuns(t *testing.T) {
+	t.Parallel()
+
+	const runs = 16
+
+	checks := AllChecks()
+	cfg := check.Config{"vacuum-scale-factors": {"min_rows": "1000"}}
+	ctx := check.ContextWithInstanceMetadata(context.Background(), &check.InstanceMetadata{EngineVersionMajor: 17})
+
+	results := make([][]*check.Report, runs)
+	var wg sync.WaitGroup
+	for i := range runs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			Run(ctx, emptyDB{}, Options{
+				Checks: checks,
+				Config: cfg,
+				Redact: check.RedactIdentifiers,
+				OnReport: func(r *check.Report) {
+					// Callers own their reports; writing to one must not
+					// touch another run's.
+					r.Duration = 0
+					for j := range r.Results {
+						r.Results[j].Details += "."
+					}
+					results[i] = append(results[i], r)
+				},
+			})
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, results[0], len(checks))
+	for i := 1; i < runs; i++ {
+		require.Len(t, results[i], len(checks))
+		for j, r := range results[i] {
+			assert.NotSame(t, results[0][j], r, "%s: reports must not be shared between runs", r.CheckID)
+		}
+	}
+}
+
+// funcChecker runs check when checked, for tests that need to observe or
+// block a check while it runs.
+type funcChecker struct {
+	fakeChecker
+	check func(context.Context) error
+}
+
+func (f *funcChecker) Check(ctx context.Context) (*check.Report, error) {
+	if err := f.check(ctx); err != nil {
+		return nil, err
+	}
+	report := check.NewReport(f.metadata)
+	report.AddFinding(check.Finding{ID: f.metadata.CheckID, Severity: check.SeverityOK})
+	return report, nil
+}
+
+func funcPackage(id string, heavy bool, fn func(context.Context) error) check.Package {
+	meta := check.Metadata{CheckID: id, Name: id, Category: check.CategoryVacuum, Heavy: heavy}
+	return check.Package{
+		Metadata: func() check.Metadata { return meta },
+		New: func(_ db.DBTX, _ check.Config) check.Checker {
+			return &funcChecker{fakeChecker: fakeChecker{metadata: meta}, check: fn}
+		},
+	}
+}
+
+func TestRun_HeavyLimit(t *testing.T) {
+	t.Parallel()
+
+	const runs = 8
+
+	// Every run's light check waits for all the others to start, so the
+	// runs deadlock if light checks are serialized too.
+	var started sync.WaitGroup
+	started.Add(runs)
+	light := funcPackage("light", false, func(ctx context.Context) error {
+		started.Done()
+		done := make(chan struct{})
+		go func() { started.Wait(); close(done) }()
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+
+	var inFlight, peak atomic.Int64
+	heavy := funcPackage("heavy", true, func(context.Context) error {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	limit := NewHeavyLimit(1)
+	results := make([][]*check.Report, runs)
+	var wg sync.WaitGroup
+	for i := range runs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			Run(ctx, nil, Options{
+				Checks:     []check.Package{light, heavy},
+				HeavyLimit: limit,
+				OnReport:   Collect(&results[i]),
+			})
+		}()
+	}
+	wg.Wait()
+
+	for _, reports := range results {
+		require.Len(t, reports, 2)
+		assert.Equal(t, check.SeverityOK, reports[0].Severity, "light checks run concurrently")
+		assert.Equal(t, check.SeverityOK, reports[1].Severity)
+	}
+	assert.Equal(t, int64(1), peak.Load(), "heavy checks run one at a time")
+}
+
+func TestRun_HeavyLimitCancelledWhileWaiting(t *testing.T) {
+	t.Parallel()
+
+	limit := NewHeavyLimit(1)
+	require.NoError(t, limit.acquire(context.Background())) // another run's heavy check
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	var reports []*check.Report
+	Run(ctx, nil, Options{
+		Checks:     []check.Package{funcPackage("heavy", true, func(context.Context) error { return nil })},
+		HeavyLimit: limit,
+		OnReport:   Collect(&reports),
+	})
+
+	require.Len(t, reports, 1)
+	assert.Equal(t, check.SeveritySkip, reports[0].Severity)
+	assert.Contains(t, reports[0].Results[0].Details, "run cancelled before check started")
+	assert.False(t, Errored(reports[0]))
+}
+
+func TestRun_ClampsSeverityPerCategory(t *testing.T) {
+	t.Parallel()
+
+	failing := func(id string, category check.Category) check.Package {
+		report := check.NewReport(check.Metadata{CheckID: id, Name: id, Category: category})
+		report.AddFinding(check.Finding{ID: id, Name: id, Severity: check.SeverityFail})
+		return fakePackage(id, category, report, nil)
+	}
+	warning := check.NewReport(check.Metadata{CheckID: "warning-config", Name: "warning-config", Category: check.CategoryConfigs})
+	warning.AddFinding(check.Finding{ID: "warning-config", Name: "warning-config", Severity: check.SeverityWarn})
+
+	var reports []*check.Report
+	Run(context.Background(), nil, Options{
+		Checks: []check.Package{
+			failing("failing-index", check.CategoryIndexes),
+			failing("failing-vacuum", check.CategoryVacuum),
+			fakePackage("warning-config", check.CategoryConfigs, warning, nil),
+		},
+		Config: check.Config{
+			"indexes": {check.MaxSeverityKey: "warn"},
+			"configs": {check.MinSeverityKey: "fail"},
+		},
+		OnReport: Collect(&reports),
+	})
+
+	require.Len(t, reports, 3)
+	assert.Equal(t, check.SeverityWarn, reports[0].Severity, "indexes are capped at warn")
+	assert.Equal(t, check.SeverityFail, reports[1].Severity, "other categories are untouched")
+	assert.Equal(t, check.SeverityFail, reports[2].Severity, "configs warnings are raised to fail")
+}
+
+// queryingPackage is a check that runs one query through its connection and
+// reports a WARN and an OK finding.
+func queryingPackage(id string) check.Package {
+	meta := check.Metadata{CheckID: id, Name: id, Category: check.CategoryConfigs}
+	return check.Package{
+		Metadata: func() check.Metadata { return meta },
+		New: func(conn db.DBTX, _ check.Config) check.Checker {
+			return checkerFunc{metadata: meta, check: func(ctx context.Context) (*check.Report, error) {
+				rows, err := conn.Query(ctx, "\nSELECT relname FROM pg_class WHERE relnamespace::regnamespace::text = ANY($1) AND relpages > $2\n",
+					[]string{"public", "o'neil"}, int64(10))
+				if err != nil {
+					return nil, err
+				}
+				rows.Close()
+				report := check.NewReport(meta)
+				report.AddFinding(check.Finding{ID: "flagged", Severity: check.SeverityWarn, Debug: "plan"})
+				report.AddFinding(check.Finding{ID: "fine", Severity: check.SeverityOK})
+				return report, nil
+			}}
+		},
+	}
+}
+
+type checkerFunc struct {
+	metadata check.Metadata
+	check    func(context.Context) (*check.Report, error)
+}
+
+func (c checkerFunc) Metadata() check.Metadata { return c.metadata }
+
+func (c checkerFunc) Check(ctx context.Context) (*check.Report, error) { return c.check(ctx) }
+
+func TestRun_RecordQueries(t *testing.T) {
+	t.Parallel()
+
+	var reports []*check.Report
+	Run(context.Background(), emptyDB{}, Options{
+		Checks:        []check.Package{queryingPackage("querying-check")},
+		OnReport:      Collect(&reports),
+		RecordQueries: true,
+	})
+	require.Len(t, reports, 1)
+	require.Len(t, reports[0].Results, 2)
+	assert.Equal(t, "plan\n\nQueries run by the check:\n\n"+
+		"-- $1 = '{\"public\",\"o''neil\"}'\n"+
+		"-- $2 = 10\n"+
+		"SELECT relname FROM pg_class WHERE relnamespace::regnamespace::text = ANY($1) AND relpages > $2",
+		reports[0].Results[0].Debug)
+	assert.Empty(t, reports[0].Results[1].Debug, "OK findings get no queries")
+
+	// Off by default.
+	reports = nil
+	Run(context.Background(), emptyDB{}, Options{
+		Checks:   []check.Package{queryingPackage("querying-check")},
+		OnReport: Collect(&reports),
+	})
+	require.Len(t, reports, 1)
+	assert.Equal(t, "plan", reports[0].Results[0].Debug)
+}
+
+func TestLinkRelated(t *testing.T) {
+	t.Parallel()
+
+	report := func(id, database string, findings ...check.Finding) *check.Report {
+		r := check.NewReport(check.Metadata{CheckID: id, Name: id, Category: check.CategoryVacuum})
+		r.Database = database
+		for _, f := range findings {
+			r.AddFinding(f)
+		}
+		return r
+	}
+	tagged := func(id string, severity check.Severity) check.Finding {
+		return check.Finding{ID: id, Name: id, Severity: severity, Tags: []string{check.TagXminHorizon}}
+	}
+
+	horizon := report("xmin-horizon", "", tagged("xmin-horizon", check.SeverityFail))
+	idle := report("connection-health", "app",
+		tagged("idle-in-transaction", check.SeverityWarn),
+		check.Finding{ID: "idle-ratio", Name: "idle-ratio", Severity: check.SeverityWarn})
+	otherDB := report("connection-health", "billing", tagged("idle-in-transaction", check.SeverityWarn))
+	passing := report("inactive-slots", "", tagged("inactive-slots", check.SeverityOK))
+	untagged := report("table-bloat", "app", check.Finding{ID: "table-bloat", Name: "table-bloat", Severity: check.SeverityWarn})
+
+	reports := []*check.Report{horizon, idle, otherDB, passing, untagged}
+	linked := LinkRelated(reports)
+
+	require.Len(t, linked, len(reports))
+	assert.Equal(t, []string{"connection-health/idle-in-transaction (app)", "connection-health/idle-in-transaction (billing)"},
+		linked[0].Results[0].SeeAlso, "a cluster-wide check relates to every database")
+	assert.Equal(t, []string{"xmin-horizon"}, linked[1].Results[0].SeeAlso, "not to the same check in another database")
+	assert.Nil(t, linked[1].Results[1].SeeAlso, "untagged findings are not linked")
+	assert.Equal(t, []string{"xmin-horizon"}, linked[2].Results[0].SeeAlso)
+	assert.Nil(t, linked[3].Results[0].SeeAlso, "passing findings are not linked")
+
+	assert.Same(t, passing, linked[3], "reports without links are not copied")
+	assert.Same(t, untagged, linked[4])
+	assert.Nil(t, horizon.Results[0].SeeAlso, "reports are not modified")
 }
@@ -0,0 +1,103 @@
+package pgdoctor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/db"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// queryLog is a db.DBTX that records every statement a check runs, with its
+// arguments, for Options.RecordQueries.
+type queryLog struct {
+	conn    db.DBTX
+	queries []loggedQuery
+}
+
+type loggedQuery struct {
+	sql  string
+	args []interface{}
+}
+
+func (l *queryLog) record(sql string, args []interface{}) {
+	l.queries = append(l.queries, loggedQuery{sql: sql, args: args})
+}
+
+func (l *queryLog) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	l.record(sql, args)
+	return l.conn.Exec(ctx, sql, args...)
+}
+
+func (l *queryLog) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	l.record(sql, args)
+	return l.conn.Query(ctx, sql, args...)
+}
+
+func (l *queryLog) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	l.record(sql, args)
+	return l.conn.QueryRow(ctx, sql, args...)
+}
+
+// String lists the recorded statements in the order they ran, each preceded
+// by its arguments as SQL literals ("-- $1 = 'public'"), so it can be run by
+// hand in psql after substituting them.
+func (l *queryLog) String() string {
+	var b strings.Builder
+	for i, q := range l.queries {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		for j, arg := range q.args {
+			fmt.Fprintf(&b, "-- $%d = %s\n", j+1, sqlLiteral(arg))
+		}
+		b.WriteString(strings.TrimSpace(q.sql))
+	}
+	return b.String()
+}
+
+// sqlLiteral formats a query argument as PostgreSQL would read it back.
+func sqlLiteral(arg interface{}) string {
+	switch v := arg.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return quoteLiteral(v)
+	case []string:
+		elems := make([]string, len(v))
+		for i, s := range v {
+			elems[i] = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+		}
+		return quoteLiteral("{" + strings.Join(elems, ",") + "}")
+	case fmt.Stringer:
+		return quoteLiteral(v.String())
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// attachQueries appends the statements a check ran to the Debug of its WARN
+// and FAIL findings, the ones an operator would want to reproduce.
+func attachQueries(report *check.Report, log *queryLog) {
+	if len(log.queries) == 0 {
+		return
+	}
+	queries := "Queries run by the check:\n\n" + log.String()
+	for i, f := range report.Results {
+		if f.Severity < check.SeverityWarn {
+			continue
+		}
+		if f.Debug != "" {
+			report.Results[i].Debug = f.Debug + "\n\n" + queries
+		} else {
+			report.Results[i].Debug = queries
+		}
+	}
+}
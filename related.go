@@ -0,0 +1,92 @@
+package pgdoctor
+
+import (
+	"slices"
+
+	"github.com/emancu/pgdoctor/check"
+)
+
+// LinkRelated returns reports with the SeeAlso of every WARN and FAIL finding
+// listing the WARN and FAIL findings of other checks that share one of its
+// tags, so one cause tripping several checks, like a transaction left open
+// for hours, reads as related findings rather than separate alerts.
+//
+// Findings are related within a database, and a cluster-wide check relates
+// to every database. Entries name the other finding as "check-id/finding-id",
+// or "check-id" when the finding ID is the check ID, followed by " (name)"
+// when it comes from another database than the finding listing it.
+//
+// It needs every report of the run, so it runs after Run rather than in it.
+// Reports with a linked finding are copied; the others are returned as they
+// are, in the same order.
+func LinkRelated(reports []*check.Report) []*check.Report {
+	type tagged struct {
+		report  *check.Report
+		finding check.Finding
+	}
+	byTag := map[string][]tagged{}
+	for _, r := range reports {
+		for _, f := range r.Results {
+			if f.Severity < check.SeverityWarn {
+				continue
+			}
+			for _, tag := range f.Tags {
+				byTag[tag] = append(byTag[tag], tagged{report: r, finding: f})
+			}
+		}
+	}
+
+	linked := make([]*check.Report, len(reports))
+	for i, r := range reports {
+		linked[i] = r
+		var results []check.Finding
+		for j, f := range r.Results {
+			if f.Severity < check.SeverityWarn {
+				continue
+			}
+			var seeAlso []string
+			for _, tag := range f.Tags {
+				for _, other := range byTag[tag] {
+					if other.report.CheckID == r.CheckID || !sameScope(r, other.report) {
+						continue
+					}
+					if ref := relatedRef(r, other.report, other.finding); !slices.Contains(seeAlso, ref) {
+						seeAlso = append(seeAlso, ref)
+					}
+				}
+			}
+			if len(seeAlso) == 0 {
+				continue
+			}
+			if results == nil {
+				results = slices.Clone(r.Results)
+			}
+			results[j].SeeAlso = seeAlso
+		}
+		if results != nil {
+			copied := *r
+			copied.Results = results
+			linked[i] = &copied
+		}
+	}
+	return linked
+}
+
+// sameScope reports whether two reports describe the same database. Reports
+// without one come from cluster-wide checks, or from a single database run,
+// and relate to every report.
+func sameScope(a, b *check.Report) bool {
+	return a.Database == b.Database || a.Database == "" || b.Database == ""
+}
+
+// relatedRef names finding f of report other as seen from report r.
+func relatedRef(r, other *check.Report, f check.Finding) string {
+	ref := other.CheckID
+	if f.ID != other.CheckID {
+		ref += "/" + f.ID
+	}
+	if other.Database != "" && other.Database != r.Database {
+		ref += " (" + other.Database + ")"
+	}
+	return ref
+}
@@ -0,0 +1,64 @@
+package sqlcheck
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// formatValue renders a value decoded by pgx as a table cell.
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "-"
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case [16]byte:
+		return fmt.Sprintf("%x-%x-%x-%x-%x", v[0:4], v[4:6], v[6:8], v[8:10], v[10:16])
+	case pgtype.Numeric:
+		if !v.Valid {
+			return "-"
+		}
+		f, err := v.Float64Value()
+		if err != nil || !f.Valid {
+			return "-"
+		}
+		return strconv.FormatFloat(f.Float64, 'f', -1, 64)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// ruleValue converts a value decoded by pgx into one check.Rule can compare:
+// numbers, strings, booleans, or nil.
+func ruleValue(v any) any {
+	switch v := v.(type) {
+	case nil, bool, string, int16, int32, int64, float32, float64:
+		return v
+	case pgtype.Numeric:
+		if !v.Valid {
+			return nil
+		}
+		f, err := v.Float64Value()
+		if err != nil || !f.Valid {
+			return nil
+		}
+		return f.Float64
+	default:
+		return formatValue(v)
+	}
+}
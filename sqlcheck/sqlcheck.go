@@ -0,0 +1,367 @@
+// Package sqlcheck loads user-defined, SQL-only checks from disk.
+//
+// Each check lives in its own directory:
+//
+//	checks.d/
+//	└── orphaned-tenants/
+//	    ├── metadata.yaml  # check_id, name, category, description, severity or warn_when/fail_when,
+//	    │                  # optional query_columns/identifier_columns for --redact
+//	    ├── query.sql      # any query; it runs in a read-only transaction
+//	    └── README.md      # optional; shown by `pgdoctor explain`
+//
+// The query's result set is rendered as a table: every column becomes a
+// header and every row a table row. A check with no rows passes; otherwise
+// it reports at the configured severity. With warn_when/fail_when rules
+// (see check.Rule), each row is graded on its own and rows matching neither
+// rule are left out.
+package sqlcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"gopkg.in/yaml.v3"
+
+	"github.com/emancu/pgdoctor/check"
+)
+
+const (
+	metadataFile = "metadata.yaml"
+	queryFile    = "query.sql"
+	readmeFile   = "README.md"
+)
+
+var checkIDPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Definition is the contents of a check's metadata.yaml.
+type Definition struct {
+	CheckID     string `yaml:"check_id"`
+	Name        string `yaml:"name"`
+	Category    string `yaml:"category"`
+	Description string `yaml:"description"`
+	// Severity reported when the query returns rows: "warn" (default) or "fail".
+	Severity string `yaml:"severity"`
+	// WarnWhen and FailWhen are check.Rule expressions over a row's columns,
+	// e.g. "bloat_pct > 40". When either is set, only matching rows are
+	// reported, each at the severity of the first rule it matches.
+	WarnWhen string `yaml:"warn_when"`
+	FailWhen string `yaml:"fail_when"`
+	// QueryColumns and IdentifierColumns name result columns holding SQL text
+	// or object names, which a redacted run strips (see check.Report.Redact).
+	QueryColumns      []string `yaml:"query_columns"`
+	IdentifierColumns []string `yaml:"identifier_columns"`
+}
+
+// LoadDir loads every check directory directly under dir.
+func LoadDir(dir string) ([]check.Package, error) {
+	return LoadFS(os.DirFS(dir))
+}
+
+// LoadFS loads every check directory at the root of fsys.
+// Directories without a metadata.yaml are ignored. Packages are returned
+// sorted by check ID.
+func LoadFS(fsys fs.FS) ([]check.Package, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("reading checks directory: %w", err)
+	}
+
+	seen := map[string]string{}
+	var packages []check.Package
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := fs.Stat(fsys, path.Join(entry.Name(), metadataFile)); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		pkg, err := load(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("loading check %s: %w", entry.Name(), err)
+		}
+
+		id := pkg.Metadata().CheckID
+		if other, ok := seen[id]; ok {
+			return nil, fmt.Errorf("duplicate check_id %q in %s and %s", id, other, entry.Name())
+		}
+		seen[id] = entry.Name()
+		packages = append(packages, pkg)
+	}
+
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].Metadata().CheckID < packages[j].Metadata().CheckID
+	})
+	return packages, nil
+}
+
+func load(fsys fs.FS, dir string) (check.Package, error) {
+	raw, err := fs.ReadFile(fsys, path.Join(dir, metadataFile))
+	if err != nil {
+		return check.Package{}, err
+	}
+
+	var def Definition
+	if err := yaml.Unmarshal(raw, &def); err != nil {
+		return check.Package{}, fmt.Errorf("parsing %s: %w", metadataFile, err)
+	}
+
+	severity, rules, err := def.validate()
+	if err != nil {
+		return check.Package{}, fmt.Errorf("%s: %w", metadataFile, err)
+	}
+
+	query, err := fs.ReadFile(fsys, path.Join(dir, queryFile))
+	if err != nil {
+		return check.Package{}, err
+	}
+	if strings.TrimSpace(string(query)) == "" {
+		return check.Package{}, fmt.Errorf("%s is empty", queryFile)
+	}
+
+	readme, err := fs.ReadFile(fsys, path.Join(dir, readmeFile))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return check.Package{}, err
+	}
+	if len(readme) == 0 {
+		readme = []byte(fmt.Sprintf("# %s\n\n%s\n", def.Name, def.Description))
+	}
+
+	metadata := check.Metadata{
+		CheckID:     def.CheckID,
+		Name:        def.Name,
+		Category:    check.Category(def.Category),
+		Description: def.Description,
+		Readme:      string(readme),
+		SQL:         string(query),
+	}
+
+	sensitive := def.sensitivity()
+	return check.Package{
+		Metadata: func() check.Metadata { return metadata },
+		New: func(conn check.DBTX, _ check.Config) check.Checker {
+			return &checker{conn: conn, metadata: metadata, severity: severity, rules: rules, sensitive: sensitive}
+		},
+	}, nil
+}
+
+// categories are the categories a check can belong to. External checks take
+// one of them like built-in ones, so --only, --ignore, and per-category
+// settings cover them.
+var categories = []check.Category{
+	check.CategoryConfigs,
+	check.CategoryIndexes,
+	check.CategoryPerformance,
+	check.CategorySchema,
+	check.CategoryVacuum,
+}
+
+func (d Definition) validate() (check.Severity, *check.SeverityRules, error) {
+	if !checkIDPattern.MatchString(d.CheckID) {
+		return 0, nil, fmt.Errorf("check_id %q must be lowercase kebab-case", d.CheckID)
+	}
+	if d.Name == "" {
+		return 0, nil, fmt.Errorf("name is required")
+	}
+	if d.Category == "" {
+		return 0, nil, fmt.Errorf("category is required")
+	}
+	if !slices.Contains(categories, check.Category(d.Category)) {
+		names := make([]string, len(categories))
+		for i, c := range categories {
+			names[i] = string(c)
+		}
+		return 0, nil, fmt.Errorf("unknown category %q (valid: %s)", d.Category, strings.Join(names, ", "))
+	}
+
+	if d.WarnWhen != "" || d.FailWhen != "" {
+		if d.Severity != "" {
+			return 0, nil, fmt.Errorf("severity cannot be combined with warn_when/fail_when")
+		}
+		rules := &check.SeverityRules{}
+		var err error
+		if d.WarnWhen != "" {
+			if rules.Warn, err = check.ParseRule(d.WarnWhen); err != nil {
+				return 0, nil, fmt.Errorf("warn_when: %w", err)
+			}
+		}
+		if d.FailWhen != "" {
+			if rules.Fail, err = check.ParseRule(d.FailWhen); err != nil {
+				return 0, nil, fmt.Errorf("fail_when: %w", err)
+			}
+		}
+		return 0, rules, nil
+	}
+
+	switch d.Severity {
+	case "", "warn":
+		return check.SeverityWarn, nil, nil
+	case "fail":
+		return check.SeverityFail, nil, nil
+	default:
+		return 0, nil, fmt.Errorf("severity %q must be warn or fail", d.Severity)
+	}
+}
+
+// sensitivity maps column names to how redaction treats them.
+func (d Definition) sensitivity() map[string]check.Sensitivity {
+	sensitive := map[string]check.Sensitivity{}
+	for _, column := range d.IdentifierColumns {
+		sensitive[column] = check.SensitiveIdentifier
+	}
+	for _, column := range d.QueryColumns {
+		sensitive[column] = check.SensitiveQuery
+	}
+	return sensitive
+}
+
+type checker struct {
+	conn      check.DBTX
+	metadata  check.Metadata
+	severity  check.Severity       // used when rules is nil
+	rules     *check.SeverityRules // nil: every row reports at severity
+	sensitive map[string]check.Sensitivity
+}
+
+func (c *checker) Metadata() check.Metadata {
+	return c.metadata
+}
+
+func (c *checker) Check(ctx context.Context) (*check.Report, error) {
+	report := check.NewReport(c.metadata)
+
+	table, total, err := c.query(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running %s/%s: %w", report.Category, report.CheckID, err)
+	}
+
+	if len(table.Rows) == 0 {
+		details := "Query returned no rows"
+		if total > 0 {
+			details = fmt.Sprintf("None of %d row(s) matched warn_when/fail_when", total)
+		}
+		report.AddFinding(check.Finding{
+			ID:       report.CheckID,
+			Name:     report.Name,
+			Severity: check.SeverityOK,
+			Details:  details,
+		})
+		return report, nil
+	}
+
+	severity := check.SeverityOK
+	for _, row := range table.Rows {
+		if row.Severity > severity {
+			severity = row.Severity
+		}
+	}
+
+	details := fmt.Sprintf("Query returned %d row(s)", total)
+	if c.rules != nil {
+		details = fmt.Sprintf("%d of %d row(s) matched warn_when/fail_when", len(table.Rows), total)
+	}
+	report.AddFinding(check.Finding{
+		ID:       report.CheckID,
+		Name:     report.Name,
+		Severity: severity,
+		Details:  details,
+		Table:    table,
+	})
+
+	return report, nil
+}
+
+// beginner is implemented by *pgx.Conn and *pgxpool.Pool.
+type beginner interface {
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+}
+
+// beginReadOnly starts a read-only transaction on conn, so that the SQL of a
+// check, which pgdoctor does not control, cannot modify the database. It
+// returns the connection to run the SQL on and a function that rolls the
+// transaction back. Connections that cannot begin transactions themselves,
+// like the runner's query log, wrap a single connection and get a
+// BEGIN READ ONLY statement instead.
+func beginReadOnly(ctx context.Context, conn check.DBTX) (check.DBTX, func(), error) {
+	// Roll back even when ctx is cancelled, so that the connection is left
+	// outside a transaction for the next check.
+	rollbackCtx := context.WithoutCancel(ctx)
+
+	if b, ok := conn.(beginner); ok {
+		tx, err := b.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+		if err != nil {
+			return nil, nil, err
+		}
+		return tx, func() { _ = tx.Rollback(rollbackCtx) }, nil
+	}
+
+	if _, err := conn.Exec(ctx, "BEGIN READ ONLY"); err != nil {
+		return nil, nil, err
+	}
+	return conn, func() { _, _ = conn.Exec(rollbackCtx, "ROLLBACK") }, nil
+}
+
+// query runs the check's SQL in a read-only transaction and returns the
+// reportable rows along with the total number of rows returned.
+func (c *checker) query(ctx context.Context) (*check.Table, int, error) {
+	conn, rollback, err := beginReadOnly(ctx, c.conn)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rollback()
+
+	rows, err := conn.Query(ctx, c.metadata.SQL)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	table := &check.Table{}
+	for _, field := range rows.FieldDescriptions() {
+		table.Headers = append(table.Headers, field.Name)
+		table.Sensitive = append(table.Sensitive, c.sensitive[field.Name])
+	}
+
+	total := 0
+	for rows.Next() {
+		values, err := rows.Values()
+		if err != nil {
+			return nil, 0, err
+		}
+		total++
+
+		severity := c.severity
+		if c.rules != nil {
+			columns := make(map[string]any, len(values))
+			for i, v := range values {
+				columns[table.Headers[i]] = ruleValue(v)
+			}
+			if severity, err = c.rules.Severity(columns); err != nil {
+				return nil, 0, err
+			}
+			if severity == check.SeverityOK {
+				continue
+			}
+		}
+
+		cells := make([]string, len(values))
+		for i, v := range values {
+			cells[i] = formatValue(v)
+		}
+		table.Rows = append(table.Rows, check.TableRow{Cells: cells, Severity: severity})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return table, total, nil
+}
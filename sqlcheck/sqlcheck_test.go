@@ -0,0 +1,422 @@
+package sqlcheck_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/emancu/pgdoctor/check"
+	"github.com/emancu/pgdoctor/sqlcheck"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDB answers every query with the same result set. Like PostgreSQL, it
+// rejects writes in a transaction begun with BEGIN READ ONLY.
+type fakeDB struct {
+	columns []string
+	values  [][]any
+	err     error
+	query   string
+
+	statements []string // run with Exec
+	readOnly   bool
+}
+
+func (f *fakeDB) Exec(_ context.Context, sql string, _ ...interface{}) (pgconn.CommandTag, error) {
+	f.statements = append(f.statements, sql)
+	switch sql {
+	case "BEGIN READ ONLY":
+		f.readOnly = true
+	case "ROLLBACK":
+		f.readOnly = false
+	}
+	return pgconn.CommandTag{}, nil
+}
+
+func (f *fakeDB) Query(_ context.Context, sql string, _ ...interface{}) (pgx.Rows, error) {
+	f.query = sql
+	if f.readOnly && !strings.HasPrefix(sql, "SELECT") {
+		return nil, &pgconn.PgError{Code: "25006", Message: "cannot execute " + strings.Fields(sql)[0] + " in a read-only transaction"}
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &fakeRows{columns: f.columns, values: f.values, pos: -1}, nil
+}
+
+func (f *fakeDB) QueryRow(context.Context, string, ...interface{}) pgx.Row {
+	return nil
+}
+
+type fakeRows struct {
+	columns []string
+	values  [][]any
+	pos     int
+}
+
+func (r *fakeRows) Close()                        {}
+func (r *fakeRows) Err() error                    { return nil }
+func (r *fakeRows) CommandTag() pgconn.CommandTag { return pgconn.CommandTag{} }
+func (r *fakeRows) Scan(...any) error             { return nil }
+func (r *fakeRows) RawValues() [][]byte           { return nil }
+func (r *fakeRows) Conn() *pgx.Conn               { return nil }
+
+func (r *fakeRows) FieldDescriptions() []pgconn.FieldDescription {
+	fields := make([]pgconn.FieldDescription, len(r.columns))
+	for i, name := range r.columns {
+		fields[i] = pgconn.FieldDescription{Name: name}
+	}
+	return fields
+}
+
+func (r *fakeRows) Next() bool {
+	r.pos++
+	return r.pos < len(r.values)
+}
+
+func (r *fakeRows) Values() ([]any, error) {
+	return r.values[r.pos], nil
+}
+
+func file(contents string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(contents)}
+}
+
+func validFS() fstest.MapFS {
+	return fstest.MapFS{
+		"orphaned-tenants/metadata.yaml": file(`
+check_id: orphaned-tenants
+name: Orphaned Tenants
+category: schema
+description: Tenants without an owner
+severity: fail
+`),
+		"orphaned-tenants/query.sql": file("SELECT id, name FROM tenants WHERE owner_id IS NULL"),
+		"orphaned-tenants/README.md": file("# Orphaned Tenants\n"),
+		"big-queues/metadata.yaml": file(`
+check_id: big-queues
+name: Big Queues
+category: performance
+description: Job queues with a backlog
+`),
+		"big-queues/query.sql": file("SELECT queue, depth FROM queue_depths WHERE depth > 1000"),
+		"notes/todo.txt":       file("not a check"),
+	}
+}
+
+func TestLoadFS(t *testing.T) {
+	t.Parallel()
+
+	packages, err := sqlcheck.LoadFS(validFS())
+	require.NoError(t, err)
+	require.Len(t, packages, 2, "directories without metadata.yaml are ignored")
+
+	queues := packages[0].Metadata()
+	assert.Equal(t, "big-queues", queues.CheckID)
+	assert.Equal(t, check.CategoryPerformance, queues.Category)
+	assert.Equal(t, "SELECT queue, depth FROM queue_depths WHERE depth > 1000", queues.SQL)
+	assert.Contains(t, queues.Readme, "Job queues with a backlog", "missing README falls back to the description")
+
+	tenants := packages[1].Metadata()
+	assert.Equal(t, "orphaned-tenants", tenants.CheckID)
+	assert.Equal(t, "Orphaned Tenants", tenants.Name)
+	assert.Equal(t, "# Orphaned Tenants\n", tenants.Readme)
+}
+
+func TestLoadFS_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		fsys  fstest.MapFS
+		error string
+	}{
+		{
+			name: "bad check id",
+			fsys: fstest.MapFS{
+				"x/metadata.yaml": file("check_id: Not_Valid\nname: X\ncategory: schema\n"),
+				"x/query.sql":     file("SELECT 1"),
+			},
+			error: "kebab-case",
+		},
+		{
+			name: "missing category",
+			fsys: fstest.MapFS{
+				"x/metadata.yaml": file("check_id: x\nname: X\n"),
+				"x/query.sql":     file("SELECT 1"),
+			},
+			error: "category is required",
+		},
+		{
+			name: "unknown category",
+			fsys: fstest.MapFS{
+				"x/metadata.yaml": file("check_id: x\nname: X\ncategory: tenants\n"),
+				"x/query.sql":     file("SELECT 1"),
+			},
+			error: `loading check x: metadata.yaml: unknown category "tenants" (valid: configs, indexes, performance, schema, vacuum)`,
+		},
+		{
+			name: "unknown severity",
+			fsys: fstest.MapFS{
+				"x/metadata.yaml": file("check_id: x\nname: X\ncategory: schema\nseverity: panic\n"),
+				"x/query.sql":     file("SELECT 1"),
+			},
+			error: "must be warn or fail",
+		},
+		{
+			name: "missing query",
+			fsys: fstest.MapFS{
+				"x/metadata.yaml": file("check_id: x\nname: X\ncategory: schema\n"),
+			},
+			error: "query.sql",
+		},
+		{
+			name: "duplicate check id",
+			fsys: fstest.MapFS{
+				"a/metadata.yaml": file("check_id: x\nname: X\ncategory: schema\n"),
+				"a/query.sql":     file("SELECT 1"),
+				"b/metadata.yaml": file("check_id: x\nname: X\ncategory: schema\n"),
+				"b/query.sql":     file("SELECT 1"),
+			},
+			error: `duplicate check_id "x"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := sqlcheck.LoadFS(tt.fsys)
+			require.ErrorContains(t, err, tt.error)
+		})
+	}
+}
+
+func loadTenants(t *testing.T) check.Package {
+	t.Helper()
+
+	packages, err := sqlcheck.LoadFS(validFS())
+	require.NoError(t, err)
+	return packages[1]
+}
+
+func TestCheck_NoRowsPasses(t *testing.T) {
+	t.Parallel()
+
+	conn := &fakeDB{columns: []string{"id", "name"}}
+	report, err := loadTenants(t).New(conn, nil).Check(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "SELECT id, name FROM tenants WHERE owner_id IS NULL", conn.query)
+	assert.Equal(t, check.SeverityOK, report.Severity)
+	require.Len(t, report.Results, 1)
+	assert.Equal(t, "orphaned-tenants", report.Results[0].ID)
+	assert.Nil(t, report.Results[0].Table)
+}
+
+func TestCheck_RowsRenderedAsTable(t *testing.T) {
+	t.Parallel()
+
+	conn := &fakeDB{
+		columns: []string{"id", "name", "balance"},
+		values: [][]any{
+			{int64(7), "acme", pgtype.Numeric{Valid: false}},
+			{int64(9), nil, float64(12.5)},
+		},
+	}
+	report, err := loadTenants(t).New(conn, nil).Check(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, check.SeverityFail, report.Severity, "severity comes from metadata.yaml")
+	finding := report.Results[0]
+	assert.Equal(t, "Query returned 2 row(s)", finding.Details)
+	require.NotNil(t, finding.Table)
+	assert.Equal(t, []string{"id", "name", "balance"}, finding.Table.Headers)
+	assert.Equal(t, []string{"7", "acme", "-"}, finding.Table.Rows[0].Cells)
+	assert.Equal(t, []string{"9", "-", "12.5"}, finding.Table.Rows[1].Cells)
+	assert.Equal(t, check.SeverityFail, finding.Table.Rows[0].Severity)
+}
+
+func TestCheck_DefaultSeverityIsWarn(t *testing.T) {
+	t.Parallel()
+
+	packages, err := sqlcheck.LoadFS(validFS())
+	require.NoError(t, err)
+
+	conn := &fakeDB{columns: []string{"queue", "depth"}, values: [][]any{{"emails", int64(5000)}}}
+	report, err := packages[0].New(conn, nil).Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, check.SeverityWarn, report.Severity)
+}
+
+func TestCheck_QueryError(t *testing.T) {
+	t.Parallel()
+
+	conn := &fakeDB{err: fmt.Errorf("relation \"tenants\" does not exist")}
+	_, err := loadTenants(t).New(conn, nil).Check(context.Background())
+	require.ErrorContains(t, err, "orphaned-tenants")
+}
+
+// fakeConn is a fakeDB that begins transactions like *pgx.Conn.
+type fakeConn struct {
+	fakeDB
+	txOptions  *pgx.TxOptions
+	rolledBack bool
+}
+
+func (c *fakeConn) BeginTx(_ context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) {
+	c.txOptions = &txOptions
+	c.readOnly = txOptions.AccessMode == pgx.ReadOnly
+	return &fakeTx{conn: c}, nil
+}
+
+// fakeTx runs queries on its fakeConn. Methods pgx.Tx has but the checks do
+// not use panic.
+type fakeTx struct {
+	pgx.Tx
+	conn *fakeConn
+}
+
+func (t *fakeTx) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return t.conn.Query(ctx, sql, args...)
+}
+
+func (t *fakeTx) Rollback(context.Context) error {
+	t.conn.rolledBack = true
+	t.conn.readOnly = false
+	return nil
+}
+
+func writeFS() fstest.MapFS {
+	return fstest.MapFS{
+		"purge/metadata.yaml": file("check_id: purge\nname: Purge\ncategory: schema\n"),
+		"purge/query.sql":     file("DELETE FROM tenants RETURNING id"),
+	}
+}
+
+func TestCheck_ReadOnlyTransaction(t *testing.T) {
+	t.Parallel()
+
+	conn := &fakeConn{fakeDB: fakeDB{columns: []string{"id", "name"}}}
+	_, err := loadTenants(t).New(conn, nil).Check(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, conn.txOptions)
+	assert.Equal(t, pgx.TxOptions{AccessMode: pgx.ReadOnly}, *conn.txOptions)
+	assert.True(t, conn.rolledBack, "the transaction is rolled back")
+
+	// Connections without BeginTx, like the runner's query log, get the
+	// statements instead.
+	db := &fakeDB{columns: []string{"id", "name"}}
+	_, err = loadTenants(t).New(db, nil).Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BEGIN READ ONLY", "ROLLBACK"}, db.statements)
+}
+
+func TestCheck_WriteRejected(t *testing.T) {
+	t.Parallel()
+
+	packages, err := sqlcheck.LoadFS(writeFS())
+	require.NoError(t, err)
+
+	conn := &fakeConn{}
+	_, err = packages[0].New(conn, nil).Check(context.Background())
+	require.ErrorContains(t, err, "cannot execute DELETE in a read-only transaction")
+	assert.True(t, conn.rolledBack)
+
+	db := &fakeDB{}
+	_, err = packages[0].New(db, nil).Check(context.Background())
+	require.ErrorContains(t, err, "cannot execute DELETE in a read-only transaction")
+	assert.Equal(t, []string{"BEGIN READ ONLY", "ROLLBACK"}, db.statements)
+}
+
+func bloatFS(rules string) fstest.MapFS {
+	return fstest.MapFS{
+		"bloat/metadata.yaml": file("check_id: bloat\nname: Bloat\ncategory: vacuum\n" + rules),
+		"bloat/query.sql":     file("SELECT relname, bloat_pct FROM bloat_estimates"),
+	}
+}
+
+func TestCheck_SeverityRules(t *testing.T) {
+	t.Parallel()
+
+	packages, err := sqlcheck.LoadFS(bloatFS("warn_when: bloat_pct > 40\nfail_when: bloat_pct > 60\n"))
+	require.NoError(t, err)
+
+	conn := &fakeDB{
+		columns: []string{"relname", "bloat_pct"},
+		values: [][]any{
+			{"events", float64(75)},
+			{"users", int64(10)},
+			{"orders", pgtype.Numeric{Int: big.NewInt(455), Exp: -1, Valid: true}},
+		},
+	}
+	report, err := packages[0].New(conn, nil).Check(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, check.SeverityFail, report.Severity)
+	finding := report.Results[0]
+	assert.Equal(t, "2 of 3 row(s) matched warn_when/fail_when", finding.Details)
+	require.NotNil(t, finding.Table)
+	require.Len(t, finding.Table.Rows, 2, "rows matching neither rule are left out")
+	assert.Equal(t, "events", finding.Table.Rows[0].Cells[0])
+	assert.Equal(t, check.SeverityFail, finding.Table.Rows[0].Severity)
+	assert.Equal(t, []string{"orders", "45.5"}, finding.Table.Rows[1].Cells)
+	assert.Equal(t, check.SeverityWarn, finding.Table.Rows[1].Severity)
+}
+
+func TestCheck_SeverityRulesNoMatch(t *testing.T) {
+	t.Parallel()
+
+	packages, err := sqlcheck.LoadFS(bloatFS("warn_when: bloat_pct > 40\n"))
+	require.NoError(t, err)
+
+	conn := &fakeDB{columns: []string{"relname", "bloat_pct"}, values: [][]any{{"users", int64(10)}}}
+	report, err := packages[0].New(conn, nil).Check(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, check.SeverityOK, report.Severity)
+	assert.Equal(t, "None of 1 row(s) matched warn_when/fail_when", report.Results[0].Details)
+}
+
+func TestCheck_SeverityRuleUnknownColumn(t *testing.T) {
+	t.Parallel()
+
+	packages, err := sqlcheck.LoadFS(bloatFS("warn_when: dead_pct > 40\n"))
+	require.NoError(t, err)
+
+	conn := &fakeDB{columns: []string{"relname", "bloat_pct"}, values: [][]any{{"users", int64(10)}}}
+	_, err = packages[0].New(conn, nil).Check(context.Background())
+	require.ErrorContains(t, err, `unknown column "dead_pct"`)
+}
+
+func TestLoadFS_InvalidSeverityRules(t *testing.T) {
+	t.Parallel()
+
+	_, err := sqlcheck.LoadFS(bloatFS("warn_when: bloat_pct >\n"))
+	require.ErrorContains(t, err, "warn_when")
+
+	_, err = sqlcheck.LoadFS(bloatFS("severity: fail\nfail_when: bloat_pct > 60\n"))
+	require.ErrorContains(t, err, "cannot be combined")
+}
+
+func TestCheck_SensitiveColumns(t *testing.T) {
+	t.Parallel()
+
+	packages, err := sqlcheck.LoadFS(bloatFS("identifier_columns: [relname]\nquery_columns: [last_query]\n"))
+	require.NoError(t, err)
+
+	conn := &fakeDB{columns: []string{"relname", "bloat_pct", "last_query"}, values: [][]any{{"users", int64(10), "SELECT 1"}}}
+	report, err := packages[0].New(conn, nil).Check(context.Background())
+	require.NoError(t, err)
+
+	table := report.Results[0].Table
+	require.NotNil(t, table)
+	assert.Equal(t, []check.Sensitivity{check.SensitiveIdentifier, check.NotSensitive, check.SensitiveQuery}, table.Sensitive)
+}